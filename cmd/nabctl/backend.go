@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/browser"
+	"github.com/benrowe/nab-bank-api/internal/config"
+	"github.com/benrowe/nab-bank-api/internal/enrich"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// backend is where nabctl gets its data from
+type backend interface {
+	Accounts(ctx context.Context) ([]model.Account, error)
+	AccountDetails(ctx context.Context, accountID string) (*model.AccountDetails, error)
+}
+
+// newBackend picks the HTTP API or the browser client based on the flags
+func newBackend(opts *globalOptions) (backend, error) {
+	if !opts.direct {
+		return &httpBackend{
+			baseURL: strings.TrimRight(opts.server, "/"),
+			apiKey:  opts.apiKey,
+			client:  &http.Client{Timeout: 5 * time.Minute},
+		}, nil
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	logger := log.New(io.Discard, "", 0)
+
+	var nabClient service.BankProvider
+	if cfg.NAB.ReplayDir != "" {
+		nabClient = browser.NewReplayClient(cfg.NAB.ReplayDir, logger)
+	} else if cfg.NAB.Username == "test" && cfg.NAB.Password == "test" {
+		nabClient = service.NewMockNABClient()
+	} else {
+		nabClient = browser.NewNABClient(&cfg.NAB, logger)
+	}
+
+	dataStore, err := store.Open(cfg.Store.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store: %w", err)
+	}
+
+	var enricher service.MerchantEnricher
+	if cfg.Sync.EnrichMerchants {
+		enricher = enrich.NewPipeline(logger, enrich.NewRules())
+	}
+
+	return &directBackend{
+		service: service.NewAccountService(nabClient, dataStore, nil, service.AlertThresholds{}, service.RetryPolicy{
+			MaxAttempts:    cfg.NAB.RetryAttempts,
+			InitialBackoff: cfg.NAB.RetryBackoff,
+			MaxBackoff:     cfg.NAB.RetryMaxBackoff,
+			Jitter:         cfg.NAB.RetryJitter,
+		}, service.BreakerPolicy{
+			Threshold: cfg.NAB.BreakerThreshold,
+			Cooldown:  cfg.NAB.BreakerCooldown,
+		}, service.CachePolicy{}, enricher, nil),
+	}, nil
+}
+
+// httpBackend reads data from a running NAB Bank API server
+type httpBackend struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// Accounts lists accounts via GET /api/v1/accounts
+func (b *httpBackend) Accounts(ctx context.Context) ([]model.Account, error) {
+	var response model.AccountsResponse
+	if err := b.get(ctx, "/api/v1/accounts", &response); err != nil {
+		return nil, err
+	}
+	return response.Accounts, nil
+}
+
+// AccountDetails fetches an account via GET /api/v1/accounts/{accountId}
+func (b *httpBackend) AccountDetails(ctx context.Context, accountID string) (*model.AccountDetails, error) {
+	var response model.AccountDetailsResponse
+	if err := b.get(ctx, "/api/v1/accounts/"+url.PathEscape(accountID), &response); err != nil {
+		return nil, err
+	}
+	return &response.Account, nil
+}
+
+// get performs a GET request and decodes the JSON response
+func (b *httpBackend) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if b.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+b.apiKey)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr model.ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err == nil && apiErr.Message != "" {
+			return fmt.Errorf("%s: %s", apiErr.Error, apiErr.Message)
+		}
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// directBackend scrapes NAB through the account service in-process,
+// recording results in the local store as the server would
+type directBackend struct {
+	service service.AccountService
+}
+
+// Accounts lists accounts from the browser client
+func (b *directBackend) Accounts(ctx context.Context) ([]model.Account, error) {
+	return b.service.GetAllAccounts(ctx)
+}
+
+// AccountDetails fetches an account and its transactions from the browser client
+func (b *directBackend) AccountDetails(ctx context.Context, accountID string) (*model.AccountDetails, error) {
+	return b.service.GetAccountDetails(ctx, accountID)
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/spf13/cobra"
+)
+
+// accountTransaction pairs a transaction with the account it belongs to
+type accountTransaction struct {
+	AccountID string `json:"accountId"`
+	model.Transaction
+}
+
+// newAccountsCommand builds the accounts subcommands
+func newAccountsCommand(opts *globalOptions) *cobra.Command {
+	accounts := &cobra.Command{
+		Use:   "accounts",
+		Short: "Work with accounts",
+	}
+
+	accounts.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List all accounts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := newBackend(opts)
+			if err != nil {
+				return err
+			}
+
+			list, err := b.Accounts(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to list accounts: %w", err)
+			}
+
+			return accountsTable(list).write(cmd.OutOrStdout(), opts.output)
+		},
+	})
+
+	return accounts
+}
+
+// newTransactionsCommand builds the transactions subcommands
+func newTransactionsCommand(opts *globalOptions) *cobra.Command {
+	var accountID, since string
+
+	transactions := &cobra.Command{
+		Use:   "transactions",
+		Short: "Work with transactions",
+	}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List recent transactions, optionally for a single account",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if since != "" {
+				if _, err := time.Parse("2006-01-02", since); err != nil {
+					return fmt.Errorf("invalid --since date %q, expected YYYY-MM-DD", since)
+				}
+			}
+
+			b, err := newBackend(opts)
+			if err != nil {
+				return err
+			}
+
+			accountIDs := []string{accountID}
+			if accountID == "" {
+				accounts, err := b.Accounts(cmd.Context())
+				if err != nil {
+					return fmt.Errorf("failed to list accounts: %w", err)
+				}
+				accountIDs = accountIDs[:0]
+				for _, account := range accounts {
+					accountIDs = append(accountIDs, account.ID)
+				}
+			}
+
+			var results []accountTransaction
+			for _, id := range accountIDs {
+				details, err := b.AccountDetails(cmd.Context(), id)
+				if err != nil {
+					return fmt.Errorf("failed to get transactions for account %s: %w", id, err)
+				}
+				for _, transaction := range details.Transactions {
+					// Dates are ISO formatted so compare as strings
+					if since != "" && transaction.Date < since {
+						continue
+					}
+					results = append(results, accountTransaction{AccountID: id, Transaction: transaction})
+				}
+			}
+
+			return transactionsTable(results).write(cmd.OutOrStdout(), opts.output)
+		},
+	}
+	list.Flags().StringVar(&accountID, "account", "", "account ID (default: all accounts)")
+	list.Flags().StringVar(&since, "since", "", "only include transactions on or after this date (YYYY-MM-DD)")
+
+	transactions.AddCommand(list)
+	return transactions
+}
+
+// newSyncCommand builds the sync subcommands
+func newSyncCommand(opts *globalOptions) *cobra.Command {
+	sync := &cobra.Command{
+		Use:   "sync",
+		Short: "Refresh stored data from NAB",
+	}
+
+	sync.AddCommand(&cobra.Command{
+		Use:   "now",
+		Short: "Scrape every account and its transactions now",
+		Long: "Scrape every account and its transactions now. Scraped data is recorded in the\n" +
+			"server's store, or the local store when run with --direct.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := newBackend(opts)
+			if err != nil {
+				return err
+			}
+
+			accounts, err := b.Accounts(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to list accounts: %w", err)
+			}
+
+			result := syncTable{}
+			for _, account := range accounts {
+				details, err := b.AccountDetails(cmd.Context(), account.ID)
+				if err != nil {
+					return fmt.Errorf("failed to sync account %s: %w", account.ID, err)
+				}
+				result.add(account, len(details.Transactions))
+			}
+
+			return result.table().write(cmd.OutOrStdout(), opts.output)
+		},
+	})
+
+	return sync
+}
+
+// accountsTable formats accounts for output
+func accountsTable(accounts []model.Account) table {
+	t := table{
+		header: []string{"ID", "NAME", "TYPE", "BALANCE", "AVAILABLE"},
+		value:  accounts,
+	}
+	for _, account := range accounts {
+		available := ""
+		if account.AvailableBalance != nil {
+			available = account.AvailableBalance.Amount
+		}
+		t.rows = append(t.rows, []string{account.ID, account.Name, account.Type, account.Balance.Amount, available})
+	}
+	return t
+}
+
+// transactionsTable formats transactions for output
+func transactionsTable(transactions []accountTransaction) table {
+	t := table{
+		header: []string{"ACCOUNT", "DATE", "DESCRIPTION", "AMOUNT", "BALANCE", "CATEGORY"},
+		value:  transactions,
+	}
+	if transactions == nil {
+		t.value = []accountTransaction{}
+	}
+	for _, transaction := range transactions {
+		t.rows = append(t.rows, []string{
+			transaction.AccountID,
+			transaction.Date,
+			transaction.Description,
+			transaction.Amount.Amount,
+			transaction.Balance.Amount,
+			valueOrEmpty(transaction.Category),
+		})
+	}
+	return t
+}
+
+// syncResult summarises what was scraped for an account
+type syncResult struct {
+	AccountID    string `json:"accountId"`
+	Name         string `json:"name"`
+	Transactions int    `json:"transactions"`
+}
+
+// syncTable collects sync results
+type syncTable struct {
+	results []syncResult
+}
+
+// add records the outcome of syncing an account
+func (s *syncTable) add(account model.Account, transactions int) {
+	s.results = append(s.results, syncResult{AccountID: account.ID, Name: account.Name, Transactions: transactions})
+}
+
+// table formats the sync results for output
+func (s *syncTable) table() table {
+	t := table{
+		header: []string{"ACCOUNT", "NAME", "TRANSACTIONS"},
+		value:  s.results,
+	}
+	if s.results == nil {
+		t.value = []syncResult{}
+	}
+	for _, result := range s.results {
+		t.rows = append(t.rows, []string{result.AccountID, result.Name, strconv.Itoa(result.Transactions)})
+	}
+	return t
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// globalOptions holds flags shared by every subcommand
+type globalOptions struct {
+	server string
+	apiKey string
+	output string
+	direct bool
+}
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// newRootCommand builds the nabctl command tree
+func newRootCommand() *cobra.Command {
+	opts := &globalOptions{}
+
+	root := &cobra.Command{
+		Use:          "nabctl",
+		Short:        "Command line client for the NAB Bank API",
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			switch opts.output {
+			case outputTable, outputJSON, outputCSV:
+				return nil
+			default:
+				return fmt.Errorf("unknown output format %q, expected table, json or csv", opts.output)
+			}
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&opts.server, "server", getEnvOrDefault("NABCTL_SERVER", "http://localhost:8080"), "NAB Bank API base URL")
+	flags.StringVar(&opts.apiKey, "api-key", os.Getenv("NABCTL_API_KEY"), "API key sent as a bearer token")
+	flags.StringVarP(&opts.output, "output", "o", outputTable, "output format: table, json or csv")
+	flags.BoolVar(&opts.direct, "direct", false, "drive the NAB browser client directly instead of calling the API")
+
+	root.AddCommand(
+		newAccountsCommand(opts),
+		newTransactionsCommand(opts),
+		newSyncCommand(opts),
+		newTroubleshootCommand(opts),
+		newSnapshotsCommand(opts),
+	)
+
+	return root
+}
+
+// getEnvOrDefault returns environment variable value or default
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestTransactionsListSince(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if r.URL.Path != "/api/v1/accounts/123" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(model.AccountDetailsResponse{Account: model.AccountDetails{
+			Account: model.Account{ID: "123"},
+			Transactions: []model.Transaction{
+				{ID: "new", Date: "2024-02-01", Description: "COLES", Amount: model.Money{Amount: "-10.00"}},
+				{ID: "old", Date: "2023-12-31", Description: "WOOLWORTHS", Amount: model.Money{Amount: "-20.00"}},
+			},
+		}})
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	cmd := newRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--server", server.URL, "--api-key", "secret", "-o", "csv",
+		"transactions", "list", "--account", "123", "--since", "2024-01-01"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected header and one row, got %q", out.String())
+	}
+	if !strings.HasPrefix(lines[1], "123,2024-02-01,COLES,-10.00") {
+		t.Errorf("unexpected row %q", lines[1])
+	}
+}
+
+func TestHTTPBackendError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(model.ErrorResponse{Error: model.ErrorTypeServiceUnavailable, Message: "NAB is down"})
+	}))
+	defer server.Close()
+
+	cmd := newRootCommand()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--server", server.URL, "accounts", "list"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "NAB is down") {
+		t.Fatalf("expected API error, got %v", err)
+	}
+}
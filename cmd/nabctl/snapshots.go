@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/browser"
+	"github.com/spf13/cobra"
+)
+
+// newSnapshotsCommand builds the snapshots command
+func newSnapshotsCommand(opts *globalOptions) *cobra.Command {
+	var dir, at string
+
+	cmd := &cobra.Command{
+		Use:   "snapshots <recording>",
+		Short: "List or rebuild archived page snapshots",
+		Long: "List the snapshot history kept for a recording in BROWSER_RECORD_DIR, such as\n" +
+			"\"accounts\" or \"direct_debits_12345678\". With --at, print the HTML of the page\n" +
+			"as it was at that time instead.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if dir == "" {
+				return errors.New("no record directory: set BROWSER_RECORD_DIR or --dir")
+			}
+
+			if at != "" {
+				when, err := time.Parse(time.RFC3339, at)
+				if err != nil {
+					return fmt.Errorf("invalid --at time, expected RFC 3339: %w", err)
+				}
+				html, err := browser.LoadSnapshot(dir, args[0], when)
+				if err != nil {
+					return err
+				}
+				_, err = io.WriteString(cmd.OutOrStdout(), html)
+				return err
+			}
+
+			snapshots, err := browser.ListSnapshots(dir, args[0])
+			if err != nil {
+				return err
+			}
+			return snapshotsTable(snapshots).write(cmd.OutOrStdout(), opts.output)
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", os.Getenv("BROWSER_RECORD_DIR"), "record directory holding the snapshot history")
+	cmd.Flags().StringVar(&at, "at", "", "print the snapshot taken at or before this RFC 3339 time")
+
+	return cmd
+}
+
+// snapshotsTable formats snapshots for output
+func snapshotsTable(snapshots []browser.SnapshotInfo) table {
+	t := table{
+		header: []string{"RECORDED", "TYPE", "SIZE", "URL"},
+		value:  snapshots,
+	}
+	for _, snapshot := range snapshots {
+		kind := "delta"
+		if snapshot.Full {
+			kind = "full"
+		}
+		t.rows = append(t.rows, []string{
+			snapshot.RecordedAt.Format(time.RFC3339),
+			kind,
+			strconv.FormatInt(snapshot.Size, 10),
+			snapshot.URL,
+		})
+	}
+	return t
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/benrowe/nab-bank-api/internal/browser"
+	"github.com/benrowe/nab-bank-api/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// errTroubleshootStopped is returned when the user stops at a pause
+var errTroubleshootStopped = errors.New("stopped")
+
+// troubleshooter is implemented by the browser client
+type troubleshooter interface {
+	Troubleshoot(ctx context.Context, after func(browser.TroubleshootStep) error) (*browser.TroubleshootReport, error)
+}
+
+// newTroubleshootCommand builds the troubleshoot command
+func newTroubleshootCommand(opts *globalOptions) *cobra.Command {
+	var headless, noPause, verbose bool
+
+	cmd := &cobra.Command{
+		Use:   "troubleshoot",
+		Short: "Walk through the NAB login step by step and diagnose where it fails",
+		Long: "Drive the NAB browser client through the login and account scrape one stage at a\n" +
+			"time, printing what each stage expected and found and saving an annotated\n" +
+			"screenshot of each to BROWSER_SCREENSHOT_PATH. The run pauses after each stage\n" +
+			"and ends with a diagnosis of where the flow got stuck, such as an OTP challenge.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadConfig()
+			if err != nil {
+				return fmt.Errorf("failed to load configuration: %w", err)
+			}
+			cfg.NAB.BrowserHeadless = headless
+
+			logger := log.New(io.Discard, "", 0)
+			if verbose {
+				logger = log.New(cmd.ErrOrStderr(), "[NAB] ", log.LstdFlags)
+			}
+			client, ok := browser.NewNABClient(&cfg.NAB, logger).(troubleshooter)
+			if !ok {
+				return errors.New("the NAB client does not support troubleshooting")
+			}
+
+			progress := cmd.ErrOrStderr()
+			input := bufio.NewReader(cmd.InOrStdin())
+			report, err := client.Troubleshoot(cmd.Context(), func(step browser.TroubleshootStep) error {
+				printStep(progress, step)
+				if noPause || !step.Passed {
+					return nil
+				}
+				fmt.Fprint(progress, "Press Enter to continue, or q to stop: ")
+				line, err := input.ReadString('\n')
+				if err != nil && !errors.Is(err, io.EOF) {
+					return err
+				}
+				if strings.EqualFold(strings.TrimSpace(line), "q") {
+					return errTroubleshootStopped
+				}
+				return nil
+			})
+			if err != nil && !errors.Is(err, errTroubleshootStopped) {
+				return fmt.Errorf("troubleshooting failed: %w", err)
+			}
+			if errors.Is(err, errTroubleshootStopped) {
+				report.Diagnosis = "stopped before the flow finished"
+			}
+
+			if err := troubleshootTable(report).write(cmd.OutOrStdout(), opts.output); err != nil {
+				return err
+			}
+			if opts.output == outputTable {
+				fmt.Fprintf(cmd.OutOrStdout(), "\nDiagnosis: %s\n", report.Diagnosis)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&headless, "headless", false, "run the browser headless instead of showing it")
+	cmd.Flags().BoolVar(&noPause, "no-pause", false, "run every stage without pausing in between")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "print the browser client's log")
+
+	return cmd
+}
+
+// printStep reports a finished stage as the run progresses
+func printStep(w io.Writer, step browser.TroubleshootStep) {
+	result := "ok"
+	if !step.Passed {
+		result = "FAILED"
+	}
+	fmt.Fprintf(w, "[%d] %s: %s\n", step.Number, step.Name, result)
+	fmt.Fprintf(w, "    expected: %s\n", step.Expected)
+	fmt.Fprintf(w, "    found:    %s\n", step.Found)
+	if step.Screenshot != "" {
+		fmt.Fprintf(w, "    screenshot: %s\n", step.Screenshot)
+	}
+}
+
+// troubleshootTable formats a troubleshooting report for output
+func troubleshootTable(report *browser.TroubleshootReport) table {
+	t := table{
+		header: []string{"STEP", "NAME", "RESULT", "FOUND", "SCREENSHOT"},
+		value:  report,
+	}
+	if report.Steps == nil {
+		report.Steps = []browser.TroubleshootStep{}
+	}
+	for _, step := range report.Steps {
+		result := "ok"
+		if !step.Passed {
+			result = "failed"
+		}
+		t.rows = append(t.rows, []string{strconv.Itoa(step.Number), step.Name, result, step.Found, step.Screenshot})
+	}
+	return t
+}
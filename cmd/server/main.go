@@ -1,15 +1,45 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/benrowe/nab-bank-api/internal/api/handler"
+	"github.com/benrowe/nab-bank-api/internal/audit"
 	"github.com/benrowe/nab-bank-api/internal/browser"
+	"github.com/benrowe/nab-bank-api/internal/cache"
+	"github.com/benrowe/nab-bank-api/internal/cdr"
 	"github.com/benrowe/nab-bank-api/internal/config"
+	"github.com/benrowe/nab-bank-api/internal/demo"
+	"github.com/benrowe/nab-bank-api/internal/enrich"
+	"github.com/benrowe/nab-bank-api/internal/events"
+	"github.com/benrowe/nab-bank-api/internal/export"
+	"github.com/benrowe/nab-bank-api/internal/firefly"
+	"github.com/benrowe/nab-bank-api/internal/hooks"
+	"github.com/benrowe/nab-bank-api/internal/jobs"
+	"github.com/benrowe/nab-bank-api/internal/locator"
+	"github.com/benrowe/nab-bank-api/internal/middleware"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/notify"
+	"github.com/benrowe/nab-bank-api/internal/openapi"
+	"github.com/benrowe/nab-bank-api/internal/provider"
+	"github.com/benrowe/nab-bank-api/internal/query"
+	"github.com/benrowe/nab-bank-api/internal/ratewatch"
+	"github.com/benrowe/nab-bank-api/internal/redact"
+	"github.com/benrowe/nab-bank-api/internal/rpc"
+	"github.com/benrowe/nab-bank-api/internal/scheduler"
 	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/benrowe/nab-bank-api/internal/store"
+	"github.com/benrowe/nab-bank-api/internal/tokens"
+	"github.com/benrowe/nab-bank-api/internal/ynab"
 	"github.com/gorilla/mux"
 )
 
@@ -21,57 +51,587 @@ func main() {
 	}
 
 	// Initialize dependencies
-	logger := log.New(os.Stdout, "[NAB-API] ", log.LstdFlags|log.Lshortfile)
-	
-	// Choose client based on environment
-	var nabClient service.NABClient
-	if cfg.NAB.Username == "test" && cfg.NAB.Password == "test" {
-		// Use mock client for testing
-		logger.Println("Using mock NAB client for testing")
-		nabClient = service.NewMockNABClient()
-	} else {
-		// Use real browser client
-		logger.Println("Using real NAB browser client")
-		nabClient = browser.NewNABClient(&cfg.NAB, logger)
+	var output io.Writer = os.Stdout
+	if cfg.Server.LogRedaction {
+		redactor := redact.New(cfg.NAB.Username, cfg.NAB.Password, cfg.NAB.CDR.AccessToken, cfg.NAB.CDR.ClientSecret)
+		output = redactor.Writer(os.Stdout)
+		log.SetOutput(redactor.Writer(os.Stderr))
+	}
+	logger := log.New(output, "[NAB-API] ", log.LstdFlags|log.Lshortfile)
+
+	// Register the bank providers BANK_PROVIDERS can enable
+	var nabClient service.BankProvider
+	var findChrome func() (string, error)
+	providers := provider.NewRegistry()
+	providers.Register("nab", func(cfg *config.Config, logger *log.Logger) (service.BankProvider, error) {
+		nabClient, findChrome = newNABClient(cfg, logger)
+		return nabClient, nil
+	})
+	bankProvider, err := providers.Open(cfg.Server.BankProviders, cfg, logger)
+	if err != nil {
+		log.Fatalf("Failed to configure bank providers: %v", err)
+	}
+	if len(cfg.Server.BankProviders) > 1 {
+		logger.Printf("Serving accounts from bank providers %s", strings.Join(cfg.Server.BankProviders, ", "))
+	}
+	if features := cfg.Features.Names(); len(features) > 0 {
+		logger.Printf("Feature flags enabled: %s", strings.Join(features, ", "))
+	}
+
+	notifier, err := newNotifier(&cfg.Notify, logger)
+	if err != nil {
+		log.Fatalf("Failed to configure notifications: %v", err)
+	}
+
+	dataStore, err := store.Open(cfg.Store.Path)
+	if err != nil {
+		log.Fatalf("Failed to open store: %v", err)
+	}
+
+	auditLog, err := audit.Open(cfg.Store.AuditLogPath, logger)
+	if err != nil {
+		log.Fatalf("Failed to open audit log: %v", err)
+	}
+	logger.Printf("Recording API calls, transfers and payments in the audit log at %s", cfg.Store.AuditLogPath)
+
+	sharedCache, err := cache.New(cfg.Store.CacheURL, cfg.Store.CacheKeyPrefix)
+	if err != nil {
+		log.Fatalf("Failed to configure the cache backend: %v", err)
+	}
+	if sharedCache != nil {
+		cacheURL := cfg.Store.CacheURL
+		if parsed, err := url.Parse(cacheURL); err == nil {
+			cacheURL = parsed.Redacted()
+		}
+		if cfg.Store.CacheTTL <= 0 {
+			logger.Printf("CACHE_URL is set but CACHE_TTL is 0, so %s won't be used", cacheURL)
+		} else {
+			logger.Printf("Sharing scrape results through %s for %s", cacheURL, cfg.Store.CacheTTL)
+		}
+		if redis, ok := sharedCache.(*cache.Redis); ok {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := redis.Ping(ctx); err != nil {
+				logger.Printf("Cache backend unreachable, scraping NAB until it recovers: %v", err)
+			}
+			cancel()
+		}
+	}
+
+	var enricher service.MerchantEnricher
+	if cfg.Sync.EnrichMerchants {
+		enricher = enrich.NewPipeline(logger, enrich.NewRules())
+		logger.Printf("Merchant enrichment enabled using local rules")
+	}
+
+	eventBroker := events.NewBroker()
+	accountService := service.NewAccountService(bankProvider, dataStore, notifier, service.AlertThresholds{
+		LowBalance:       cfg.Notify.LowBalanceThreshold,
+		LargeTransaction: cfg.Notify.LargeTransactionThreshold,
+	}, service.RetryPolicy{
+		MaxAttempts:    cfg.NAB.RetryAttempts,
+		InitialBackoff: cfg.NAB.RetryBackoff,
+		MaxBackoff:     cfg.NAB.RetryMaxBackoff,
+		Jitter:         cfg.NAB.RetryJitter,
+	}, service.BreakerPolicy{
+		Threshold: cfg.NAB.BreakerThreshold,
+		Cooldown:  cfg.NAB.BreakerCooldown,
+	}, service.CachePolicy{
+		TTL:      cfg.Store.CacheTTL,
+		MaxStale: cfg.Store.CacheMaxStale,
+		Backend:  sharedCache,
+	}, enricher, eventBroker)
+	accountsHandler := handler.NewAccountsHandler(accountService, service.NewHistoryService(dataStore), dataStore, logger)
+
+	configReloader := &reloader{
+		nabClient: nabClient,
+		accounts:  accountService,
+		router:    notifier,
+		logger:    logger,
+	}
+	configReloader.watchSIGHUP()
+	configHandler := handler.NewConfigHandler(configReloader.reload, logger)
+
+	disputeService := service.NewDisputeService(accountService, bankProvider, dataStore)
+	disputeHandler := handler.NewDisputeHandler(disputeService, logger)
+
+	transferService := service.NewTransferService(accountService, bankProvider, auditLog)
+	transfersHandler := handler.NewTransfersHandler(transferService, logger)
+
+	paymentService := service.NewPaymentService(accountService, bankProvider, auditLog)
+	paymentsHandler := handler.NewPaymentsHandler(paymentService, logger)
+
+	messageService := service.NewMessageService(bankProvider, dataStore, notifier, cfg.Notify.MessageKeywords)
+	messagesHandler := handler.NewMessagesHandler(messageService, logger)
+
+	payeeService := service.NewPayeeService(bankProvider, notifier)
+	payeesHandler := handler.NewPayeesHandler(payeeService, logger)
+
+	scheduledPaymentService := service.NewScheduledPaymentService(bankProvider, notifier)
+	scheduledPaymentsHandler := handler.NewScheduledPaymentsHandler(scheduledPaymentService, logger)
+	directDebitService := service.NewDirectDebitService(bankProvider, notifier)
+	directDebitsHandler := handler.NewDirectDebitsHandler(directDebitService, logger)
+	goalsHandler := handler.NewGoalsHandler(service.NewSavingsGoalService(bankProvider, notifier), logger)
+	payIDService := service.NewPayIDService(bankProvider, notifier)
+	payIDsHandler := handler.NewPayIDsHandler(payIDService, logger)
+	cardService := service.NewCardService(bankProvider, notifier)
+	cardsHandler := handler.NewCardsHandler(cardService, logger)
+
+	redaction, err := export.ParseRedaction(cfg.Export.Redaction)
+	if err != nil {
+		log.Fatalf("Failed to configure export: %v", err)
+	}
+
+	var exporter *export.Exporter
+	if cfg.Export.Destination != "" {
+		sink, err := export.NewSink(cfg.Export.Destination, export.S3Credentials{
+			Region:          cfg.Export.AWSRegion,
+			Endpoint:        cfg.Export.S3Endpoint,
+			AccessKeyID:     cfg.Export.AWSAccessKeyID,
+			SecretAccessKey: cfg.Export.AWSSecretAccessKey,
+			SessionToken:    cfg.Export.AWSSessionToken,
+		})
+		if err != nil {
+			log.Fatalf("Failed to configure export: %v", err)
+		}
+		exporter = export.NewExporter(dataStore, sink, cfg.Export.RedactionSalt)
+	}
+	exportHandler := handler.NewExportHandler(exporter, redaction, logger)
+
+	queryEngine := query.NewEngine(dataStore, cfg.Query.DuckDBPath, cfg.Query.Timeout, cfg.Query.MaxRows)
+	if !queryEngine.Available() {
+		logger.Printf("duckdb not found at %q, ad-hoc queries are disabled", cfg.Query.DuckDBPath)
+	}
+	queryHandler := handler.NewQueryHandler(queryEngine, logger)
+
+	ratesHandler := handler.NewRatesHandler(dataStore, logger)
+	searchHandler := handler.NewSearchHandler(service.NewSearchService(dataStore), logger)
+	transactionsHandler := handler.NewTransactionsHandler(service.NewTransactionsService(accountService, cfg.Sync.Concurrency), dataStore, logger)
+	v2Handler := handler.NewV2Handler(accountService, dataStore, logger)
+	if cfg.RateWatch.Enabled {
+		pages := cfg.RateWatch.Pages
+		if len(pages) == 0 {
+			pages = ratewatch.DefaultPages
+		}
+		watcher := ratewatch.NewWatcher(browser.NewPageFetcher(&cfg.NAB, logger), dataStore, notifier, pages, logger)
+		go watcher.Run(context.Background(), cfg.RateWatch.Interval)
+		logger.Printf("Watching %d NAB rate pages every %s", len(pages), cfg.RateWatch.Interval)
+	}
+
+	hooksHandler := handler.NewHooksHandler(dataStore, logger)
+	annotationService := service.NewAnnotationService(dataStore, cfg.Store.TrashRetention)
+	annotationsHandler := handler.NewAnnotationsHandler(annotationService, logger)
+	metadataHandler := handler.NewAccountMetadataHandler(service.NewAccountMetadataService(dataStore), logger)
+	jobManager := jobs.NewManager(cfg.Server.JobRetention)
+	categoryService := service.NewCategoryService(dataStore)
+	categoriesHandler := handler.NewCategoriesHandler(categoryService, logger)
+
+	budgetService := service.NewBudgetService(dataStore, notifier, logger)
+	budgetsHandler := handler.NewBudgetsHandler(budgetService, logger)
+	if cfg.Notify.BudgetCheckInterval > 0 {
+		go budgetService.Run(context.Background(), cfg.Notify.BudgetCheckInterval)
+		logger.Printf("Checking budgets every %s", cfg.Notify.BudgetCheckInterval)
+	}
+	reportsHandler := handler.NewReportsHandler(service.NewReportService(dataStore), logger)
+
+	bulkHandler := handler.NewBulkHandler(service.NewBulkService(dataStore, annotationService, categoryService, jobManager), logger)
+	eventsHandler := handler.NewEventsHandler(eventBroker, logger)
+	syncService := service.NewSyncService(accountService, jobManager, eventBroker)
+	syncHandler := handler.NewSyncHandler(syncService, logger)
+	syncReporter := service.NewSyncReporter(bankProvider, eventBroker)
+	operationsHandler := handler.NewOperationsHandler(service.NewOperationsService(bankProvider, accountService, syncService, syncReporter), logger)
+	dashboardHandler := handler.NewDashboardHandler(service.NewDashboardService(accountService, dataStore, syncReporter), logger)
+	auditHandler := handler.NewAuditHandler(auditLog, logger)
+	reconcileHandler := handler.NewReconcileHandler(service.NewBalanceAssertionService(dataStore, notifier), logger)
+	ynabAccounts, err := ynab.ParseAccounts(cfg.Export.YNABAccounts)
+	if err != nil {
+		log.Fatalf("Failed to configure YNAB: %v", err)
+	}
+	ynabHandler := handler.NewYNABHandler(dataStore, ynabAccounts, logger)
+
+	var feeds []scheduler.Feed
+	if cfg.Export.FireflyURL != "" {
+		feeds = append(feeds, firefly.NewSyncer(firefly.NewClient(cfg.Export.FireflyURL, cfg.Export.FireflyToken), dataStore, logger))
+		if cfg.Sync.Schedule == "" && cfg.Sync.Interval <= 0 {
+			logger.Printf("FIREFLY_URL is set but nothing will be pushed without SYNC_INTERVAL or SYNC_SCHEDULE")
+		}
+	}
+	if cfg.Export.YNABToken != "" {
+		feeds = append(feeds, ynab.NewPusher(ynab.NewClient("", cfg.Export.YNABToken), dataStore, cfg.Export.YNABBudgetID, ynabAccounts, logger))
+		if cfg.Sync.Schedule == "" && cfg.Sync.Interval <= 0 {
+			logger.Printf("YNAB_TOKEN is set but nothing will be pushed without SYNC_INTERVAL or SYNC_SCHEDULE")
+		}
+	}
+	if cfg.Sync.Schedule != "" || cfg.Sync.Interval > 0 {
+		var schedule scheduler.Schedule = scheduler.Every(cfg.Sync.Interval)
+		if cfg.Sync.Schedule != "" {
+			if schedule, err = scheduler.ParseSchedule(cfg.Sync.Schedule, cfg.Sync.Timezone); err != nil {
+				logger.Fatalf("Failed to load sync schedule: %v", err)
+			}
+		}
+
+		syncScheduler := scheduler.NewScheduler(accountService, dataStore, hooks.NewCaller(cfg.Sync.HookTimeout), cfg.Sync.MaxHookDelay, cfg.Sync.Concurrency, eventBroker, logger, feeds...)
+		go syncScheduler.Run(context.Background(), schedule)
+		if cfg.Sync.Schedule != "" {
+			logger.Printf("Syncing accounts on the %s schedule (%s)", cfg.Sync.Schedule, cfg.Sync.Timezone)
+		} else {
+			logger.Printf("Syncing accounts every %s", cfg.Sync.Interval)
+		}
+		if cfg.Export.FireflyURL != "" {
+			logger.Printf("Pushing accounts and transactions to Firefly III at %s after each sync", cfg.Export.FireflyURL)
+		}
+		if cfg.Export.YNABToken != "" {
+			logger.Printf("Pushing transactions on %d accounts to YNAB after each sync", len(ynabAccounts))
+		}
+	}
+
+	locatorHandler := handler.NewLocatorHandler(locator.NewClient(cfg.Locator.URL, cfg.Locator.APIKey, cfg.Locator.CacheTTL), logger)
+
+	graphqlHandler, err := handler.NewGraphQLHandler(accountService, dataStore, logger)
+	if err != nil {
+		log.Fatalf("Failed to build GraphQL schema: %v", err)
+	}
+
+	// API_KEYS become managed tokens so they can be rotated and revoked
+	tokenManager := tokens.NewManager(dataStore, cfg.Auth.TokenTTL, cfg.Auth.RotationGrace)
+	if imported, err := tokenManager.Import(cfg.Auth.APIKeys, "API_KEYS"); err != nil {
+		log.Fatalf("Failed to import API keys: %v", err)
+	} else if imported > 0 {
+		logger.Printf("Imported %d API_KEYS as tokens that never expire; rotate them through /admin/tokens", imported)
+	}
+
+	usageTracker := middleware.NewUsageTracker(cfg.Auth.AdminKeys, tokenManager, dataStore)
+	go usageTracker.Run(context.Background(), time.Minute)
+	adminHandler := handler.NewAdminHandler(usageTracker, tokenManager, logger)
+	sessionHandler := handler.NewSessionHandler(service.NewSessionService(bankProvider), logger)
+
+	if cfg.NAB.SessionDir != "" && cfg.NAB.BrowserRemoteURL == "" {
+		if cfg.NAB.SessionKey != "" {
+			logger.Printf("Encrypting the browser session saved in %s", cfg.NAB.SessionDir)
+		} else {
+			logger.Printf("Warning: the browser session in %s is saved unencrypted; set BROWSER_SESSION_KEY to encrypt it", cfg.NAB.SessionDir)
+		}
+	}
+
+	warmUp := service.NewWarmUp(bankProvider, cfg.NAB.WarmUp)
+	if keepAlive := service.NewKeepAlive(bankProvider, cfg.NAB.KeepAlive, cfg.NAB.KeepAliveJitter, logger); keepAlive != nil {
+		if cfg.NAB.SessionDir == "" {
+			logger.Println("BROWSER_SESSION_KEEPALIVE is set without BROWSER_SESSION_DIR, so there is no session to keep alive")
+		} else {
+			logger.Printf("Keeping the NAB session alive every %s", cfg.NAB.KeepAlive)
+			go keepAlive.Run(context.Background())
+		}
+	}
+	healthHandler := handler.NewHealthHandler(warmUp, accountService, bankProvider, dataStore, logger)
+	metricsHandler := handler.NewMetricsHandler(bankProvider, logger)
+	probeHandler := handler.NewProbeHandler(cfg, warmUp, dataStore, findChrome, logger)
+
+	openAPIHandler, err := openapi.SpecHandler(handler.OpenAPIDocument())
+	if err != nil {
+		log.Fatalf("Failed to build OpenAPI document: %v", err)
 	}
-	
-	accountService := service.NewAccountService(nabClient)
-	accountsHandler := handler.NewAccountsHandler(accountService, logger)
 
 	// Setup routes
 	router := mux.NewRouter()
-	
+
 	// Health check
-	router.HandleFunc("/health", healthHandler).Methods("GET")
-	
+	router.HandleFunc("/health", healthCheckHandler).Methods("GET")
+	router.HandleFunc("/health/ready", healthHandler.Ready).Methods("GET")
+	router.HandleFunc("/health/detailed", healthHandler.Detailed).Methods("GET")
+	router.HandleFunc("/metrics", metricsHandler.Metrics).Methods("GET")
+	router.HandleFunc("/livez", probeHandler.Livez).Methods("GET")
+	router.HandleFunc("/readyz", probeHandler.Readyz).Methods("GET")
+
 	// Hello world (for backward compatibility)
 	router.HandleFunc("/", helloHandler).Methods("GET")
-	
+
+	// API documentation
+	router.HandleFunc("/openapi.json", openAPIHandler).Methods("GET")
+	router.HandleFunc("/docs", openapi.DocsHandler).Methods("GET")
+
+	// GraphQL
+	router.HandleFunc("/graphql", graphqlHandler.ServeGraphQL).Methods("GET", "POST")
+
+	// API versions. v1 stays for existing clients while breaking model
+	// changes land in v2; announcing a v1 deprecation or sunset date marks
+	// its responses with Deprecation and Sunset headers.
+	v1Version := model.APIVersion{Version: "v1", BasePath: "/api/v1", Status: model.APIVersionStatusSupported, Successor: "v2"}
+	if !cfg.Server.V1Deprecated.IsZero() {
+		v1Version.Status = model.APIVersionStatusDeprecated
+		v1Version.Deprecated = &cfg.Server.V1Deprecated
+	}
+	if !cfg.Server.V1Sunset.IsZero() {
+		v1Version.Sunset = &cfg.Server.V1Sunset
+	}
+	v2Version := model.APIVersion{Version: "v2", BasePath: "/api/v2", Status: model.APIVersionStatusCurrent}
+	versionsHandler := handler.NewVersionsHandler([]model.APIVersion{v1Version, v2Version}, logger)
+	router.HandleFunc("/api", versionsHandler.ListVersions).Methods("GET")
+	v1Deprecation := middleware.Deprecation(v1Version, v2Version.BasePath, router)
+
 	// API v1 routes
-	v1 := router.PathPrefix("/api/v1").Subrouter()
+	v1 := router.PathPrefix(v1Version.BasePath).Subrouter()
+	v1.Use(v1Deprecation)
 	v1.HandleFunc("/accounts", accountsHandler.ListAccounts).Methods("GET")
 	v1.HandleFunc("/accounts/{accountId}", accountsHandler.GetAccount).Methods("GET")
+	v1.HandleFunc("/accounts/{accountId}/direct-debits", directDebitsHandler.ListDirectDebits).Methods("GET")
+	v1.HandleFunc("/accounts/{accountId}/goals", goalsHandler.ListGoals).Methods("GET")
+	v1.HandleFunc("/messages", messagesHandler.ListMessages).Methods("GET")
+	v1.HandleFunc("/payees", payeesHandler.ListPayees).Methods("GET")
+	v1.HandleFunc("/payids", payIDsHandler.ListPayIDs).Methods("GET")
+	v1.HandleFunc("/cards", cardsHandler.ListCards).Methods("GET")
+	v1.HandleFunc("/payments/scheduled", scheduledPaymentsHandler.ListScheduledPayments).Methods("GET")
+	v1.HandleFunc("/locator", locatorHandler.Search).Methods("GET")
+	v1.HandleFunc("/rates", ratesHandler.ListRates).Methods("GET")
+	v1.HandleFunc("/transactions", transactionsHandler.ListTransactions).Methods("GET")
+	v1.HandleFunc("/transactions/search", searchHandler.SearchTransactions).Methods("GET")
+	v1.HandleFunc("/categories", categoriesHandler.ListCategories).Methods("GET")
+	v1.HandleFunc("/budgets", budgetsHandler.ListBudgets).Methods("GET")
+	v1.HandleFunc("/reports/spending", reportsHandler.Spending).Methods("GET")
+	v1.HandleFunc("/reports/net-worth", reportsHandler.NetWorth).Methods("GET")
+	v1.HandleFunc("/reports/fees", reportsHandler.Fees).Methods("GET")
+	v1.HandleFunc("/reports/subscriptions", reportsHandler.Subscriptions).Methods("GET")
+	v1.HandleFunc("/dashboard", dashboardHandler.Dashboard).Methods("GET")
+	v1.HandleFunc("/categories/rules", categoriesHandler.ListRules).Methods("GET")
+	v1.HandleFunc("/exports/ynab", ynabHandler.ExportYNAB).Methods("GET")
+
+	// Authenticated API v1 routes
+	authenticated := v1.NewRoute().Subrouter()
+	authenticated.Use(middleware.TokenAuth(tokenManager))
+	authenticated.Use(middleware.Idempotency(dataStore, tokenManager, cfg.Server.IdempotencyKeyTTL, logger))
+	authenticated.HandleFunc("/query", queryHandler.RunQuery).Methods("POST")
+	authenticated.HandleFunc("/exports/parquet", exportHandler.ExportParquet).Methods("POST")
+	authenticated.HandleFunc("/accounts/{accountId}/transactions/{transactionId}/dispute", disputeHandler.PrepareDispute).Methods("POST")
+	payments := authenticated.NewRoute().Subrouter()
+	payments.Use(middleware.Feature(config.FeaturePayments, cfg.Features.Enabled(config.FeaturePayments)))
+	payments.HandleFunc("/transfers", transfersHandler.CreateTransfer).Methods("POST")
+	payments.HandleFunc("/payments/payanyone", paymentsHandler.PayAnyone).Methods("POST")
+	payments.HandleFunc("/payments/{paymentId}/authorize", paymentsHandler.AuthorizePayment).Methods("POST")
+	authenticated.HandleFunc("/cards/{cardId}/lock", cardsHandler.LockCard).Methods("POST")
+	authenticated.HandleFunc("/cards/{cardId}/unlock", cardsHandler.UnlockCard).Methods("POST")
+	authenticated.HandleFunc("/accounts/{accountId}/hooks", hooksHandler.ListHooks).Methods("GET")
+	authenticated.HandleFunc("/accounts/{accountId}/hooks", hooksHandler.CreateHook).Methods("POST")
+	authenticated.HandleFunc("/accounts/{accountId}/hooks/{hookId}", hooksHandler.DeleteHook).Methods("DELETE")
+	authenticated.HandleFunc("/accounts/{accountId}/balance-assertions", reconcileHandler.AssertBalance).Methods("POST")
+	authenticated.HandleFunc("/accounts/{accountId}/transactions/{transactionId}/annotations", annotationsHandler.ListAnnotations).Methods("GET")
+	authenticated.HandleFunc("/accounts/{accountId}/transactions/{transactionId}/annotations", annotationsHandler.CreateAnnotation).Methods("POST")
+	authenticated.HandleFunc("/accounts/{accountId}/metadata", metadataHandler.UpdateMetadata).Methods("PATCH")
+	authenticated.HandleFunc("/annotations/trash", annotationsHandler.ListTrash).Methods("GET")
+	authenticated.HandleFunc("/annotations/{annotationId}", annotationsHandler.DeleteAnnotation).Methods("DELETE")
+	authenticated.HandleFunc("/annotations/{annotationId}/restore", annotationsHandler.RestoreAnnotation).Methods("POST")
+	authenticated.HandleFunc("/categories/rules", categoriesHandler.CreateRule).Methods("POST")
+	authenticated.HandleFunc("/categories/rules/{ruleId}", categoriesHandler.UpdateRule).Methods("PUT")
+	authenticated.HandleFunc("/categories/rules/{ruleId}", categoriesHandler.DeleteRule).Methods("DELETE")
+	authenticated.HandleFunc("/budgets", budgetsHandler.CreateBudget).Methods("POST")
+	authenticated.HandleFunc("/budgets/{budgetId}", budgetsHandler.UpdateBudget).Methods("PUT")
+	authenticated.HandleFunc("/budgets/{budgetId}", budgetsHandler.DeleteBudget).Methods("DELETE")
+	authenticated.HandleFunc("/bulk", bulkHandler.SubmitBulk).Methods("POST")
+	authenticated.HandleFunc("/sync", syncHandler.StartSync).Methods("POST")
+	authenticated.HandleFunc("/events", eventsHandler.Stream).Methods("GET")
+	authenticated.HandleFunc("/jobs/{jobId}", bulkHandler.GetJob).Methods("GET")
+
+	// API v2 routes
+	v2 := router.PathPrefix(v2Version.BasePath).Subrouter()
+	v2.HandleFunc("/accounts", v2Handler.ListAccounts).Methods("GET")
+	v2.HandleFunc("/accounts/{accountId}", v2Handler.GetAccount).Methods("GET")
+	v2.HandleFunc("/transactions", v2Handler.ListTransactions).Methods("GET")
+
+	// Basiq compatibility routes
+	if cfg.Server.BasiqCompat {
+		basiqHandler := handler.NewBasiqHandler(dataStore, tokenManager, cfg.Server.BasiqInstitution, logger)
+		router.HandleFunc("/basiq/token", basiqHandler.Token).Methods("POST")
+		basiqRoutes := router.PathPrefix("/basiq/users/{userId}").Subrouter()
+		basiqRoutes.Use(middleware.TokenAuth(tokenManager))
+		basiqRoutes.HandleFunc("/accounts", basiqHandler.ListAccounts).Methods("GET")
+		basiqRoutes.HandleFunc("/accounts/{accountId}", basiqHandler.GetAccount).Methods("GET")
+		basiqRoutes.HandleFunc("/transactions", basiqHandler.ListTransactions).Methods("GET")
+		basiqRoutes.HandleFunc("/transactions/{transactionId}", basiqHandler.GetTransaction).Methods("GET")
+	}
+
+	// Admin routes
+	admin := router.PathPrefix("/admin").Subrouter()
+	admin.Use(middleware.APIKeyAuth(cfg.Auth.AdminKeys))
+	admin.HandleFunc("/tokens", adminHandler.ListTokens).Methods("GET")
+	admin.HandleFunc("/tokens", adminHandler.CreateToken).Methods("POST")
+	admin.HandleFunc("/tokens/{tokenId}", adminHandler.RevokeToken).Methods("DELETE")
+	admin.HandleFunc("/tokens/{tokenId}/rotate", adminHandler.RotateToken).Methods("POST")
+	admin.HandleFunc("/tokens/{tokenId}/usage", adminHandler.TokenUsage).Methods("GET")
+	admin.HandleFunc("/config/reload", configHandler.Reload).Methods("POST")
+
+	// Admin API v1 routes
+	v1Admin := router.PathPrefix("/api/v1/admin").Subrouter()
+	v1Admin.Use(v1Deprecation)
+	v1Admin.Use(middleware.APIKeyAuth(cfg.Auth.AdminKeys))
+	v1Admin.Use(middleware.Idempotency(dataStore, tokenManager, cfg.Server.IdempotencyKeyTTL, logger))
+	v1Admin.HandleFunc("/session/logout", sessionHandler.Logout).Methods("POST")
+	v1Admin.HandleFunc("/debug/screenshot", sessionHandler.Screenshot).Methods("GET")
+	v1Admin.HandleFunc("/sync", operationsHandler.ForceSync).Methods("POST")
+	v1Admin.HandleFunc("/sync/last", operationsHandler.LastSync).Methods("GET")
+	v1Admin.HandleFunc("/cache", operationsHandler.CacheStats).Methods("GET")
+	v1Admin.HandleFunc("/cache", operationsHandler.ClearCache).Methods("DELETE")
+	v1Admin.HandleFunc("/cache/entries", operationsHandler.EvictCache).Methods("DELETE")
+	v1Admin.HandleFunc("/browser", operationsHandler.SetBrowserMode).Methods("PUT")
+	v1Admin.HandleFunc("/audit", auditHandler.ListEntries).Methods("GET")
 
 	// Add middleware
 	router.Use(loggingMiddleware(logger))
+	router.Use(middleware.Audit(auditLog))
+	router.Use(usageTracker.Middleware)
 	router.Use(corsMiddleware)
+	router.Use(middleware.Timeout(middleware.RouteTimeouts{
+		Default:      cfg.Server.RequestTimeout,
+		Accounts:     cfg.Server.AccountsTimeout,
+		Transactions: cfg.Server.TransactionsTimeout,
+		Sync:         cfg.Server.SyncTimeout,
+	}))
+
+	if cfg.Server.GRPCEnabled {
+		listener, err := net.Listen("tcp", ":"+cfg.Server.GRPCPort)
+		if err != nil {
+			log.Fatalf("Failed to listen for gRPC: %v", err)
+		}
+
+		grpcServer := rpc.NewServer(accountService, logger)
+		go func() {
+			logger.Printf("gRPC server starting on port %s", cfg.Server.GRPCPort)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("gRPC server failed: %v", err)
+			}
+		}()
+	}
+
+	// Log in once everything else is set up, so the first request doesn't
+	// wait for the browser to start
+	if cfg.NAB.WarmUp {
+		go func() {
+			if err := warmUp.Run(context.Background()); err != nil {
+				logger.Printf("Browser warm-up failed, requests will log in on demand: %v", err)
+			}
+		}()
+	}
 
 	logger.Printf("Server starting on port %s", cfg.Server.Port)
 	logger.Printf("API endpoints:")
 	logger.Printf("  GET /health - Health check")
-	logger.Printf("  GET /api/v1/accounts - List all accounts")
-	logger.Printf("  GET /api/v1/accounts/{id} - Get account details")
-	
+	logger.Printf("  GET /health/ready - Readiness, including browser warm-up status")
+	logger.Printf("  GET /health/detailed - Scrape, session, browser and cache health")
+	logger.Printf("  GET /metrics - Prometheus metrics for each step of the NAB login and scrape")
+	logger.Printf("  GET /livez - Liveness probe")
+	logger.Printf("  GET /readyz - Readiness probe")
+	logger.Printf("  GET /openapi.json - OpenAPI specification")
+	logger.Printf("  GET /docs - Swagger UI")
+	logger.Printf("  GET /api - API versions and their deprecation status")
+	logger.Printf("  GET /api/v1/accounts?type=&minBalance=&sort=&limit=&cursor=&asOf= - List, filter and sort accounts, optionally as they were at a past time")
+	logger.Printf("  GET /api/v1/accounts/{id}?asOf=&statement= - Get account details")
+	logger.Printf("  GET /api/v1/accounts/{id}/direct-debits - List direct debit authorities")
+	logger.Printf("  GET /api/v1/accounts/{id}/goals - List savings goals and their progress")
+	logger.Printf("  GET /api/v1/messages - List secure inbox messages")
+	logger.Printf("  GET /api/v1/payees - List saved payees")
+	logger.Printf("  GET /api/v1/payids - List registered PayIDs")
+	logger.Printf("  GET /api/v1/cards - List debit and credit cards")
+	logger.Printf("  GET /api/v1/payments/scheduled - List upcoming scheduled payments")
+	logger.Printf("  GET /api/v1/locator?lat=&lng= - Nearby NAB ATMs and branches")
+	logger.Printf("  GET /api/v1/rates - Advertised rates from NAB product pages")
+	logger.Printf("  GET /api/v1/transactions?accountId=&refresh=&limit=&cursor= - Page through stored transactions across accounts")
+	logger.Printf("  GET /api/v1/transactions/search?q=&amountMin=&amountMax= - Search stored transactions across accounts")
+	logger.Printf("  GET /api/v1/categories - List categories in use")
+	logger.Printf("  GET /api/v1/categories/rules - List category rules")
+	logger.Printf("  GET /api/v1/budgets - Budgets with spending this period")
+	logger.Printf("  GET /api/v1/reports/spending - Monthly spending by category and merchant")
+	logger.Printf("  GET /api/v1/reports/net-worth - Assets less liabilities across accounts, with history")
+	logger.Printf("  GET /api/v1/reports/fees - Fees per account per month, by kind of fee")
+	logger.Printf("  GET /api/v1/reports/subscriptions - Recurring charges with their monthly cost and price rises")
+	logger.Printf("  GET /api/v1/dashboard - Accounts, recent transactions, totals and the last sync in one payload")
+	logger.Printf("  GET /api/v1/exports/ynab?accountId= - Export an account's transactions for YNAB")
+	logger.Printf("  GET|POST /graphql - GraphQL API")
+	logger.Printf("  POST /api/v1/query - Read-only SQL over stored data (API key required)")
+	logger.Printf("  POST /api/v1/exports/parquet?redact={none|hash|bucket} - Export stored data as Parquet (API key required)")
+	logger.Printf("  POST /api/v1/accounts/{id}/transactions/{txnId}/dispute - Prepare a dispute summary (API key required)")
+	logger.Printf("  POST /api/v1/transfers - Transfer between own accounts (API key required)")
+	logger.Printf("  POST /api/v1/payments/payanyone - Pay Anyone to a saved or new payee (API key required)")
+	logger.Printf("  POST /api/v1/payments/{id}/authorize - Authorise a payment with its SMS code (API key required)")
+	logger.Printf("  POST /api/v1/cards/{id}/lock|unlock - Temporarily block or unblock a card (API key required)")
+	logger.Printf("  GET|POST /api/v1/accounts/{id}/hooks - Refresh hooks for scheduled scrapes (API key required)")
+	logger.Printf("  DELETE /api/v1/accounts/{id}/hooks/{hookId} - Remove a refresh hook (API key required)")
+	logger.Printf("  POST /api/v1/accounts/{id}/balance-assertions - Check an expected balance (API key required)")
+	logger.Printf("  PATCH /api/v1/accounts/{id}/metadata - Nickname, emoji, colour and notes for an account (API key required)")
+	logger.Printf("  GET|POST /api/v1/accounts/{id}/transactions/{txnId}/annotations - Tags and notes on a transaction (API key required)")
+	logger.Printf("  DELETE /api/v1/annotations/{id} - Move an annotation to the trash (API key required)")
+	logger.Printf("  POST /api/v1/annotations/{id}/restore - Restore an annotation from the trash (API key required)")
+	logger.Printf("  GET /api/v1/annotations/trash - List restorable deleted annotations (API key required)")
+	logger.Printf("  POST /api/v1/categories/rules, PUT/DELETE /api/v1/categories/rules/{id} - Manage category rules (API key required)")
+	logger.Printf("  POST /api/v1/budgets, PUT/DELETE /api/v1/budgets/{id} - Manage budgets (API key required)")
+	logger.Printf("  POST /api/v1/bulk - Tag, recategorise or archive in bulk as a background job (API key required)")
+	logger.Printf("  POST /api/v1/sync - Sync every account as a background job (API key required)")
+	logger.Printf("  GET /api/v1/events - Stream sync progress and new transactions as Server-Sent Events (API key required)")
+	logger.Printf("  GET /api/v1/jobs/{id} - Background job progress and per-item results (API key required)")
+	logger.Printf("  GET /api/v2/accounts?limit=&cursor=, GET /api/v2/accounts/{id} - Accounts with money in cents")
+	logger.Printf("  GET /api/v2/transactions?accountId=&limit=&cursor= - Stored transactions with money in cents and dates as times")
+	if v1Version.Deprecated != nil {
+		logger.Printf("API v1 is deprecated as of %s", v1Version.Deprecated.Format("2006-01-02"))
+	}
+	if cfg.Server.RequestTimeout > 0 || cfg.Server.AccountsTimeout > 0 || cfg.Server.TransactionsTimeout > 0 || cfg.Server.SyncTimeout > 0 {
+		logger.Printf("Request timeouts: default %s, accounts %s, transactions %s, sync %s",
+			cfg.Server.RequestTimeout, cfg.Server.AccountsTimeout, cfg.Server.TransactionsTimeout, cfg.Server.SyncTimeout)
+	}
+	logger.Printf("  GET|POST /admin/tokens - List or create API tokens (admin key required)")
+	logger.Printf("  POST /admin/tokens/{id}/rotate - Rotate an API token (admin key required)")
+	logger.Printf("  DELETE /admin/tokens/{id} - Revoke an API token (admin key required)")
+	logger.Printf("  GET /admin/tokens/{id}/usage - Usage for one API token (admin key required)")
+	logger.Printf("  POST /admin/config/reload - Reload selectors, cache TTLs, alert thresholds and notifications (admin key required)")
+	logger.Printf("  POST /api/v1/admin/session/logout - Log out of NAB and clear the saved session (admin key required)")
+	logger.Printf("  GET /api/v1/admin/debug/screenshot - PNG of the page NAB shows the saved session (admin key required)")
+	logger.Printf("  POST /api/v1/admin/sync - Clear the cache and sync every account (admin key required)")
+	logger.Printf("  GET /api/v1/admin/sync/last - Report on the last sync (admin key required)")
+	logger.Printf("  GET /api/v1/admin/cache - Cache hits, misses and entries with their ages and sizes (admin key required)")
+	logger.Printf("  DELETE /api/v1/admin/cache - Clear the cache (admin key required)")
+	logger.Printf("  DELETE /api/v1/admin/cache/entries?key= - Evict specific cache entries (admin key required)")
+	logger.Printf("  PUT /api/v1/admin/browser - Switch the browser between headless and visible (admin key required)")
+	logger.Printf("  GET /api/v1/admin/audit?actor=&kind=&result=&since=&until=&limit= - Query the audit log of API calls, transfers and payments (admin key required)")
+	if cfg.Server.BasiqCompat {
+		logger.Printf("  POST /basiq/token, GET /basiq/users/{userId}/accounts|transactions - Basiq compatible API (API key required)")
+	}
+
 	if err := http.ListenAndServe(":"+cfg.Server.Port, router); err != nil {
 		log.Fatal(err)
 	}
 }
 
+// newNotifier builds a notification router from the configured push channels
+func newNotifier(cfg *config.NotifyConfig, logger *log.Logger) (*notify.Router, error) {
+	channels, routes, err := notifierChannels(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, channel := range channels {
+		logger.Printf("Push notifications enabled via %s", channel.Name())
+	}
+
+	return notify.NewRouter(channels, routes, logger), nil
+}
+
+// notifierChannels returns the configured push channels and the routes
+// between them
+func notifierChannels(cfg *config.NotifyConfig) ([]notify.Notifier, map[notify.Event][]string, error) {
+	var channels []notify.Notifier
+	if cfg.NtfyTopic != "" {
+		channels = append(channels, notify.NewNtfyNotifier(cfg.NtfyURL, cfg.NtfyTopic, cfg.NtfyToken))
+	}
+	if cfg.PushoverToken != "" && cfg.PushoverUserKey != "" {
+		channels = append(channels, notify.NewPushoverNotifier(cfg.PushoverToken, cfg.PushoverUserKey))
+	}
+
+	routes, err := notify.ParseRoutes(cfg.Routes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return channels, routes, nil
+}
+
 func helloHandler(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintf(w, "Hello, World! NAB Bank API is running.\n")
 }
 
-func healthHandler(w http.ResponseWriter, r *http.Request) {
+func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	fmt.Fprintf(w, "OK\n")
 }
@@ -90,14 +650,56 @@ func loggingMiddleware(logger *log.Logger) mux.MiddlewareFunc {
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match")
+		w.Header().Set("Access-Control-Expose-Headers", "ETag, X-Next-Cursor, Deprecation, Sunset, Link")
+
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
 			return
 		}
-		
+
 		next.ServeHTTP(w, r)
 	})
 }
+
+// newNABClient chooses how NAB is read based on the configuration. The
+// Chrome lookup used by the readiness probe is returned when a browser
+// will be launched.
+func newNABClient(cfg *config.Config, logger *log.Logger) (service.BankProvider, func() (string, error)) {
+	switch {
+	case cfg.NAB.Demo:
+		// Serve synthetic data, keeping it out of the real store
+		logger.Printf("Demo mode: serving synthetic data generated from seed %d", cfg.NAB.DemoSeed)
+		cfg.Store.Path = ""
+		cfg.RateWatch.Enabled = false
+		return demo.NewClient(int64(cfg.NAB.DemoSeed)), nil
+	case cfg.NAB.ReplayDir != "":
+		// Replay recorded pages without launching a browser
+		logger.Printf("Replaying recorded NAB pages from %s", cfg.NAB.ReplayDir)
+		return browser.NewReplayClient(cfg.NAB.ReplayDir, logger), nil
+	case cfg.NAB.DataSource == "cdr":
+		// Read the Consumer Data Right APIs instead of scraping
+		logger.Printf("Using CDR open banking data from %s", cfg.NAB.CDR.BaseURL)
+		return cdr.NewClient(cfg.NAB.CDR.BaseURL, cdr.Credentials{
+			AccessToken:  cfg.NAB.CDR.AccessToken,
+			TokenURL:     cfg.NAB.CDR.TokenURL,
+			ClientID:     cfg.NAB.CDR.ClientID,
+			ClientSecret: cfg.NAB.CDR.ClientSecret,
+			Scope:        cfg.NAB.CDR.Scope,
+		}), nil
+	case cfg.NAB.Username == "test" && cfg.NAB.Password == "test":
+		// Use mock client for testing
+		logger.Println("Using mock NAB client for testing")
+		return service.NewMockNABClient(), nil
+	default:
+		// Use real browser client, with no local Chrome needed when it
+		// connects to a remote browser
+		if cfg.NAB.BrowserRemoteURL != "" {
+			logger.Println("Using real NAB browser client with a remote browser")
+			return browser.NewNABClient(&cfg.NAB, logger), nil
+		}
+		logger.Println("Using real NAB browser client")
+		return browser.NewNABClient(&cfg.NAB, logger), browser.FindChrome
+	}
+}
@@ -37,7 +37,7 @@ func TestHealthHandler(t *testing.T) {
 	}
 
 	rr := httptest.NewRecorder()
-	handler := http.HandlerFunc(healthHandler)
+	handler := http.HandlerFunc(healthCheckHandler)
 
 	handler.ServeHTTP(rr, req)
 
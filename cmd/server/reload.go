@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/config"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/notify"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// selectorReloader is implemented by NAB clients that can pick up new
+// interstitial rules and device profiles without restarting the browser
+type selectorReloader interface {
+	ReloadSelectors(cfg *config.NABConfig)
+}
+
+// reloader applies a reloaded configuration to the running services.
+// Only selectors, cache TTLs, alert thresholds and notification settings
+// change; the rest of the configuration needs a restart.
+type reloader struct {
+	mu        sync.Mutex
+	nabClient service.BankProvider
+	accounts  service.AccountService
+	router    *notify.Router
+	logger    *log.Logger
+}
+
+// reload loads the configuration again and applies it. Nothing is applied
+// unless the whole configuration is valid.
+func (r *reloader) reload() (*model.ConfigReloadResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration, keeping the running one: %w", err)
+	}
+	channels, routes, err := notifierChannels(&cfg.Notify)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notification settings, keeping the running ones: %w", err)
+	}
+
+	var reloaded []string
+	if client, ok := r.nabClient.(selectorReloader); ok {
+		client.ReloadSelectors(&cfg.NAB)
+		reloaded = append(reloaded, "selectors")
+	}
+	if accounts, ok := r.accounts.(service.ReloadableService); ok {
+		accounts.SetCacheTTL(cfg.Store.CacheTTL, cfg.Store.CacheMaxStale)
+		accounts.SetAlertThresholds(service.AlertThresholds{
+			LowBalance:       cfg.Notify.LowBalanceThreshold,
+			LargeTransaction: cfg.Notify.LargeTransactionThreshold,
+		})
+		reloaded = append(reloaded, "cache", "alerts")
+	}
+	r.router.Update(channels, routes)
+	reloaded = append(reloaded, "notifications")
+
+	r.logger.Printf("Reloaded configuration: %s", strings.Join(reloaded, ", "))
+	return &model.ConfigReloadResponse{
+		Reloaded:   reloaded,
+		ReloadedAt: time.Now(),
+	}, nil
+}
+
+// watchSIGHUP reloads the configuration whenever the process is sent
+// SIGHUP
+func (r *reloader) watchSIGHUP() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		for range signals {
+			if _, err := r.reload(); err != nil {
+				r.logger.Printf("Failed to reload configuration: %v", err)
+			}
+		}
+	}()
+}
@@ -1,103 +1,211 @@
 package handler
 
 import (
-	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/pagination"
 	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/benrowe/nab-bank-api/internal/store"
 	"github.com/gorilla/mux"
 )
 
 // AccountsHandler handles account-related HTTP requests
 type AccountsHandler struct {
 	accountService service.AccountService
+	history        service.HistoryService
+	store          *store.Store
 	logger         *log.Logger
 }
 
-// NewAccountsHandler creates a new accounts handler
-func NewAccountsHandler(accountService service.AccountService, logger *log.Logger) *AccountsHandler {
+// NewAccountsHandler creates a new accounts handler. Requests with asOf
+// are answered from history, and the store's sync generation is recorded
+// in pagination cursors.
+func NewAccountsHandler(accountService service.AccountService, history service.HistoryService, store *store.Store, logger *log.Logger) *AccountsHandler {
 	return &AccountsHandler{
 		accountService: accountService,
+		history:        history,
+		store:          store,
 		logger:         logger,
 	}
 }
 
+// parseAsOf reads the asOf query parameter, either an RFC 3339 time or a
+// date meaning the end of that day in the server's time zone. The zero
+// time means now.
+func parseAsOf(r *http.Request) (time.Time, error) {
+	value := r.URL.Query().Get("asOf")
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("asOf must be a date (2006-01-02) or an RFC 3339 time")
+	}
+	return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+}
+
 // ListAccounts handles GET /api/v1/accounts
 func (h *AccountsHandler) ListAccounts(w http.ResponseWriter, r *http.Request) {
 	h.logger.Printf("ListAccounts: %s %s", r.Method, r.URL.Path)
 
-	accounts, err := h.accountService.GetAllAccounts(r.Context())
+	limit, cursor, err := pageParams(r, 0)
+	if err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, err.Error(), nil)
+		return
+	}
+	asOf, err := parseAsOf(r)
+	if err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, err.Error(), nil)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := service.AccountFilter{
+		MinBalance: query.Get("minBalance"),
+		MaxBalance: query.Get("maxBalance"),
+		Sort:       query.Get("sort"),
+	}
+	for _, value := range query["type"] {
+		for _, accountType := range strings.Split(value, ",") {
+			if accountType = strings.TrimSpace(accountType); accountType != "" {
+				filter.Types = append(filter.Types, accountType)
+			}
+		}
+	}
+	if err := filter.Validate(); err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid account filter", err.Error())
+		return
+	}
+
+	var accounts []model.Account
+	if asOf.IsZero() {
+		accounts, err = h.accountService.GetAllAccounts(r.Context())
+	} else {
+		accounts = h.history.AccountsAsOf(asOf)
+	}
 	if err != nil {
 		h.logger.Printf("Failed to get accounts: %v", err)
-		h.writeErrorResponse(w, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to retrieve accounts", err)
+		if writeBlockedResponse(w, h.logger, err) {
+			return
+		}
+		if errors.Is(err, service.ErrServiceUnavailable) {
+			writeErrorResponse(w, h.logger, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "Service temporarily unavailable", errorDetails(err))
+			return
+		}
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to retrieve accounts", errorDetails(err))
+		return
+	}
+
+	// The filter was validated before scraping
+	accounts, _ = service.FilterAccounts(accounts, filter)
+
+	retrievedAt := time.Now()
+	for _, account := range accounts {
+		if (account.Stale || account.Cached) && account.LastUpdated != nil && account.LastUpdated.Before(retrievedAt) {
+			retrievedAt = *account.LastUpdated
+		}
+	}
+
+	page, next, err := pagination.Page(accounts, cursor, limit, h.store.Generation(), func(account model.Account) pagination.Key {
+		return pagination.Key{AccountID: account.ID}
+	})
+	if err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid cursor", err.Error())
 		return
 	}
 
 	response := model.AccountsResponse{
-		Accounts:    accounts,
-		RetrievedAt: time.Now(),
-		Count:       len(accounts),
+		Accounts:    page,
+		RetrievedAt: retrievedAt,
+		Count:       len(page),
+		NextCursor:  next,
+	}
+	if !asOf.IsZero() {
+		response.AsOf = &asOf
 	}
 
-	h.writeJSONResponse(w, http.StatusOK, response)
+	header, rows := accountRows(page)
+	writeListResponse(w, r, h.logger, http.StatusOK, listBody{
+		name:       "accountsResponse",
+		data:       response,
+		header:     header,
+		rows:       rows,
+		nextCursor: next,
+	})
 }
 
 // GetAccount handles GET /api/v1/accounts/{accountId}
 func (h *AccountsHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	accountID := vars["accountId"]
-	
+
 	h.logger.Printf("GetAccount: %s %s (ID: %s)", r.Method, r.URL.Path, accountID)
 
 	if accountID == "" {
-		h.writeErrorResponse(w, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Account ID is required", nil)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Account ID is required", nil)
 		return
 	}
 
-	accountDetails, err := h.accountService.GetAccountDetails(r.Context(), accountID)
+	asOf, err := parseAsOf(r)
 	if err != nil {
-		switch err {
-		case service.ErrAccountNotFound:
-			h.writeErrorResponse(w, http.StatusNotFound, model.ErrorTypeAccountNotFound, "Account not found", nil)
-		case service.ErrServiceUnavailable:
-			h.writeErrorResponse(w, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "Service temporarily unavailable", err)
-		case service.ErrAuthenticationFailed:
-			h.writeErrorResponse(w, http.StatusUnauthorized, model.ErrorTypeAuthenticationFailed, "Authentication failed", nil)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, err.Error(), nil)
+		return
+	}
+	statement := r.URL.Query().Get("statement")
+	if statement != "" && statement != service.StatementCurrent && statement != service.StatementPrevious {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "statement must be current or previous", nil)
+		return
+	}
+
+	var accountDetails *model.AccountDetails
+	if asOf.IsZero() {
+		accountDetails, err = h.accountService.GetAccountDetails(r.Context(), accountID)
+	} else {
+		accountDetails, err = h.history.AccountDetailsAsOf(accountID, asOf)
+	}
+	if err != nil {
+		if writeBlockedResponse(w, h.logger, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrAccountNotFound):
+			writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeAccountNotFound, "Account not found", nil)
+		case errors.Is(err, service.ErrServiceUnavailable):
+			writeErrorResponse(w, h.logger, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "Service temporarily unavailable", errorDetails(err))
+		case errors.Is(err, service.ErrAuthenticationFailed):
+			writeErrorResponse(w, h.logger, http.StatusUnauthorized, model.ErrorTypeAuthenticationFailed, "Authentication failed", nil)
 		default:
 			h.logger.Printf("Failed to get account details: %v", err)
-			h.writeErrorResponse(w, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to retrieve account details", err)
+			writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to retrieve account details", errorDetails(err))
 		}
 		return
 	}
+	if statement != "" {
+		if err := service.FilterStatement(accountDetails, statement); err != nil {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Cannot limit transactions to a statement", err.Error())
+			return
+		}
+	}
 
 	response := model.AccountDetailsResponse{
-		Account: *accountDetails,
+		Account:     *accountDetails,
+		RetrievedAt: time.Now(),
 	}
-
-	h.writeJSONResponse(w, http.StatusOK, response)
-}
-
-// writeJSONResponse writes a JSON response
-func (h *AccountsHandler) writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	
-	if err := json.NewEncoder(w).Encode(data); err != nil {
-		h.logger.Printf("Failed to encode JSON response: %v", err)
+	if (accountDetails.Stale || accountDetails.Cached) && accountDetails.LastUpdated != nil {
+		response.RetrievedAt = *accountDetails.LastUpdated
 	}
-}
-
-// writeErrorResponse writes an error response
-func (h *AccountsHandler) writeErrorResponse(w http.ResponseWriter, statusCode int, errorType, message string, details interface{}) {
-	errorResponse := model.ErrorResponse{
-		Error:     errorType,
-		Message:   message,
-		Details:   details,
-		Timestamp: time.Now(),
+	if !asOf.IsZero() {
+		response.AsOf = &asOf
 	}
 
-	h.writeJSONResponse(w, statusCode, errorResponse)
-}
\ No newline at end of file
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
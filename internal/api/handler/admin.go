@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/benrowe/nab-bank-api/internal/middleware"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/tokens"
+	"github.com/gorilla/mux"
+)
+
+// defaultTopEndpoints is how many endpoints are listed per token unless
+// ?top= says otherwise
+const defaultTopEndpoints = 5
+
+// AdminHandler handles administrative HTTP requests
+type AdminHandler struct {
+	usage  *middleware.UsageTracker
+	tokens *tokens.Manager
+	logger *log.Logger
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(usage *middleware.UsageTracker, tokens *tokens.Manager, logger *log.Logger) *AdminHandler {
+	return &AdminHandler{
+		usage:  usage,
+		tokens: tokens,
+		logger: logger,
+	}
+}
+
+// ListTokens handles GET /admin/tokens
+func (h *AdminHandler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("ListTokens: %s %s", r.Method, r.URL.Path)
+
+	top, ok := h.topParam(w, r)
+	if !ok {
+		return
+	}
+
+	list := h.tokens.List()
+	for i := range list {
+		if usage, found := h.usage.Usage(list[i].ID); found {
+			usage.Endpoints = topEndpoints(usage.Endpoints, top)
+			list[i].Usage = &usage
+		}
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, model.TokensResponse{
+		Tokens: list,
+		Count:  len(list),
+	})
+}
+
+// CreateToken handles POST /admin/tokens
+func (h *AdminHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("CreateToken: %s %s", r.Method, r.URL.Path)
+
+	var req model.TokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	created, err := h.tokens.Create(req)
+	if err != nil {
+		h.writeTokenError(w, err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusCreated, created)
+}
+
+// RotateToken handles POST /admin/tokens/{tokenId}/rotate
+func (h *AdminHandler) RotateToken(w http.ResponseWriter, r *http.Request) {
+	tokenID := mux.Vars(r)["tokenId"]
+	h.logger.Printf("RotateToken: %s %s (token: %s)", r.Method, r.URL.Path, tokenID)
+
+	var req model.RotateTokenRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid request body", err.Error())
+			return
+		}
+	}
+
+	created, err := h.tokens.Rotate(tokenID, req)
+	if err != nil {
+		h.writeTokenError(w, err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusCreated, created)
+}
+
+// RevokeToken handles DELETE /admin/tokens/{tokenId}
+func (h *AdminHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	tokenID := mux.Vars(r)["tokenId"]
+	h.logger.Printf("RevokeToken: %s %s (token: %s)", r.Method, r.URL.Path, tokenID)
+
+	if err := h.tokens.Revoke(tokenID); err != nil {
+		h.writeTokenError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeTokenError maps token manager errors to responses
+func (h *AdminHandler) writeTokenError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, tokens.ErrInvalidToken):
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid token request", err.Error())
+	case errors.Is(err, tokens.ErrTokenNotFound):
+		writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeTokenNotFound, "Token not found", nil)
+	default:
+		h.logger.Printf("Failed to update token: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to update token", err.Error())
+	}
+}
+
+// TokenUsage handles GET /admin/tokens/{tokenId}/usage
+func (h *AdminHandler) TokenUsage(w http.ResponseWriter, r *http.Request) {
+	tokenID := mux.Vars(r)["tokenId"]
+	h.logger.Printf("TokenUsage: %s %s (token: %s)", r.Method, r.URL.Path, tokenID)
+
+	top, ok := h.topParam(w, r)
+	if !ok {
+		return
+	}
+
+	usage, found := h.usage.Usage(tokenID)
+	if !found {
+		writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeTokenNotFound, "Token not found", nil)
+		return
+	}
+	usage.Endpoints = topEndpoints(usage.Endpoints, top)
+
+	writeJSONResponse(w, h.logger, http.StatusOK, usage)
+}
+
+// topParam parses the optional ?top= endpoint limit
+func (h *AdminHandler) topParam(w http.ResponseWriter, r *http.Request) (int, bool) {
+	value := r.URL.Query().Get("top")
+	if value == "" {
+		return defaultTopEndpoints, true
+	}
+
+	top, err := strconv.Atoi(value)
+	if err != nil || top < 0 {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid top parameter", "top must be a non-negative integer")
+		return 0, false
+	}
+	return top, true
+}
+
+// topEndpoints keeps the busiest endpoints; zero keeps them all
+func topEndpoints(endpoints []model.EndpointUsage, top int) []model.EndpointUsage {
+	if top > 0 && len(endpoints) > top {
+		return endpoints[:top]
+	}
+	return endpoints
+}
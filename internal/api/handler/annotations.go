@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// AnnotationsHandler handles transaction tag and note HTTP requests
+type AnnotationsHandler struct {
+	annotations service.AnnotationService
+	logger      *log.Logger
+}
+
+// NewAnnotationsHandler creates a new annotations handler
+func NewAnnotationsHandler(annotations service.AnnotationService, logger *log.Logger) *AnnotationsHandler {
+	return &AnnotationsHandler{
+		annotations: annotations,
+		logger:      logger,
+	}
+}
+
+// ListAnnotations handles GET /api/v1/accounts/{accountId}/transactions/{transactionId}/annotations
+func (h *AnnotationsHandler) ListAnnotations(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	h.logger.Printf("ListAnnotations: %s %s (account: %s, transaction: %s)", r.Method, r.URL.Path, vars["accountId"], vars["transactionId"])
+
+	annotations := h.annotations.Annotations(vars["accountId"], vars["transactionId"])
+	if annotations == nil {
+		annotations = []model.Annotation{}
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, model.AnnotationsResponse{
+		Annotations: annotations,
+		Count:       len(annotations),
+	})
+}
+
+// CreateAnnotation handles POST /api/v1/accounts/{accountId}/transactions/{transactionId}/annotations
+func (h *AnnotationsHandler) CreateAnnotation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	h.logger.Printf("CreateAnnotation: %s %s (account: %s, transaction: %s)", r.Method, r.URL.Path, vars["accountId"], vars["transactionId"])
+
+	var req model.AnnotationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	annotation, err := h.annotations.Annotate(vars["accountId"], vars["transactionId"], req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidAnnotation):
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid annotation", err.Error())
+		case errors.Is(err, service.ErrTransactionNotFound):
+			writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeTransactionNotFound, "Transaction not found or not scraped yet", nil)
+		default:
+			h.logger.Printf("Failed to annotate transaction: %v", err)
+			writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to annotate transaction", err.Error())
+		}
+		return
+	}
+
+	setETag(w, annotation.Version)
+	writeJSONResponse(w, h.logger, http.StatusCreated, annotation)
+}
+
+// DeleteAnnotation handles DELETE /api/v1/annotations/{annotationId},
+// returning the trashed annotation so clients can offer an undo
+func (h *AnnotationsHandler) DeleteAnnotation(w http.ResponseWriter, r *http.Request) {
+	annotationID := mux.Vars(r)["annotationId"]
+	h.logger.Printf("DeleteAnnotation: %s %s (annotation: %s)", r.Method, r.URL.Path, annotationID)
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid If-Match header", err.Error())
+		return
+	}
+
+	annotation, err := h.annotations.Delete(annotationID, version)
+	if err != nil {
+		h.writeAnnotationError(w, "delete", err)
+		return
+	}
+
+	setETag(w, annotation.Version)
+	writeJSONResponse(w, h.logger, http.StatusOK, annotation)
+}
+
+// RestoreAnnotation handles POST /api/v1/annotations/{annotationId}/restore
+func (h *AnnotationsHandler) RestoreAnnotation(w http.ResponseWriter, r *http.Request) {
+	annotationID := mux.Vars(r)["annotationId"]
+	h.logger.Printf("RestoreAnnotation: %s %s (annotation: %s)", r.Method, r.URL.Path, annotationID)
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid If-Match header", err.Error())
+		return
+	}
+
+	annotation, err := h.annotations.Restore(annotationID, version)
+	if err != nil {
+		h.writeAnnotationError(w, "restore", err)
+		return
+	}
+
+	setETag(w, annotation.Version)
+	writeJSONResponse(w, h.logger, http.StatusOK, annotation)
+}
+
+// ListTrash handles GET /api/v1/annotations/trash
+func (h *AnnotationsHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("ListTrash: %s %s", r.Method, r.URL.Path)
+
+	trash, err := h.annotations.Trash()
+	if err != nil {
+		h.logger.Printf("Failed to list annotation trash: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to list trash", err.Error())
+		return
+	}
+	if trash == nil {
+		trash = []model.Annotation{}
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, model.AnnotationTrashResponse{
+		Annotations: trash,
+		Count:       len(trash),
+		Retention:   h.annotations.Retention().String(),
+	})
+}
+
+// writeAnnotationError maps annotation service errors to responses
+func (h *AnnotationsHandler) writeAnnotationError(w http.ResponseWriter, action string, err error) {
+	if errors.Is(err, service.ErrAnnotationNotFound) {
+		writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeAnnotationNotFound, "Annotation not found", err.Error())
+		return
+	}
+	if writeVersionMismatch(w, h.logger, err) {
+		return
+	}
+	h.logger.Printf("Failed to %s annotation: %v", action, err)
+	writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to "+action+" annotation", err.Error())
+}
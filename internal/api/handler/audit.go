@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/audit"
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// defaultAuditLimit is how many audit entries are returned by default
+const defaultAuditLimit = 100
+
+// AuditHandler handles audit log HTTP requests
+type AuditHandler struct {
+	audit  *audit.Log
+	logger *log.Logger
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditLog *audit.Log, logger *log.Logger) *AuditHandler {
+	return &AuditHandler{
+		audit:  auditLog,
+		logger: logger,
+	}
+}
+
+// ListEntries handles GET /api/v1/admin/audit
+func (h *AuditHandler) ListEntries(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("ListAuditEntries: %s %s", r.Method, r.URL.Path)
+
+	query := r.URL.Query()
+	filter := audit.Filter{
+		Actor:  query.Get("actor"),
+		Kind:   query.Get("kind"),
+		Result: query.Get("result"),
+		Limit:  defaultAuditLimit,
+	}
+	switch filter.Kind {
+	case "", model.AuditKindAPICall, model.AuditKindTransfer, model.AuditKindPayment:
+	default:
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "kind must be api_call, transfer or payment", nil)
+		return
+	}
+	switch filter.Result {
+	case "", model.AuditResultSuccess, model.AuditResultFailure:
+	default:
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "result must be success or failure", nil)
+		return
+	}
+	for name, at := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if value := query.Get(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, name+" must be an RFC 3339 time", nil)
+				return
+			}
+			*at = parsed
+		}
+	}
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "limit must be a positive integer, or 0 for every entry", nil)
+			return
+		}
+		filter.Limit = parsed
+	}
+
+	entries, err := h.audit.Query(filter)
+	if err != nil {
+		h.logger.Printf("Failed to query audit log: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to read the audit log", err.Error())
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, model.AuditLogResponse{
+		Entries: entries,
+		Count:   len(entries),
+	})
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/benrowe/nab-bank-api/internal/basiq"
+	"github.com/benrowe/nab-bank-api/internal/middleware"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/pagination"
+	"github.com/benrowe/nab-bank-api/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// Basiq transaction page sizes, by default and at most
+const (
+	defaultBasiqLimit = 500
+	maxBasiqLimit     = 500
+)
+
+// BasiqHandler serves stored accounts and transactions in the shape of
+// Basiq's API. Every user ID sees the same accounts.
+type BasiqHandler struct {
+	store       *store.Store
+	tokens      middleware.TokenAuthenticator
+	institution string
+	logger      *log.Logger
+}
+
+// NewBasiqHandler creates a new Basiq compatibility handler. Accounts and
+// transactions are reported under the given institution ID.
+func NewBasiqHandler(store *store.Store, tokens middleware.TokenAuthenticator, institution string, logger *log.Logger) *BasiqHandler {
+	return &BasiqHandler{
+		store:       store,
+		tokens:      tokens,
+		institution: institution,
+		logger:      logger,
+	}
+}
+
+// Token handles POST /basiq/token. Basiq clients swap their API key, sent
+// as basic auth, for an access token; here the API key is the access
+// token, so a valid key is handed straight back.
+func (h *BasiqHandler) Token(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("BasiqToken: %s %s", r.Method, r.URL.Path)
+
+	presented, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Basic ")
+	presented = strings.TrimSpace(presented)
+	candidates := []string{presented}
+	if decoded, err := base64.StdEncoding.DecodeString(presented); err == nil {
+		candidates = append(candidates, strings.TrimSuffix(string(decoded), ":"))
+	}
+	for _, key := range candidates {
+		if _, ok := h.tokens.Authenticate(key); key != "" && ok {
+			writeJSONResponse(w, h.logger, http.StatusOK, model.BasiqToken{AccessToken: key, TokenType: "Bearer", ExpiresIn: 3600})
+			return
+		}
+	}
+	h.writeError(w, http.StatusUnauthorized, "unauthorized-access", "Unauthorized Access", "A valid API key is required.")
+}
+
+// ListAccounts handles GET /basiq/users/{userId}/accounts
+func (h *BasiqHandler) ListAccounts(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("BasiqListAccounts: %s %s", r.Method, r.URL.Path)
+
+	converter := h.converter(r)
+	accounts := []model.BasiqAccount{}
+	for _, account := range h.store.Accounts() {
+		accounts = append(accounts, converter.Account(account))
+	}
+	writeJSONResponse(w, h.logger, http.StatusOK, model.BasiqAccountList{
+		Type:  "list",
+		Count: len(accounts),
+		Data:  accounts,
+		Links: model.BasiqLinks{Self: converter.Base + "/accounts"},
+	})
+}
+
+// GetAccount handles GET /basiq/users/{userId}/accounts/{accountId}
+func (h *BasiqHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
+	accountID := mux.Vars(r)["accountId"]
+	h.logger.Printf("BasiqGetAccount: %s %s (account: %s)", r.Method, r.URL.Path, accountID)
+
+	for _, account := range h.store.Accounts() {
+		if account.ID == accountID {
+			writeJSONResponse(w, h.logger, http.StatusOK, h.converter(r).Account(account))
+			return
+		}
+	}
+	h.writeError(w, http.StatusNotFound, "resource-not-found", "Resource not found.", "Account "+accountID+" not found.")
+}
+
+// ListTransactions handles GET /basiq/users/{userId}/transactions, newest
+// first, following the next link for later pages
+func (h *BasiqHandler) ListTransactions(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("BasiqListTransactions: %s %s", r.Method, r.URL.Path)
+
+	query := r.URL.Query()
+	filter, err := basiq.ParseFilter(query.Get("filter"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "parameter-not-valid", "Parameter value is not valid.", err.Error())
+		return
+	}
+	limit := defaultBasiqLimit
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > maxBasiqLimit {
+			h.writeError(w, http.StatusBadRequest, "parameter-not-valid", "Parameter value is not valid.", "limit must be between 1 and 500.")
+			return
+		}
+		limit = parsed
+	}
+
+	generation := h.store.Generation()
+	matches := []model.TransactionMatch{}
+	for accountID, transactions := range h.store.AllTransactions() {
+		for _, transaction := range transactions {
+			if filter.Matches(accountID, transaction) {
+				matches = append(matches, model.TransactionMatch{AccountID: accountID, Transaction: transaction})
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Date != matches[j].Date {
+			return matches[i].Date > matches[j].Date
+		}
+		if matches[i].AccountID != matches[j].AccountID {
+			return matches[i].AccountID < matches[j].AccountID
+		}
+		return matches[i].ID < matches[j].ID
+	})
+
+	page, next, err := pagination.Page(matches, query.Get("next"), limit, generation, func(match model.TransactionMatch) pagination.Key {
+		return pagination.Key{AccountID: match.AccountID, TransactionID: match.ID}
+	})
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		h.writeError(w, http.StatusBadRequest, "parameter-not-valid", "Parameter value is not valid.", err.Error())
+		return
+	}
+
+	converter := h.converter(r)
+	response := model.BasiqTransactionList{
+		Type:  "list",
+		Count: len(page),
+		Size:  len(matches),
+		Data:  make([]model.BasiqTransaction, 0, len(page)),
+		Links: model.BasiqLinks{Self: converter.Base + "/transactions?" + query.Encode()},
+	}
+	for _, match := range page {
+		response.Data = append(response.Data, converter.Transaction(match.AccountID, match.Transaction))
+	}
+	if next != "" {
+		nextQuery := url.Values{}
+		for key, values := range query {
+			nextQuery[key] = values
+		}
+		nextQuery.Set("next", next)
+		response.Links.Next = converter.Base + "/transactions?" + nextQuery.Encode()
+	}
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// GetTransaction handles GET /basiq/users/{userId}/transactions/{transactionId}
+func (h *BasiqHandler) GetTransaction(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["transactionId"]
+	h.logger.Printf("BasiqGetTransaction: %s %s (transaction: %s)", r.Method, r.URL.Path, id)
+
+	if accountID, transactionID, ok := basiq.SplitTransactionID(id); ok {
+		for _, transaction := range h.store.Transactions(accountID) {
+			if transaction.ID == transactionID {
+				writeJSONResponse(w, h.logger, http.StatusOK, h.converter(r).Transaction(accountID, transaction))
+				return
+			}
+		}
+	}
+	h.writeError(w, http.StatusNotFound, "resource-not-found", "Resource not found.", "Transaction "+id+" not found.")
+}
+
+// converter builds links under the requested user
+func (h *BasiqHandler) converter(r *http.Request) basiq.Converter {
+	return basiq.Converter{
+		Base:        "/basiq/users/" + url.PathEscape(mux.Vars(r)["userId"]),
+		Institution: h.institution,
+	}
+}
+
+// writeError writes an error in Basiq's error envelope
+func (h *BasiqHandler) writeError(w http.ResponseWriter, status int, code, title, detail string) {
+	writeJSONResponse(w, h.logger, status, model.BasiqErrorResponse{
+		Type: "list",
+		Data: []model.BasiqError{{Type: "error", Code: code, Title: title, Detail: detail}},
+	})
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// BudgetsHandler handles budget HTTP requests
+type BudgetsHandler struct {
+	budgets service.BudgetService
+	logger  *log.Logger
+}
+
+// NewBudgetsHandler creates a new budgets handler
+func NewBudgetsHandler(budgets service.BudgetService, logger *log.Logger) *BudgetsHandler {
+	return &BudgetsHandler{
+		budgets: budgets,
+		logger:  logger,
+	}
+}
+
+// ListBudgets handles GET /api/v1/budgets
+func (h *BudgetsHandler) ListBudgets(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("ListBudgets: %s %s", r.Method, r.URL.Path)
+
+	now := time.Now()
+	budgets := h.budgets.Budgets(now)
+	writeJSONResponse(w, h.logger, http.StatusOK, model.BudgetsResponse{
+		Budgets:     budgets,
+		Count:       len(budgets),
+		RetrievedAt: now,
+	})
+}
+
+// CreateBudget handles POST /api/v1/budgets
+func (h *BudgetsHandler) CreateBudget(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("CreateBudget: %s %s", r.Method, r.URL.Path)
+
+	var req model.BudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	budget, err := h.budgets.Create(req)
+	if err != nil {
+		h.writeBudgetError(w, "create", err)
+		return
+	}
+
+	setETag(w, budget.Version)
+	writeJSONResponse(w, h.logger, http.StatusCreated, budget)
+}
+
+// UpdateBudget handles PUT /api/v1/budgets/{budgetId}
+func (h *BudgetsHandler) UpdateBudget(w http.ResponseWriter, r *http.Request) {
+	budgetID := mux.Vars(r)["budgetId"]
+	h.logger.Printf("UpdateBudget: %s %s (budget: %s)", r.Method, r.URL.Path, budgetID)
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid If-Match header", err.Error())
+		return
+	}
+	var req model.BudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	budget, err := h.budgets.Update(budgetID, req, version)
+	if err != nil {
+		h.writeBudgetError(w, "update", err)
+		return
+	}
+
+	setETag(w, budget.Version)
+	writeJSONResponse(w, h.logger, http.StatusOK, budget)
+}
+
+// DeleteBudget handles DELETE /api/v1/budgets/{budgetId}
+func (h *BudgetsHandler) DeleteBudget(w http.ResponseWriter, r *http.Request) {
+	budgetID := mux.Vars(r)["budgetId"]
+	h.logger.Printf("DeleteBudget: %s %s (budget: %s)", r.Method, r.URL.Path, budgetID)
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid If-Match header", err.Error())
+		return
+	}
+
+	budget, err := h.budgets.Delete(budgetID, version)
+	if err != nil {
+		h.writeBudgetError(w, "delete", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, budget)
+}
+
+// writeBudgetError maps budget service errors to responses
+func (h *BudgetsHandler) writeBudgetError(w http.ResponseWriter, action string, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidBudget):
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid budget", err.Error())
+		return
+	case errors.Is(err, service.ErrBudgetNotFound):
+		writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeBudgetNotFound, "Budget not found", nil)
+		return
+	}
+	if writeVersionMismatch(w, h.logger, err) {
+		return
+	}
+	h.logger.Printf("Failed to %s budget: %v", action, err)
+	writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to "+action+" budget", err.Error())
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// BulkHandler handles bulk operation and job status HTTP requests
+type BulkHandler struct {
+	bulk   service.BulkService
+	logger *log.Logger
+}
+
+// NewBulkHandler creates a new bulk handler
+func NewBulkHandler(bulk service.BulkService, logger *log.Logger) *BulkHandler {
+	return &BulkHandler{
+		bulk:   bulk,
+		logger: logger,
+	}
+}
+
+// SubmitBulk handles POST /api/v1/bulk, accepting the operations as a
+// background job and pointing at its status
+func (h *BulkHandler) SubmitBulk(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("SubmitBulk: %s %s", r.Method, r.URL.Path)
+
+	var req model.BulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	job, err := h.bulk.Submit(req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidBulkRequest) {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid bulk request", err.Error())
+			return
+		}
+		h.logger.Printf("Failed to start bulk job: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to start bulk job", err.Error())
+		return
+	}
+
+	h.logger.Printf("Started bulk job %s with %d operations", job.ID, job.Total)
+	w.Header().Set("Location", "/api/v1/jobs/"+job.ID)
+	writeJSONResponse(w, h.logger, http.StatusAccepted, job)
+}
+
+// GetJob handles GET /api/v1/jobs/{jobId}
+func (h *BulkHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	jobID := mux.Vars(r)["jobId"]
+	h.logger.Printf("GetJob: %s %s (job: %s)", r.Method, r.URL.Path, jobID)
+
+	job, err := h.bulk.Job(jobID)
+	if err != nil {
+		writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeJobNotFound, "Job not found", nil)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, job)
+}
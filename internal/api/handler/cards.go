@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// CardsHandler handles card HTTP requests
+type CardsHandler struct {
+	cardService service.CardService
+	logger      *log.Logger
+}
+
+// NewCardsHandler creates a new cards handler
+func NewCardsHandler(cardService service.CardService, logger *log.Logger) *CardsHandler {
+	return &CardsHandler{
+		cardService: cardService,
+		logger:      logger,
+	}
+}
+
+// ListCards handles GET /api/v1/cards
+func (h *CardsHandler) ListCards(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("ListCards: %s %s", r.Method, r.URL.Path)
+
+	cards, err := h.cardService.GetCards(r.Context())
+	if err != nil {
+		h.writeCardError(w, err)
+		return
+	}
+
+	response := model.CardsResponse{
+		Cards:       cards,
+		RetrievedAt: time.Now(),
+		Count:       len(cards),
+	}
+	if response.Cards == nil {
+		response.Cards = []model.Card{}
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// LockCard handles POST /api/v1/cards/{cardId}/lock
+func (h *CardsHandler) LockCard(w http.ResponseWriter, r *http.Request) {
+	h.setLocked(w, r, "LockCard", h.cardService.LockCard)
+}
+
+// UnlockCard handles POST /api/v1/cards/{cardId}/unlock
+func (h *CardsHandler) UnlockCard(w http.ResponseWriter, r *http.Request) {
+	h.setLocked(w, r, "UnlockCard", h.cardService.UnlockCard)
+}
+
+// setLocked runs a lock or unlock and writes the updated card
+func (h *CardsHandler) setLocked(w http.ResponseWriter, r *http.Request, name string, update func(context.Context, string) (*model.Card, error)) {
+	cardID := mux.Vars(r)["cardId"]
+	h.logger.Printf("%s: %s %s (card: %s)", name, r.Method, r.URL.Path, cardID)
+
+	card, err := update(r.Context(), cardID)
+	if err != nil {
+		h.writeCardError(w, err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, card)
+}
+
+// writeCardError maps card service errors to responses
+func (h *CardsHandler) writeCardError(w http.ResponseWriter, err error) {
+	if writeBlockedResponse(w, h.logger, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrCardNotFound):
+		writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeCardNotFound, "Card not found", nil)
+	case errors.Is(err, service.ErrCardRejected):
+		writeErrorResponse(w, h.logger, http.StatusUnprocessableEntity, model.ErrorTypeCardRejected, "Card change rejected by NAB", err.Error())
+	case errors.Is(err, service.ErrCardsUnsupported):
+		writeErrorResponse(w, h.logger, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "Card management is not available", nil)
+	case errors.Is(err, service.ErrAuthenticationFailed):
+		writeErrorResponse(w, h.logger, http.StatusUnauthorized, model.ErrorTypeAuthenticationFailed, "Authentication failed", nil)
+	default:
+		h.logger.Printf("Card request failed: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Card request failed", errorDetails(err))
+	}
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// CategoriesHandler handles category and category rule HTTP requests
+type CategoriesHandler struct {
+	categories service.CategoryService
+	logger     *log.Logger
+}
+
+// NewCategoriesHandler creates a new categories handler
+func NewCategoriesHandler(categories service.CategoryService, logger *log.Logger) *CategoriesHandler {
+	return &CategoriesHandler{
+		categories: categories,
+		logger:     logger,
+	}
+}
+
+// ListCategories handles GET /api/v1/categories
+func (h *CategoriesHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("ListCategories: %s %s", r.Method, r.URL.Path)
+
+	categories := h.categories.Categories()
+	writeJSONResponse(w, h.logger, http.StatusOK, model.CategoriesResponse{
+		Categories: categories,
+		Count:      len(categories),
+	})
+}
+
+// ListRules handles GET /api/v1/categories/rules
+func (h *CategoriesHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("ListRules: %s %s", r.Method, r.URL.Path)
+
+	rules := h.categories.Rules()
+	writeJSONResponse(w, h.logger, http.StatusOK, model.CategoryRulesResponse{
+		Rules: rules,
+		Count: len(rules),
+	})
+}
+
+// CreateRule handles POST /api/v1/categories/rules
+func (h *CategoriesHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("CreateRule: %s %s", r.Method, r.URL.Path)
+
+	var req model.CategoryRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	response, err := h.categories.CreateRule(req)
+	if err != nil {
+		h.writeRuleError(w, "create", err)
+		return
+	}
+
+	setETag(w, response.Rule.Version)
+	writeJSONResponse(w, h.logger, http.StatusCreated, response)
+}
+
+// UpdateRule handles PUT /api/v1/categories/rules/{ruleId}
+func (h *CategoriesHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	ruleID := mux.Vars(r)["ruleId"]
+	h.logger.Printf("UpdateRule: %s %s (rule: %s)", r.Method, r.URL.Path, ruleID)
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid If-Match header", err.Error())
+		return
+	}
+	var req model.CategoryRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	response, err := h.categories.UpdateRule(ruleID, req, version)
+	if err != nil {
+		h.writeRuleError(w, "update", err)
+		return
+	}
+
+	setETag(w, response.Rule.Version)
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// DeleteRule handles DELETE /api/v1/categories/rules/{ruleId}
+func (h *CategoriesHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	ruleID := mux.Vars(r)["ruleId"]
+	h.logger.Printf("DeleteRule: %s %s (rule: %s)", r.Method, r.URL.Path, ruleID)
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid If-Match header", err.Error())
+		return
+	}
+
+	response, err := h.categories.DeleteRule(ruleID, version)
+	if err != nil {
+		h.writeRuleError(w, "delete", err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// writeRuleError maps category service errors to responses
+func (h *CategoriesHandler) writeRuleError(w http.ResponseWriter, action string, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidCategoryRule):
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid category rule", err.Error())
+		return
+	case errors.Is(err, service.ErrCategoryRuleNotFound):
+		writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeCategoryRuleNotFound, "Category rule not found", nil)
+		return
+	case errors.Is(err, service.ErrCategoryRuleExists):
+		writeErrorResponse(w, h.logger, http.StatusConflict, model.ErrorTypeCategoryRuleExists, "Another rule already has this pattern", err.Error())
+		return
+	}
+	if writeVersionMismatch(w, h.logger, err) {
+		return
+	}
+	h.logger.Printf("Failed to %s category rule: %v", action, err)
+	writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to "+action+" category rule", err.Error())
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// ConfigHandler handles reloading the configuration
+type ConfigHandler struct {
+	reload func() (*model.ConfigReloadResponse, error)
+	logger *log.Logger
+}
+
+// NewConfigHandler creates a new config handler. reload loads the
+// configuration again and applies what can change while running.
+func NewConfigHandler(reload func() (*model.ConfigReloadResponse, error), logger *log.Logger) *ConfigHandler {
+	return &ConfigHandler{
+		reload: reload,
+		logger: logger,
+	}
+}
+
+// Reload handles POST /api/v1/admin/config/reload. An invalid
+// configuration is rejected and the running one kept.
+func (h *ConfigHandler) Reload(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("ReloadConfig: %s %s", r.Method, r.URL.Path)
+
+	response, err := h.reload()
+	if err != nil {
+		h.logger.Printf("Failed to reload configuration: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Failed to reload configuration", err.Error())
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
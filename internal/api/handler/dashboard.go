@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// DashboardHandler handles the home screen dashboard
+type DashboardHandler struct {
+	dashboard service.DashboardService
+	logger    *log.Logger
+}
+
+// NewDashboardHandler creates a new dashboard handler
+func NewDashboardHandler(dashboard service.DashboardService, logger *log.Logger) *DashboardHandler {
+	return &DashboardHandler{
+		dashboard: dashboard,
+		logger:    logger,
+	}
+}
+
+// Dashboard handles GET /api/v1/dashboard
+func (h *DashboardHandler) Dashboard(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("Dashboard: %s %s", r.Method, r.URL.Path)
+
+	dashboard, err := h.dashboard.Dashboard(r.Context())
+	if err != nil {
+		h.logger.Printf("Failed to build dashboard: %v", err)
+		if writeBlockedResponse(w, h.logger, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrServiceUnavailable):
+			writeErrorResponse(w, h.logger, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "Service temporarily unavailable", errorDetails(err))
+		case errors.Is(err, service.ErrAuthenticationFailed):
+			writeErrorResponse(w, h.logger, http.StatusUnauthorized, model.ErrorTypeAuthenticationFailed, "Authentication failed", nil)
+		default:
+			writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to build dashboard", errorDetails(err))
+		}
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, dashboard)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// DirectDebitsHandler handles direct debit HTTP requests
+type DirectDebitsHandler struct {
+	directDebitService service.DirectDebitService
+	logger             *log.Logger
+}
+
+// NewDirectDebitsHandler creates a new direct debits handler
+func NewDirectDebitsHandler(directDebitService service.DirectDebitService, logger *log.Logger) *DirectDebitsHandler {
+	return &DirectDebitsHandler{
+		directDebitService: directDebitService,
+		logger:             logger,
+	}
+}
+
+// ListDirectDebits handles GET /api/v1/accounts/{accountId}/direct-debits
+func (h *DirectDebitsHandler) ListDirectDebits(w http.ResponseWriter, r *http.Request) {
+	accountID := mux.Vars(r)["accountId"]
+	h.logger.Printf("ListDirectDebits: %s %s (account: %s)", r.Method, r.URL.Path, accountID)
+
+	debits, err := h.directDebitService.GetDirectDebits(r.Context(), accountID)
+	if err != nil {
+		h.logger.Printf("Failed to get direct debits: %v", err)
+		if writeBlockedResponse(w, h.logger, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrAccountNotFound):
+			writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeAccountNotFound, "Account not found", nil)
+		case errors.Is(err, service.ErrDirectDebitsUnsupported):
+			writeErrorResponse(w, h.logger, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "Direct debits are not available", nil)
+		default:
+			writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to retrieve direct debits", errorDetails(err))
+		}
+		return
+	}
+
+	response := model.DirectDebitsResponse{
+		AccountID:    accountID,
+		DirectDebits: debits,
+		RetrievedAt:  time.Now(),
+		Count:        len(debits),
+	}
+	if response.DirectDebits == nil {
+		response.DirectDebits = []model.DirectDebit{}
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
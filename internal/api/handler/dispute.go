@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// DisputeHandler handles transaction dispute HTTP requests
+type DisputeHandler struct {
+	disputeService service.DisputeService
+	logger         *log.Logger
+}
+
+// NewDisputeHandler creates a new dispute handler
+func NewDisputeHandler(disputeService service.DisputeService, logger *log.Logger) *DisputeHandler {
+	return &DisputeHandler{
+		disputeService: disputeService,
+		logger:         logger,
+	}
+}
+
+// PrepareDispute handles POST /api/v1/accounts/{accountId}/transactions/{transactionId}/dispute
+func (h *DisputeHandler) PrepareDispute(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	accountID := vars["accountId"]
+	transactionID := vars["transactionId"]
+
+	h.logger.Printf("PrepareDispute: %s %s (account: %s, transaction: %s)", r.Method, r.URL.Path, accountID, transactionID)
+
+	// The body is optional; an empty one prepares a summary with no reason
+	var req model.DisputeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	summary, err := h.disputeService.PrepareDispute(r.Context(), accountID, transactionID, req)
+	if err != nil {
+		if writeBlockedResponse(w, h.logger, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrAccountNotFound):
+			writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeAccountNotFound, "Account not found", nil)
+		case errors.Is(err, service.ErrTransactionNotFound):
+			writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeTransactionNotFound, "Transaction not found", nil)
+		case errors.Is(err, service.ErrDisputeFormUnsupported):
+			writeErrorResponse(w, h.logger, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "Dispute form navigation is not available", nil)
+		case errors.Is(err, service.ErrAuthenticationFailed):
+			writeErrorResponse(w, h.logger, http.StatusUnauthorized, model.ErrorTypeAuthenticationFailed, "Authentication failed", nil)
+		default:
+			h.logger.Printf("Failed to prepare dispute: %v", err)
+			writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to prepare dispute", errorDetails(err))
+		}
+		return
+	}
+
+	if r.URL.Query().Get("format") == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, summary.Document)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, summary)
+}
@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// List endpoints can answer in JSON, CSV or XML, chosen by the request's
+// Accept header. JSON and XML carry the whole response; CSV carries only
+// the listed items, one per row.
+
+// nextCursorHeader carries the next page cursor on list responses, for
+// formats such as CSV that have nowhere else to put it
+const nextCursorHeader = "X-Next-Cursor"
+
+// xmlItemElement names the elements of a list in XML responses
+const xmlItemElement = "item"
+
+// listBody is a list endpoint's response along with its items as a table
+type listBody struct {
+	// name is the root element of XML responses
+	name string
+
+	// data is the full response, sent as JSON and XML
+	data interface{}
+
+	// header and rows are the listed items, sent as CSV
+	header []string
+	rows   [][]string
+
+	// nextCursor is sent in nextCursorHeader when there are more pages
+	nextCursor string
+}
+
+// responseEncoder writes a list response in one media type
+type responseEncoder interface {
+	// mediaTypes lists the media types the encoder answers to, the first
+	// being the one it writes
+	mediaTypes() []string
+
+	// contentType is the Content-Type header of the responses written
+	contentType() string
+
+	encode(w io.Writer, body listBody) error
+}
+
+// responseEncoders are the supported list response formats in order of
+// preference, used to break ties between equally acceptable formats
+var responseEncoders = []responseEncoder{jsonEncoder{}, csvEncoder{}, xmlEncoder{}}
+
+// writeListResponse writes a list response in the format the request
+// accepts, or 406 if it accepts none of them
+func writeListResponse(w http.ResponseWriter, r *http.Request, logger *log.Logger, statusCode int, body listBody) {
+	w.Header().Add("Vary", "Accept")
+
+	encoder := negotiateEncoder(r.Header.Get("Accept"))
+	if encoder == nil {
+		supported := supportedListMediaTypes()
+		writeErrorResponse(w, logger, http.StatusNotAcceptable, model.ErrorTypeNotAcceptable, "Accept must allow one of "+strings.Join(supported, ", "), supported)
+		return
+	}
+
+	if body.nextCursor != "" {
+		w.Header().Set(nextCursorHeader, body.nextCursor)
+	}
+	w.Header().Set("Content-Type", encoder.contentType())
+	w.WriteHeader(statusCode)
+
+	if err := encoder.encode(w, body); err != nil {
+		logger.Printf("Failed to encode %s response: %v", encoder.mediaTypes()[0], err)
+	}
+}
+
+// supportedListMediaTypes lists the media types list responses can be
+// written in, JSON first
+func supportedListMediaTypes() []string {
+	mediaTypes := make([]string, 0, len(responseEncoders))
+	for _, encoder := range responseEncoders {
+		mediaTypes = append(mediaTypes, encoder.mediaTypes()[0])
+	}
+	return mediaTypes
+}
+
+// negotiateEncoder picks the encoder for an Accept header: the most
+// acceptable by quality, then by preference. A missing header accepts
+// JSON. It returns nil if nothing supported is acceptable.
+func negotiateEncoder(accept string) responseEncoder {
+	if strings.TrimSpace(accept) == "" {
+		return responseEncoders[0]
+	}
+
+	var best responseEncoder
+	bestQuality := 0.0
+	for _, encoder := range responseEncoders {
+		if quality := acceptQuality(accept, encoder.mediaTypes()); quality > bestQuality {
+			best, bestQuality = encoder, quality
+		}
+	}
+	return best
+}
+
+// acceptQuality returns how acceptable an Accept header finds any of the
+// media types, taking the most specific matching range for each
+func acceptQuality(accept string, mediaTypes []string) float64 {
+	best := 0.0
+	for _, mediaType := range mediaTypes {
+		quality, specificity := 0.0, -1
+		for _, part := range strings.Split(accept, ",") {
+			params := strings.Split(part, ";")
+			mediaRange := strings.ToLower(strings.TrimSpace(params[0]))
+			rangeSpecificity := mediaRangeSpecificity(mediaRange, mediaType)
+			if rangeSpecificity <= specificity {
+				continue
+			}
+			specificity, quality = rangeSpecificity, 1.0
+			for _, param := range params[1:] {
+				key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if strings.TrimSpace(key) == "q" {
+					if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+						quality = parsed
+					}
+				}
+			}
+		}
+		if quality > best {
+			best = quality
+		}
+	}
+	return best
+}
+
+// mediaRangeSpecificity returns how specifically a media range matches a
+// media type: 2 for an exact match, 1 for type/*, 0 for */* and -1 for no
+// match
+func mediaRangeSpecificity(mediaRange, mediaType string) int {
+	switch {
+	case mediaRange == mediaType:
+		return 2
+	case mediaRange == "*/*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*")):
+		return 1
+	}
+	return -1
+}
+
+// jsonEncoder writes the response as JSON
+type jsonEncoder struct{}
+
+func (jsonEncoder) mediaTypes() []string { return []string{"application/json"} }
+
+func (jsonEncoder) contentType() string { return "application/json" }
+
+func (jsonEncoder) encode(w io.Writer, body listBody) error {
+	return json.NewEncoder(w).Encode(body.data)
+}
+
+// csvEncoder writes the listed items as CSV with a header row
+type csvEncoder struct{}
+
+func (csvEncoder) mediaTypes() []string { return []string{"text/csv"} }
+
+func (csvEncoder) contentType() string { return "text/csv; charset=utf-8" }
+
+func (csvEncoder) encode(w io.Writer, body listBody) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(body.header); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(body.rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// xmlEncoder writes the response as XML. Elements are named after the
+// JSON fields, so both formats have the same shape, and list entries are
+// written as item elements.
+type xmlEncoder struct{}
+
+func (xmlEncoder) mediaTypes() []string { return []string{"application/xml", "text/xml"} }
+
+func (xmlEncoder) contentType() string { return "application/xml; charset=utf-8" }
+
+func (xmlEncoder) encode(w io.Writer, body listBody) error {
+	data, err := json.Marshal(body.data)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	if err := writeXMLValue(encoder, decoder, xml.StartElement{Name: xml.Name{Local: body.name}}); err != nil {
+		return err
+	}
+	return encoder.Flush()
+}
+
+// writeXMLValue converts the next JSON value from the decoder into an
+// element. Nulls are left out.
+func writeXMLValue(encoder *xml.Encoder, decoder *json.Decoder, start xml.StartElement) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		if token == nil {
+			return nil
+		}
+		return encoder.EncodeElement(fmt.Sprint(token), start)
+	}
+
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	for decoder.More() {
+		child := xml.StartElement{Name: xml.Name{Local: xmlItemElement}}
+		if delim == '{' {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			child = xmlFieldElement(keyToken.(string))
+		}
+		if err := writeXMLValue(encoder, decoder, child); err != nil {
+			return err
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	return encoder.EncodeToken(start.End())
+}
+
+// xmlFieldElement names the element for a JSON field. Keys that aren't
+// valid element names, such as account IDs keying a map, are written as
+// entry elements with a key attribute.
+func xmlFieldElement(key string) xml.StartElement {
+	valid := key != ""
+	for i, r := range key {
+		if !(unicode.IsLetter(r) || r == '_' || (i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'))) {
+			valid = false
+			break
+		}
+	}
+	if valid && !strings.HasPrefix(strings.ToLower(key), "xml") {
+		return xml.StartElement{Name: xml.Name{Local: key}}
+	}
+	return xml.StartElement{
+		Name: xml.Name{Local: "entry"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+	}
+}
+
+// accountRows tabulates accounts for CSV responses
+func accountRows(accounts []model.Account) ([]string, [][]string) {
+	header := []string{"id", "name", "type", "balance", "availableBalance", "accountNumber", "bsb", "lastUpdated"}
+	rows := make([][]string, 0, len(accounts))
+	for _, account := range accounts {
+		row := []string{account.ID, account.Name, account.Type, account.Balance.Amount, "", stringValue(account.AccountNumber), stringValue(account.BSB), ""}
+		if account.AvailableBalance != nil {
+			row[4] = account.AvailableBalance.Amount
+		}
+		if account.LastUpdated != nil {
+			row[7] = account.LastUpdated.Format(time.RFC3339)
+		}
+		rows = append(rows, row)
+	}
+	return header, rows
+}
+
+// transactionRows tabulates transactions for CSV responses
+func transactionRows(transactions []model.TransactionMatch) ([]string, [][]string) {
+	header := []string{"accountId", "id", "date", "description", "amount", "balance", "category", "merchant"}
+	rows := make([][]string, 0, len(transactions))
+	for _, match := range transactions {
+		rows = append(rows, []string{
+			match.AccountID,
+			match.ID,
+			match.Date,
+			match.Description,
+			match.Amount.Amount,
+			match.Balance.Amount,
+			stringValue(match.Category),
+			stringValue(match.Merchant),
+		})
+	}
+	return header, rows
+}
+
+// stringValue returns the string pointed to, or "" for nil
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// Locally mutable resources carry a version number that is sent as their
+// ETag. Clients send it back in If-Match to make a change conditional on
+// nobody else having changed the resource first.
+
+// setETag sets the ETag header to a resource's version
+func setETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatchVersion reads the version required by a request's If-Match
+// header. A missing header or "*" gives zero, meaning the change is
+// unconditional.
+func ifMatchVersion(r *http.Request) (int, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	tag := strings.TrimPrefix(value, "W/")
+	if unquoted, err := strconv.Unquote(tag); err == nil {
+		tag = unquoted
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		return 0, errors.New("If-Match must be a single ETag returned by the API")
+	}
+	return version, nil
+}
+
+// writeVersionMismatch responds 412 when a conditional change lost a race,
+// reporting the error if it is a version mismatch
+func writeVersionMismatch(w http.ResponseWriter, logger *log.Logger, err error) bool {
+	if !errors.Is(err, service.ErrVersionMismatch) {
+		return false
+	}
+	writeErrorResponse(w, logger, http.StatusPreconditionFailed, model.ErrorTypePreconditionFailed, "Resource has changed, fetch it again before retrying", err.Error())
+	return true
+}
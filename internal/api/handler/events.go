@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/events"
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// eventsHeartbeat is how often an idle event stream sends a comment, so
+// proxies don't close it
+const eventsHeartbeat = 15 * time.Second
+
+// EventsHandler streams sync progress over Server-Sent Events
+type EventsHandler struct {
+	broker *events.Broker
+	logger *log.Logger
+}
+
+// NewEventsHandler creates a new events handler
+func NewEventsHandler(broker *events.Broker, logger *log.Logger) *EventsHandler {
+	return &EventsHandler{
+		broker: broker,
+		logger: logger,
+	}
+}
+
+// Stream handles GET /api/v1/events, sending each event as it's published
+// until the client goes away
+func (h *EventsHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("Stream: %s %s", r.Method, r.URL.Path)
+
+	controller := http.NewResponseController(w)
+	subscription, unsubscribe := h.broker.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := controller.Flush(); err != nil {
+		h.logger.Printf("Event stream can't be flushed: %v", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(eventsHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case event := <-subscription:
+			if err := writeEvent(w, event); err != nil {
+				h.logger.Printf("Failed to write event %d: %v", event.ID, err)
+				return
+			}
+		}
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeEvent writes an event in the Server-Sent Events format, with the
+// event's data as JSON
+func writeEvent(w http.ResponseWriter, event model.Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
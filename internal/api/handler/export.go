@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/benrowe/nab-bank-api/internal/export"
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// ExportHandler handles data export HTTP requests
+type ExportHandler struct {
+	exporter  *export.Exporter
+	redaction export.Redaction
+	logger    *log.Logger
+}
+
+// NewExportHandler creates a new export handler. A nil exporter means no
+// export destination is configured; redaction is used when a request does
+// not choose its own.
+func NewExportHandler(exporter *export.Exporter, redaction export.Redaction, logger *log.Logger) *ExportHandler {
+	return &ExportHandler{
+		exporter:  exporter,
+		redaction: redaction,
+		logger:    logger,
+	}
+}
+
+// ExportParquet handles POST /api/v1/exports/parquet
+func (h *ExportHandler) ExportParquet(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("ExportParquet: %s %s", r.Method, r.URL.Path)
+
+	if h.exporter == nil {
+		writeErrorResponse(w, h.logger, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "Export destination is not configured", nil)
+		return
+	}
+
+	redaction := h.redaction
+	if value := r.URL.Query().Get("redact"); value != "" {
+		parsed, err := export.ParseRedaction(value)
+		if err != nil {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid redact parameter", err.Error())
+			return
+		}
+		redaction = parsed
+	}
+
+	result, err := h.exporter.Export(r.Context(), export.Options{Redaction: redaction})
+	if err != nil {
+		h.logger.Printf("Failed to export data: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to export data", err.Error())
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, result)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// GoalsHandler handles savings goal HTTP requests
+type GoalsHandler struct {
+	goalService service.SavingsGoalService
+	logger      *log.Logger
+}
+
+// NewGoalsHandler creates a new savings goals handler
+func NewGoalsHandler(goalService service.SavingsGoalService, logger *log.Logger) *GoalsHandler {
+	return &GoalsHandler{
+		goalService: goalService,
+		logger:      logger,
+	}
+}
+
+// ListGoals handles GET /api/v1/accounts/{accountId}/goals
+func (h *GoalsHandler) ListGoals(w http.ResponseWriter, r *http.Request) {
+	accountID := mux.Vars(r)["accountId"]
+	h.logger.Printf("ListGoals: %s %s (account: %s)", r.Method, r.URL.Path, accountID)
+
+	goals, err := h.goalService.GetSavingsGoals(r.Context(), accountID)
+	if err != nil {
+		h.logger.Printf("Failed to get savings goals: %v", err)
+		if writeBlockedResponse(w, h.logger, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrAccountNotFound):
+			writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeAccountNotFound, "Account not found", nil)
+		case errors.Is(err, service.ErrSavingsGoalsUnsupported):
+			writeErrorResponse(w, h.logger, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "Savings goals are not available", nil)
+		default:
+			writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to retrieve savings goals", errorDetails(err))
+		}
+		return
+	}
+
+	response := model.SavingsGoalsResponse{
+		AccountID:   accountID,
+		Goals:       goals,
+		RetrievedAt: time.Now(),
+		Count:       len(goals),
+	}
+	if response.Goals == nil {
+		response.Goals = []model.SavingsGoal{}
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
@@ -0,0 +1,321 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/benrowe/nab-bank-api/internal/graphql"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// Queries are limited in depth and size, as a single query could otherwise
+// ask for the same accounts over and over under different aliases
+const (
+	graphQLMaxDepth  = 10
+	graphQLMaxFields = 500
+)
+
+// GraphQLHandler serves the GraphQL API alongside the REST routes
+type GraphQLHandler struct {
+	schema *graphql.Schema
+	logger *log.Logger
+}
+
+// NewGraphQLHandler creates a new GraphQL handler
+func NewGraphQLHandler(accountService service.AccountService, store *store.Store, logger *log.Logger) (*GraphQLHandler, error) {
+	schema, err := newGraphQLSchema(accountService, store)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GraphQLHandler{
+		schema: schema,
+		logger: logger,
+	}, nil
+}
+
+// ServeGraphQL handles GET and POST /graphql
+func (h *GraphQLHandler) ServeGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req graphql.Request
+	if r.Method == http.MethodGet {
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+		if variables := r.URL.Query().Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid variables", err.Error())
+				return
+			}
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid GraphQL request body", err.Error())
+		return
+	}
+
+	if req.Query == "" {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Query is required", nil)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), accountDetailsKey{}, &accountDetailsCache{})
+	writeJSONResponse(w, h.logger, http.StatusOK, h.schema.Execute(ctx, req))
+}
+
+// accountDetailsKey is the context key for a request's accountDetailsCache
+type accountDetailsKey struct{}
+
+// accountDetailsCache holds the account details looked up for one request,
+// and any error looking them up, so each account is scraped at most once
+// however often a query asks for it
+type accountDetailsCache struct {
+	mu      sync.Mutex
+	lookups map[string]accountDetailsLookup
+}
+
+// accountDetailsLookup is the outcome of looking up an account's details
+type accountDetailsLookup struct {
+	details *model.AccountDetails
+	err     error
+}
+
+// accountDetails looks up an account's details, once per request
+func accountDetails(ctx context.Context, accountService service.AccountService, accountID string) (*model.AccountDetails, error) {
+	cache, ok := ctx.Value(accountDetailsKey{}).(*accountDetailsCache)
+	if !ok {
+		return accountService.GetAccountDetails(ctx, accountID)
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	lookup, ok := cache.lookups[accountID]
+	if !ok {
+		lookup.details, lookup.err = accountService.GetAccountDetails(ctx, accountID)
+		if cache.lookups == nil {
+			cache.lookups = make(map[string]accountDetailsLookup)
+		}
+		cache.lookups[accountID] = lookup
+	}
+	return lookup.details, lookup.err
+}
+
+// newGraphQLSchema builds the schema exposing accounts, transactions and
+// balance history. Transactions are read from the store unless a query
+// asks for them to be refreshed.
+func newGraphQLSchema(accountService service.AccountService, store *store.Store) (*graphql.Schema, error) {
+	accountTransactions := func(ctx context.Context, accountID string, args map[string]interface{}) (interface{}, error) {
+		transactions := store.Transactions(accountID)
+		if refresh, _ := args["refresh"].(bool); refresh {
+			details, err := accountDetails(ctx, accountService, accountID)
+			if err != nil {
+				return nil, graphQLError(err)
+			}
+			transactions = details.Transactions
+		}
+
+		if limit, ok := graphql.IntArg(args, "limit"); ok && limit >= 0 && limit < len(transactions) {
+			transactions = transactions[:limit]
+		}
+		return transactions, nil
+	}
+
+	money := &graphql.Object{
+		Name: "Money",
+		Fields: map[string]*graphql.Field{
+			"amount":   {},
+			"currency": {},
+		},
+	}
+
+	transaction := &graphql.Object{
+		Name: "Transaction",
+		Fields: map[string]*graphql.Field{
+			"id":          {},
+			"date":        {},
+			"description": {},
+			"amount":      {Type: "Money"},
+			"balance":     {Type: "Money"},
+			"category":    {},
+			"merchant":    {},
+			"type":        {},
+			"feeType":     {},
+		},
+	}
+
+	interest := &graphql.Object{
+		Name: "Interest",
+		Fields: map[string]*graphql.Field{
+			"rate":                    {},
+			"baseRate":                {},
+			"bonusRate":               {},
+			"earnedThisFinancialYear": {Type: "Money"},
+			"bonusQualified":          {},
+		},
+	}
+
+	credit := &graphql.Object{
+		Name: "Credit",
+		Fields: map[string]*graphql.Field{
+			"creditLimit":      {Type: "Money"},
+			"availableCredit":  {Type: "Money"},
+			"statementBalance": {Type: "Money"},
+			"minimumPayment":   {Type: "Money"},
+			"paymentDueDate":   {},
+		},
+	}
+
+	loan := &graphql.Object{
+		Name: "Loan",
+		Fields: map[string]*graphql.Field{
+			"interestRate":       {},
+			"repaymentAmount":    {Type: "Money"},
+			"repaymentFrequency": {},
+			"nextRepaymentDate":  {},
+			"redrawAvailable":    {Type: "Money"},
+			"originalAmount":     {Type: "Money"},
+		},
+	}
+
+	termDeposit := &graphql.Object{
+		Name: "TermDeposit",
+		Fields: map[string]*graphql.Field{
+			"interestRate":       {},
+			"term":               {},
+			"maturityDate":       {},
+			"interestAtMaturity": {Type: "Money"},
+		},
+	}
+
+	balanceHistory := &graphql.Object{
+		Name: "BalanceHistory",
+		Fields: map[string]*graphql.Field{
+			"accountId":        {},
+			"balance":          {Type: "Money"},
+			"availableBalance": {Type: "Money"},
+			"recordedAt":       {},
+		},
+	}
+
+	account := &graphql.Object{
+		Name: "Account",
+		Fields: map[string]*graphql.Field{
+			"id":               {},
+			"name":             {},
+			"type":             {},
+			"balance":          {Type: "Money"},
+			"availableBalance": {Type: "Money"},
+			"accountNumber":    {},
+			"bsb":              {},
+			"interest":         {Type: "Interest"},
+			"credit":           {Type: "Credit"},
+			"termDeposit":      {Type: "TermDeposit"},
+			"loan": {
+				Type: "Loan",
+				Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+					account := source.(model.Account)
+					if account.Type != model.AccountTypeLoan {
+						return nil, nil
+					}
+
+					details, err := accountDetails(ctx, accountService, account.ID)
+					if err != nil {
+						return nil, graphQLError(err)
+					}
+					return details.Loan, nil
+				},
+			},
+			"lastUpdated": {},
+			"transactions": {
+				Type: "Transaction",
+				Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+					return accountTransactions(ctx, source.(model.Account).ID, args)
+				},
+			},
+			"balanceHistory": {
+				Type: "BalanceHistory",
+				Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+					return store.BalanceHistory(source.(model.Account).ID), nil
+				},
+			},
+		},
+	}
+
+	query := &graphql.Object{
+		Name: "Query",
+		Fields: map[string]*graphql.Field{
+			"accounts": {
+				Type: "Account",
+				Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+					accounts, err := accountService.GetAllAccounts(ctx)
+					if err != nil {
+						return nil, graphQLError(err)
+					}
+					return accounts, nil
+				},
+			},
+			"account": {
+				Type: "Account",
+				Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+					id, ok := graphql.StringArg(args, "id")
+					if !ok {
+						return nil, errors.New("argument \"id\" is required")
+					}
+
+					details, err := accountDetails(ctx, accountService, id)
+					if err != nil {
+						return nil, graphQLError(err)
+					}
+					return details.Account, nil
+				},
+			},
+			"transactions": {
+				Type: "Transaction",
+				Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+					accountID, ok := graphql.StringArg(args, "accountId")
+					if !ok {
+						return nil, errors.New("argument \"accountId\" is required")
+					}
+					return accountTransactions(ctx, accountID, args)
+				},
+			},
+			"balanceHistory": {
+				Type: "BalanceHistory",
+				Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+					accountID, _ := graphql.StringArg(args, "accountId")
+					return store.BalanceHistory(accountID), nil
+				},
+			},
+		},
+	}
+
+	schema, err := graphql.NewSchema(query, account, interest, credit, loan, termDeposit, transaction, balanceHistory, money)
+	if err != nil {
+		return nil, err
+	}
+	schema.MaxDepth = graphQLMaxDepth
+	schema.MaxFields = graphQLMaxFields
+	return schema, nil
+}
+
+// graphQLError prefixes service errors with the error types used by the
+// REST API so clients can handle both surfaces the same way
+func graphQLError(err error) error {
+	errorType := model.ErrorTypeInternalError
+	switch {
+	case errors.Is(err, service.ErrAccountNotFound):
+		errorType = model.ErrorTypeAccountNotFound
+	case errors.Is(err, service.ErrServiceUnavailable):
+		errorType = model.ErrorTypeServiceUnavailable
+	case errors.Is(err, service.ErrAuthenticationFailed):
+		errorType = model.ErrorTypeAuthenticationFailed
+	case errors.Is(err, service.ErrChallengeRequired):
+		errorType = model.ErrorTypeChallengeRequired
+	}
+
+	return fmt.Errorf("%s: %w", errorType, err)
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// HealthHandler handles readiness and detailed health HTTP requests
+type HealthHandler struct {
+	warmUp         *service.WarmUp
+	accountService service.AccountService
+	nabClient      service.BankProvider
+	store          *store.Store
+	logger         *log.Logger
+}
+
+// NewHealthHandler creates a new health handler. Scrape and browser
+// details are reported when the account service and NAB client track them.
+func NewHealthHandler(warmUp *service.WarmUp, accountService service.AccountService, nabClient service.BankProvider, store *store.Store, logger *log.Logger) *HealthHandler {
+	return &HealthHandler{
+		warmUp:         warmUp,
+		accountService: accountService,
+		nabClient:      nabClient,
+		store:          store,
+		logger:         logger,
+	}
+}
+
+// Ready handles GET /health/ready. It returns 503 while the browser is
+// warming up; a failed warm-up is reported as degraded but still ready, as
+// requests fall back to logging in themselves.
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	warmUp := h.warmUp.Status()
+
+	response := model.ReadinessResponse{
+		Status:    model.ReadinessReady,
+		WarmUp:    warmUp,
+		Timestamp: time.Now(),
+	}
+	status := http.StatusOK
+	switch warmUp.State {
+	case model.WarmUpStatePending, model.WarmUpStateRunning:
+		response.Status = model.ReadinessWarmingUp
+		status = http.StatusServiceUnavailable
+	case model.WarmUpStateFailed:
+		response.Status = model.ReadinessDegraded
+	}
+
+	writeJSONResponse(w, h.logger, status, response)
+}
+
+// Detailed handles GET /health/detailed, reporting recent scrapes, the NAB
+// login session, browsers and how fresh the stored accounts are. It returns
+// 503 when scraping is known to be broken: the circuit breaker is open,
+// scraping is paused for updated terms, or NAB rejected the credentials.
+func (h *HealthHandler) Detailed(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	response := model.DetailedHealthResponse{
+		Status:    model.HealthStatusHealthy,
+		Cache:     h.cacheHealth(now),
+		WarmUp:    h.warmUp.Status(),
+		Timestamp: now,
+	}
+
+	var broken, degraded []string
+	if reporter, ok := h.accountService.(service.ScrapeHealthReporter); ok {
+		scrape := reporter.ScrapeHealth()
+		response.Scrape = &scrape
+		switch {
+		case scrape.CircuitOpen:
+			broken = append(broken, fmt.Sprintf("scraping suspended after %d consecutive failures", scrape.ConsecutiveFailures))
+		case scrape.AuthenticationFailed:
+			broken = append(broken, "NAB rejected the login credentials")
+		case scrape.ConsecutiveFailures > 0:
+			degraded = append(degraded, fmt.Sprintf("last %d scrapes failed: %s", scrape.ConsecutiveFailures, scrape.LastError))
+		}
+		if scrape.BalanceGaps > 0 {
+			degraded = append(degraded, fmt.Sprintf("%d running balance gaps in synced transactions, which may be missing some", scrape.BalanceGaps))
+		}
+	}
+	if reporter, ok := h.nabClient.(service.BrowserHealthReporter); ok {
+		browser := reporter.BrowserHealth()
+		response.Browser = &browser
+		if browser.PausedUntil != nil {
+			broken = append(broken, "scraping paused until the updated NAB terms are accepted")
+		}
+	}
+	if response.WarmUp.State == model.WarmUpStateFailed {
+		degraded = append(degraded, "browser warm-up failed: "+response.WarmUp.Error)
+	}
+
+	status := http.StatusOK
+	switch {
+	case len(broken) > 0:
+		response.Status = model.HealthStatusBroken
+		status = http.StatusServiceUnavailable
+	case len(degraded) > 0:
+		response.Status = model.HealthStatusDegraded
+	}
+	response.Problems = append(broken, degraded...)
+
+	writeJSONResponse(w, h.logger, status, response)
+}
+
+// cacheHealth reports the stored accounts and when they were last scraped
+func (h *HealthHandler) cacheHealth(now time.Time) model.CacheHealth {
+	accounts := h.store.Accounts()
+	cache := model.CacheHealth{Accounts: len(accounts)}
+	for _, account := range accounts {
+		if account.LastUpdated != nil && (cache.LastUpdatedAt == nil || account.LastUpdated.After(*cache.LastUpdatedAt)) {
+			cache.LastUpdatedAt = account.LastUpdated
+		}
+	}
+	if cache.LastUpdatedAt != nil {
+		age := int64(now.Sub(*cache.LastUpdatedAt).Seconds())
+		cache.AgeSeconds = &age
+	}
+	return cache
+}
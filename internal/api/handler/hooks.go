@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/benrowe/nab-bank-api/internal/hooks"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/benrowe/nab-bank-api/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// HooksHandler handles refresh hook registration HTTP requests
+type HooksHandler struct {
+	store  *store.Store
+	logger *log.Logger
+}
+
+// NewHooksHandler creates a new hooks handler
+func NewHooksHandler(store *store.Store, logger *log.Logger) *HooksHandler {
+	return &HooksHandler{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// ListHooks handles GET /api/v1/accounts/{accountId}/hooks
+func (h *HooksHandler) ListHooks(w http.ResponseWriter, r *http.Request) {
+	accountID := mux.Vars(r)["accountId"]
+	h.logger.Printf("ListHooks: %s %s (account: %s)", r.Method, r.URL.Path, accountID)
+
+	response := model.RefreshHooksResponse{Hooks: []model.RefreshHook{}}
+	for _, hook := range h.store.Hooks(accountID) {
+		response.Hooks = append(response.Hooks, redactHook(hook))
+	}
+	response.Count = len(response.Hooks)
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// CreateHook handles POST /api/v1/accounts/{accountId}/hooks
+func (h *HooksHandler) CreateHook(w http.ResponseWriter, r *http.Request) {
+	accountID := mux.Vars(r)["accountId"]
+	h.logger.Printf("CreateHook: %s %s (account: %s)", r.Method, r.URL.Path, accountID)
+
+	var req model.RefreshHookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid request body", err.Error())
+		return
+	}
+	if err := hooks.Validate(&req); err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid hook", err.Error())
+		return
+	}
+	if !h.knownAccount(accountID) {
+		writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeAccountNotFound, "Account not found", nil)
+		return
+	}
+
+	hook, err := hooks.NewHook(accountID, req)
+	if err == nil {
+		err = h.store.SaveHook(hook)
+	}
+	if err != nil {
+		h.logger.Printf("Failed to register hook: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to register hook", err.Error())
+		return
+	}
+
+	setETag(w, hook.Version)
+	writeJSONResponse(w, h.logger, http.StatusCreated, redactHook(hook))
+}
+
+// DeleteHook handles DELETE /api/v1/accounts/{accountId}/hooks/{hookId}
+func (h *HooksHandler) DeleteHook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	h.logger.Printf("DeleteHook: %s %s (account: %s, hook: %s)", r.Method, r.URL.Path, vars["accountId"], vars["hookId"])
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid If-Match header", err.Error())
+		return
+	}
+	if !h.hasHook(vars["accountId"], vars["hookId"]) {
+		writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeHookNotFound, "Hook not found", nil)
+		return
+	}
+
+	_, err = h.store.DeleteHook(vars["hookId"], func(hook model.RefreshHook) error {
+		return service.CheckVersion(hook.Version, version)
+	})
+	if writeVersionMismatch(w, h.logger, err) {
+		return
+	}
+	if err != nil {
+		h.logger.Printf("Failed to delete hook: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to delete hook", err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// knownAccount reports whether the account has been scraped. Before the
+// first scrape every account is accepted.
+func (h *HooksHandler) knownAccount(accountID string) bool {
+	accounts := h.store.Accounts()
+	for _, account := range accounts {
+		if account.ID == accountID {
+			return true
+		}
+	}
+	return len(accounts) == 0
+}
+
+// hasHook reports whether the hook is registered on the account
+func (h *HooksHandler) hasHook(accountID, hookID string) bool {
+	for _, hook := range h.store.Hooks(accountID) {
+		if hook.ID == hookID {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHook hides the signing secret in responses
+func redactHook(hook model.RefreshHook) model.RefreshHook {
+	if hook.Secret != "" {
+		hook.Secret = "********"
+	}
+	return hook
+}
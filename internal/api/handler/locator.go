@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/locator"
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// maxLocatorRadiusKm caps how wide a single locator search may be
+const maxLocatorRadiusKm = 50
+
+// LocatorHandler handles ATM and branch locator HTTP requests
+type LocatorHandler struct {
+	client *locator.Client
+	logger *log.Logger
+}
+
+// NewLocatorHandler creates a new locator handler
+func NewLocatorHandler(client *locator.Client, logger *log.Logger) *LocatorHandler {
+	return &LocatorHandler{
+		client: client,
+		logger: logger,
+	}
+}
+
+// Search handles GET /api/v1/locator
+func (h *LocatorHandler) Search(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("Locator: %s %s", r.Method, r.URL.Path)
+
+	query := r.URL.Query()
+	lat, latErr := strconv.ParseFloat(query.Get("lat"), 64)
+	lng, lngErr := strconv.ParseFloat(query.Get("lng"), 64)
+	if latErr != nil || lngErr != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "lat and lng must be valid coordinates", nil)
+		return
+	}
+
+	q := locator.Query{Latitude: lat, Longitude: lng, RadiusKm: 5, Limit: 20}
+	if value := query.Get("radius"); value != "" {
+		radius, err := strconv.ParseFloat(value, 64)
+		if err != nil || radius <= 0 || radius > maxLocatorRadiusKm {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "radius must be between 0 and 50 km", nil)
+			return
+		}
+		q.RadiusKm = radius
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "limit must be a positive integer", nil)
+			return
+		}
+		q.Limit = limit
+	}
+	switch value := query.Get("type"); value {
+	case "", model.LocationTypeATM, model.LocationTypeBranch:
+		q.Type = value
+	default:
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "type must be atm or branch", nil)
+		return
+	}
+
+	locations, cached, err := h.client.Search(r.Context(), q)
+	if err != nil {
+		h.logger.Printf("Locator search failed: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "NAB locator is unavailable", err.Error())
+		return
+	}
+	if locations == nil {
+		locations = []model.Location{}
+	}
+
+	response := model.LocationsResponse{
+		Locations:   locations,
+		Count:       len(locations),
+		Cached:      cached,
+		RetrievedAt: time.Now(),
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
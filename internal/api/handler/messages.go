@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// MessagesHandler handles secure message HTTP requests
+type MessagesHandler struct {
+	messageService service.MessageService
+	logger         *log.Logger
+}
+
+// NewMessagesHandler creates a new messages handler
+func NewMessagesHandler(messageService service.MessageService, logger *log.Logger) *MessagesHandler {
+	return &MessagesHandler{
+		messageService: messageService,
+		logger:         logger,
+	}
+}
+
+// ListMessages handles GET /api/v1/messages
+func (h *MessagesHandler) ListMessages(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("ListMessages: %s %s", r.Method, r.URL.Path)
+
+	unreadOnly := false
+	if value := r.URL.Query().Get("unread"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid unread parameter", err.Error())
+			return
+		}
+		unreadOnly = parsed
+	}
+
+	messages, err := h.messageService.GetMessages(r.Context())
+	if err != nil {
+		h.logger.Printf("Failed to get messages: %v", err)
+		if writeBlockedResponse(w, h.logger, err) {
+			return
+		}
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to retrieve messages", errorDetails(err))
+		return
+	}
+
+	response := model.MessagesResponse{
+		Messages:    []model.Message{},
+		RetrievedAt: time.Now(),
+	}
+	for _, message := range messages {
+		if !message.Read {
+			response.Unread++
+		} else if unreadOnly {
+			continue
+		}
+		response.Messages = append(response.Messages, message)
+	}
+	response.Count = len(response.Messages)
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// AccountMetadataHandler handles account metadata HTTP requests
+type AccountMetadataHandler struct {
+	metadata service.AccountMetadataService
+	logger   *log.Logger
+}
+
+// NewAccountMetadataHandler creates a new account metadata handler
+func NewAccountMetadataHandler(metadata service.AccountMetadataService, logger *log.Logger) *AccountMetadataHandler {
+	return &AccountMetadataHandler{
+		metadata: metadata,
+		logger:   logger,
+	}
+}
+
+// UpdateMetadata handles PATCH /api/v1/accounts/{accountId}/metadata
+func (h *AccountMetadataHandler) UpdateMetadata(w http.ResponseWriter, r *http.Request) {
+	accountID := mux.Vars(r)["accountId"]
+	h.logger.Printf("UpdateMetadata: %s %s (account: %s)", r.Method, r.URL.Path, accountID)
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid If-Match header", err.Error())
+		return
+	}
+	var req model.AccountMetadataRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	metadata, err := h.metadata.Update(accountID, req, version)
+	if err != nil {
+		if writeVersionMismatch(w, h.logger, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrInvalidAccountMetadata):
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid account metadata", err.Error())
+		case errors.Is(err, service.ErrAccountNotFound):
+			writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeAccountNotFound, "Account not found or not scraped yet", nil)
+		default:
+			h.logger.Printf("Failed to update account metadata: %v", err)
+			writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to update account metadata", err.Error())
+		}
+		return
+	}
+
+	setETag(w, metadata.Version)
+	writeJSONResponse(w, h.logger, http.StatusOK, metadata)
+}
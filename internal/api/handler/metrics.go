@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"bufio"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// labelEscaper escapes Prometheus label values
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// MetricsHandler serves metrics for Prometheus to scrape
+type MetricsHandler struct {
+	nabClient service.BankProvider
+	logger    *log.Logger
+}
+
+// NewMetricsHandler creates a new metrics handler. Step metrics are only
+// reported when the NAB client times its steps.
+func NewMetricsHandler(nabClient service.BankProvider, logger *log.Logger) *MetricsHandler {
+	return &MetricsHandler{
+		nabClient: nabClient,
+		logger:    logger,
+	}
+}
+
+// Metrics handles GET /metrics, writing the duration of each step of the
+// NAB login and scrape flow as a histogram, how often each failed, and the
+// last error of each as a label on the time it happened
+func (h *MetricsHandler) Metrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	reporter, ok := h.nabClient.(service.StepMetricsReporter)
+	if !ok {
+		return
+	}
+	steps := reporter.StepMetrics()
+
+	out := bufio.NewWriter(w)
+	out.WriteString("# HELP nab_scrape_step_duration_seconds How long each step of the NAB login and scrape takes\n")
+	out.WriteString("# TYPE nab_scrape_step_duration_seconds histogram\n")
+	for _, step := range steps {
+		label := `step="` + labelEscaper.Replace(step.Step) + `"`
+		for _, bucket := range step.Buckets {
+			out.WriteString("nab_scrape_step_duration_seconds_bucket{" + label + `,le="` + formatFloat(bucket.UpperBound) + `"} ` + strconv.FormatInt(bucket.Count, 10) + "\n")
+		}
+		out.WriteString("nab_scrape_step_duration_seconds_bucket{" + label + `,le="+Inf"} ` + strconv.FormatInt(step.Count, 10) + "\n")
+		out.WriteString("nab_scrape_step_duration_seconds_sum{" + label + "} " + formatFloat(step.TotalSeconds) + "\n")
+		out.WriteString("nab_scrape_step_duration_seconds_count{" + label + "} " + strconv.FormatInt(step.Count, 10) + "\n")
+	}
+
+	out.WriteString("# HELP nab_scrape_step_failures_total Runs of each step of the NAB login and scrape that failed\n")
+	out.WriteString("# TYPE nab_scrape_step_failures_total counter\n")
+	for _, step := range steps {
+		out.WriteString(`nab_scrape_step_failures_total{step="` + labelEscaper.Replace(step.Step) + `"} ` + strconv.FormatInt(step.Failures, 10) + "\n")
+	}
+
+	out.WriteString("# HELP nab_scrape_step_last_error_timestamp_seconds When each step of the NAB login and scrape last failed, labelled with the error\n")
+	out.WriteString("# TYPE nab_scrape_step_last_error_timestamp_seconds gauge\n")
+	for _, step := range steps {
+		if step.LastErrorAt == nil {
+			continue
+		}
+		out.WriteString(`nab_scrape_step_last_error_timestamp_seconds{step="` + labelEscaper.Replace(step.Step) + `",error="` + labelEscaper.Replace(step.LastError) + `"} ` + strconv.FormatInt(step.LastErrorAt.Unix(), 10) + "\n")
+	}
+
+	if err := out.Flush(); err != nil {
+		h.logger.Printf("Failed to write metrics: %v", err)
+	}
+}
+
+// formatFloat formats a sample value or bucket bound
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,1288 @@
+package handler
+
+import (
+	"github.com/benrowe/nab-bank-api/internal/graphql"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/openapi"
+)
+
+// OpenAPIDocument describes the HTTP API. Keep it in step with the routes
+// registered in cmd/server.
+func OpenAPIDocument() *openapi.Document {
+	errorResponse := model.ErrorResponse{}
+	builder := openapi.NewBuilder(openapi.Info{
+		Title:       "NAB Bank API",
+		Description: "REST API for NAB internet banking account data",
+		Version:     "1.0.0",
+	})
+
+	builder.Add(openapi.Route{
+		Method:      "GET",
+		Path:        "/health",
+		Summary:     "Health check",
+		Tag:         "system",
+		Responses:   map[int]interface{}{200: ""},
+		ContentType: "text/plain",
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/health/ready",
+		Summary: "Readiness check; 503 while the browser is warming up",
+		Tag:     "system",
+		Responses: map[int]interface{}{
+			200: model.ReadinessResponse{},
+			503: model.ReadinessResponse{},
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/livez",
+		Summary: "Liveness probe; succeeds while the process is serving requests",
+		Tag:     "system",
+		Responses: map[int]interface{}{
+			200: model.ProbeResponse{},
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/readyz",
+		Summary: "Readiness probe checking configuration, browser, store and warm-up; 503 until ready to scrape",
+		Tag:     "system",
+		Responses: map[int]interface{}{
+			200: model.ProbeResponse{},
+			503: model.ProbeResponse{},
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/health/detailed",
+		Summary: "Detailed health: recent scrapes, NAB session, browsers and cache freshness; 503 when scraping is broken",
+		Tag:     "system",
+		Responses: map[int]interface{}{
+			200: model.DetailedHealthResponse{},
+			503: model.DetailedHealthResponse{},
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:      "GET",
+		Path:        "/metrics",
+		Summary:     "Prometheus metrics: duration histograms, failure counts and the last error of each step of the NAB login and scrape",
+		Tag:         "system",
+		Responses:   map[int]interface{}{200: ""},
+		ContentType: "text/plain",
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api",
+		Summary: "List API versions, which is current and when deprecated ones will be removed",
+		Tag:     "system",
+		Responses: map[int]interface{}{
+			200: model.APIVersionsResponse{},
+		},
+	})
+	listContentTypes := supportedListMediaTypes()[1:]
+	cursorParameter := openapi.Parameter{
+		Name:        "cursor",
+		In:          "query",
+		Description: "nextCursor from the previous page",
+		Schema:      &openapi.Schema{Type: "string"},
+	}
+	asOfParameter := openapi.Parameter{
+		Name:        "asOf",
+		In:          "query",
+		Description: "Show the data as recorded at this time instead of scraping: a date (end of day) or an RFC 3339 time",
+		Schema:      &openapi.Schema{Type: "string", Example: "2024-06-30"},
+	}
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/accounts",
+		Summary: "List all accounts",
+		Tag:     "accounts",
+		Parameters: []openapi.Parameter{
+			{Name: "limit", In: "query", Description: "Maximum accounts per page (default: all)", Schema: &openapi.Schema{Type: "integer"}},
+			{Name: "type", In: "query", Description: "Comma separated account types to include", Schema: &openapi.Schema{Type: "string", Example: "savings,credit"}},
+			{Name: "minBalance", In: "query", Description: "Only accounts with at least this balance", Schema: &openapi.Schema{Type: "string", Example: "0"}},
+			{Name: "maxBalance", In: "query", Description: "Only accounts with at most this balance", Schema: &openapi.Schema{Type: "string", Example: "10000"}},
+			{Name: "sort", In: "query", Description: "balance or name, prefixed with - for descending (default: NAB's order)", Schema: &openapi.Schema{Type: "string", Example: "-balance"}},
+			cursorParameter,
+			asOfParameter,
+		},
+		AlternateContentTypes: listContentTypes,
+		Responses: map[int]interface{}{
+			200: model.AccountsResponse{},
+			400: errorResponse,
+			406: errorResponse,
+			401: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+			504: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/accounts/{accountId}",
+		Summary: "Get account details with recent transactions",
+		Tag:     "accounts",
+		Parameters: []openapi.Parameter{
+			{Name: "accountId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Example: "12345678"}},
+			asOfParameter,
+			{Name: "statement", In: "query", Description: "Only transactions in a credit card's current or previous statement period", Schema: &openapi.Schema{Type: "string", Enum: []string{"current", "previous"}}},
+		},
+		Responses: map[int]interface{}{
+			200: model.AccountDetailsResponse{},
+			400: errorResponse,
+			401: errorResponse,
+			404: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+			504: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/accounts/{accountId}/direct-debits",
+		Summary: "List the direct debit authorities merchants hold on an account",
+		Tag:     "accounts",
+		Parameters: []openapi.Parameter{
+			{Name: "accountId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Example: "12345678"}},
+		},
+		Responses: map[int]interface{}{
+			200: model.DirectDebitsResponse{},
+			404: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+			504: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/accounts/{accountId}/goals",
+		Summary: "List the savings goals set on an account with their target, amount saved and target date",
+		Tag:     "accounts",
+		Parameters: []openapi.Parameter{
+			{Name: "accountId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Example: "11223344"}},
+		},
+		Responses: map[int]interface{}{
+			200: model.SavingsGoalsResponse{},
+			404: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+			504: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "POST",
+		Path:    "/api/v1/accounts/{accountId}/transactions/{transactionId}/dispute",
+		Summary: "Prepare a pre-filled dispute summary for a transaction",
+		Tag:     "accounts",
+		Parameters: []openapi.Parameter{
+			{Name: "accountId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Example: "12345678"}},
+			{Name: "transactionId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Example: "txn_20231017_001"}},
+			{Name: "format", In: "query", Description: "Return the plain text document instead of JSON", Schema: &openapi.Schema{Type: "string", Enum: []string{"text"}}},
+		},
+		Request: model.DisputeRequest{},
+		Responses: map[int]interface{}{
+			200: model.DisputeSummary{},
+			400: errorResponse,
+			401: errorResponse,
+			404: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+			504: errorResponse,
+		},
+		Secured: true,
+	})
+	ifMatchParameter := openapi.Parameter{
+		Name:        "If-Match",
+		In:          "header",
+		Description: "ETag (version) the change is conditional on; a stale one gets 412",
+		Schema:      &openapi.Schema{Type: "string", Example: `"1"`},
+	}
+	idempotencyKeyParameter := openapi.Parameter{
+		Name:        "Idempotency-Key",
+		In:          "header",
+		Description: "Unique key for the request; retries with the same key get the first response replayed instead of running again",
+		Schema:      &openapi.Schema{Type: "string", Example: "7f3c9a2e-transfer-rent-may"},
+	}
+	hookParameters := []openapi.Parameter{
+		{Name: "accountId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Example: "12345678"}},
+	}
+	builder.Add(openapi.Route{
+		Method:     "GET",
+		Path:       "/api/v1/accounts/{accountId}/hooks",
+		Summary:    "List the refresh hooks called around scheduled scrapes of an account",
+		Tag:        "hooks",
+		Parameters: hookParameters,
+		Responses: map[int]interface{}{
+			200: model.RefreshHooksResponse{},
+			401: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:     "POST",
+		Path:       "/api/v1/accounts/{accountId}/hooks",
+		Summary:    "Register a webhook called before (pre) and after (post) scheduled scrapes of an account",
+		Tag:        "hooks",
+		Parameters: hookParameters,
+		Request:    model.RefreshHookRequest{},
+		Responses: map[int]interface{}{
+			201: model.RefreshHook{},
+			400: errorResponse,
+			401: errorResponse,
+			404: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:     "POST",
+		Path:       "/api/v1/accounts/{accountId}/balance-assertions",
+		Summary:    "Compare a balance expected by an external system with the latest scraped balance",
+		Tag:        "accounts",
+		Parameters: hookParameters,
+		Request:    model.BalanceAssertionRequest{},
+		Responses: map[int]interface{}{
+			200: model.BalanceAssertionResponse{},
+			400: errorResponse,
+			401: errorResponse,
+			404: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "PATCH",
+		Path:    "/api/v1/accounts/{accountId}/metadata",
+		Summary: "Set an account's nickname, emoji, colour and notes; fields left out are kept and empty ones cleared",
+		Tag:     "accounts",
+		Parameters: []openapi.Parameter{
+			{Name: "accountId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Example: "12345678"}},
+			ifMatchParameter,
+		},
+		Request: model.AccountMetadataRequest{},
+		Responses: map[int]interface{}{
+			200: model.AccountMetadata{},
+			400: errorResponse,
+			401: errorResponse,
+			404: errorResponse,
+			412: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	annotationParameters := []openapi.Parameter{
+		{Name: "accountId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Example: "12345678"}},
+		{Name: "transactionId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Example: "txn_20231017_001"}},
+	}
+	annotationIDParameters := []openapi.Parameter{
+		{Name: "annotationId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Example: "ann_1f2e3d4c5b6a7988"}},
+		ifMatchParameter,
+	}
+	builder.Add(openapi.Route{
+		Method:     "GET",
+		Path:       "/api/v1/accounts/{accountId}/transactions/{transactionId}/annotations",
+		Summary:    "List the tags and notes on a stored transaction",
+		Tag:        "annotations",
+		Parameters: annotationParameters,
+		Responses: map[int]interface{}{
+			200: model.AnnotationsResponse{},
+			401: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:     "POST",
+		Path:       "/api/v1/accounts/{accountId}/transactions/{transactionId}/annotations",
+		Summary:    "Tag or add a note to a stored transaction",
+		Tag:        "annotations",
+		Parameters: annotationParameters,
+		Request:    model.AnnotationRequest{},
+		Responses: map[int]interface{}{
+			201: model.Annotation{},
+			400: errorResponse,
+			401: errorResponse,
+			404: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:     "DELETE",
+		Path:       "/api/v1/annotations/{annotationId}",
+		Summary:    "Move an annotation to the trash, where it can be restored until purgeAt",
+		Tag:        "annotations",
+		Parameters: annotationIDParameters,
+		Responses: map[int]interface{}{
+			200: model.Annotation{},
+			401: errorResponse,
+			404: errorResponse,
+			412: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:     "POST",
+		Path:       "/api/v1/annotations/{annotationId}/restore",
+		Summary:    "Restore a deleted annotation from the trash",
+		Tag:        "annotations",
+		Parameters: annotationIDParameters,
+		Responses: map[int]interface{}{
+			200: model.Annotation{},
+			401: errorResponse,
+			404: errorResponse,
+			412: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/annotations/trash",
+		Summary: "List deleted annotations that can still be restored",
+		Tag:     "annotations",
+		Responses: map[int]interface{}{
+			200: model.AnnotationTrashResponse{},
+			401: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/categories",
+		Summary: "List the categories used by rules and stored transactions",
+		Tag:     "categories",
+		Responses: map[int]interface{}{
+			200: model.CategoriesResponse{},
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/categories/rules",
+		Summary: "List the rules filing transactions under categories",
+		Tag:     "categories",
+		Responses: map[int]interface{}{
+			200: model.CategoryRulesResponse{},
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "POST",
+		Path:    "/api/v1/categories/rules",
+		Summary: "Add a category rule, recategorising stored transactions that match",
+		Tag:     "categories",
+		Request: model.CategoryRuleRequest{},
+		Responses: map[int]interface{}{
+			201: model.CategoryRuleResponse{},
+			400: errorResponse,
+			401: errorResponse,
+			409: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	ruleIDParameters := []openapi.Parameter{
+		{Name: "ruleId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Example: "rule_1f2e3d4c5b6a7988"}},
+		ifMatchParameter,
+	}
+	builder.Add(openapi.Route{
+		Method:     "PUT",
+		Path:       "/api/v1/categories/rules/{ruleId}",
+		Summary:    "Change a category rule's pattern and category, recategorising stored transactions",
+		Tag:        "categories",
+		Parameters: ruleIDParameters,
+		Request:    model.CategoryRuleRequest{},
+		Responses: map[int]interface{}{
+			200: model.CategoryRuleResponse{},
+			400: errorResponse,
+			401: errorResponse,
+			404: errorResponse,
+			409: errorResponse,
+			412: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:     "DELETE",
+		Path:       "/api/v1/categories/rules/{ruleId}",
+		Summary:    "Remove a category rule, recategorising stored transactions",
+		Tag:        "categories",
+		Parameters: ruleIDParameters,
+		Responses: map[int]interface{}{
+			200: model.CategoryRuleResponse{},
+			401: errorResponse,
+			404: errorResponse,
+			412: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/budgets",
+		Summary: "List budgets with spending against each limit this period",
+		Tag:     "budgets",
+		Responses: map[int]interface{}{
+			200: model.BudgetsResponse{},
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "POST",
+		Path:    "/api/v1/budgets",
+		Summary: "Add a weekly, monthly or yearly budget for a category",
+		Tag:     "budgets",
+		Request: model.BudgetRequest{},
+		Responses: map[int]interface{}{
+			201: model.BudgetProgress{},
+			400: errorResponse,
+			401: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	budgetIDParameters := []openapi.Parameter{
+		{Name: "budgetId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Example: "bud_1f2e3d4c5b6a7988"}},
+		ifMatchParameter,
+	}
+	builder.Add(openapi.Route{
+		Method:     "PUT",
+		Path:       "/api/v1/budgets/{budgetId}",
+		Summary:    "Change a budget's category, period, limit or alert percentage",
+		Tag:        "budgets",
+		Parameters: budgetIDParameters,
+		Request:    model.BudgetRequest{},
+		Responses: map[int]interface{}{
+			200: model.BudgetProgress{},
+			400: errorResponse,
+			401: errorResponse,
+			404: errorResponse,
+			412: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:     "DELETE",
+		Path:       "/api/v1/budgets/{budgetId}",
+		Summary:    "Remove a budget",
+		Tag:        "budgets",
+		Parameters: budgetIDParameters,
+		Responses: map[int]interface{}{
+			200: model.Budget{},
+			401: errorResponse,
+			404: errorResponse,
+			412: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/reports/spending",
+		Summary: "Spending in a month by category and merchant, compared with the month before",
+		Tag:     "reports",
+		Parameters: []openapi.Parameter{
+			{Name: "period", In: "query", Description: "Month to report on (default: this month)", Schema: &openapi.Schema{Type: "string", Example: "2024-05"}},
+			{Name: "accountId", In: "query", Description: "Only spending from this account", Schema: &openapi.Schema{Type: "string", Example: "12345678"}},
+		},
+		Responses: map[int]interface{}{
+			200: model.SpendingReport{},
+			400: errorResponse,
+			500: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/reports/net-worth",
+		Summary: "Assets less liabilities across every account, with daily history from balance snapshots",
+		Tag:     "reports",
+		Parameters: []openapi.Parameter{
+			{Name: "days", In: "query", Description: "Days of history, up to 365 (default: 90)", Schema: &openapi.Schema{Type: "integer", Example: 30}},
+		},
+		Responses: map[int]interface{}{
+			200: model.NetWorthReport{},
+			400: errorResponse,
+			500: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/reports/fees",
+		Summary: "Account keeping, international transaction, ATM and other fees per account per month",
+		Tag:     "reports",
+		Parameters: []openapi.Parameter{
+			{Name: "months", In: "query", Description: "Months to report on, including this one, up to 36 (default: 12)", Schema: &openapi.Schema{Type: "integer", Example: 12}},
+		},
+		Responses: map[int]interface{}{
+			200: model.FeeReport{},
+			400: errorResponse,
+			500: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/reports/subscriptions",
+		Summary: "Streaming, gym, insurance and other subscriptions found in stored transactions, with monthly costs and price rises",
+		Tag:     "reports",
+		Responses: map[int]interface{}{
+			200: model.SubscriptionReport{},
+			500: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/dashboard",
+		Summary: "Every account with its last five transactions, balances totalled by account type and the last sync, for a home screen",
+		Tag:     "reports",
+		Responses: map[int]interface{}{
+			200: model.DashboardResponse{},
+			401: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "POST",
+		Path:    "/api/v1/bulk",
+		Summary: "Tag transactions, recategorise merchants or archive accounts in bulk, processed as a background job",
+		Tag:     "bulk",
+		Request: model.BulkRequest{},
+		Responses: map[int]interface{}{
+			202: model.Job{},
+			400: errorResponse,
+			401: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:     "POST",
+		Path:       "/api/v1/sync",
+		Summary:    "Scrape every account as a background job with a result per account; a sync already running is returned instead of starting another",
+		Tag:        "bulk",
+		Parameters: []openapi.Parameter{idempotencyKeyParameter},
+		Responses: map[int]interface{}{
+			202: model.Job{},
+			400: errorResponse,
+			401: errorResponse,
+			409: errorResponse,
+			422: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:      "GET",
+		Path:        "/api/v1/events",
+		Summary:     "Stream sync.started, sync.step, transaction.new and sync.completed events as Server-Sent Events",
+		Tag:         "bulk",
+		Responses:   map[int]interface{}{200: ""},
+		ContentType: "text/event-stream",
+		Secured:     true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/jobs/{jobId}",
+		Summary: "Get a background job's progress and per-item results",
+		Tag:     "bulk",
+		Parameters: []openapi.Parameter{
+			{Name: "jobId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Example: "job_1f2e3d4c5b6a7988"}},
+		},
+		Responses: map[int]interface{}{
+			200: model.Job{},
+			401: errorResponse,
+			404: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "DELETE",
+		Path:    "/api/v1/accounts/{accountId}/hooks/{hookId}",
+		Summary: "Remove a refresh hook",
+		Tag:     "hooks",
+		Parameters: append(hookParameters,
+			openapi.Parameter{Name: "hookId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Example: "hook_1f2e3d4c5b6a7988"}},
+			ifMatchParameter,
+		),
+		Responses: map[int]interface{}{
+			204: nil,
+			401: errorResponse,
+			404: errorResponse,
+			412: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:     "POST",
+		Path:       "/api/v1/transfers",
+		Summary:    "Transfer money between your own NAB accounts, or validate it with dryRun and get the fee and processing date NAB would apply",
+		Tag:        "transfers",
+		Parameters: []openapi.Parameter{idempotencyKeyParameter},
+		Request:    model.TransferRequest{},
+		Responses: map[int]interface{}{
+			200: model.TransferResult{},
+			400: errorResponse,
+			401: errorResponse,
+			404: errorResponse,
+			409: errorResponse,
+			422: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+			504: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:     "POST",
+		Path:       "/api/v1/payments/payanyone",
+		Summary:    "Pay Anyone to a saved or new payee, or validate it with dryRun; 202 means NAB is waiting for the SMS code",
+		Tag:        "payments",
+		Parameters: []openapi.Parameter{idempotencyKeyParameter},
+		Request:    model.PayAnyoneRequest{},
+		Responses: map[int]interface{}{
+			200: model.PaymentResult{},
+			202: model.PaymentResult{},
+			400: errorResponse,
+			401: errorResponse,
+			404: errorResponse,
+			409: errorResponse,
+			422: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+			504: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "POST",
+		Path:    "/api/v1/payments/{paymentId}/authorize",
+		Summary: "Authorise a pending payment with the SMS code from NAB",
+		Tag:     "payments",
+		Parameters: []openapi.Parameter{
+			{Name: "paymentId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Example: "pay_8c1f2e3d4b5a6978"}},
+			idempotencyKeyParameter,
+		},
+		Request: model.PaymentAuthRequest{},
+		Responses: map[int]interface{}{
+			200: model.PaymentResult{},
+			400: errorResponse,
+			401: errorResponse,
+			404: errorResponse,
+			409: errorResponse,
+			422: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+			504: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/messages",
+		Summary: "List secure messages from the NAB inbox",
+		Tag:     "messages",
+		Parameters: []openapi.Parameter{
+			{Name: "unread", In: "query", Description: "Only return unread messages", Schema: &openapi.Schema{Type: "boolean"}},
+		},
+		Responses: map[int]interface{}{
+			200: model.MessagesResponse{},
+			400: errorResponse,
+			500: errorResponse,
+			504: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/payees",
+		Summary: "List saved payees from the NAB address book",
+		Tag:     "payees",
+		Responses: map[int]interface{}{
+			200: model.PayeesResponse{},
+			500: errorResponse,
+			504: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/cards",
+		Summary: "List debit and credit cards and whether they are locked",
+		Tag:     "cards",
+		Responses: map[int]interface{}{
+			200: model.CardsResponse{},
+			500: errorResponse,
+			503: errorResponse,
+			504: errorResponse,
+		},
+	})
+	cardParameters := []openapi.Parameter{
+		{Name: "cardId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Example: "card_6d2f8a1b4c9e3d70"}},
+	}
+	builder.Add(openapi.Route{
+		Method:     "POST",
+		Path:       "/api/v1/cards/{cardId}/lock",
+		Summary:    "Apply NAB's temporary block to a card",
+		Tag:        "cards",
+		Parameters: cardParameters,
+		Responses: map[int]interface{}{
+			200: model.Card{},
+			401: errorResponse,
+			404: errorResponse,
+			422: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+			504: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:     "POST",
+		Path:       "/api/v1/cards/{cardId}/unlock",
+		Summary:    "Remove NAB's temporary block from a card",
+		Tag:        "cards",
+		Parameters: cardParameters,
+		Responses: map[int]interface{}{
+			200: model.Card{},
+			401: errorResponse,
+			404: errorResponse,
+			422: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+			504: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/payids",
+		Summary: "List registered PayIDs and the accounts they are linked to",
+		Tag:     "payids",
+		Responses: map[int]interface{}{
+			200: model.PayIDsResponse{},
+			500: errorResponse,
+			503: errorResponse,
+			504: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/payments/scheduled",
+		Summary: "List future-dated and recurring payments, soonest first",
+		Tag:     "payments",
+		Parameters: []openapi.Parameter{
+			{Name: "accountId", In: "query", Description: "Only return payments leaving this account", Schema: &openapi.Schema{Type: "string", Example: "12345678"}},
+		},
+		Responses: map[int]interface{}{
+			200: model.ScheduledPaymentsResponse{},
+			500: errorResponse,
+			503: errorResponse,
+			504: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/locator",
+		Summary: "Find nearby NAB ATMs and branches",
+		Tag:     "locator",
+		Parameters: []openapi.Parameter{
+			{Name: "lat", In: "query", Required: true, Schema: &openapi.Schema{Type: "number", Example: -37.8152}},
+			{Name: "lng", In: "query", Required: true, Schema: &openapi.Schema{Type: "number", Example: 144.9597}},
+			{Name: "radius", In: "query", Description: "Search radius in km (default 5, max 50)", Schema: &openapi.Schema{Type: "number"}},
+			{Name: "type", In: "query", Schema: &openapi.Schema{Type: "string", Enum: []string{model.LocationTypeATM, model.LocationTypeBranch}}},
+			{Name: "limit", In: "query", Description: "Maximum results (default 20)", Schema: &openapi.Schema{Type: "integer"}},
+		},
+		Responses: map[int]interface{}{
+			200: model.LocationsResponse{},
+			400: errorResponse,
+			503: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/transactions",
+		Summary: "Page through stored transactions across accounts, newest first, optionally fetching the accounts first",
+		Tag:     "transactions",
+		Parameters: []openapi.Parameter{
+			{Name: "accountId", In: "query", Description: "Only list these accounts' transactions; comma separated or repeated", Schema: &openapi.Schema{Type: "string", Example: "12345678,87654321"}},
+			{Name: "refresh", In: "query", Description: "Fetch the accounts, several at once, before listing", Schema: &openapi.Schema{Type: "boolean"}},
+			{Name: "limit", In: "query", Description: "Maximum transactions per page (default 50)", Schema: &openapi.Schema{Type: "integer"}},
+			cursorParameter,
+		},
+		AlternateContentTypes: listContentTypes,
+		Responses: map[int]interface{}{
+			200: model.TransactionsResponse{},
+			400: errorResponse,
+			401: errorResponse,
+			404: errorResponse,
+			406: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+			504: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/transactions/search",
+		Summary: "Search stored transactions across accounts by normalised description, newest first",
+		Tag:     "transactions",
+		Parameters: []openapi.Parameter{
+			{Name: "q", In: "query", Required: true, Description: "Words to find; abbreviations like TFR and W/D match their expanded forms, and each word matches the start of a word", Schema: &openapi.Schema{Type: "string", Example: "tfr j smith"}},
+			{Name: "accountId", In: "query", Description: "Only search this account", Schema: &openapi.Schema{Type: "string", Example: "12345678"}},
+			{Name: "amountMin", In: "query", Description: "Only transactions of at least this many dollars, in or out", Schema: &openapi.Schema{Type: "string", Example: "50"}},
+			{Name: "amountMax", In: "query", Description: "Only transactions of at most this many dollars, in or out", Schema: &openapi.Schema{Type: "string", Example: "200"}},
+			{Name: "limit", In: "query", Description: "Maximum results (default 50)", Schema: &openapi.Schema{Type: "integer"}},
+		},
+		AlternateContentTypes: listContentTypes,
+		Responses: map[int]interface{}{
+			200: model.TransactionSearchResponse{},
+			400: errorResponse,
+			406: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v2/accounts",
+		Summary: "List accounts with money in cents, a page at a time",
+		Tag:     "v2",
+		Parameters: []openapi.Parameter{
+			{Name: "limit", In: "query", Description: "Maximum accounts per page (default 100)", Schema: &openapi.Schema{Type: "integer"}},
+			cursorParameter,
+		},
+		Responses: map[int]interface{}{
+			200: model.V2AccountsResponse{},
+			400: errorResponse,
+			401: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+			504: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v2/accounts/{accountId}",
+		Summary: "Get an account with money in cents",
+		Tag:     "v2",
+		Parameters: []openapi.Parameter{
+			{Name: "accountId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Example: "12345678"}},
+		},
+		Responses: map[int]interface{}{
+			200: model.V2AccountResponse{},
+			401: errorResponse,
+			404: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+			504: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v2/transactions",
+		Summary: "Page through stored transactions, newest first, with money in cents and dates as times",
+		Tag:     "v2",
+		Parameters: []openapi.Parameter{
+			{Name: "accountId", In: "query", Description: "Only list these accounts' transactions; comma separated or repeated", Schema: &openapi.Schema{Type: "string", Example: "12345678,87654321"}},
+			{Name: "limit", In: "query", Description: "Maximum transactions per page (default 50)", Schema: &openapi.Schema{Type: "integer"}},
+			cursorParameter,
+		},
+		Responses: map[int]interface{}{
+			200: model.V2TransactionsResponse{},
+			400: errorResponse,
+			500: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/rates",
+		Summary: "List rates advertised on NAB's public product pages",
+		Tag:     "rates",
+		Responses: map[int]interface{}{
+			200: model.AdvertisedRatesResponse{},
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "POST",
+		Path:    "/api/v1/exports/parquet",
+		Summary: "Export stored transactions and balance history as Parquet",
+		Tag:     "exports",
+		Parameters: []openapi.Parameter{
+			{Name: "redact", In: "query", Description: "Merchant redaction mode", Schema: &openapi.Schema{Type: "string", Enum: []string{"none", "hash", "bucket"}}},
+		},
+		Responses: map[int]interface{}{
+			200: model.ExportResult{},
+			400: errorResponse,
+			401: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/exports/ynab",
+		Summary: "Export an account's stored transactions for YNAB, as a CSV file for its file import or with format=json in the body its API takes",
+		Tag:     "exports",
+		Parameters: []openapi.Parameter{
+			{Name: "accountId", In: "query", Required: true, Description: "Account to export", Schema: &openapi.Schema{Type: "string", Example: "12345678"}},
+			{Name: "from", In: "query", Description: "First transaction date to include", Schema: &openapi.Schema{Type: "string", Example: "2024-05-01"}},
+			{Name: "to", In: "query", Description: "Last transaction date to include", Schema: &openapi.Schema{Type: "string", Example: "2024-05-31"}},
+			{Name: "format", In: "query", Description: "csv (default) or json", Schema: &openapi.Schema{Type: "string", Enum: []string{"csv", "json"}}},
+		},
+		Responses: map[int]interface{}{
+			200: model.YNABTransactionsResponse{},
+			400: errorResponse,
+			404: errorResponse,
+			500: errorResponse,
+		},
+	})
+	builder.Add(openapi.Route{
+		Method:  "POST",
+		Path:    "/api/v1/query",
+		Summary: "Run a read-only SQL query over stored data",
+		Tag:     "query",
+		Request: model.QueryRequest{},
+		Responses: map[int]interface{}{
+			200: model.QueryResponse{},
+			400: errorResponse,
+			401: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+		},
+		Secured: true,
+	})
+	basiqError := model.BasiqErrorResponse{}
+	builder.Add(openapi.Route{
+		Method:  "POST",
+		Path:    "/basiq/token",
+		Summary: "Exchange an API key sent as basic auth for a Basiq access token (requires BASIQ_COMPAT_ENABLED)",
+		Tag:     "basiq",
+		Responses: map[int]interface{}{
+			200: model.BasiqToken{},
+			401: basiqError,
+		},
+	})
+	userIDParameter := openapi.Parameter{Name: "userId", In: "path", Required: true, Description: "Any user ID; every user sees the same accounts", Schema: &openapi.Schema{Type: "string", Example: "me"}}
+	builder.Add(openapi.Route{
+		Method:     "GET",
+		Path:       "/basiq/users/{userId}/accounts",
+		Summary:    "Stored accounts in the shape of Basiq's API",
+		Tag:        "basiq",
+		Parameters: []openapi.Parameter{userIDParameter},
+		Responses: map[int]interface{}{
+			200: model.BasiqAccountList{},
+			401: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/basiq/users/{userId}/accounts/{accountId}",
+		Summary: "A stored account in the shape of Basiq's API",
+		Tag:     "basiq",
+		Parameters: []openapi.Parameter{
+			userIDParameter,
+			{Name: "accountId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Example: "12345678"}},
+		},
+		Responses: map[int]interface{}{
+			200: model.BasiqAccount{},
+			401: errorResponse,
+			404: basiqError,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/basiq/users/{userId}/transactions",
+		Summary: "Stored transactions in the shape of Basiq's API, newest first",
+		Tag:     "basiq",
+		Parameters: []openapi.Parameter{
+			userIDParameter,
+			{Name: "filter", In: "query", Description: "Basiq filter on account.id, transaction.postDate or transaction.direction", Schema: &openapi.Schema{Type: "string", Example: "account.id.eq('12345678'),transaction.postDate.bt('2024-05-01','2024-05-31')"}},
+			{Name: "limit", In: "query", Description: "Transactions per page, up to 500 (default 500)", Schema: &openapi.Schema{Type: "integer"}},
+			{Name: "next", In: "query", Description: "Cursor from the previous page's next link", Schema: &openapi.Schema{Type: "string"}},
+		},
+		Responses: map[int]interface{}{
+			200: model.BasiqTransactionList{},
+			400: basiqError,
+			401: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/basiq/users/{userId}/transactions/{transactionId}",
+		Summary: "A stored transaction in the shape of Basiq's API",
+		Tag:     "basiq",
+		Parameters: []openapi.Parameter{
+			userIDParameter,
+			{Name: "transactionId", In: "path", Required: true, Description: "Account ID and transaction ID joined by a dot", Schema: &openapi.Schema{Type: "string", Example: "12345678.txn_20240517_001"}},
+		},
+		Responses: map[int]interface{}{
+			200: model.BasiqTransaction{},
+			401: errorResponse,
+			404: basiqError,
+		},
+		Secured: true,
+	})
+	topParameter := openapi.Parameter{Name: "top", In: "query", Description: "Endpoints to list per token, busiest first (default 5, 0 for all)", Schema: &openapi.Schema{Type: "integer"}}
+	builder.Add(openapi.Route{
+		Method:     "GET",
+		Path:       "/admin/tokens",
+		Summary:    "List API tokens with their usage (requires an admin key)",
+		Tag:        "admin",
+		Parameters: []openapi.Parameter{topParameter},
+		Responses: map[int]interface{}{
+			200: model.TokensResponse{},
+			400: errorResponse,
+			401: errorResponse,
+		},
+		Secured: true,
+	})
+	tokenParameter := openapi.Parameter{Name: "tokenId", In: "path", Required: true, Schema: &openapi.Schema{Type: "string", Example: "tok_9f86d081884c"}}
+	builder.Add(openapi.Route{
+		Method:  "POST",
+		Path:    "/admin/tokens",
+		Summary: "Create an API token; the key is only shown in this response (requires an admin key)",
+		Tag:     "admin",
+		Request: model.TokenRequest{},
+		Responses: map[int]interface{}{
+			201: model.CreatedToken{},
+			400: errorResponse,
+			401: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:     "POST",
+		Path:       "/admin/tokens/{tokenId}/rotate",
+		Summary:    "Replace an API token; the old key keeps working for the grace period (requires an admin key)",
+		Tag:        "admin",
+		Parameters: []openapi.Parameter{tokenParameter},
+		Request:    model.RotateTokenRequest{},
+		Responses: map[int]interface{}{
+			201: model.CreatedToken{},
+			400: errorResponse,
+			401: errorResponse,
+			404: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:     "DELETE",
+		Path:       "/admin/tokens/{tokenId}",
+		Summary:    "Revoke an API token immediately (requires an admin key)",
+		Tag:        "admin",
+		Parameters: []openapi.Parameter{tokenParameter},
+		Responses: map[int]interface{}{
+			204: nil,
+			401: errorResponse,
+			404: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:     "GET",
+		Path:       "/admin/tokens/{tokenId}/usage",
+		Summary:    "Get usage for an API token (requires an admin key)",
+		Tag:        "admin",
+		Parameters: []openapi.Parameter{tokenParameter, topParameter},
+		Responses: map[int]interface{}{
+			200: model.TokenUsage{},
+			400: errorResponse,
+			401: errorResponse,
+			404: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "POST",
+		Path:    "/admin/config/reload",
+		Summary: "Reload selectors, cache TTLs, alert thresholds and notification settings without restarting (requires an admin key)",
+		Tag:     "admin",
+		Responses: map[int]interface{}{
+			200: model.ConfigReloadResponse{},
+			400: errorResponse,
+			401: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "POST",
+		Path:    "/api/v1/admin/session/logout",
+		Summary: "Log out of NAB and discard the persisted browser session (requires an admin key)",
+		Tag:     "admin",
+		Responses: map[int]interface{}{
+			200: model.SessionLogoutResponse{},
+			401: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/admin/debug/screenshot",
+		Summary: "Capture the internet banking page NAB shows the persisted browser session, as a PNG, without logging in (requires an admin key)",
+		Tag:     "admin",
+		Responses: map[int]interface{}{
+			200: "",
+			401: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+			504: errorResponse,
+		},
+		Secured:     true,
+		ContentType: "image/png",
+	})
+	builder.Add(openapi.Route{
+		Method:     "POST",
+		Path:       "/api/v1/admin/sync",
+		Summary:    "Clear the cache and start a sync job so every account is scraped (requires an admin key)",
+		Tag:        "admin",
+		Parameters: []openapi.Parameter{idempotencyKeyParameter},
+		Responses: map[int]interface{}{
+			202: model.Job{},
+			400: errorResponse,
+			401: errorResponse,
+			409: errorResponse,
+			422: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/admin/sync/last",
+		Summary: "Report on the running or most recent sync: duration, accounts found, errors and screenshots (requires an admin key)",
+		Tag:     "admin",
+		Responses: map[int]interface{}{
+			200: model.SyncReport{},
+			401: errorResponse,
+			404: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/admin/cache",
+		Summary: "Cache hits and misses since startup, and each stored and shared entry with its state, age and size (requires an admin key)",
+		Tag:     "admin",
+		Responses: map[int]interface{}{
+			200: model.CacheStatsResponse{},
+			401: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "DELETE",
+		Path:    "/api/v1/admin/cache/entries",
+		Summary: "Evict specific cache entries, by the keys GET /api/v1/admin/cache lists, so the next request for them scrapes NAB (requires an admin key)",
+		Tag:     "admin",
+		Parameters: []openapi.Parameter{
+			{Name: "key", In: "query", Required: true, Description: "Entry to evict, such as accounts or account:123456789; repeat for more", Schema: &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}}},
+		},
+		Responses: map[int]interface{}{
+			200: model.CacheEvictResponse{},
+			400: errorResponse,
+			401: errorResponse,
+			500: errorResponse,
+			503: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "DELETE",
+		Path:    "/api/v1/admin/cache",
+		Summary: "Clear the cache so the next request for each account scrapes NAB (requires an admin key)",
+		Tag:     "admin",
+		Responses: map[int]interface{}{
+			200: model.CacheClearResponse{},
+			401: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "PUT",
+		Path:    "/api/v1/admin/browser",
+		Summary: "Launch browsers headless or visible from the next scrape on (requires an admin key)",
+		Tag:     "admin",
+		Request: model.BrowserModeRequest{},
+		Responses: map[int]interface{}{
+			200: model.BrowserModeResponse{},
+			400: errorResponse,
+			401: errorResponse,
+			503: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "GET",
+		Path:    "/api/v1/admin/audit",
+		Summary: "Query the audit log of API calls, transfers and payments, newest first (requires an admin key)",
+		Tag:     "admin",
+		Parameters: []openapi.Parameter{
+			{Name: "actor", In: "query", Description: "Only entries by this token ID, or anonymous", Schema: &openapi.Schema{Type: "string", Example: "tok_3f9a1c2b7d4e"}},
+			{Name: "kind", In: "query", Description: "api_call, transfer or payment", Schema: &openapi.Schema{Type: "string", Example: "transfer"}},
+			{Name: "result", In: "query", Description: "success or failure", Schema: &openapi.Schema{Type: "string", Example: "failure"}},
+			{Name: "since", In: "query", Description: "Only entries at or after this RFC 3339 time", Schema: &openapi.Schema{Type: "string", Format: "date-time"}},
+			{Name: "until", In: "query", Description: "Only entries before this RFC 3339 time", Schema: &openapi.Schema{Type: "string", Format: "date-time"}},
+			{Name: "limit", In: "query", Description: "Maximum entries to return, 0 for all (default: 100)", Schema: &openapi.Schema{Type: "integer"}},
+		},
+		Responses: map[int]interface{}{
+			200: model.AuditLogResponse{},
+			400: errorResponse,
+			401: errorResponse,
+			500: errorResponse,
+		},
+		Secured: true,
+	})
+	builder.Add(openapi.Route{
+		Method:  "POST",
+		Path:    "/graphql",
+		Summary: "Run a GraphQL query",
+		Tag:     "graphql",
+		Request: graphql.Request{},
+		Responses: map[int]interface{}{
+			200: graphql.Response{},
+			400: errorResponse,
+		},
+	})
+
+	doc := builder.Document()
+	if schema, ok := doc.Components.Schemas["ErrorResponse"]; ok {
+		schema.Properties["error"].Enum = []string{
+			model.ErrorTypeAuthenticationFailed,
+			model.ErrorTypeAccountNotFound,
+			model.ErrorTypeTransactionNotFound,
+			model.ErrorTypeServiceUnavailable,
+			model.ErrorTypeInternalError,
+			model.ErrorTypeInvalidRequest,
+			model.ErrorTypeTransferRejected,
+			model.ErrorTypeTermsAcceptanceRequired,
+			model.ErrorTypeChallengeRequired,
+			model.ErrorTypeHookNotFound,
+			model.ErrorTypeTokenNotFound,
+			model.ErrorTypePayeeNotFound,
+			model.ErrorTypePaymentNotFound,
+			model.ErrorTypePaymentRejected,
+			model.ErrorTypeCardNotFound,
+			model.ErrorTypeCardRejected,
+			model.ErrorTypeAnnotationNotFound,
+			model.ErrorTypeJobNotFound,
+			model.ErrorTypePreconditionFailed,
+			model.ErrorTypeCategoryRuleNotFound,
+			model.ErrorTypeCategoryRuleExists,
+			model.ErrorTypeBudgetNotFound,
+			model.ErrorTypeSyncNotFound,
+		}
+	}
+
+	return doc
+}
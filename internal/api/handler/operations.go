@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// OperationsHandler handles admin HTTP requests that drive syncs and the
+// browser
+type OperationsHandler struct {
+	operations service.OperationsService
+	logger     *log.Logger
+}
+
+// NewOperationsHandler creates a new operations handler
+func NewOperationsHandler(operations service.OperationsService, logger *log.Logger) *OperationsHandler {
+	return &OperationsHandler{
+		operations: operations,
+		logger:     logger,
+	}
+}
+
+// ForceSync handles POST /api/v1/admin/sync
+func (h *OperationsHandler) ForceSync(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("ForceSync: %s %s", r.Method, r.URL.Path)
+
+	job, err := h.operations.ForceSync(r.Context())
+	if err != nil {
+		h.logger.Printf("Failed to force sync: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to start sync", err.Error())
+		return
+	}
+
+	h.logger.Printf("Forced sync job %s is %s", job.ID, job.Status)
+	w.Header().Set("Location", "/api/v1/jobs/"+job.ID)
+	writeJSONResponse(w, h.logger, http.StatusAccepted, job)
+}
+
+// LastSync handles GET /api/v1/admin/sync/last
+func (h *OperationsHandler) LastSync(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("LastSync: %s %s", r.Method, r.URL.Path)
+
+	report, err := h.operations.LastSync()
+	if err != nil {
+		writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeSyncNotFound, "No sync has run since the server started", nil)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, report)
+}
+
+// ClearCache handles DELETE /api/v1/admin/cache
+func (h *OperationsHandler) ClearCache(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("ClearCache: %s %s", r.Method, r.URL.Path)
+
+	response, err := h.operations.ClearCache(r.Context())
+	if err != nil {
+		h.logger.Printf("Failed to clear cache: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to clear cache", err.Error())
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// CacheStats handles GET /api/v1/admin/cache
+func (h *OperationsHandler) CacheStats(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("CacheStats: %s %s", r.Method, r.URL.Path)
+
+	response, err := h.operations.CacheStats(r.Context())
+	if err != nil {
+		h.writeCacheError(w, err, "Failed to read cache statistics")
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// EvictCache handles DELETE /api/v1/admin/cache/entries?key=
+func (h *OperationsHandler) EvictCache(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("EvictCache: %s %s", r.Method, r.URL.Path)
+
+	keys := r.URL.Query()["key"]
+	if len(keys) == 0 {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "At least one key is required", nil)
+		return
+	}
+
+	response, err := h.operations.EvictCache(r.Context(), keys)
+	if err != nil {
+		h.writeCacheError(w, err, "Failed to evict cache entries")
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// writeCacheError writes the response for a failure to inspect or evict
+// the cache
+func (h *OperationsHandler) writeCacheError(w http.ResponseWriter, err error, message string) {
+	h.logger.Printf("%s: %v", message, err)
+	if errors.Is(err, service.ErrCacheUnsupported) {
+		writeErrorResponse(w, h.logger, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "The account service has no cache", nil)
+		return
+	}
+	writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, message, err.Error())
+}
+
+// SetBrowserMode handles PUT /api/v1/admin/browser
+func (h *OperationsHandler) SetBrowserMode(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("SetBrowserMode: %s %s", r.Method, r.URL.Path)
+
+	var req model.BrowserModeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid request body", err.Error())
+		return
+	}
+	if req.Headless == nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "headless is required", nil)
+		return
+	}
+
+	response, err := h.operations.SetHeadless(*req.Headless)
+	if err != nil {
+		if errors.Is(err, service.ErrBrowserModeUnsupported) {
+			writeErrorResponse(w, h.logger, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "The browser mode can't be changed for the configured bank provider or remote browser", nil)
+			return
+		}
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to change browser mode", err.Error())
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// defaultTransactionsLimit is how many transactions a page holds by default
+const defaultTransactionsLimit = 50
+
+// pageParams reads the limit and cursor query parameters, falling back to
+// defaultLimit, where 0 means no limit
+func pageParams(r *http.Request, defaultLimit int) (int, string, error) {
+	query := r.URL.Query()
+	limit := defaultLimit
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return 0, "", fmt.Errorf("limit must be a positive integer")
+		}
+		limit = parsed
+	}
+	return limit, query.Get("cursor"), nil
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// PayeesHandler handles saved payee HTTP requests
+type PayeesHandler struct {
+	payeeService service.PayeeService
+	logger       *log.Logger
+}
+
+// NewPayeesHandler creates a new payees handler
+func NewPayeesHandler(payeeService service.PayeeService, logger *log.Logger) *PayeesHandler {
+	return &PayeesHandler{
+		payeeService: payeeService,
+		logger:       logger,
+	}
+}
+
+// ListPayees handles GET /api/v1/payees
+func (h *PayeesHandler) ListPayees(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("ListPayees: %s %s", r.Method, r.URL.Path)
+
+	payees, err := h.payeeService.GetPayees(r.Context())
+	if err != nil {
+		h.logger.Printf("Failed to get payees: %v", err)
+		if writeBlockedResponse(w, h.logger, err) {
+			return
+		}
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to retrieve payees", errorDetails(err))
+		return
+	}
+
+	response := model.PayeesResponse{
+		Payees:      payees,
+		RetrievedAt: time.Now(),
+		Count:       len(payees),
+	}
+	if response.Payees == nil {
+		response.Payees = []model.Payee{}
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
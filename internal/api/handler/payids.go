@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// PayIDsHandler handles PayID HTTP requests
+type PayIDsHandler struct {
+	payIDService service.PayIDService
+	logger       *log.Logger
+}
+
+// NewPayIDsHandler creates a new PayIDs handler
+func NewPayIDsHandler(payIDService service.PayIDService, logger *log.Logger) *PayIDsHandler {
+	return &PayIDsHandler{
+		payIDService: payIDService,
+		logger:       logger,
+	}
+}
+
+// ListPayIDs handles GET /api/v1/payids
+func (h *PayIDsHandler) ListPayIDs(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("ListPayIDs: %s %s", r.Method, r.URL.Path)
+
+	payIDs, err := h.payIDService.GetPayIDs(r.Context())
+	if err != nil {
+		h.logger.Printf("Failed to get PayIDs: %v", err)
+		if writeBlockedResponse(w, h.logger, err) {
+			return
+		}
+		if errors.Is(err, service.ErrPayIDsUnsupported) {
+			writeErrorResponse(w, h.logger, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "PayIDs are not available", nil)
+			return
+		}
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to retrieve PayIDs", errorDetails(err))
+		return
+	}
+
+	response := model.PayIDsResponse{
+		PayIDs:      payIDs,
+		RetrievedAt: time.Now(),
+		Count:       len(payIDs),
+	}
+	if response.PayIDs == nil {
+		response.PayIDs = []model.PayID{}
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// PaymentsHandler handles Pay Anyone payment HTTP requests
+type PaymentsHandler struct {
+	paymentService service.PaymentService
+	logger         *log.Logger
+}
+
+// NewPaymentsHandler creates a new payments handler
+func NewPaymentsHandler(paymentService service.PaymentService, logger *log.Logger) *PaymentsHandler {
+	return &PaymentsHandler{
+		paymentService: paymentService,
+		logger:         logger,
+	}
+}
+
+// PayAnyone handles POST /api/v1/payments/payanyone. Payments waiting for
+// an SMS code are returned with 202 Accepted.
+func (h *PaymentsHandler) PayAnyone(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("PayAnyone: %s %s", r.Method, r.URL.Path)
+
+	var req model.PayAnyoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.paymentService.PayAnyone(r.Context(), req)
+	if err != nil {
+		h.writePaymentError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if result.Status == model.PaymentStatusPendingAuth {
+		status = http.StatusAccepted
+	}
+	writeJSONResponse(w, h.logger, status, result)
+}
+
+// AuthorizePayment handles POST /api/v1/payments/{paymentId}/authorize
+func (h *PaymentsHandler) AuthorizePayment(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("AuthorizePayment: %s %s", r.Method, r.URL.Path)
+
+	var req model.PaymentAuthRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.paymentService.AuthorizePayment(r.Context(), mux.Vars(r)["paymentId"], req)
+	if err != nil {
+		h.writePaymentError(w, err)
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, result)
+}
+
+// writePaymentError maps payment service errors to responses
+func (h *PaymentsHandler) writePaymentError(w http.ResponseWriter, err error) {
+	if writeBlockedResponse(w, h.logger, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrInvalidPayment):
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid payment", err.Error())
+	case errors.Is(err, service.ErrAccountNotFound):
+		writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeAccountNotFound, "Account not found", nil)
+	case errors.Is(err, service.ErrPayeeNotFound):
+		writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypePayeeNotFound, "Payee not found", nil)
+	case errors.Is(err, service.ErrPaymentNotFound):
+		writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypePaymentNotFound, "No payment is waiting for authorisation with this ID", nil)
+	case errors.Is(err, service.ErrPaymentRejected):
+		writeErrorResponse(w, h.logger, http.StatusUnprocessableEntity, model.ErrorTypePaymentRejected, "Payment rejected by NAB", err.Error())
+	case errors.Is(err, service.ErrPaymentsUnsupported):
+		writeErrorResponse(w, h.logger, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "Payments are not available", nil)
+	case errors.Is(err, service.ErrAuthenticationFailed):
+		writeErrorResponse(w, h.logger, http.StatusUnauthorized, model.ErrorTypeAuthenticationFailed, "Authentication failed", nil)
+	default:
+		h.logger.Printf("Failed to pay: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to pay", errorDetails(err))
+	}
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/config"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// ProbeHandler handles Kubernetes-style liveness and readiness probes
+type ProbeHandler struct {
+	config     *config.Config
+	warmUp     *service.WarmUp
+	store      *store.Store
+	findChrome func() (string, error)
+	logger     *log.Logger
+}
+
+// NewProbeHandler creates a new probe handler. findChrome locates the
+// browser the NAB client launches, and is nil for clients that don't
+// launch one.
+func NewProbeHandler(cfg *config.Config, warmUp *service.WarmUp, store *store.Store, findChrome func() (string, error), logger *log.Logger) *ProbeHandler {
+	return &ProbeHandler{
+		config:     cfg,
+		warmUp:     warmUp,
+		store:      store,
+		findChrome: findChrome,
+		logger:     logger,
+	}
+}
+
+// Livez handles GET /livez. It succeeds whenever the process is serving
+// requests, so only a hung or crashed process gets restarted.
+func (h *ProbeHandler) Livez(w http.ResponseWriter, r *http.Request) {
+	writeJSONResponse(w, h.logger, http.StatusOK, model.ProbeResponse{
+		Status:    model.ProbeStatusOK,
+		Timestamp: time.Now(),
+	})
+}
+
+// Readyz handles GET /readyz. It returns 503 unless the configuration is
+// valid, a browser is installed, the store can be written and the warm-up
+// login has finished, so traffic only reaches instances that can scrape.
+func (h *ProbeHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	checks := []model.ProbeCheck{
+		h.checkConfig(),
+		h.checkBrowser(),
+		h.checkStore(),
+		h.checkWarmUp(),
+	}
+
+	response := model.ProbeResponse{
+		Status:    model.ProbeStatusOK,
+		Checks:    checks,
+		Timestamp: time.Now(),
+	}
+	status := http.StatusOK
+	for _, check := range checks {
+		if check.Status == model.ProbeStatusFailed {
+			response.Status = model.ProbeStatusFailed
+			status = http.StatusServiceUnavailable
+		}
+	}
+
+	writeJSONResponse(w, h.logger, status, response)
+}
+
+// checkConfig validates the required configuration
+func (h *ProbeHandler) checkConfig() model.ProbeCheck {
+	if err := h.config.Validate(); err != nil {
+		return model.ProbeCheck{Name: "config", Status: model.ProbeStatusFailed, Detail: err.Error()}
+	}
+	return model.ProbeCheck{Name: "config", Status: model.ProbeStatusOK}
+}
+
+// checkBrowser looks for the browser executable
+func (h *ProbeHandler) checkBrowser() model.ProbeCheck {
+	if h.findChrome == nil {
+		return model.ProbeCheck{Name: "browser", Status: model.ProbeStatusSkipped, Detail: "NAB client doesn't use a browser"}
+	}
+	path, err := h.findChrome()
+	if err != nil {
+		return model.ProbeCheck{Name: "browser", Status: model.ProbeStatusFailed, Detail: err.Error()}
+	}
+	return model.ProbeCheck{Name: "browser", Status: model.ProbeStatusOK, Detail: path}
+}
+
+// checkStore makes sure scraped data can be saved, when it's persisted
+func (h *ProbeHandler) checkStore() model.ProbeCheck {
+	if h.config.Store.Path == "" {
+		return model.ProbeCheck{Name: "store", Status: model.ProbeStatusSkipped, Detail: "in memory"}
+	}
+	if err := h.store.Writable(); err != nil {
+		return model.ProbeCheck{Name: "store", Status: model.ProbeStatusFailed, Detail: err.Error()}
+	}
+	return model.ProbeCheck{Name: "store", Status: model.ProbeStatusOK, Detail: h.config.Store.Path}
+}
+
+// checkWarmUp fails while the warm-up login is still running. A failed
+// warm-up doesn't fail the probe, as requests log in themselves.
+func (h *ProbeHandler) checkWarmUp() model.ProbeCheck {
+	warmUp := h.warmUp.Status()
+	switch warmUp.State {
+	case model.WarmUpStateDisabled:
+		return model.ProbeCheck{Name: "warmup", Status: model.ProbeStatusSkipped}
+	case model.WarmUpStatePending, model.WarmUpStateRunning:
+		return model.ProbeCheck{Name: "warmup", Status: model.ProbeStatusFailed, Detail: "warming up"}
+	}
+	return model.ProbeCheck{Name: "warmup", Status: model.ProbeStatusOK, Detail: warmUp.State}
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/query"
+)
+
+// QueryHandler handles ad-hoc SQL query requests
+type QueryHandler struct {
+	engine *query.Engine
+	logger *log.Logger
+}
+
+// NewQueryHandler creates a new query handler
+func NewQueryHandler(engine *query.Engine, logger *log.Logger) *QueryHandler {
+	return &QueryHandler{
+		engine: engine,
+		logger: logger,
+	}
+}
+
+// RunQuery handles POST /api/v1/query
+func (h *QueryHandler) RunQuery(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("RunQuery: %s %s", r.Method, r.URL.Path)
+
+	var req model.QueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.SQL == "" {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "A JSON body with an sql field is required", nil)
+		return
+	}
+
+	result, err := h.engine.Run(r.Context(), req.SQL)
+	if err != nil {
+		switch {
+		case errors.Is(err, query.ErrNotReadOnly):
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, err.Error(), nil)
+		case errors.Is(err, query.ErrQueryTimeout):
+			writeErrorResponse(w, h.logger, http.StatusGatewayTimeout, model.ErrorTypeInvalidRequest, "Query exceeded the time limit", nil)
+		case errors.Is(err, query.ErrEngineNotFound):
+			writeErrorResponse(w, h.logger, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "Query engine is not installed", nil)
+		default:
+			h.logger.Printf("Query failed: %v", err)
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Query failed", err.Error())
+		}
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, result)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// RatesHandler handles advertised interest rate HTTP requests
+type RatesHandler struct {
+	store  *store.Store
+	logger *log.Logger
+}
+
+// NewRatesHandler creates a new rates handler
+func NewRatesHandler(store *store.Store, logger *log.Logger) *RatesHandler {
+	return &RatesHandler{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// ListRates handles GET /api/v1/rates
+func (h *RatesHandler) ListRates(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("ListRates: %s %s", r.Method, r.URL.Path)
+
+	rates := h.store.AdvertisedRates()
+	response := model.AdvertisedRatesResponse{
+		Rates:       rates,
+		Count:       len(rates),
+		RetrievedAt: time.Now(),
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
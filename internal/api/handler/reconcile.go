@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/gorilla/mux"
+)
+
+// ReconcileHandler handles balance assertion HTTP requests
+type ReconcileHandler struct {
+	assertions service.BalanceAssertionService
+	logger     *log.Logger
+}
+
+// NewReconcileHandler creates a new reconcile handler
+func NewReconcileHandler(assertions service.BalanceAssertionService, logger *log.Logger) *ReconcileHandler {
+	return &ReconcileHandler{
+		assertions: assertions,
+		logger:     logger,
+	}
+}
+
+// AssertBalance handles POST /api/v1/accounts/{accountId}/balance-assertions
+func (h *ReconcileHandler) AssertBalance(w http.ResponseWriter, r *http.Request) {
+	accountID := mux.Vars(r)["accountId"]
+	h.logger.Printf("AssertBalance: %s %s (account: %s)", r.Method, r.URL.Path, accountID)
+
+	var req model.BalanceAssertionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	response, err := h.assertions.Assert(accountID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidBalanceAssertion):
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid balance assertion", err.Error())
+		case errors.Is(err, service.ErrAccountNotFound):
+			writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeAccountNotFound, "Account not found or not scraped yet", nil)
+		default:
+			h.logger.Printf("Failed to assert balance: %v", err)
+			writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to check balance", err.Error())
+		}
+		return
+	}
+
+	if response.Status == model.BalanceAssertionMismatched {
+		h.logger.Printf("Balance mismatch for account %s: expected %s, scraped %s", accountID, response.ExpectedBalance.Amount, response.ActualBalance.Amount)
+	}
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
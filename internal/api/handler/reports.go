@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// ReportsHandler handles report HTTP requests
+type ReportsHandler struct {
+	reports service.ReportService
+	logger  *log.Logger
+}
+
+// NewReportsHandler creates a new reports handler
+func NewReportsHandler(reports service.ReportService, logger *log.Logger) *ReportsHandler {
+	return &ReportsHandler{
+		reports: reports,
+		logger:  logger,
+	}
+}
+
+// Spending handles GET /api/v1/reports/spending
+func (h *ReportsHandler) Spending(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("Spending: %s %s", r.Method, r.URL.Path)
+
+	query := r.URL.Query()
+	report, err := h.reports.Spending(query.Get("period"), query.Get("accountId"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidReport) {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid report", err.Error())
+			return
+		}
+		h.logger.Printf("Failed to build spending report: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to build spending report", err.Error())
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, report)
+}
+
+// NetWorth handles GET /api/v1/reports/net-worth
+func (h *ReportsHandler) NetWorth(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("NetWorth: %s %s", r.Method, r.URL.Path)
+
+	var days int
+	if value := r.URL.Query().Get("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "days must be a positive integer", nil)
+			return
+		}
+		days = parsed
+	}
+
+	report, err := h.reports.NetWorth(days)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidReport) {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid report", err.Error())
+			return
+		}
+		h.logger.Printf("Failed to build net worth report: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to build net worth report", err.Error())
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, report)
+}
+
+// Fees handles GET /api/v1/reports/fees
+func (h *ReportsHandler) Fees(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("Fees: %s %s", r.Method, r.URL.Path)
+
+	var months int
+	if value := r.URL.Query().Get("months"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "months must be a positive integer", nil)
+			return
+		}
+		months = parsed
+	}
+
+	report, err := h.reports.Fees(months)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidReport) {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid report", err.Error())
+			return
+		}
+		h.logger.Printf("Failed to build fee report: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to build fee report", err.Error())
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, report)
+}
+
+// Subscriptions handles GET /api/v1/reports/subscriptions
+func (h *ReportsHandler) Subscriptions(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("Subscriptions: %s %s", r.Method, r.URL.Path)
+
+	report, err := h.reports.Subscriptions()
+	if err != nil {
+		h.logger.Printf("Failed to build subscription report: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to build subscription report", err.Error())
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, report)
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// writeJSONResponse writes a JSON response
+func writeJSONResponse(w http.ResponseWriter, logger *log.Logger, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		logger.Printf("Failed to encode JSON response: %v", err)
+	}
+}
+
+// writeErrorResponse writes an error response
+func writeErrorResponse(w http.ResponseWriter, logger *log.Logger, statusCode int, errorType, message string, details interface{}) {
+	errorResponse := model.ErrorResponse{
+		Error:     errorType,
+		Message:   message,
+		Details:   details,
+		Timestamp: time.Now(),
+	}
+
+	writeJSONResponse(w, logger, statusCode, errorResponse)
+}
+
+// errorDetails describes err in an error response, along with the error
+// bundle saved if it was a failed scrape
+func errorDetails(err error) interface{} {
+	var scrapeErr *service.ScrapeError
+	if errors.As(err, &scrapeErr) {
+		return model.ScrapeErrorDetails{Error: err.Error(), ErrorBundle: scrapeErr.Bundle}
+	}
+	return err.Error()
+}
+
+// writeBlockedResponse writes a 503 if scraping is blocked until someone
+// accepts NAB's updated terms or solves a security challenge, or a 504 if
+// the request timed out or was cancelled part way, reporting whether it
+// did
+func writeBlockedResponse(w http.ResponseWriter, logger *log.Logger, err error) bool {
+	if errors.Is(err, service.ErrRequestAborted) {
+		writeErrorResponse(w, logger, http.StatusGatewayTimeout, model.ErrorTypeTimeout, "The request timed out before NAB answered", err.Error())
+		return true
+	}
+	var challenge *service.ChallengeError
+	if errors.As(err, &challenge) {
+		writeErrorResponse(w, logger, http.StatusServiceUnavailable, model.ErrorTypeChallengeRequired, "NAB showed a security challenge that needs solving by hand", challenge.Challenge)
+		return true
+	}
+	if !errors.Is(err, service.ErrTermsAcceptanceRequired) && !errors.Is(err, service.ErrScrapingPaused) {
+		return false
+	}
+
+	writeErrorResponse(w, logger, http.StatusServiceUnavailable, model.ErrorTypeTermsAcceptanceRequired, "Log in to NAB internet banking to accept the updated terms and conditions", err.Error())
+	return true
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// ScheduledPaymentsHandler handles upcoming payment HTTP requests
+type ScheduledPaymentsHandler struct {
+	scheduledPaymentService service.ScheduledPaymentService
+	logger                  *log.Logger
+}
+
+// NewScheduledPaymentsHandler creates a new scheduled payments handler
+func NewScheduledPaymentsHandler(scheduledPaymentService service.ScheduledPaymentService, logger *log.Logger) *ScheduledPaymentsHandler {
+	return &ScheduledPaymentsHandler{
+		scheduledPaymentService: scheduledPaymentService,
+		logger:                  logger,
+	}
+}
+
+// ListScheduledPayments handles GET /api/v1/payments/scheduled
+func (h *ScheduledPaymentsHandler) ListScheduledPayments(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("ListScheduledPayments: %s %s", r.Method, r.URL.Path)
+
+	payments, err := h.scheduledPaymentService.GetScheduledPayments(r.Context(), r.URL.Query().Get("accountId"))
+	if err != nil {
+		h.logger.Printf("Failed to get scheduled payments: %v", err)
+		if writeBlockedResponse(w, h.logger, err) {
+			return
+		}
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to retrieve scheduled payments", errorDetails(err))
+		return
+	}
+
+	response := model.ScheduledPaymentsResponse{
+		Payments:    payments,
+		RetrievedAt: time.Now(),
+		Count:       len(payments),
+	}
+	if response.Payments == nil {
+		response.Payments = []model.ScheduledPayment{}
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
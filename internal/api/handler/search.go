@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/search"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// defaultSearchLimit is how many matches a search returns by default
+const defaultSearchLimit = 50
+
+// SearchHandler handles transaction search HTTP requests
+type SearchHandler struct {
+	search service.SearchService
+	logger *log.Logger
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(search service.SearchService, logger *log.Logger) *SearchHandler {
+	return &SearchHandler{
+		search: search,
+		logger: logger,
+	}
+}
+
+// SearchTransactions handles GET /api/v1/transactions/search
+func (h *SearchHandler) SearchTransactions(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("SearchTransactions: %s %s", r.Method, r.URL.Path)
+
+	query := r.URL.Query()
+	q := strings.TrimSpace(query.Get("q"))
+
+	limit := defaultSearchLimit
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "limit must be a positive integer", nil)
+			return
+		}
+		limit = parsed
+	}
+
+	matches, err := h.search.Search(service.TransactionSearch{
+		Query:     q,
+		AccountID: query.Get("accountId"),
+		AmountMin: query.Get("amountMin"),
+		AmountMax: query.Get("amountMax"),
+		Limit:     limit,
+	})
+	if errors.Is(err, service.ErrInvalidSearch) {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid search", err.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Printf("Failed to search transactions: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to search transactions", err.Error())
+		return
+	}
+
+	response := model.TransactionSearchResponse{
+		Query:        q,
+		Normalized:   search.Normalize(q),
+		Transactions: matches,
+		RetrievedAt:  time.Now(),
+		Count:        len(matches),
+	}
+
+	header, rows := transactionRows(matches)
+	writeListResponse(w, r, h.logger, http.StatusOK, listBody{
+		name:   "transactionSearchResponse",
+		data:   response,
+		header: header,
+		rows:   rows,
+	})
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// SessionHandler handles NAB session HTTP requests
+type SessionHandler struct {
+	sessions service.SessionService
+	logger   *log.Logger
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(sessions service.SessionService, logger *log.Logger) *SessionHandler {
+	return &SessionHandler{
+		sessions: sessions,
+		logger:   logger,
+	}
+}
+
+// Logout handles POST /api/v1/admin/session/logout
+func (h *SessionHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("Logout: %s %s", r.Method, r.URL.Path)
+
+	response, err := h.sessions.Logout(r.Context())
+	if err != nil {
+		if errors.Is(err, service.ErrLogoutUnsupported) {
+			writeErrorResponse(w, h.logger, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "Logout is not available for the configured bank provider", nil)
+			return
+		}
+		h.logger.Printf("Failed to log out: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to clear the NAB session", err.Error())
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// Screenshot handles GET /api/v1/admin/debug/screenshot
+func (h *SessionHandler) Screenshot(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("Screenshot: %s %s", r.Method, r.URL.Path)
+
+	png, err := h.sessions.Screenshot(r.Context())
+	if err != nil {
+		h.logger.Printf("Failed to capture screenshot: %v", err)
+		if writeBlockedResponse(w, h.logger, err) {
+			return
+		}
+		if errors.Is(err, service.ErrScreenshotUnsupported) {
+			writeErrorResponse(w, h.logger, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "Screenshots are not available for the configured bank provider", nil)
+			return
+		}
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to capture screenshot", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(png); err != nil {
+		h.logger.Printf("Failed to write screenshot: %v", err)
+	}
+}
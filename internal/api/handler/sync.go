@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// SyncHandler handles on-demand sync HTTP requests
+type SyncHandler struct {
+	syncs  service.SyncService
+	logger *log.Logger
+}
+
+// NewSyncHandler creates a new sync handler
+func NewSyncHandler(syncs service.SyncService, logger *log.Logger) *SyncHandler {
+	return &SyncHandler{
+		syncs:  syncs,
+		logger: logger,
+	}
+}
+
+// StartSync handles POST /api/v1/sync, starting a background sync of every
+// account and pointing at its job
+func (h *SyncHandler) StartSync(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("StartSync: %s %s", r.Method, r.URL.Path)
+
+	job, err := h.syncs.Start()
+	if err != nil {
+		h.logger.Printf("Failed to start sync job: %v", err)
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to start sync", err.Error())
+		return
+	}
+
+	h.logger.Printf("Sync job %s is %s", job.ID, job.Status)
+	w.Header().Set("Location", "/api/v1/jobs/"+job.ID)
+	writeJSONResponse(w, h.logger, http.StatusAccepted, job)
+}
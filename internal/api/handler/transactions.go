@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/pagination"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// TransactionsHandler handles stored transaction listing HTTP requests
+type TransactionsHandler struct {
+	transactionsService service.TransactionsService
+	store               *store.Store
+	logger              *log.Logger
+}
+
+// NewTransactionsHandler creates a new transactions handler. The
+// transactions service brings stored transactions up to date when a
+// listing asks for a refresh.
+func NewTransactionsHandler(transactionsService service.TransactionsService, store *store.Store, logger *log.Logger) *TransactionsHandler {
+	return &TransactionsHandler{
+		transactionsService: transactionsService,
+		store:               store,
+		logger:              logger,
+	}
+}
+
+// ListTransactions handles GET /api/v1/transactions
+func (h *TransactionsHandler) ListTransactions(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("ListTransactions: %s %s", r.Method, r.URL.Path)
+
+	limit, cursor, err := pageParams(r, defaultTransactionsLimit)
+	if err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, err.Error(), nil)
+		return
+	}
+	accountIDs := accountIDsParam(r)
+
+	if value := r.URL.Query().Get("refresh"); value != "" {
+		refresh, err := strconv.ParseBool(value)
+		if err != nil {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid refresh parameter", err.Error())
+			return
+		}
+		// Later pages are read from the store as the first page left it
+		if refresh && cursor == "" {
+			if err := h.transactionsService.RefreshTransactions(r.Context(), accountIDs); err != nil {
+				h.writeRefreshError(w, err)
+				return
+			}
+		}
+	}
+
+	page, next, err := pageStoredTransactions(h.store, accountIDs, cursor, limit)
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid cursor", err.Error())
+		return
+	}
+
+	response := model.TransactionsResponse{
+		Transactions: page,
+		RetrievedAt:  time.Now(),
+		Count:        len(page),
+		NextCursor:   next,
+	}
+
+	header, rows := transactionRows(page)
+	writeListResponse(w, r, h.logger, http.StatusOK, listBody{
+		name:       "transactionsResponse",
+		data:       response,
+		header:     header,
+		rows:       rows,
+		nextCursor: next,
+	})
+}
+
+// writeRefreshError writes the response for a failure to refresh
+// transactions
+func (h *TransactionsHandler) writeRefreshError(w http.ResponseWriter, err error) {
+	h.logger.Printf("Failed to refresh transactions: %v", err)
+	if writeBlockedResponse(w, h.logger, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrAccountNotFound):
+		writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeAccountNotFound, "Account not found", err.Error())
+	case errors.Is(err, service.ErrServiceUnavailable):
+		writeErrorResponse(w, h.logger, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "Service temporarily unavailable", errorDetails(err))
+	case errors.Is(err, service.ErrAuthenticationFailed):
+		writeErrorResponse(w, h.logger, http.StatusUnauthorized, model.ErrorTypeAuthenticationFailed, "Authentication failed", nil)
+	default:
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to refresh transactions", errorDetails(err))
+	}
+}
+
+// accountIDsParam returns the accounts listed in accountId, which may be
+// comma separated or repeated
+func accountIDsParam(r *http.Request) []string {
+	var accountIDs []string
+	for _, value := range r.URL.Query()["accountId"] {
+		for _, accountID := range strings.Split(value, ",") {
+			if accountID = strings.TrimSpace(accountID); accountID != "" {
+				accountIDs = append(accountIDs, accountID)
+			}
+		}
+	}
+	return accountIDs
+}
+
+// pageStoredTransactions returns a page of the stored transactions, of the
+// given accounts or all of them, newest first
+func pageStoredTransactions(store *store.Store, accountIDs []string, cursor string, limit int) ([]model.TransactionMatch, string, error) {
+	wanted := make(map[string]bool, len(accountIDs))
+	for _, accountID := range accountIDs {
+		wanted[accountID] = true
+	}
+
+	generation := store.Generation()
+	transactions := []model.TransactionMatch{}
+	for id, stored := range store.AllTransactions() {
+		if len(wanted) > 0 && !wanted[id] {
+			continue
+		}
+		for _, transaction := range stored {
+			transactions = append(transactions, model.TransactionMatch{AccountID: id, Transaction: transaction})
+		}
+	}
+
+	// Newest first across accounts, with a stable order within a day so
+	// cursors resume in the right place
+	sort.Slice(transactions, func(i, j int) bool {
+		if transactions[i].Date != transactions[j].Date {
+			return transactions[i].Date > transactions[j].Date
+		}
+		if transactions[i].AccountID != transactions[j].AccountID {
+			return transactions[i].AccountID < transactions[j].AccountID
+		}
+		return transactions[i].ID < transactions[j].ID
+	})
+
+	return pagination.Page(transactions, cursor, limit, generation, func(match model.TransactionMatch) pagination.Key {
+		return pagination.Key{AccountID: match.AccountID, TransactionID: match.ID}
+	})
+}
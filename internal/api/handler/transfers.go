@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// TransfersHandler handles funds transfer HTTP requests
+type TransfersHandler struct {
+	transferService service.TransferService
+	logger          *log.Logger
+}
+
+// NewTransfersHandler creates a new transfers handler
+func NewTransfersHandler(transferService service.TransferService, logger *log.Logger) *TransfersHandler {
+	return &TransfersHandler{
+		transferService: transferService,
+		logger:          logger,
+	}
+}
+
+// CreateTransfer handles POST /api/v1/transfers
+func (h *TransfersHandler) CreateTransfer(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("CreateTransfer: %s %s", r.Method, r.URL.Path)
+
+	var req model.TransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.transferService.Transfer(r.Context(), req)
+	if err != nil {
+		if writeBlockedResponse(w, h.logger, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrInvalidTransfer):
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid transfer", err.Error())
+		case errors.Is(err, service.ErrAccountNotFound):
+			writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeAccountNotFound, "Account not found", nil)
+		case errors.Is(err, service.ErrTransferRejected):
+			writeErrorResponse(w, h.logger, http.StatusUnprocessableEntity, model.ErrorTypeTransferRejected, "Transfer rejected by NAB", err.Error())
+		case errors.Is(err, service.ErrTransfersUnsupported):
+			writeErrorResponse(w, h.logger, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "Transfers are not available", nil)
+		case errors.Is(err, service.ErrAuthenticationFailed):
+			writeErrorResponse(w, h.logger, http.StatusUnauthorized, model.ErrorTypeAuthenticationFailed, "Authentication failed", nil)
+		default:
+			h.logger.Printf("Failed to transfer: %v", err)
+			writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to transfer", errorDetails(err))
+		}
+		return
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, result)
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/apiv2"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/pagination"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/benrowe/nab-bank-api/internal/store"
+	"github.com/gorilla/mux"
+)
+
+// defaultV2AccountsLimit is how many accounts a v2 page holds by default.
+// Every v2 list is paged.
+const defaultV2AccountsLimit = 100
+
+// V2Handler handles API v2 account and transaction requests. It serves
+// the same data as v1 in the v2 models.
+type V2Handler struct {
+	accountService service.AccountService
+	store          *store.Store
+	logger         *log.Logger
+}
+
+// NewV2Handler creates a new API v2 handler. The store's sync generation
+// is recorded in pagination cursors.
+func NewV2Handler(accountService service.AccountService, store *store.Store, logger *log.Logger) *V2Handler {
+	return &V2Handler{
+		accountService: accountService,
+		store:          store,
+		logger:         logger,
+	}
+}
+
+// ListAccounts handles GET /api/v2/accounts
+func (h *V2Handler) ListAccounts(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("V2ListAccounts: %s %s", r.Method, r.URL.Path)
+
+	limit, cursor, err := pageParams(r, defaultV2AccountsLimit)
+	if err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, err.Error(), nil)
+		return
+	}
+
+	accounts, err := h.accountService.GetAllAccounts(r.Context())
+	if err != nil {
+		h.writeAccountsError(w, err)
+		return
+	}
+
+	page, next, err := pagination.Page(accounts, cursor, limit, h.store.Generation(), func(account model.Account) pagination.Key {
+		return pagination.Key{AccountID: account.ID}
+	})
+	if err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid cursor", err.Error())
+		return
+	}
+
+	response := model.V2AccountsResponse{
+		Accounts:    make([]model.V2Account, 0, len(page)),
+		Page:        v2Page(r, limit, next),
+		RetrievedAt: time.Now(),
+	}
+	for _, account := range page {
+		converted, err := apiv2.Account(account)
+		if err != nil {
+			h.logger.Printf("Failed to convert account for v2: %v", err)
+			writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to retrieve accounts", err.Error())
+			return
+		}
+		response.Accounts = append(response.Accounts, converted)
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// GetAccount handles GET /api/v2/accounts/{accountId}. Unlike v1 it
+// returns just the account; its transactions are listed by
+// /api/v2/transactions.
+func (h *V2Handler) GetAccount(w http.ResponseWriter, r *http.Request) {
+	accountID := mux.Vars(r)["accountId"]
+	h.logger.Printf("V2GetAccount: %s %s (ID: %s)", r.Method, r.URL.Path, accountID)
+
+	accounts, err := h.accountService.GetAllAccounts(r.Context())
+	if err != nil {
+		h.writeAccountsError(w, err)
+		return
+	}
+
+	for _, account := range accounts {
+		if account.ID != accountID {
+			continue
+		}
+		converted, err := apiv2.Account(account)
+		if err != nil {
+			h.logger.Printf("Failed to convert account for v2: %v", err)
+			writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to retrieve account", err.Error())
+			return
+		}
+		writeJSONResponse(w, h.logger, http.StatusOK, model.V2AccountResponse{
+			Account:     converted,
+			RetrievedAt: time.Now(),
+		})
+		return
+	}
+
+	writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeAccountNotFound, "Account not found", nil)
+}
+
+// ListTransactions handles GET /api/v2/transactions
+func (h *V2Handler) ListTransactions(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("V2ListTransactions: %s %s", r.Method, r.URL.Path)
+
+	limit, cursor, err := pageParams(r, defaultTransactionsLimit)
+	if err != nil {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, err.Error(), nil)
+		return
+	}
+
+	page, next, err := pageStoredTransactions(h.store, accountIDsParam(r), cursor, limit)
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Invalid cursor", err.Error())
+		return
+	}
+
+	response := model.V2TransactionsResponse{
+		Transactions: make([]model.V2Transaction, 0, len(page)),
+		Page:         v2Page(r, limit, next),
+		RetrievedAt:  time.Now(),
+	}
+	for _, match := range page {
+		converted, err := apiv2.Transaction(match.AccountID, match.Transaction)
+		if err != nil {
+			h.logger.Printf("Failed to convert transaction for v2: %v", err)
+			writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to retrieve transactions", err.Error())
+			return
+		}
+		response.Transactions = append(response.Transactions, converted)
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
+
+// writeAccountsError writes the response for a failure to get accounts
+func (h *V2Handler) writeAccountsError(w http.ResponseWriter, err error) {
+	h.logger.Printf("Failed to get accounts: %v", err)
+	if writeBlockedResponse(w, h.logger, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrServiceUnavailable):
+		writeErrorResponse(w, h.logger, http.StatusServiceUnavailable, model.ErrorTypeServiceUnavailable, "Service temporarily unavailable", errorDetails(err))
+	case errors.Is(err, service.ErrAuthenticationFailed):
+		writeErrorResponse(w, h.logger, http.StatusUnauthorized, model.ErrorTypeAuthenticationFailed, "Authentication failed", nil)
+	default:
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to retrieve accounts", errorDetails(err))
+	}
+}
+
+// v2Page describes a page, linking to the next one with the request's
+// other parameters kept
+func v2Page(r *http.Request, limit int, nextCursor string) model.V2Page {
+	page := model.V2Page{Limit: limit, NextCursor: nextCursor}
+	if nextCursor != "" {
+		query := r.URL.Query()
+		query.Set("limit", strconv.Itoa(limit))
+		query.Set("cursor", nextCursor)
+		page.Next = r.URL.Path + "?" + query.Encode()
+	}
+	return page
+}
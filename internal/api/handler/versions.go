@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// VersionsHandler lists the API's versions
+type VersionsHandler struct {
+	versions []model.APIVersion
+	logger   *log.Logger
+}
+
+// NewVersionsHandler creates a new versions handler. The current version
+// is the one with APIVersionStatusCurrent.
+func NewVersionsHandler(versions []model.APIVersion, logger *log.Logger) *VersionsHandler {
+	return &VersionsHandler{
+		versions: versions,
+		logger:   logger,
+	}
+}
+
+// ListVersions handles GET /api
+func (h *VersionsHandler) ListVersions(w http.ResponseWriter, r *http.Request) {
+	response := model.APIVersionsResponse{Versions: h.versions}
+	for _, version := range h.versions {
+		if version.Status == model.APIVersionStatusCurrent {
+			response.Current = version.Version
+		}
+	}
+
+	writeJSONResponse(w, h.logger, http.StatusOK, response)
+}
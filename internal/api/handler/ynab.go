@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+	"github.com/benrowe/nab-bank-api/internal/ynab"
+)
+
+// YNABHandler handles YNAB export HTTP requests
+type YNABHandler struct {
+	store    *store.Store
+	accounts map[string]string
+	logger   *log.Logger
+}
+
+// NewYNABHandler creates a new YNAB handler. Accounts maps NAB account IDs
+// to YNAB account IDs, filled into JSON exports; it may be empty.
+func NewYNABHandler(store *store.Store, accounts map[string]string, logger *log.Logger) *YNABHandler {
+	return &YNABHandler{
+		store:    store,
+		accounts: accounts,
+		logger:   logger,
+	}
+}
+
+// ExportYNAB handles GET /api/v1/exports/ynab
+func (h *YNABHandler) ExportYNAB(w http.ResponseWriter, r *http.Request) {
+	h.logger.Printf("ExportYNAB: %s %s", r.Method, r.URL.Path)
+
+	query := r.URL.Query()
+	accountID := query.Get("accountId")
+	if accountID == "" {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "accountId is required", nil)
+		return
+	}
+	from, to := query.Get("from"), query.Get("to")
+	for _, date := range []string{from, to} {
+		if _, err := time.Parse("2006-01-02", date); date != "" && err != nil {
+			writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "from and to must be dates like 2024-05-01", nil)
+			return
+		}
+	}
+	format := query.Get("format")
+	if format != "" && format != "csv" && format != "json" {
+		writeErrorResponse(w, h.logger, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "format must be csv or json", nil)
+		return
+	}
+
+	found := false
+	for _, account := range h.store.Accounts() {
+		found = found || account.ID == accountID
+	}
+	if !found {
+		writeErrorResponse(w, h.logger, http.StatusNotFound, model.ErrorTypeAccountNotFound, "Account not found or not scraped yet", nil)
+		return
+	}
+
+	transactions := ynab.Transactions(accountID, h.accounts[accountID], h.store.Transactions(accountID), from, to)
+	if format == "json" {
+		writeJSONResponse(w, h.logger, http.StatusOK, model.YNABTransactionsResponse{Transactions: transactions})
+		return
+	}
+
+	body, err := ynab.CSV(transactions)
+	if err != nil {
+		writeErrorResponse(w, h.logger, http.StatusInternalServerError, model.ErrorTypeInternalError, "Failed to export transactions", err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="nab-`+accountID+`-ynab.csv"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
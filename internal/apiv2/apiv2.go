@@ -0,0 +1,120 @@
+// Package apiv2 converts accounts and transactions into the API v2
+// models, which type money as cents and dates as times where v1 uses
+// strings
+package apiv2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// dateLayout is the layout of stored transaction dates
+const dateLayout = "2006-01-02"
+
+// Money converts a decimal amount such as "-85.67" to cents without going
+// through floating point
+func Money(money model.Money) (model.V2Money, error) {
+	amount := strings.TrimSpace(money.Amount)
+	negative := strings.HasPrefix(amount, "-")
+	digits := strings.ReplaceAll(strings.TrimLeft(amount, "+-"), ",", "")
+
+	whole, fraction, _ := strings.Cut(digits, ".")
+	if len(fraction) > 2 {
+		return model.V2Money{}, fmt.Errorf("invalid amount %q: more than two decimal places", money.Amount)
+	}
+	fraction = (fraction + "00")[:2]
+
+	cents, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil || whole == "" {
+		return model.V2Money{}, fmt.Errorf("invalid amount %q", money.Amount)
+	}
+	if negative {
+		cents = -cents
+	}
+	return model.V2Money{Cents: cents, Value: FormatCents(cents), Currency: money.CurrencyCode()}, nil
+}
+
+// FormatCents formats cents as a decimal amount such as "-85.67"
+func FormatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// Account converts an account
+func Account(account model.Account) (model.V2Account, error) {
+	balance, err := Money(account.Balance)
+	if err != nil {
+		return model.V2Account{}, fmt.Errorf("account %s balance: %w", account.ID, err)
+	}
+
+	converted := model.V2Account{
+		ID:          account.ID,
+		Name:        account.Name,
+		Type:        account.Type,
+		Balance:     balance,
+		Provider:    account.Provider,
+		Archived:    account.Archived,
+		LastUpdated: account.LastUpdated,
+		Cached:      account.Cached,
+		Stale:       account.Stale,
+	}
+	if account.AvailableBalance != nil && account.AvailableBalance.Amount != "" {
+		available, err := Money(*account.AvailableBalance)
+		if err != nil {
+			return model.V2Account{}, fmt.Errorf("account %s available balance: %w", account.ID, err)
+		}
+		converted.AvailableBalance = &available
+	}
+	if account.AccountNumber != nil {
+		converted.AccountNumber = *account.AccountNumber
+	}
+	if account.BSB != nil {
+		converted.BSB = *account.BSB
+	}
+	return converted, nil
+}
+
+// Transaction converts one of an account's stored transactions. Its date
+// becomes midnight in Sydney, NAB's time zone, on that day.
+func Transaction(accountID string, transaction model.Transaction) (model.V2Transaction, error) {
+	date, err := time.ParseInLocation(dateLayout, transaction.Date, model.BankLocation())
+	if err != nil {
+		return model.V2Transaction{}, fmt.Errorf("transaction %s date: %w", transaction.ID, err)
+	}
+	amount, err := Money(transaction.Amount)
+	if err != nil {
+		return model.V2Transaction{}, fmt.Errorf("transaction %s amount: %w", transaction.ID, err)
+	}
+
+	converted := model.V2Transaction{
+		ID:              transaction.ID,
+		AccountID:       accountID,
+		Date:            date,
+		Description:     transaction.Description,
+		Amount:          amount,
+		Type:            transaction.Type,
+		FeeType:         transaction.FeeType,
+		MerchantDetails: transaction.MerchantDetails,
+	}
+	if transaction.Balance.Amount != "" {
+		balance, err := Money(transaction.Balance)
+		if err != nil {
+			return model.V2Transaction{}, fmt.Errorf("transaction %s balance: %w", transaction.ID, err)
+		}
+		converted.Balance = &balance
+	}
+	if transaction.Category != nil {
+		converted.Category = *transaction.Category
+	}
+	if transaction.Merchant != nil {
+		converted.Merchant = *transaction.Merchant
+	}
+	return converted, nil
+}
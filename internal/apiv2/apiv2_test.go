@@ -0,0 +1,62 @@
+package apiv2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestMoney(t *testing.T) {
+	tests := []struct {
+		amount string
+		cents  int64
+		value  string
+	}{
+		{"1234.56", 123456, "1234.56"},
+		{"-85.6", -8560, "-85.60"},
+		{"1,000", 100000, "1000.00"},
+		{"-0.05", -5, "-0.05"},
+	}
+	for _, tt := range tests {
+		money, err := Money(model.Money{Amount: tt.amount})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.amount, err)
+		}
+		if money.Cents != tt.cents || money.Value != tt.value || money.Currency != model.DefaultCurrency {
+			t.Errorf("%s: got %+v, want %d cents, %s", tt.amount, money, tt.cents, tt.value)
+		}
+	}
+	if money, err := Money(model.Money{Amount: "250.00", Currency: "USD"}); err != nil || money.Currency != "USD" {
+		t.Errorf("expected the currency kept, got %+v, %v", money, err)
+	}
+
+	for _, amount := range []string{"", "abc", "1.234", "-.5x"} {
+		if _, err := Money(model.Money{Amount: amount}); err == nil {
+			t.Errorf("%q: expected an error", amount)
+		}
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	category := "Groceries"
+	converted, err := Transaction("12345678", model.Transaction{
+		ID:       "txn_001",
+		Date:     "2024-05-17",
+		Amount:   model.Money{Amount: "-42.10"},
+		Category: &category,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !converted.Date.Equal(time.Date(2024, 5, 17, 0, 0, 0, 0, model.BankLocation())) || converted.Date.Format(time.RFC3339) != "2024-05-17T00:00:00+10:00" {
+		t.Errorf("date = %s", converted.Date)
+	}
+	if converted.Amount.Cents != -4210 || converted.Balance != nil || converted.Category != category || converted.AccountID != "12345678" {
+		t.Errorf("unexpected transaction %+v", converted)
+	}
+
+	if _, err := Transaction("12345678", model.Transaction{ID: "txn_002", Date: "17/05/2024", Amount: model.Money{Amount: "1.00"}}); err == nil {
+		t.Error("expected an error for an unparseable date")
+	}
+}
@@ -0,0 +1,275 @@
+// Package audit keeps an append-only record of who called the API and
+// every attempt to move money, since the service holds live banking
+// credentials.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// maxMemoryEntries is how many entries a log without a file keeps
+const maxMemoryEntries = 10000
+
+// maxEntrySize is the longest line read back from the log file
+const maxEntrySize = 1 << 20
+
+// AnonymousActor is recorded for requests that present no API key
+const AnonymousActor = "anonymous"
+
+// Log is an append-only audit log. Entries are written to a file as JSON
+// lines, opened for appending only, so existing entries are never
+// rewritten. Without a file the most recent entries are kept in memory.
+// A nil log discards entries.
+type Log struct {
+	path   string
+	now    func() time.Time
+	logger *log.Logger
+
+	mu      sync.Mutex
+	file    *os.File
+	entries []model.AuditEntry
+}
+
+// Filter narrows an audit log query. Empty fields match everything and a
+// zero Limit returns every match.
+type Filter struct {
+	Actor  string
+	Kind   string
+	Result string
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+}
+
+// Open opens the audit log at path, creating it if needed, or an in-memory
+// log for an empty path. Entries that can't be written are reported to
+// the logger.
+func Open(path string, logger *log.Logger) (*Log, error) {
+	l := &Log{path: path, now: time.Now, logger: logger}
+	if path == "" {
+		return l, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if err := terminateTornEntry(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+	l.file = file
+	return l, nil
+}
+
+// terminateTornEntry ends the log file with a newline if it doesn't have
+// one, as when the server stopped partway through writing an entry, so
+// the next entry starts on a line of its own
+func terminateTornEntry(file *os.File) error {
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if info.Size() == 0 {
+		return nil
+	}
+	last := make([]byte, 1)
+	if _, err := file.ReadAt(last, info.Size()-1); err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	if last[0] == '\n' {
+		return nil
+	}
+	if _, err := file.Write([]byte{'\n'}); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Record appends an entry, filling in its ID and time. Money-moving
+// entries are synced to disk before Record returns. Recording never fails
+// the action being audited; write errors are logged instead.
+func (l *Log) Record(entry model.AuditEntry) {
+	if l == nil {
+		return
+	}
+	entry.ID = newID()
+	if entry.Time.IsZero() {
+		entry.Time = l.now()
+	}
+	if entry.Actor == "" {
+		entry.Actor = AnonymousActor
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		l.entries = append(l.entries, entry)
+		if len(l.entries) > maxMemoryEntries {
+			l.entries = l.entries[len(l.entries)-maxMemoryEntries:]
+		}
+		return
+	}
+
+	if err := l.write(entry); err != nil {
+		l.logger.Printf("Failed to record audit entry %s (%s %s by %s): %v", entry.ID, entry.Kind, entry.Action, entry.Actor, err)
+	}
+}
+
+// write appends an entry to the log file. Callers must hold the lock.
+func (l *Log) write(entry model.AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	if entry.Kind != model.AuditKindAPICall {
+		if err := l.file.Sync(); err != nil {
+			return fmt.Errorf("failed to sync audit log: %w", err)
+		}
+	}
+	return nil
+}
+
+// Query returns the entries matching the filter, newest first. The log
+// file is read through a handle of its own, so entries are still recorded
+// while it is read.
+func (l *Log) Query(filter Filter) ([]model.AuditEntry, error) {
+	if l == nil {
+		return []model.AuditEntry{}, nil
+	}
+
+	var matches []model.AuditEntry
+	keep := func(entry model.AuditEntry) {
+		if !filter.matches(entry) {
+			return
+		}
+		matches = append(matches, entry)
+		if filter.Limit > 0 && len(matches) > filter.Limit {
+			matches = matches[1:]
+		}
+	}
+
+	if l.path == "" {
+		l.mu.Lock()
+		for _, entry := range l.entries {
+			keep(entry)
+		}
+		l.mu.Unlock()
+	} else if err := l.scan(keep); err != nil {
+		return nil, err
+	}
+
+	entries := make([]model.AuditEntry, len(matches))
+	for i, entry := range matches {
+		entries[len(matches)-1-i] = entry
+	}
+	return entries, nil
+}
+
+// Close closes the log file
+func (l *Log) Close() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// scan reads every entry in the log file in the order written. A last
+// line without a newline is an entry still being written and is left for
+// the next scan. Lines that can't be read, such as an entry torn by the
+// server stopping partway through writing it, are reported and skipped.
+func (l *Log) scan(fn func(model.AuditEntry)) error {
+	file, err := os.Open(l.path)
+	if err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxEntrySize)
+	scanner.Split(scanEntries)
+	for line := 1; scanner.Scan(); line++ {
+		data, ok := bytes.CutSuffix(scanner.Bytes(), []byte{'\n'})
+		if !ok || len(data) == 0 {
+			continue
+		}
+		var entry model.AuditEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			l.logger.Printf("Skipping unreadable audit entry on line %d of %s: %v", line, l.path, err)
+			continue
+		}
+		fn(entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return nil
+}
+
+// scanEntries splits the log file into lines as bufio.ScanLines does, but
+// keeps each line's newline so a line still being written can be told
+// apart from a complete one
+func scanEntries(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, data[:i+1], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// matches reports whether an entry passes the filter
+func (f Filter) matches(entry model.AuditEntry) bool {
+	return (f.Actor == "" || entry.Actor == f.Actor) &&
+		(f.Kind == "" || entry.Kind == f.Kind) &&
+		(f.Result == "" || entry.Result == f.Result) &&
+		(f.Since.IsZero() || !entry.Time.Before(f.Since)) &&
+		(f.Until.IsZero() || entry.Time.Before(f.Until))
+}
+
+// newID generates an audit entry ID
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return "aud_" + hex.EncodeToString(b)
+}
+
+// actorKey is the context key for the caller making a request
+type actorKey struct{}
+
+// WithActor returns a context recording who is making the request, so
+// actions taken on their behalf are attributed to them
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// Actor returns the caller recorded in the context, or AnonymousActor
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
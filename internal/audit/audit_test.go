@@ -0,0 +1,130 @@
+package audit
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestLogAppendsAndQueries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	auditLog, err := Open(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 5, 17, 9, 0, 0, 0, time.UTC)
+	auditLog.now = func() time.Time { start = start.Add(time.Minute); return start }
+
+	auditLog.Record(model.AuditEntry{Actor: "tok_a", Kind: model.AuditKindAPICall, Action: "GET /api/v1/accounts", Result: model.AuditResultSuccess})
+	auditLog.Record(model.AuditEntry{Actor: "tok_a", Kind: model.AuditKindTransfer, Action: "transfer", Result: model.AuditResultFailure})
+	auditLog.Record(model.AuditEntry{Kind: model.AuditKindAPICall, Action: "GET /api/v1/accounts", Result: model.AuditResultFailure})
+	if err := auditLog.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Entries survive reopening the log
+	auditLog, err = Open(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer auditLog.Close()
+	auditLog.Record(model.AuditEntry{Actor: "tok_b", Kind: model.AuditKindPayment, Action: "pay_anyone", Result: model.AuditResultSuccess})
+
+	all, err := auditLog.Query(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 4 || all[0].Actor != "tok_b" || all[3].Action != "GET /api/v1/accounts" || all[1].Actor != AnonymousActor {
+		t.Fatalf("expected every entry newest first, got %+v", all)
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   int
+	}{
+		{"actor", Filter{Actor: "tok_a"}, 2},
+		{"kind", Filter{Kind: model.AuditKindTransfer}, 1},
+		{"result", Filter{Result: model.AuditResultFailure}, 2},
+		{"since", Filter{Since: time.Date(2024, 5, 17, 9, 2, 0, 0, time.UTC)}, 3},
+		{"until", Filter{Until: time.Date(2024, 5, 17, 9, 2, 0, 0, time.UTC)}, 1},
+		{"limit", Filter{Limit: 2}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := auditLog.Query(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != tt.want {
+				t.Errorf("expected %d entries, got %d", tt.want, len(entries))
+			}
+		})
+	}
+}
+
+func TestQuerySkipsTornEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	var logged bytes.Buffer
+	logger := log.New(&logged, "", 0)
+	auditLog, err := Open(path, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auditLog.Record(model.AuditEntry{Actor: "tok_a", Kind: model.AuditKindTransfer, Action: "transfer", Result: model.AuditResultSuccess})
+	auditLog.Close()
+
+	// The server stopped partway through writing the next entry
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"id":"aud_torn","actor":"tok_`)
+	file.Close()
+
+	auditLog, err = Open(path, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer auditLog.Close()
+	auditLog.Record(model.AuditEntry{Actor: "tok_b", Kind: model.AuditKindPayment, Action: "pay_anyone", Result: model.AuditResultSuccess})
+
+	// Queries don't wait for entries being recorded
+	auditLog.mu.Lock()
+	entries, err := auditLog.Query(Filter{})
+	auditLog.mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Actor != "tok_b" || entries[1].Actor != "tok_a" {
+		t.Errorf("expected the entries either side of the torn one, got %+v", entries)
+	}
+	if !strings.Contains(logged.String(), "line 2") {
+		t.Errorf("expected the torn entry reported, got %q", logged.String())
+	}
+}
+
+func TestQueryLeavesAnEntryBeingWritten(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	auditLog, err := Open(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer auditLog.Close()
+	auditLog.Record(model.AuditEntry{Actor: "tok_a", Kind: model.AuditKindTransfer, Action: "transfer", Result: model.AuditResultSuccess})
+	auditLog.file.WriteString(`{"id":"aud_partial",`)
+
+	entries, err := auditLog.Query(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Actor != "tok_a" {
+		t.Errorf("expected only the complete entry, got %+v", entries)
+	}
+}
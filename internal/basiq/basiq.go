@@ -0,0 +1,229 @@
+// Package basiq presents stored accounts and transactions in the shape of
+// Basiq's aggregation API, so apps built against Basiq can read from this
+// server instead
+package basiq
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// Connection is the single Basiq connection every account belongs to
+const Connection = "nab"
+
+// ErrInvalidFilter is returned for a filter this compatibility layer
+// doesn't understand
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// accountClasses maps account types to Basiq account class types
+var accountClasses = map[string]string{
+	model.AccountTypeSavings:     "savings",
+	model.AccountTypeChecking:    "transaction",
+	model.AccountTypeCredit:      "credit-card",
+	model.AccountTypeLoan:        "mortgage",
+	model.AccountTypeTermDeposit: "term-deposit",
+	model.AccountTypeInvestment:  "investment",
+}
+
+// Converter turns accounts and transactions into Basiq resources. Base is
+// the user's path, such as /basiq/users/me, that links are built from.
+type Converter struct {
+	Base        string
+	Institution string
+}
+
+// Account converts an account
+func (c Converter) Account(account model.Account) model.BasiqAccount {
+	class, ok := accountClasses[account.Type]
+	if !ok {
+		class = "transaction"
+	}
+	available := account.Balance.Amount
+	if account.AvailableBalance != nil {
+		available = account.AvailableBalance.Amount
+	}
+	var number []string
+	for _, part := range []*string{account.BSB, account.AccountNumber} {
+		if part != nil && *part != "" {
+			number = append(number, *part)
+		}
+	}
+
+	converted := model.BasiqAccount{
+		Type:           "account",
+		ID:             account.ID,
+		AccountNo:      strings.Join(number, " "),
+		Name:           account.Name,
+		Currency:       account.Balance.CurrencyCode(),
+		Balance:        account.Balance.Amount,
+		AvailableFunds: available,
+		Status:         "available",
+		Institution:    c.Institution,
+		Connection:     Connection,
+		Class:          model.BasiqAccountClass{Type: class, Product: account.Name},
+		Links: model.BasiqLinks{
+			Self:         c.Base + "/accounts/" + account.ID,
+			Transactions: c.Base + "/transactions?filter=account.id.eq('" + account.ID + "')",
+		},
+	}
+	if account.LastUpdated != nil {
+		converted.LastUpdated = account.LastUpdated.UTC().Format(time.RFC3339)
+	}
+	return converted
+}
+
+// Transaction converts one of an account's transactions. Money out is a
+// debit payment and money in a direct credit.
+func (c Converter) Transaction(accountID string, transaction model.Transaction) model.BasiqTransaction {
+	id := TransactionID(accountID, transaction.ID)
+	converted := model.BasiqTransaction{
+		Type:            "transaction",
+		ID:              id,
+		Status:          "posted",
+		Description:     transaction.Description,
+		Amount:          transaction.Amount.Amount,
+		Account:         accountID,
+		Balance:         transaction.Balance.Amount,
+		Direction:       "credit",
+		Class:           "direct-credit",
+		Institution:     c.Institution,
+		Connection:      Connection,
+		TransactionDate: transaction.Date,
+		PostDate:        transaction.Date,
+		Links: model.BasiqLinks{
+			Self:    c.Base + "/transactions/" + id,
+			Account: c.Base + "/accounts/" + accountID,
+		},
+	}
+	if strings.HasPrefix(transaction.Amount.Amount, "-") {
+		converted.Direction, converted.Class = "debit", "payment"
+	}
+	if transaction.Category != nil && *transaction.Category != "" {
+		converted.SubClass = &model.BasiqSubClass{Title: *transaction.Category}
+	}
+	if transaction.MerchantDetails != nil {
+		converted.Enrich = &model.BasiqEnrich{Merchant: model.BasiqMerchant{BusinessName: transaction.MerchantDetails.Name}}
+		if transaction.MerchantDetails.Domain != nil {
+			converted.Enrich.Merchant.Website = *transaction.MerchantDetails.Domain
+		}
+	}
+	return converted
+}
+
+// TransactionID joins an account ID and a transaction ID into a Basiq
+// transaction ID, as transaction IDs are only unique within an account
+func TransactionID(accountID, transactionID string) string {
+	return accountID + "." + transactionID
+}
+
+// SplitTransactionID reverses TransactionID
+func SplitTransactionID(id string) (string, string, bool) {
+	return strings.Cut(id, ".")
+}
+
+// Filter is the subset of Basiq's transaction filters that is supported
+type Filter struct {
+	AccountID string
+	From      string
+	To        string
+	Direction string
+}
+
+// Matches reports whether one of an account's transactions passes the
+// filter
+func (f Filter) Matches(accountID string, transaction model.Transaction) bool {
+	if f.AccountID != "" && accountID != f.AccountID {
+		return false
+	}
+	if (f.From != "" && transaction.Date < f.From) || (f.To != "" && transaction.Date > f.To) {
+		return false
+	}
+	if f.Direction != "" {
+		debit := strings.HasPrefix(transaction.Amount.Amount, "-")
+		if debit != (f.Direction == "debit") {
+			return false
+		}
+	}
+	return true
+}
+
+// filterClause matches one clause of a filter, such as
+// transaction.postDate.bt('2024-05-01','2024-05-31')
+var filterClause = regexp.MustCompile(`^([a-zA-Z.]+)\.(eq|bt|gt|gteq|lt|lteq)\(('[^']*'(?:\s*,\s*'[^']*')?)\)$`)
+
+// ParseFilter parses a Basiq filter such as
+// "account.id.eq('12345678'),transaction.postDate.gteq('2024-05-01')".
+// Account ID, post or transaction date and direction filters are
+// supported; dates compare by day, so gt and lt behave like gteq and lteq.
+func ParseFilter(spec string) (Filter, error) {
+	var filter Filter
+	for _, clause := range splitClauses(spec) {
+		match := filterClause.FindStringSubmatch(clause)
+		if match == nil {
+			return Filter{}, fmt.Errorf("%w: %q", ErrInvalidFilter, clause)
+		}
+		field, operator := match[1], match[2]
+		var args []string
+		for _, arg := range strings.Split(match[3], ",") {
+			args = append(args, strings.Trim(strings.TrimSpace(arg), "'"))
+		}
+		if (operator == "bt") != (len(args) == 2) {
+			return Filter{}, fmt.Errorf("%w: %q has the wrong number of values", ErrInvalidFilter, clause)
+		}
+
+		switch {
+		case field == "account.id" && operator == "eq":
+			filter.AccountID = args[0]
+		case field == "transaction.direction" && operator == "eq" && (args[0] == "debit" || args[0] == "credit"):
+			filter.Direction = args[0]
+		case field == "transaction.postDate" || field == "transaction.transactionDate":
+			for _, arg := range args {
+				if _, err := time.Parse("2006-01-02", arg); err != nil {
+					return Filter{}, fmt.Errorf("%w: %q is not a date like 2024-05-01", ErrInvalidFilter, arg)
+				}
+			}
+			switch operator {
+			case "bt":
+				filter.From, filter.To = args[0], args[1]
+			case "gt", "gteq":
+				filter.From = args[0]
+			case "lt", "lteq":
+				filter.To = args[0]
+			case "eq":
+				filter.From, filter.To = args[0], args[0]
+			}
+		default:
+			return Filter{}, fmt.Errorf("%w: %s.%s is not supported", ErrInvalidFilter, field, operator)
+		}
+	}
+	return filter, nil
+}
+
+// splitClauses splits a filter on the commas between clauses, leaving
+// those inside brackets alone
+func splitClauses(spec string) []string {
+	var clauses []string
+	depth, start := 0, 0
+	for i, r := range spec {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				clauses = append(clauses, strings.TrimSpace(spec[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if last := strings.TrimSpace(spec[start:]); last != "" {
+		clauses = append(clauses, last)
+	}
+	return clauses
+}
@@ -0,0 +1,63 @@
+package basiq
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestParseFilter(t *testing.T) {
+	filter, err := ParseFilter("account.id.eq('12345678'), transaction.postDate.bt('2024-05-01','2024-05-31'),transaction.direction.eq('debit')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter != (Filter{AccountID: "12345678", From: "2024-05-01", To: "2024-05-31", Direction: "debit"}) {
+		t.Fatalf("unexpected filter %+v", filter)
+	}
+
+	tests := []struct {
+		accountID string
+		date      string
+		amount    string
+		want      bool
+	}{
+		{"12345678", "2024-05-10", "-10.00", true},
+		{"12345678", "2024-05-10", "10.00", false},
+		{"12345678", "2024-06-01", "-10.00", false},
+		{"87654321", "2024-05-10", "-10.00", false},
+	}
+	for _, tt := range tests {
+		transaction := model.Transaction{Date: tt.date, Amount: model.Money{Amount: tt.amount}}
+		if got := filter.Matches(tt.accountID, transaction); got != tt.want {
+			t.Errorf("%s %s %s: got %v, want %v", tt.accountID, tt.date, tt.amount, got, tt.want)
+		}
+	}
+
+	if filter, err := ParseFilter(""); err != nil || filter != (Filter{}) {
+		t.Errorf("expected an empty filter, got %+v, %v", filter, err)
+	}
+	for _, spec := range []string{"account.id.eq(12345678)", "transaction.amount.gt('10')", "transaction.postDate.bt('2024-05-01')", "transaction.postDate.gteq('May')"} {
+		if _, err := ParseFilter(spec); !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("%s: expected an invalid filter error, got %v", spec, err)
+		}
+	}
+}
+
+func TestConverter(t *testing.T) {
+	converter := Converter{Base: "/basiq/users/me", Institution: "nab"}
+	bsb, number := "084001", "****1234"
+	account := converter.Account(model.Account{ID: "12345678", Name: "Low Rate Card", Type: model.AccountTypeCredit, Balance: model.Money{Amount: "-50.00"}, BSB: &bsb, AccountNumber: &number})
+	if account.Class.Type != "credit-card" || account.AccountNo != "084001 ****1234" || account.AvailableFunds != "-50.00" || account.Links.Self != "/basiq/users/me/accounts/12345678" {
+		t.Errorf("unexpected account %+v", account)
+	}
+
+	category, domain := "Groceries", "coles.com.au"
+	transaction := converter.Transaction("12345678", model.Transaction{ID: "txn_1", Date: "2024-05-17", Amount: model.Money{Amount: "-85.67"}, Category: &category, MerchantDetails: &model.MerchantDetails{Name: "Coles", Domain: &domain}})
+	if transaction.ID != "12345678.txn_1" || transaction.Direction != "debit" || transaction.SubClass.Title != "Groceries" || transaction.Enrich.Merchant.Website != "coles.com.au" {
+		t.Errorf("unexpected transaction %+v", transaction)
+	}
+	if accountID, transactionID, ok := SplitTransactionID(transaction.ID); !ok || accountID != "12345678" || transactionID != "txn_1" {
+		t.Errorf("expected the ID to split back, got %s %s", accountID, transactionID)
+	}
+}
@@ -0,0 +1,62 @@
+package browser
+
+import (
+	"context"
+	"strings"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/chromedp/chromedp"
+)
+
+// accountTilesScript returns the text of each account tile on the dashboard
+const accountTilesScript = `Array.from(document.querySelectorAll(
+	'[data-account-id], [class*="account-tile"], [class*="accountTile"], [class*="account-card"], [class*="accountCard"]'
+)).map(el => el.innerText || '')`
+
+// addAccountDetails matches each account to the dashboard tile showing its
+// balance, refines the account type from the tile and attaches
+// type-specific details: interest for savings, limits and statement
+// details for credit cards, and maturity details for term deposits
+func (c *NABClient) addAccountDetails(ctx context.Context, accounts []model.Account) {
+	var tiles []string
+	if err := chromedp.Evaluate(accountTilesScript, &tiles).Do(ctx); err != nil {
+		c.logger.Printf("Failed to read account tiles: %v", err)
+		return
+	}
+	c.recorder.record(ctx, recordAccounts, "", tiles)
+
+	c.applyAccountTiles(accounts, tiles)
+}
+
+// applyAccountTiles refines each account from the dashboard tile showing
+// its balance
+func (c *NABClient) applyAccountTiles(accounts []model.Account, tiles []string) {
+	for i := range accounts {
+		tile, ok := tileFor(accounts[i], tiles)
+		if !ok {
+			continue
+		}
+
+		accounts[i].Type = c.extractAccountType(tile)
+		switch accounts[i].Type {
+		case model.AccountTypeSavings:
+			accounts[i].Interest = parseInterestDetails(tile)
+		case model.AccountTypeCredit:
+			accounts[i].Credit = parseCreditDetails(tile, accounts[i].Balance.Amount)
+		case model.AccountTypeTermDeposit:
+			accounts[i].TermDeposit = parseTermDepositDetails(tile)
+		}
+	}
+}
+
+// tileFor finds the tile that shows the account's balance
+func tileFor(account model.Account, tiles []string) (string, bool) {
+	balance := strings.TrimPrefix(account.Balance.Amount, "-")
+	for _, tile := range tiles {
+		plain := strings.NewReplacer("$", "", ",", "").Replace(tile)
+		if strings.Contains(plain, balance) {
+			return tile, true
+		}
+	}
+	return "", false
+}
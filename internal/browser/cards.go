@@ -0,0 +1,282 @@
+package browser
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/chromedp/chromedp"
+)
+
+// cardsLinkSelectors locate the cards section
+var cardsLinkSelectors = []string{
+	`a[href*="cards"]`,
+	`a[href*="card-services"]`,
+	`a[title*="Cards" i]`,
+	`[role="menuitem"][href*="card"]`,
+}
+
+// cardBlockSelectors toggle NAB's temporary card block
+var cardBlockSelectors = []string{
+	`[role="switch"][aria-label*="block" i]`,
+	`[role="switch"][aria-label*="lock" i]`,
+	`input[type="checkbox"][name*="block" i]`,
+	`button[class*="block"]`,
+	`button[class*="lock"]`,
+}
+
+// cardBlockConfirmSelectors confirm the block or unblock when NAB asks
+var cardBlockConfirmSelectors = []string{
+	`[role="dialog"] button[class*="confirm"]`,
+	`[role="dialog"] button[class*="primary"]`,
+	`button[id*="confirm"]`,
+}
+
+var (
+	cardLast4Pattern     = regexp.MustCompile(`(?i)(?:(?:[*•x.]\s*){2,}|ending(?:\s+in)?\s*)(\d{4})\b`)
+	cardExpiryPattern    = regexp.MustCompile(`\b(0[1-9]|1[0-2])\s*/\s*(\d{2})\b`)
+	cardAccountPattern   = regexp.MustCompile(`\b\d{8,10}\b`)
+	cardLockedPattern    = regexp.MustCompile(`(?i)\b(?:temporarily\s+blocked|blocked|locked)\b`)
+	cardCancelledPattern = regexp.MustCompile(`(?i)\b(?:cancell?ed|closed|reported\s+(?:lost|stolen))\b`)
+
+	// cardCreditPattern picks out credit cards, either by name or by the
+	// credit limit shown alongside them
+	cardCreditPattern = regexp.MustCompile(`(?i)\bcredit\b|low\s+rate|rewards|qantas`)
+)
+
+// cardRow is a card read from the page
+type cardRow struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Number  string `json:"number"`
+	Holder  string `json:"holder"`
+	Account string `json:"account"`
+	Expiry  string `json:"expiry"`
+	Status  string `json:"status"`
+	Text    string `json:"text"`
+}
+
+// extractCardsScript reads cards from the cards section. Fields are taken
+// from labelled elements where NAB provides them, otherwise from the row
+// text.
+const extractCardsScript = `(() => {
+	const rows = Array.from(document.querySelectorAll(
+		'[class*="card-list"] li, [class*="cardList"] li, [class*="card-tile"], [data-card-id]'));
+	const text = (row, selector) => {
+		const el = row.querySelector(selector);
+		return el ? (el.innerText || '').trim() : '';
+	};
+	return rows.map(row => ({
+		id: row.getAttribute('data-card-id') || '',
+		name: text(row, '[class*="card-name"], [class*="product"], h2, h3'),
+		number: text(row, '[class*="number"]'),
+		holder: text(row, '[class*="holder"], [class*="embossed"]'),
+		account: row.getAttribute('data-account-id') || text(row, '[class*="account"]'),
+		expiry: text(row, '[class*="expiry"], [class*="expires"]'),
+		status: text(row, '[class*="status"]'),
+		text: (row.innerText || '').trim(),
+	})).filter(row => row.text !== '');
+})()`
+
+// openCardScript clicks the card with the given last four digits,
+// reporting whether it was found
+const openCardScript = `((last4) => {
+	const rows = Array.from(document.querySelectorAll(
+		'[class*="card-list"] li, [class*="cardList"] li, [class*="card-tile"], [data-card-id]'));
+	const row = rows.find(row => (row.innerText || '').includes(last4));
+	if (!row) return false;
+	(row.querySelector('a, button') || row).click();
+	return true;
+})(%q)`
+
+// GetCards scrapes the debit and credit cards from NAB's cards section
+func (c *NABClient) GetCards(ctx context.Context) ([]model.Card, error) {
+	c.logger.Println("Scraping NAB cards...")
+
+	var cards []model.Card
+	err := c.runLoggedIn(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		cards, err = c.readCards(ctx)
+		return err
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrape NAB cards: %w", err)
+	}
+
+	c.logger.Printf("Successfully scraped %d cards", len(cards))
+	return cards, nil
+}
+
+// SetCardLocked applies or removes NAB's temporary block on a card and
+// returns the card as NAB shows it afterwards
+func (c *NABClient) SetCardLocked(ctx context.Context, card model.Card, locked bool) (*model.Card, error) {
+	action := "Unlocking"
+	if locked {
+		action = "Locking"
+	}
+	c.logger.Printf("%s card ending %s...", action, card.Last4)
+
+	var updated *model.Card
+	err := c.runLoggedIn(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		if err := c.clickFirstVisible(ctx, cardsLinkSelectors); err != nil {
+			c.takeScreenshot(ctx, "cards_not_found")
+			return fmt.Errorf("could not find cards: %w", err)
+		}
+		chromedp.Sleep(2 * time.Second).Do(ctx)
+
+		var found bool
+		if err := chromedp.Evaluate(fmt.Sprintf(openCardScript, card.Last4), &found).Do(ctx); err != nil {
+			return fmt.Errorf("failed to open card: %w", err)
+		}
+		if !found {
+			c.takeScreenshot(ctx, "card_not_found")
+			return fmt.Errorf("%w: card ending %s is not listed", service.ErrCardNotFound, card.Last4)
+		}
+		chromedp.Sleep(2 * time.Second).Do(ctx)
+
+		if err := c.clickFirstVisible(ctx, cardBlockSelectors); err != nil {
+			c.takeScreenshot(ctx, "card_block_not_found")
+			return fmt.Errorf("could not find temporary block: %w", err)
+		}
+		chromedp.Sleep(time.Second).Do(ctx)
+
+		// NAB doesn't always ask for confirmation
+		if err := c.clickFirstVisible(ctx, cardBlockConfirmSelectors); err == nil {
+			chromedp.Sleep(2 * time.Second).Do(ctx)
+		}
+		if err := c.formError(ctx, service.ErrCardRejected); err != nil {
+			return err
+		}
+
+		cards, err := c.readCards(ctx)
+		if err != nil {
+			return err
+		}
+		for i := range cards {
+			if cards[i].Last4 == card.Last4 {
+				updated = &cards[i]
+				break
+			}
+		}
+		if updated == nil || (updated.Status == model.CardStatusLocked) != locked {
+			c.takeScreenshot(ctx, "card_block_unchanged")
+			return fmt.Errorf("%w: card status did not change", service.ErrCardRejected)
+		}
+		return nil
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update NAB card: %w", err)
+	}
+
+	// Keep the ID the caller looked the card up by
+	updated.ID = card.ID
+	c.logger.Printf("Card ending %s is now %s", updated.Last4, updated.Status)
+	return updated, nil
+}
+
+// readCards opens the cards section and parses the cards listed
+func (c *NABClient) readCards(ctx context.Context) ([]model.Card, error) {
+	if err := c.clickFirstVisible(ctx, cardsLinkSelectors); err != nil {
+		c.takeScreenshot(ctx, "cards_not_found")
+		return nil, fmt.Errorf("could not find cards: %w", err)
+	}
+	chromedp.Sleep(2 * time.Second).Do(ctx)
+
+	var rows []cardRow
+	if err := chromedp.Evaluate(extractCardsScript, &rows).Do(ctx); err != nil {
+		return nil, fmt.Errorf("failed to read cards: %w", err)
+	}
+
+	c.recorder.record(ctx, recordCards, "", rows)
+
+	return parseCards(rows, c.logger), nil
+}
+
+// parseCards builds cards from the scraped rows, skipping rows without a
+// card number
+func parseCards(rows []cardRow, logger *log.Logger) []model.Card {
+	var cards []model.Card
+	for _, row := range rows {
+		card, ok := parseCard(row)
+		if !ok {
+			logger.Printf("Skipping card row without a card number: %q", row.Text)
+			continue
+		}
+		cards = append(cards, card)
+	}
+	return cards
+}
+
+// parseCard builds a card from a scraped row, falling back to the row text
+// for fields the page doesn't label. Rows without the last four digits of a
+// card number are skipped.
+func parseCard(row cardRow) (model.Card, bool) {
+	match := cardLast4Pattern.FindStringSubmatch(row.Number)
+	if match == nil {
+		match = cardLast4Pattern.FindStringSubmatch(row.Text)
+	}
+	if match == nil {
+		return model.Card{}, false
+	}
+
+	name := strings.TrimSpace(row.Name)
+	if name == "" {
+		name = strings.TrimSpace(strings.SplitN(row.Text, "\n", 2)[0])
+	}
+
+	card := model.Card{
+		ID:     row.ID,
+		Name:   name,
+		Type:   model.CardTypeDebit,
+		Last4:  match[1],
+		Status: model.CardStatusActive,
+	}
+	if !strings.Contains(strings.ToLower(name), "debit") && cardCreditPattern.MatchString(name+"\n"+row.Text) {
+		card.Type = model.CardTypeCredit
+	}
+
+	if holder := strings.TrimSpace(row.Holder); holder != "" {
+		card.Cardholder = &holder
+	}
+	expiry := cardExpiryPattern.FindStringSubmatch(row.Expiry)
+	if expiry == nil {
+		expiry = cardExpiryPattern.FindStringSubmatch(row.Text)
+	}
+	if expiry != nil {
+		value := expiry[1] + "/" + expiry[2]
+		card.Expiry = &value
+	}
+	if account := cardAccountPattern.FindString(row.Account); account != "" {
+		card.AccountID = &account
+	}
+
+	status := row.Status
+	if status == "" {
+		status = row.Text
+	}
+	switch {
+	case cardCancelledPattern.MatchString(status):
+		card.Status = model.CardStatusCancelled
+	case cardLockedPattern.MatchString(status):
+		card.Status = model.CardStatusLocked
+	}
+
+	if card.ID == "" {
+		card.ID = cardID(name, card.Last4)
+	}
+
+	return card, true
+}
+
+// cardID derives a stable ID for cards that don't expose one
+func cardID(name, last4 string) string {
+	sum := sha256.Sum256([]byte(name + "\x00" + last4))
+	return "card_" + hex.EncodeToString(sum[:])[:16]
+}
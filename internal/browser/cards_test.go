@@ -0,0 +1,44 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestParseCard(t *testing.T) {
+	card, ok := parseCard(cardRow{
+		Name:    "NAB Visa Debit",
+		Number:  "•••• •••• •••• 4821",
+		Holder:  "J SMITH",
+		Account: "NAB Classic Banking 87654321",
+		Expiry:  "Expires 09/27",
+		Text:    "NAB Visa Debit\n•••• •••• •••• 4821\nJ SMITH\nExpires 09/27",
+	})
+	if !ok {
+		t.Fatal("expected card")
+	}
+	if card.Last4 != "4821" || card.Type != model.CardTypeDebit || card.Status != model.CardStatusActive {
+		t.Errorf("unexpected card %+v", card)
+	}
+	if card.Expiry == nil || *card.Expiry != "09/27" {
+		t.Errorf("unexpected expiry %v", card.Expiry)
+	}
+	if card.AccountID == nil || *card.AccountID != "87654321" || card.ID != cardID("NAB Visa Debit", "4821") {
+		t.Errorf("unexpected card %+v", card)
+	}
+
+	locked, ok := parseCard(cardRow{Text: "NAB Low Rate Card\nCard ending in 1234\nTemporarily blocked"})
+	if !ok || locked.Type != model.CardTypeCredit || locked.Last4 != "1234" || locked.Status != model.CardStatusLocked {
+		t.Errorf("unexpected card from text %+v", locked)
+	}
+
+	cancelled, ok := parseCard(cardRow{Text: "NAB Visa Debit\n**** 9999\nReported lost"})
+	if !ok || cancelled.Status != model.CardStatusCancelled {
+		t.Errorf("expected cancelled card, got %+v", cancelled)
+	}
+
+	if _, ok := parseCard(cardRow{Text: "Order a new card"}); ok {
+		t.Error("expected row without a card number to be skipped")
+	}
+}
@@ -0,0 +1,132 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/chromedp/chromedp"
+)
+
+// challengeScript reports the kind of captcha or security check the page
+// is showing, or an empty string if there isn't one
+const challengeScript = `(() => {
+	const visible = el => el.getClientRects().length > 0;
+	if (Array.from(document.querySelectorAll('iframe')).some(frame => visible(frame) && /recaptcha|hcaptcha|turnstile|captcha|arkoselabs|funcaptcha/i.test(frame.src || ''))) return 'captcha';
+	if (Array.from(document.querySelectorAll('.g-recaptcha, .h-captcha, .cf-turnstile, [id*="captcha" i], [class*="captcha" i]')).some(visible)) return 'captcha';
+	const text = document.body ? document.body.innerText : '';
+	if (/verify (that )?you('| a)re (a )?human|unusual (activity|traffic)|security check|are you a robot|press (&|and) hold/i.test(text)) return 'security_check';
+	return '';
+})()`
+
+// challengePollInterval is how often a hand-off checks whether the
+// challenge has been solved
+const challengePollInterval = 2 * time.Second
+
+// ChallengeSolver tries to get a browser session past a captcha or
+// security check, such as by calling a solving service or handing the
+// browser to a person. ctx runs actions in the browser showing the
+// challenge, which is checked again once Solve returns.
+type ChallengeSolver interface {
+	Solve(ctx context.Context, challenge model.Challenge) error
+}
+
+// SetChallengeSolver replaces the solver tried when NAB shows a security
+// challenge. A nil solver fails straight away with the challenge.
+func (c *NABClient) SetChallengeSolver(solver ChallengeSolver) {
+	c.solver = solver
+}
+
+// handOff waits for a person to solve the challenge in the browser, which
+// needs BROWSER_HEADLESS=false or a remote browser they can see
+type handOff struct {
+	wait   time.Duration
+	logger *log.Logger
+}
+
+// newHandOff creates a hand-off solver, or nil if wait is zero
+func newHandOff(wait time.Duration, logger *log.Logger) ChallengeSolver {
+	if wait <= 0 {
+		return nil
+	}
+	return &handOff{wait: wait, logger: logger}
+}
+
+// Solve polls until the challenge is gone or the wait is over
+func (h *handOff) Solve(ctx context.Context, challenge model.Challenge) error {
+	h.logger.Printf("NAB is showing a %s at %s; waiting up to %s for it to be solved in the browser", challenge.Kind, challenge.URL, h.wait)
+	deadline := time.Now().Add(h.wait)
+	for time.Now().Before(deadline) {
+		if err := chromedp.Sleep(challengePollInterval).Do(ctx); err != nil {
+			return err
+		}
+		var kind string
+		if err := chromedp.Evaluate(challengeScript, &kind).Do(ctx); err != nil {
+			return err
+		}
+		if kind == "" {
+			h.logger.Println("Security challenge solved, continuing")
+			return nil
+		}
+	}
+	return nil
+}
+
+// checkChallenge detects a captcha or security check in place of the page
+// expected, giving the solver a chance at it. A challenge still showing
+// afterwards fails with a ChallengeError carrying a screenshot of it.
+func (c *NABClient) checkChallenge() chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		var kind string
+		if err := chromedp.Evaluate(challengeScript, &kind).Do(ctx); err != nil {
+			c.logger.Printf("Failed to check for a security challenge: %v", err)
+			return nil
+		}
+		if kind == "" {
+			return nil
+		}
+
+		var location string
+		_ = chromedp.Location(&location).Do(ctx)
+		challenge := model.Challenge{Kind: kind, URL: location, DetectedAt: time.Now()}
+
+		if c.solver != nil {
+			if err := c.solver.Solve(ctx, challenge); err != nil {
+				c.logger.Printf("Security challenge solver failed: %v", err)
+			}
+			if err := chromedp.Evaluate(challengeScript, &kind).Do(ctx); err == nil && kind == "" {
+				return nil
+			}
+		}
+
+		challenge.Screenshot = c.saveScreenshot(ctx, "challenge")
+		c.logger.Printf("NAB is showing a %s at %s", challenge.Kind, challenge.URL)
+		return &service.ChallengeError{Challenge: challenge}
+	})
+}
+
+// saveScreenshot writes the page to the screenshot directory, returning
+// its path, or an empty path if it couldn't be saved
+func (c *NABClient) saveScreenshot(ctx context.Context, name string) string {
+	var buf []byte
+	if err := c.captureScreenshot(&buf).Do(ctx); err != nil {
+		c.logger.Printf("Failed to capture %s screenshot: %v", name, err)
+		return ""
+	}
+	if err := os.MkdirAll(c.config.ScreenshotPath, 0o755); err != nil {
+		c.logger.Printf("Failed to create screenshot directory: %v", err)
+		return ""
+	}
+	path := filepath.Join(c.config.ScreenshotPath, fmt.Sprintf("nab_%s_%s.png", name, time.Now().Format("20060102_150405")))
+	if err := os.WriteFile(path, buf, 0o644); err != nil {
+		c.logger.Printf("Failed to save screenshot: %v", err)
+		return ""
+	}
+	c.screenshots.add(path)
+	return path
+}
@@ -0,0 +1,35 @@
+package browser
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// chromeExecutables are the names and paths chromedp looks for Chrome
+// under, in its order
+var chromeExecutables = []string{
+	"headless_shell",
+	"headless-shell",
+	"chromium",
+	"chromium-browser",
+	"google-chrome",
+	"google-chrome-stable",
+	"google-chrome-beta",
+	"google-chrome-unstable",
+	"/usr/bin/google-chrome",
+	"/usr/local/bin/chrome",
+	"/snap/bin/chromium",
+	"chrome",
+	"/Applications/Chromium.app/Contents/MacOS/Chromium",
+	"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
+}
+
+// FindChrome returns the Chrome executable the browser client will launch
+func FindChrome() (string, error) {
+	for _, name := range chromeExecutables {
+		if found, err := exec.LookPath(name); err == nil {
+			return found, nil
+		}
+	}
+	return "", errors.New("no Chrome or Chromium executable found")
+}
@@ -0,0 +1,92 @@
+package browser
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+var (
+	creditLimitPattern      = regexp.MustCompile(`(?i)credit\s+limit[^\d$]*\$?\s*([\d,]+\.\d{2})`)
+	availableCreditPattern  = regexp.MustCompile(`(?i)available\s+(?:credit|to\s+spend|balance)[^\d$]*\$?\s*([\d,]+\.\d{2})`)
+	statementBalancePattern = regexp.MustCompile(`(?i)(?:closing|statement)\s+balance[^\d$]*\$?\s*([\d,]+\.\d{2})`)
+	minimumPaymentPattern   = regexp.MustCompile(`(?i)minimum\s+(?:payment|repayment)(?:\s+due)?[^\d$]*\$?\s*([\d,]+\.\d{2})`)
+	paymentDueDatePattern   = regexp.MustCompile(`(?i)(?:payment\s+)?due\s*(?:date|by|on)?[:\s]*(\d{1,2}\s+[A-Za-z]{3,9}\s+\d{4}|\d{1,2}/\d{1,2}/\d{4}|\d{4}-\d{2}-\d{2})`)
+	statementPeriodPattern  = regexp.MustCompile(`(?i)statement(?:\s+period)?[:\s]*(\d{1,2}\s+[A-Za-z]{3,9}\s+\d{2,4}|\d{1,2}/\d{1,2}/\d{2,4}|\d{4}-\d{2}-\d{2})\s*(?:-|–|to)\s*(\d{1,2}\s+[A-Za-z]{3,9}\s+\d{2,4}|\d{1,2}/\d{1,2}/\d{2,4}|\d{4}-\d{2}-\d{2})`)
+)
+
+// parseCreditDetails extracts credit card details from account text,
+// returning nil if no credit limit is shown. The account balance is used to
+// work out available credit when the page doesn't show it.
+func parseCreditDetails(text, balance string) *model.CreditDetails {
+	limit := findAmount(creditLimitPattern, text)
+	if limit == nil {
+		return nil
+	}
+
+	credit := &model.CreditDetails{
+		CreditLimit:      *limit,
+		StatementBalance: findAmount(statementBalancePattern, text),
+		MinimumPayment:   findAmount(minimumPaymentPattern, text),
+	}
+
+	if available := findAmount(availableCreditPattern, text); available != nil {
+		credit.AvailableCredit = *available
+	} else if available, ok := availableCredit(limit.Amount, balance); ok {
+		credit.AvailableCredit = model.Money{Amount: available}
+	}
+
+	if match := paymentDueDatePattern.FindStringSubmatch(text); match != nil {
+		due := parseDisplayDate(match[1])
+		credit.PaymentDueDate = &due
+	}
+
+	credit.StatementPeriod = parseStatementPeriod(text)
+	return credit
+}
+
+// parseStatementPeriod extracts the open statement's dates, as in
+// "Statement period 16 Oct 2023 - 15 Nov 2023", returning nil unless both
+// are readable dates
+func parseStatementPeriod(text string) *model.StatementPeriod {
+	match := statementPeriodPattern.FindStringSubmatch(text)
+	if match == nil {
+		return nil
+	}
+	start, end := parseDisplayDate(match[1]), parseDisplayDate(match[2])
+	if _, err := time.Parse("2006-01-02", start); err != nil {
+		return nil
+	}
+	if _, err := time.Parse("2006-01-02", end); err != nil {
+		return nil
+	}
+	return &model.StatementPeriod{Start: start, End: end}
+}
+
+// availableCredit derives available credit from the limit and the amount
+// owed, for pages that don't show it
+func availableCredit(limit, balance string) (string, bool) {
+	l, err := strconv.ParseFloat(limit, 64)
+	if err != nil {
+		return "", false
+	}
+	b, err := strconv.ParseFloat(balance, 64)
+	if err != nil {
+		return "", false
+	}
+	// Credit card balances are owed, so may be shown with or without a sign
+	return strconv.FormatFloat(l-math.Abs(b), 'f', 2, 64), true
+}
+
+// findAmount returns the first dollar amount captured by the pattern
+func findAmount(pattern *regexp.Regexp, text string) *model.Money {
+	match := pattern.FindStringSubmatch(text)
+	if match == nil {
+		return nil
+	}
+	return &model.Money{Amount: strings.ReplaceAll(match[1], ",", "")}
+}
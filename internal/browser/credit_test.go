@@ -0,0 +1,44 @@
+package browser
+
+import (
+	"testing"
+)
+
+func TestParseCreditDetails(t *testing.T) {
+	credit := parseCreditDetails(`NAB Low Rate Card
+Balance $1,234.56
+Credit limit $6,000.00
+Available credit $4,765.44
+Closing balance $980.10
+Minimum payment due $29.40
+Payment due date 5 Nov 2023
+Statement period 16 Oct 2023 - 15 Nov 2023`, "-1234.56")
+
+	if credit == nil {
+		t.Fatal("expected credit details")
+	}
+	if credit.CreditLimit.Amount != "6000.00" || credit.AvailableCredit.Amount != "4765.44" {
+		t.Errorf("unexpected limit/available %+v", credit)
+	}
+	if credit.StatementBalance == nil || credit.StatementBalance.Amount != "980.10" {
+		t.Errorf("unexpected statement balance %v", credit.StatementBalance)
+	}
+	if credit.MinimumPayment == nil || credit.MinimumPayment.Amount != "29.40" {
+		t.Errorf("unexpected minimum payment %v", credit.MinimumPayment)
+	}
+	if credit.PaymentDueDate == nil || *credit.PaymentDueDate != "2023-11-05" {
+		t.Errorf("unexpected due date %v", credit.PaymentDueDate)
+	}
+	if period := credit.StatementPeriod; period == nil || period.Start != "2023-10-16" || period.End != "2023-11-15" {
+		t.Errorf("unexpected statement period %v", period)
+	}
+
+	derived := parseCreditDetails("Credit limit $5,000.00", "-1200.00")
+	if derived == nil || derived.AvailableCredit.Amount != "3800.00" {
+		t.Errorf("expected derived available credit, got %+v", derived)
+	}
+
+	if parseCreditDetails("Complete Access Account $2,543.67", "2543.67") != nil {
+		t.Error("expected nil without a credit limit")
+	}
+}
@@ -0,0 +1,48 @@
+package browser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// currencyCodes are the currencies NAB's foreign currency and travel
+// accounts hold, as shown beside their balances
+const currencyCodes = `AUD|USD|EUR|GBP|NZD|JPY|CAD|SGD|HKD|CHF|CNY|THB`
+
+// balancePattern matches a balance with its currency symbol, optionally
+// preceded or followed by a currency code, as in "$1,234.56",
+// "USD $250.00" or "€80.00 EUR"
+var balancePattern = regexp.MustCompile(`(?:\b(` + currencyCodes + `)\s?)?(US\$|NZ\$|A\$|\$|€|£|¥)([\d,]+\.\d{2})(?:\s?(` + currencyCodes + `)\b)?`)
+
+// currencySymbols maps balance symbols to the currency they mean without a
+// code beside them. NAB shows Australian dollars as a bare "$".
+var currencySymbols = map[string]string{
+	"$":   "AUD",
+	"A$":  "AUD",
+	"US$": "USD",
+	"NZ$": "NZD",
+	"€":   "EUR",
+	"£":   "GBP",
+	"¥":   "JPY",
+}
+
+// findBalances returns every balance in text with its currency, taken
+// from a code beside the amount, or else from its symbol
+func findBalances(text string) []model.Money {
+	var balances []model.Money
+	for _, match := range balancePattern.FindAllStringSubmatch(text, -1) {
+		currency := currencySymbols[match[2]]
+		if match[1] != "" {
+			currency = match[1]
+		} else if match[4] != "" {
+			currency = match[4]
+		}
+		balances = append(balances, model.Money{
+			Amount:   strings.ReplaceAll(match[3], ",", ""),
+			Currency: currency,
+		})
+	}
+	return balances
+}
@@ -0,0 +1,29 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestFindBalances(t *testing.T) {
+	balances := findBalances(`<td>Complete Access</td><td>$1,234.56</td>
+<td>Travel Card USD</td><td>USD $250.00</td>
+<td>Euro Account</td><td>€80.10 EUR</td>
+<td>Kiwi Saver</td><td>NZ$45.00</td>`)
+
+	want := []model.Money{
+		{Amount: "1234.56", Currency: "AUD"},
+		{Amount: "250.00", Currency: "USD"},
+		{Amount: "80.10", Currency: "EUR"},
+		{Amount: "45.00", Currency: "NZD"},
+	}
+	if len(balances) != len(want) {
+		t.Fatalf("expected %v, got %v", want, balances)
+	}
+	for i := range want {
+		if balances[i] != want[i] {
+			t.Errorf("balance %d: got %v, want %v", i, balances[i], want[i])
+		}
+	}
+}
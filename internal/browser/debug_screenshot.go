@@ -0,0 +1,46 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/chromedp"
+)
+
+// Screenshot opens internet banking with the persisted session and
+// captures what NAB shows, as a PNG: the accounts while the session is
+// alive, otherwise the login form, a maintenance page or a challenge. It
+// never logs in, so it is safe to call while NAB is refusing logins, and
+// it ignores any terms pause for the same reason.
+func (c *NABClient) Screenshot(ctx context.Context) ([]byte, error) {
+	if c.config.SessionDir != "" {
+		if err := c.sessionLock.lock(ctx); err != nil {
+			return nil, abortedError(ctx)
+		}
+		defer c.sessionLock.unlock()
+	}
+
+	profile := c.profiles.pick()
+	browserCtx, cancel, err := newBrowserContext(ctx, c.launchConfig(), profile, c.config.BrowserTimeout, c.logger)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	stopped := c.health.started()
+	defer stopped()
+
+	var buf []byte
+	err = chromedp.Run(browserCtx,
+		profile.emulate(),
+		chromedp.Navigate(c.config.KeepAliveURL),
+		chromedp.WaitVisible(`body`, chromedp.ByQuery),
+		c.captureScreenshot(&buf),
+	)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, abortedError(ctx)
+		}
+		return nil, fmt.Errorf("failed to capture %s: %w", c.config.KeepAliveURL, err)
+	}
+	return buf, nil
+}
@@ -0,0 +1,47 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestParseDirectDebit(t *testing.T) {
+	debit, ok := parseDirectDebit(directDebitRow{
+		Merchant: "AGL SALES PTY LTD",
+		UserID:   "User ID 064123",
+		Amount:   "$182.40",
+		Date:     "3 Oct 2023",
+		Text:     "AGL SALES PTY LTD\nUser ID 064123\nReference: 4001234567\n$182.40\n3 Oct 2023",
+	})
+	if !ok {
+		t.Fatal("expected direct debit")
+	}
+	if debit.UserID == nil || *debit.UserID != "064123" {
+		t.Errorf("unexpected user ID %v", debit.UserID)
+	}
+	if debit.Reference == nil || *debit.Reference != "4001234567" {
+		t.Errorf("unexpected reference %v", debit.Reference)
+	}
+	if debit.LastAmount == nil || debit.LastAmount.Amount != "182.40" {
+		t.Errorf("unexpected last amount %v", debit.LastAmount)
+	}
+	if debit.LastDebitDate == nil || *debit.LastDebitDate != "2023-10-03" {
+		t.Errorf("unexpected last debit date %v", debit.LastDebitDate)
+	}
+	if debit.Status != model.DirectDebitStatusActive || debit.ID != directDebitID("AGL SALES PTY LTD", "064123") {
+		t.Errorf("unexpected direct debit %+v", debit)
+	}
+
+	fromText, ok := parseDirectDebit(directDebitRow{Text: "FITNESS FIRST\nAPCA ID 123456\nCancelled"})
+	if !ok || fromText.Merchant != "FITNESS FIRST" || fromText.Status != model.DirectDebitStatusCancelled {
+		t.Errorf("unexpected direct debit from text %+v", fromText)
+	}
+	if fromText.UserID == nil || *fromText.UserID != "123456" || fromText.LastAmount != nil {
+		t.Errorf("unexpected fields from text %+v", fromText)
+	}
+
+	if _, ok := parseDirectDebit(directDebitRow{}); ok {
+		t.Error("expected empty row to be skipped")
+	}
+}
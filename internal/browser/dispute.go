@@ -0,0 +1,88 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/chromedp/chromedp"
+)
+
+// disputeLinkSelectors locate the dispute a transaction form
+var disputeLinkSelectors = []string{
+	`a[href*="dispute"]`,
+	`button[class*="dispute"]`,
+	`a[title*="Dispute"]`,
+	`[role="menuitem"][href*="dispute"]`,
+}
+
+// disputeField maps a dispute summary value onto candidate form inputs
+type disputeField struct {
+	name      string
+	value     string
+	selectors []string
+}
+
+// FillDisputeForm opens NAB's dispute form and fills it from the summary.
+// It is a dry run: the form is never submitted.
+func (c *NABClient) FillDisputeForm(ctx context.Context, dispute model.DisputeSummary) (*model.DisputeFormResult, error) {
+	c.logger.Printf("Filling dispute form for transaction %s (dry run)...", dispute.TransactionID)
+
+	result := &model.DisputeFormResult{FieldsFilled: []string{}}
+	err := c.runLoggedIn(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		if err := c.clickFirstVisible(ctx, disputeLinkSelectors); err != nil {
+			c.takeScreenshot(ctx, "dispute_form_not_found")
+			return fmt.Errorf("could not find dispute form: %w", err)
+		}
+		chromedp.Sleep(2 * time.Second).Do(ctx)
+		result.Reached = true
+
+		for _, field := range disputeFields(dispute) {
+			if c.fillFirstVisible(ctx, field.selectors, field.value) {
+				result.FieldsFilled = append(result.FieldsFilled, field.name)
+			}
+		}
+
+		c.takeScreenshot(ctx, "dispute_form_filled")
+		return nil
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fill NAB dispute form: %w", err)
+	}
+
+	return result, nil
+}
+
+// disputeFields lists the form fields to fill for a dispute
+func disputeFields(dispute model.DisputeSummary) []disputeField {
+	return []disputeField{
+		{"date", dispute.Date, []string{`input[name*="date" i]`, `input[type="date"]`}},
+		{"merchant", dispute.Merchant, []string{`input[name*="merchant" i]`, `input[id*="merchant" i]`}},
+		{"amount", strings.TrimPrefix(dispute.Amount.Amount, "-"), []string{`input[name*="amount" i]`, `input[id*="amount" i]`}},
+		{"reason", dispute.Reason, []string{`textarea[name*="reason" i]`, `textarea[name*="description" i]`, `textarea`}},
+	}
+}
+
+// fillFirstVisible types the value into the first visible selector,
+// reporting whether a field was filled
+func (c *NABClient) fillFirstVisible(ctx context.Context, selectors []string, value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, selector := range selectors {
+		selectorCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		err := chromedp.WaitVisible(selector, chromedp.ByQuery).Do(selectorCtx)
+		cancel()
+		if err != nil {
+			continue
+		}
+		if err := chromedp.SendKeys(selector, value, chromedp.ByQuery).Do(ctx); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
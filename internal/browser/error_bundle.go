@@ -0,0 +1,173 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/redact"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// maxConsoleEntries caps the console messages kept for a browser session
+const maxConsoleEntries = 200
+
+// consoleLog collects the console messages and uncaught exceptions of a
+// browser session
+type consoleLog struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+type consoleLogKey struct{}
+
+// recordConsole starts logging the browser session's console, returning a
+// context carrying the log
+func recordConsole(ctx context.Context) context.Context {
+	console := &consoleLog{}
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		var entry string
+		switch e := ev.(type) {
+		case *runtime.EventConsoleAPICalled:
+			args := make([]string, 0, len(e.Args))
+			for _, arg := range e.Args {
+				args = append(args, consoleValue(arg))
+			}
+			entry = fmt.Sprintf("%s [%s] %s", consoleTime(e.Timestamp), e.Type, strings.Join(args, " "))
+		case *runtime.EventExceptionThrown:
+			text := e.ExceptionDetails.Text
+			if e.ExceptionDetails.Exception != nil && e.ExceptionDetails.Exception.Description != "" {
+				text = e.ExceptionDetails.Exception.Description
+			}
+			entry = fmt.Sprintf("%s [exception] %s", consoleTime(e.Timestamp), text)
+		default:
+			return
+		}
+
+		console.mu.Lock()
+		defer console.mu.Unlock()
+		console.entries = append(console.entries, entry)
+		if len(console.entries) > maxConsoleEntries {
+			console.entries = console.entries[len(console.entries)-maxConsoleEntries:]
+		}
+	})
+	return context.WithValue(ctx, consoleLogKey{}, console)
+}
+
+// consoleFrom returns the console messages logged so far in the session
+func consoleFrom(ctx context.Context) []string {
+	console, ok := ctx.Value(consoleLogKey{}).(*consoleLog)
+	if !ok {
+		return nil
+	}
+	console.mu.Lock()
+	defer console.mu.Unlock()
+	return append([]string(nil), console.entries...)
+}
+
+// consoleValue formats a console argument the way DevTools shows it
+func consoleValue(arg *runtime.RemoteObject) string {
+	if arg.Type == runtime.TypeString {
+		var s string
+		if err := json.Unmarshal(arg.Value, &s); err == nil {
+			return s
+		}
+	}
+	if len(arg.Value) > 0 {
+		return string(arg.Value)
+	}
+	if arg.Description != "" {
+		return arg.Description
+	}
+	return string(arg.Type)
+}
+
+// consoleTime formats a console event's timestamp
+func consoleTime(timestamp *runtime.Timestamp) string {
+	if timestamp == nil {
+		return time.Now().Format(time.RFC3339Nano)
+	}
+	return timestamp.Time().Format(time.RFC3339Nano)
+}
+
+// bundleError saves an error bundle for a scrape that failed with err and
+// returns err carrying it, or err alone if nothing could be saved
+func (c *NABClient) bundleError(ctx context.Context, name string, err error) error {
+	bundle, ok := c.saveErrorBundle(ctx, name, err)
+	if !ok {
+		return err
+	}
+	c.logger.Printf("Error bundle saved: %s", bundle.Path)
+	return &service.ScrapeError{Err: err, Bundle: bundle}
+}
+
+// saveErrorBundle saves a screenshot of the page, its HTML, its URL and
+// the session's console log to a timestamped directory alongside the
+// screenshots, readable only by this user. Whatever can't be captured is
+// left out. With LOG_REDACTION or BROWSER_SCREENSHOT_REDACT set sensitive
+// values are masked in everything but the screenshot, which is blurred
+// with BROWSER_SCREENSHOT_REDACT, so the bundle can be shared.
+func (c *NABClient) saveErrorBundle(ctx context.Context, name string, cause error) (model.ErrorBundle, bool) {
+	bundle := model.ErrorBundle{
+		Path:       filepath.Join(c.config.ScreenshotPath, fmt.Sprintf("nab_%s_%s", name, time.Now().Format("20060102_150405.000"))),
+		CapturedAt: time.Now(),
+	}
+	if err := os.MkdirAll(bundle.Path, 0o700); err != nil {
+		c.logger.Printf("Failed to create error bundle directory: %v", err)
+		return model.ErrorBundle{}, false
+	}
+
+	mask := func(text string) string { return text }
+	if c.config.LogRedaction || c.config.ScreenshotRedact {
+		mask = redact.New(c.config.Username, c.config.Password, c.config.CDR.AccessToken, c.config.CDR.ClientSecret).String
+	}
+	write := func(file string, data []byte) string {
+		path := filepath.Join(bundle.Path, file)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			c.logger.Printf("Failed to save %s: %v", path, err)
+			return ""
+		}
+		return path
+	}
+
+	var screenshot []byte
+	if err := c.captureScreenshot(&screenshot).Do(ctx); err != nil {
+		c.logger.Printf("Failed to capture error screenshot: %v", err)
+	} else if bundle.Screenshot = write("screenshot.png", screenshot); bundle.Screenshot != "" {
+		c.screenshots.add(bundle.Screenshot)
+	}
+
+	var html string
+	if err := chromedp.Run(ctx,
+		chromedp.Location(&bundle.URL),
+		chromedp.Title(&bundle.Title),
+		chromedp.OuterHTML(`html`, &html, chromedp.ByQuery),
+	); err != nil {
+		c.logger.Printf("Failed to capture error page: %v", err)
+	}
+	bundle.URL = mask(bundle.URL)
+	bundle.Title = mask(bundle.Title)
+	if html != "" {
+		bundle.HTML = write("page.html", []byte(mask(html)))
+	}
+	if console := consoleFrom(ctx); len(console) > 0 {
+		bundle.ConsoleLog = write("console.log", []byte(mask(strings.Join(console, "\n"))+"\n"))
+	}
+
+	summary, err := json.MarshalIndent(struct {
+		model.ErrorBundle
+		Error string `json:"error"`
+	}{bundle, mask(cause.Error())}, "", "  ")
+	if err == nil {
+		write("bundle.json", summary)
+	}
+	return bundle, true
+}
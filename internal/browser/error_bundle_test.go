@@ -0,0 +1,58 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/config"
+	"github.com/chromedp/cdproto/runtime"
+)
+
+func TestConsoleValue(t *testing.T) {
+	tests := []struct {
+		arg  *runtime.RemoteObject
+		want string
+	}{
+		{&runtime.RemoteObject{Type: runtime.TypeString, Value: []byte(`"login failed"`)}, "login failed"},
+		{&runtime.RemoteObject{Type: runtime.TypeNumber, Value: []byte(`42`)}, "42"},
+		{&runtime.RemoteObject{Type: runtime.TypeObject, Description: "TypeError: x is undefined"}, "TypeError: x is undefined"},
+		{&runtime.RemoteObject{Type: runtime.TypeUndefined}, "undefined"},
+	}
+	for _, tt := range tests {
+		if got := consoleValue(tt.arg); got != tt.want {
+			t.Errorf("consoleValue(%+v) = %q, want %q", tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestErrorBundleIsPrivateAndRedacted(t *testing.T) {
+	client := &NABClient{
+		config: &config.NABConfig{ScreenshotPath: t.TempDir(), Username: "jsmith99", LogRedaction: true},
+		logger: log.New(io.Discard, "", 0),
+	}
+	// Without a browser only the console log and summary are saved
+	ctx := context.WithValue(context.Background(), consoleLogKey{}, &consoleLog{entries: []string{"[log] signed in as jsmith99, balance $1,234.56"}})
+
+	bundle, ok := client.saveErrorBundle(ctx, "error", errors.New("failed for jsmith99"))
+	if !ok || bundle.ConsoleLog == "" {
+		t.Fatalf("expected a bundle with a console log, got %+v", bundle)
+	}
+	for path, want := range map[string]os.FileMode{bundle.Path: 0o700, bundle.ConsoleLog: 0o600} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != want {
+			t.Errorf("expected %s to be %v, got %v", path, want, info.Mode().Perm())
+		}
+	}
+	console, _ := os.ReadFile(bundle.ConsoleLog)
+	if strings.Contains(string(console), "jsmith99") || strings.Contains(string(console), "1,234.56") {
+		t.Errorf("expected the console log to be redacted, got %q", console)
+	}
+}
@@ -0,0 +1,185 @@
+package browser
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/chromedp/chromedp"
+)
+
+// goalsLinkSelectors locate the savings goals on an account
+var goalsLinkSelectors = []string{
+	`a[href*="goal"]`,
+	`a[href*="isaver"]`,
+	`a[title*="Goal" i]`,
+	`[role="tab"][aria-controls*="goal" i]`,
+}
+
+var (
+	goalProgressPattern  = regexp.MustCompile(`\$\s*([\d,]+\.\d{2})\s+(?:of|/)\s+\$\s*([\d,]+\.\d{2})`)
+	goalSavedPattern     = regexp.MustCompile(`(?i)(?:saved|balance|so\s+far)[^\d$]*\$\s*([\d,]+\.\d{2})`)
+	goalTargetPattern    = regexp.MustCompile(`(?i)(?:target|goal)(?:\s+amount)?[^\d$]*\$\s*([\d,]+\.\d{2})`)
+	goalAmountPattern    = regexp.MustCompile(`\$\s*([\d,]+\.\d{2})`)
+	goalDateValuePattern = regexp.MustCompile(`\d{1,2}\s+[A-Za-z]{3,9}\s+\d{4}|\d{1,2}/\d{1,2}/\d{4}|\d{4}-\d{2}-\d{2}`)
+	goalDatePattern      = regexp.MustCompile(`(?i)(?:by|target\s+date|reach\s+by|due)[:\s]*(\d{1,2}\s+[A-Za-z]{3,9}\s+\d{4}|\d{1,2}/\d{1,2}/\d{4}|\d{4}-\d{2}-\d{2})`)
+)
+
+// goalRow is a savings goal read from the page
+type goalRow struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Saved  string `json:"saved"`
+	Target string `json:"target"`
+	Date   string `json:"date"`
+	Text   string `json:"text"`
+}
+
+// extractGoalsScript reads goals from the account's goals list. Fields
+// are taken from labelled elements where NAB provides them, otherwise from
+// the goal's text.
+const extractGoalsScript = `(() => {
+	const rows = Array.from(document.querySelectorAll(
+		'[class*="goal"] li, [class*="Goal"] li, [class*="goal-card"], [data-goal-id]'));
+	const text = (row, selector) => {
+		const el = row.querySelector(selector);
+		return el ? (el.innerText || '').trim() : '';
+	};
+	return rows.map(row => ({
+		id: row.getAttribute('data-goal-id') || row.getAttribute('data-id') || '',
+		name: text(row, '[class*="goal-name"], [class*="goalName"], [class*="name"], h3'),
+		saved: text(row, '[class*="saved"], [class*="balance"], [class*="progress-amount"]'),
+		target: text(row, '[class*="target-amount"], [class*="targetAmount"], [class*="target"]'),
+		date: text(row, '[class*="target-date"], [class*="targetDate"], [class*="date"]'),
+		text: (row.innerText || '').trim(),
+	})).filter(row => row.text !== '');
+})()`
+
+// GetSavingsGoals opens an account and scrapes the savings goals set on
+// it
+func (c *NABClient) GetSavingsGoals(ctx context.Context, accountID string) ([]model.SavingsGoal, error) {
+	c.logger.Printf("Scraping savings goals for account %s...", accountID)
+
+	var goals []model.SavingsGoal
+	err := c.runLoggedIn(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		if err := c.openAccount(ctx, accountID); err != nil {
+			return err
+		}
+		chromedp.Sleep(2 * time.Second).Do(ctx)
+
+		if err := c.clickFirstVisible(ctx, goalsLinkSelectors); err != nil {
+			c.takeScreenshot(ctx, "goals_not_found")
+			return fmt.Errorf("could not find savings goals: %w", err)
+		}
+		chromedp.Sleep(2 * time.Second).Do(ctx)
+
+		var rows []goalRow
+		if err := chromedp.Evaluate(extractGoalsScript, &rows).Do(ctx); err != nil {
+			return fmt.Errorf("failed to read savings goals: %w", err)
+		}
+
+		c.recorder.record(ctx, recordGoals, accountID, rows)
+
+		goals = parseGoals(rows, c.logger)
+		return nil
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrape NAB savings goals: %w", err)
+	}
+
+	c.logger.Printf("Successfully scraped %d savings goals", len(goals))
+	return goals, nil
+}
+
+// parseGoals builds savings goals from the scraped rows, skipping rows
+// without a name or saved amount
+func parseGoals(rows []goalRow, logger *log.Logger) []model.SavingsGoal {
+	var goals []model.SavingsGoal
+	for _, row := range rows {
+		goal, ok := parseGoal(row)
+		if !ok {
+			logger.Printf("Skipping savings goal row without a name or amount: %q", row.Text)
+			continue
+		}
+		goals = append(goals, goal)
+	}
+	return goals
+}
+
+// parseGoal builds a savings goal from a scraped row. Without labelled
+// fields the amounts come from text like "$1,200.00 of $5,000.00" or
+// "Saved $1,200.00 Target $5,000.00", and the target date from "by 1 Dec
+// 2024".
+func parseGoal(row goalRow) (model.SavingsGoal, bool) {
+	name := strings.TrimSpace(row.Name)
+	if name == "" {
+		name = strings.TrimSpace(strings.SplitN(row.Text, "\n", 2)[0])
+	}
+	if name == "" {
+		return model.SavingsGoal{}, false
+	}
+
+	saved := findAmount(goalAmountPattern, row.Saved)
+	target := findAmount(goalAmountPattern, row.Target)
+	if match := goalProgressPattern.FindStringSubmatch(row.Text); match != nil {
+		if saved == nil {
+			saved = &model.Money{Amount: strings.ReplaceAll(match[1], ",", "")}
+		}
+		if target == nil {
+			target = &model.Money{Amount: strings.ReplaceAll(match[2], ",", "")}
+		}
+	}
+	if saved == nil {
+		saved = findAmount(goalSavedPattern, row.Text)
+	}
+	if target == nil {
+		target = findAmount(goalTargetPattern, row.Text)
+	}
+	if saved == nil {
+		return model.SavingsGoal{}, false
+	}
+
+	goal := model.SavingsGoal{
+		ID:           row.ID,
+		Name:         name,
+		SavedAmount:  *saved,
+		TargetAmount: target,
+	}
+	if goal.ID == "" {
+		goal.ID = goalID(name)
+	}
+
+	date := goalDateValuePattern.FindString(row.Date)
+	if match := goalDatePattern.FindStringSubmatch(row.Text); date == "" && match != nil {
+		date = match[1]
+	}
+	if date != "" {
+		date = parseDisplayDate(date)
+		goal.TargetDate = &date
+	}
+
+	if target != nil {
+		savedValue, savedErr := strconv.ParseFloat(saved.Amount, 64)
+		targetValue, targetErr := strconv.ParseFloat(target.Amount, 64)
+		if savedErr == nil && targetErr == nil && targetValue > 0 {
+			percent := math.Round(savedValue/targetValue*1000) / 10
+			goal.PercentComplete = &percent
+		}
+	}
+
+	return goal, true
+}
+
+// goalID derives a stable ID for goals that don't expose one
+func goalID(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return "goal_" + hex.EncodeToString(sum[:])[:16]
+}
@@ -0,0 +1,41 @@
+package browser
+
+import (
+	"io"
+	"log"
+	"testing"
+)
+
+func TestParseGoal(t *testing.T) {
+	goal, ok := parseGoal(goalRow{
+		Name:   "Japan trip",
+		Saved:  "$1,200.00",
+		Target: "Target $5,000.00",
+		Date:   "by 1 Dec 2024",
+		Text:   "Japan trip\n$1,200.00\nTarget $5,000.00\nby 1 Dec 2024",
+	})
+	if !ok {
+		t.Fatal("expected a goal")
+	}
+	if goal.SavedAmount.Amount != "1200.00" || goal.TargetAmount == nil || goal.TargetAmount.Amount != "5000.00" {
+		t.Errorf("unexpected amounts %+v", goal)
+	}
+	if goal.TargetDate == nil || *goal.TargetDate != "2024-12-01" {
+		t.Errorf("unexpected target date %v", goal.TargetDate)
+	}
+	if goal.PercentComplete == nil || *goal.PercentComplete != 24 || goal.ID != goalID("Japan trip") {
+		t.Errorf("unexpected goal %+v", goal)
+	}
+
+	fromText, ok := parseGoal(goalRow{Text: "Emergency fund\n$750.50 of $3,000.00\nReach by 30/06/2025"})
+	if !ok || fromText.Name != "Emergency fund" || fromText.SavedAmount.Amount != "750.50" || fromText.TargetAmount.Amount != "3000.00" {
+		t.Errorf("unexpected goal from text %+v", fromText)
+	}
+	if fromText.TargetDate == nil || *fromText.TargetDate != "2025-06-30" {
+		t.Errorf("unexpected target date from text %v", fromText.TargetDate)
+	}
+
+	if goals := parseGoals([]goalRow{{Text: "New car"}, {}}, log.New(io.Discard, "", 0)); len(goals) != 0 {
+		t.Errorf("expected rows without a saved amount skipped, got %+v", goals)
+	}
+}
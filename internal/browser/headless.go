@@ -0,0 +1,35 @@
+package browser
+
+import (
+	"github.com/benrowe/nab-bank-api/internal/config"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// SetHeadless overrides BROWSER_HEADLESS for browsers launched from now
+// on, such as to watch the next scrape when something is going wrong. A
+// remote browser's mode isn't up to this client.
+func (c *NABClient) SetHeadless(headless bool) error {
+	if c.config.BrowserRemoteURL != "" {
+		return service.ErrBrowserModeUnsupported
+	}
+	c.headless.Store(&headless)
+	c.logger.Printf("Browsers will be launched with headless=%v", headless)
+	return nil
+}
+
+// Headless reports whether browsers launched now run headless
+func (c *NABClient) Headless() bool {
+	return c.launchConfig().BrowserHeadless
+}
+
+// launchConfig is the configuration to launch a browser with, applying
+// any headless override
+func (c *NABClient) launchConfig() *config.NABConfig {
+	override := c.headless.Load()
+	if override == nil {
+		return c.config
+	}
+	cfg := *c.config
+	cfg.BrowserHeadless = *override
+	return &cfg
+}
@@ -0,0 +1,75 @@
+package browser
+
+import (
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// browserHealth tracks logins and running browsers for health checks
+type browserHealth struct {
+	mu           sync.Mutex
+	active       int
+	lastLogin    time.Time
+	lastLoginErr error
+	profile      string
+}
+
+// started notes a browser being launched, returning a func to call when
+// it closes
+func (h *browserHealth) started() func() {
+	h.mu.Lock()
+	h.active++
+	h.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			h.mu.Lock()
+			h.active--
+			h.mu.Unlock()
+		})
+	}
+}
+
+// loggedIn notes the outcome of a login
+func (h *browserHealth) loggedIn(profile DeviceProfile, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastLogin = time.Now()
+	h.lastLoginErr = err
+	h.profile = profile.Name
+}
+
+// BrowserHealth reports the login session and running browsers
+func (c *NABClient) BrowserHealth() model.BrowserHealth {
+	c.health.mu.Lock()
+	health := model.BrowserHealth{
+		ActiveBrowsers: c.health.active,
+		LoggedIn:       !c.health.lastLogin.IsZero() && c.health.lastLoginErr == nil,
+		DeviceProfile:  c.health.profile,
+	}
+	if !c.health.lastLogin.IsZero() {
+		lastLogin := c.health.lastLogin
+		health.LastLoginAt = &lastLogin
+	}
+	if c.health.lastLoginErr != nil {
+		health.LastLoginError = c.health.lastLoginErr.Error()
+	}
+	c.health.mu.Unlock()
+
+	// Chrome locks a persisted profile, so only one browser runs at a time
+	if c.config.SessionDir != "" {
+		health.MaxBrowsers = 1
+		health.PersistedSession = true
+		if state, err := loadSessionState(c.config); err == nil && state != nil {
+			health.SessionSavedAt = &state.SavedAt
+		}
+	}
+	if until, paused := c.pause.active(); paused {
+		health.PausedUntil = &until
+	}
+	return health
+}
@@ -0,0 +1,60 @@
+package browser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+var (
+	ratePattern      = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*%\s*p\.?\s*a\.?`)
+	baseRatePattern  = regexp.MustCompile(`(?i)base\s+(?:interest\s+)?rate[^\d%]*(\d+(?:\.\d+)?)\s*%`)
+	bonusRatePattern = regexp.MustCompile(`(?i)bonus\s+(?:interest\s+)?rate[^\d%]*(\d+(?:\.\d+)?)\s*%`)
+	earnedPattern    = regexp.MustCompile(`(?i)interest\s+(?:earned|paid)\s+(?:this|in\s+the\s+current)\s+financial\s+year[^$\d]*\$?\s*([\d,]+\.\d{2})`)
+	bonusNotMet      = regexp.MustCompile(`(?i)bonus[^.\n]*\b(not\s+(?:qualified|eligible|met|on\s+track)|missed|ineligible)\b`)
+	bonusMet         = regexp.MustCompile(`(?i)bonus[^.\n]*\b(qualified|eligible|earned|met|on\s+track)\b`)
+)
+
+// parseInterestDetails extracts interest information from account text,
+// returning nil if no rate is shown
+func parseInterestDetails(text string) *model.InterestDetails {
+	interest := &model.InterestDetails{}
+
+	if match := baseRatePattern.FindStringSubmatch(text); match != nil {
+		interest.BaseRate = &match[1]
+	}
+	if match := bonusRatePattern.FindStringSubmatch(text); match != nil {
+		interest.BonusRate = &match[1]
+	}
+
+	switch {
+	case interest.BaseRate != nil && interest.BonusRate != nil:
+		// The total rate is what's earned when the bonus conditions are met
+		base, _ := strconv.ParseFloat(*interest.BaseRate, 64)
+		bonus, _ := strconv.ParseFloat(*interest.BonusRate, 64)
+		interest.Rate = strconv.FormatFloat(base+bonus, 'f', 2, 64)
+	default:
+		match := ratePattern.FindStringSubmatch(text)
+		if match == nil {
+			return nil
+		}
+		interest.Rate = match[1]
+	}
+
+	if match := earnedPattern.FindStringSubmatch(text); match != nil {
+		interest.EarnedThisFinancialYear = &model.Money{Amount: strings.ReplaceAll(match[1], ",", "")}
+	}
+
+	switch {
+	case bonusNotMet.MatchString(text):
+		qualified := false
+		interest.BonusQualified = &qualified
+	case bonusMet.MatchString(text):
+		qualified := true
+		interest.BonusQualified = &qualified
+	}
+
+	return interest
+}
@@ -0,0 +1,39 @@
+package browser
+
+import (
+	"testing"
+)
+
+func TestParseInterestDetails(t *testing.T) {
+	interest := parseInterestDetails(`NAB Reward Saver
+$15,420.89
+Base rate 0.10% p.a.
+Bonus rate 4.90% p.a.
+Bonus interest: on track this month
+Interest earned this financial year $312.45`)
+
+	if interest == nil {
+		t.Fatal("expected interest details")
+	}
+	if interest.Rate != "5.00" {
+		t.Errorf("Rate = %q, want 5.00", interest.Rate)
+	}
+	if interest.BaseRate == nil || *interest.BaseRate != "0.10" || interest.BonusRate == nil || *interest.BonusRate != "4.90" {
+		t.Errorf("unexpected base/bonus rates %v %v", interest.BaseRate, interest.BonusRate)
+	}
+	if interest.EarnedThisFinancialYear == nil || interest.EarnedThisFinancialYear.Amount != "312.45" {
+		t.Errorf("unexpected earned %v", interest.EarnedThisFinancialYear)
+	}
+	if interest.BonusQualified == nil || !*interest.BonusQualified {
+		t.Errorf("expected bonus qualified")
+	}
+
+	missed := parseInterestDetails("Interest rate 0.01% p.a. Bonus interest not met this month")
+	if missed == nil || missed.Rate != "0.01" || missed.BonusQualified == nil || *missed.BonusQualified {
+		t.Errorf("unexpected details %+v", missed)
+	}
+
+	if parseInterestDetails("Complete Access Account $2,543.67") != nil {
+		t.Error("expected nil when no rate is shown")
+	}
+}
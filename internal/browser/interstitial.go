@@ -0,0 +1,133 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/config"
+	"github.com/chromedp/chromedp"
+)
+
+// maxDismissalPasses bounds how many times the page is checked for popups,
+// since closing one can reveal another
+const maxDismissalPasses = 3
+
+// DismissalRule describes a popup that can appear over internet banking
+// and how to close it. If Dismiss is empty the popup is removed from the
+// page instead.
+type DismissalRule struct {
+	Name     string `json:"name"`
+	Selector string `json:"selector"`
+	Dismiss  string `json:"dismiss,omitempty"`
+}
+
+// defaultDismissalRules covers the surveys, promos and consent banners NAB
+// is known to show after login
+var defaultDismissalRules = []DismissalRule{
+	{
+		Name:     "cookie-consent",
+		Selector: `#onetrust-banner-sdk, [class*="cookie-banner"], [id*="cookie-banner"], [class*="consent-banner"]`,
+		Dismiss:  `#onetrust-accept-btn-handler, button[id*="accept"], button[class*="accept"]`,
+	},
+	{
+		Name:     "feedback-survey",
+		Selector: `#kampyleInvite, [id*="QSIFeedback"], [class*="QSIPopOver"], [class*="survey-modal"], [id*="survey"]`,
+		Dismiss:  `#kplDeclineButton, button[aria-label*="lose"], button[class*="close"], button[class*="decline"]`,
+	},
+	{
+		Name:     "promo",
+		Selector: `[role="dialog"][class*="promo"], [class*="promo-modal"], [class*="marketing-modal"], [class*="interstitial"]`,
+		Dismiss:  `button[aria-label*="lose"], button[class*="close"], button[class*="dismiss"], a[class*="remind-me-later"]`,
+	},
+}
+
+// dismissInterstitialsScript closes any visible popup matching the rules
+// and returns the names of the rules that fired
+const dismissInterstitialsScript = `(rules => {
+	const visible = el => el && el.getClientRects().length > 0;
+	const dismissed = [];
+	for (const rule of rules) {
+		const popup = Array.from(document.querySelectorAll(rule.selector)).find(visible);
+		if (!popup) continue;
+		if (rule.dismiss) {
+			const button = Array.from(popup.querySelectorAll(rule.dismiss)).find(visible)
+				|| Array.from(document.querySelectorAll(rule.dismiss)).find(visible);
+			if (!button) continue;
+			button.click();
+		} else {
+			popup.remove();
+		}
+		dismissed.push(rule.name);
+	}
+	return dismissed;
+})(%s)`
+
+// loadDismissalRules reads extra rules from a JSON file. They are tried
+// before the built-in rules.
+func loadDismissalRules(path string) ([]DismissalRule, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read interstitial rules: %w", err)
+	}
+
+	var rules []DismissalRule
+	if err := json.Unmarshal(raw, &rules); err != nil {
+		return nil, fmt.Errorf("failed to decode interstitial rules: %w", err)
+	}
+	for i, rule := range rules {
+		if rule.Name == "" || rule.Selector == "" {
+			return nil, fmt.Errorf("interstitial rule %d needs a name and selector", i+1)
+		}
+	}
+
+	return append(rules, defaultDismissalRules...), nil
+}
+
+// configuredDismissalRules returns the rules from cfg, falling back to the
+// built-in rules if none are configured or they can't be loaded
+func configuredDismissalRules(cfg *config.NABConfig, logger *log.Logger) []DismissalRule {
+	if cfg.InterstitialRules == "" {
+		return defaultDismissalRules
+	}
+
+	rules, err := loadDismissalRules(cfg.InterstitialRules)
+	if err != nil {
+		logger.Printf("Ignoring interstitial rules: %v", err)
+		return defaultDismissalRules
+	}
+	return rules
+}
+
+// dismissInterstitials closes popups that would otherwise cover the page
+// and cause selector timeouts. It never fails the session; popups that
+// can't be closed are left for the following actions to deal with.
+func (c *NABClient) dismissInterstitials() chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		rules, err := json.Marshal(*c.interstitials.Load())
+		if err != nil {
+			return fmt.Errorf("failed to encode interstitial rules: %w", err)
+		}
+		script := fmt.Sprintf(dismissInterstitialsScript, rules)
+
+		for pass := 0; pass < maxDismissalPasses; pass++ {
+			var dismissed []string
+			if err := chromedp.Evaluate(script, &dismissed).Do(ctx); err != nil {
+				c.logger.Printf("Failed to check for interstitials: %v", err)
+				return nil
+			}
+			if len(dismissed) == 0 {
+				return nil
+			}
+
+			c.logger.Printf("Dismissed interstitials: %v", dismissed)
+			if err := chromedp.Sleep(500 * time.Millisecond).Do(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
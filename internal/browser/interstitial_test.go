@@ -0,0 +1,29 @@
+package browser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadDismissalRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(path, []byte(`[{"name": "app-promo", "selector": "#download-app", "dismiss": ".not-now"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := loadDismissalRules(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != len(defaultDismissalRules)+1 || rules[0].Name != "app-promo" {
+		t.Errorf("expected custom rule before the defaults, got %+v", rules)
+	}
+
+	if err := os.WriteFile(path, []byte(`[{"name": "no-selector"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadDismissalRules(path); err == nil {
+		t.Error("expected error for rule without a selector")
+	}
+}
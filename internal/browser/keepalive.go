@@ -0,0 +1,56 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/chromedp/chromedp"
+)
+
+// loginFormScript reports whether the page is asking for a password, which
+// means the session has expired
+const loginFormScript = `Array.from(document.querySelectorAll('input[type="password"]')).some(el => el.getClientRects().length > 0)`
+
+// KeepAlive opens an internet banking page with the persisted session so
+// NAB doesn't expire it between scrapes. It never logs in: a session that
+// has already expired fails with ErrSessionExpired and is left for the
+// next scrape to replace. Without a persisted session there is nothing to
+// keep alive.
+func (c *NABClient) KeepAlive(ctx context.Context) error {
+	if c.config.SessionDir == "" || c.config.BrowserRemoteURL != "" {
+		return nil
+	}
+	if _, paused := c.pause.active(); paused {
+		return nil
+	}
+
+	if err := c.sessionLock.lock(ctx); err != nil {
+		return err
+	}
+	defer c.sessionLock.unlock()
+
+	profile := c.profiles.pick()
+	browserCtx, cancel, err := newBrowserContext(ctx, c.launchConfig(), profile, c.config.BrowserTimeout, c.logger)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	stopped := c.health.started()
+	defer stopped()
+
+	var expired bool
+	err = chromedp.Run(browserCtx,
+		profile.emulate(),
+		chromedp.Navigate(c.config.KeepAliveURL),
+		chromedp.WaitVisible(`body`, chromedp.ByQuery),
+		chromedp.Evaluate(loginFormScript, &expired),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", c.config.KeepAliveURL, err)
+	}
+	if expired {
+		return service.ErrSessionExpired
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/chromedp/chromedp"
+)
+
+var (
+	loanRatePattern        = regexp.MustCompile(`(?i)(?:interest\s+rate|variable\s+rate|fixed\s+rate)[^\d%]*(\d+(?:\.\d+)?)\s*%`)
+	repaymentAmountPattern = regexp.MustCompile(`(?i)(?:repayment|payment)\s+amount[^\d$]*\$?\s*([\d,]+\.\d{2})`)
+	repaymentFreqPattern   = regexp.MustCompile(`(?i)\b(weekly|fortnightly|monthly)\b`)
+	nextRepaymentPattern   = regexp.MustCompile(`(?i)next\s+(?:repayment|payment)(?:\s+(?:date|due))?[:\s]*(\d{1,2}\s+[A-Za-z]{3,9}\s+\d{4}|\d{1,2}/\d{1,2}/\d{4}|\d{4}-\d{2}-\d{2})`)
+	redrawPattern          = regexp.MustCompile(`(?i)(?:available\s+)?redraw(?:\s+available)?[^\d$]*\$?\s*([\d,]+\.\d{2})`)
+	originalLoanPattern    = regexp.MustCompile(`(?i)(?:original\s+loan\s+amount|amount\s+borrowed|loan\s+amount)[^\d$]*\$?\s*([\d,]+\.\d{2})`)
+)
+
+// GetLoanDetails opens a loan account and scrapes its rate, repayment and
+// redraw details
+func (c *NABClient) GetLoanDetails(ctx context.Context, accountID string) (*model.LoanDetails, error) {
+	c.logger.Printf("Scraping loan details for account %s...", accountID)
+
+	var text string
+	err := c.runLoggedIn(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		text, err = c.readLoanPage(ctx, accountID)
+		return err
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrape NAB loan details: %w", err)
+	}
+	return loanDetailsFrom(text, accountID)
+}
+
+// readLoanPage opens a loan account from the dashboard and returns its
+// page text
+func (c *NABClient) readLoanPage(ctx context.Context, accountID string) (string, error) {
+	if err := c.openAccount(ctx, accountID); err != nil {
+		return "", err
+	}
+	chromedp.Sleep(2 * time.Second).Do(ctx)
+	var text string
+	if err := chromedp.Text(`body`, &text, chromedp.ByQuery).Do(ctx); err != nil {
+		return "", err
+	}
+	c.recorder.record(ctx, recordLoan, accountID, text)
+	return text, nil
+}
+
+// loanDetailsFrom parses a loan account's page text, failing if it shows
+// no loan details
+func loanDetailsFrom(text, accountID string) (*model.LoanDetails, error) {
+	loan := parseLoanDetails(text)
+	if loan == nil {
+		return nil, fmt.Errorf("no loan details found for account %s", accountID)
+	}
+	return loan, nil
+}
+
+// openAccount clicks through to an account from the dashboard
+func (c *NABClient) openAccount(ctx context.Context, accountID string) error {
+	selectors := []string{
+		fmt.Sprintf(`[data-account-id=%q] a`, accountID),
+		fmt.Sprintf(`[data-account-id=%q]`, accountID),
+		fmt.Sprintf(`a[href*=%q]`, accountID),
+	}
+	if err := c.clickFirstVisible(ctx, selectors); err != nil {
+		c.takeScreenshot(ctx, "account_not_found")
+		return fmt.Errorf("could not open account %s: %w", accountID, err)
+	}
+	return nil
+}
+
+// parseLoanDetails extracts loan details from account text, returning nil
+// if no interest rate is shown
+func parseLoanDetails(text string) *model.LoanDetails {
+	match := loanRatePattern.FindStringSubmatch(text)
+	if match == nil {
+		return nil
+	}
+
+	loan := &model.LoanDetails{
+		InterestRate:    match[1],
+		RepaymentAmount: findAmount(repaymentAmountPattern, text),
+		RedrawAvailable: findAmount(redrawPattern, text),
+		OriginalAmount:  findAmount(originalLoanPattern, text),
+	}
+
+	if match := repaymentFreqPattern.FindStringSubmatch(text); match != nil {
+		loan.RepaymentFrequency = strings.ToLower(match[1])
+	}
+	if match := nextRepaymentPattern.FindStringSubmatch(text); match != nil {
+		next := parseDisplayDate(match[1])
+		loan.NextRepaymentDate = &next
+	}
+
+	return loan
+}
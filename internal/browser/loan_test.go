@@ -0,0 +1,38 @@
+package browser
+
+import (
+	"testing"
+)
+
+func TestParseLoanDetails(t *testing.T) {
+	loan := parseLoanDetails(`NAB Base Variable Rate Home Loan
+Balance owing $412,345.67
+Interest rate 6.24% p.a.
+Repayment amount $2,850.00 monthly
+Next repayment 1 Nov 2023
+Available redraw $15,200.00
+Original loan amount $500,000.00`)
+
+	if loan == nil {
+		t.Fatal("expected loan details")
+	}
+	if loan.InterestRate != "6.24" || loan.RepaymentFrequency != "monthly" {
+		t.Errorf("unexpected rate/frequency %+v", loan)
+	}
+	if loan.RepaymentAmount == nil || loan.RepaymentAmount.Amount != "2850.00" {
+		t.Errorf("unexpected repayment %v", loan.RepaymentAmount)
+	}
+	if loan.NextRepaymentDate == nil || *loan.NextRepaymentDate != "2023-11-01" {
+		t.Errorf("unexpected next repayment %v", loan.NextRepaymentDate)
+	}
+	if loan.RedrawAvailable == nil || loan.RedrawAvailable.Amount != "15200.00" {
+		t.Errorf("unexpected redraw %v", loan.RedrawAvailable)
+	}
+	if loan.OriginalAmount == nil || loan.OriginalAmount.Amount != "500000.00" {
+		t.Errorf("unexpected original amount %v", loan.OriginalAmount)
+	}
+
+	if parseLoanDetails("Complete Access Account $2,543.67") != nil {
+		t.Error("expected nil without an interest rate")
+	}
+}
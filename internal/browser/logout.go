@@ -0,0 +1,90 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// logoutSelector matches the log out control in internet banking
+const logoutSelector = `a[href*="logout" i], a[href*="Logout" i], button[data-testid="logout"]`
+
+// logoutWait is how long NAB gets to confirm a logout before the session
+// is discarded regardless
+const logoutWait = 15 * time.Second
+
+// Logout ends the persisted NAB session and discards its cookies and
+// browser data, so the next scrape logs in from scratch with the current
+// credentials. Logging out with NAB is best effort: the local session is
+// cleared even if NAB can't be reached. Without a persisted session every
+// scrape already logs in afresh, so there is nothing to do.
+func (c *NABClient) Logout(ctx context.Context) (bool, bool, error) {
+	if c.config.SessionDir == "" {
+		return false, false, nil
+	}
+
+	if err := c.sessionLock.lock(ctx); err != nil {
+		return false, false, err
+	}
+	defer c.sessionLock.unlock()
+
+	loggedOut := false
+	if c.config.BrowserRemoteURL == "" {
+		var err error
+		if loggedOut, err = c.endSession(ctx); err != nil {
+			c.logger.Printf("Failed to log out of NAB, discarding the session anyway: %v", err)
+		}
+	}
+
+	if err := clearSessionState(c.config.SessionDir); err != nil {
+		return loggedOut, false, err
+	}
+	c.profiles.unpin()
+	c.logger.Println("Discarded saved browser session after logout")
+	return loggedOut, true, nil
+}
+
+// endSession opens internet banking with the persisted session and clicks
+// log out, reporting whether there was a live session to end. The caller
+// holds the session lock.
+func (c *NABClient) endSession(ctx context.Context) (bool, error) {
+	state, err := loadSessionState(c.config)
+	if err != nil || state == nil {
+		return false, err
+	}
+
+	browserCtx, cancel, err := newBrowserContext(ctx, c.launchConfig(), state.Profile, c.config.BrowserTimeout, c.logger)
+	if err != nil {
+		return false, err
+	}
+	defer cancel()
+	stopped := c.health.started()
+	defer stopped()
+
+	var expired bool
+	err = chromedp.Run(browserCtx,
+		state.Profile.emulate(),
+		chromedp.Navigate(c.config.KeepAliveURL),
+		chromedp.WaitVisible(`body`, chromedp.ByQuery),
+		chromedp.Evaluate(loginFormScript, &expired),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to open %s: %w", c.config.KeepAliveURL, err)
+	}
+	if expired {
+		return false, nil
+	}
+
+	logoutCtx, cancelLogout := context.WithTimeout(browserCtx, logoutWait)
+	defer cancelLogout()
+	err = chromedp.Run(logoutCtx,
+		chromedp.Click(logoutSelector, chromedp.ByQuery),
+		chromedp.WaitVisible(`input[type="password"]`, chromedp.ByQuery),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to log out: %w", err)
+	}
+	return true, nil
+}
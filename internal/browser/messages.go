@@ -0,0 +1,212 @@
+package browser
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/chromedp/chromedp"
+)
+
+// inboxLinkSelectors locate the secure messages inbox after login
+var inboxLinkSelectors = []string{
+	`a[href*="inbox"]`,
+	`a[href*="messages"]`,
+	`a[title*="Inbox"]`,
+	`a[title*="Messages"]`,
+	`[role="menuitem"][href*="message"]`,
+}
+
+// messageBodySelectors locate the body of an opened message
+var messageBodySelectors = []string{
+	`[class*="message-body"]`,
+	`[class*="messageBody"]`,
+	`[class*="message-content"]`,
+	`article`,
+	`main`,
+}
+
+// inboxRow is a message summary read from the inbox list
+type inboxRow struct {
+	ID      string `json:"id"`
+	Subject string `json:"subject"`
+	Date    string `json:"date"`
+	Unread  bool   `json:"unread"`
+	Href    string `json:"href"`
+}
+
+// extractInboxScript reads message rows from the inbox list. Unread state
+// is taken from a class name or bold subject, which is how NAB marks it.
+const extractInboxScript = `(() => {
+	const rows = Array.from(document.querySelectorAll(
+		'[class*="message-list"] li, [class*="messageList"] li, table[class*="message"] tbody tr, [class*="inbox"] [role="row"]'));
+	return rows.map(row => {
+		const link = row.querySelector('a[href]');
+		const subject = row.querySelector('[class*="subject"]') || link || row;
+		const date = row.querySelector('time, [class*="date"]');
+		const weight = parseInt(getComputedStyle(subject).fontWeight, 10);
+		return {
+			id: row.getAttribute('data-id') || row.getAttribute('data-message-id') || '',
+			subject: (subject.innerText || '').trim(),
+			date: date ? (date.getAttribute('datetime') || date.innerText || '').trim() : '',
+			unread: /unread/i.test(row.className) || weight >= 600,
+			href: link ? link.href : '',
+		};
+	}).filter(row => row.subject !== '');
+})()`
+
+// GetMessages scrapes the secure messages inbox, opening each message to
+// read its body
+func (c *NABClient) GetMessages(ctx context.Context) ([]model.Message, error) {
+	c.logger.Println("Scraping NAB secure messages...")
+
+	var messages []model.Message
+	err := c.runLoggedIn(ctx, c.scrapeMessages(&messages))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrape NAB messages: %w", err)
+	}
+
+	c.logger.Printf("Successfully scraped %d messages", len(messages))
+	return messages, nil
+}
+
+// scrapeMessages opens the inbox and reads every listed message
+func (c *NABClient) scrapeMessages(messages *[]model.Message) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if err := c.clickFirstVisible(ctx, inboxLinkSelectors); err != nil {
+			c.takeScreenshot(ctx, "inbox_not_found")
+			return fmt.Errorf("could not find messages inbox: %w", err)
+		}
+		chromedp.Sleep(2 * time.Second).Do(ctx)
+
+		var rows []inboxRow
+		if err := chromedp.Evaluate(extractInboxScript, &rows).Do(ctx); err != nil {
+			return fmt.Errorf("failed to read inbox: %w", err)
+		}
+
+		inbox := recordedInbox{Rows: rows, Bodies: make(map[string]string)}
+		for _, row := range rows {
+			if row.Href == "" {
+				continue
+			}
+			body, err := c.readMessageBody(ctx, row.Href)
+			if err != nil {
+				c.logger.Printf("Failed to read message %q: %v", row.Subject, err)
+			}
+			inbox.Bodies[row.Href] = body
+		}
+		c.recorder.record(ctx, recordMessages, "", inbox)
+
+		*messages = inbox.messages()
+		return nil
+	})
+}
+
+// recordedInbox is the inbox list along with the body of each message,
+// keyed by its link
+type recordedInbox struct {
+	Rows   []inboxRow        `json:"rows"`
+	Bodies map[string]string `json:"bodies"`
+}
+
+// messages builds messages from the inbox rows and their bodies
+func (i recordedInbox) messages() []model.Message {
+	var messages []model.Message
+	for _, row := range i.Rows {
+		message := model.Message{
+			ID:      row.ID,
+			Subject: row.Subject,
+			Date:    parseDisplayDate(row.Date),
+			Read:    !row.Unread,
+			Body:    i.Bodies[row.Href],
+		}
+		if message.ID == "" {
+			message.ID = messageID(row.Subject, row.Date)
+		}
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+// readMessageBody opens a message and returns its text
+func (c *NABClient) readMessageBody(ctx context.Context, href string) (string, error) {
+	if err := chromedp.Navigate(href).Do(ctx); err != nil {
+		return "", err
+	}
+
+	for _, selector := range messageBodySelectors {
+		var text string
+		// Short timeout per selector so missing ones fail fast
+		selectorCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		err := chromedp.Text(selector, &text, chromedp.ByQuery).Do(selectorCtx)
+		cancel()
+		if err == nil && strings.TrimSpace(text) != "" {
+			return strings.TrimSpace(text), nil
+		}
+	}
+
+	return "", fmt.Errorf("could not find message body")
+}
+
+// clickFirstVisible clicks the first selector that becomes visible
+func (c *NABClient) clickFirstVisible(ctx context.Context, selectors []string) error {
+	for _, selector := range selectors {
+		selectorCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		err := chromedp.WaitVisible(selector, chromedp.ByQuery).Do(selectorCtx)
+		cancel()
+		if err == nil {
+			c.logger.Printf("Found link with selector: %s", selector)
+			return chromedp.Click(selector, chromedp.ByQuery).Do(ctx)
+		}
+	}
+
+	return fmt.Errorf("none of %d selectors matched", len(selectors))
+}
+
+// displayDateLayouts are the date formats NAB shows on screen. Two digit
+// years, as in "17 Oct 23", come after the four digit ones.
+var displayDateLayouts = []string{
+	"2006-01-02",
+	time.RFC3339,
+	"02/01/2006",
+	"2 Jan 2006",
+	"02 Jan 2006",
+	"2 January 2006",
+	"2 Jan 06",
+	"02/01/06",
+}
+
+// parseDisplayDate normalises a displayed date to YYYY-MM-DD, falling back to
+// the raw text when the format is unrecognised
+func parseDisplayDate(text string) string {
+	return parseDisplayDateAt(text, time.Now())
+}
+
+// parseDisplayDateAt is parseDisplayDate with "Today" and "Yesterday"
+// taken relative to now in NAB's time zone
+func parseDisplayDateAt(text string, now time.Time) string {
+	text = strings.TrimSpace(text)
+	today := now.In(model.BankLocation())
+	switch strings.ToLower(text) {
+	case "today":
+		return today.Format("2006-01-02")
+	case "yesterday":
+		return today.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	for _, layout := range displayDateLayouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+	return text
+}
+
+// messageID derives a stable ID for messages that don't expose one
+func messageID(subject, date string) string {
+	sum := sha256.Sum256([]byte(date + "\x00" + subject))
+	return "msg_" + hex.EncodeToString(sum[:])[:16]
+}
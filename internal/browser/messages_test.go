@@ -0,0 +1,26 @@
+package browser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDisplayDateAt(t *testing.T) {
+	// Early on the 18th in Sydney
+	now := time.Date(2024, 5, 17, 20, 0, 0, 0, time.UTC)
+
+	tests := map[string]string{
+		"17 Oct 23":      "2023-10-17",
+		"17 Oct 2023":    "2023-10-17",
+		"17/10/2023":     "2023-10-17",
+		"2 January 2024": "2024-01-02",
+		"Today":          "2024-05-18",
+		" yesterday ":    "2024-05-17",
+		"Pending":        "Pending",
+	}
+	for text, want := range tests {
+		if got := parseDisplayDateAt(text, now); got != want {
+			t.Errorf("%q: got %q, want %q", text, got, want)
+		}
+	}
+}
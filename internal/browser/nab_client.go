@@ -4,9 +4,9 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"path/filepath"
 	"regexp"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/chromedp/chromedp"
@@ -15,70 +15,51 @@ import (
 	"github.com/benrowe/nab-bank-api/internal/service"
 )
 
-// NABClient implements the NABClient interface using chromedp
+// NABClient implements the BankProvider interface using chromedp
 type NABClient struct {
-	config *config.NABConfig
-	logger *log.Logger
+	config        *config.NABConfig
+	logger        *log.Logger
+	profiles      *profileRotator
+	interstitials atomic.Pointer[[]DismissalRule]
+	sessionLock   sessionLock
+	pause         termsPause
+	payments      pendingPayments
+	recorder      *recorder
+	health        browserHealth
+	solver        ChallengeSolver
+	headless      atomic.Pointer[bool]
+	screenshots   screenshotLog
+	steps         stepMetrics
 }
 
 // NewNABClient creates a new NAB browser client
-func NewNABClient(cfg *config.NABConfig, logger *log.Logger) service.NABClient {
-	return &NABClient{
-		config: cfg,
-		logger: logger,
+func NewNABClient(cfg *config.NABConfig, logger *log.Logger) service.BankProvider {
+	profiles := configuredProfiles(cfg, logger)
+	client := &NABClient{
+		config:   cfg,
+		logger:   logger,
+		profiles: newProfileRotator(profiles, cfg.RotateProfiles || cfg.BrowserStealth),
+		recorder: newRecorder(cfg.RecordDir, logger),
+		solver:   newHandOff(cfg.ChallengeWait, logger),
 	}
+	rules := configuredDismissalRules(cfg, logger)
+	client.interstitials.Store(&rules)
+	client.restoreSession(profiles)
+	return client
 }
 
 // GetAccounts scrapes account information from NAB website
 func (c *NABClient) GetAccounts(ctx context.Context) ([]model.Account, error) {
 	c.logger.Println("Starting NAB account scraping...")
 
-	// Create browser context
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.Flag("headless", c.config.BrowserHeadless),
-		chromedp.Flag("disable-gpu", true),
-		chromedp.Flag("no-sandbox", true),
-		chromedp.Flag("disable-dev-shm-usage", true),
-		chromedp.UserAgent(c.config.UserAgent),
-	)
-
-	allocCtx, cancel := chromedp.NewExecAllocator(ctx, opts...)
-	defer cancel()
-
-	browserCtx, cancel := chromedp.NewContext(allocCtx)
-	defer cancel()
-
-	// Set timeout
-	timeoutCtx, cancel := context.WithTimeout(browserCtx, c.config.BrowserTimeout)
-	defer cancel()
-
 	// Perform login and scraping
 	var accounts []model.Account
-	err := chromedp.Run(timeoutCtx,
-		// Navigate to NAB homepage
-		chromedp.Navigate(c.config.BaseURL),
-		chromedp.WaitVisible(`body`, chromedp.ByQuery),
-
-		// Click Login button in header
-		c.clickLoginButton(),
-
-		// Select Internet Banking from dropdown
-		c.selectInternetBanking(),
-
-		// Perform login
-		c.performLogin(),
-
-		// Wait for successful login and navigate to accounts
-		chromedp.WaitVisible(`body`, chromedp.ByQuery),
-		chromedp.Sleep(3*time.Second), // Give time for page to load
-
+	err := c.runLoggedIn(ctx,
 		// Navigate to accounts page or scrape from dashboard
-		c.scrapeAccounts(&accounts),
+		c.timed(stepScrapeAccounts, c.scrapeAccounts(&accounts)),
 	)
 
 	if err != nil {
-		// Take screenshot for debugging
-		c.takeScreenshot(timeoutCtx, "error")
 		return nil, fmt.Errorf("failed to scrape NAB accounts: %w", err)
 	}
 
@@ -86,15 +67,6 @@ func (c *NABClient) GetAccounts(ctx context.Context) ([]model.Account, error) {
 	return accounts, nil
 }
 
-// GetAccountTransactions scrapes transaction data for a specific account
-func (c *NABClient) GetAccountTransactions(ctx context.Context, accountID string) ([]model.Transaction, error) {
-	c.logger.Printf("Scraping transactions for account %s...", accountID)
-
-	// For now, return empty transactions as this requires more complex navigation
-	// This would be implemented similarly to GetAccounts but navigating to the specific account's transaction page
-	return []model.Transaction{}, nil
-}
-
 // clickLoginButton clicks the Login button in the header
 func (c *NABClient) clickLoginButton() chromedp.Action {
 	return chromedp.ActionFunc(func(ctx context.Context) error {
@@ -115,6 +87,10 @@ func (c *NABClient) clickLoginButton() chromedp.Action {
 
 		// Try each selector to find the login button
 		for _, selector := range loginButtonSelectors {
+			// Stop trying selectors once the request is abandoned
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			err := chromedp.WaitVisible(selector, chromedp.ByQuery).Do(ctx)
 			if err == nil {
 				c.logger.Printf("Found login button with selector: %s", selector)
@@ -150,6 +126,9 @@ func (c *NABClient) selectInternetBanking() chromedp.Action {
 
 		// Try each selector to find the Internet Banking link
 		for _, selector := range internetBankingSelectors {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			err := chromedp.WaitVisible(selector, chromedp.ByQuery).Do(ctx)
 			if err == nil {
 				c.logger.Printf("Found Internet Banking link with selector: %s", selector)
@@ -194,6 +173,9 @@ func (c *NABClient) performLogin() chromedp.Action {
 
 		// Find username field
 		for _, selector := range loginSelectors {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			err := chromedp.WaitVisible(selector, chromedp.ByQuery).Do(ctx)
 			if err == nil {
 				usernameSelector = selector
@@ -207,6 +189,9 @@ func (c *NABClient) performLogin() chromedp.Action {
 
 		// Find password field
 		for _, selector := range passwordSelectors {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			err := chromedp.WaitVisible(selector, chromedp.ByQuery).Do(ctx)
 			if err == nil {
 				passwordSelector = selector
@@ -220,6 +205,9 @@ func (c *NABClient) performLogin() chromedp.Action {
 
 		// Find submit button
 		for _, selector := range submitSelectors {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			err := chromedp.WaitVisible(selector, chromedp.ByQuery).Do(ctx)
 			if err == nil {
 				submitSelector = selector
@@ -232,6 +220,15 @@ func (c *NABClient) performLogin() chromedp.Action {
 		}
 
 		// Perform login
+		if c.config.BrowserStealth {
+			return chromedp.Tasks{
+				typeHumanly(usernameSelector, c.config.Username),
+				chromedp.Sleep(keystrokeDelay() * 3),
+				typeHumanly(passwordSelector, c.config.Password),
+				chromedp.Sleep(keystrokeDelay() * 3),
+				chromedp.Click(submitSelector, chromedp.ByQuery),
+			}.Do(ctx)
+		}
 		return chromedp.Tasks{
 			chromedp.SendKeys(usernameSelector, c.config.Username, chromedp.ByQuery),
 			chromedp.SendKeys(passwordSelector, c.config.Password, chromedp.ByQuery),
@@ -249,6 +246,7 @@ func (c *NABClient) scrapeAccounts(accounts *[]model.Account) chromedp.Action {
 		// Use the generic page source approach for now
 		c.logger.Println("Extracting accounts from page source...")
 		foundAccounts := c.extractAccountsGeneric(ctx)
+		c.addAccountDetails(ctx, foundAccounts)
 
 		*accounts = foundAccounts
 		return nil
@@ -262,24 +260,26 @@ func (c *NABClient) extractAccountsGeneric(ctx context.Context) []model.Account
 	var pageSource string
 	chromedp.OuterHTML(`html`, &pageSource, chromedp.ByQuery).Do(ctx)
 
+	return parseAccountsHTML(pageSource)
+}
+
+// parseAccountsHTML extracts accounts from the accounts page source
+func parseAccountsHTML(pageSource string) []model.Account {
 	// Look for currency patterns and account names
 	accounts := []model.Account{}
 
 	// This is a simplified example - real implementation would need to be
 	// tailored to NAB's actual website structure
-	balanceRegex := regexp.MustCompile(`\$[\d,]+\.[\d]{2}`)
-	balances := balanceRegex.FindAllString(pageSource, -1)
+	balances := findBalances(pageSource)
 
 	for i, balance := range balances {
-		// Clean up balance string
-		cleanBalance := strings.ReplaceAll(strings.TrimPrefix(balance, "$"), ",", "")
-
+		available := balance
 		account := model.Account{
 			ID:      fmt.Sprintf("account_%d", i+1),
 			Name:    fmt.Sprintf("NAB Account %d", i+1),
 			Type:    model.AccountTypeSavings,
-			Balance: model.Money{Amount: cleanBalance},
-			AvailableBalance: &model.Money{Amount: cleanBalance},
+			Balance: balance,
+			AvailableBalance: &available,
 		}
 
 		accounts = append(accounts, account)
@@ -339,6 +339,9 @@ func (c *NABClient) extractAccountName(text string) string {
 func (c *NABClient) extractAccountType(text string) string {
 	textLower := strings.ToLower(text)
 
+	if strings.Contains(textLower, "term deposit") {
+		return model.AccountTypeTermDeposit
+	}
 	if strings.Contains(textLower, "saver") || strings.Contains(textLower, "savings") {
 		return model.AccountTypeSavings
 	}
@@ -390,12 +393,7 @@ func (c *NABClient) extractBSB(text string) string {
 
 // takeScreenshot captures a screenshot for debugging
 func (c *NABClient) takeScreenshot(ctx context.Context, suffix string) {
-	timestamp := time.Now().Format("20060102_150405")
-	filename := filepath.Join(c.config.ScreenshotPath, fmt.Sprintf("nab_debug_%s_%s.png", suffix, timestamp))
-
-	var buf []byte
-	if err := chromedp.CaptureScreenshot(&buf).Do(ctx); err == nil {
-		// In a real implementation, you'd write buf to the file
-		c.logger.Printf("Screenshot captured: %s", filename)
+	if path := c.saveScreenshot(ctx, "debug_"+suffix); path != "" {
+		c.logger.Printf("Screenshot captured: %s", path)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,312 @@
+package browser
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/chromedp/chromedp"
+)
+
+// payAnyoneLinkSelectors locate the Pay Anyone form
+var payAnyoneLinkSelectors = []string{
+	`a[href*="pay-anyone"]`,
+	`a[href*="payanyone"]`,
+	`a[title*="Pay anyone" i]`,
+	`[role="menuitem"][href*="pay"]`,
+	`a[href*="payments"]`,
+}
+
+// newPayeeSelectors switch the payee picker to entering a new payee
+var newPayeeSelectors = []string{
+	`button[class*="new-payee"]`,
+	`a[href*="new-payee"]`,
+	`button[id*="newPayee"]`,
+	`input[type="radio"][value*="new" i]`,
+}
+
+// paymentCodeSelectors find the SMS one-time code field
+var paymentCodeSelectors = []string{
+	`input[autocomplete="one-time-code"]`,
+	`input[name*="otp" i]`,
+	`input[name*="code" i]`,
+	`input[id*="code" i]`,
+}
+
+// paymentCodeScreenScript reports whether NAB is asking for an SMS code
+const paymentCodeScreenScript = `(() => Array.from(document.querySelectorAll(
+	'input[autocomplete="one-time-code"], input[name*="otp" i], input[name*="code" i], input[id*="code" i]'))
+	.some(el => el.getClientRects().length > 0))()`
+
+// PayAnyone makes a Pay Anyone payment to a saved or new payee. The form is
+// filled and taken to the review screen, whose fee and processing date are
+// returned; unless this is a dry run the payment is then confirmed. If NAB
+// asks for an SMS code the logged in browser is kept open until
+// AuthorizePayment is called or the auth window passes. Payments log in
+// from a copy of the persisted session, so NAB sees the usual device, but
+// one waiting for a code doesn't hold the persisted session and block
+// scrapes and syncs in the meantime.
+func (c *NABClient) PayAnyone(ctx context.Context, req model.PayAnyoneRequest, from model.Account, payee model.Payee) (*model.PaymentResult, error) {
+	mode := "live"
+	if req.DryRun {
+		mode = "dry run"
+	}
+	c.logger.Printf("Paying $%s from %s to %s (%s)...", req.Amount, from.ID, payee.Name, mode)
+
+	id, err := newPaymentID()
+	if err != nil {
+		return nil, err
+	}
+	result := &model.PaymentResult{
+		ID:            id,
+		Status:        model.PaymentStatusValidated,
+		FromAccountID: from.ID,
+		Payee:         payee,
+		Amount:        model.Money{Amount: req.Amount},
+		Description:   req.Description,
+		Reference:     req.Reference,
+		DryRun:        req.DryRun,
+	}
+
+	// The session may outlive the request while waiting for the SMS code
+	sessionCtx, release, err := c.startEphemeralSession(context.WithoutCancel(ctx), c.config.BrowserTimeout+c.config.PaymentAuthWindow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pay from NAB account: %w", err)
+	}
+
+	var pending bool
+	err = chromedp.Run(sessionCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		if err := c.fillPayAnyoneForm(ctx, req, from, payee); err != nil {
+			return err
+		}
+		result.Review = c.readReview(ctx)
+		if req.DryRun {
+			c.takeScreenshot(ctx, "payment_review")
+			return nil
+		}
+
+		if err := c.clickFirstVisible(ctx, transferConfirmSelectors); err != nil {
+			return fmt.Errorf("could not confirm payment: %w", err)
+		}
+		chromedp.Sleep(3 * time.Second).Do(ctx)
+		if err := c.formError(ctx, service.ErrPaymentRejected); err != nil {
+			return err
+		}
+
+		if err := chromedp.Evaluate(paymentCodeScreenScript, &pending).Do(ctx); err != nil {
+			return fmt.Errorf("failed to check for SMS code: %w", err)
+		}
+		if pending {
+			c.takeScreenshot(ctx, "payment_sms_code")
+			return nil
+		}
+		return c.readPaymentReceipt(ctx, result)
+	}))
+	if err != nil {
+		err = c.bundleError(sessionCtx, "payment_error", err)
+		release()
+		return nil, fmt.Errorf("failed to pay from NAB account: %w", err)
+	}
+
+	result.ProcessedAt = time.Now()
+	if !pending {
+		release()
+		return result, nil
+	}
+
+	expires := time.Now().Add(c.config.PaymentAuthWindow)
+	result.Status = model.PaymentStatusPendingAuth
+	result.AuthExpiresAt = &expires
+	c.payments.hold(&pendingPayment{ctx: sessionCtx, release: release, result: *result}, c.config.PaymentAuthWindow)
+
+	c.logger.Printf("Payment %s is waiting for the SMS code until %s", id, expires.Format(time.RFC3339))
+	return result, nil
+}
+
+// AuthorizePayment enters the SMS code for a payment held in pending_auth
+// and captures the receipt. A rejected code leaves the payment pending so
+// it can be retried.
+func (c *NABClient) AuthorizePayment(ctx context.Context, paymentID, code string) (*model.PaymentResult, error) {
+	payment := c.payments.get(paymentID)
+	if payment == nil {
+		return nil, service.ErrPaymentNotFound
+	}
+	payment.mu.Lock()
+	defer payment.mu.Unlock()
+
+	c.logger.Printf("Authorising payment %s...", paymentID)
+
+	result := payment.result
+	err := chromedp.Run(payment.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		if !c.fillFirstVisible(ctx, paymentCodeSelectors, code) {
+			return fmt.Errorf("could not find SMS code field")
+		}
+		if err := c.clickFirstVisible(ctx, transferConfirmSelectors); err != nil {
+			return fmt.Errorf("could not submit SMS code: %w", err)
+		}
+		chromedp.Sleep(3 * time.Second).Do(ctx)
+		if err := c.formError(ctx, service.ErrPaymentRejected); err != nil {
+			return err
+		}
+		return c.readPaymentReceipt(ctx, &result)
+	}))
+	if err != nil {
+		if payment.ctx.Err() != nil {
+			c.payments.finish(paymentID)
+			return nil, service.ErrPaymentNotFound
+		}
+		if !errors.Is(err, service.ErrPaymentRejected) {
+			err = c.bundleError(payment.ctx, "payment_error", err)
+			c.payments.finish(paymentID)
+		}
+		return nil, fmt.Errorf("failed to authorise NAB payment: %w", err)
+	}
+	c.payments.finish(paymentID)
+
+	result.AuthExpiresAt = nil
+	result.ProcessedAt = time.Now()
+	return &result, nil
+}
+
+// fillPayAnyoneForm fills in the Pay Anyone form and moves on to the
+// review screen, where NAB validates the payment
+func (c *NABClient) fillPayAnyoneForm(ctx context.Context, req model.PayAnyoneRequest, from model.Account, payee model.Payee) error {
+	if err := c.clickFirstVisible(ctx, payAnyoneLinkSelectors); err != nil {
+		c.takeScreenshot(ctx, "payment_form_not_found")
+		return fmt.Errorf("could not find Pay Anyone form: %w", err)
+	}
+	chromedp.Sleep(2 * time.Second).Do(ctx)
+
+	if err := c.selectTransferAccount(ctx, "from", from); err != nil {
+		return err
+	}
+
+	if payee.ID != "" {
+		if err := c.selectPayee(ctx, payee); err != nil {
+			return err
+		}
+	} else {
+		if err := c.clickFirstVisible(ctx, newPayeeSelectors); err != nil {
+			c.takeScreenshot(ctx, "new_payee_not_found")
+			return fmt.Errorf("could not find new payee option: %w", err)
+		}
+		chromedp.Sleep(time.Second).Do(ctx)
+
+		fields := []struct {
+			name      string
+			value     string
+			selectors []string
+		}{
+			{"payee name", payee.Name, []string{`input[name*="accountName" i]`, `input[name*="payeeName" i]`, `input[name*="name" i]`}},
+			{"BSB", payee.BSB, []string{`input[name*="bsb" i]`, `input[id*="bsb" i]`}},
+			{"account number", payee.AccountNumber, []string{`input[name*="accountNumber" i]`, `input[id*="accountNumber" i]`}},
+		}
+		for _, field := range fields {
+			if !c.fillFirstVisible(ctx, field.selectors, field.value) {
+				return fmt.Errorf("could not find %s field", field.name)
+			}
+		}
+	}
+
+	if !c.fillFirstVisible(ctx, []string{`input[name*="amount" i]`, `input[id*="amount" i]`}, req.Amount) {
+		return fmt.Errorf("could not find amount field")
+	}
+	c.fillFirstVisible(ctx, []string{`input[name*="description" i]`, `textarea`}, req.Description)
+	c.fillFirstVisible(ctx, []string{`input[name*="reference" i]`, `input[id*="reference" i]`}, req.Reference)
+
+	if err := c.clickFirstVisible(ctx, transferNextSelectors); err != nil {
+		return fmt.Errorf("could not continue to review: %w", err)
+	}
+	chromedp.Sleep(2 * time.Second).Do(ctx)
+	return c.formError(ctx, service.ErrPaymentRejected)
+}
+
+// selectPayee chooses a saved payee in the payee dropdown by its ID,
+// account number or name
+func (c *NABClient) selectPayee(ctx context.Context, payee model.Payee) error {
+	needles := []string{payee.ID, digits(payee.AccountNumber), payee.Name}
+	return c.selectOption(ctx, []string{"payee", "to"}, needles, "payee "+payee.Name, "payee")
+}
+
+// readPaymentReceipt marks the payment completed and captures the receipt
+// number from the confirmation screen
+func (c *NABClient) readPaymentReceipt(ctx context.Context, result *model.PaymentResult) error {
+	var text string
+	if err := chromedp.Text(`body`, &text, chromedp.ByQuery).Do(ctx); err != nil {
+		return fmt.Errorf("failed to read payment receipt: %w", err)
+	}
+	c.takeScreenshot(ctx, "payment_receipt")
+
+	result.Status = model.PaymentStatusCompleted
+	if match := receiptPattern.FindStringSubmatch(text); match != nil {
+		result.ReceiptNumber = &match[1]
+	} else {
+		c.logger.Println("Payment confirmed but no receipt number was found")
+	}
+	return nil
+}
+
+// newPaymentID generates a random payment ID
+func newPaymentID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate payment ID: %w", err)
+	}
+	return "pay_" + hex.EncodeToString(id), nil
+}
+
+// pendingPayment is a payment waiting for its SMS code, along with the
+// logged in browser it was started in
+type pendingPayment struct {
+	mu      sync.Mutex
+	ctx     context.Context
+	release func()
+	result  model.PaymentResult
+	timer   *time.Timer
+}
+
+// pendingPayments holds payments waiting for SMS codes, closing their
+// browsers once authorised or expired
+type pendingPayments struct {
+	mu       sync.Mutex
+	payments map[string]*pendingPayment
+}
+
+// hold keeps the payment until it is finished or ttl passes
+func (p *pendingPayments) hold(payment *pendingPayment, ttl time.Duration) {
+	id := payment.result.ID
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.payments == nil {
+		p.payments = make(map[string]*pendingPayment)
+	}
+	payment.timer = time.AfterFunc(ttl, func() { p.finish(id) })
+	p.payments[id] = payment
+}
+
+// get returns the pending payment, or nil if there is none
+func (p *pendingPayments) get(id string) *pendingPayment {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.payments[id]
+}
+
+// finish forgets the payment and closes its browser
+func (p *pendingPayments) finish(id string) {
+	p.mu.Lock()
+	payment, ok := p.payments[id]
+	delete(p.payments, id)
+	p.mu.Unlock()
+
+	if ok {
+		payment.timer.Stop()
+		payment.release()
+	}
+}
@@ -0,0 +1,54 @@
+package browser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestPendingPaymentsExpire(t *testing.T) {
+	var payments pendingPayments
+	released := make(chan string, 2)
+
+	for _, id := range []string{"pay_expires", "pay_finished"} {
+		id := id
+		payments.hold(&pendingPayment{
+			ctx:     context.Background(),
+			release: func() { released <- id },
+			result:  model.PaymentResult{ID: id},
+		}, 50*time.Millisecond)
+	}
+
+	payments.finish("pay_finished")
+	if got := <-released; got != "pay_finished" {
+		t.Fatalf("expected finished payment to be released first, got %s", got)
+	}
+	if payments.get("pay_finished") != nil {
+		t.Error("finished payment should be forgotten")
+	}
+	if payments.get("pay_expires") == nil {
+		t.Fatal("pending payment should be held until it expires")
+	}
+
+	select {
+	case got := <-released:
+		if got != "pay_expires" {
+			t.Errorf("unexpected release of %s", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("pending payment was not released after expiring")
+	}
+	if payments.get("pay_expires") != nil {
+		t.Error("expired payment should be forgotten")
+	}
+
+	// Finishing twice must not release the browser again
+	payments.finish("pay_finished")
+	select {
+	case got := <-released:
+		t.Errorf("unexpected second release of %s", got)
+	default:
+	}
+}
@@ -0,0 +1,172 @@
+package browser
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/chromedp/chromedp"
+)
+
+// payeesLinkSelectors locate the payee list after login
+var payeesLinkSelectors = []string{
+	`a[href*="payee"]`,
+	`a[href*="address-book"]`,
+	`a[href*="addressbook"]`,
+	`a[title*="Payees"]`,
+	`a[title*="Address book"]`,
+	`[role="menuitem"][href*="payee"]`,
+}
+
+var (
+	payeeBSBPattern     = regexp.MustCompile(`\b(\d{3})[- ]?(\d{3})\b`)
+	payeeAccountPattern = regexp.MustCompile(`\b\d{5,10}\b`)
+)
+
+// payeeRow is a payee read from the address book
+type payeeRow struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Nickname string `json:"nickname"`
+	BSB      string `json:"bsb"`
+	Account  string `json:"account"`
+	Text     string `json:"text"`
+}
+
+// extractPayeesScript reads payee rows from the address book. Fields are
+// taken from labelled elements where NAB provides them, otherwise from the
+// row text.
+const extractPayeesScript = `(() => {
+	const rows = Array.from(document.querySelectorAll(
+		'[class*="payee-list"] li, [class*="payeeList"] li, table[class*="payee"] tbody tr, [class*="address-book"] [role="row"], [data-payee-id]'));
+	const text = (row, selector) => {
+		const el = row.querySelector(selector);
+		return el ? (el.innerText || '').trim() : '';
+	};
+	return rows.map(row => ({
+		id: row.getAttribute('data-payee-id') || row.getAttribute('data-id') || '',
+		name: text(row, '[class*="account-name"], [class*="accountName"], [class*="payee-name"], [class*="payeeName"]'),
+		nickname: text(row, '[class*="nickname"], [class*="nick-name"]'),
+		bsb: text(row, '[class*="bsb"]'),
+		account: text(row, '[class*="account-number"], [class*="accountNumber"]'),
+		text: (row.innerText || '').trim(),
+	})).filter(row => row.text !== '');
+})()`
+
+// GetPayees scrapes the saved payees from the NAB address book
+func (c *NABClient) GetPayees(ctx context.Context) ([]model.Payee, error) {
+	c.logger.Println("Scraping NAB payees...")
+
+	var payees []model.Payee
+	err := c.runLoggedIn(ctx, c.scrapePayees(&payees))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrape NAB payees: %w", err)
+	}
+
+	c.logger.Printf("Successfully scraped %d payees", len(payees))
+	return payees, nil
+}
+
+// scrapePayees opens the address book and reads every listed payee
+func (c *NABClient) scrapePayees(payees *[]model.Payee) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if err := c.clickFirstVisible(ctx, payeesLinkSelectors); err != nil {
+			c.takeScreenshot(ctx, "payees_not_found")
+			return fmt.Errorf("could not find payee list: %w", err)
+		}
+		chromedp.Sleep(2 * time.Second).Do(ctx)
+
+		var rows []payeeRow
+		if err := chromedp.Evaluate(extractPayeesScript, &rows).Do(ctx); err != nil {
+			return fmt.Errorf("failed to read payees: %w", err)
+		}
+
+		c.recorder.record(ctx, recordPayees, "", rows)
+
+		*payees = parsePayees(rows, c.logger)
+		return nil
+	})
+}
+
+// parsePayees builds payees from the scraped rows, skipping rows that
+// aren't bank account payees
+func parsePayees(rows []payeeRow, logger *log.Logger) []model.Payee {
+	var payees []model.Payee
+	for _, row := range rows {
+		payee, ok := parsePayee(row)
+		if !ok {
+			logger.Printf("Skipping payee row without BSB and account number: %q", row.Text)
+			continue
+		}
+		payees = append(payees, payee)
+	}
+	return payees
+}
+
+// parsePayee builds a payee from a scraped row, falling back to the row
+// text for fields the page doesn't label. Rows without a BSB and account
+// number (such as BPAY billers) are skipped.
+func parsePayee(row payeeRow) (model.Payee, bool) {
+	bsb, bsbText := digits(row.BSB), row.BSB
+	if bsb == "" {
+		if match := payeeBSBPattern.FindStringSubmatch(row.Text); match != nil {
+			bsb, bsbText = match[1]+match[2], match[0]
+		}
+	}
+
+	account := digits(row.Account)
+	if account == "" {
+		// The account number follows the BSB, which would otherwise match too
+		text := row.Text
+		if bsbText != "" {
+			text = strings.Replace(text, bsbText, "", 1)
+		}
+		account = payeeAccountPattern.FindString(text)
+	}
+
+	if len(bsb) != 6 || account == "" {
+		return model.Payee{}, false
+	}
+
+	name := strings.TrimSpace(row.Name)
+	if name == "" {
+		name = strings.TrimSpace(strings.SplitN(row.Text, "\n", 2)[0])
+	}
+
+	payee := model.Payee{
+		ID:            row.ID,
+		Name:          name,
+		BSB:           bsb,
+		AccountNumber: account,
+	}
+	if payee.ID == "" {
+		payee.ID = payeeID(bsb, account)
+	}
+	if nickname := strings.TrimSpace(row.Nickname); nickname != "" && nickname != name {
+		payee.Nickname = &nickname
+	}
+
+	return payee, true
+}
+
+// digits strips everything but digits from s
+func digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// payeeID derives a stable ID for payees that don't expose one
+func payeeID(bsb, account string) string {
+	sum := sha256.Sum256([]byte(bsb + "\x00" + account))
+	return "payee_" + hex.EncodeToString(sum[:])[:16]
+}
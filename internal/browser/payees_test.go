@@ -0,0 +1,36 @@
+package browser
+
+import (
+	"testing"
+)
+
+func TestParsePayee(t *testing.T) {
+	payee, ok := parsePayee(payeeRow{
+		Name:     "J SMITH",
+		Nickname: "Rent",
+		BSB:      "062-000",
+		Account:  "1234 5678",
+		Text:     "J SMITH\nRent\n062-000 1234 5678",
+	})
+	if !ok {
+		t.Fatal("expected payee")
+	}
+	if payee.BSB != "062000" || payee.AccountNumber != "12345678" {
+		t.Errorf("unexpected BSB/account %+v", payee)
+	}
+	if payee.Nickname == nil || *payee.Nickname != "Rent" {
+		t.Errorf("unexpected nickname %v", payee.Nickname)
+	}
+	if payee.ID == "" || payee.ID != payeeID("062000", "12345678") {
+		t.Errorf("expected derived ID, got %q", payee.ID)
+	}
+
+	fromText, ok := parsePayee(payeeRow{Text: "ACME PLUMBING PTY LTD\nBSB 033-001 Account 987654"})
+	if !ok || fromText.Name != "ACME PLUMBING PTY LTD" || fromText.BSB != "033001" || fromText.AccountNumber != "987654" {
+		t.Errorf("unexpected payee from text %+v", fromText)
+	}
+
+	if _, ok := parsePayee(payeeRow{Text: "AGL ENERGY\nBiller code 23796"}); ok {
+		t.Error("expected BPAY biller to be skipped")
+	}
+}
@@ -0,0 +1,196 @@
+package browser
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/chromedp/chromedp"
+)
+
+// payIDLinkSelectors locate the PayID settings page
+var payIDLinkSelectors = []string{
+	`a[href*="payid"]`,
+	`a[href*="PayID"]`,
+	`a[title*="PayID" i]`,
+	`[role="menuitem"][href*="payid" i]`,
+}
+
+var (
+	payIDEmailPattern    = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	payIDMobilePattern   = regexp.MustCompile(`(?:\+61\s?|0)4(?:\s?\d){8}`)
+	payIDABNPattern      = regexp.MustCompile(`(?i)\bABN[:\s]*((?:\d\s?){11})`)
+	payIDAccountPattern  = regexp.MustCompile(`\b\d{8,10}\b`)
+	payIDDisabledPattern = regexp.MustCompile(`(?i)\b(?:locked|disabled|suspended)\b`)
+	payIDTransferPattern = regexp.MustCompile(`(?i)\b(?:transferr?ing|being\s+transferred|porting)\b`)
+	payIDNamePattern     = regexp.MustCompile(`(?i)(?:name|shown\s+as)[:\s]+([^\n]+)`)
+)
+
+// payIDRow is a registered PayID read from the page
+type payIDRow struct {
+	ID      string `json:"id"`
+	Value   string `json:"value"`
+	Name    string `json:"name"`
+	Account string `json:"account"`
+	Status  string `json:"status"`
+	Text    string `json:"text"`
+}
+
+// extractPayIDsScript reads rows from the PayID settings list. Fields are
+// taken from labelled elements where NAB provides them, otherwise from the
+// row text.
+const extractPayIDsScript = `(() => {
+	const rows = Array.from(document.querySelectorAll(
+		'[class*="payid"] li, [class*="PayId"] li, table[class*="payid"] tbody tr, [data-payid]'));
+	const text = (row, selector) => {
+		const el = row.querySelector(selector);
+		return el ? (el.innerText || '').trim() : '';
+	};
+	return rows.map(row => ({
+		id: row.getAttribute('data-payid') || row.getAttribute('data-id') || '',
+		value: text(row, '[class*="value"], [class*="identifier"]'),
+		name: text(row, '[class*="name"]'),
+		account: row.getAttribute('data-account-id') || text(row, '[class*="account"]'),
+		status: text(row, '[class*="status"]'),
+		text: (row.innerText || '').trim(),
+	})).filter(row => row.text !== '');
+})()`
+
+// GetPayIDs scrapes the PayIDs registered with NAB and the accounts they
+// are linked to
+func (c *NABClient) GetPayIDs(ctx context.Context) ([]model.PayID, error) {
+	c.logger.Println("Scraping NAB PayIDs...")
+
+	var payIDs []model.PayID
+	err := c.runLoggedIn(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		if err := c.clickFirstVisible(ctx, payIDLinkSelectors); err != nil {
+			c.takeScreenshot(ctx, "payids_not_found")
+			return fmt.Errorf("could not find PayID settings: %w", err)
+		}
+		chromedp.Sleep(2 * time.Second).Do(ctx)
+
+		var rows []payIDRow
+		if err := chromedp.Evaluate(extractPayIDsScript, &rows).Do(ctx); err != nil {
+			return fmt.Errorf("failed to read PayIDs: %w", err)
+		}
+
+		c.recorder.record(ctx, recordPayIDs, "", rows)
+
+		payIDs = parsePayIDs(rows, c.logger)
+		return nil
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrape NAB PayIDs: %w", err)
+	}
+
+	c.logger.Printf("Successfully scraped %d PayIDs", len(payIDs))
+	return payIDs, nil
+}
+
+// parsePayIDs builds PayIDs from the scraped rows, skipping rows without a
+// recognisable PayID
+func parsePayIDs(rows []payIDRow, logger *log.Logger) []model.PayID {
+	var payIDs []model.PayID
+	for _, row := range rows {
+		payID, ok := parsePayID(row)
+		if !ok {
+			logger.Printf("Skipping PayID row without a recognisable PayID: %q", row.Text)
+			continue
+		}
+		payIDs = append(payIDs, payID)
+	}
+	return payIDs
+}
+
+// parsePayID builds a PayID from a scraped row, working out its type from
+// the value. Rows without a recognisable PayID are skipped.
+func parsePayID(row payIDRow) (model.PayID, bool) {
+	payIDType, value := "", ""
+	for _, text := range []string{row.Value, row.Text} {
+		if payIDType, value = classifyPayID(text); payIDType != "" {
+			break
+		}
+	}
+	if payIDType == "" {
+		return model.PayID{}, false
+	}
+
+	payID := model.PayID{
+		ID:     row.ID,
+		Type:   payIDType,
+		Value:  value,
+		Status: model.PayIDStatusActive,
+	}
+
+	if name := strings.TrimSpace(row.Name); name != "" {
+		payID.DisplayName = &name
+	} else if match := payIDNamePattern.FindStringSubmatch(row.Text); match != nil {
+		name := strings.TrimSpace(match[1])
+		payID.DisplayName = &name
+	}
+
+	account := payIDAccountPattern.FindString(row.Account)
+	if account == "" {
+		// Skip lines holding the PayID itself, as mobiles and ABNs look
+		// like account numbers
+		for _, line := range strings.Split(row.Text, "\n") {
+			if t, _ := classifyPayID(line); t != "" {
+				continue
+			}
+			if account = payIDAccountPattern.FindString(line); account != "" {
+				break
+			}
+		}
+	}
+	if account != "" {
+		payID.AccountID = &account
+	}
+
+	status := row.Status
+	if status == "" {
+		status = row.Text
+	}
+	switch {
+	case payIDTransferPattern.MatchString(status):
+		payID.Status = model.PayIDStatusTransferring
+	case payIDDisabledPattern.MatchString(status):
+		payID.Status = model.PayIDStatusDisabled
+	}
+
+	if payID.ID == "" {
+		payID.ID = payIDID(payIDType, value)
+	}
+
+	return payID, true
+}
+
+// classifyPayID finds a PayID in text, returning its type and normalised
+// value. Emails are lower-cased and mobiles and ABNs reduced to digits.
+func classifyPayID(text string) (string, string) {
+	if email := payIDEmailPattern.FindString(text); email != "" {
+		return model.PayIDTypeEmail, strings.ToLower(email)
+	}
+	if match := payIDABNPattern.FindStringSubmatch(text); match != nil {
+		return model.PayIDTypeABN, digits(match[1])
+	}
+	if mobile := payIDMobilePattern.FindString(text); mobile != "" {
+		mobile = digits(mobile)
+		if strings.HasPrefix(mobile, "61") {
+			mobile = "0" + mobile[2:]
+		}
+		return model.PayIDTypeMobile, mobile
+	}
+	return "", ""
+}
+
+// payIDID derives a stable ID for PayIDs that don't expose one
+func payIDID(payIDType, value string) string {
+	sum := sha256.Sum256([]byte(payIDType + "\x00" + value))
+	return "payid_" + hex.EncodeToString(sum[:])[:16]
+}
@@ -0,0 +1,45 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestParsePayID(t *testing.T) {
+	mobile, ok := parsePayID(payIDRow{
+		Value:   "+61 412 345 678",
+		Name:    "J SMITH",
+		Account: "NAB Classic Banking 87654321",
+		Text:    "+61 412 345 678\nJ SMITH\nNAB Classic Banking 87654321",
+	})
+	if !ok {
+		t.Fatal("expected PayID")
+	}
+	if mobile.Type != model.PayIDTypeMobile || mobile.Value != "0412345678" || mobile.Status != model.PayIDStatusActive {
+		t.Errorf("unexpected PayID %+v", mobile)
+	}
+	if mobile.AccountID == nil || *mobile.AccountID != "87654321" {
+		t.Errorf("unexpected account %v", mobile.AccountID)
+	}
+	if mobile.DisplayName == nil || *mobile.DisplayName != "J SMITH" || mobile.ID != payIDID(model.PayIDTypeMobile, "0412345678") {
+		t.Errorf("unexpected PayID %+v", mobile)
+	}
+
+	email, ok := parsePayID(payIDRow{Text: "J.Smith@Example.com\nLinked to 12345678\nLocked"})
+	if !ok || email.Type != model.PayIDTypeEmail || email.Value != "j.smith@example.com" || email.Status != model.PayIDStatusDisabled {
+		t.Errorf("unexpected PayID from text %+v", email)
+	}
+	if email.AccountID == nil || *email.AccountID != "12345678" {
+		t.Errorf("unexpected account from text %v", email.AccountID)
+	}
+
+	abn, ok := parsePayID(payIDRow{Text: "ABN 51 824 753 556\nTransferring to another institution"})
+	if !ok || abn.Type != model.PayIDTypeABN || abn.Value != "51824753556" || abn.Status != model.PayIDStatusTransferring || abn.AccountID != nil {
+		t.Errorf("unexpected ABN PayID %+v", abn)
+	}
+
+	if _, ok := parsePayID(payIDRow{Text: "You haven't registered a PayID"}); ok {
+		t.Error("expected row without a PayID to be skipped")
+	}
+}
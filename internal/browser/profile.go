@@ -0,0 +1,228 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/benrowe/nab-bank-api/internal/config"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/chromedp"
+)
+
+// DeviceProfile describes the browser a session presents itself as
+type DeviceProfile struct {
+	Name      string `json:"name"`
+	UserAgent string `json:"userAgent"`
+	Width     int64  `json:"width"`
+	Height    int64  `json:"height"`
+	Platform  string `json:"platform"`
+	Mobile    bool   `json:"mobile"`
+	Touch     bool   `json:"touch"`
+}
+
+// deviceProfiles are the built-in profiles selectable by name
+var deviceProfiles = map[string]DeviceProfile{
+	"windows-chrome": {
+		Name:      "windows-chrome",
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+		Width:     1920,
+		Height:    1080,
+		Platform:  "Win32",
+	},
+	"macos-chrome": {
+		Name:      "macos-chrome",
+		UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+		Width:     1440,
+		Height:    900,
+		Platform:  "MacIntel",
+	},
+	"linux-chrome": {
+		Name:      "linux-chrome",
+		UserAgent: "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+		Width:     1920,
+		Height:    1080,
+		Platform:  "Linux x86_64",
+	},
+	"iphone-safari": {
+		Name:      "iphone-safari",
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+		Width:     390,
+		Height:    844,
+		Platform:  "iPhone",
+		Mobile:    true,
+		Touch:     true,
+	},
+	"android-chrome": {
+		Name:      "android-chrome",
+		UserAgent: "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36",
+		Width:     412,
+		Height:    915,
+		Platform:  "Linux armv8l",
+		Mobile:    true,
+		Touch:     true,
+	},
+}
+
+// customProfile builds a desktop profile around a configured user agent
+func customProfile(userAgent string) DeviceProfile {
+	platform := "Linux x86_64"
+	switch {
+	case strings.Contains(userAgent, "Windows"):
+		platform = "Win32"
+	case strings.Contains(userAgent, "Macintosh"):
+		platform = "MacIntel"
+	}
+
+	return DeviceProfile{
+		Name:      "custom",
+		UserAgent: userAgent,
+		Width:     1920,
+		Height:    1080,
+		Platform:  platform,
+	}
+}
+
+// LookupDeviceProfiles resolves profile names to built-in profiles
+func LookupDeviceProfiles(names []string) ([]DeviceProfile, error) {
+	profiles := make([]DeviceProfile, 0, len(names))
+	for _, name := range names {
+		profile, ok := deviceProfiles[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown device profile %q", name)
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+// configuredProfiles returns the profiles named in the config, falling back
+// to a profile built from the configured user agent
+func configuredProfiles(cfg *config.NABConfig, logger *log.Logger) []DeviceProfile {
+	if len(cfg.DeviceProfiles) == 0 && cfg.BrowserStealth {
+		profiles, _ := LookupDeviceProfiles(stealthProfiles)
+		return profiles
+	}
+	if len(cfg.DeviceProfiles) == 0 {
+		return []DeviceProfile{customProfile(cfg.UserAgent)}
+	}
+
+	profiles, err := LookupDeviceProfiles(cfg.DeviceProfiles)
+	if err != nil {
+		logger.Printf("Ignoring device profiles: %v", err)
+		return []DeviceProfile{customProfile(cfg.UserAgent)}
+	}
+	return profiles
+}
+
+// allocatorOptions sets the browser window and user agent for the profile
+func (p DeviceProfile) allocatorOptions() []chromedp.ExecAllocatorOption {
+	return []chromedp.ExecAllocatorOption{
+		chromedp.UserAgent(p.UserAgent),
+		chromedp.WindowSize(int(p.Width), int(p.Height)),
+	}
+}
+
+// emulate applies the viewport, touch support and platform to the page
+func (p DeviceProfile) emulate() chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		var opts []chromedp.EmulateViewportOption
+		if p.Mobile {
+			opts = append(opts, chromedp.EmulateMobile)
+		}
+		if p.Touch {
+			opts = append(opts, chromedp.EmulateTouch)
+		}
+		if err := chromedp.EmulateViewport(p.Width, p.Height, opts...).Do(ctx); err != nil {
+			return err
+		}
+
+		return emulation.SetUserAgentOverride(p.UserAgent).WithPlatform(p.Platform).Do(ctx)
+	})
+}
+
+// profileRotator chooses the device profile for each browser session.
+// With rotation enabled each new session uses the next profile, until a
+// login succeeds; that profile is then pinned so NAB keeps seeing the same
+// device, and only released if a later login with it fails.
+type profileRotator struct {
+	profiles []DeviceProfile
+	rotate   bool
+
+	mu     sync.Mutex
+	next   int
+	pinned *DeviceProfile
+}
+
+func newProfileRotator(profiles []DeviceProfile, rotate bool) *profileRotator {
+	return &profileRotator{
+		profiles: profiles,
+		rotate:   rotate,
+	}
+}
+
+// pin fixes the profile used by every session, such as one restored from a
+// persisted session
+func (r *profileRotator) pin(profile DeviceProfile) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pinned = &profile
+}
+
+// unpin releases any pinned profile, such as after the session it
+// belonged to was logged out
+func (r *profileRotator) unpin() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pinned = nil
+}
+
+// pick returns the profile for a new session
+func (r *profileRotator) pick() DeviceProfile {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.pinned != nil {
+		return *r.pinned
+	}
+	if !r.rotate {
+		return r.profiles[0]
+	}
+
+	profile := r.profiles[r.next%len(r.profiles)]
+	r.next++
+	return profile
+}
+
+// replace swaps in new profiles for the sessions that follow. A pinned
+// profile is kept so the live session isn't logged out.
+func (r *profileRotator) replace(profiles []DeviceProfile, rotate bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.profiles = profiles
+	r.rotate = rotate
+	r.next = 0
+}
+
+// succeeded pins the profile after a successful login
+func (r *profileRotator) succeeded(profile DeviceProfile) {
+	r.pin(profile)
+}
+
+// failed releases the pin if the pinned profile could not log in,
+// reporting whether it was pinned
+func (r *profileRotator) failed(profile DeviceProfile) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.pinned != nil && r.pinned.Name == profile.Name {
+		r.pinned = nil
+		return true
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package browser
+
+import (
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/config"
+)
+
+func TestProfileRotatorPinsSuccessfulProfile(t *testing.T) {
+	profiles, err := LookupDeviceProfiles([]string{"windows-chrome", "iphone-safari"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotator := newProfileRotator(profiles, true)
+
+	first := rotator.pick()
+	second := rotator.pick()
+	if first.Name != "windows-chrome" || second.Name != "iphone-safari" {
+		t.Fatalf("expected rotation, got %s then %s", first.Name, second.Name)
+	}
+
+	rotator.succeeded(second)
+	for i := 0; i < 3; i++ {
+		if got := rotator.pick().Name; got != "iphone-safari" {
+			t.Fatalf("expected pinned profile, got %s", got)
+		}
+	}
+
+	rotator.failed(second)
+	if got := rotator.pick().Name; got != "windows-chrome" {
+		t.Errorf("expected rotation to resume after a failed pinned login, got %s", got)
+	}
+}
+
+func TestLookupDeviceProfiles(t *testing.T) {
+	if _, err := LookupDeviceProfiles([]string{"nokia-3310"}); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+
+	if profile := customProfile("Mozilla/5.0 (Windows NT 10.0; Win64; x64)"); profile.Platform != "Win32" {
+		t.Errorf("custom profile platform = %q", profile.Platform)
+	}
+}
+
+func TestStealthProfiles(t *testing.T) {
+	cfg := &config.NABConfig{BrowserStealth: true, UserAgent: "Mozilla/5.0 (X11; Linux x86_64) Chrome/91.0.4472.124"}
+	profiles := configuredProfiles(cfg, log.New(io.Discard, "", 0))
+	if len(profiles) != len(stealthProfiles) || profiles[0].Name != "windows-chrome" {
+		t.Errorf("expected stealth mode to use the built-in desktop profiles, got %+v", profiles)
+	}
+
+	for i := 0; i < 100; i++ {
+		if delay := keystrokeDelay(); delay < 60*time.Millisecond || delay >= 220*time.Millisecond {
+			t.Fatalf("keystroke delay %s out of range", delay)
+		}
+	}
+}
+
+func TestReloadSelectorsKeepsPinnedProfile(t *testing.T) {
+	logger := log.New(io.Discard, "", 0)
+	client := NewNABClient(&config.NABConfig{DeviceProfiles: []string{"windows-chrome"}}, logger).(*NABClient)
+	client.profiles.succeeded(client.profiles.pick())
+
+	client.ReloadSelectors(&config.NABConfig{DeviceProfiles: []string{"iphone-safari"}})
+	if got := client.profiles.pick().Name; got != "windows-chrome" {
+		t.Errorf("expected the live session's profile to stay pinned, got %s", got)
+	}
+
+	client.profiles.unpin()
+	if got := client.profiles.pick().Name; got != "iphone-safari" {
+		t.Errorf("expected the reloaded profile once unpinned, got %s", got)
+	}
+}
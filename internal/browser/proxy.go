@@ -0,0 +1,56 @@
+package browser
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/chromedp"
+)
+
+// proxyServer returns the proxy for Chrome's --proxy-server flag, which
+// takes the scheme, host and port but not credentials
+func proxyServer(proxy *url.URL) string {
+	return proxy.Scheme + "://" + proxy.Host
+}
+
+// authenticateProxy answers the proxy's authentication challenges with the
+// credentials from its URL. Chrome only hands out challenges through the
+// Fetch domain, which pauses every request while it's enabled, so paused
+// requests are continued straight away. Challenges from websites get
+// Chrome's default handling.
+func authenticateProxy(proxy *url.URL) chromedp.Action {
+	username := proxy.User.Username()
+	password, _ := proxy.User.Password()
+
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			switch e := ev.(type) {
+			case *fetch.EventRequestPaused:
+				go func() {
+					_ = fetch.ContinueRequest(e.RequestID).Do(targetExecutor(ctx))
+				}()
+			case *fetch.EventAuthRequired:
+				response := &fetch.AuthChallengeResponse{Response: fetch.AuthChallengeResponseResponseDefault}
+				if e.AuthChallenge.Source == fetch.AuthChallengeSourceProxy {
+					response = &fetch.AuthChallengeResponse{
+						Response: fetch.AuthChallengeResponseResponseProvideCredentials,
+						Username: username,
+						Password: password,
+					}
+				}
+				go func() {
+					_ = fetch.ContinueWithAuth(e.RequestID, response).Do(targetExecutor(ctx))
+				}()
+			}
+		})
+		return fetch.Enable().WithHandleAuthRequests(true).Do(ctx)
+	})
+}
+
+// targetExecutor runs CDP commands against the browser tab in ctx from
+// outside an action, such as in an event listener
+func targetExecutor(ctx context.Context) context.Context {
+	return cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Target)
+}
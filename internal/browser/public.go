@@ -0,0 +1,56 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/config"
+	"github.com/chromedp/chromedp"
+)
+
+// PageFetcher loads public NAB pages that don't need a login
+type PageFetcher struct {
+	config  *config.NABConfig
+	profile DeviceProfile
+	logger  *log.Logger
+}
+
+// NewPageFetcher creates a fetcher using the same browser settings as the
+// NAB client. Public pages don't need the persisted login, so the fetcher
+// never opens it and can't race the client saving it.
+func NewPageFetcher(cfg *config.NABConfig, logger *log.Logger) *PageFetcher {
+	public := *cfg
+	public.SessionDir = ""
+	return &PageFetcher{
+		config:  &public,
+		profile: configuredProfiles(cfg, logger)[0],
+		logger:  logger,
+	}
+}
+
+// FetchText loads the page and returns its rendered text
+func (f *PageFetcher) FetchText(ctx context.Context, url string) (string, error) {
+	f.logger.Printf("Fetching public page %s...", url)
+
+	browserCtx, cancel, err := newBrowserContext(ctx, f.config, f.profile, f.config.BrowserTimeout, f.logger)
+	if err != nil {
+		return "", err
+	}
+	defer cancel()
+
+	var text string
+	err = chromedp.Run(browserCtx,
+		f.profile.emulate(),
+		chromedp.Navigate(url),
+		chromedp.WaitVisible(`body`, chromedp.ByQuery),
+		chromedp.Sleep(2*time.Second), // Rates are often rendered client side
+		chromedp.Text(`body`, &text, chromedp.ByQuery),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+
+	return text, nil
+}
@@ -0,0 +1,211 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// ErrNoRecording is returned in replay mode when nothing was recorded for
+// an operation
+var ErrNoRecording = errors.New("no recording")
+
+// Recorded operations
+const (
+	recordAccounts          = "accounts"
+	recordMessages          = "messages"
+	recordPayees            = "payees"
+	recordScheduledPayments = "scheduled_payments"
+	recordDirectDebits      = "direct_debits"
+	recordPayIDs            = "payids"
+	recordCards             = "cards"
+	recordLoan              = "loan"
+	recordGoals             = "goals"
+	recordTransactions      = "transactions"
+)
+
+// Recording is a page captured while scraping: its URL and HTML, the pages
+// visited to reach it, and the data read from it that the parsers work on
+type Recording struct {
+	Operation  string          `json:"operation"`
+	Key        string          `json:"key,omitempty"`
+	URL        string          `json:"url"`
+	HTMLFile   string          `json:"htmlFile,omitempty"`
+	Navigation []string        `json:"navigation,omitempty"`
+	Data       json.RawMessage `json:"data"`
+	RecordedAt time.Time       `json:"recordedAt"`
+
+	// HTML is kept in a file beside the recording so it can be viewed
+	HTML string `json:"-"`
+}
+
+// recordingNamePattern matches characters not wanted in recording names
+var recordingNamePattern = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// recordingName is the file name, without extension, for an operation
+func recordingName(operation, key string) string {
+	if key == "" {
+		return operation
+	}
+	return operation + "_" + recordingNamePattern.ReplaceAllString(key, "_")
+}
+
+// writeRecording saves a recording as JSON, with its HTML beside it
+func writeRecording(dir string, recording Recording) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create recording directory: %w", err)
+	}
+
+	name := recordingName(recording.Operation, recording.Key)
+	if recording.HTML != "" {
+		recording.HTMLFile = name + ".html"
+		if err := os.WriteFile(filepath.Join(dir, recording.HTMLFile), []byte(recording.HTML), 0o600); err != nil {
+			return fmt.Errorf("failed to write recorded HTML: %w", err)
+		}
+	}
+
+	raw, err := json.MarshalIndent(recording, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode recording: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+".json"), raw, 0o600); err != nil {
+		return fmt.Errorf("failed to write recording: %w", err)
+	}
+	return nil
+}
+
+// readRecording loads a recording and its HTML
+func readRecording(dir, operation, key string) (*Recording, error) {
+	name := recordingName(operation, key)
+	raw, err := os.ReadFile(filepath.Join(dir, name+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w of %s in %s", ErrNoRecording, name, dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+
+	var recording Recording
+	if err := json.Unmarshal(raw, &recording); err != nil {
+		return nil, fmt.Errorf("failed to decode recording %s: %w", name, err)
+	}
+	if recording.HTMLFile != "" {
+		html, err := os.ReadFile(filepath.Join(dir, filepath.Base(recording.HTMLFile)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read recorded HTML: %w", err)
+		}
+		recording.HTML = string(html)
+	}
+	return &recording, nil
+}
+
+// decode unmarshals the recorded data
+func (r *Recording) decode(out interface{}) error {
+	if len(r.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(r.Data, out); err != nil {
+		return fmt.Errorf("failed to decode recorded %s: %w", r.Operation, err)
+	}
+	return nil
+}
+
+// navigationLog collects the pages a browser session visits
+type navigationLog struct {
+	mu   sync.Mutex
+	urls []string
+}
+
+type navigationLogKey struct{}
+
+// recordNavigation starts logging top-level navigations in the browser
+// session, returning a context carrying the log
+func recordNavigation(ctx context.Context) context.Context {
+	navigation := &navigationLog{}
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		if e, ok := ev.(*page.EventFrameNavigated); ok && e.Frame.ParentID == "" {
+			navigation.mu.Lock()
+			navigation.urls = append(navigation.urls, e.Frame.URL)
+			navigation.mu.Unlock()
+		}
+	})
+	return context.WithValue(ctx, navigationLogKey{}, navigation)
+}
+
+// navigationFrom returns the pages visited so far in the session
+func navigationFrom(ctx context.Context) []string {
+	navigation, ok := ctx.Value(navigationLogKey{}).(*navigationLog)
+	if !ok {
+		return nil
+	}
+	navigation.mu.Lock()
+	defer navigation.mu.Unlock()
+	return append([]string(nil), navigation.urls...)
+}
+
+// recorder saves the pages scrapers read when record mode is on. A nil
+// recorder records nothing.
+type recorder struct {
+	dir    string
+	logger *log.Logger
+}
+
+// newRecorder returns a recorder writing to dir, or nil if dir is empty
+func newRecorder(dir string, logger *log.Logger) *recorder {
+	if dir == "" {
+		return nil
+	}
+	return &recorder{dir: dir, logger: logger}
+}
+
+// record captures the current page along with the data read from it.
+// Failures are logged rather than failing the scrape.
+func (r *recorder) record(ctx context.Context, operation, key string, data interface{}) {
+	if r == nil {
+		return
+	}
+
+	recording := Recording{
+		Operation:  operation,
+		Key:        key,
+		Navigation: navigationFrom(ctx),
+		RecordedAt: time.Now(),
+	}
+	if err := chromedp.Run(ctx,
+		chromedp.Location(&recording.URL),
+		chromedp.OuterHTML(`html`, &recording.HTML, chromedp.ByQuery),
+	); err != nil {
+		r.logger.Printf("Failed to capture page for %s recording: %v", operation, err)
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		r.logger.Printf("Failed to encode %s recording: %v", operation, err)
+		return
+	}
+	recording.Data = raw
+
+	if err := writeRecording(r.dir, recording); err != nil {
+		r.logger.Printf("Failed to save %s recording: %v", operation, err)
+		return
+	}
+	r.logger.Printf("Recorded %s to %s", recordingName(operation, key), r.dir)
+
+	// The recording is replaced each scrape, so the page is also kept in
+	// the snapshot history for looking back at how it changed
+	if recording.HTML != "" {
+		if err := archiveSnapshot(r.dir, recordingName(operation, key), recording.URL, recording.HTML, recording.RecordedAt); err != nil {
+			r.logger.Printf("Failed to archive %s snapshot: %v", operation, err)
+		}
+	}
+}
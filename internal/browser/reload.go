@@ -0,0 +1,12 @@
+package browser
+
+import "github.com/benrowe/nab-bank-api/internal/config"
+
+// ReloadSelectors applies reloaded interstitial dismissal rules and device
+// profiles to the sessions that follow. The live browser session and its
+// pinned profile are kept, so nothing has to log in again.
+func (c *NABClient) ReloadSelectors(cfg *config.NABConfig) {
+	rules := configuredDismissalRules(cfg, c.logger)
+	c.interstitials.Store(&rules)
+	c.profiles.replace(configuredProfiles(cfg, c.logger), cfg.RotateProfiles || cfg.BrowserStealth)
+}
@@ -0,0 +1,154 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/benrowe/nab-bank-api/internal/config"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// ReplayClient serves scraped data from pages recorded with
+// BROWSER_RECORD_DIR, running the same parsers as the browser client
+// without launching Chrome. Only scrapes are replayed; transfers, payments
+// and other changes aren't supported.
+type ReplayClient struct {
+	dir    string
+	logger *log.Logger
+
+	// parser is a browser client that is never started, used for the
+	// parsing helpers it shares with live scrapes
+	parser *NABClient
+}
+
+// NewReplayClient creates a client replaying the recordings in dir
+func NewReplayClient(dir string, logger *log.Logger) service.BankProvider {
+	return &ReplayClient{
+		dir:    dir,
+		logger: logger,
+		parser: &NABClient{config: &config.NABConfig{}, logger: logger},
+	}
+}
+
+// load reads a recording and decodes its data into out
+func (r *ReplayClient) load(operation, key string, out interface{}) (*Recording, error) {
+	recording, err := readRecording(r.dir, operation, key)
+	if err != nil {
+		return nil, err
+	}
+	if err := recording.decode(out); err != nil {
+		return nil, err
+	}
+	r.logger.Printf("Replaying %s recorded at %s from %s", recordingName(operation, key), recording.RecordedAt.Format("2006-01-02 15:04:05"), recording.URL)
+	return recording, nil
+}
+
+// GetAccounts parses accounts from the recorded dashboard
+func (r *ReplayClient) GetAccounts(ctx context.Context) ([]model.Account, error) {
+	var tiles []string
+	recording, err := r.load(recordAccounts, "", &tiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay NAB accounts: %w", err)
+	}
+
+	accounts := parseAccountsHTML(recording.HTML)
+	r.parser.applyAccountTiles(accounts, tiles)
+	return accounts, nil
+}
+
+// GetAccountTransactions parses transactions from the recorded page for
+// an account
+func (r *ReplayClient) GetAccountTransactions(ctx context.Context, accountID string) ([]model.Transaction, error) {
+	var rows []transactionRow
+	if _, err := r.load(recordTransactions, accountID, &rows); err != nil {
+		return nil, fmt.Errorf("failed to replay NAB transactions: %w", err)
+	}
+	return parseTransactions(accountID, rows, r.logger), nil
+}
+
+// GetMessages parses messages from the recorded inbox
+func (r *ReplayClient) GetMessages(ctx context.Context) ([]model.Message, error) {
+	var inbox recordedInbox
+	if _, err := r.load(recordMessages, "", &inbox); err != nil {
+		return nil, fmt.Errorf("failed to replay NAB messages: %w", err)
+	}
+	return inbox.messages(), nil
+}
+
+// GetPayees parses payees from the recorded address book
+func (r *ReplayClient) GetPayees(ctx context.Context) ([]model.Payee, error) {
+	var rows []payeeRow
+	if _, err := r.load(recordPayees, "", &rows); err != nil {
+		return nil, fmt.Errorf("failed to replay NAB payees: %w", err)
+	}
+	return parsePayees(rows, r.logger), nil
+}
+
+// GetScheduledPayments parses scheduled payments from the recorded
+// upcoming payments page
+func (r *ReplayClient) GetScheduledPayments(ctx context.Context) ([]model.ScheduledPayment, error) {
+	var rows []scheduledPaymentRow
+	if _, err := r.load(recordScheduledPayments, "", &rows); err != nil {
+		return nil, fmt.Errorf("failed to replay NAB scheduled payments: %w", err)
+	}
+	return parseScheduledPayments(rows, r.logger), nil
+}
+
+// GetDirectDebits parses direct debits from the recorded page for an
+// account
+func (r *ReplayClient) GetDirectDebits(ctx context.Context, accountID string) ([]model.DirectDebit, error) {
+	var rows []directDebitRow
+	if _, err := r.load(recordDirectDebits, accountID, &rows); err != nil {
+		return nil, fmt.Errorf("failed to replay NAB direct debits: %w", err)
+	}
+	return parseDirectDebits(rows, r.logger), nil
+}
+
+// GetSavingsGoals parses savings goals from the recorded page for an
+// account
+func (r *ReplayClient) GetSavingsGoals(ctx context.Context, accountID string) ([]model.SavingsGoal, error) {
+	var rows []goalRow
+	if _, err := r.load(recordGoals, accountID, &rows); err != nil {
+		return nil, fmt.Errorf("failed to replay NAB savings goals: %w", err)
+	}
+	return parseGoals(rows, r.logger), nil
+}
+
+// GetPayIDs parses PayIDs from the recorded PayID settings page
+func (r *ReplayClient) GetPayIDs(ctx context.Context) ([]model.PayID, error) {
+	var rows []payIDRow
+	if _, err := r.load(recordPayIDs, "", &rows); err != nil {
+		return nil, fmt.Errorf("failed to replay NAB PayIDs: %w", err)
+	}
+	return parsePayIDs(rows, r.logger), nil
+}
+
+// GetCards parses cards from the recorded cards section
+func (r *ReplayClient) GetCards(ctx context.Context) ([]model.Card, error) {
+	var rows []cardRow
+	if _, err := r.load(recordCards, "", &rows); err != nil {
+		return nil, fmt.Errorf("failed to replay NAB cards: %w", err)
+	}
+	return parseCards(rows, r.logger), nil
+}
+
+// SetCardLocked always fails, as recordings can't be changed
+func (r *ReplayClient) SetCardLocked(ctx context.Context, card model.Card, locked bool) (*model.Card, error) {
+	return nil, fmt.Errorf("%w: cards can't be locked or unlocked in replay mode", service.ErrCardsUnsupported)
+}
+
+// GetLoanDetails parses loan details from the recorded loan account page
+func (r *ReplayClient) GetLoanDetails(ctx context.Context, accountID string) (*model.LoanDetails, error) {
+	var text string
+	if _, err := r.load(recordLoan, accountID, &text); err != nil {
+		return nil, fmt.Errorf("failed to replay NAB loan details: %w", err)
+	}
+
+	loan := parseLoanDetails(text)
+	if loan == nil {
+		return nil, fmt.Errorf("no loan details found for account %s", accountID)
+	}
+	return loan, nil
+}
@@ -0,0 +1,74 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestReplayClient(t *testing.T) {
+	client := NewReplayClient("testdata/recordings", log.New(io.Discard, "", 0)).(*ReplayClient)
+	ctx := context.Background()
+
+	accounts, err := client.GetAccounts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 2 || accounts[0].Balance.Amount != "2543.67" || accounts[1].Balance.Amount != "847.23" {
+		t.Fatalf("unexpected accounts %+v", accounts)
+	}
+	if accounts[0].Interest == nil || accounts[1].Type != model.AccountTypeChecking {
+		t.Errorf("expected tiles to be applied, got %+v", accounts)
+	}
+
+	payees, err := client.GetPayees(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payees) != 1 || payees[0].BSB != "062000" || payees[0].AccountNumber != "12345678" {
+		t.Errorf("unexpected payees %+v", payees)
+	}
+
+	payments, err := client.GetScheduledPayments(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payments) != 1 || payments[0].Frequency != model.PaymentFrequencyFortnightly || payments[0].NextDate != "2023-11-14" {
+		t.Errorf("unexpected scheduled payments %+v", payments)
+	}
+
+	if _, err := client.GetMessages(ctx); !errors.Is(err, ErrNoRecording) {
+		t.Errorf("expected ErrNoRecording, got %v", err)
+	}
+}
+
+func TestRecordingRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	err := writeRecording(dir, Recording{
+		Operation: recordDirectDebits,
+		Key:       "87654321",
+		URL:       "https://ib.nab.com.au/nabib/directdebits.ctl",
+		HTML:      "<html><body>AGL</body></html>",
+		Data:      []byte(`[{"merchant":"AGL SALES PTY LTD","text":"AGL SALES PTY LTD"}]`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recording, err := readRecording(dir, recordDirectDebits, "87654321")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recording.HTMLFile != "direct_debits_87654321.html" || recording.HTML != "<html><body>AGL</body></html>" {
+		t.Errorf("unexpected recording %+v", recording)
+	}
+
+	var rows []directDebitRow
+	if err := recording.decode(&rows); err != nil || len(rows) != 1 || rows[0].Merchant != "AGL SALES PTY LTD" {
+		t.Errorf("unexpected rows %+v, %v", rows, err)
+	}
+}
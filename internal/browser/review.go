@@ -0,0 +1,48 @@
+package browser
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/chromedp/chromedp"
+)
+
+var (
+	reviewFeePattern   = regexp.MustCompile(`(?i)\bfees?\b[^\d$\n]*\$\s*([\d,]+\.\d{2})`)
+	reviewNoFeePattern = regexp.MustCompile(`(?i)\b(?:no|nil|free\s+of)\s+(?:charge|fees?)\b|\bfees?[:\s]*(?:none|nil|free)\b`)
+	reviewDatePattern  = regexp.MustCompile(`(?i)(?:processing|payment|transfer|debit)\s+date[:\s]*(today|\d{1,2}\s+[A-Za-z]{3,9}\s+\d{4}|\d{1,2}/\d{1,2}/\d{4}|\d{4}-\d{2}-\d{2})`)
+	whitespacePattern  = regexp.MustCompile(`\s+`)
+)
+
+// readReview reads NAB's review screen for a transfer or payment. It never
+// fails the transfer; a review that can't be read is left out.
+func (c *NABClient) readReview(ctx context.Context) *model.PaymentReview {
+	var text string
+	if err := chromedp.Text(`body`, &text, chromedp.ByQuery).Do(ctx); err != nil {
+		c.logger.Printf("Failed to read review screen: %v", err)
+		return nil
+	}
+	return parseReview(text, time.Now())
+}
+
+// parseReview extracts the fee and processing date from review screen
+// text. A processing date of today is given as now's date in NAB's time
+// zone.
+func parseReview(text string, now time.Time) *model.PaymentReview {
+	review := &model.PaymentReview{
+		Fee:  findAmount(reviewFeePattern, text),
+		Text: strings.TrimSpace(whitespacePattern.ReplaceAllString(text, " ")),
+	}
+	if review.Fee == nil && reviewNoFeePattern.MatchString(text) {
+		review.Fee = &model.Money{Amount: "0.00"}
+	}
+
+	if match := reviewDatePattern.FindStringSubmatch(text); match != nil {
+		date := parseDisplayDateAt(match[1], now)
+		review.ProcessingDate = &date
+	}
+	return review
+}
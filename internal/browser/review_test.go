@@ -0,0 +1,39 @@
+package browser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseReview(t *testing.T) {
+	now := time.Date(2024, 5, 17, 9, 30, 0, 0, time.UTC)
+
+	review := parseReview(`Review your transfer
+From Complete Access 5678
+To  Savings   4321
+Amount $1,250.00
+Fee $2.50
+Transfer date 20/05/2024`, now)
+	if review.Fee == nil || review.Fee.Amount != "2.50" {
+		t.Errorf("unexpected fee %v", review.Fee)
+	}
+	if review.ProcessingDate == nil || *review.ProcessingDate != "2024-05-20" {
+		t.Errorf("unexpected processing date %v", review.ProcessingDate)
+	}
+	if review.Text != "Review your transfer From Complete Access 5678 To Savings 4321 Amount $1,250.00 Fee $2.50 Transfer date 20/05/2024" {
+		t.Errorf("unexpected text %q", review.Text)
+	}
+
+	review = parseReview("Amount $120.00\nNo fee\nPayment date Today", now)
+	if review.Fee == nil || review.Fee.Amount != "0.00" {
+		t.Errorf("expected no fee to be a zero fee, got %v", review.Fee)
+	}
+	if review.ProcessingDate == nil || *review.ProcessingDate != "2024-05-17" {
+		t.Errorf("expected today's date, got %v", review.ProcessingDate)
+	}
+
+	review = parseReview("Amount $120.00", now)
+	if review.Fee != nil || review.ProcessingDate != nil {
+		t.Errorf("expected no fee or date when not shown, got %+v", review)
+	}
+}
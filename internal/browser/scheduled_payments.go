@@ -0,0 +1,210 @@
+package browser
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/chromedp/chromedp"
+)
+
+// scheduledPaymentsLinkSelectors locate the upcoming payments page
+var scheduledPaymentsLinkSelectors = []string{
+	`a[href*="scheduled"]`,
+	`a[href*="future-dated"]`,
+	`a[href*="upcoming"]`,
+	`a[title*="Scheduled" i]`,
+	`a[title*="Upcoming" i]`,
+	`[role="menuitem"][href*="scheduled"]`,
+}
+
+var (
+	scheduledAmountPattern    = regexp.MustCompile(`\$\s*([\d,]+\.\d{2})`)
+	scheduledDatePattern      = regexp.MustCompile(`\d{1,2}\s+[A-Za-z]{3,9}\s+\d{4}|\d{1,2}/\d{1,2}/\d{4}|\d{4}-\d{2}-\d{2}`)
+	scheduledEndDatePattern   = regexp.MustCompile(`(?i)(?:end(?:s|\s+date)?|until|final\s+payment)[:\s]*(\d{1,2}\s+[A-Za-z]{3,9}\s+\d{4}|\d{1,2}/\d{1,2}/\d{4}|\d{4}-\d{2}-\d{2})`)
+	scheduledAccountIDPattern = regexp.MustCompile(`\b\d{8,10}\b`)
+)
+
+// frequencyWords maps the wording NAB uses to scheduled payment frequencies.
+// Longer phrases come first so "every 2 weeks" isn't read as weekly.
+var frequencyWords = []struct {
+	word      string
+	frequency string
+}{
+	{"fortnight", model.PaymentFrequencyFortnightly},
+	{"every 2 weeks", model.PaymentFrequencyFortnightly},
+	{"quarter", model.PaymentFrequencyQuarterly},
+	{"every 3 months", model.PaymentFrequencyQuarterly},
+	{"week", model.PaymentFrequencyWeekly},
+	{"month", model.PaymentFrequencyMonthly},
+	{"year", model.PaymentFrequencyYearly},
+	{"annual", model.PaymentFrequencyYearly},
+}
+
+// scheduledPaymentRow is an upcoming payment read from the page
+type scheduledPaymentRow struct {
+	ID          string `json:"id"`
+	Payee       string `json:"payee"`
+	Amount      string `json:"amount"`
+	Frequency   string `json:"frequency"`
+	NextDate    string `json:"nextDate"`
+	EndDate     string `json:"endDate"`
+	From        string `json:"from"`
+	Description string `json:"description"`
+	Text        string `json:"text"`
+}
+
+// extractScheduledPaymentsScript reads rows from the upcoming payments
+// list. Fields are taken from labelled elements where NAB provides them,
+// otherwise from the row text.
+const extractScheduledPaymentsScript = `(() => {
+	const rows = Array.from(document.querySelectorAll(
+		'[class*="scheduled"] li, [class*="upcoming"] li, table[class*="scheduled"] tbody tr, table[class*="payments"] tbody tr, [data-payment-id]'));
+	const text = (row, selector) => {
+		const el = row.querySelector(selector);
+		return el ? (el.innerText || '').trim() : '';
+	};
+	return rows.map(row => ({
+		id: row.getAttribute('data-payment-id') || row.getAttribute('data-id') || '',
+		payee: text(row, '[class*="payee"], [class*="to-account"], [class*="toAccount"]'),
+		amount: text(row, '[class*="amount"]'),
+		frequency: text(row, '[class*="frequency"]'),
+		nextDate: text(row, '[class*="next"], [class*="date"]'),
+		endDate: text(row, '[class*="end"]'),
+		from: row.getAttribute('data-account-id') || text(row, '[class*="from"]'),
+		description: text(row, '[class*="description"], [class*="reference"]'),
+		text: (row.innerText || '').trim(),
+	})).filter(row => row.text !== '');
+})()`
+
+// GetScheduledPayments scrapes future-dated and recurring payments from
+// NAB's upcoming payments page
+func (c *NABClient) GetScheduledPayments(ctx context.Context) ([]model.ScheduledPayment, error) {
+	c.logger.Println("Scraping NAB scheduled payments...")
+
+	var payments []model.ScheduledPayment
+	err := c.runLoggedIn(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		if err := c.clickFirstVisible(ctx, scheduledPaymentsLinkSelectors); err != nil {
+			c.takeScreenshot(ctx, "scheduled_payments_not_found")
+			return fmt.Errorf("could not find scheduled payments: %w", err)
+		}
+		chromedp.Sleep(2 * time.Second).Do(ctx)
+
+		var rows []scheduledPaymentRow
+		if err := chromedp.Evaluate(extractScheduledPaymentsScript, &rows).Do(ctx); err != nil {
+			return fmt.Errorf("failed to read scheduled payments: %w", err)
+		}
+
+		c.recorder.record(ctx, recordScheduledPayments, "", rows)
+
+		payments = parseScheduledPayments(rows, c.logger)
+		return nil
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrape NAB scheduled payments: %w", err)
+	}
+
+	c.logger.Printf("Successfully scraped %d scheduled payments", len(payments))
+	return payments, nil
+}
+
+// parseScheduledPayments builds scheduled payments from the scraped rows,
+// skipping rows that aren't payments
+func parseScheduledPayments(rows []scheduledPaymentRow, logger *log.Logger) []model.ScheduledPayment {
+	var payments []model.ScheduledPayment
+	for _, row := range rows {
+		payment, ok := parseScheduledPayment(row)
+		if !ok {
+			logger.Printf("Skipping scheduled payment row without amount and date: %q", row.Text)
+			continue
+		}
+		payments = append(payments, payment)
+	}
+	return payments
+}
+
+// parseScheduledPayment builds a scheduled payment from a scraped row,
+// falling back to the row text for fields the page doesn't label. Rows
+// without an amount and next date are skipped.
+func parseScheduledPayment(row scheduledPaymentRow) (model.ScheduledPayment, bool) {
+	amount := findAmount(scheduledAmountPattern, row.Amount)
+	if amount == nil {
+		amount = findAmount(scheduledAmountPattern, row.Text)
+	}
+
+	nextDate := scheduledDatePattern.FindString(row.NextDate)
+	if nextDate == "" {
+		nextDate = scheduledDatePattern.FindString(row.Text)
+	}
+	if amount == nil || nextDate == "" {
+		return model.ScheduledPayment{}, false
+	}
+
+	payee := strings.TrimSpace(row.Payee)
+	if payee == "" {
+		payee = strings.TrimSpace(strings.SplitN(row.Text, "\n", 2)[0])
+	}
+
+	frequency := parseFrequency(row.Frequency)
+	if frequency == "" {
+		frequency = parseFrequency(row.Text)
+	}
+	if frequency == "" {
+		frequency = model.PaymentFrequencyOnce
+	}
+
+	payment := model.ScheduledPayment{
+		ID:        row.ID,
+		Payee:     payee,
+		Amount:    *amount,
+		Frequency: frequency,
+		NextDate:  parseDisplayDate(nextDate),
+	}
+
+	if end := scheduledDatePattern.FindString(row.EndDate); end != "" {
+		endDate := parseDisplayDate(end)
+		payment.EndDate = &endDate
+	} else if match := scheduledEndDatePattern.FindStringSubmatch(row.Text); match != nil {
+		endDate := parseDisplayDate(match[1])
+		payment.EndDate = &endDate
+	}
+	if from := scheduledAccountIDPattern.FindString(row.From); from != "" {
+		payment.FromAccountID = &from
+	}
+	if description := strings.TrimSpace(row.Description); description != "" {
+		payment.Description = &description
+	}
+	if payment.ID == "" {
+		payment.ID = scheduledPaymentID(payee, amount.Amount, frequency, row.From)
+	}
+
+	return payment, true
+}
+
+// parseFrequency reads a payment frequency from NAB's wording, returning an
+// empty string if none is mentioned
+func parseFrequency(text string) string {
+	text = strings.ToLower(text)
+	if strings.Contains(text, "once") || strings.Contains(text, "one-off") || strings.Contains(text, "one off") {
+		return model.PaymentFrequencyOnce
+	}
+	for _, word := range frequencyWords {
+		if strings.Contains(text, word.word) {
+			return word.frequency
+		}
+	}
+	return ""
+}
+
+// scheduledPaymentID derives a stable ID for scheduled payments that don't
+// expose one. The next date is left out as it moves on after each payment.
+func scheduledPaymentID(payee, amount, frequency, from string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{payee, amount, frequency, from}, "\x00")))
+	return "sched_" + hex.EncodeToString(sum[:])[:16]
+}
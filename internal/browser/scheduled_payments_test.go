@@ -0,0 +1,43 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestParseScheduledPayment(t *testing.T) {
+	payment, ok := parseScheduledPayment(scheduledPaymentRow{
+		Payee:       "J SMITH",
+		Amount:      "$1,450.00",
+		Frequency:   "Every 2 weeks",
+		NextDate:    "14 Nov 2023",
+		From:        "NAB Classic Banking 87654321",
+		Description: "Rent",
+		Text:        "J SMITH\n$1,450.00\nEvery 2 weeks\n14 Nov 2023\nEnds 30 Jun 2024",
+	})
+	if !ok {
+		t.Fatal("expected scheduled payment")
+	}
+	if payment.Amount.Amount != "1450.00" || payment.Frequency != model.PaymentFrequencyFortnightly || payment.NextDate != "2023-11-14" {
+		t.Errorf("unexpected payment %+v", payment)
+	}
+	if payment.EndDate == nil || *payment.EndDate != "2024-06-30" {
+		t.Errorf("unexpected end date %v", payment.EndDate)
+	}
+	if payment.FromAccountID == nil || *payment.FromAccountID != "87654321" {
+		t.Errorf("unexpected from account %v", payment.FromAccountID)
+	}
+	if payment.ID != scheduledPaymentID("J SMITH", "1450.00", model.PaymentFrequencyFortnightly, "NAB Classic Banking 87654321") {
+		t.Errorf("expected derived ID, got %q", payment.ID)
+	}
+
+	fromText, ok := parseScheduledPayment(scheduledPaymentRow{Text: "ACME PLUMBING PTY LTD\nPay $385.00 on 02/12/2023"})
+	if !ok || fromText.Payee != "ACME PLUMBING PTY LTD" || fromText.Frequency != model.PaymentFrequencyOnce || fromText.NextDate != "2023-12-02" {
+		t.Errorf("unexpected payment from text %+v", fromText)
+	}
+
+	if _, ok := parseScheduledPayment(scheduledPaymentRow{Text: "No upcoming payments"}); ok {
+		t.Error("expected row without amount to be skipped")
+	}
+}
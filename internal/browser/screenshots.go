@@ -0,0 +1,101 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// maxScreenshotLog caps how many screenshots are remembered
+const maxScreenshotLog = 100
+
+// screenshot is a saved screenshot and when it was taken
+type screenshot struct {
+	path  string
+	taken time.Time
+}
+
+// screenshotLog remembers the most recent screenshots so sync reports can
+// list those taken while they ran
+type screenshotLog struct {
+	mu      sync.Mutex
+	entries []screenshot
+}
+
+// add records a saved screenshot
+func (l *screenshotLog) add(path string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, screenshot{path: path, taken: time.Now()})
+	if len(l.entries) > maxScreenshotLog {
+		l.entries = l.entries[len(l.entries)-maxScreenshotLog:]
+	}
+}
+
+// redactPageScript blurs form fields, such as the username on the login
+// page, and any element whose own text looks like an amount, account
+// number or BSB, other than the troubleshooting banner
+const redactPageScript = `(() => {
+	const style = document.createElement('style');
+	style.id = 'nab-api-redact';
+	style.textContent = '[data-nab-api-redact] { filter: blur(8px) !important; }';
+	document.head.appendChild(style);
+	document.querySelectorAll('input:not([type="hidden"]), textarea, select')
+		.forEach(el => el.setAttribute('data-nab-api-redact', ''));
+	const sensitive = /\$\s?-?\d|\d[\d,]*\.\d{2}\b|\d{3}[\s-]?\d{3}/;
+	const walker = document.createTreeWalker(document.body, NodeFilter.SHOW_TEXT);
+	while (walker.nextNode()) {
+		const node = walker.currentNode;
+		if (node.parentElement && !node.parentElement.closest('#nab-troubleshoot-banner') && sensitive.test(node.textContent)) {
+			node.parentElement.setAttribute('data-nab-api-redact', '');
+		}
+	}
+	return true;
+})()`
+
+// restorePageScript undoes redactPageScript
+const restorePageScript = `(() => {
+	document.getElementById('nab-api-redact')?.remove();
+	document.querySelectorAll('[data-nab-api-redact]').forEach(el => el.removeAttribute('data-nab-api-redact'));
+	return true;
+})()`
+
+// captureScreenshot captures the page, first blurring anything sensitive
+// when BROWSER_SCREENSHOT_REDACT is set. A page that can't be redacted
+// isn't captured.
+func (c *NABClient) captureScreenshot(buf *[]byte) chromedp.Action {
+	if !c.config.ScreenshotRedact {
+		return chromedp.CaptureScreenshot(buf)
+	}
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		var ok bool
+		if err := chromedp.Evaluate(redactPageScript, &ok).Do(ctx); err != nil {
+			return fmt.Errorf("failed to redact page: %w", err)
+		}
+		err := chromedp.CaptureScreenshot(buf).Do(ctx)
+		if restoreErr := chromedp.Evaluate(restorePageScript, &ok).Do(ctx); err == nil {
+			err = restoreErr
+		}
+		return err
+	})
+}
+
+// Screenshots lists the screenshots saved between from and to; a zero to
+// means up to now
+func (c *NABClient) Screenshots(from, to time.Time) []string {
+	c.screenshots.mu.Lock()
+	defer c.screenshots.mu.Unlock()
+
+	paths := []string{}
+	for _, entry := range c.screenshots.entries {
+		if entry.taken.Before(from) || (!to.IsZero() && entry.taken.After(to)) {
+			continue
+		}
+		paths = append(paths, entry.path)
+	}
+	return paths
+}
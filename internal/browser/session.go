@@ -0,0 +1,348 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/config"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+)
+
+// runLoggedIn starts a browser, logs in to NAB internet banking and then
+// runs the given actions. An error bundle is saved if anything fails. The
+// browser is closed as soon as ctx is done, abandoning the remaining steps.
+func (c *NABClient) runLoggedIn(ctx context.Context, actions ...chromedp.Action) error {
+	sessionCtx, release, err := c.startSession(ctx, c.config.BrowserTimeout)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := chromedp.Run(sessionCtx, actions...); err != nil {
+		if ctx.Err() != nil {
+			return abortedError(ctx)
+		}
+		return c.bundleError(sessionCtx, "error", err)
+	}
+
+	return nil
+}
+
+// sessionProfileKey is the context key for the device profile a session
+// logged in with, so tabs opened later in it emulate the same device
+type sessionProfileKey struct{}
+
+// abortedError is the error for a browser session cut short because ctx,
+// the caller's context, was cancelled or ran out of time. Whatever step
+// was running when it happened failed because of it, so that failure says
+// nothing about NAB and isn't reported.
+func abortedError(ctx context.Context) error {
+	return fmt.Errorf("%w: %w", service.ErrRequestAborted, ctx.Err())
+}
+
+// startSession starts a browser and logs in to NAB internet banking,
+// returning a context bounded by timeout for running actions in the
+// logged in browser. The caller must call release to close the browser.
+func (c *NABClient) startSession(ctx context.Context, timeout time.Duration) (context.Context, func(), error) {
+	return c.openSession(ctx, timeout, true)
+}
+
+// startEphemeralSession is startSession in a copy of the persisted
+// session, for sessions held open a long time. NAB sees the same cookies
+// and device as every other login, but the persisted session is only held
+// while it is copied, so it doesn't hold up other browsers for long. The
+// copy is removed on release; the persisted session is neither saved nor
+// discarded on the way.
+func (c *NABClient) startEphemeralSession(ctx context.Context, timeout time.Duration) (context.Context, func(), error) {
+	return c.openSession(ctx, timeout, false)
+}
+
+// openSession starts a browser and logs in, in the persisted session if
+// persist is set and there is one
+func (c *NABClient) openSession(ctx context.Context, timeout time.Duration, persist bool) (context.Context, func(), error) {
+	if until, paused := c.pause.active(); paused {
+		return nil, nil, fmt.Errorf("%w until %s: NAB terms and conditions must be accepted", service.ErrScrapingPaused, until.Format(time.RFC3339))
+	}
+
+	// Chrome locks its user data directory, so persisted sessions are used
+	// one browser at a time. Sessions that don't persist run in a copy
+	// instead, holding the persisted session only while it is copied.
+	cfg := c.launchConfig()
+	done := func() {}
+	if cfg.SessionDir != "" {
+		if err := c.sessionLock.lock(ctx); err != nil {
+			return nil, nil, abortedError(ctx)
+		}
+		done = c.sessionLock.unlock
+	}
+	if !persist && cfg.SessionDir != "" {
+		dir, err := copySession(cfg)
+		done()
+		if err != nil {
+			return nil, nil, err
+		}
+		done = func() { os.RemoveAll(dir) }
+		ephemeral := *cfg
+		ephemeral.SessionDir = dir
+		cfg = &ephemeral
+	}
+	persist = persist && cfg.SessionDir != ""
+
+	profile := c.profiles.pick()
+	c.logger.Printf("Using device profile %s", profile.Name)
+
+	// A person solving a challenge needs longer than the session allows
+	timeoutCtx, cancel, err := newBrowserContext(ctx, cfg, profile, timeout+c.config.ChallengeWait, c.logger)
+	if err != nil {
+		done()
+		return nil, nil, err
+	}
+	timeoutCtx = recordConsole(timeoutCtx)
+	if c.recorder != nil {
+		timeoutCtx = recordNavigation(timeoutCtx)
+	}
+	timeoutCtx = context.WithValue(timeoutCtx, sessionProfileKey{}, profile)
+	stopped := c.health.started()
+	release := func() {
+		cancel()
+		stopped()
+		done()
+	}
+
+	login := []chromedp.Action{
+		profile.emulate(),
+
+		// Navigate to NAB homepage
+		c.timed(stepNavigate,
+			chromedp.Navigate(c.config.BaseURL),
+			chromedp.WaitVisible(`body`, chromedp.ByQuery),
+		),
+
+		// Click Login button in header
+		c.timed(stepClickLogin, c.clickLoginButton()),
+
+		// Select Internet Banking from dropdown
+		c.timed(stepSelectInternetBanking, c.selectInternetBanking()),
+
+		// Perform login
+		c.timed(stepFillCredentials, c.performLogin()),
+
+		// Wait for successful login
+		chromedp.WaitVisible(`body`, chromedp.ByQuery),
+		chromedp.Sleep(3 * time.Second), // Give time for page to load
+	}
+
+	if err := chromedp.Run(timeoutCtx, login...); err != nil {
+		// An abandoned login isn't held against the device profile
+		if ctx.Err() != nil {
+			release()
+			return nil, nil, abortedError(ctx)
+		}
+		// A challenge in place of the login form is the better explanation
+		// for not finding it
+		if challenge := chromedp.Run(timeoutCtx, c.checkChallenge()); errors.Is(challenge, service.ErrChallengeRequired) {
+			err = challenge
+		}
+		c.health.loggedIn(profile, err)
+		if c.profiles.failed(profile) && persist {
+			c.discardSession()
+		}
+		err = c.bundleError(timeoutCtx, "login_error", err)
+		release()
+		return nil, nil, err
+	}
+
+	if err := chromedp.Run(timeoutCtx, c.checkChallenge()); err != nil {
+		release()
+		if ctx.Err() != nil {
+			return nil, nil, abortedError(ctx)
+		}
+		c.health.loggedIn(profile, err)
+		return nil, nil, err
+	}
+
+	// A rejected login is down to the credentials rather than the device
+	// profile, so it isn't counted against the profile
+	if err := chromedp.Run(timeoutCtx, c.checkLoginRejected()); err != nil {
+		if ctx.Err() != nil {
+			release()
+			return nil, nil, abortedError(ctx)
+		}
+		c.health.loggedIn(profile, err)
+		err = c.bundleError(timeoutCtx, "login_rejected", err)
+		release()
+		return nil, nil, err
+	}
+	c.profiles.succeeded(profile)
+	c.health.loggedIn(profile, nil)
+	if persist {
+		c.saveSession(profile)
+	}
+
+	// Check for updated terms before closing surveys, promos and consent
+	// banners, so a terms screen is never dismissed as a popup
+	if err := chromedp.Run(timeoutCtx, c.checkTerms(), c.dismissInterstitials()); err != nil {
+		if ctx.Err() != nil {
+			release()
+			return nil, nil, abortedError(ctx)
+		}
+		err = c.bundleError(timeoutCtx, "error", err)
+		release()
+		return nil, nil, err
+	}
+
+	return timeoutCtx, release, nil
+}
+
+// loginRejectedScript returns the error NAB shows when it rejects the
+// credentials: an alert alongside the login form, which is still showing
+const loginRejectedScript = `(() => {
+	const visible = el => el.getClientRects().length > 0;
+	if (!Array.from(document.querySelectorAll('input[type="password"]')).some(visible)) return '';
+	const alert = Array.from(document.querySelectorAll('[role="alert"], [class*="error"], [class*="Error"]'))
+		.find(el => visible(el) && (el.innerText || '').trim() !== '');
+	return alert ? alert.innerText.trim() : '';
+})()`
+
+// checkLoginRejected fails with ErrAuthenticationFailed when NAB rejected
+// the username or password
+func (c *NABClient) checkLoginRejected() chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		var rejection string
+		if err := chromedp.Evaluate(loginRejectedScript, &rejection).Do(ctx); err != nil {
+			return fmt.Errorf("failed to check login result: %w", err)
+		}
+		if rejection != "" {
+			return fmt.Errorf("%w: NAB rejected the login: %s", service.ErrAuthenticationFailed, rejection)
+		}
+		return nil
+	})
+}
+
+// newBrowserContext starts a browser configured from cfg and the device
+// profile, and returns a context bounded by timeout. Cancelling it closes
+// the browser, or the session's tab when connected to a remote browser.
+// Failures to save the persisted profile on close are reported to logger.
+func newBrowserContext(ctx context.Context, cfg *config.NABConfig, profile DeviceProfile, timeout time.Duration, logger *log.Logger) (context.Context, context.CancelFunc, error) {
+	var proxy *url.URL
+	if cfg.BrowserProxyURL != "" {
+		var err error
+		if proxy, err = url.Parse(cfg.BrowserProxyURL); err != nil {
+			// The error would repeat the URL and any password in it
+			return nil, nil, fmt.Errorf("invalid BROWSER_PROXY_URL")
+		}
+	}
+
+	var browserCtx context.Context
+	var cancel context.CancelFunc
+	if cfg.BrowserRemoteURL != "" {
+		browserCtx, cancel = newRemoteBrowserContext(ctx, cfg.BrowserRemoteURL, proxy)
+	} else {
+		var err error
+		if browserCtx, cancel, err = newLocalBrowserContext(ctx, cfg, profile, proxy, logger); err != nil {
+			return nil, nil, err
+		}
+	}
+	timeoutCtx, cancelTimeout := context.WithTimeout(browserCtx, timeout)
+	release := func() {
+		cancelTimeout()
+		cancel()
+	}
+
+	if err := setUpTab(timeoutCtx, cfg, proxy); err != nil {
+		release()
+		return nil, nil, err
+	}
+	return timeoutCtx, release, nil
+}
+
+// setUpTab sets up proxy authentication and stealth mode in a new tab,
+// which Chrome applies tab by tab
+func setUpTab(ctx context.Context, cfg *config.NABConfig, proxy *url.URL) error {
+	if proxy != nil && proxy.User != nil {
+		if err := chromedp.Run(ctx, authenticateProxy(proxy)); err != nil {
+			return fmt.Errorf("failed to set up proxy authentication: %w", err)
+		}
+	}
+	if cfg.BrowserStealth {
+		if err := chromedp.Run(ctx, stealth(cfg)); err != nil {
+			return fmt.Errorf("failed to set up stealth mode: %w", err)
+		}
+	}
+	return nil
+}
+
+// newLocalBrowserContext launches Chrome, going through the proxy if
+// there is one. A persisted profile is saved once Chrome has exited.
+func newLocalBrowserContext(ctx context.Context, cfg *config.NABConfig, profile DeviceProfile, proxy *url.URL, logger *log.Logger) (context.Context, context.CancelFunc, error) {
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Flag("headless", cfg.BrowserHeadless),
+		chromedp.Flag("disable-gpu", true),
+		chromedp.Flag("no-sandbox", true),
+		chromedp.Flag("disable-dev-shm-usage", true),
+	)
+	opts = append(opts, profile.allocatorOptions()...)
+	closeProfile := func() error { return nil }
+	if cfg.SessionDir != "" {
+		dir, closeDir, err := openProfile(cfg, logger)
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, chromedp.UserDataDir(dir))
+		closeProfile = closeDir
+	}
+	if proxy != nil {
+		opts = append(opts, chromedp.ProxyServer(proxyServer(proxy)))
+	}
+	if cfg.BrowserStealth {
+		opts = append(opts, stealthAllocatorOptions(cfg)...)
+	}
+
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(ctx, opts...)
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+
+	return browserCtx, func() {
+		cancelBrowser()
+		cancelAlloc()
+		if err := closeProfile(); err != nil {
+			logger.Printf("Failed to save browser profile: %v", err)
+		}
+	}, nil
+}
+
+// newRemoteBrowserContext connects to a running Chrome, or a service like
+// browserless, over the DevTools protocol. Each session gets its own
+// browser context, going through the proxy if there is one, so cookies
+// aren't shared with anyone else using the browser and are discarded when
+// the session ends. The device profile is still applied by emulation after
+// connecting.
+func newRemoteBrowserContext(ctx context.Context, remoteURL string, proxy *url.URL) (context.Context, context.CancelFunc) {
+	var opts []chromedp.RemoteAllocatorOption
+	// chromedp looks up the browser's websocket URL from /json/version,
+	// which drops query parameters such as a browserless token
+	if parsed, err := url.Parse(remoteURL); err == nil && parsed.RawQuery != "" {
+		opts = append(opts, chromedp.NoModifyURL)
+	}
+
+	var contextOpts []chromedp.CreateBrowserContextOption
+	if proxy != nil {
+		contextOpts = append(contextOpts, func(p *target.CreateBrowserContextParams) *target.CreateBrowserContextParams {
+			return p.WithProxyServer(proxyServer(proxy))
+		})
+	}
+
+	allocCtx, cancelAlloc := chromedp.NewRemoteAllocator(ctx, remoteURL, opts...)
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx, chromedp.WithNewBrowserContext(contextOpts...))
+
+	return browserCtx, func() {
+		cancelBrowser()
+		cancelAlloc()
+	}
+}
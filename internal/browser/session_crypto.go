@@ -0,0 +1,283 @@
+package browser
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/benrowe/nab-bank-api/internal/config"
+)
+
+// errUnsealFailed is returned when a sealed file can't be decrypted with the
+// configured key
+var errUnsealFailed = errors.New("failed to decrypt")
+
+// Encrypted files inside the session directory
+const (
+	sealedStateFile  = sessionStateFile + ".enc"
+	sealedChromeFile = sessionChromeDir + ".tar.gz.enc"
+)
+
+// unsealedCacheDirs are Chrome caches left out of the sealed profile. They
+// only make the archive bigger and are rebuilt as pages load.
+var unsealedCacheDirs = map[string]bool{
+	"Cache":             true,
+	"Code Cache":        true,
+	"GPUCache":          true,
+	"GrShaderCache":     true,
+	"ShaderCache":       true,
+	"DawnCache":         true,
+	"GraphiteDawnCache": true,
+	"CacheStorage":      true,
+}
+
+// sessionCipher returns the cipher encrypting the persisted session, or
+// nil when BROWSER_SESSION_KEY isn't set
+func sessionCipher(cfg *config.NABConfig) (cipher.AEAD, error) {
+	if cfg.SessionKey == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(cfg.SessionKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts data, prefixing the random nonce. The file name is bound
+// in as additional data so sealed files can't be swapped for one another.
+func seal(aead cipher.AEAD, name string, data []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, data, []byte(name)), nil
+}
+
+// unseal decrypts data written by seal
+func unseal(aead cipher.AEAD, name string, sealed []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("sealed data is truncated")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	data, err := aead.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s, was BROWSER_SESSION_KEY changed?", errUnsealFailed, name)
+	}
+	return data, nil
+}
+
+// writeSealed encrypts data into name in dir, replacing it atomically
+func writeSealed(aead cipher.AEAD, dir, name string, data []byte) error {
+	sealed, err := seal(aead, name, data)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	tmp := filepath.Join(dir, name+".tmp")
+	if err := os.WriteFile(tmp, sealed, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return os.Rename(tmp, filepath.Join(dir, name))
+}
+
+// readSealed decrypts name in dir, returning os.ErrNotExist if it hasn't
+// been written
+func readSealed(aead cipher.AEAD, dir, name string) ([]byte, error) {
+	sealed, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	return unseal(aead, name, sealed)
+}
+
+// openProfile prepares the Chrome user data directory for a browser
+// launch. Without a session key that is the plain directory in SessionDir.
+// With one, the sealed profile is decrypted into a private temporary
+// directory for the browser's lifetime; close encrypts it back and removes
+// the plaintext. Profiles are only sealed while a session state is saved
+// with them, so a session discarded while the browser was open stays
+// discarded. An unencrypted profile left from before the key was set is
+// carried over and removed once sealed. A profile that can't be decrypted,
+// say because the key changed, is replaced by a fresh one.
+func openProfile(cfg *config.NABConfig, logger *log.Logger) (string, func() error, error) {
+	aead, err := sessionCipher(cfg)
+	if err != nil {
+		return "", nil, err
+	}
+	if aead == nil {
+		return chromeDataDir(cfg.SessionDir), func() error { return nil }, nil
+	}
+
+	dir, err := os.MkdirTemp("", "nab-session-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create browser profile directory: %w", err)
+	}
+	archive, err := readSealed(aead, cfg.SessionDir, sealedChromeFile)
+	if errors.Is(err, os.ErrNotExist) {
+		archive, err = archiveDir(chromeDataDir(cfg.SessionDir))
+	} else if errors.Is(err, errUnsealFailed) {
+		logger.Printf("Starting a fresh browser profile: %v", err)
+		archive, err = nil, nil
+	}
+	if err == nil && archive != nil {
+		err = extractArchive(archive, dir)
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", nil, fmt.Errorf("failed to restore browser profile: %w", err)
+	}
+
+	closeProfile := func() error {
+		defer os.RemoveAll(dir)
+		if state, err := loadSessionState(cfg); err != nil || state == nil {
+			return err
+		}
+		archive, err := archiveDir(dir)
+		if err != nil {
+			return fmt.Errorf("failed to archive browser profile: %w", err)
+		}
+		if err := writeSealed(aead, cfg.SessionDir, sealedChromeFile, archive); err != nil {
+			return err
+		}
+		return os.RemoveAll(chromeDataDir(cfg.SessionDir))
+	}
+	return dir, closeProfile, nil
+}
+
+// copySession copies the persisted browser profile, encrypted or not, into
+// a new session directory, leaving out caches and Chrome's locks. A
+// browser started from the copy carries the persisted session's cookies
+// and device identity without holding the persisted session. The caller
+// removes the copy once done; nothing in it is saved back.
+func copySession(cfg *config.NABConfig) (string, error) {
+	dir, err := os.MkdirTemp("", "nab-session-copy-")
+	if err != nil {
+		return "", fmt.Errorf("failed to copy browser profile: %w", err)
+	}
+	sealed, err := os.ReadFile(filepath.Join(cfg.SessionDir, sealedChromeFile))
+	if err == nil {
+		err = os.WriteFile(filepath.Join(dir, sealedChromeFile), sealed, 0o600)
+	} else if errors.Is(err, os.ErrNotExist) {
+		var archive []byte
+		if archive, err = archiveDir(chromeDataDir(cfg.SessionDir)); err == nil && archive != nil {
+			err = extractArchive(archive, chromeDataDir(dir))
+		}
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to copy browser profile: %w", err)
+	}
+	return dir, nil
+}
+
+// archiveDir packs the regular files under dir into a gzipped tarball,
+// skipping caches, or returns nil if dir doesn't exist. Symlinks, such as
+// Chrome's singleton locks, are left out.
+func archiveDir(dir string) ([]byte, error) {
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && unsealedCacheDirs[entry.Name()] {
+			return filepath.SkipDir
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(tw, file)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// extractArchive unpacks a tarball written by archiveDir into dir
+func extractArchive(archive []byte, dir string) error {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(path, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("archive entry %q is outside the profile", header.Name)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return err
+		}
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(file, tr)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
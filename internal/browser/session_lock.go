@@ -0,0 +1,32 @@
+package browser
+
+import (
+	"context"
+	"sync"
+)
+
+// sessionLock serialises use of the persisted session, as Chrome locks
+// its user data directory. Unlike a mutex, waiting for it is given up
+// when the caller's context is done, so a request abandoned while queued
+// never starts a browser. The zero value is unlocked.
+type sessionLock struct {
+	once sync.Once
+	held chan struct{}
+}
+
+// lock waits for the session, failing with the context's error if it is
+// done first
+func (l *sessionLock) lock(ctx context.Context) error {
+	l.once.Do(func() { l.held = make(chan struct{}, 1) })
+	select {
+	case l.held <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// unlock releases the session
+func (l *sessionLock) unlock() {
+	<-l.held
+}
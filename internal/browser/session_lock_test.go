@@ -0,0 +1,26 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSessionLock(t *testing.T) {
+	var l sessionLock
+	if err := l.lock(context.Background()); err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.lock(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected waiting on a held lock to time out, got %v", err)
+	}
+
+	l.unlock()
+	if err := l.lock(context.Background()); err != nil {
+		t.Fatalf("lock after unlock: %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package browser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/config"
+)
+
+// Files inside the session directory
+const (
+	sessionStateFile = "session.json"
+	sessionChromeDir = "chrome"
+)
+
+// sessionState is saved alongside the persisted browser profile so later
+// runs present the same device fingerprint that established the session
+type sessionState struct {
+	Profile DeviceProfile `json:"profile"`
+	SavedAt time.Time     `json:"savedAt"`
+}
+
+// chromeDataDir is the Chrome user data directory for a session directory
+func chromeDataDir(dir string) string {
+	return filepath.Join(dir, sessionChromeDir)
+}
+
+// loadSessionState reads the saved session, decrypting it if a session
+// key is configured, and returns nil if there is none. An unencrypted
+// session saved before the key was set is still read.
+func loadSessionState(cfg *config.NABConfig) (*sessionState, error) {
+	aead, err := sessionCipher(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var raw []byte
+	err = os.ErrNotExist
+	if aead != nil {
+		raw, err = readSealed(aead, cfg.SessionDir, sealedStateFile)
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		raw, err = os.ReadFile(filepath.Join(cfg.SessionDir, sessionStateFile))
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session state: %w", err)
+	}
+
+	var state sessionState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode session state: %w", err)
+	}
+	return &state, nil
+}
+
+// saveSessionState records the profile used by the persisted session,
+// encrypted if a session key is configured
+func saveSessionState(cfg *config.NABConfig, profile DeviceProfile) error {
+	raw, err := json.Marshal(sessionState{Profile: profile, SavedAt: time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to encode session state: %w", err)
+	}
+
+	dir := cfg.SessionDir
+	aead, err := sessionCipher(cfg)
+	if err != nil {
+		return err
+	}
+	if aead != nil {
+		if err := writeSealed(aead, dir, sealedStateFile, raw); err != nil {
+			return fmt.Errorf("failed to write session state: %w", err)
+		}
+		if err := os.Remove(filepath.Join(dir, sessionStateFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove unencrypted session state: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	tmp := filepath.Join(dir, sessionStateFile+".tmp")
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return fmt.Errorf("failed to write session state: %w", err)
+	}
+	return os.Rename(tmp, filepath.Join(dir, sessionStateFile))
+}
+
+// clearSessionState discards the persisted session so the next run starts
+// from a clean browser profile
+func clearSessionState(dir string) error {
+	for _, name := range []string{sessionStateFile, sealedStateFile, sealedChromeFile} {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove session state: %w", err)
+		}
+	}
+	if err := os.RemoveAll(chromeDataDir(dir)); err != nil {
+		return fmt.Errorf("failed to remove browser profile: %w", err)
+	}
+	return nil
+}
+
+// fingerprintDifferences lists what differs between the saved profile and
+// the one configuration would otherwise select
+func fingerprintDifferences(saved, configured DeviceProfile) []string {
+	var diffs []string
+	if saved.UserAgent != configured.UserAgent {
+		diffs = append(diffs, "user agent")
+	}
+	if saved.Width != configured.Width || saved.Height != configured.Height {
+		diffs = append(diffs, "viewport")
+	}
+	if saved.Platform != configured.Platform {
+		diffs = append(diffs, "platform")
+	}
+	if saved.Mobile != configured.Mobile || saved.Touch != configured.Touch {
+		diffs = append(diffs, "touch/mobile emulation")
+	}
+	return diffs
+}
+
+// restoreSession pins the device profile saved with a persisted session so
+// the browser keeps presenting the fingerprint NAB already knows. A warning
+// is logged when the configured profiles would have picked something else.
+func (c *NABClient) restoreSession(configured []DeviceProfile) {
+	if c.config.SessionDir == "" {
+		return
+	}
+
+	state, err := loadSessionState(c.config)
+	if err != nil {
+		c.logger.Printf("Ignoring saved browser session: %v", err)
+		return
+	}
+	if state == nil {
+		return
+	}
+
+	var match *DeviceProfile
+	for i := range configured {
+		if configured[i].Name == state.Profile.Name {
+			match = &configured[i]
+			break
+		}
+	}
+
+	if match == nil {
+		c.logger.Printf("Warning: saved browser session uses device profile %s, which is no longer configured; reusing it to keep the session valid", state.Profile.Name)
+	} else if diffs := fingerprintDifferences(state.Profile, *match); len(diffs) > 0 {
+		c.logger.Printf("Warning: configured %s for device profile %s differs from the saved browser session; reusing the saved fingerprint", strings.Join(diffs, ", "), state.Profile.Name)
+	}
+
+	c.logger.Printf("Restored browser session from %s using device profile %s", state.SavedAt.Format(time.RFC3339), state.Profile.Name)
+	c.profiles.pin(state.Profile)
+}
+
+// saveSession records the profile behind a successful login
+func (c *NABClient) saveSession(profile DeviceProfile) {
+	if c.config.SessionDir == "" {
+		return
+	}
+	if err := saveSessionState(c.config, profile); err != nil {
+		c.logger.Printf("Failed to save browser session: %v", err)
+	}
+}
+
+// discardSession removes a persisted session that can no longer log in
+func (c *NABClient) discardSession() {
+	if c.config.SessionDir == "" {
+		return
+	}
+	c.logger.Println("Discarding saved browser session after failed login")
+	if err := clearSessionState(c.config.SessionDir); err != nil {
+		c.logger.Printf("Failed to discard browser session: %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package browser
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/config"
+)
+
+func TestRestoreSessionReusesSavedProfile(t *testing.T) {
+	dir := t.TempDir()
+	saved := customProfile("Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/120.0")
+	cfg := &config.NABConfig{
+		UserAgent:  "Mozilla/5.0 (X11; Linux x86_64) Chrome/126.0",
+		SessionDir: dir,
+	}
+	if err := saveSessionState(cfg, saved); err != nil {
+		t.Fatal(err)
+	}
+
+	var logs bytes.Buffer
+	client := NewNABClient(cfg, log.New(&logs, "", 0)).(*NABClient)
+
+	if got := client.profiles.pick(); got.UserAgent != saved.UserAgent {
+		t.Errorf("expected saved user agent, got %q", got.UserAgent)
+	}
+	if !strings.Contains(logs.String(), "user agent") {
+		t.Errorf("expected fingerprint warning, got %q", logs.String())
+	}
+
+	if client.profiles.failed(saved) {
+		client.discardSession()
+	}
+	if state, err := loadSessionState(cfg); err != nil || state != nil {
+		t.Errorf("expected session to be discarded, got %+v, %v", state, err)
+	}
+}
+
+func TestLogoutClearsSavedSession(t *testing.T) {
+	dir := t.TempDir()
+	saved := customProfile("Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/120.0")
+	// A remote browser never used the saved profile, so nothing is launched
+	cfg := &config.NABConfig{SessionDir: dir, BrowserRemoteURL: "ws://localhost:9222"}
+	if err := saveSessionState(cfg, saved); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(chromeDataDir(dir), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewNABClient(cfg, log.New(io.Discard, "", 0)).(*NABClient)
+
+	loggedOut, cleared, err := client.Logout(context.Background())
+	if err != nil || loggedOut || !cleared {
+		t.Fatalf("expected session cleared without a NAB logout, got %v, %v, %v", loggedOut, cleared, err)
+	}
+	if state, err := loadSessionState(cfg); err != nil || state != nil {
+		t.Errorf("expected session state to be removed, got %+v, %v", state, err)
+	}
+	if _, err := os.Stat(chromeDataDir(dir)); !os.IsNotExist(err) {
+		t.Errorf("expected browser profile to be removed, got %v", err)
+	}
+	if client.profiles.pinned != nil {
+		t.Errorf("expected saved profile to be unpinned")
+	}
+}
+
+func TestSessionEncryptedAtRest(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.NABConfig{SessionDir: dir, SessionKey: base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32))}
+	saved := customProfile("Mozilla/5.0 (Windows NT 10.0; Win64; x64) Chrome/120.0")
+
+	// An unencrypted profile from before the key was set is carried over
+	if err := os.MkdirAll(filepath.Join(chromeDataDir(dir), "Default"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(chromeDataDir(dir), "Default", "Cookies"), []byte("session=secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	profileDir, closeProfile, err := openProfile(cfg, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := saveSessionState(cfg, saved); err != nil {
+		t.Fatal(err)
+	}
+	if err := closeProfile(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{sealedStateFile, sealedChromeFile} {
+		raw, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(raw, []byte("Chrome/120.0")) || bytes.Contains(raw, []byte("Cookies")) {
+			t.Errorf("expected %s to be encrypted", name)
+		}
+	}
+	for _, path := range []string{chromeDataDir(dir), filepath.Join(dir, sessionStateFile), profileDir} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected no plaintext left at %s, got %v", path, err)
+		}
+	}
+
+	if state, err := loadSessionState(cfg); err != nil || state == nil || state.Profile.UserAgent != saved.UserAgent {
+		t.Fatalf("expected the saved session back, got %+v, %v", state, err)
+	}
+	profileDir, closeProfile, err = openProfile(cfg, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeProfile()
+	if cookies, err := os.ReadFile(filepath.Join(profileDir, "Default", "Cookies")); err != nil || string(cookies) != "session=secret" {
+		t.Errorf("expected cookies restored, got %q, %v", cookies, err)
+	}
+
+	// A different key can't read the session, so starts afresh
+	cfg.SessionKey = base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{8}, 32))
+	if _, err := loadSessionState(cfg); err == nil {
+		t.Error("expected a different key to fail to decrypt the session")
+	}
+	freshDir, closeFresh, err := openProfile(cfg, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeFresh()
+	if _, err := os.Stat(filepath.Join(freshDir, "Default", "Cookies")); !os.IsNotExist(err) {
+		t.Errorf("expected a fresh profile, got %v", err)
+	}
+}
+
+func TestCopySessionLeavesPersistedSessionAlone(t *testing.T) {
+	for _, key := range []string{"", base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32))} {
+		dir := t.TempDir()
+		cfg := &config.NABConfig{SessionDir: dir, SessionKey: key}
+		for name, data := range map[string]string{"Default/Cookies": "session=secret", "Default/Cache/data_0": "cached"} {
+			path := filepath.Join(chromeDataDir(dir), filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if key != "" {
+			// Seal the profile as a logged in session would
+			_, closeProfile, err := openProfile(cfg, log.New(io.Discard, "", 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := saveSessionState(cfg, customProfile("Chrome/120.0")); err != nil {
+				t.Fatal(err)
+			}
+			if err := closeProfile(); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		copyDir, err := copySession(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		copyCfg := *cfg
+		copyCfg.SessionDir = copyDir
+		profileDir, closeProfile, err := openProfile(&copyCfg, log.New(io.Discard, "", 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cookies, err := os.ReadFile(filepath.Join(profileDir, "Default", "Cookies")); err != nil || string(cookies) != "session=secret" {
+			t.Errorf("expected the copy to carry the session's cookies, got %q, %v", cookies, err)
+		}
+		if _, err := os.Stat(filepath.Join(profileDir, "Default", "Cache")); !os.IsNotExist(err) {
+			t.Errorf("expected caches left out of the copy, got %v", err)
+		}
+
+		// Nothing the copy's browser does is saved back
+		if err := os.WriteFile(filepath.Join(profileDir, "Default", "Cookies"), []byte("session=other"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := closeProfile(); err != nil {
+			t.Fatal(err)
+		}
+		os.RemoveAll(copyDir)
+
+		profileDir, closeProfile, err = openProfile(cfg, log.New(io.Discard, "", 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cookies, err := os.ReadFile(filepath.Join(profileDir, "Default", "Cookies")); err != nil || string(cookies) != "session=secret" {
+			t.Errorf("expected the persisted session unchanged, got %q, %v", cookies, err)
+		}
+		closeProfile()
+	}
+}
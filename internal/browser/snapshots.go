@@ -0,0 +1,284 @@
+package browser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNoSnapshot is returned when no snapshot was archived for a recording
+// at the requested time
+var ErrNoSnapshot = errors.New("no snapshot")
+
+const (
+	// snapshotHistoryDir holds the snapshot history within the record
+	// directory, one subdirectory per recording
+	snapshotHistoryDir = "history"
+
+	// snapshotFullEvery is how many deltas are chained before a full
+	// snapshot is stored again, bounding the work to rebuild one
+	snapshotFullEvery = 50
+
+	// snapshotMaxCandidates limits the places in the previous snapshot
+	// checked for a match, since chunks like </div> repeat throughout a page
+	snapshotMaxCandidates = 16
+
+	snapshotTimeFormat = "20060102T150405.000000000Z"
+)
+
+// snapshot is an archived page. The first snapshot, and one in every
+// snapshotFullEvery after it, holds the full HTML; the rest hold the
+// changes from the snapshot before, which is named by Base.
+type snapshot struct {
+	RecordedAt time.Time    `json:"recordedAt"`
+	URL        string       `json:"url,omitempty"`
+	Base       string       `json:"base,omitempty"`
+	Depth      int          `json:"depth,omitempty"`
+	HTML       string       `json:"html,omitempty"`
+	Ops        []snapshotOp `json:"ops,omitempty"`
+}
+
+// snapshotOp either copies a run of chunks from the base snapshot or
+// inserts new HTML
+type snapshotOp struct {
+	Copy   []int  `json:"c,omitempty"`
+	Insert string `json:"i,omitempty"`
+}
+
+// SnapshotInfo describes an archived snapshot
+type SnapshotInfo struct {
+	RecordedAt time.Time `json:"recordedAt"`
+	URL        string    `json:"url"`
+	Full       bool      `json:"full"`
+	Size       int64     `json:"size"`
+}
+
+// snapshotDir is the history directory for a recording
+func snapshotDir(dir, name string) string {
+	return filepath.Join(dir, snapshotHistoryDir, name)
+}
+
+// snapshotFiles lists a recording's snapshot files, oldest first
+func snapshotFiles(dir, name string) ([]string, error) {
+	entries, err := os.ReadDir(snapshotDir(dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			files = append(files, entry.Name())
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// readSnapshot loads a snapshot file without rebuilding its HTML
+func readSnapshot(dir, name, file string) (*snapshot, error) {
+	raw, err := os.ReadFile(filepath.Join(snapshotDir(dir, name), file))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	var s snapshot
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot %s: %w", file, err)
+	}
+	return &s, nil
+}
+
+// rebuildSnapshot returns the full HTML of a snapshot, applying its chain
+// of deltas to the full snapshot it starts from
+func rebuildSnapshot(dir, name, file string) (*snapshot, string, error) {
+	s, err := readSnapshot(dir, name, file)
+	if err != nil {
+		return nil, "", err
+	}
+	if s.Base == "" {
+		return s, s.HTML, nil
+	}
+
+	_, base, err := rebuildSnapshot(dir, name, s.Base)
+	if err != nil {
+		return nil, "", err
+	}
+	html, err := applySnapshotOps(splitHTML(base), s.Ops)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to rebuild snapshot %s: %w", file, err)
+	}
+	return s, html, nil
+}
+
+// archiveSnapshot adds a page to a recording's history, as a delta
+// against the latest snapshot unless a full copy is due or smaller
+func archiveSnapshot(dir, name, url, html string, recordedAt time.Time) error {
+	files, err := snapshotFiles(dir, name)
+	if err != nil {
+		return err
+	}
+
+	next := snapshot{RecordedAt: recordedAt, URL: url, HTML: html}
+	if len(files) > 0 {
+		latest := files[len(files)-1]
+		previous, previousHTML, err := rebuildSnapshot(dir, name, latest)
+		if err != nil {
+			return err
+		}
+		if previous.Depth+1 < snapshotFullEvery {
+			ops := diffChunks(splitHTML(previousHTML), splitHTML(html))
+			if size, err := json.Marshal(ops); err == nil && len(size) < len(html) {
+				next = snapshot{RecordedAt: recordedAt, URL: url, Base: latest, Depth: previous.Depth + 1, Ops: ops}
+			}
+		}
+	}
+
+	raw, err := json.Marshal(next)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := os.MkdirAll(snapshotDir(dir, name), 0o700); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	file := recordedAt.UTC().Format(snapshotTimeFormat) + ".json"
+	if err := os.WriteFile(filepath.Join(snapshotDir(dir, name), file), raw, 0o600); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// ListSnapshots describes the snapshots archived for a recording, such as
+// "accounts" or "direct_debits_12345678", oldest first
+func ListSnapshots(dir, name string) ([]SnapshotInfo, error) {
+	files, err := snapshotFiles(dir, name)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]SnapshotInfo, 0, len(files))
+	for _, file := range files {
+		s, err := readSnapshot(dir, name, file)
+		if err != nil {
+			return nil, err
+		}
+		info := SnapshotInfo{RecordedAt: s.RecordedAt, URL: s.URL, Full: s.Base == ""}
+		if stat, err := os.Stat(filepath.Join(snapshotDir(dir, name), file)); err == nil {
+			info.Size = stat.Size()
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// LoadSnapshot returns the HTML of the latest snapshot of a recording
+// taken at or before the given time
+func LoadSnapshot(dir, name string, at time.Time) (string, error) {
+	files, err := snapshotFiles(dir, name)
+	if err != nil {
+		return "", err
+	}
+
+	cutoff := at.UTC().Format(snapshotTimeFormat) + ".json"
+	i := sort.Search(len(files), func(i int) bool { return files[i] > cutoff })
+	if i == 0 {
+		return "", fmt.Errorf("%w of %s at %s", ErrNoSnapshot, name, at.Format(time.RFC3339))
+	}
+
+	_, html, err := rebuildSnapshot(dir, name, files[i-1])
+	return html, err
+}
+
+// splitHTML breaks a page into chunks at each tag, so deltas follow the
+// page structure and an edit to one element leaves the rest matching
+func splitHTML(html string) []string {
+	var chunks []string
+	for len(html) > 0 {
+		next := strings.IndexByte(html[1:], '<')
+		if next < 0 {
+			chunks = append(chunks, html)
+			break
+		}
+		chunks = append(chunks, html[:next+1])
+		html = html[next+1:]
+	}
+	return chunks
+}
+
+// diffChunks describes target as runs copied from base and inserted HTML.
+// Each chunk is matched against its places in base, preferring the one
+// straight after the previous copy, and the longest run wins.
+func diffChunks(base, target []string) []snapshotOp {
+	index := make(map[string][]int, len(base))
+	for i, chunk := range base {
+		index[chunk] = append(index[chunk], i)
+	}
+
+	var ops []snapshotOp
+	var insert strings.Builder
+	flush := func() {
+		if insert.Len() > 0 {
+			ops = append(ops, snapshotOp{Insert: insert.String()})
+			insert.Reset()
+		}
+	}
+
+	expected := 0
+	for i := 0; i < len(target); {
+		candidates := index[target[i]]
+		if len(candidates) > snapshotMaxCandidates {
+			candidates = candidates[:snapshotMaxCandidates]
+		}
+		if expected < len(base) && base[expected] == target[i] {
+			candidates = append([]int{expected}, candidates...)
+		}
+
+		start, length := -1, 0
+		for _, candidate := range candidates {
+			n := 0
+			for candidate+n < len(base) && i+n < len(target) && base[candidate+n] == target[i+n] {
+				n++
+			}
+			if n > length {
+				start, length = candidate, n
+			}
+		}
+
+		if length == 0 {
+			insert.WriteString(target[i])
+			i++
+			continue
+		}
+		flush()
+		ops = append(ops, snapshotOp{Copy: []int{start, length}})
+		expected = start + length
+		i += length
+	}
+	flush()
+	return ops
+}
+
+// applySnapshotOps rebuilds HTML from the base chunks and a delta
+func applySnapshotOps(base []string, ops []snapshotOp) (string, error) {
+	var html strings.Builder
+	for _, op := range ops {
+		if op.Copy == nil {
+			html.WriteString(op.Insert)
+			continue
+		}
+		if len(op.Copy) != 2 || op.Copy[0] < 0 || op.Copy[1] < 0 || op.Copy[0]+op.Copy[1] > len(base) {
+			return "", fmt.Errorf("copy %v out of range of %d chunks", op.Copy, len(base))
+		}
+		for _, chunk := range base[op.Copy[0] : op.Copy[0]+op.Copy[1]] {
+			html.WriteString(chunk)
+		}
+	}
+	return html.String(), nil
+}
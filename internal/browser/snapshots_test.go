@@ -0,0 +1,72 @@
+package browser
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnapshotHistory(t *testing.T) {
+	page, err := os.ReadFile("testdata/recordings/accounts.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := strings.Repeat(string(page), 20)
+	second := strings.Replace(first, "$", "$1", 1)
+	third := "<html><body>Maintenance</body></html>"
+
+	dir := t.TempDir()
+	start := time.Date(2026, time.October, 16, 9, 0, 0, 0, time.UTC)
+	for i, html := range []string{first, second, third} {
+		if err := archiveSnapshot(dir, "accounts", "https://ib.nab.com.au/", html, start.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	infos, err := ListSnapshots(dir, "accounts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 3 || !infos[0].Full || infos[1].Full {
+		t.Fatalf("expected a full snapshot then a delta, got %+v", infos)
+	}
+	if infos[1].Size*10 > infos[0].Size {
+		t.Errorf("expected a small delta, got %d bytes against %d", infos[1].Size, infos[0].Size)
+	}
+
+	for i, want := range []string{first, second, third} {
+		got, err := LoadSnapshot(dir, "accounts", start.Add(time.Duration(i)*time.Hour+time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("snapshot %d didn't rebuild to the archived page", i)
+		}
+	}
+
+	if _, err := LoadSnapshot(dir, "accounts", start.Add(-time.Minute)); !errors.Is(err, ErrNoSnapshot) {
+		t.Errorf("expected ErrNoSnapshot before the first snapshot, got %v", err)
+	}
+}
+
+func TestDiffChunksRoundTrip(t *testing.T) {
+	base := `<div class="a"><span>one</span></div><div class="b"><span>two</span></div>`
+	tests := []string{
+		"",
+		base,
+		`<div class="b"><span>two</span></div><div class="a"><span>one</span></div>`,
+		`<div class="a"><span>one</span></div><p>new</p><div class="b"><span>three</span></div>`,
+		"plain text",
+	}
+	for _, target := range tests {
+		got, err := applySnapshotOps(splitHTML(base), diffChunks(splitHTML(base), splitHTML(target)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != target {
+			t.Errorf("round trip gave %q, want %q", got, target)
+		}
+	}
+}
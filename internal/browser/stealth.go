@@ -0,0 +1,90 @@
+package browser
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/config"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// stealthProfiles are rotated through in stealth mode when no device
+// profiles are configured, as the default user agent is years out of date
+var stealthProfiles = []string{"windows-chrome", "macos-chrome"}
+
+// stealthScript hides the giveaways of an automated headless Chrome before
+// any of NAB's scripts run
+const stealthScript = `(() => {
+	Object.defineProperty(Navigator.prototype, 'webdriver', { get: () => undefined });
+	Object.defineProperty(Navigator.prototype, 'languages', { get: () => LANGUAGES });
+	if (navigator.plugins.length === 0) {
+		Object.defineProperty(Navigator.prototype, 'plugins', { get: () => [
+			{ name: 'PDF Viewer', filename: 'internal-pdf-viewer', description: 'Portable Document Format' },
+			{ name: 'Chrome PDF Viewer', filename: 'internal-pdf-viewer', description: 'Portable Document Format' },
+		] });
+	}
+	if (!window.chrome) {
+		window.chrome = { runtime: {} };
+	}
+	const query = navigator.permissions && navigator.permissions.query;
+	if (query) {
+		navigator.permissions.query = params => params && params.name === 'notifications'
+			? Promise.resolve({ state: Notification.permission })
+			: query.call(navigator.permissions, params);
+	}
+})()`
+
+// stealthAllocatorOptions stops Chrome announcing that it's automated and
+// sets its language when it's launched locally
+func stealthAllocatorOptions(cfg *config.NABConfig) []chromedp.ExecAllocatorOption {
+	return []chromedp.ExecAllocatorOption{
+		chromedp.Flag("disable-blink-features", "AutomationControlled"),
+		chromedp.Flag("lang", cfg.BrowserLocale),
+	}
+}
+
+// stealth patches the automation giveaways in every page the session opens
+// and presents the configured locale and timezone, so the browser looks
+// like a customer at home in Australia rather than a server
+func stealth(cfg *config.NABConfig) chromedp.Action {
+	languages := `['` + cfg.BrowserLocale + `', 'en']`
+	script := strings.Replace(stealthScript, "LANGUAGES", languages, 1)
+
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if _, err := page.AddScriptToEvaluateOnNewDocument(script).Do(ctx); err != nil {
+			return err
+		}
+		if err := emulation.SetLocaleOverride().WithLocale(cfg.BrowserLocale).Do(ctx); err != nil {
+			return err
+		}
+		return emulation.SetTimezoneOverride(cfg.BrowserTimezone).Do(ctx)
+	})
+}
+
+// typeHumanly types text into the field a key at a time, pausing a random
+// time between keys as a person would
+func typeHumanly(selector, text string) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if err := chromedp.Click(selector, chromedp.ByQuery).Do(ctx); err != nil {
+			return err
+		}
+		for _, key := range text {
+			if err := chromedp.SendKeys(selector, string(key), chromedp.ByQuery).Do(ctx); err != nil {
+				return err
+			}
+			if err := chromedp.Sleep(keystrokeDelay()).Do(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// keystrokeDelay returns a random pause between 60ms and 220ms
+func keystrokeDelay() time.Duration {
+	return 60*time.Millisecond + time.Duration(rand.Int63n(int64(160*time.Millisecond)))
+}
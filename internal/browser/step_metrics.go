@@ -0,0 +1,124 @@
+package browser
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/chromedp/chromedp"
+)
+
+// Named steps of the login and scrape flow
+const (
+	stepNavigate              = "navigate"
+	stepClickLogin            = "click_login"
+	stepSelectInternetBanking = "select_internet_banking"
+	stepFillCredentials       = "fill_credentials"
+	stepScrapeAccounts        = "scrape_accounts"
+)
+
+// stepBuckets are the upper bounds, in seconds, of the step duration
+// histogram buckets
+var stepBuckets = []float64{0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 60}
+
+// maxStepErrorLength caps the last error kept for a step, as it is
+// reported as a metric label
+const maxStepErrorLength = 200
+
+// stepStats accumulates the timings of one step
+type stepStats struct {
+	count       int64
+	failures    int64
+	total       time.Duration
+	buckets     []int64
+	lastError   string
+	lastErrorAt time.Time
+}
+
+// stepMetrics times the steps of the login and scrape flow so it can be
+// seen which step is slowing down or failing over time
+type stepMetrics struct {
+	mu    sync.Mutex
+	steps map[string]*stepStats
+}
+
+// observe records a run of a step
+func (m *stepMetrics) observe(step string, took time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.steps == nil {
+		m.steps = make(map[string]*stepStats)
+	}
+	stats, ok := m.steps[step]
+	if !ok {
+		stats = &stepStats{buckets: make([]int64, len(stepBuckets))}
+		m.steps[step] = stats
+	}
+
+	stats.count++
+	stats.total += took
+	for i, bound := range stepBuckets {
+		if took.Seconds() <= bound {
+			stats.buckets[i]++
+		}
+	}
+	if err != nil {
+		stats.failures++
+		stats.lastError = err.Error()
+		if len(stats.lastError) > maxStepErrorLength {
+			stats.lastError = stats.lastError[:maxStepErrorLength]
+		}
+		stats.lastErrorAt = time.Now()
+	}
+}
+
+// snapshot returns the metrics of every step run so far, by step name
+func (m *stepMetrics) snapshot() []model.ScrapeStepMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	metrics := make([]model.ScrapeStepMetrics, 0, len(m.steps))
+	for step, stats := range m.steps {
+		metric := model.ScrapeStepMetrics{
+			Step:         step,
+			Count:        stats.count,
+			Failures:     stats.failures,
+			TotalSeconds: stats.total.Seconds(),
+			Buckets:      make([]model.DurationBucket, len(stepBuckets)),
+			LastError:    stats.lastError,
+		}
+		for i, bound := range stepBuckets {
+			metric.Buckets[i] = model.DurationBucket{UpperBound: bound, Count: stats.buckets[i]}
+		}
+		if !stats.lastErrorAt.IsZero() {
+			lastErrorAt := stats.lastErrorAt
+			metric.LastErrorAt = &lastErrorAt
+		}
+		metrics = append(metrics, metric)
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Step < metrics[j].Step })
+	return metrics
+}
+
+// timed runs actions as the named step, recording how long they took and
+// whether they failed. Steps cut short because the session was abandoned
+// aren't recorded, as their timings say nothing about NAB.
+func (c *NABClient) timed(step string, actions ...chromedp.Action) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		started := time.Now()
+		err := chromedp.Tasks(actions).Do(ctx)
+		if err == nil || ctx.Err() == nil {
+			c.steps.observe(step, time.Since(started), err)
+		}
+		return err
+	})
+}
+
+// StepMetrics reports the duration and failures of each step of the login
+// and scrape flow since startup
+func (c *NABClient) StepMetrics() []model.ScrapeStepMetrics {
+	return c.steps.snapshot()
+}
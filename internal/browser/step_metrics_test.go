@@ -0,0 +1,34 @@
+package browser
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStepMetrics(t *testing.T) {
+	var m stepMetrics
+	m.observe(stepClickLogin, 300*time.Millisecond, nil)
+	m.observe(stepClickLogin, 12*time.Second, errors.New("could not find login button"))
+	m.observe(stepNavigate, time.Second, nil)
+
+	metrics := m.snapshot()
+	if len(metrics) != 2 || metrics[0].Step != stepClickLogin || metrics[1].Step != stepNavigate {
+		t.Fatalf("expected click_login then navigate, got %+v", metrics)
+	}
+
+	login := metrics[0]
+	if login.Count != 2 || login.Failures != 1 || login.TotalSeconds != 12.3 {
+		t.Errorf("unexpected totals %+v", login)
+	}
+	if login.LastError != "could not find login button" || login.LastErrorAt == nil {
+		t.Errorf("expected the last error to be kept, got %q at %v", login.LastError, login.LastErrorAt)
+	}
+	counts := map[float64]int64{}
+	for _, bucket := range login.Buckets {
+		counts[bucket.UpperBound] = bucket.Count
+	}
+	if counts[0.25] != 0 || counts[0.5] != 1 || counts[10] != 1 || counts[20] != 2 || counts[60] != 2 {
+		t.Errorf("unexpected cumulative buckets %+v", login.Buckets)
+	}
+}
@@ -0,0 +1,156 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/chromedp/chromedp"
+)
+
+// maxSyncSessionLength bounds a sync's session, which stays logged in
+// while each of its accounts is scraped within BROWSER_TIMEOUT
+const maxSyncSessionLength = 30 * time.Minute
+
+// syncSession is a browser logged in to NAB for a sync. The account list
+// is read in the tab that logged in, and each account is opened in a tab
+// of its own, so several can be scraped at once over the one login.
+type syncSession struct {
+	client    *NABClient
+	ctx       context.Context
+	release   func()
+	dashboard string
+
+	// mu guards the tab that logged in
+	mu sync.Mutex
+}
+
+// OpenSession logs in to NAB for reading several accounts. The session
+// holds the persisted session, if there is one, until it is closed.
+func (c *NABClient) OpenSession(ctx context.Context) (service.ProviderSession, error) {
+	sessionCtx, release, err := c.startSession(ctx, maxSyncSessionLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to log in to NAB: %w", err)
+	}
+
+	var dashboard string
+	if err := chromedp.Run(sessionCtx, chromedp.Location(&dashboard)); err != nil {
+		release()
+		return nil, fmt.Errorf("failed to log in to NAB: %w", err)
+	}
+	return &syncSession{
+		client:    c,
+		ctx:       sessionCtx,
+		release:   release,
+		dashboard: dashboard,
+	}, nil
+}
+
+// GetAccounts scrapes the account list from the dashboard the session
+// logged in to
+func (s *syncSession) GetAccounts(ctx context.Context) ([]model.Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.client.logger.Println("Starting NAB account scraping...")
+	runCtx, cancel := context.WithTimeout(s.ctx, s.client.config.BrowserTimeout)
+	defer cancel()
+	defer context.AfterFunc(ctx, cancel)()
+
+	var accounts []model.Account
+	if err := chromedp.Run(runCtx, s.client.timed(stepScrapeAccounts, s.client.scrapeAccounts(&accounts))); err != nil {
+		if ctx.Err() != nil {
+			return nil, abortedError(ctx)
+		}
+		return nil, fmt.Errorf("failed to scrape NAB accounts: %w", s.client.bundleError(runCtx, "error", err))
+	}
+	s.client.logger.Printf("Successfully scraped %d accounts", len(accounts))
+	return accounts, nil
+}
+
+// GetAccountTransactions opens an account in a new tab and scrapes its
+// transactions. It reads through the session's own browser, never
+// runLoggedIn, which would wait for the session lock the sync holds.
+func (s *syncSession) GetAccountTransactions(ctx context.Context, accountID string) ([]model.Transaction, error) {
+	s.client.logger.Printf("Scraping transactions for account %s...", accountID)
+
+	var transactions []model.Transaction
+	err := s.inTab(ctx, func(ctx context.Context) error {
+		var err error
+		transactions, err = s.client.readTransactions(ctx, accountID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrape NAB transactions: %w", err)
+	}
+	return transactions, nil
+}
+
+// GetLoanDetails opens a loan account in a new tab and scrapes its rate,
+// repayment and redraw details
+func (s *syncSession) GetLoanDetails(ctx context.Context, accountID string) (*model.LoanDetails, error) {
+	s.client.logger.Printf("Scraping loan details for account %s...", accountID)
+
+	var text string
+	err := s.inTab(ctx, func(ctx context.Context) error {
+		var err error
+		text, err = s.client.readLoanPage(ctx, accountID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrape NAB loan details: %w", err)
+	}
+	return loanDetailsFrom(text, accountID)
+}
+
+// Close closes the session's browser
+func (s *syncSession) Close() {
+	s.release()
+}
+
+// inTab runs fn in a new tab of the session's browser, opened at the
+// dashboard and set up like the tab that logged in: the same device
+// profile, proxy and stealth mode. Tabs share the session's cookies, so
+// they are logged in too. The tab is closed once fn returns.
+func (s *syncSession) inTab(ctx context.Context, fn func(ctx context.Context) error) error {
+	c := s.client
+	tabCtx, cancelTab := chromedp.NewContext(s.ctx)
+	defer cancelTab()
+	tabCtx, cancel := context.WithTimeout(tabCtx, c.config.BrowserTimeout)
+	defer cancel()
+	defer context.AfterFunc(ctx, cancel)()
+	tabCtx = recordConsole(tabCtx)
+	if c.recorder != nil {
+		tabCtx = recordNavigation(tabCtx)
+	}
+
+	var proxy *url.URL
+	if c.config.BrowserProxyURL != "" {
+		// Already checked when the session's browser was started
+		proxy, _ = url.Parse(c.config.BrowserProxyURL)
+	}
+	if err := setUpTab(tabCtx, c.launchConfig(), proxy); err != nil {
+		return err
+	}
+
+	var actions []chromedp.Action
+	if profile, ok := s.ctx.Value(sessionProfileKey{}).(DeviceProfile); ok {
+		actions = append(actions, profile.emulate())
+	}
+	actions = append(actions,
+		chromedp.Navigate(s.dashboard),
+		chromedp.WaitVisible(`body`, chromedp.ByQuery),
+		chromedp.ActionFunc(fn),
+	)
+	if err := chromedp.Run(tabCtx, actions...); err != nil {
+		if ctx.Err() != nil {
+			return abortedError(ctx)
+		}
+		return c.bundleError(tabCtx, "error", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package browser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+var (
+	termPattern               = regexp.MustCompile(`(?i)(?:term[:\s]+(\d+)\s*(day|week|month|year)s?|(\d+)[\s-]*(day|week|month|year)s?\s+term)`)
+	maturityDatePattern       = regexp.MustCompile(`(?i)matur(?:ity\s+date|es|ing)(?:\s+on)?[:\s]*(\d{1,2}\s+[A-Za-z]{3,9}\s+\d{4}|\d{1,2}/\d{1,2}/\d{4}|\d{4}-\d{2}-\d{2})`)
+	interestAtMaturityPattern = regexp.MustCompile(`(?i)interest\s+(?:payable\s+|due\s+)?(?:at|on)\s+maturity[^\d$]*\$?\s*([\d,]+\.\d{2})`)
+)
+
+// parseTermDepositDetails extracts term deposit details from account text,
+// returning nil if no interest rate is shown
+func parseTermDepositDetails(text string) *model.TermDepositDetails {
+	rate := ratePattern.FindStringSubmatch(text)
+	if rate == nil {
+		return nil
+	}
+
+	deposit := &model.TermDepositDetails{
+		InterestRate:       rate[1],
+		InterestAtMaturity: findAmount(interestAtMaturityPattern, text),
+	}
+
+	if match := termPattern.FindStringSubmatch(text); match != nil {
+		count, unit := match[1], match[2]
+		if count == "" {
+			count, unit = match[3], match[4]
+		}
+		unit = strings.ToLower(unit)
+		if count != "1" {
+			unit += "s"
+		}
+		deposit.Term = count + " " + unit
+	}
+
+	if match := maturityDatePattern.FindStringSubmatch(text); match != nil {
+		maturity := parseDisplayDate(match[1])
+		deposit.MaturityDate = &maturity
+	}
+
+	return deposit
+}
@@ -0,0 +1,40 @@
+package browser
+
+import (
+	"testing"
+)
+
+func TestParseTermDepositDetails(t *testing.T) {
+	deposit := parseTermDepositDetails(`NAB Term Deposit
+Balance $25,000.00
+Interest rate 4.50% p.a.
+Term 12 months
+Maturity date 17 Oct 2024
+Interest at maturity $1,125.00`)
+
+	if deposit == nil {
+		t.Fatal("expected term deposit details")
+	}
+	if deposit.InterestRate != "4.50" || deposit.Term != "12 months" {
+		t.Errorf("unexpected rate/term %+v", deposit)
+	}
+	if deposit.MaturityDate == nil || *deposit.MaturityDate != "2024-10-17" {
+		t.Errorf("unexpected maturity date %v", deposit.MaturityDate)
+	}
+	if deposit.InterestAtMaturity == nil || deposit.InterestAtMaturity.Amount != "1125.00" {
+		t.Errorf("unexpected interest at maturity %v", deposit.InterestAtMaturity)
+	}
+
+	if got := parseTermDepositDetails("6 month term, 4.10% p.a., matures 01/03/2024"); got == nil || got.Term != "6 months" || got.MaturityDate == nil || *got.MaturityDate != "2024-03-01" {
+		t.Errorf("unexpected short form details %+v", got)
+	}
+
+	if parseTermDepositDetails("NAB Term Deposit $25,000.00") != nil {
+		t.Error("expected nil without an interest rate")
+	}
+
+	client := &NABClient{}
+	if got := client.extractAccountType("NAB Term Deposit\nSavings"); got != "term_deposit" {
+		t.Errorf("expected term deposit type, got %q", got)
+	}
+}
@@ -0,0 +1,94 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/chromedp/chromedp"
+)
+
+// termsScreenScript reports whether the page is asking for updated terms
+// and conditions to be accepted
+const termsScreenScript = `(() => {
+	const text = document.body ? document.body.innerText : '';
+	if (!/(updated|new|changes to (our|the|your))\s+(terms|conditions|terms and conditions)/i.test(text)) return false;
+	return Array.from(document.querySelectorAll('button, input[type="submit"], a[role="button"]'))
+		.some(el => el.getClientRects().length > 0 && /accept|i agree|agree and continue/i.test(el.innerText || el.value || ''));
+})()`
+
+// acceptTermsScript ticks any acknowledgement boxes and clicks the accept
+// button, returning whether one was found
+const acceptTermsScript = `(() => {
+	for (const box of document.querySelectorAll('input[type="checkbox"]')) {
+		if (box.getClientRects().length > 0 && !box.checked) box.click();
+	}
+	const button = Array.from(document.querySelectorAll('button, input[type="submit"], a[role="button"]'))
+		.find(el => el.getClientRects().length > 0 && /accept|i agree|agree and continue/i.test(el.innerText || el.value || ''));
+	if (!button) return false;
+	button.click();
+	return true;
+})()`
+
+// termsPause stops logins after NAB asks for updated terms to be accepted,
+// so the account isn't hammered with logins that can't get past the terms
+// screen. One login is let through after the pause to see if they have
+// been accepted manually.
+type termsPause struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// start pauses logins for d
+func (p *termsPause) start(d time.Duration) time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.until = time.Now().Add(d)
+	return p.until
+}
+
+// active reports whether logins are paused, and until when
+func (p *termsPause) active() (time.Time, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.until, time.Now().Before(p.until)
+}
+
+// checkTerms detects NAB's updated terms and conditions screen. The terms
+// are accepted if NAB_AUTO_ACCEPT_TERMS is set; otherwise scraping is
+// paused and ErrTermsAcceptanceRequired returned so the user can be told
+// to accept them manually.
+func (c *NABClient) checkTerms() chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		var found bool
+		if err := chromedp.Evaluate(termsScreenScript, &found).Do(ctx); err != nil {
+			c.logger.Printf("Failed to check for updated terms: %v", err)
+			return nil
+		}
+		if !found {
+			return nil
+		}
+
+		c.takeScreenshot(ctx, "terms_update")
+
+		if c.config.AutoAcceptTerms {
+			c.logger.Println("NAB is asking for updated terms and conditions; accepting them as NAB_AUTO_ACCEPT_TERMS is set")
+			var accepted bool
+			if err := chromedp.Evaluate(acceptTermsScript, &accepted).Do(ctx); err != nil {
+				return fmt.Errorf("failed to accept updated terms: %w", err)
+			}
+			if accepted {
+				return chromedp.Sleep(3 * time.Second).Do(ctx)
+			}
+			c.logger.Println("Could not find the button to accept updated terms")
+		}
+
+		until := c.pause.start(c.config.TermsRecheck)
+		c.logger.Printf("NAB is asking for updated terms and conditions; pausing scraping until %s", until.Format(time.RFC3339))
+		return service.ErrTermsAcceptanceRequired
+	})
+}
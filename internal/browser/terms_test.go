@@ -0,0 +1,29 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/config"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+func TestRunLoggedInPausedForTerms(t *testing.T) {
+	cfg := &config.NABConfig{TermsRecheck: time.Hour}
+	client := NewNABClient(cfg, log.New(io.Discard, "", 0)).(*NABClient)
+	client.pause.start(cfg.TermsRecheck)
+
+	// The browser is never started while paused
+	if _, err := client.GetAccounts(context.Background()); !errors.Is(err, service.ErrScrapingPaused) {
+		t.Fatalf("expected scraping to be paused, got %v", err)
+	}
+
+	client.pause.start(0)
+	if _, paused := client.pause.active(); paused {
+		t.Error("expected pause to have ended")
+	}
+}
@@ -0,0 +1,192 @@
+package browser
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/chromedp/chromedp"
+)
+
+var (
+	transactionDatePattern = regexp.MustCompile(`\d{1,2}\s+[A-Za-z]{3,9}\s+\d{2,4}|\d{1,2}/\d{1,2}/\d{2,4}|\d{4}-\d{2}-\d{2}`)
+
+	// transactionAmountPattern matches an amount as balancePattern does,
+	// along with a minus sign before it or "DR" after it marking a debit
+	transactionAmountPattern = regexp.MustCompile(`([-−]\s?)?` + balancePattern.String() + `(\s?DR\b)?`)
+)
+
+// transactionRow is a transaction read from an account's transaction list
+type transactionRow struct {
+	ID          string `json:"id"`
+	Date        string `json:"date"`
+	Description string `json:"description"`
+	Debit       string `json:"debit"`
+	Credit      string `json:"credit"`
+	Amount      string `json:"amount"`
+	Balance     string `json:"balance"`
+	Text        string `json:"text"`
+}
+
+// extractTransactionsScript reads transactions from an account's
+// transaction list. Fields are taken from labelled cells where NAB
+// provides them, otherwise from the row's text.
+const extractTransactionsScript = `(() => {
+	const rows = Array.from(document.querySelectorAll(
+		'[data-transaction-id], [class*="transaction-list"] li, table[class*="transaction" i] tbody tr'));
+	const text = (row, selector) => {
+		const el = row.querySelector(selector);
+		return el ? (el.innerText || '').trim() : '';
+	};
+	return rows.map(row => ({
+		id: row.getAttribute('data-transaction-id') || '',
+		date: text(row, '[class*="date" i]'),
+		description: text(row, '[class*="description" i], [class*="narrative" i]'),
+		debit: text(row, '[class*="debit" i]'),
+		credit: text(row, '[class*="credit" i]'),
+		amount: text(row, '[class*="amount" i]'),
+		balance: text(row, '[class*="balance" i]'),
+		text: (row.innerText || '').trim(),
+	})).filter(row => row.text !== '');
+})()`
+
+// GetAccountTransactions opens an account and scrapes its transactions
+func (c *NABClient) GetAccountTransactions(ctx context.Context, accountID string) ([]model.Transaction, error) {
+	c.logger.Printf("Scraping transactions for account %s...", accountID)
+
+	var transactions []model.Transaction
+	err := c.runLoggedIn(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		transactions, err = c.readTransactions(ctx, accountID)
+		return err
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrape NAB transactions: %w", err)
+	}
+
+	c.logger.Printf("Successfully scraped %d transactions", len(transactions))
+	return transactions, nil
+}
+
+// readTransactions opens an account from the dashboard and reads its
+// transaction list
+func (c *NABClient) readTransactions(ctx context.Context, accountID string) ([]model.Transaction, error) {
+	if err := c.openAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	chromedp.Sleep(2 * time.Second).Do(ctx)
+
+	var rows []transactionRow
+	if err := chromedp.Evaluate(extractTransactionsScript, &rows).Do(ctx); err != nil {
+		return nil, fmt.Errorf("failed to read transactions: %w", err)
+	}
+	c.recorder.record(ctx, recordTransactions, accountID, rows)
+	return parseTransactions(accountID, rows, c.logger), nil
+}
+
+// parseTransactions builds transactions from the scraped rows, skipping
+// rows without a date, description or amount
+func parseTransactions(accountID string, rows []transactionRow, logger *log.Logger) []model.Transaction {
+	transactions := []model.Transaction{}
+	generated := make(map[string]int)
+	for _, row := range rows {
+		transaction, ok := parseTransaction(row)
+		if !ok {
+			logger.Printf("Skipping transaction row without a date, description or amount: %q", row.Text)
+			continue
+		}
+		if transaction.ID == "" {
+			transaction.ID = transactionID(accountID, transaction, generated)
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions
+}
+
+// parseTransaction builds a transaction from a scraped row. Without
+// labelled cells the first amount in the row's text is the transaction's
+// and the last, when there are two, the running balance after it.
+func parseTransaction(row transactionRow) (model.Transaction, bool) {
+	date := transactionDatePattern.FindString(row.Date)
+	if date == "" {
+		date = transactionDatePattern.FindString(row.Text)
+	}
+
+	var amount, balance *model.Money
+	if debit := findSignedAmounts(row.Debit); len(debit) > 0 {
+		amount = &debit[0]
+		amount.Amount = "-" + strings.TrimPrefix(amount.Amount, "-")
+	} else if credit := findSignedAmounts(row.Credit); len(credit) > 0 {
+		amount = &credit[0]
+	} else if amounts := findSignedAmounts(row.Amount); len(amounts) > 0 {
+		amount = &amounts[0]
+	}
+	if balances := findSignedAmounts(row.Balance); len(balances) > 0 {
+		balance = &balances[0]
+	}
+	amounts := findSignedAmounts(row.Text)
+	if amount == nil && len(amounts) > 0 {
+		amount = &amounts[0]
+	}
+	if balance == nil && len(amounts) > 1 {
+		balance = &amounts[len(amounts)-1]
+	}
+
+	description := strings.Join(strings.Fields(row.Description), " ")
+	if description == "" {
+		for _, line := range strings.Split(row.Text, "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !transactionDatePattern.MatchString(line) && !transactionAmountPattern.MatchString(line) {
+				description = strings.Join(strings.Fields(line), " ")
+				break
+			}
+		}
+	}
+	if date == "" || description == "" || amount == nil {
+		return model.Transaction{}, false
+	}
+
+	transaction := model.Transaction{
+		ID:          row.ID,
+		Date:        parseDisplayDate(date),
+		Description: description,
+		Amount:      *amount,
+	}
+	if balance != nil {
+		transaction.Balance = *balance
+	}
+	return transaction, true
+}
+
+// findSignedAmounts returns every amount in text as findBalances does,
+// negative where it is shown as a debit
+func findSignedAmounts(text string) []model.Money {
+	var amounts []model.Money
+	for _, match := range transactionAmountPattern.FindAllStringSubmatch(text, -1) {
+		money := findBalances(match[0])[0]
+		if match[1] != "" || match[6] != "" {
+			money.Amount = "-" + money.Amount
+		}
+		amounts = append(amounts, money)
+	}
+	return amounts
+}
+
+// transactionID derives an ID for transactions NAB doesn't give one.
+// generated counts the IDs derived so far, so identical transactions in
+// the one list are still told apart.
+func transactionID(accountID string, transaction model.Transaction, generated map[string]int) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{accountID, transaction.Date, transaction.Description, transaction.Amount.Amount, transaction.Balance.Amount}, "|")))
+	id := "txn_" + hex.EncodeToString(sum[:])[:16]
+	generated[id]++
+	if n := generated[id]; n > 1 {
+		id = fmt.Sprintf("%s_%d", id, n)
+	}
+	return id
+}
@@ -0,0 +1,93 @@
+package browser
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/config"
+	"github.com/chromedp/chromedp"
+)
+
+func TestParseTransactions(t *testing.T) {
+	transactions := parseTransactions("12345678", []transactionRow{
+		{Date: "17 Oct 2023", Description: "EFTPOS PURCHASE  COLES", Debit: "$85.67", Balance: "$2,543.67", Text: "17 Oct 2023\nEFTPOS PURCHASE COLES\n$85.67\n$2,543.67"},
+		{Date: "16 Oct 2023", Description: "SALARY", Credit: "$2,500.00", Balance: "$2,629.34", Text: "16 Oct 2023\nSALARY\n$2,500.00\n$2,629.34"},
+		{Text: "15/10/2023\nCARD FEE\n-$5.00\n$129.34"},
+		{Text: "15/10/2023\nCARD FEE\n-$5.00\n$129.34"},
+		{Text: "Opening balance $134.34"},
+	}, log.New(io.Discard, "", 0))
+
+	if len(transactions) != 4 {
+		t.Fatalf("expected the row without a date skipped, got %+v", transactions)
+	}
+	coles := transactions[0]
+	if coles.Date != "2023-10-17" || coles.Description != "EFTPOS PURCHASE COLES" || coles.Amount.Amount != "-85.67" || coles.Balance.Amount != "2543.67" || coles.Amount.Currency != "AUD" {
+		t.Errorf("unexpected debit %+v", coles)
+	}
+	if salary := transactions[1]; salary.Amount.Amount != "2500.00" || salary.Balance.Amount != "2629.34" {
+		t.Errorf("unexpected credit %+v", salary)
+	}
+	fee := transactions[2]
+	if fee.Date != "2023-10-15" || fee.Description != "CARD FEE" || fee.Amount.Amount != "-5.00" || fee.Balance.Amount != "129.34" {
+		t.Errorf("unexpected transaction from text %+v", fee)
+	}
+	if fee.ID == "" || fee.ID == transactions[3].ID {
+		t.Errorf("expected identical transactions to get their own IDs, got %q and %q", fee.ID, transactions[3].ID)
+	}
+	again := parseTransactions("12345678", []transactionRow{{Text: "15/10/2023\nCARD FEE\n-$5.00\n$129.34"}}, log.New(io.Discard, "", 0))
+	if again[0].ID != fee.ID {
+		t.Errorf("expected a stable ID, got %q then %q", fee.ID, again[0].ID)
+	}
+
+	if overdrawn, ok := parseTransaction(transactionRow{Text: "1 Nov 2023\nDIRECT DEBIT AGL\n$120.00 DR\n$20.00 DR"}); !ok || overdrawn.Amount.Amount != "-120.00" || overdrawn.Balance.Amount != "-20.00" {
+		t.Errorf("unexpected DR amounts %+v", overdrawn)
+	}
+}
+
+func TestSyncSessionScrapesTransactionsInATab(t *testing.T) {
+	if _, err := FindChrome(); err != nil {
+		t.Skip(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/accounts/12345678" {
+			w.Write([]byte(`<html><body><table class="transactions"><tbody>
+				<tr><td class="date">17 Oct 2023</td><td class="description">EFTPOS PURCHASE COLES</td><td class="debit">$85.67</td><td class="balance">$2,543.67</td></tr>
+			</tbody></table></body></html>`))
+			return
+		}
+		w.Write([]byte(`<html><body><a data-account-id="12345678" href="/accounts/12345678">Everyday</a></body></html>`))
+	}))
+	defer server.Close()
+
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), append(chromedp.DefaultExecAllocatorOptions[:], chromedp.NoSandbox)...)
+	defer cancelAlloc()
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+	defer cancelBrowser()
+	if err := chromedp.Run(browserCtx, chromedp.Navigate(server.URL)); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	client := &NABClient{config: &config.NABConfig{BrowserTimeout: 20 * time.Second}, logger: log.New(io.Discard, "", 0)}
+	// The sync holds the session lock for as long as it runs
+	if err := client.sessionLock.lock(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer client.sessionLock.unlock()
+	session := &syncSession{client: client, ctx: browserCtx, release: func() {}, dashboard: server.URL}
+
+	transactions, err := session.GetAccountTransactions(ctx, "12345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transactions) != 1 || transactions[0].Amount.Amount != "-85.67" || transactions[0].Balance.Amount != "2543.67" {
+		t.Errorf("unexpected transactions %+v", transactions)
+	}
+}
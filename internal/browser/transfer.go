@@ -0,0 +1,319 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/chromedp/chromedp"
+)
+
+// transferLinkSelectors locate the transfer between accounts form
+var transferLinkSelectors = []string{
+	`a[href*="transfer"]`,
+	`a[title*="Transfer"]`,
+	`[role="menuitem"][href*="transfer"]`,
+	`button[class*="transfer"]`,
+}
+
+// transferNextSelectors move the form on to the review screen
+var transferNextSelectors = []string{
+	`button[type="submit"]`,
+	`button[class*="next"]`,
+	`button[class*="continue"]`,
+	`input[type="submit"]`,
+}
+
+// transferConfirmSelectors confirm the transfer on the review screen. A
+// generic submit button isn't enough to go on here, as it could be any
+// button on the page.
+var transferConfirmSelectors = []string{
+	`button[class*="confirm"]`,
+	`button[id*="confirm"]`,
+	`input[type="submit"][value*="Confirm"]`,
+}
+
+// transferReceiptWait bounds reading the receipt once a transfer has been
+// confirmed, which goes on even if the request has gone away
+const transferReceiptWait = 30 * time.Second
+
+// receiptPattern finds the receipt number on the confirmation screen
+var receiptPattern = regexp.MustCompile(`(?i)receipt\s*(?:number|no\.?)?[:\s#]*([A-Z0-9][A-Z0-9-]{5,})`)
+
+// selectOptionScript picks the option for an account or payee in a
+// dropdown labelled with one of roles, such as "from" in fromAccount or
+// from-account but not in "fromage". An option matches a needle when its
+// value or text is the needle, or, for a number, when a run of digits in
+// its text is. The option is only picked if it is the one match, and the
+// number of matches is returned.
+const selectOptionScript = `((roles, needles) => {
+	const words = el => [el.name, el.id, el.getAttribute('aria-label')].join(' ')
+		.replace(/([a-z])([A-Z])/g, '$1 $2').toLowerCase().split(/[^a-z]+/);
+	const matches = [];
+	for (const select of document.querySelectorAll('select')) {
+		if (!words(select).some(word => roles.includes(word))) {
+			continue;
+		}
+		for (const option of select.options) {
+			const text = (option.text || '').trim();
+			const numbers = text.replace(/[\s-]/g, '').match(/\d+/g) || [];
+			if (needles.some(needle => needle && (option.value === needle || text === needle || numbers.includes(needle)))) {
+				matches.push([select, option]);
+			}
+		}
+	}
+	if (matches.length === 1) {
+		const [select, option] = matches[0];
+		select.value = option.value;
+		select.dispatchEvent(new Event('change', { bubbles: true }));
+	}
+	return matches.length;
+})(%s, %s)`
+
+// formErrorScript returns any validation error shown on a form
+const formErrorScript = `(() => {
+	const el = Array.from(document.querySelectorAll('[role="alert"], [class*="error"], [class*="Error"]'))
+		.find(el => el.getClientRects().length > 0 && (el.innerText || '').trim() !== '');
+	return el ? el.innerText.trim() : '';
+})()`
+
+// Transfer moves money between two of the customer's accounts. The form is
+// filled and taken to the review screen, whose fee and processing date are
+// returned; unless this is a dry run the transfer is then confirmed and the
+// receipt number captured. A request that goes away before the transfer is
+// confirmed abandons it, but once confirmed the receipt is read regardless,
+// and if it can't be the status is unknown rather than failed.
+func (c *NABClient) Transfer(ctx context.Context, req model.TransferRequest, from, to model.Account) (*model.TransferResult, error) {
+	mode := "live"
+	if req.DryRun {
+		mode = "dry run"
+	}
+	c.logger.Printf("Transferring $%s from %s to %s (%s)...", req.Amount, from.ID, to.ID, mode)
+
+	result := &model.TransferResult{
+		Status:        model.TransferStatusValidated,
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        model.Money{Amount: req.Amount},
+		Description:   req.Description,
+		DryRun:        req.DryRun,
+	}
+
+	// The browser follows the request until the transfer is confirmed
+	detached, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	stopFollowing := context.AfterFunc(ctx, cancel)
+	sessionCtx, release, err := c.startSession(detached, c.config.BrowserTimeout+transferReceiptWait)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transfer between NAB accounts: %w", err)
+	}
+	defer release()
+
+	var confirmed bool
+	err = chromedp.Run(sessionCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		if err := c.clickFirstVisible(ctx, transferLinkSelectors); err != nil {
+			c.takeScreenshot(ctx, "transfer_form_not_found")
+			return fmt.Errorf("could not find transfer form: %w", err)
+		}
+		chromedp.Sleep(2 * time.Second).Do(ctx)
+
+		if err := c.selectTransferAccount(ctx, "from", from); err != nil {
+			return err
+		}
+		if err := c.selectTransferAccount(ctx, "to", to); err != nil {
+			return err
+		}
+
+		if !c.fillFirstVisible(ctx, []string{`input[name*="amount" i]`, `input[id*="amount" i]`}, req.Amount) {
+			return fmt.Errorf("could not find amount field")
+		}
+		c.fillFirstVisible(ctx, []string{`input[name*="description" i]`, `input[name*="reference" i]`, `textarea`}, req.Description)
+
+		// Move on to the review screen, where NAB validates the transfer
+		if err := c.clickFirstVisible(ctx, transferNextSelectors); err != nil {
+			return fmt.Errorf("could not continue to review: %w", err)
+		}
+		chromedp.Sleep(2 * time.Second).Do(ctx)
+		if err := c.formError(ctx, service.ErrTransferRejected); err != nil {
+			return err
+		}
+		result.Review = c.readReview(ctx)
+		if err := checkReview(result.Review, req.Amount, from, to); err != nil {
+			c.takeScreenshot(ctx, "transfer_review_mismatch")
+			return err
+		}
+
+		if req.DryRun {
+			c.takeScreenshot(ctx, "transfer_review")
+			return nil
+		}
+
+		// From here the transfer goes ahead even if the request goes away
+		if !stopFollowing() {
+			return context.Canceled
+		}
+		if err := c.clickFirstVisible(ctx, transferConfirmSelectors); err != nil {
+			return fmt.Errorf("could not confirm transfer: %w", err)
+		}
+		confirmed = true
+
+		receiptCtx, cancel := context.WithTimeout(ctx, transferReceiptWait)
+		defer cancel()
+		return c.readTransferReceipt(receiptCtx, result)
+	}))
+	if err != nil {
+		if !confirmed && ctx.Err() != nil {
+			return nil, fmt.Errorf("failed to transfer between NAB accounts: %w", abortedError(ctx))
+		}
+		return nil, fmt.Errorf("failed to transfer between NAB accounts: %w", c.bundleError(sessionCtx, "error", err))
+	}
+
+	result.ProcessedAt = time.Now()
+	return result, nil
+}
+
+// readTransferReceipt marks a confirmed transfer completed and captures
+// the receipt number. NAB may have made the transfer even if the
+// confirmation screen can't be read, so then the status is unknown; only
+// an error NAB shows fails the transfer.
+func (c *NABClient) readTransferReceipt(ctx context.Context, result *model.TransferResult) error {
+	chromedp.Sleep(3 * time.Second).Do(ctx)
+	err := c.formError(ctx, service.ErrTransferRejected)
+	if errors.Is(err, service.ErrTransferRejected) {
+		return err
+	}
+
+	var text string
+	if err == nil {
+		err = chromedp.Text(`body`, &text, chromedp.ByQuery).Do(ctx)
+	}
+	if err != nil {
+		c.logger.Printf("Transfer confirmed but its receipt couldn't be read, so its status is unknown: %v", err)
+		result.Status = model.TransferStatusUnknown
+		return nil
+	}
+	c.takeScreenshot(ctx, "transfer_receipt")
+
+	result.Status = model.TransferStatusCompleted
+	if match := receiptPattern.FindStringSubmatch(text); match != nil {
+		result.ReceiptNumber = &match[1]
+	} else {
+		c.logger.Println("Transfer confirmed but no receipt number was found")
+	}
+	return nil
+}
+
+// checkReview checks that the review screen shows the transfer asked for,
+// the amount and the from account ahead of the to account, before it is
+// confirmed
+func checkReview(review *model.PaymentReview, amount string, from, to model.Account) error {
+	if review == nil {
+		return fmt.Errorf("could not read the transfer review screen")
+	}
+
+	want, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return fmt.Errorf("invalid transfer amount %q: %w", amount, err)
+	}
+	var amountShown bool
+	for _, shown := range findBalances(review.Text) {
+		if value, err := strconv.ParseFloat(shown.Amount, 64); err == nil && math.Abs(value-want) < 0.005 {
+			amountShown = true
+			break
+		}
+	}
+	if !amountShown {
+		return fmt.Errorf("the transfer review screen doesn't show the amount $%s", amount)
+	}
+
+	fromAt, toAt := accountPosition(review.Text, from), accountPosition(review.Text, to)
+	if fromAt < 0 || toAt < 0 || fromAt >= toAt {
+		return fmt.Errorf("the transfer review screen doesn't show a transfer from %s to %s", from.ID, to.ID)
+	}
+	return nil
+}
+
+// accountPosition returns where text first mentions an account by its ID,
+// its number or the last four digits of its number, or -1 if it doesn't
+func accountPosition(text string, account model.Account) int {
+	needles := []string{account.ID}
+	if account.AccountNumber != nil {
+		if number := digits(*account.AccountNumber); len(number) >= 4 {
+			needles = append(needles, number, number[len(number)-4:])
+		}
+	}
+
+	position := -1
+	for _, needle := range needles {
+		if needle == "" {
+			continue
+		}
+		match := regexp.MustCompile(`\b` + regexp.QuoteMeta(needle) + `\b`).FindStringIndex(text)
+		if match != nil && (position < 0 || match[0] < position) {
+			position = match[0]
+		}
+	}
+	return position
+}
+
+// selectTransferAccount chooses the account in the from or to dropdown by
+// its ID or, when it isn't masked, its full number
+func (c *NABClient) selectTransferAccount(ctx context.Context, role string, account model.Account) error {
+	needles := []string{account.ID}
+	if account.AccountNumber != nil && !strings.ContainsAny(*account.AccountNumber, "*xX•") {
+		needles = append(needles, digits(*account.AccountNumber))
+	}
+	return c.selectOption(ctx, []string{role}, needles, fmt.Sprintf("%s account %s", role, account.ID), "transfer_account")
+}
+
+// selectOption picks the one option matching needles in a dropdown
+// labelled with one of roles, failing if none or several match. what names
+// the option in errors, and screenshot the screenshot taken when it can't
+// be picked.
+func (c *NABClient) selectOption(ctx context.Context, roles, needles []string, what, screenshot string) error {
+	encodedRoles, err := json.Marshal(roles)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", what, err)
+	}
+	encodedNeedles, err := json.Marshal(needles)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", what, err)
+	}
+
+	var matches int
+	script := fmt.Sprintf(selectOptionScript, encodedRoles, encodedNeedles)
+	if err := chromedp.Evaluate(script, &matches).Do(ctx); err != nil {
+		return fmt.Errorf("failed to select %s: %w", what, err)
+	}
+	switch {
+	case matches == 0:
+		c.takeScreenshot(ctx, screenshot+"_not_found")
+		return fmt.Errorf("could not find %s on the form", what)
+	case matches > 1:
+		c.takeScreenshot(ctx, screenshot+"_ambiguous")
+		return fmt.Errorf("%d options on the form match %s", matches, what)
+	}
+	return nil
+}
+
+// formError returns the rejected error with NAB's message if the form is
+// showing a validation error
+func (c *NABClient) formError(ctx context.Context, rejected error) error {
+	var message string
+	if err := chromedp.Evaluate(formErrorScript, &message).Do(ctx); err != nil {
+		return fmt.Errorf("failed to check form: %w", err)
+	}
+	if message != "" {
+		c.takeScreenshot(ctx, "form_rejected")
+		return fmt.Errorf("%w: %s", rejected, message)
+	}
+	return nil
+}
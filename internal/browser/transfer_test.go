@@ -0,0 +1,35 @@
+package browser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestCheckReview(t *testing.T) {
+	everyday := "****5678"
+	savings := "084-004 98764321"
+	from := model.Account{ID: "acc_everyday", AccountNumber: &everyday}
+	to := model.Account{ID: "acc_savings", AccountNumber: &savings}
+	review := parseReview("From Complete Access 5678 To Reward Saver 4321 Amount $250.00 Fee $0.00 Transfer date Today", time.Now())
+
+	if err := checkReview(review, "250", from, to); err != nil {
+		t.Errorf("expected the review to match, got %v", err)
+	}
+	if err := checkReview(review, "25.00", from, to); err == nil {
+		t.Error("expected a different amount to be refused")
+	}
+	if err := checkReview(review, "250", to, from); err == nil {
+		t.Error("expected swapped accounts to be refused")
+	}
+	if err := checkReview(nil, "250", from, to); err == nil {
+		t.Error("expected an unread review to be refused")
+	}
+
+	// 5678 inside a longer number isn't the account
+	other := parseReview("From Complete Access 125678 To Reward Saver 4321 Amount $250.00", time.Now())
+	if err := checkReview(other, "250", from, to); err == nil {
+		t.Error("expected a partial account number not to match")
+	}
+}
@@ -0,0 +1,294 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// troubleshootTimeout bounds a whole troubleshooting run, leaving time for
+// the pauses between stages. Each stage is bounded by the browser timeout.
+const troubleshootTimeout = 30 * time.Minute
+
+// TroubleshootStep is the outcome of one stage of a troubleshooting run
+type TroubleshootStep struct {
+	Number     int    `json:"number"`
+	Name       string `json:"name"`
+	Expected   string `json:"expected"`
+	Found      string `json:"found"`
+	Passed     bool   `json:"passed"`
+	Screenshot string `json:"screenshot,omitempty"`
+}
+
+// TroubleshootReport summarises a troubleshooting run
+type TroubleshootReport struct {
+	Steps     []TroubleshootStep `json:"steps"`
+	Diagnosis string             `json:"diagnosis"`
+}
+
+// troubleshootStage is one stage of the login and scrape flow
+type troubleshootStage struct {
+	name     string
+	expected string
+	run      func(ctx context.Context) (string, error)
+}
+
+// diagnosisError is a failure that already explains itself, so it is used
+// as the diagnosis as is
+type diagnosisError string
+
+func (e diagnosisError) Error() string { return string(e) }
+
+// loginState is what the page shows once credentials are submitted
+type loginState struct {
+	State  string `json:"state"`
+	Detail string `json:"detail"`
+}
+
+// loginStateScript works out where the login landed: an SMS or app code
+// challenge, an error message, updated terms, the login form again, or the
+// accounts overview
+const loginStateScript = `(() => {
+	const visible = selector => Array.from(document.querySelectorAll(selector))
+		.some(el => el.getClientRects().length > 0);
+	const text = document.body ? document.body.innerText : '';
+	if (visible('input[autocomplete="one-time-code"], input[name*="otp" i]') ||
+		/(verification|security|one[- ]time)\s+code|we('ve| have) sent (you )?a code/i.test(text)) {
+		return {state: 'otp', detail: ''};
+	}
+	const alert = Array.from(document.querySelectorAll('[role="alert"], [class*="error"], [class*="Error"]'))
+		.find(el => el.getClientRects().length > 0 && (el.innerText || '').trim() !== '');
+	if (alert) return {state: 'error', detail: alert.innerText.trim()};
+	if (/(updated|new|changes to (our|the|your))\s+(terms|conditions|terms and conditions)/i.test(text)) {
+		return {state: 'terms', detail: ''};
+	}
+	if (visible('input[type="password"]')) return {state: 'login_form', detail: ''};
+	if (/\$[\d,]+\.\d{2}/.test(text)) return {state: 'accounts', detail: ''};
+	return {state: 'unknown', detail: document.title};
+})()`
+
+// annotateScript overlays a banner describing the stage so screenshots
+// explain themselves
+const annotateScript = `((text, passed) => {
+	const banner = document.createElement('div');
+	banner.id = 'nab-troubleshoot-banner';
+	banner.textContent = text;
+	banner.style.cssText = 'position:fixed;top:0;left:0;right:0;z-index:2147483647;padding:8px 12px;' +
+		'font:bold 14px sans-serif;color:#fff;background:' + (passed ? '#2e7d32' : '#c62828');
+	document.body.appendChild(banner);
+	return true;
+})(%q, %t)`
+
+// removeAnnotationScript removes the banner again
+const removeAnnotationScript = `(() => {
+	const banner = document.getElementById('nab-troubleshoot-banner');
+	if (banner) banner.remove();
+	return true;
+})()`
+
+// screenshotNamePattern matches characters not wanted in screenshot names
+var screenshotNamePattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Troubleshoot runs the login and account scrape one stage at a time,
+// recording what each stage expected and found and saving an annotated
+// screenshot of each. after is called once each stage finishes, so the
+// caller can report progress and pause; an error from it stops the run. The
+// run stops at the first failing stage, and the report says where the flow
+// got stuck.
+func (c *NABClient) Troubleshoot(ctx context.Context, after func(TroubleshootStep) error) (*TroubleshootReport, error) {
+	// Unlike scrapes this ignores any terms pause, as finding out why logins
+	// fail is the point
+	profile := c.profiles.pick()
+	browserCtx, cancel, err := newBrowserContext(ctx, c.config, profile, troubleshootTimeout, c.logger)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	var accounts int
+	stages := []troubleshootStage{
+		{
+			name:     "Open NAB homepage",
+			expected: "the NAB homepage loads from " + c.config.BaseURL,
+			run: func(ctx context.Context) (string, error) {
+				var title, location string
+				err := chromedp.Run(ctx,
+					profile.emulate(),
+					chromedp.Navigate(c.config.BaseURL),
+					chromedp.WaitVisible(`body`, chromedp.ByQuery),
+					chromedp.Title(&title),
+					chromedp.Location(&location),
+				)
+				return done(fmt.Sprintf("%q at %s", title, location), err)
+			},
+		},
+		{
+			name:     "Open login menu",
+			expected: "a Login button in the header",
+			run: func(ctx context.Context) (string, error) {
+				return done("clicked the Login button", chromedp.Run(ctx, c.clickLoginButton()))
+			},
+		},
+		{
+			name:     "Choose Internet Banking",
+			expected: "an Internet Banking link in the login menu",
+			run: func(ctx context.Context) (string, error) {
+				return done("opened Internet Banking", chromedp.Run(ctx, c.selectInternetBanking()))
+			},
+		},
+		{
+			name:     "Submit credentials",
+			expected: "username and password fields and a submit button",
+			run: func(ctx context.Context) (string, error) {
+				return done("filled in and submitted the login form", chromedp.Run(ctx, c.performLogin()))
+			},
+		},
+		{
+			name:     "Check login result",
+			expected: "the accounts overview",
+			run: func(ctx context.Context) (string, error) {
+				chromedp.Sleep(3 * time.Second).Do(ctx)
+				var state loginState
+				if err := chromedp.Evaluate(loginStateScript, &state).Do(ctx); err != nil {
+					return "", fmt.Errorf("failed to read page: %w", err)
+				}
+				return describeLoginState(state)
+			},
+		},
+		{
+			name:     "Dismiss popups",
+			expected: "no surveys, promotions or consent banners in the way",
+			run: func(ctx context.Context) (string, error) {
+				return done("dismissed any popups", chromedp.Run(ctx, c.dismissInterstitials()))
+			},
+		},
+		{
+			name:     "Read accounts",
+			expected: "at least one account with a balance",
+			run: func(ctx context.Context) (string, error) {
+				accounts = len(c.extractAccountsGeneric(ctx))
+				found := fmt.Sprintf("%d accounts", accounts)
+				if accounts == 0 {
+					return found, diagnosisError("logged in but no accounts were found: the accounts page layout may have changed")
+				}
+				return found, nil
+			},
+		},
+	}
+
+	report := &TroubleshootReport{}
+	for i, stage := range stages {
+		stageCtx, cancelStage := context.WithTimeout(browserCtx, c.config.BrowserTimeout)
+		found, err := stage.run(stageCtx)
+
+		step := TroubleshootStep{
+			Number:   i + 1,
+			Name:     stage.name,
+			Expected: stage.expected,
+			Found:    found,
+			Passed:   err == nil,
+		}
+		if err != nil {
+			if step.Found == "" {
+				step.Found = err.Error()
+			}
+			report.Diagnosis = diagnose(stage.name, err)
+		}
+		step.Screenshot = c.saveAnnotatedScreenshot(browserCtx, step)
+		cancelStage()
+
+		report.Steps = append(report.Steps, step)
+		if after != nil {
+			if err := after(step); err != nil {
+				return report, err
+			}
+		}
+		if !step.Passed {
+			return report, nil
+		}
+	}
+
+	report.Diagnosis = fmt.Sprintf("no problems found: logged in and read %d accounts", accounts)
+	return report, nil
+}
+
+// done reports what a stage found, or nothing if it failed so the error is
+// reported instead
+func done(found string, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	return found, nil
+}
+
+// describeLoginState reports what the page showed after logging in,
+// failing with a diagnosis unless it reached the accounts overview
+func describeLoginState(state loginState) (string, error) {
+	switch state.State {
+	case "accounts":
+		return "the accounts overview", nil
+	case "otp":
+		return "a one-time code challenge", diagnosisError("stuck at OTP challenge: NAB wants a one-time code for this login, so approve the device or enter the code manually once and persist the session (BROWSER_SESSION_DIR)")
+	case "error":
+		return fmt.Sprintf("an error: %q", state.Detail), diagnosisError(fmt.Sprintf("login rejected by NAB: %s", state.Detail))
+	case "terms":
+		return "updated terms and conditions", diagnosisError("stuck at updated terms and conditions: accept them in internet banking, or set NAB_AUTO_ACCEPT_TERMS")
+	case "login_form":
+		return "the login form again", diagnosisError("still on the login form after submitting: the credentials may not have been entered, or the form layout has changed")
+	}
+	return fmt.Sprintf("an unrecognised page %q", state.Detail), diagnosisError(fmt.Sprintf("did not reach the accounts overview: landed on an unrecognised page %q", state.Detail))
+}
+
+// diagnose explains a failed stage
+func diagnose(stage string, err error) string {
+	var diagnosis diagnosisError
+	if errors.As(err, &diagnosis) {
+		return string(diagnosis)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Sprintf("stuck at %s: timed out waiting for the page", strings.ToLower(stage))
+	}
+	return fmt.Sprintf("stuck at %s: %v", strings.ToLower(stage), err)
+}
+
+// saveAnnotatedScreenshot captures the page with a banner describing the
+// step and writes it to the screenshot directory, returning its path. It
+// returns an empty path if the screenshot could not be taken.
+func (c *NABClient) saveAnnotatedScreenshot(ctx context.Context, step TroubleshootStep) string {
+	result := "PASS"
+	if !step.Passed {
+		result = "FAIL"
+	}
+	banner := fmt.Sprintf("Step %d: %s - %s - found %s", step.Number, step.Name, result, step.Found)
+
+	var buf []byte
+	var ok bool
+	err := chromedp.Run(ctx,
+		chromedp.Evaluate(fmt.Sprintf(annotateScript, banner, step.Passed), &ok),
+		c.captureScreenshot(&buf),
+		chromedp.Evaluate(removeAnnotationScript, &ok),
+	)
+	if err != nil {
+		c.logger.Printf("Failed to capture screenshot for step %d: %v", step.Number, err)
+		return ""
+	}
+
+	if err := os.MkdirAll(c.config.ScreenshotPath, 0o755); err != nil {
+		c.logger.Printf("Failed to create screenshot directory: %v", err)
+		return ""
+	}
+	name := strings.Trim(screenshotNamePattern.ReplaceAllString(strings.ToLower(step.Name), "_"), "_")
+	path := filepath.Join(c.config.ScreenshotPath, fmt.Sprintf("troubleshoot_%s_%d_%s.png", time.Now().Format("20060102_150405"), step.Number, name))
+	if err := os.WriteFile(path, buf, 0o644); err != nil {
+		c.logger.Printf("Failed to save screenshot: %v", err)
+		return ""
+	}
+	return path
+}
@@ -0,0 +1,35 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDescribeLoginState(t *testing.T) {
+	if found, err := describeLoginState(loginState{State: "accounts"}); err != nil || found != "the accounts overview" {
+		t.Errorf("expected accounts overview to pass, got %q, %v", found, err)
+	}
+
+	_, err := describeLoginState(loginState{State: "otp"})
+	if diagnosis := diagnose("Check login result", err); !strings.HasPrefix(diagnosis, "stuck at OTP challenge") {
+		t.Errorf("unexpected OTP diagnosis %q", diagnosis)
+	}
+
+	_, err = describeLoginState(loginState{State: "error", Detail: "Your NAB ID or password is incorrect"})
+	if diagnosis := diagnose("Check login result", err); diagnosis != "login rejected by NAB: Your NAB ID or password is incorrect" {
+		t.Errorf("unexpected error diagnosis %q", diagnosis)
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	err := fmt.Errorf("failed: %w", context.DeadlineExceeded)
+	if diagnosis := diagnose("Open login menu", err); diagnosis != "stuck at open login menu: timed out waiting for the page" {
+		t.Errorf("unexpected timeout diagnosis %q", diagnosis)
+	}
+	if diagnosis := diagnose("Submit credentials", errors.New("could not find password input field")); diagnosis != "stuck at submit credentials: could not find password input field" {
+		t.Errorf("unexpected diagnosis %q", diagnosis)
+	}
+}
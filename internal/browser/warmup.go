@@ -0,0 +1,20 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+)
+
+// WarmUp logs in to NAB without scraping anything, so Chrome has started
+// once and, with BROWSER_SESSION_DIR set, the session is saved ready for
+// the first request
+func (c *NABClient) WarmUp(ctx context.Context) error {
+	c.logger.Println("Warming up NAB browser session...")
+
+	if err := c.runLoggedIn(ctx); err != nil {
+		return fmt.Errorf("failed to warm up NAB session: %w", err)
+	}
+
+	c.logger.Println("NAB browser session warmed up")
+	return nil
+}
@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrMiss is returned when a key isn't cached or has expired
+var ErrMiss = errors.New("cache miss")
+
+// Cache stores values under keys for a limited time. Backends shared
+// between API instances let one instance reuse what another scraped.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the cache backend for a URL: "memory" for a cache private to
+// this process, or a redis:// or rediss:// URL for a Redis server shared
+// between instances, with keys prefixed by prefix. An empty URL returns
+// nil.
+func New(rawURL, prefix string) (Cache, error) {
+	switch {
+	case rawURL == "":
+		return nil, nil
+	case rawURL == "memory":
+		return NewMemory(), nil
+	case strings.HasPrefix(rawURL, "redis://"), strings.HasPrefix(rawURL, "rediss://"):
+		redis, err := NewRedis(rawURL, prefix)
+		if err != nil {
+			return nil, err
+		}
+		return redis, nil
+	}
+	return nil, fmt.Errorf("unsupported cache backend %q, expected memory or a redis:// URL", rawURL)
+}
+
+// Memory is a cache held in this process's memory
+type Memory struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+// memoryEntry is a cached value and when it expires
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemory creates an in-memory cache
+func NewMemory() *Memory {
+	return &Memory{
+		now:     time.Now,
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+// Get returns a cached value, or ErrMiss
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, ErrMiss
+	}
+	if !entry.expiresAt.IsZero() && !m.now().Before(entry.expiresAt) {
+		delete(m.entries, key)
+		return nil, ErrMiss
+	}
+	return append([]byte(nil), entry.value...), nil
+}
+
+// Set caches a value for ttl, or until deleted if ttl is zero
+func (m *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := memoryEntry{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.expiresAt = m.now().Add(ttl)
+	}
+	m.entries[key] = entry
+	return nil
+}
+
+// Delete removes a cached value
+func (m *Memory) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
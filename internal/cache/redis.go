@@ -0,0 +1,277 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxIdleRedisConns caps the connections kept open between commands
+const maxIdleRedisConns = 4
+
+// redisError is an error reply from the server. The connection is still
+// usable after one.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// Redis is a cache backed by a Redis server. It speaks just enough of the
+// Redis protocol (RESP) for GET, SET, DEL and PING, reusing connections
+// between commands.
+type Redis struct {
+	addr     string
+	username string
+	password string
+	db       int
+	tls      *tls.Config
+	prefix   string
+	timeout  time.Duration
+
+	mu   sync.Mutex
+	idle []*redisConn
+}
+
+// redisConn is an open connection to the server
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewRedis creates a Redis cache from a URL like
+// redis://:password@host:6379/0, or rediss:// for TLS. Keys are prefixed
+// with prefix so several deployments can share a server.
+func NewRedis(rawURL, prefix string) (*Redis, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return nil, fmt.Errorf("invalid Redis URL scheme %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, errors.New("invalid Redis URL: missing host")
+	}
+
+	r := &Redis{
+		addr:    u.Host,
+		prefix:  prefix,
+		timeout: 5 * time.Second,
+	}
+	if u.Port() == "" {
+		r.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		r.username = u.User.Username()
+		r.password, _ = u.User.Password()
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		if r.db, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid Redis database %q", db)
+		}
+	}
+	if u.Scheme == "rediss" {
+		r.tls = &tls.Config{ServerName: u.Hostname()}
+	}
+
+	return r, nil
+}
+
+// Get returns a cached value, or ErrMiss
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
+	reply, err := r.do(ctx, "GET", r.prefix+key)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, ErrMiss
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected reply to GET: %v", reply)
+	}
+	return value, nil
+}
+
+// Set caches a value for ttl, or until deleted if ttl is zero
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []string{"SET", r.prefix + key, string(value)}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	_, err := r.do(ctx, args...)
+	return err
+}
+
+// Delete removes a cached value
+func (r *Redis) Delete(ctx context.Context, key string) error {
+	_, err := r.do(ctx, "DEL", r.prefix+key)
+	return err
+}
+
+// Ping checks the server can be reached
+func (r *Redis) Ping(ctx context.Context) error {
+	_, err := r.do(ctx, "PING")
+	return err
+}
+
+// do runs a command on an idle connection, or a new one, and returns the
+// reply. Connections that fail are closed rather than reused.
+func (r *Redis) do(ctx context.Context, args ...string) (interface{}, error) {
+	c, err := r.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := c.command(ctx, r.timeout, args...)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		c.conn.Close()
+		return nil, err
+	}
+
+	r.mu.Lock()
+	if len(r.idle) < maxIdleRedisConns {
+		r.idle = append(r.idle, c)
+		c = nil
+	}
+	r.mu.Unlock()
+	if c != nil {
+		c.conn.Close()
+	}
+
+	return reply, err
+}
+
+// conn takes an idle connection or dials a new one, authenticating and
+// selecting the database
+func (r *Redis) conn(ctx context.Context) (*redisConn, error) {
+	r.mu.Lock()
+	if n := len(r.idle); n > 0 {
+		c := r.idle[n-1]
+		r.idle = r.idle[:n-1]
+		r.mu.Unlock()
+		return c, nil
+	}
+	r.mu.Unlock()
+
+	dialer := net.Dialer{Timeout: r.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", r.addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	if r.tls != nil {
+		tlsConn := tls.Client(conn, r.tls)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+		}
+		conn = tlsConn
+	}
+
+	c := &redisConn{conn: conn, reader: bufio.NewReader(conn)}
+	var setup [][]string
+	switch {
+	case r.username != "":
+		setup = append(setup, []string{"AUTH", r.username, r.password})
+	case r.password != "":
+		setup = append(setup, []string{"AUTH", r.password})
+	}
+	if r.db != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(r.db)})
+	}
+	for _, args := range setup {
+		if _, err := c.command(ctx, r.timeout, args...); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to set up Redis connection: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+// command sends a command and reads its reply
+func (c *redisConn) command(ctx context.Context, timeout time.Duration, args ...string) (interface{}, error) {
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&buf, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := c.conn.Write(buf.Bytes()); err != nil {
+		return nil, fmt.Errorf("failed to send Redis command: %w", err)
+	}
+
+	return readReply(c.reader)
+}
+
+// readReply reads one RESP reply. Simple strings come back as strings,
+// bulk strings as bytes, integers as int64, arrays as slices and nil
+// replies as nil.
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Redis reply: %w", err)
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("malformed Redis reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("malformed Redis reply %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		value := make([]byte, n+2)
+		if _, err := io.ReadFull(r, value); err != nil {
+			return nil, fmt.Errorf("failed to read Redis reply: %w", err)
+		}
+		return value[:n], nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("malformed Redis reply %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+
+	return nil, fmt.Errorf("unknown Redis reply type %q", kind)
+}
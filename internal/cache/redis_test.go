@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis serves GET, SET, DEL, AUTH, SELECT and PING from memory
+type fakeRedis struct {
+	password string
+
+	mu     sync.Mutex
+	values map[string]string
+	dbs    []string
+}
+
+func (f *fakeRedis) serve(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.handle(conn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authed := f.password == ""
+	for {
+		request, err := readReply(reader)
+		if err != nil {
+			return
+		}
+		var args []string
+		for _, item := range request.([]interface{}) {
+			args = append(args, string(item.([]byte)))
+		}
+
+		f.mu.Lock()
+		var reply string
+		switch {
+		case args[0] == "AUTH":
+			authed = args[len(args)-1] == f.password
+			reply = "+OK\r\n"
+			if !authed {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case !authed:
+			reply = "-NOAUTH Authentication required\r\n"
+		case args[0] == "SELECT":
+			f.dbs = append(f.dbs, args[1])
+			reply = "+OK\r\n"
+		case args[0] == "PING":
+			reply = "+PONG\r\n"
+		case args[0] == "SET":
+			f.values[args[1]] = args[2]
+			reply = "+OK\r\n"
+		case args[0] == "GET":
+			value, ok := f.values[args[1]]
+			reply = "$-1\r\n"
+			if ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+			}
+		case args[0] == "DEL":
+			delete(f.values, args[1])
+			reply = ":1\r\n"
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		f.mu.Unlock()
+
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func TestRedis(t *testing.T) {
+	server := &fakeRedis{password: "s3cret", values: make(map[string]string)}
+	addr := server.serve(t)
+	ctx := context.Background()
+
+	cache, err := NewRedis("redis://:s3cret@"+addr+"/2", "test:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Ping(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Set(ctx, "accounts", []byte("[\r\n]"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := cache.Get(ctx, "accounts"); err != nil || string(value) != "[\r\n]" {
+		t.Errorf("expected the value back, got %q, %v", value, err)
+	}
+	if _, ok := server.values["test:accounts"]; !ok {
+		t.Error("expected keys to be prefixed")
+	}
+	if err := cache.Delete(ctx, "accounts"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Get(ctx, "accounts"); !errors.Is(err, ErrMiss) {
+		t.Errorf("expected a miss after deleting, got %v", err)
+	}
+	if len(server.dbs) != 1 || server.dbs[0] != "2" {
+		t.Errorf("expected one connection selecting database 2, got %v", server.dbs)
+	}
+
+	wrong, err := NewRedis("redis://:wrong@"+addr, "test:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wrong.Ping(ctx); err == nil {
+		t.Error("expected a wrong password to fail")
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, rawURL := range []string{"memcached://localhost", "redis://", "redis://localhost/db"} {
+		if _, err := New(rawURL, ""); err == nil {
+			t.Errorf("expected %q to be rejected", rawURL)
+		}
+	}
+	if backend, err := New("", ""); backend != nil || err != nil {
+		t.Errorf("expected no backend for an empty URL, got %v, %v", backend, err)
+	}
+}
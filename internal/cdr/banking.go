@@ -0,0 +1,446 @@
+package cdr
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// Endpoint versions requested with the x-v header
+const (
+	accountsVersion          = "1"
+	balancesVersion          = "1"
+	transactionsVersion      = "1"
+	payeesVersion            = "2"
+	scheduledPaymentsVersion = "2"
+)
+
+// pageSize is the largest page the CDR standards require data holders to
+// support
+const pageSize = "1000"
+
+type cdrAccount struct {
+	AccountID       string `json:"accountId"`
+	DisplayName     string `json:"displayName"`
+	Nickname        string `json:"nickname"`
+	MaskedNumber    string `json:"maskedNumber"`
+	ProductCategory string `json:"productCategory"`
+	ProductName     string `json:"productName"`
+}
+
+type cdrBalance struct {
+	AccountID        string `json:"accountId"`
+	CurrentBalance   string `json:"currentBalance"`
+	AvailableBalance string `json:"availableBalance"`
+	CreditLimit      string `json:"creditLimit"`
+}
+
+type cdrTransaction struct {
+	TransactionID   string `json:"transactionId"`
+	Status          string `json:"status"`
+	Description     string `json:"description"`
+	PostingDateTime string `json:"postingDateTime"`
+	ValueDateTime   string `json:"valueDateTime"`
+	Amount          string `json:"amount"`
+	Reference       string `json:"reference"`
+	MerchantName    string `json:"merchantName"`
+}
+
+type cdrDomesticPayee struct {
+	Account *struct {
+		AccountName   string `json:"accountName"`
+		BSB           string `json:"bsb"`
+		AccountNumber string `json:"accountNumber"`
+	} `json:"account"`
+}
+
+type cdrPayee struct {
+	PayeeID  string            `json:"payeeId"`
+	Nickname string            `json:"nickname"`
+	Type     string            `json:"type"`
+	Domestic *cdrDomesticPayee `json:"domestic"`
+}
+
+type cdrPaymentTo struct {
+	Nickname string            `json:"nickname"`
+	Domestic *cdrDomesticPayee `json:"domestic"`
+	Biller   *struct {
+		BillerName string `json:"billerName"`
+	} `json:"biller"`
+}
+
+type cdrScheduledPayment struct {
+	ScheduledPaymentID string `json:"scheduledPaymentId"`
+	Nickname           string `json:"nickname"`
+	PayerReference     string `json:"payerReference"`
+	Status             string `json:"status"`
+	From               struct {
+		AccountID string `json:"accountId"`
+	} `json:"from"`
+	PaymentSet []struct {
+		To     cdrPaymentTo `json:"to"`
+		Amount string       `json:"amount"`
+	} `json:"paymentSet"`
+	Recurrence struct {
+		NextPaymentDate  string `json:"nextPaymentDate"`
+		RecurrenceUType  string `json:"recurrenceUType"`
+		IntervalSchedule *struct {
+			FinalPaymentDate string `json:"finalPaymentDate"`
+			Intervals        []struct {
+				Interval string `json:"interval"`
+			} `json:"intervals"`
+		} `json:"intervalSchedule"`
+		LastWeekDay *struct {
+			FinalPaymentDate string `json:"finalPaymentDate"`
+			Interval         string `json:"interval"`
+		} `json:"lastWeekDay"`
+	} `json:"recurrence"`
+}
+
+// GetAccounts lists the consented accounts with their balances
+func (c *Client) GetAccounts(ctx context.Context) ([]model.Account, error) {
+	var accounts []cdrAccount
+	err := c.get(ctx, "/banking/accounts", accountsVersion, url.Values{"open-status": {"OPEN"}, "page-size": {pageSize}}, func(raw []byte) error {
+		var page struct {
+			Data struct {
+				Accounts []cdrAccount `json:"accounts"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return err
+		}
+		accounts = append(accounts, page.Data.Accounts...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]cdrBalance)
+	err = c.get(ctx, "/banking/accounts/balances", balancesVersion, url.Values{"open-status": {"OPEN"}, "page-size": {pageSize}}, func(raw []byte) error {
+		var page struct {
+			Data struct {
+				Balances []cdrBalance `json:"balances"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return err
+		}
+		for _, balance := range page.Data.Balances {
+			balances[balance.AccountID] = balance
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.Account, 0, len(accounts))
+	for _, account := range accounts {
+		result = append(result, convertAccount(account, balances[account.AccountID]))
+	}
+	return result, nil
+}
+
+// GetAccountTransactions returns an account's posted transactions, newest
+// first
+func (c *Client) GetAccountTransactions(ctx context.Context, accountID string) ([]model.Transaction, error) {
+	return c.GetAccountTransactionsSince(ctx, accountID, "")
+}
+
+// GetAccountTransactionsSince returns an account's posted transactions on
+// or after since, a YYYY-MM-DD date
+func (c *Client) GetAccountTransactionsSince(ctx context.Context, accountID string, since string) ([]model.Transaction, error) {
+	query := url.Values{"page-size": {pageSize}}
+	if since != "" {
+		query.Set("oldest-time", since+"T00:00:00Z")
+	}
+
+	var transactions []model.Transaction
+	generated := make(map[string]int)
+	path := "/banking/accounts/" + url.PathEscape(accountID) + "/transactions"
+	err := c.get(ctx, path, transactionsVersion, query, func(raw []byte) error {
+		var page struct {
+			Data struct {
+				Transactions []cdrTransaction `json:"transactions"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return err
+		}
+		for _, transaction := range page.Data.Transactions {
+			// Pending transactions have no posting date and change or
+			// disappear before they settle
+			if transaction.Status != "POSTED" {
+				continue
+			}
+			transactions = append(transactions, convertTransaction(accountID, transaction, generated))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// GetMessages returns no messages, as the CDR has no secure mail inbox
+func (c *Client) GetMessages(ctx context.Context) ([]model.Message, error) {
+	return []model.Message{}, nil
+}
+
+// GetPayees lists saved payees that pay to a BSB and account number. The
+// list endpoint only returns summaries, so each payee's detail is fetched
+// for its account.
+func (c *Client) GetPayees(ctx context.Context) ([]model.Payee, error) {
+	var summaries []cdrPayee
+	err := c.get(ctx, "/banking/payees", payeesVersion, url.Values{"type": {"DOMESTIC"}, "page-size": {pageSize}}, func(raw []byte) error {
+		var page struct {
+			Data struct {
+				Payees []cdrPayee `json:"payees"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return err
+		}
+		summaries = append(summaries, page.Data.Payees...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	payees := make([]model.Payee, 0, len(summaries))
+	for _, summary := range summaries {
+		var detail cdrPayee
+		err := c.get(ctx, "/banking/payees/"+url.PathEscape(summary.PayeeID), payeesVersion, nil, func(raw []byte) error {
+			var page struct {
+				Data cdrPayee `json:"data"`
+			}
+			if err := json.Unmarshal(raw, &page); err != nil {
+				return err
+			}
+			detail = page.Data
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		// PayID payees have no account to show
+		if detail.Domestic == nil || detail.Domestic.Account == nil {
+			continue
+		}
+
+		payee := model.Payee{
+			ID:            summary.PayeeID,
+			Name:          detail.Domestic.Account.AccountName,
+			BSB:           strings.ReplaceAll(detail.Domestic.Account.BSB, "-", ""),
+			AccountNumber: detail.Domestic.Account.AccountNumber,
+		}
+		if summary.Nickname != "" {
+			nickname := summary.Nickname
+			payee.Nickname = &nickname
+		}
+		payees = append(payees, payee)
+	}
+	return payees, nil
+}
+
+// GetScheduledPayments lists active scheduled payments. A payment set can
+// pay several payees, each of which becomes its own scheduled payment.
+func (c *Client) GetScheduledPayments(ctx context.Context) ([]model.ScheduledPayment, error) {
+	var payments []model.ScheduledPayment
+	err := c.get(ctx, "/banking/payments/scheduled", scheduledPaymentsVersion, url.Values{"page-size": {pageSize}}, func(raw []byte) error {
+		var page struct {
+			Data struct {
+				ScheduledPayments []cdrScheduledPayment `json:"scheduledPayments"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return err
+		}
+		for _, payment := range page.Data.ScheduledPayments {
+			if payment.Status != "" && payment.Status != "ACTIVE" {
+				continue
+			}
+			payments = append(payments, convertScheduledPayment(payment)...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// convertAccount maps a CDR account and its balance onto an account
+func convertAccount(account cdrAccount, balance cdrBalance) model.Account {
+	name := account.DisplayName
+	if name == "" {
+		name = account.ProductName
+	}
+	result := model.Account{
+		ID:      account.AccountID,
+		Name:    name,
+		Type:    accountType(account),
+		Balance: model.Money{Amount: balance.CurrentBalance},
+	}
+	if balance.AvailableBalance != "" {
+		result.AvailableBalance = &model.Money{Amount: balance.AvailableBalance}
+	}
+	if account.MaskedNumber != "" {
+		number := account.MaskedNumber
+		result.AccountNumber = &number
+	}
+	if result.Type == model.AccountTypeCredit && balance.CreditLimit != "" {
+		result.Credit = &model.CreditDetails{
+			CreditLimit:     model.Money{Amount: balance.CreditLimit},
+			AvailableCredit: model.Money{Amount: balance.AvailableBalance},
+		}
+	}
+	return result
+}
+
+// accountType maps a CDR product category onto an account type. Savings
+// and transaction accounts share a category, so the product name decides.
+func accountType(account cdrAccount) string {
+	switch account.ProductCategory {
+	case "TERM_DEPOSITS":
+		return model.AccountTypeTermDeposit
+	case "CRED_AND_CHRG_CARDS", "OVERDRAFTS":
+		return model.AccountTypeCredit
+	case "RESIDENTIAL_MORTGAGES", "PERS_LOANS", "BUSINESS_LOANS", "MARGIN_LOANS", "LEASES", "TRADE_FINANCE":
+		return model.AccountTypeLoan
+	}
+	name := strings.ToLower(account.ProductName + " " + account.DisplayName)
+	if strings.Contains(name, "saver") || strings.Contains(name, "savings") {
+		return model.AccountTypeSavings
+	}
+	return model.AccountTypeChecking
+}
+
+// convertTransaction maps a posted CDR transaction onto a transaction. The
+// CDR doesn't give running balances, so Balance is left empty and the
+// store matches these transactions by ID rather than by fingerprint.
+// generated counts the IDs generated so far, so identical transactions
+// without one of their own are still told apart.
+func convertTransaction(accountID string, transaction cdrTransaction, generated map[string]int) model.Transaction {
+	date := transaction.PostingDateTime
+	if date == "" {
+		date = transaction.ValueDateTime
+	}
+	if len(date) > 10 {
+		date = date[:10]
+	}
+
+	result := model.Transaction{
+		ID:          transaction.TransactionID,
+		Date:        date,
+		Description: transaction.Description,
+		Amount:      model.Money{Amount: transaction.Amount},
+	}
+	// Transaction IDs are optional in the standards, so fall back to an
+	// ID that's stable across syncs. Syncs fetch whole days, so the nth of
+	// several identical transactions is the nth again next time.
+	if result.ID == "" {
+		sum := sha256.Sum256([]byte(strings.Join([]string{accountID, transaction.PostingDateTime, transaction.Description, transaction.Amount, transaction.Reference}, "|")))
+		result.ID = "txn_" + hex.EncodeToString(sum[:8])
+		generated[result.ID]++
+		if n := generated[result.ID]; n > 1 {
+			result.ID = fmt.Sprintf("%s_%d", result.ID, n)
+		}
+	}
+	if transaction.MerchantName != "" {
+		merchant := transaction.MerchantName
+		result.Merchant = &merchant
+	}
+	return result
+}
+
+// convertScheduledPayment maps a CDR scheduled payment onto one scheduled
+// payment per payee
+func convertScheduledPayment(payment cdrScheduledPayment) []model.ScheduledPayment {
+	frequency, endDate := recurrence(payment)
+	var result []model.ScheduledPayment
+	for i, set := range payment.PaymentSet {
+		scheduled := model.ScheduledPayment{
+			ID:        payment.ScheduledPaymentID,
+			Payee:     payeeName(set.To),
+			Amount:    model.Money{Amount: strings.TrimPrefix(set.Amount, "-")},
+			Frequency: frequency,
+			NextDate:  payment.Recurrence.NextPaymentDate,
+			EndDate:   endDate,
+		}
+		if len(payment.PaymentSet) > 1 {
+			scheduled.ID = fmt.Sprintf("%s_%d", payment.ScheduledPaymentID, i+1)
+		}
+		if payment.From.AccountID != "" {
+			from := payment.From.AccountID
+			scheduled.FromAccountID = &from
+		}
+		description := payment.PayerReference
+		if description == "" {
+			description = payment.Nickname
+		}
+		if description != "" {
+			scheduled.Description = &description
+		}
+		result = append(result, scheduled)
+	}
+	return result
+}
+
+// payeeName picks the clearest name for where a scheduled payment goes
+func payeeName(to cdrPaymentTo) string {
+	switch {
+	case to.Nickname != "":
+		return to.Nickname
+	case to.Domestic != nil && to.Domestic.Account != nil:
+		return to.Domestic.Account.AccountName
+	case to.Biller != nil:
+		return to.Biller.BillerName
+	}
+	return ""
+}
+
+// recurrence maps a CDR recurrence onto a payment frequency and end date
+func recurrence(payment cdrScheduledPayment) (string, *string) {
+	var interval, final string
+	switch payment.Recurrence.RecurrenceUType {
+	case "onceOff":
+		return model.PaymentFrequencyOnce, nil
+	case "intervalSchedule":
+		if schedule := payment.Recurrence.IntervalSchedule; schedule != nil {
+			final = schedule.FinalPaymentDate
+			if len(schedule.Intervals) > 0 {
+				interval = schedule.Intervals[0].Interval
+			}
+		}
+	case "lastWeekDay":
+		if schedule := payment.Recurrence.LastWeekDay; schedule != nil {
+			final, interval = schedule.FinalPaymentDate, schedule.Interval
+		}
+	}
+
+	var endDate *string
+	if final != "" {
+		endDate = &final
+	}
+	switch interval {
+	case "P1W", "P7D":
+		return model.PaymentFrequencyWeekly, endDate
+	case "P2W", "P14D":
+		return model.PaymentFrequencyFortnightly, endDate
+	case "P3M":
+		return model.PaymentFrequencyQuarterly, endDate
+	case "P1Y", "P12M":
+		return model.PaymentFrequencyYearly, endDate
+	}
+	return model.PaymentFrequencyMonthly, endDate
+}
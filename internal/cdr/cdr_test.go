@@ -0,0 +1,133 @@
+package cdr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeDataHolder serves canned CDR Banking API responses, with the
+// transactions split over two pages
+func fakeDataHolder(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if user, pass, _ := r.BasicAuth(); user != "client" || pass != "secret" || r.FormValue("grant_type") != "client_credentials" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"access_token": "token", "expires_in": 600}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" || r.Header.Get("x-v") == "" || r.Header.Get("x-fapi-interaction-id") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/cds-au/v1/banking/accounts":
+			w.Write([]byte(`{"data": {"accounts": [
+				{"accountId": "acc-1", "displayName": "Everyday", "maskedNumber": "xxxx1234", "productCategory": "TRANS_AND_SAVINGS_ACCOUNTS", "productName": "NAB Classic Banking"},
+				{"accountId": "acc-2", "displayName": "Rewards Card", "maskedNumber": "xxxx9876", "productCategory": "CRED_AND_CHRG_CARDS", "productName": "NAB Qantas Rewards"}
+			]}, "links": {}}`))
+		case "/cds-au/v1/banking/accounts/balances":
+			w.Write([]byte(`{"data": {"balances": [
+				{"accountId": "acc-1", "currentBalance": "2543.67", "availableBalance": "2500.00"},
+				{"accountId": "acc-2", "currentBalance": "-1250.00", "availableBalance": "4750.00", "creditLimit": "6000.00"}
+			]}, "links": {}}`))
+		case "/cds-au/v1/banking/accounts/acc-1/transactions":
+			if r.URL.Query().Get("page") == "2" {
+				w.Write([]byte(`{"data": {"transactions": [
+					{"status": "POSTED", "description": "SALARY", "postingDateTime": "2024-05-01T09:00:00+10:00", "amount": "2500.00"},
+					{"status": "POSTED", "description": "EFTPOS COFFEE", "postingDateTime": "2024-05-01", "amount": "-4.50"},
+					{"status": "POSTED", "description": "EFTPOS COFFEE", "postingDateTime": "2024-05-01", "amount": "-4.50"}
+				]}, "links": {}}`))
+				return
+			}
+			if r.URL.Query().Get("oldest-time") != "2024-05-01T00:00:00Z" {
+				t.Errorf("unexpected oldest-time %q", r.URL.Query().Get("oldest-time"))
+			}
+			w.Write([]byte(`{"data": {"transactions": [
+				{"transactionId": "t-3", "status": "PENDING", "description": "PENDING", "amount": "-5.00"},
+				{"transactionId": "t-2", "status": "POSTED", "description": "EFTPOS COLES", "postingDateTime": "2024-05-02T12:30:00+10:00", "amount": "-85.67", "merchantName": "COLES"}
+			]}, "links": {"next": "` + server.URL + `/cds-au/v1/banking/accounts/acc-1/transactions?page=2"}}`))
+		case "/cds-au/v1/banking/payees":
+			w.Write([]byte(`{"data": {"payees": [{"payeeId": "p-1", "nickname": "Rent", "type": "DOMESTIC"}, {"payeeId": "p-2", "nickname": "Mum", "type": "DOMESTIC"}]}, "links": {}}`))
+		case "/cds-au/v1/banking/payees/p-1":
+			w.Write([]byte(`{"data": {"payeeId": "p-1", "nickname": "Rent", "payeeUType": "domestic", "domestic": {"payeeAccountUType": "account", "account": {"accountName": "J SMITH", "bsb": "062-000", "accountNumber": "12345678"}}}}`))
+		case "/cds-au/v1/banking/payees/p-2":
+			w.Write([]byte(`{"data": {"payeeId": "p-2", "nickname": "Mum", "payeeUType": "domestic", "domestic": {"payeeAccountUType": "payId", "payId": {"identifier": "mum@example.com"}}}}`))
+		case "/cds-au/v1/banking/payments/scheduled":
+			w.Write([]byte(`{"data": {"scheduledPayments": [
+				{"scheduledPaymentId": "s-1", "payerReference": "Rent", "status": "ACTIVE", "from": {"accountId": "acc-1"},
+				 "paymentSet": [{"to": {"toUType": "payeeId", "nickname": "J SMITH"}, "amount": "450.00"}],
+				 "recurrence": {"nextPaymentDate": "2024-06-01", "recurrenceUType": "intervalSchedule", "intervalSchedule": {"finalPaymentDate": "2025-06-01", "intervals": [{"interval": "P2W"}]}}},
+				{"scheduledPaymentId": "s-2", "status": "SKIP", "paymentSet": [], "recurrence": {"recurrenceUType": "onceOff"}}
+			]}, "links": {}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server
+}
+
+func TestClient(t *testing.T) {
+	server := fakeDataHolder(t)
+	defer server.Close()
+
+	client := NewClient(server.URL+"/cds-au/v1/", Credentials{TokenURL: server.URL + "/token", ClientID: "client", ClientSecret: "secret"})
+	ctx := context.Background()
+
+	accounts, err := client.GetAccounts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 2 || accounts[0].Type != "checking" || accounts[0].Balance.Amount != "2543.67" || *accounts[0].AccountNumber != "xxxx1234" {
+		t.Fatalf("unexpected accounts %+v", accounts)
+	}
+	if accounts[1].Type != "credit" || accounts[1].Credit == nil || accounts[1].Credit.CreditLimit.Amount != "6000.00" {
+		t.Errorf("unexpected credit card %+v", accounts[1])
+	}
+
+	transactions, err := client.GetAccountTransactionsSince(ctx, "acc-1", "2024-05-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transactions) != 4 {
+		t.Fatalf("expected pending transaction to be skipped, got %+v", transactions)
+	}
+	if transactions[0].ID != "t-2" || transactions[0].Date != "2024-05-02" || transactions[0].Merchant == nil || *transactions[0].Merchant != "COLES" {
+		t.Errorf("unexpected transaction %+v", transactions[0])
+	}
+	if transactions[1].ID == "" || transactions[1].Date != "2024-05-01" {
+		t.Errorf("expected a generated ID for the second page, got %+v", transactions[1])
+	}
+	if transactions[2].ID == transactions[3].ID {
+		t.Errorf("expected identical purchases to get their own IDs, got %s twice", transactions[2].ID)
+	}
+	again, err := client.GetAccountTransactionsSince(ctx, "acc-1", "2024-05-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range transactions {
+		if again[i].ID != transactions[i].ID || again[i].Balance.Amount != "" {
+			t.Errorf("expected transaction %d to keep ID %s without a balance, got %+v", i, transactions[i].ID, again[i])
+		}
+	}
+
+	payees, err := client.GetPayees(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payees) != 1 || payees[0].BSB != "062000" || payees[0].Name != "J SMITH" || *payees[0].Nickname != "Rent" {
+		t.Errorf("unexpected payees %+v", payees)
+	}
+
+	payments, err := client.GetScheduledPayments(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payments) != 1 || payments[0].Frequency != "fortnightly" || payments[0].NextDate != "2024-06-01" || *payments[0].EndDate != "2025-06-01" || *payments[0].FromAccountID != "acc-1" {
+		t.Errorf("unexpected scheduled payments %+v", payments)
+	}
+}
@@ -0,0 +1,180 @@
+// Package cdr reads accounts and transactions through the Consumer Data
+// Right (Open Banking) Banking APIs instead of scraping NAB's website
+package cdr
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Credentials authorise requests to the CDR Banking APIs. Accredited
+// intermediaries either issue a long-lived AccessToken or OAuth client
+// credentials that are exchanged for short-lived tokens at TokenURL.
+type Credentials struct {
+	AccessToken  string
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scope        string
+}
+
+// Client reads from a CDR data holder's Banking APIs, usually through an
+// accredited intermediary that holds the consent. It implements the same
+// interface as the scraping client, so it can stand in for it.
+type Client struct {
+	baseURL     string
+	credentials Credentials
+	httpClient  *http.Client
+	now         func() time.Time
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewClient creates a CDR client for the Banking APIs at baseURL, the
+// resource server path ending in /cds-au/v1
+func NewClient(baseURL string, credentials Credentials) *Client {
+	return &Client{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		credentials: credentials,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		now:         time.Now,
+	}
+}
+
+// links are the pagination links on a CDR response
+type links struct {
+	Next string `json:"next"`
+}
+
+// get fetches every page of a CDR endpoint at the given API version,
+// passing each response body to page
+func (c *Client) get(ctx context.Context, path, version string, query url.Values, page func(raw []byte) error) error {
+	next := c.baseURL + path
+	if len(query) > 0 {
+		next += "?" + query.Encode()
+	}
+
+	for next != "" {
+		raw, err := c.fetch(ctx, next, version)
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s: %w", path, err)
+		}
+		if err := page(raw); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+
+		var response struct {
+			Links links `json:"links"`
+		}
+		if err := json.Unmarshal(raw, &response); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+		next = response.Links.Next
+	}
+	return nil
+}
+
+// fetch sends one authorised request with the CDR version headers
+func (c *Client) fetch(ctx context.Context, endpoint, version string) ([]byte, error) {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("x-v", version)
+	req.Header.Set("x-min-v", "1")
+	req.Header.Set("x-fapi-interaction-id", interactionID())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		c.mu.Lock()
+		c.token = ""
+		c.mu.Unlock()
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	return raw, nil
+}
+
+// accessToken returns the configured access token, or a client
+// credentials token that is renewed a minute before it expires
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	if c.credentials.TokenURL == "" {
+		return c.credentials.AccessToken, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && c.now().Before(c.expires) {
+		return c.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if c.credentials.Scope != "" {
+		form.Set("scope", c.credentials.Scope)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.credentials.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(c.credentials.ClientID, c.credentials.ClientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get CDR access token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get CDR access token: unexpected status %d", resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil || token.AccessToken == "" {
+		return "", fmt.Errorf("failed to decode CDR access token")
+	}
+	c.token = token.AccessToken
+	c.expires = c.now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return c.token, nil
+}
+
+// interactionID returns a random UUID for the x-fapi-interaction-id
+// header, which data holders echo back for tracing
+func interactionID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	s := hex.EncodeToString(b)
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Feature flags gating experimental or risky behaviour
+const (
+	// FeaturePayments serves the transfer and Pay Anyone endpoints
+	FeaturePayments = "payments"
+
+	// FeatureStealth launches the browser in stealth mode, as
+	// BROWSER_STEALTH does
+	FeatureStealth = "stealth"
+)
+
+// featureDefaults are the known feature flags, and whether each is on
+// when FEATURE_FLAGS doesn't mention it
+var featureDefaults = map[string]bool{
+	FeaturePayments: true,
+	FeatureStealth:  false,
+}
+
+// FeatureFlags records whether each feature flag is on
+type FeatureFlags map[string]bool
+
+// Enabled reports whether the named feature is on
+func (f FeatureFlags) Enabled(name string) bool {
+	return f[name]
+}
+
+// Names returns the features that are on, sorted
+func (f FeatureFlags) Names() []string {
+	var names []string
+	for name, enabled := range f {
+		if enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseFeatureFlags applies a list such as "stealth,-payments" over the
+// defaults: a name turns its feature on and a leading - turns it off
+func parseFeatureFlags(items []string) (FeatureFlags, error) {
+	flags := make(FeatureFlags, len(featureDefaults))
+	for name, enabled := range featureDefaults {
+		flags[name] = enabled
+	}
+
+	for _, item := range items {
+		name, disabled := strings.CutPrefix(item, "-")
+		if _, ok := featureDefaults[name]; !ok {
+			known := make([]string, 0, len(featureDefaults))
+			for name := range featureDefaults {
+				known = append(known, name)
+			}
+			sort.Strings(known)
+			return nil, fmt.Errorf("unknown feature flag %q in FEATURE_FLAGS, expected one of %s", name, strings.Join(known, ", "))
+		}
+		flags[name] = !disabled
+	}
+	return flags, nil
+}
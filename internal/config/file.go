@@ -0,0 +1,99 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// envValue is an environment variable as it was before a config file
+// overrode it
+type envValue struct {
+	value string
+	set   bool
+}
+
+var (
+	fileMu sync.Mutex
+
+	// fileKeys are the keys set by the last config file read
+	fileKeys = map[string]bool{}
+
+	// originalEnv holds the environment's own values for keys a config
+	// file has overridden, so removing a key from the file restores them
+	originalEnv = map[string]envValue{}
+)
+
+// applyConfigFile sets the variables in CONFIG_FILE, a file of KEY=VALUE
+// lines, over the environment. It's read again on every load so settings
+// can be reloaded without a restart.
+func applyConfigFile() error {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+
+	values := map[string]string{}
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		var err error
+		if values, err = readConfigFile(path); err != nil {
+			return err
+		}
+	}
+
+	for key := range fileKeys {
+		if _, ok := values[key]; ok {
+			continue
+		}
+		if original := originalEnv[key]; original.set {
+			os.Setenv(key, original.value)
+		} else {
+			os.Unsetenv(key)
+		}
+		delete(fileKeys, key)
+	}
+
+	for key, value := range values {
+		if !fileKeys[key] {
+			original, set := os.LookupEnv(key)
+			originalEnv[key] = envValue{value: original, set: set}
+			fileKeys[key] = true
+		}
+		os.Setenv(key, value)
+	}
+	return nil
+}
+
+// readConfigFile parses KEY=VALUE lines, skipping blank lines and #
+// comments. Values may be quoted.
+func readConfigFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CONFIG_FILE: %w", err)
+	}
+	defer file.Close()
+
+	values := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || key == "CONFIG_FILE" {
+			return nil, fmt.Errorf("CONFIG_FILE line %d must be KEY=VALUE", line)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read CONFIG_FILE: %w", err)
+	}
+	return values, nil
+}
@@ -0,0 +1,161 @@
+package demo
+
+import (
+	"context"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// GetMessages returns generated inbox messages
+func (c *Client) GetMessages(ctx context.Context) ([]model.Message, error) {
+	now := c.now()
+	return []model.Message{
+		{
+			ID:      "msg_demo_001",
+			Subject: "Your NAB Reward Saver bonus interest",
+			Date:    now.AddDate(0, 0, -2).Format("2006-01-02"),
+			Body:    "You qualified for bonus interest on your NAB Reward Saver last month. Keep growing your balance to earn it again.",
+		},
+		{
+			ID:      "msg_demo_002",
+			Subject: "Your home loan statement is ready",
+			Date:    now.AddDate(0, 0, -9).Format("2006-01-02"),
+			Read:    true,
+			Body:    "Your latest home loan statement is available to view in NAB Internet Banking.",
+		},
+	}, nil
+}
+
+// GetPayees returns generated saved payees
+func (c *Client) GetPayees(ctx context.Context) ([]model.Payee, error) {
+	return []model.Payee{
+		{ID: "payee_demo_001", Name: "A CITIZEN", BSB: "063000", AccountNumber: "10203040", Nickname: stringPtr("Housemate")},
+		{ID: "payee_demo_002", Name: "NORTHSIDE FOOTBALL CLUB", BSB: "033000", AccountNumber: "556677"},
+	}, nil
+}
+
+// GetScheduledPayments returns the recurring transfers the generated
+// history is made of
+func (c *Client) GetScheduledPayments(ctx context.Context) ([]model.ScheduledPayment, error) {
+	now := c.now()
+	return []model.ScheduledPayment{
+		{
+			ID:            "sched_demo_001",
+			FromAccountID: stringPtr(c.accounts[everyday].id),
+			Payee:         c.accounts[savings].name,
+			Amount:        model.Money{Amount: formatCents(savingsTransfer)},
+			Frequency:     model.PaymentFrequencyMonthly,
+			NextDate:      nextDayOfMonth(now, 1).Format("2006-01-02"),
+		},
+		{
+			ID:            "sched_demo_002",
+			FromAccountID: stringPtr(c.accounts[everyday].id),
+			Payee:         c.accounts[homeLoan].name,
+			Amount:        model.Money{Amount: formatCents(loanRepayment)},
+			Frequency:     model.PaymentFrequencyMonthly,
+			NextDate:      nextDayOfMonth(now, 15).Format("2006-01-02"),
+			Description:   stringPtr("Loan repayment"),
+		},
+	}, nil
+}
+
+// GetDirectDebits returns the energy retailer's authority on the everyday
+// account
+func (c *Client) GetDirectDebits(ctx context.Context, accountID string) ([]model.DirectDebit, error) {
+	if accountID != c.accounts[everyday].id {
+		return []model.DirectDebit{}, nil
+	}
+	return []model.DirectDebit{
+		{
+			ID:            "dd_demo_001",
+			Merchant:      "AGL SALES PTY LTD",
+			UserID:        stringPtr("123456"),
+			LastDebitDate: stringPtr(nextDayOfMonth(c.now(), 8).AddDate(0, -1, 0).Format("2006-01-02")),
+			Status:        model.DirectDebitStatusActive,
+		},
+	}, nil
+}
+
+// GetSavingsGoals returns a holiday goal on the savings account, part way
+// to its target
+func (c *Client) GetSavingsGoals(ctx context.Context, accountID string) ([]model.SavingsGoal, error) {
+	if accountID != c.accounts[savings].id {
+		return []model.SavingsGoal{}, nil
+	}
+	percent := 37.5
+	return []model.SavingsGoal{
+		{
+			ID:              "goal_demo_001",
+			Name:            "Holiday",
+			TargetAmount:    &model.Money{Amount: "6000.00"},
+			SavedAmount:     model.Money{Amount: "2250.00"},
+			TargetDate:      stringPtr(nextDayOfMonth(c.now(), 1).AddDate(0, 8, 0).Format("2006-01-02")),
+			PercentComplete: &percent,
+		},
+	}, nil
+}
+
+// GetPayIDs returns a generated email PayID on the everyday account
+func (c *Client) GetPayIDs(ctx context.Context) ([]model.PayID, error) {
+	return []model.PayID{
+		{
+			ID:          "payid_demo_001",
+			Type:        model.PayIDTypeEmail,
+			Value:       "a.citizen@example.com",
+			DisplayName: stringPtr("A CITIZEN"),
+			AccountID:   stringPtr(c.accounts[everyday].id),
+			Status:      model.PayIDStatusActive,
+		},
+	}, nil
+}
+
+// GetCards returns the generated cards, reflecting any locks applied
+func (c *Client) GetCards(ctx context.Context) ([]model.Card, error) {
+	cards := []model.Card{
+		{
+			ID:         "card_demo_001",
+			Name:       "NAB Visa Debit",
+			Type:       model.CardTypeDebit,
+			Last4:      c.accounts[everyday].last4,
+			Cardholder: stringPtr("A CITIZEN"),
+			AccountID:  stringPtr(c.accounts[everyday].id),
+			Expiry:     stringPtr("11/28"),
+			Status:     model.CardStatusActive,
+		},
+		{
+			ID:         "card_demo_002",
+			Name:       c.accounts[card].name,
+			Type:       model.CardTypeCredit,
+			Last4:      c.accounts[card].last4,
+			Cardholder: stringPtr("A CITIZEN"),
+			AccountID:  stringPtr(c.accounts[card].id),
+			Expiry:     stringPtr("04/27"),
+			Status:     model.CardStatusActive,
+		},
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range cards {
+		if c.lockedCards[cards[i].ID] {
+			cards[i].Status = model.CardStatusLocked
+		}
+	}
+	return cards, nil
+}
+
+// SetCardLocked pretends to apply or remove the temporary block on a card
+func (c *Client) SetCardLocked(ctx context.Context, card model.Card, locked bool) (*model.Card, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.lockedCards == nil {
+		c.lockedCards = make(map[string]bool)
+	}
+	c.lockedCards[card.ID] = locked
+
+	card.Status = model.CardStatusActive
+	if locked {
+		card.Status = model.CardStatusLocked
+	}
+	return &card, nil
+}
@@ -0,0 +1,229 @@
+// Package demo serves realistic but entirely synthetic banking data, so the
+// API can be demonstrated publicly and clients developed without a NAB
+// login or anyone's real transactions.
+package demo
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// epoch is where the generated history starts. Every day from it is
+// generated from the seed and the date alone, so a day's transactions and
+// balances are the same whenever they're requested.
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// recentDays is how many days of transactions an account lists, like
+// NAB's recent transactions page
+const recentDays = 90
+
+// Client is a NAB client that generates its data. Payments, transfers and
+// dispute forms behave as they do on the mock client.
+type Client struct {
+	service.MockNABClient
+
+	seed     int64
+	accounts []accountSpec
+	now      func() time.Time
+
+	mu          sync.Mutex
+	ledgerDay   string
+	ledger      *ledger
+	lockedCards map[string]bool
+}
+
+// accountSpec is a generated account before any transactions
+type accountSpec struct {
+	id      string
+	name    string
+	kind    string
+	last4   string
+	opening int64
+}
+
+// NewClient creates a demo client. The same seed always generates the same
+// customer.
+func NewClient(seed int64) *Client {
+	rng := rand.New(rand.NewSource(seed))
+	accountID := func() string {
+		return fmt.Sprintf("%08d", 10000000+rng.Intn(89999999))
+	}
+	spec := func(name, kind string, opening int64) accountSpec {
+		id := accountID()
+		return accountSpec{id: id, name: name, kind: kind, last4: id[4:], opening: opening}
+	}
+
+	return &Client{
+		seed: seed,
+		accounts: []accountSpec{
+			spec("NAB Classic Banking", model.AccountTypeChecking, 150000+rng.Int63n(150000)),
+			spec("NAB Reward Saver", model.AccountTypeSavings, 800000+rng.Int63n(1200000)),
+			spec("NAB Low Rate Card", model.AccountTypeCredit, -(20000 + rng.Int63n(70000))),
+			spec("NAB Base Variable Rate Home Loan", model.AccountTypeLoan, -(48000000 + rng.Int63n(4000000))),
+		},
+		now: time.Now,
+	}
+}
+
+// Account roles, indexing Client.accounts
+const (
+	everyday = iota
+	savings
+	card
+	homeLoan
+)
+
+// Rates and amounts the generated customer lives by, in cents where they
+// are amounts
+const (
+	savingsRate     = 4.50
+	loanRate        = 6.24
+	creditLimit     = 800000
+	loanRepayment   = 285000
+	savingsTransfer = 100000
+)
+
+// GetAccounts returns the generated accounts with today's balances
+func (c *Client) GetAccounts(ctx context.Context) ([]model.Account, error) {
+	l := c.currentLedger()
+	today := c.now()
+
+	accounts := make([]model.Account, 0, len(c.accounts))
+	for i, spec := range c.accounts {
+		balance := l.balances[i]
+		account := model.Account{
+			ID:            spec.id,
+			Name:          spec.name,
+			Type:          spec.kind,
+			Balance:       model.Money{Amount: formatCents(balance)},
+			AccountNumber: stringPtr("****" + spec.last4),
+		}
+		if spec.kind != model.AccountTypeCredit {
+			account.BSB = stringPtr("083004")
+		}
+
+		switch i {
+		case everyday, savings:
+			account.AvailableBalance = &model.Money{Amount: formatCents(balance)}
+		case card:
+			available := creditLimit + balance
+			account.AvailableBalance = &model.Money{Amount: formatCents(available)}
+			statementEnd := nextDayOfMonth(today, 27)
+			account.Credit = &model.CreditDetails{
+				CreditLimit:      model.Money{Amount: formatCents(creditLimit)},
+				AvailableCredit:  model.Money{Amount: formatCents(available)},
+				StatementBalance: &model.Money{Amount: formatCents(l.statementBalance)},
+				MinimumPayment:   &model.Money{Amount: formatCents(l.statementBalance / 50)},
+				PaymentDueDate:   stringPtr(nextDayOfMonth(today, 20).Format("2006-01-02")),
+				StatementPeriod: &model.StatementPeriod{
+					Start: statementEnd.AddDate(0, -1, 1).Format("2006-01-02"),
+					End:   statementEnd.Format("2006-01-02"),
+				},
+			}
+		}
+		if i == savings {
+			account.Interest = &model.InterestDetails{
+				Rate:                    fmt.Sprintf("%.2f", savingsRate),
+				BaseRate:                stringPtr("0.10"),
+				BonusRate:               stringPtr(fmt.Sprintf("%.2f", savingsRate-0.10)),
+				EarnedThisFinancialYear: &model.Money{Amount: formatCents(l.interestThisFinancialYear)},
+				BonusQualified:          boolPtr(true),
+			}
+		}
+		accounts = append(accounts, account)
+	}
+
+	return accounts, nil
+}
+
+// GetAccountTransactions returns an account's generated transactions over
+// the last recentDays days, newest first
+func (c *Client) GetAccountTransactions(ctx context.Context, accountID string) ([]model.Transaction, error) {
+	for i, spec := range c.accounts {
+		if spec.id == accountID {
+			recent := c.currentLedger().recent[i]
+			transactions := make([]model.Transaction, len(recent))
+			for j := range recent {
+				transactions[j] = recent[len(recent)-1-j]
+			}
+			return transactions, nil
+		}
+	}
+	return nil, service.ErrAccountNotFound
+}
+
+// GetAccountTransactionsSince returns an account's transactions dated on
+// or after since, newest first
+func (c *Client) GetAccountTransactionsSince(ctx context.Context, accountID string, since string) ([]model.Transaction, error) {
+	transactions, err := c.GetAccountTransactions(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	for i, transaction := range transactions {
+		if transaction.Date < since {
+			return transactions[:i], nil
+		}
+	}
+	return transactions, nil
+}
+
+// GetLoanDetails returns the home loan's terms
+func (c *Client) GetLoanDetails(ctx context.Context, accountID string) (*model.LoanDetails, error) {
+	if accountID != c.accounts[homeLoan].id {
+		return nil, service.ErrAccountNotFound
+	}
+	return &model.LoanDetails{
+		InterestRate:       fmt.Sprintf("%.2f", loanRate),
+		RepaymentAmount:    &model.Money{Amount: formatCents(loanRepayment)},
+		RepaymentFrequency: model.RepaymentFrequencyMonthly,
+		NextRepaymentDate:  stringPtr(nextDayOfMonth(c.now(), 15).Format("2006-01-02")),
+		RedrawAvailable:    &model.Money{Amount: "12480.00"},
+		OriginalAmount:     &model.Money{Amount: "560000.00"},
+	}, nil
+}
+
+// currentLedger returns the ledger up to today, generating it once a day
+func (c *Client) currentLedger() *ledger {
+	today := c.now().Format("2006-01-02")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ledger == nil || c.ledgerDay != today {
+		c.ledger = c.generate(today)
+		c.ledgerDay = today
+	}
+	return c.ledger
+}
+
+// nextDayOfMonth returns the next date falling on day of the month, today
+// included
+func nextDayOfMonth(now time.Time, day int) time.Time {
+	next := time.Date(now.Year(), now.Month(), day, 0, 0, 0, 0, now.Location())
+	if next.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())) {
+		next = next.AddDate(0, 1, 0)
+	}
+	return next
+}
+
+// formatCents formats cents as a dollar amount with two decimal places
+func formatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
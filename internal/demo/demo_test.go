@@ -0,0 +1,58 @@
+package demo
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientIsDeterministic(t *testing.T) {
+	now := func() time.Time { return time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC) }
+	first, second := NewClient(7), NewClient(7)
+	first.now, second.now = now, now
+
+	accounts, err := first.GetAccounts(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := second.GetAccounts(context.Background())
+	if !reflect.DeepEqual(accounts, again) {
+		t.Fatal("expected the same seed to generate the same accounts")
+	}
+	other := NewClient(8)
+	other.now = now
+	if otherAccounts, _ := other.GetAccounts(context.Background()); otherAccounts[0].ID == accounts[0].ID {
+		t.Error("expected a different seed to generate a different customer")
+	}
+
+	for _, account := range accounts {
+		transactions, err := first.GetAccountTransactions(context.Background(), account.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(transactions) == 0 {
+			t.Fatalf("expected transactions for %s", account.Name)
+		}
+		if transactions[0].Balance != account.Balance {
+			t.Errorf("%s: latest transaction balance %s doesn't match the account balance %s", account.Name, transactions[0].Balance.Amount, account.Balance.Amount)
+		}
+		// Newest first, each balance is the older one plus the amount
+		for i := 0; i+1 < len(transactions); i++ {
+			if cents(t, transactions[i+1].Balance.Amount)+cents(t, transactions[i].Amount.Amount) != cents(t, transactions[i].Balance.Amount) {
+				t.Fatalf("%s: running balance breaks at %s", account.Name, transactions[i].ID)
+			}
+		}
+	}
+}
+
+func cents(t *testing.T, amount string) int64 {
+	t.Helper()
+	value, err := strconv.ParseInt(strings.Replace(amount, ".", "", 1), 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
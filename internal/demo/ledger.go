@@ -0,0 +1,157 @@
+package demo
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// merchant is somewhere the generated customer spends money
+type merchant struct {
+	description string
+	name        string
+	category    string
+	min, max    int64
+}
+
+// Everyday spending, by habit. Each day, each habit has its chance of
+// happening at one of its merchants.
+var habits = []struct {
+	chance    float64
+	account   int
+	merchants []merchant
+}{
+	{0.35, everyday, []merchant{
+		{"EFTPOS Purchase - WOOLWORTHS", "WOOLWORTHS", "Groceries", 2500, 18000},
+		{"EFTPOS Purchase - COLES SUPERMARKET", "COLES SUPERMARKET", "Groceries", 2500, 16000},
+		{"EFTPOS Purchase - ALDI STORES", "ALDI STORES", "Groceries", 2000, 9000},
+	}},
+	{0.5, card, []merchant{
+		{"VISA Purchase - SEVEN SEEDS CARLTON", "SEVEN SEEDS", "Coffee", 450, 1200},
+		{"VISA Purchase - PATRICIA COFFEE BREWERS", "PATRICIA COFFEE BREWERS", "Coffee", 450, 900},
+	}},
+	{0.2, card, []merchant{
+		{"VISA Purchase - GUZMAN Y GOMEZ", "GUZMAN Y GOMEZ", "Dining", 1500, 4500},
+		{"VISA Purchase - UBER EATS", "UBER EATS", "Dining", 2500, 7500},
+		{"VISA Purchase - CHIN CHIN MELBOURNE", "CHIN CHIN", "Dining", 6000, 16000},
+	}},
+	{0.3, everyday, []merchant{
+		{"EFTPOS Purchase - MYKI TOP UP", "PTV MYKI", "Transport", 500, 2000},
+	}},
+	{0.12, everyday, []merchant{
+		{"EFTPOS Purchase - BP CONNECT", "BP", "Fuel", 6000, 11000},
+		{"EFTPOS Purchase - AMPOL FOODARY", "AMPOL", "Fuel", 5500, 10500},
+	}},
+	{0.1, card, []merchant{
+		{"VISA Purchase - KMART", "KMART", "Shopping", 1500, 12000},
+		{"VISA Purchase - JB HI-FI", "JB HI-FI", "Shopping", 3000, 40000},
+		{"VISA Purchase - BUNNINGS WAREHOUSE", "BUNNINGS WAREHOUSE", "Home", 2000, 25000},
+	}},
+}
+
+// ledger is the generated history of every account
+type ledger struct {
+	balances                  []int64
+	recent                    [][]model.Transaction
+	statementBalance          int64
+	interestThisFinancialYear int64
+}
+
+// generate replays every day from the epoch to today. Each day's events
+// are drawn from a generator seeded with the seed and the day alone.
+func (c *Client) generate(today string) *ledger {
+	l := &ledger{
+		balances: make([]int64, len(c.accounts)),
+		recent:   make([][]model.Transaction, len(c.accounts)),
+	}
+	for i, spec := range c.accounts {
+		l.balances[i] = spec.opening
+	}
+	end, _ := time.Parse("2006-01-02", today)
+	recentFrom := end.AddDate(0, 0, -(recentDays - 1))
+	financialYear := time.Date(end.Year(), time.July, 1, 0, 0, 0, 0, time.UTC)
+	if end.Before(financialYear) {
+		financialYear = financialYear.AddDate(-1, 0, 0)
+	}
+
+	for day, index := epoch, int64(0); !day.After(end); day, index = day.AddDate(0, 0, 1), index+1 {
+		rng := rand.New(rand.NewSource(c.seed*1000003 + index))
+		date := day.Format("2006-01-02")
+		sequence := 0
+		post := func(account int, cents int64, description, name, category string) {
+			l.balances[account] += cents
+			sequence++
+			if day.Before(recentFrom) {
+				return
+			}
+			transaction := model.Transaction{
+				ID:          fmt.Sprintf("txn_%s_%s_%02d", day.Format("20060102"), c.accounts[account].id, sequence),
+				Date:        date,
+				Description: description,
+				Amount:      model.Money{Amount: formatCents(cents)},
+				Balance:     model.Money{Amount: formatCents(l.balances[account])},
+				Category:    stringPtr(category),
+			}
+			if name != "" {
+				transaction.Merchant = stringPtr(name)
+			}
+			l.recent[account] = append(l.recent[account], transaction)
+		}
+		between := func(min, max int64) int64 {
+			return min + rng.Int63n(max-min+1)
+		}
+
+		// Interest is paid and charged on the first of the month
+		if day.Day() == 1 {
+			interest := int64(float64(l.balances[savings]) * savingsRate / 100 / 12)
+			post(savings, interest, "Interest Paid", "", "Interest")
+			if !day.Before(financialYear) {
+				l.interestThisFinancialYear += interest
+			}
+			post(homeLoan, int64(float64(l.balances[homeLoan])*loanRate/100/12), "Interest Charged", "", "Interest")
+			post(everyday, -savingsTransfer, "Online Transfer to NAB Reward Saver", "", "Transfer")
+			post(savings, savingsTransfer, "Online Transfer from NAB Classic Banking", "", "Transfer")
+		}
+
+		// Pay comes in every second Thursday
+		if day.Weekday() == time.Thursday && (index/7)%2 == 0 {
+			post(everyday, between(318000, 334000), "Direct Credit - ACME CORP PAYROLL", "ACME CORP", "Income")
+		}
+
+		switch day.Day() {
+		case 5:
+			post(card, -1899, "VISA Purchase - NETFLIX.COM", "NETFLIX", "Subscriptions")
+		case 8:
+			post(everyday, -between(14000, 23000), "Direct Debit - AGL SALES PTY LTD", "AGL SALES PTY LTD", "Utilities")
+		case 12:
+			post(card, -1399, "VISA Purchase - SPOTIFY", "SPOTIFY", "Subscriptions")
+		case 15:
+			post(everyday, -loanRepayment, "Loan Repayment - NAB Home Loan", "", "Transfer")
+			post(homeLoan, loanRepayment, "Repayment Received", "", "Transfer")
+		case 20:
+			// The card is paid off in full from the everyday account
+			if owing := -l.balances[card]; owing > 0 {
+				l.statementBalance = owing
+				post(everyday, -owing, "Online Payment to NAB Low Rate Card", "", "Transfer")
+				post(card, owing, "Payment Received - Thank You", "", "Transfer")
+			}
+		}
+
+		// More is spent in the lead up to Christmas
+		spending := 1.0
+		if day.Month() == time.December && day.Day() < 25 {
+			spending = 1.6
+		}
+		for _, habit := range habits {
+			if rng.Float64() >= habit.chance*spending {
+				continue
+			}
+			m := habit.merchants[rng.Intn(len(habit.merchants))]
+			post(habit.account, -between(m.min, m.max), m.description, m.name, m.category)
+		}
+	}
+
+	return l
+}
@@ -0,0 +1,58 @@
+// Package enrich identifies the merchants behind raw NAB transaction
+// descriptions like "EFTPOS 1234 COLES 0482 MELB".
+package enrich
+
+import (
+	"context"
+	"log"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// Provider recognises the merchant behind a transaction description. It
+// returns nil when it doesn't recognise one.
+type Provider interface {
+	Name() string
+	Merchant(ctx context.Context, description string) (*model.MerchantDetails, error)
+}
+
+// Pipeline enriches transactions by asking each of its providers in turn,
+// so local rules can be backed by an external API or the other way around
+type Pipeline struct {
+	providers []Provider
+	logger    *log.Logger
+}
+
+// NewPipeline creates a pipeline asking the providers in the given order
+func NewPipeline(logger *log.Logger, providers ...Provider) *Pipeline {
+	return &Pipeline{
+		providers: providers,
+		logger:    logger,
+	}
+}
+
+// Enrich fills in the merchant details of transactions, taking the first
+// provider to recognise each merchant and also setting the merchant name
+// where the scrape didn't. A provider that fails is logged and skipped, so
+// an unreachable API never fails a sync.
+func (p *Pipeline) Enrich(ctx context.Context, transactions []model.Transaction) {
+	for i := range transactions {
+		for _, provider := range p.providers {
+			merchant, err := provider.Merchant(ctx, transactions[i].Description)
+			if err != nil {
+				p.logger.Printf("Merchant enrichment by %s failed for %q: %v", provider.Name(), transactions[i].Description, err)
+				continue
+			}
+			if merchant == nil {
+				continue
+			}
+			merchant.Source = provider.Name()
+			transactions[i].MerchantDetails = merchant
+			if transactions[i].Merchant == nil {
+				name := merchant.Name
+				transactions[i].Merchant = &name
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package enrich
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/search"
+)
+
+// knownMerchant is a merchant the local rules recognise by name
+type knownMerchant struct {
+	// phrases are normalised descriptions the merchant's start with
+	phrases []string
+	name    string
+	id      string
+	domain  string
+}
+
+// knownMerchants are matched in order, so longer phrases come before
+// shorter ones they start with
+var knownMerchants = []knownMerchant{
+	{[]string{"coles"}, "Coles", "coles", "coles.com.au"},
+	{[]string{"woolworths", "woolies"}, "Woolworths", "woolworths", "woolworths.com.au"},
+	{[]string{"aldi"}, "Aldi", "aldi", "aldi.com.au"},
+	{[]string{"iga"}, "IGA", "iga", "iga.com.au"},
+	{[]string{"bunnings"}, "Bunnings", "bunnings", "bunnings.com.au"},
+	{[]string{"kmart"}, "Kmart", "kmart", "kmart.com.au"},
+	{[]string{"target"}, "Target", "target", "target.com.au"},
+	{[]string{"officeworks"}, "Officeworks", "officeworks", "officeworks.com.au"},
+	{[]string{"jb hi fi", "jbhifi"}, "JB Hi-Fi", "jb-hi-fi", "jbhifi.com.au"},
+	{[]string{"chemist warehouse"}, "Chemist Warehouse", "chemist-warehouse", "chemistwarehouse.com.au"},
+	{[]string{"7 eleven"}, "7-Eleven", "7-eleven", "7eleven.com.au"},
+	{[]string{"mcdonalds", "mcdonald s"}, "McDonald's", "mcdonalds", "mcdonalds.com.au"},
+	{[]string{"uber eats", "ubereats"}, "Uber Eats", "uber-eats", "ubereats.com"},
+	{[]string{"uber"}, "Uber", "uber", "uber.com"},
+	{[]string{"netflix"}, "Netflix", "netflix", "netflix.com"},
+	{[]string{"spotify"}, "Spotify", "spotify", "spotify.com"},
+	{[]string{"amazon"}, "Amazon", "amazon", "amazon.com.au"},
+	{[]string{"apple com"}, "Apple", "apple", "apple.com"},
+	{[]string{"telstra"}, "Telstra", "telstra", "telstra.com.au"},
+	{[]string{"optus"}, "Optus", "optus", "optus.com.au"},
+	{[]string{"agl"}, "AGL", "agl", "agl.com.au"},
+	{[]string{"origin energy"}, "Origin Energy", "origin-energy", "originenergy.com.au"},
+	{[]string{"bp"}, "BP", "bp", "bp.com.au"},
+	{[]string{"myki"}, "Myki", "myki", "ptv.vic.gov.au"},
+	{[]string{"opal"}, "Opal", "opal", "transportnsw.info"},
+}
+
+// transactionTypeWords are how NAB prefixes card and debit transactions,
+// before the merchant
+var transactionTypeWords = map[string]bool{
+	"eftpos":        true,
+	"purchase":      true,
+	"visa":          true,
+	"mastercard":    true,
+	"debit":         true,
+	"credit":        true,
+	"direct":        true,
+	"card":          true,
+	"pos":           true,
+	"online":        true,
+	"contactless":   true,
+	"recurring":     true,
+	"authorisation": true,
+}
+
+// nonMerchantWords mark transactions that aren't with a merchant at all
+var nonMerchantWords = map[string]bool{
+	"transfer":   true,
+	"interest":   true,
+	"salary":     true,
+	"wages":      true,
+	"payroll":    true,
+	"atm":        true,
+	"withdrawal": true,
+	"fee":        true,
+	"repayment":  true,
+}
+
+// states are Australian state and territory codes, and the country codes
+// that can follow them
+var (
+	states    = map[string]bool{"vic": true, "nsw": true, "qld": true, "sa": true, "wa": true, "tas": true, "nt": true, "act": true}
+	countries = map[string]bool{"au": true, "aus": true, "australia": true}
+)
+
+// cities maps how NAB abbreviates cities to their names
+var cities = map[string]string{
+	"melb":      "Melbourne",
+	"melbourne": "Melbourne",
+	"syd":       "Sydney",
+	"sydney":    "Sydney",
+	"bris":      "Brisbane",
+	"brisbane":  "Brisbane",
+	"adel":      "Adelaide",
+	"adelaide":  "Adelaide",
+	"perth":     "Perth",
+	"hobart":    "Hobart",
+	"canb":      "Canberra",
+	"canberra":  "Canberra",
+	"darwin":    "Darwin",
+	"geelong":   "Geelong",
+}
+
+// corporateSuffixes are dropped from the end of merchant names
+var corporateSuffixes = map[string]bool{"pty": true, "ltd": true, "limited": true, "inc": true}
+
+// Rules recognises merchants with local rules. The description is
+// stripped of NAB's transaction type, reference and store numbers and a
+// trailing location, then matched against well-known merchants; anything
+// else left over is taken as the merchant's name.
+type Rules struct{}
+
+// NewRules creates the local rules provider
+func NewRules() *Rules {
+	return &Rules{}
+}
+
+// Name identifies the provider in enriched transactions
+func (r *Rules) Name() string {
+	return "rules"
+}
+
+// Merchant returns the merchant behind a description, or nil for
+// transfers, interest, fees and other transactions without one
+func (r *Rules) Merchant(ctx context.Context, description string) (*model.MerchantDetails, error) {
+	for _, word := range strings.Fields(search.Normalize(description)) {
+		if nonMerchantWords[word] {
+			return nil, nil
+		}
+	}
+
+	// "EFTPOS Purchase - COLES SUPERMARKET" names the type before a dash
+	if kind, rest, ok := strings.Cut(description, " - "); ok && onlyTransactionType(kind) {
+		description = rest
+	}
+
+	var tokens []string
+	for _, token := range strings.Fields(strings.ToLower(description)) {
+		if !isReference(token) {
+			tokens = append(tokens, token)
+		}
+	}
+	for len(tokens) > 0 && transactionTypeWords[strings.Trim(tokens[0], "-:")] {
+		tokens = tokens[1:]
+	}
+	tokens, location := trimLocation(tokens)
+	for len(tokens) > 0 && corporateSuffixes[strings.Trim(tokens[len(tokens)-1], ".")] {
+		tokens = tokens[:len(tokens)-1]
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	merchant := &model.MerchantDetails{Location: location}
+	normalized := search.Normalize(strings.Join(tokens, " ")) + " "
+	for _, known := range knownMerchants {
+		for _, phrase := range known.phrases {
+			if strings.HasPrefix(normalized, phrase+" ") {
+				domain := known.domain
+				merchant.Name, merchant.ID, merchant.Domain = known.name, known.id, &domain
+				return merchant, nil
+			}
+		}
+	}
+
+	words := make([]string, len(tokens))
+	for i, token := range tokens {
+		words[i] = titleCase(token)
+	}
+	merchant.Name = strings.Join(words, " ")
+	merchant.ID = strings.ReplaceAll(search.Normalize(merchant.Name), " ", "-")
+	if merchant.ID == "" {
+		return nil, nil
+	}
+	return merchant, nil
+}
+
+// onlyTransactionType reports whether text is nothing but transaction
+// type words, like "EFTPOS Purchase"
+func onlyTransactionType(text string) bool {
+	words := strings.Fields(strings.ToLower(text))
+	for _, word := range words {
+		if !transactionTypeWords[word] {
+			return false
+		}
+	}
+	return len(words) > 0
+}
+
+// isReference reports whether a token is a reference, card or store
+// number rather than part of the merchant's name: three or more digits,
+// or nothing but digits and punctuation
+func isReference(token string) bool {
+	digits, letters := 0, 0
+	for _, r := range token {
+		switch {
+		case unicode.IsDigit(r):
+			digits++
+		case unicode.IsLetter(r):
+			letters++
+		}
+	}
+	return digits >= 3 || (digits > 0 && letters == 0) || (letters == 0 && digits == 0)
+}
+
+// trimLocation removes a trailing city, state and country from the tokens,
+// returning what was removed as a location like "Melbourne VIC". At least
+// one token is always left for the merchant's name.
+func trimLocation(tokens []string) ([]string, *string) {
+	var city, state string
+	for len(tokens) > 1 {
+		last := tokens[len(tokens)-1]
+		switch {
+		case countries[last] && city == "" && state == "":
+		case states[last] && city == "" && state == "":
+			state = strings.ToUpper(last)
+		case cities[last] != "" && city == "":
+			city = cities[last]
+		default:
+			return tokens, location(city, state)
+		}
+		tokens = tokens[:len(tokens)-1]
+	}
+	return tokens, location(city, state)
+}
+
+// location joins a city and state, either of which may be empty
+func location(city, state string) *string {
+	joined := strings.TrimSpace(city + " " + state)
+	if joined == "" {
+		return nil
+	}
+	return &joined
+}
+
+// titleCase capitalises the first letter of a word and lower cases the rest
+func titleCase(word string) string {
+	runes := []rune(strings.ToLower(word))
+	for i, r := range runes {
+		if unicode.IsLetter(r) {
+			runes[i] = unicode.ToUpper(r)
+			break
+		}
+	}
+	return string(runes)
+}
@@ -0,0 +1,81 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestRulesMerchant(t *testing.T) {
+	tests := []struct {
+		description  string
+		wantName     string
+		wantID       string
+		wantLocation string
+	}{
+		{"EFTPOS 1234 COLES 0482 MELB", "Coles", "coles", "Melbourne"},
+		{"EFTPOS Purchase - COLES SUPERMARKET", "Coles", "coles", ""},
+		{"VISA PURCHASE 7-ELEVEN 2145 SYDNEY NSW AU", "7-Eleven", "7-eleven", "Sydney NSW"},
+		{"Direct Debit - NETFLIX.COM 12345678", "Netflix", "netflix", ""},
+		{"EFTPOS BLUE BOTTLE CAFE PTY LTD MELBOURNE VIC", "Blue Bottle Cafe", "blue-bottle-cafe", "Melbourne VIC"},
+		{"UBER *EATS HELP.UBER.COM", "Uber Eats", "uber-eats", ""},
+		{"UBER *TRIP 4021 MELB", "Uber", "uber", "Melbourne"},
+	}
+	rules := NewRules()
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			merchant, err := rules.Merchant(context.Background(), tt.description)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if merchant == nil {
+				t.Fatal("expected a merchant")
+			}
+			location := ""
+			if merchant.Location != nil {
+				location = *merchant.Location
+			}
+			if merchant.Name != tt.wantName || merchant.ID != tt.wantID || location != tt.wantLocation {
+				t.Errorf("got %s (%s) in %q, want %s (%s) in %q", merchant.Name, merchant.ID, location, tt.wantName, tt.wantID, tt.wantLocation)
+			}
+		})
+	}
+
+	for _, description := range []string{"Direct Credit - SALARY PAYMENT", "ATM Withdrawal - NAB ATM", "TFR TO J SMITH REF 99231", "Interest", "Loan Repayment - NAB Home Loan", "EFTPOS 1234"} {
+		if merchant, _ := rules.Merchant(context.Background(), description); merchant != nil {
+			t.Errorf("expected no merchant for %q, got %+v", description, merchant)
+		}
+	}
+}
+
+// failingProvider is an external provider that can't be reached
+type failingProvider struct{}
+
+func (failingProvider) Name() string { return "api" }
+
+func (failingProvider) Merchant(ctx context.Context, description string) (*model.MerchantDetails, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestPipelineFallsBackPastFailingProviders(t *testing.T) {
+	pipeline := NewPipeline(log.New(io.Discard, "", 0), failingProvider{}, NewRules())
+	transactions := []model.Transaction{
+		{Description: "EFTPOS 1234 COLES 0482 MELB"},
+		{Description: "Interest"},
+	}
+	pipeline.Enrich(context.Background(), transactions)
+
+	if details := transactions[0].MerchantDetails; details == nil || details.Source != "rules" || details.Name != "Coles" {
+		t.Fatalf("expected the rules to recognise Coles, got %+v", details)
+	}
+	if transactions[0].Merchant == nil || *transactions[0].Merchant != "Coles" {
+		t.Errorf("expected the merchant name filled in, got %v", transactions[0].Merchant)
+	}
+	if transactions[1].MerchantDetails != nil {
+		t.Errorf("expected no merchant for interest, got %+v", transactions[1].MerchantDetails)
+	}
+}
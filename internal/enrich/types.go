@@ -0,0 +1,87 @@
+package enrich
+
+import (
+	"strings"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/search"
+)
+
+// transactionTypes are matched in order against whole words of the
+// normalised description. How the money moved comes first, so a direct
+// debit for a gym's fee is a direct debit, then fees before ATMs, so an
+// ATM operator fee is a fee.
+var transactionTypes = []struct {
+	phrases         []string
+	transactionType string
+}{
+	{[]string{"bpay"}, model.TransactionTypeBPAY},
+	{[]string{"direct debit"}, model.TransactionTypeDirectDebit},
+	{[]string{"direct credit", "salary"}, model.TransactionTypeDirectCredit},
+	{[]string{"transfer", "payment anyone", "osko", "payid"}, model.TransactionTypeTransfer},
+	{[]string{"fee", "fees"}, model.TransactionTypeFee},
+	{[]string{"interest"}, model.TransactionTypeInterest},
+	{[]string{"atm", "cash withdrawal"}, model.TransactionTypeATM},
+	{[]string{"eftpos", "purchase", "visa"}, model.TransactionTypePurchase},
+}
+
+// feeTypes are matched in order against whole words of a fee's
+// normalised description
+var feeTypes = []struct {
+	phrases []string
+	feeType string
+}{
+	{[]string{"atm"}, model.FeeTypeATM},
+	{[]string{"international", "foreign", "overseas", "currency conversion"}, model.FeeTypeInternational},
+	{[]string{"account", "keeping", "monthly", "service"}, model.FeeTypeAccountKeeping},
+}
+
+// Classify fills in a transaction's type, and for fees the kind of fee
+func Classify(transaction *model.Transaction) {
+	transaction.Type = TransactionType(*transaction)
+	transaction.FeeType = ""
+	if transaction.Type == model.TransactionTypeFee {
+		transaction.FeeType = FeeType(*transaction)
+	}
+}
+
+// FeeType tells account keeping, international transaction and ATM fees
+// apart by their descriptions, giving FeeTypeOther for any other fee
+func FeeType(transaction model.Transaction) string {
+	text := " " + normalizedDescription(transaction) + " "
+	for _, candidate := range feeTypes {
+		for _, phrase := range candidate.phrases {
+			if strings.Contains(text, " "+phrase+" ") {
+				return candidate.feeType
+			}
+		}
+	}
+	return model.FeeTypeOther
+}
+
+// normalizedDescription returns the transaction's search text, normalising
+// its description if that hasn't been done yet
+func normalizedDescription(transaction model.Transaction) string {
+	if transaction.SearchText != "" {
+		return transaction.SearchText
+	}
+	return search.Normalize(transaction.Description)
+}
+
+// TransactionType infers the kind of a transaction from its description.
+// Unrecognised money out is taken as a card purchase, as NAB often shows
+// those as just the merchant; unrecognised money in is left untyped.
+func TransactionType(transaction model.Transaction) string {
+	text := " " + normalizedDescription(transaction) + " "
+	for _, candidate := range transactionTypes {
+		for _, phrase := range candidate.phrases {
+			if strings.Contains(text, " "+phrase+" ") {
+				return candidate.transactionType
+			}
+		}
+	}
+	if strings.HasPrefix(strings.TrimSpace(transaction.Amount.Amount), "-") {
+		return model.TransactionTypePurchase
+	}
+	return ""
+}
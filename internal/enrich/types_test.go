@@ -0,0 +1,57 @@
+package enrich
+
+import (
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestTransactionType(t *testing.T) {
+	tests := []struct {
+		description string
+		amount      string
+		want        string
+	}{
+		{"EFTPOS Purchase - COLES SUPERMARKET", "-85.67", model.TransactionTypePurchase},
+		{"COLES 1234 SYDNEY", "-12.40", model.TransactionTypePurchase},
+		{"TFR TO J SMITH REF 99231", "-50.00", model.TransactionTypeTransfer},
+		{"Online Transfer from Savings", "200.00", model.TransactionTypeTransfer},
+		{"Direct Debit - NETFLIX.COM", "-16.99", model.TransactionTypeDirectDebit},
+		{"DD ANYTIME FITNESS MEMBERSHIP FEE", "-25.00", model.TransactionTypeDirectDebit},
+		{"Direct Credit - SALARY PAYMENT", "2500.00", model.TransactionTypeDirectCredit},
+		{"ATM Withdrawal - NAB ATM", "-100.00", model.TransactionTypeATM},
+		{"ATM OPERATOR FEE", "-3.00", model.TransactionTypeFee},
+		{"INTL TRANSACTION FEE", "-2.10", model.TransactionTypeFee},
+		{"INTEREST PAID", "4.12", model.TransactionTypeInterest},
+		{"BPAY AGL ENERGY", "-180.00", model.TransactionTypeBPAY},
+		{"REFUND", "5.00", ""},
+	}
+	for _, tt := range tests {
+		transaction := model.Transaction{Description: tt.description, Amount: model.Money{Amount: tt.amount}}
+		if got := TransactionType(transaction); got != tt.want {
+			t.Errorf("%q: got %q, want %q", tt.description, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyFees(t *testing.T) {
+	tests := map[string]string{
+		"MONTHLY ACCOUNT FEE":             model.FeeTypeAccountKeeping,
+		"INTL TRANSACTION FEE":            model.FeeTypeInternational,
+		"FOREIGN CURRENCY CONVERSION FEE": model.FeeTypeInternational,
+		"ATM OPERATOR FEE":                model.FeeTypeATM,
+		"LATE PAYMENT FEE":                model.FeeTypeOther,
+	}
+	for description, want := range tests {
+		transaction := model.Transaction{Description: description, Amount: model.Money{Amount: "-1.00"}, FeeType: "stale"}
+		Classify(&transaction)
+		if transaction.Type != model.TransactionTypeFee || transaction.FeeType != want {
+			t.Errorf("%q: got %q %q, want a %q fee", description, transaction.Type, transaction.FeeType, want)
+		}
+	}
+
+	transaction := model.Transaction{Description: "EFTPOS COLES", Amount: model.Money{Amount: "-1.00"}, FeeType: "stale"}
+	if Classify(&transaction); transaction.FeeType != "" {
+		t.Errorf("expected no fee type on a purchase, got %q", transaction.FeeType)
+	}
+}
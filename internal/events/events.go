@@ -0,0 +1,137 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// subscriberBuffer is how many events a subscriber can fall behind by
+// before it starts missing them
+const subscriberBuffer = 256
+
+// Broker fans events out to subscribers, such as Server-Sent Events
+// streams. A subscriber that falls behind misses events rather than
+// holding up the sync publishing them. A nil broker discards events.
+type Broker struct {
+	now func() time.Time
+
+	mu          sync.Mutex
+	lastID      int64
+	subscribers map[chan model.Event]struct{}
+}
+
+// NewBroker creates an event broker with no subscribers
+func NewBroker() *Broker {
+	return &Broker{
+		now:         time.Now,
+		subscribers: make(map[chan model.Event]struct{}),
+	}
+}
+
+// Publish sends an event to every current subscriber
+func (b *Broker) Publish(eventType string, data interface{}) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event := model.Event{ID: b.lastID, Type: eventType, Data: data, Time: b.now()}
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel receiving events published from now on, and
+// a func to call once the subscriber is done, which closes the channel
+func (b *Broker) Subscribe() (<-chan model.Event, func()) {
+	subscriber := make(chan model.Event, subscriberBuffer)
+
+	b.mu.Lock()
+	b.subscribers[subscriber] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return subscriber, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, subscriber)
+			b.mu.Unlock()
+			close(subscriber)
+		})
+	}
+}
+
+// Sync publishes the progress of one sync
+type Sync struct {
+	broker  *Broker
+	id      string
+	trigger string
+	started time.Time
+
+	mu     sync.Mutex
+	errors []model.SyncAccountError
+}
+
+// StartSync publishes sync.started and returns the sync to report the
+// rest of its progress on. The trigger says what started it, such as
+// "api" or "schedule"; without an ID, such as a job's, one is generated.
+func (b *Broker) StartSync(id, trigger string) *Sync {
+	if id == "" {
+		raw := make([]byte, 8)
+		_, _ = rand.Read(raw)
+		id = "sync_" + hex.EncodeToString(raw)
+	}
+	s := &Sync{broker: b, id: id, trigger: trigger, started: time.Now()}
+	b.Publish(model.EventSyncStarted, s.event())
+	return s
+}
+
+// Step publishes sync.step, with the account being scraped if any
+func (s *Sync) Step(step, accountID string) {
+	event := s.event()
+	event.Step = step
+	event.AccountID = accountID
+	s.broker.Publish(model.EventSyncStep, event)
+}
+
+// AccountFailed notes an account that failed to sync, to be reported when
+// the sync completes. Accounts may be synced concurrently.
+func (s *Sync) AccountFailed(accountID string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errors = append(s.errors, model.SyncAccountError{AccountID: accountID, Error: err.Error()})
+}
+
+// Completed publishes sync.completed with how many accounts were found and
+// those that failed. An error means the sync couldn't start on the
+// accounts at all.
+func (s *Sync) Completed(accounts int, err error) {
+	s.mu.Lock()
+	failures := append([]model.SyncAccountError(nil), s.errors...)
+	s.mu.Unlock()
+
+	event := s.event()
+	event.Accounts = accounts
+	event.Failed = len(failures)
+	event.Errors = failures
+	event.DurationMs = time.Since(s.started).Milliseconds()
+	if err != nil {
+		event.Error = err.Error()
+	}
+	s.broker.Publish(model.EventSyncCompleted, event)
+}
+
+// event is the sync's identity, shared by all its events
+func (s *Sync) event() model.SyncEvent {
+	return model.SyncEvent{SyncID: s.id, Trigger: s.trigger}
+}
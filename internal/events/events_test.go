@@ -0,0 +1,59 @@
+package events
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestBrokerPublishesSyncProgress(t *testing.T) {
+	broker := NewBroker()
+	subscription, unsubscribe := broker.Subscribe()
+
+	progress := broker.StartSync("job_1", "api")
+	progress.Step(model.SyncStepScrapingAccount, "12345678")
+	progress.AccountFailed("87654321", errors.New("login timed out"))
+	progress.Completed(2, nil)
+
+	var types []string
+	for i := 0; i < 3; i++ {
+		event := <-subscription
+		types = append(types, event.Type)
+		sync := event.Data.(model.SyncEvent)
+		if sync.SyncID != "job_1" || sync.Trigger != "api" {
+			t.Errorf("expected events for sync job_1, got %+v", sync)
+		}
+		if event.Type == model.EventSyncCompleted && (sync.Failed != 1 || sync.Errors[0].AccountID != "87654321") {
+			t.Errorf("expected the failed account on completion, got %+v", sync)
+		}
+		if event.ID != int64(i+1) {
+			t.Errorf("expected event ID %d, got %d", i+1, event.ID)
+		}
+	}
+	if types[0] != model.EventSyncStarted || types[1] != model.EventSyncStep || types[2] != model.EventSyncCompleted {
+		t.Errorf("unexpected event order %v", types)
+	}
+
+	unsubscribe()
+	if _, open := <-subscription; open {
+		t.Error("expected the subscription to be closed")
+	}
+	broker.Publish(model.EventSyncStarted, nil)
+
+	var discard *Broker
+	discard.StartSync("", "schedule").Completed(0, nil)
+}
+
+func TestSlowSubscriberMissesEvents(t *testing.T) {
+	broker := NewBroker()
+	subscription, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+
+	for i := 0; i < subscriberBuffer+10; i++ {
+		broker.Publish(model.EventTransactionNew, i)
+	}
+	if got := len(subscription); got != subscriberBuffer {
+		t.Errorf("expected %d buffered events, got %d", subscriberBuffer, got)
+	}
+}
@@ -0,0 +1,199 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// Export file names
+const (
+	TransactionsFile   = "transactions.parquet"
+	BalanceHistoryFile = "balance_history.parquet"
+)
+
+// Exporter writes the contents of the store to a sink as Parquet files
+type Exporter struct {
+	store *store.Store
+	sink  Sink
+	salt  []byte
+}
+
+// Options controls a single export run
+type Options struct {
+	Redaction Redaction
+}
+
+// NewExporter creates a new Parquet exporter. The salt keys the hashes used
+// by RedactHash; keeping it secret stops hashed merchants being recovered
+// by hashing a list of known merchant names.
+func NewExporter(store *store.Store, sink Sink, salt string) *Exporter {
+	return &Exporter{
+		store: store,
+		sink:  sink,
+		salt:  []byte(salt),
+	}
+}
+
+// Export writes the stored transactions and balance history to the sink
+func (e *Exporter) Export(ctx context.Context, opts Options) (*model.ExportResult, error) {
+	if opts.Redaction == "" {
+		opts.Redaction = RedactNone
+	}
+
+	result := &model.ExportResult{
+		Format:     "parquet",
+		Redaction:  string(opts.Redaction),
+		ExportedAt: time.Now(),
+	}
+
+	transactions, rows, err := e.transactionsParquet(redactor{mode: opts.Redaction, salt: e.salt})
+	if err != nil {
+		return nil, err
+	}
+	location, err := e.sink.Put(ctx, TransactionsFile, transactions)
+	if err != nil {
+		return nil, err
+	}
+	result.Files = append(result.Files, model.ExportFile{Name: TransactionsFile, Location: location, Rows: rows})
+
+	balances, rows, err := e.balanceHistoryParquet()
+	if err != nil {
+		return nil, err
+	}
+	location, err = e.sink.Put(ctx, BalanceHistoryFile, balances)
+	if err != nil {
+		return nil, err
+	}
+	result.Files = append(result.Files, model.ExportFile{Name: BalanceHistoryFile, Location: location, Rows: rows})
+
+	return result, nil
+}
+
+// transactionsParquet encodes every stored transaction as a Parquet file
+func (e *Exporter) transactionsParquet(redactor redactor) ([]byte, int, error) {
+	all := e.store.AllTransactions()
+	accountIDs := make([]string, 0, len(all))
+	for accountID := range all {
+		accountIDs = append(accountIDs, accountID)
+	}
+	sort.Strings(accountIDs)
+
+	columns := []column{
+		{name: "account_id", kind: kindString},
+		{name: "transaction_id", kind: kindString},
+		{name: "date", kind: kindDate, optional: true},
+		{name: "description", kind: kindString},
+		{name: "amount", kind: kindDecimal, optional: true},
+		{name: "balance", kind: kindDecimal, optional: true},
+		{name: "category", kind: kindString, optional: true},
+		{name: "merchant", kind: kindString, optional: true},
+	}
+
+	rows := 0
+	for _, accountID := range accountIDs {
+		for _, transaction := range all[accountID] {
+			transaction = redactor.redact(transaction)
+			columns[0].values = append(columns[0].values, accountID)
+			columns[1].values = append(columns[1].values, transaction.ID)
+			columns[2].values = append(columns[2].values, dateValue(transaction.Date))
+			columns[3].values = append(columns[3].values, transaction.Description)
+			columns[4].values = append(columns[4].values, centsValue(&transaction.Amount))
+			columns[5].values = append(columns[5].values, centsValue(&transaction.Balance))
+			columns[6].values = append(columns[6].values, stringValue(transaction.Category))
+			columns[7].values = append(columns[7].values, stringValue(transaction.Merchant))
+			rows++
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := writeParquet(&buf, columns, rows); err != nil {
+		return nil, 0, fmt.Errorf("failed to encode transactions: %w", err)
+	}
+
+	return buf.Bytes(), rows, nil
+}
+
+// balanceHistoryParquet encodes the balance history as a Parquet file
+func (e *Exporter) balanceHistoryParquet() ([]byte, int, error) {
+	history := e.store.BalanceHistory("")
+
+	columns := []column{
+		{name: "account_id", kind: kindString},
+		{name: "recorded_at", kind: kindTimestamp},
+		{name: "balance", kind: kindDecimal, optional: true},
+		{name: "available_balance", kind: kindDecimal, optional: true},
+	}
+
+	for _, snapshot := range history {
+		columns[0].values = append(columns[0].values, snapshot.AccountID)
+		columns[1].values = append(columns[1].values, snapshot.RecordedAt)
+		columns[2].values = append(columns[2].values, centsValue(&snapshot.Balance))
+		columns[3].values = append(columns[3].values, centsValue(snapshot.AvailableBalance))
+	}
+
+	var buf bytes.Buffer
+	if err := writeParquet(&buf, columns, len(history)); err != nil {
+		return nil, 0, fmt.Errorf("failed to encode balance history: %w", err)
+	}
+
+	return buf.Bytes(), len(history), nil
+}
+
+// dateValue parses a transaction date, returning nil when it is unparseable
+func dateValue(date string) interface{} {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil
+	}
+	return t
+}
+
+// centsValue converts a decimal money amount to integer cents, returning nil
+// when the amount is missing or unparseable
+func centsValue(money *model.Money) interface{} {
+	if money == nil {
+		return nil
+	}
+
+	cents, err := parseCents(money.Amount)
+	if err != nil {
+		return nil
+	}
+	return cents
+}
+
+func stringValue(s *string) interface{} {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+// parseCents parses a decimal string such as "-85.67" without going
+// through floating point
+func parseCents(amount string) (int64, error) {
+	amount = strings.TrimSpace(amount)
+	negative := strings.HasPrefix(amount, "-")
+	amount = strings.TrimLeft(amount, "+-")
+
+	whole, fraction, _ := strings.Cut(amount, ".")
+	fraction = (fraction + "00")[:2]
+
+	cents, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q: %w", amount, err)
+	}
+
+	if negative {
+		cents = -cents
+	}
+	return cents, nil
+}
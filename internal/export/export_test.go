@@ -0,0 +1,115 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestParseCents(t *testing.T) {
+	tests := map[string]int64{
+		"1234.56": 123456,
+		"-85.67":  -8567,
+		"2500":    250000,
+		"0.5":     50,
+	}
+
+	for input, want := range tests {
+		got, err := parseCents(input)
+		if err != nil {
+			t.Errorf("parseCents(%q) returned error: %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("parseCents(%q) = %d, want %d", input, got, want)
+		}
+	}
+
+	if _, err := parseCents("abc"); err == nil {
+		t.Error("expected error for non-numeric amount")
+	}
+}
+
+func TestExportWritesParquetFiles(t *testing.T) {
+	s, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	category := "Groceries"
+	err = s.SaveTransactions("12345678", []model.Transaction{
+		{ID: "txn_1", Date: "2024-05-01", Description: "COLES", Amount: model.Money{Amount: "-85.67"}, Balance: model.Money{Amount: "100.00"}, Category: &category},
+		{ID: "txn_2", Date: "2024-05-02", Description: "SALARY", Amount: model.Money{Amount: "2500.00"}, Balance: model.Money{Amount: "2600.00"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RecordAccounts([]model.Account{{ID: "12345678", Balance: model.Money{Amount: "2600.00"}}}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	result, err := NewExporter(s, &LocalSink{dir: dir}, "").Export(context.Background(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Files) != 2 || result.Files[0].Rows != 2 || result.Files[1].Rows != 1 {
+		t.Fatalf("unexpected export result: %+v", result.Files)
+	}
+
+	for _, name := range []string{TransactionsFile, BalanceHistoryFile} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.HasPrefix(data, []byte(parquetMagic)) || !bytes.HasSuffix(data, []byte(parquetMagic)) {
+			t.Errorf("%s is missing the Parquet magic bytes", name)
+		}
+
+		footerLength := binary.LittleEndian.Uint32(data[len(data)-8:])
+		if int(footerLength) >= len(data)-12 {
+			t.Errorf("%s has an invalid footer length %d", name, footerLength)
+		}
+	}
+}
+
+func TestRedactor(t *testing.T) {
+	merchant := "Coles Supermarkets"
+	category := "Groceries"
+	transaction := model.Transaction{Description: "COLES 1234 SYDNEY", Merchant: &merchant, Category: &category}
+
+	hashed := redactor{mode: RedactHash, salt: []byte("salt")}.redact(transaction)
+	if *hashed.Merchant == merchant || !strings.HasPrefix(*hashed.Merchant, "m_") {
+		t.Errorf("merchant not hashed: %q", *hashed.Merchant)
+	}
+	other := "coles  supermarkets"
+	again := redactor{mode: RedactHash, salt: []byte("salt")}.redact(model.Transaction{Merchant: &other})
+	if *again.Merchant != *hashed.Merchant {
+		t.Errorf("expected stable hash, got %q and %q", *hashed.Merchant, *again.Merchant)
+	}
+	if merchant != "Coles Supermarkets" {
+		t.Error("redaction modified the original merchant")
+	}
+
+	bucketed := redactor{mode: RedactBucket}.redact(transaction)
+	if bucketed.Description != "Groceries" || *bucketed.Merchant != "Groceries" {
+		t.Errorf("unexpected bucket: %q / %q", bucketed.Description, *bucketed.Merchant)
+	}
+	if got := (redactor{mode: RedactBucket}.redact(model.Transaction{Description: "SALARY"})); got.Description != uncategorisedBucket {
+		t.Errorf("expected %q, got %q", uncategorisedBucket, got.Description)
+	}
+
+	if _, err := ParseRedaction("scramble"); err == nil {
+		t.Error("expected error for unknown redaction mode")
+	}
+}
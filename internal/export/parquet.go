@@ -0,0 +1,263 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+)
+
+// columnKind is the logical type of a Parquet column
+type columnKind int
+
+const (
+	kindString    columnKind = iota // BYTE_ARRAY annotated UTF8
+	kindDate                        // INT32 days since the Unix epoch
+	kindTimestamp                   // INT64 milliseconds since the Unix epoch
+	kindDecimal                     // INT64 DECIMAL(18,2) holding cents
+)
+
+// Parquet enum values used by the writer
+const (
+	parquetInt32     = 1
+	parquetInt64     = 2
+	parquetByteArray = 6
+
+	parquetRequired = 0
+	parquetOptional = 1
+
+	convertedUTF8            = 0
+	convertedDecimal         = 5
+	convertedDate            = 6
+	convertedTimestampMillis = 9
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	pageTypeData = 0
+
+	decimalScale     = 2
+	decimalPrecision = 18
+)
+
+const parquetMagic = "PAR1"
+
+// column is a named column of values. Values must match the column kind:
+// string, time.Time (date and timestamp) or int64 (decimal cents). A nil
+// value is written as null and requires the column to be optional.
+type column struct {
+	name     string
+	kind     columnKind
+	optional bool
+	values   []interface{}
+}
+
+// writeParquet writes the columns as a single row group, uncompressed and
+// PLAIN encoded, which every Parquet reader supports
+func writeParquet(w io.Writer, columns []column, numRows int) error {
+	var file bytes.Buffer
+	file.WriteString(parquetMagic)
+
+	type chunk struct {
+		offset int64
+		size   int64
+	}
+	chunks := make([]chunk, len(columns))
+
+	for i, col := range columns {
+		if len(col.values) != numRows {
+			return fmt.Errorf("column %s has %d values, expected %d", col.name, len(col.values), numRows)
+		}
+
+		page, err := encodePage(col)
+		if err != nil {
+			return err
+		}
+
+		header := &compactWriter{}
+		header.i32(1, pageTypeData)
+		header.i32(2, int32(len(page)))
+		header.i32(3, int32(len(page)))
+		header.beginStruct(5)
+		header.i32(1, int32(numRows))
+		header.i32(2, encodingPlain)
+		header.i32(3, encodingRLE)
+		header.i32(4, encodingRLE)
+		header.endStruct()
+		header.buf.WriteByte(0)
+
+		chunks[i] = chunk{offset: int64(file.Len()), size: int64(header.buf.Len() + len(page))}
+		file.Write(header.bytes())
+		file.Write(page)
+	}
+
+	meta := &compactWriter{}
+	meta.i32(1, 1)
+
+	// Schema is flattened depth-first, starting with the root message
+	meta.listHeader(2, compactStruct, len(columns)+1)
+	meta.pushStruct()
+	meta.binary(4, "schema")
+	meta.i32(5, int32(len(columns)))
+	meta.endStruct()
+	for _, col := range columns {
+		meta.pushStruct()
+		meta.i32(1, physicalType(col.kind))
+		if col.optional {
+			meta.i32(3, parquetOptional)
+		} else {
+			meta.i32(3, parquetRequired)
+		}
+		meta.binary(4, col.name)
+		meta.i32(6, convertedType(col.kind))
+		if col.kind == kindDecimal {
+			meta.i32(7, decimalScale)
+			meta.i32(8, decimalPrecision)
+		}
+		meta.endStruct()
+	}
+
+	meta.i64(3, int64(numRows))
+
+	var totalSize int64
+	for _, c := range chunks {
+		totalSize += c.size
+	}
+
+	meta.listHeader(4, compactStruct, 1)
+	meta.pushStruct()
+	meta.listHeader(1, compactStruct, len(columns))
+	for i, col := range columns {
+		meta.pushStruct()
+		meta.i64(2, chunks[i].offset)
+		meta.beginStruct(3)
+		meta.i32(1, physicalType(col.kind))
+		meta.i32List(2, []int32{encodingPlain, encodingRLE})
+		meta.binaryList(3, []string{col.name})
+		meta.i32(4, 0) // UNCOMPRESSED
+		meta.i64(5, int64(numRows))
+		meta.i64(6, chunks[i].size)
+		meta.i64(7, chunks[i].size)
+		meta.i64(9, chunks[i].offset)
+		meta.endStruct()
+		meta.endStruct()
+	}
+	meta.i64(2, totalSize)
+	meta.i64(3, int64(numRows))
+	meta.endStruct()
+
+	meta.binary(6, "nab-bank-api")
+	meta.buf.WriteByte(0)
+
+	file.Write(meta.bytes())
+	_ = binary.Write(&file, binary.LittleEndian, uint32(len(meta.bytes())))
+	file.WriteString(parquetMagic)
+
+	_, err := w.Write(file.Bytes())
+	return err
+}
+
+// encodePage encodes the definition levels (for optional columns) and the
+// PLAIN encoded non-null values of a column
+func encodePage(col column) ([]byte, error) {
+	var page bytes.Buffer
+
+	if col.optional {
+		levels := encodeDefinitionLevels(col.values)
+		_ = binary.Write(&page, binary.LittleEndian, uint32(len(levels)))
+		page.Write(levels)
+	}
+
+	for _, value := range col.values {
+		if value == nil {
+			if !col.optional {
+				return nil, fmt.Errorf("column %s is required but has a null value", col.name)
+			}
+			continue
+		}
+
+		switch col.kind {
+		case kindString:
+			s, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("column %s expects string values", col.name)
+			}
+			_ = binary.Write(&page, binary.LittleEndian, uint32(len(s)))
+			page.WriteString(s)
+		case kindDate:
+			t, ok := value.(time.Time)
+			if !ok {
+				return nil, fmt.Errorf("column %s expects time values", col.name)
+			}
+			days := t.Unix() / 86400
+			if t.Unix() < 0 && t.Unix()%86400 != 0 {
+				days--
+			}
+			_ = binary.Write(&page, binary.LittleEndian, int32(days))
+		case kindTimestamp:
+			t, ok := value.(time.Time)
+			if !ok {
+				return nil, fmt.Errorf("column %s expects time values", col.name)
+			}
+			_ = binary.Write(&page, binary.LittleEndian, t.UnixMilli())
+		case kindDecimal:
+			cents, ok := value.(int64)
+			if !ok {
+				return nil, fmt.Errorf("column %s expects int64 values", col.name)
+			}
+			_ = binary.Write(&page, binary.LittleEndian, cents)
+		}
+	}
+
+	return page.Bytes(), nil
+}
+
+// encodeDefinitionLevels encodes 0/1 definition levels using RLE runs of
+// the RLE/bit-packing hybrid encoding with a bit width of 1
+func encodeDefinitionLevels(values []interface{}) []byte {
+	var levels bytes.Buffer
+	for i := 0; i < len(values); {
+		defined := values[i] != nil
+		run := 1
+		for i+run < len(values) && (values[i+run] != nil) == defined {
+			run++
+		}
+
+		var tmp [binary.MaxVarintLen64]byte
+		n := binary.PutUvarint(tmp[:], uint64(run)<<1)
+		levels.Write(tmp[:n])
+		if defined {
+			levels.WriteByte(1)
+		} else {
+			levels.WriteByte(0)
+		}
+		i += run
+	}
+
+	return levels.Bytes()
+}
+
+func physicalType(kind columnKind) int32 {
+	switch kind {
+	case kindDate:
+		return parquetInt32
+	case kindTimestamp, kindDecimal:
+		return parquetInt64
+	default:
+		return parquetByteArray
+	}
+}
+
+func convertedType(kind columnKind) int32 {
+	switch kind {
+	case kindDate:
+		return convertedDate
+	case kindTimestamp:
+		return convertedTimestampMillis
+	case kindDecimal:
+		return convertedDecimal
+	default:
+		return convertedUTF8
+	}
+}
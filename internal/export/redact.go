@@ -0,0 +1,82 @@
+package export
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// Redaction controls how merchant names and descriptions are written to
+// exports so datasets can be shared without revealing exact spending
+type Redaction string
+
+// Redaction modes
+const (
+	// RedactNone exports merchant names and descriptions unchanged
+	RedactNone Redaction = "none"
+
+	// RedactHash replaces each merchant and description with a stable keyed
+	// hash, so the same merchant always maps to the same token
+	RedactHash Redaction = "hash"
+
+	// RedactBucket replaces merchants and descriptions with the transaction
+	// category, keeping only coarse spending groups
+	RedactBucket Redaction = "bucket"
+)
+
+// uncategorisedBucket is used when bucketing a transaction with no category
+const uncategorisedBucket = "Uncategorised"
+
+// ParseRedaction validates a redaction mode, treating empty as none
+func ParseRedaction(value string) (Redaction, error) {
+	switch mode := Redaction(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "", RedactNone:
+		return RedactNone, nil
+	case RedactHash, RedactBucket:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown redaction mode %q", value)
+	}
+}
+
+// redactor applies a redaction mode to transactions
+type redactor struct {
+	mode Redaction
+	salt []byte
+}
+
+// redact returns a copy of the transaction with its merchant and
+// description redacted
+func (r redactor) redact(transaction model.Transaction) model.Transaction {
+	switch r.mode {
+	case RedactHash:
+		transaction.Description = r.hash("d", transaction.Description)
+		if transaction.Merchant != nil {
+			merchant := r.hash("m", *transaction.Merchant)
+			transaction.Merchant = &merchant
+		}
+	case RedactBucket:
+		bucket := uncategorisedBucket
+		if transaction.Category != nil && *transaction.Category != "" {
+			bucket = *transaction.Category
+		}
+		transaction.Description = bucket
+		if transaction.Merchant != nil {
+			transaction.Merchant = &bucket
+		}
+	}
+
+	return transaction
+}
+
+// hash returns a short keyed hash of the value, normalised so trivial case
+// and spacing differences map to the same token
+func (r redactor) hash(prefix, value string) string {
+	mac := hmac.New(sha256.New, r.salt)
+	mac.Write([]byte(strings.ToUpper(strings.Join(strings.Fields(value), " "))))
+	return prefix + "_" + hex.EncodeToString(mac.Sum(nil))[:12]
+}
@@ -0,0 +1,210 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Sink is a destination that export files are written to
+type Sink interface {
+	Put(ctx context.Context, name string, data []byte) (string, error)
+}
+
+// S3Credentials holds the credentials used to sign S3 requests
+type S3Credentials struct {
+	Region          string
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// NewSink returns a sink for the destination, which is either a local
+// directory or an s3://bucket/prefix URL
+func NewSink(destination string, creds S3Credentials) (Sink, error) {
+	if rest, ok := strings.CutPrefix(destination, "s3://"); ok {
+		bucket, prefix, _ := strings.Cut(rest, "/")
+		if bucket == "" {
+			return nil, fmt.Errorf("invalid S3 destination %q", destination)
+		}
+		if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+			return nil, fmt.Errorf("AWS credentials are required for S3 export")
+		}
+		return newS3Sink(bucket, prefix, creds), nil
+	}
+
+	if destination == "" {
+		return nil, fmt.Errorf("export destination is not configured")
+	}
+	return &LocalSink{dir: destination}, nil
+}
+
+// LocalSink writes export files to a local directory
+type LocalSink struct {
+	dir string
+}
+
+// Put writes the file into the sink directory
+func (s *LocalSink) Put(ctx context.Context, name string, data []byte) (string, error) {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	path := filepath.Join(s.dir, name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write export file: %w", err)
+	}
+
+	return path, nil
+}
+
+// S3Sink uploads export files to an S3 (or S3-compatible) bucket using
+// SigV4 signed PutObject requests
+type S3Sink struct {
+	bucket string
+	prefix string
+	creds  S3Credentials
+	client *http.Client
+}
+
+func newS3Sink(bucket, prefix string, creds S3Credentials) *S3Sink {
+	if creds.Region == "" {
+		creds.Region = "us-east-1"
+	}
+	if creds.Endpoint == "" {
+		creds.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", creds.Region)
+	}
+	creds.Endpoint = strings.TrimRight(creds.Endpoint, "/")
+
+	return &S3Sink{
+		bucket: bucket,
+		prefix: strings.Trim(prefix, "/"),
+		creds:  creds,
+		client: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Put uploads the file to the bucket under the configured prefix
+func (s *S3Sink) Put(ctx context.Context, name string, data []byte) (string, error) {
+	key := name
+	if s.prefix != "" {
+		key = s.prefix + "/" + name
+	}
+
+	path := "/" + s.bucket + "/" + awsURIEncode(key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.creds.Endpoint+path, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = int64(len(data))
+	req.Header.Set("Content-Type", "application/vnd.apache.parquet")
+
+	s.sign(req, path, data, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("S3 upload of %s returned status %d", key, resp.StatusCode)
+	}
+
+	return "s3://" + s.bucket + "/" + key, nil
+}
+
+// sign adds AWS Signature Version 4 headers to the request
+func (s *S3Sink) sign(req *http.Request, path string, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.creds.SessionToken)
+	}
+
+	headers := map[string]string{
+		"content-type":         req.Header.Get("Content-Type"),
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	names := []string{"content-type", "host", "x-amz-content-sha256", "x-amz-date"}
+	if s.creds.SessionToken != "" {
+		headers["x-amz-security-token"] = s.creds.SessionToken
+		names = append(names, "x-amz-security-token")
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + s.creds.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.creds.SecretAccessKey), day)
+	key = hmacSHA256(key, s.creds.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.creds.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+// awsURIEncode encodes an object key as required by SigV4, leaving only
+// unreserved characters and path separators as-is
+func awsURIEncode(key string) string {
+	var b strings.Builder
+	for _, c := range []byte(key) {
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,109 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol type identifiers
+const (
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+// compactWriter encodes the subset of the Thrift compact protocol needed to
+// write Parquet page headers and file metadata
+type compactWriter struct {
+	buf      bytes.Buffer
+	lastID   int16
+	idsStack []int16
+}
+
+func (w *compactWriter) fieldHeader(id int16, typ byte) {
+	delta := id - w.lastID
+	if delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.varint(int64(id))
+	}
+	w.lastID = id
+}
+
+func (w *compactWriter) uvarint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	w.buf.Write(tmp[:n])
+}
+
+// varint writes a zigzag encoded signed integer
+func (w *compactWriter) varint(v int64) {
+	w.uvarint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (w *compactWriter) i32(id int16, v int32) {
+	w.fieldHeader(id, compactI32)
+	w.varint(int64(v))
+}
+
+func (w *compactWriter) i64(id int16, v int64) {
+	w.fieldHeader(id, compactI64)
+	w.varint(v)
+}
+
+func (w *compactWriter) binary(id int16, v string) {
+	w.fieldHeader(id, compactBinary)
+	w.uvarint(uint64(len(v)))
+	w.buf.WriteString(v)
+}
+
+// beginStruct starts a nested struct field
+func (w *compactWriter) beginStruct(id int16) {
+	w.fieldHeader(id, compactStruct)
+	w.pushStruct()
+}
+
+// pushStruct starts a struct value, e.g. a list element
+func (w *compactWriter) pushStruct() {
+	w.idsStack = append(w.idsStack, w.lastID)
+	w.lastID = 0
+}
+
+// endStruct writes the stop field and restores the enclosing field ID
+func (w *compactWriter) endStruct() {
+	w.buf.WriteByte(0)
+	w.lastID = w.idsStack[len(w.idsStack)-1]
+	w.idsStack = w.idsStack[:len(w.idsStack)-1]
+}
+
+func (w *compactWriter) listHeader(id int16, elemType byte, size int) {
+	w.fieldHeader(id, compactList)
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elemType)
+		return
+	}
+	w.buf.WriteByte(0xf0 | elemType)
+	w.uvarint(uint64(size))
+}
+
+func (w *compactWriter) i32List(id int16, values []int32) {
+	w.listHeader(id, compactI32, len(values))
+	for _, v := range values {
+		w.varint(int64(v))
+	}
+}
+
+func (w *compactWriter) binaryList(id int16, values []string) {
+	w.listHeader(id, compactBinary, len(values))
+	for _, v := range values {
+		w.uvarint(uint64(len(v)))
+		w.buf.WriteString(v)
+	}
+}
+
+func (w *compactWriter) bytes() []byte {
+	return w.buf.Bytes()
+}
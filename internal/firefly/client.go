@@ -0,0 +1,211 @@
+package firefly
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrDuplicate is returned when Firefly III already has an identical
+// transaction
+var ErrDuplicate = errors.New("duplicate transaction")
+
+// Account types as Firefly III names them
+const (
+	AccountTypeAsset     = "asset"
+	AccountTypeLiability = "liability"
+)
+
+// Transaction types as Firefly III names them
+const (
+	TransactionWithdrawal = "withdrawal"
+	TransactionDeposit    = "deposit"
+)
+
+// Account is a Firefly III asset or liability account
+type Account struct {
+	ID            string
+	Name          string
+	Type          string
+	AccountNumber string
+}
+
+// AccountRequest creates a Firefly III account
+type AccountRequest struct {
+	Name               string `json:"name"`
+	Type               string `json:"type"`
+	AccountRole        string `json:"account_role,omitempty"`
+	AccountNumber      string `json:"account_number,omitempty"`
+	CurrencyCode       string `json:"currency_code"`
+	OpeningBalance     string `json:"opening_balance,omitempty"`
+	OpeningBalanceDate string `json:"opening_balance_date,omitempty"`
+	CreditCardType     string `json:"credit_card_type,omitempty"`
+	MonthlyPaymentDate string `json:"monthly_payment_date,omitempty"`
+	LiabilityType      string `json:"liability_type,omitempty"`
+	LiabilityDirection string `json:"liability_direction,omitempty"`
+	Interest           string `json:"interest,omitempty"`
+	InterestPeriod     string `json:"interest_period,omitempty"`
+	Notes              string `json:"notes,omitempty"`
+}
+
+// TransactionSplit is a single Firefly III transaction. Money leaves the
+// source and arrives at the destination, each given by ID for our own
+// accounts or by name for the merchants and payers on the other side.
+type TransactionSplit struct {
+	Type            string   `json:"type"`
+	Date            string   `json:"date"`
+	Amount          string   `json:"amount"`
+	Description     string   `json:"description"`
+	SourceID        string   `json:"source_id,omitempty"`
+	SourceName      string   `json:"source_name,omitempty"`
+	DestinationID   string   `json:"destination_id,omitempty"`
+	DestinationName string   `json:"destination_name,omitempty"`
+	CategoryName    string   `json:"category_name,omitempty"`
+	ExternalID      string   `json:"external_id,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+}
+
+// Client talks to a Firefly III instance's REST API with a personal access
+// token
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a Firefly III client for the instance at baseURL
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// accountData is an account in Firefly III's JSON:API responses
+type accountData struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Name          string `json:"name"`
+		Type          string `json:"type"`
+		AccountNumber string `json:"account_number"`
+	} `json:"attributes"`
+}
+
+// Accounts lists the asset and liability accounts, following pagination
+func (c *Client) Accounts(ctx context.Context) ([]Account, error) {
+	var accounts []Account
+	for page := 1; ; page++ {
+		var response struct {
+			Data []accountData `json:"data"`
+			Meta struct {
+				Pagination struct {
+					TotalPages int `json:"total_pages"`
+				} `json:"pagination"`
+			} `json:"meta"`
+		}
+		if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/accounts?type=all&page=%d", page), nil, &response); err != nil {
+			return nil, fmt.Errorf("failed to list Firefly III accounts: %w", err)
+		}
+
+		for _, data := range response.Data {
+			account := Account{
+				ID:            data.ID,
+				Name:          data.Attributes.Name,
+				Type:          data.Attributes.Type,
+				AccountNumber: data.Attributes.AccountNumber,
+			}
+			// Liabilities are listed as "liabilities" by some versions
+			if account.Type == "liabilities" {
+				account.Type = AccountTypeLiability
+			}
+			if account.Type == AccountTypeAsset || account.Type == AccountTypeLiability {
+				accounts = append(accounts, account)
+			}
+		}
+		if page >= response.Meta.Pagination.TotalPages {
+			return accounts, nil
+		}
+	}
+}
+
+// CreateAccount creates an account and returns its ID
+func (c *Client) CreateAccount(ctx context.Context, req AccountRequest) (string, error) {
+	var response struct {
+		Data accountData `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/accounts", req, &response); err != nil {
+		return "", fmt.Errorf("failed to create Firefly III account %q: %w", req.Name, err)
+	}
+	return response.Data.ID, nil
+}
+
+// CreateTransaction stores a transaction and returns its ID. Firefly III's
+// rules run on it, and it is refused with ErrDuplicate if an identical
+// transaction is already stored.
+func (c *Client) CreateTransaction(ctx context.Context, split TransactionSplit) (string, error) {
+	req := struct {
+		ErrorIfDuplicateHash bool               `json:"error_if_duplicate_hash"`
+		ApplyRules           bool               `json:"apply_rules"`
+		Transactions         []TransactionSplit `json:"transactions"`
+	}{true, true, []TransactionSplit{split}}
+
+	var response struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/transactions", req, &response); err != nil {
+		return "", fmt.Errorf("failed to create Firefly III transaction %s: %w", split.ExternalID, err)
+	}
+	return response.Data.ID, nil
+}
+
+// do sends a request and decodes the JSON response into out
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/vnd.api+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusUnprocessableEntity && bytes.Contains(raw, []byte("Duplicate of transaction")) {
+		return ErrDuplicate
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(raw, out)
+}
@@ -0,0 +1,258 @@
+package firefly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// FeedName is the name the Firefly III feed's state is stored under
+const FeedName = "firefly"
+
+// Syncer pushes stored accounts and their new transactions into Firefly
+// III, making this server a bank feed for it
+type Syncer struct {
+	client *Client
+	store  *store.Store
+	logger *log.Logger
+	now    func() time.Time
+}
+
+// NewSyncer creates a Firefly III syncer
+func NewSyncer(client *Client, store *store.Store, logger *log.Logger) *Syncer {
+	return &Syncer{
+		client: client,
+		store:  store,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Name identifies the feed in logs
+func (s *Syncer) Name() string {
+	return "Firefly III"
+}
+
+// Push links every stored account to a Firefly III account, creating one
+// unless an account with the same name or account number already exists,
+// then sends the transactions Firefly III hasn't been given yet, oldest
+// first. Progress is saved per account, so a failure part way through
+// picks up where it left off on the next sync.
+func (s *Syncer) Push(ctx context.Context) error {
+	state := s.store.FeedState(FeedName)
+	var remote []Account
+	pushed := 0
+
+	for _, account := range s.store.Accounts() {
+		link, linked := state.Accounts[account.ID]
+		if !linked {
+			if remote == nil {
+				var err error
+				if remote, err = s.client.Accounts(ctx); err != nil {
+					return err
+				}
+			}
+			var err error
+			if link, err = s.link(ctx, account, remote, state.Accounts); err != nil {
+				return err
+			}
+			state.Accounts[account.ID] = link
+		}
+
+		before := len(state.Pushed)
+		count, err := s.pushTransactions(ctx, account.ID, link, state.Pushed)
+		pushed += count
+		if linked && len(state.Pushed) == before {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if saveErr := s.store.UpdateFeedState(FeedName, func(stored *model.FeedState) {
+			stored.Accounts[account.ID] = link
+			for key, remoteID := range state.Pushed {
+				stored.Pushed[key] = remoteID
+			}
+		}); saveErr != nil {
+			return fmt.Errorf("failed to save Firefly III progress: %w", saveErr)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	now := s.now()
+	if err := s.store.UpdateFeedState(FeedName, func(stored *model.FeedState) {
+		stored.LastPushAt = &now
+	}); err != nil {
+		return fmt.Errorf("failed to save Firefly III progress: %w", err)
+	}
+	if pushed > 0 {
+		s.logger.Printf("Pushed %d transactions to Firefly III", pushed)
+	}
+	return nil
+}
+
+// link finds the Firefly III account for an account, or creates one with
+// an opening balance that makes its stored transactions add up to the
+// current balance
+func (s *Syncer) link(ctx context.Context, account model.Account, remote []Account, linked map[string]model.FeedAccount) (model.FeedAccount, error) {
+	now := s.now()
+	taken := make(map[string]bool, len(linked))
+	for _, link := range linked {
+		taken[link.RemoteID] = true
+	}
+	for _, candidate := range remote {
+		if !taken[candidate.ID] && matches(account, candidate) {
+			s.logger.Printf("Linked account %s to existing Firefly III account %q", account.ID, candidate.Name)
+			return model.FeedAccount{RemoteID: candidate.ID, Since: now.Format("2006-01-02"), LinkedAt: now}, nil
+		}
+	}
+
+	id, err := s.client.CreateAccount(ctx, s.accountRequest(account))
+	if err != nil {
+		return model.FeedAccount{}, err
+	}
+	s.logger.Printf("Created Firefly III account %q for account %s", account.Name, account.ID)
+	return model.FeedAccount{RemoteID: id, LinkedAt: now}, nil
+}
+
+// matches reports whether a Firefly III account is the same account, by
+// name or by the last digits of the account number NAB shows
+func matches(account model.Account, candidate Account) bool {
+	if strings.EqualFold(strings.TrimSpace(candidate.Name), strings.TrimSpace(account.Name)) {
+		return true
+	}
+	if account.AccountNumber == nil {
+		return false
+	}
+	digits := strings.TrimLeft(*account.AccountNumber, "*x ")
+	return len(digits) >= 4 && strings.HasSuffix(strings.ReplaceAll(candidate.AccountNumber, " ", ""), digits)
+}
+
+// accountRequest describes a new Firefly III account for an account.
+// Loans become liabilities and everything else an asset account.
+func (s *Syncer) accountRequest(account model.Account) AccountRequest {
+	opening, date := s.openingBalance(account)
+	req := AccountRequest{
+		Name:               account.Name,
+		Type:               AccountTypeAsset,
+		AccountRole:        "defaultAsset",
+		CurrencyCode:       account.Balance.CurrencyCode(),
+		OpeningBalance:     strconv.FormatFloat(opening, 'f', 2, 64),
+		OpeningBalanceDate: date,
+		Notes:              "Created by nab-banking-api for NAB account " + account.ID,
+	}
+	if account.AccountNumber != nil {
+		req.AccountNumber = *account.AccountNumber
+	}
+
+	switch account.Type {
+	case model.AccountTypeSavings, model.AccountTypeTermDeposit:
+		req.AccountRole = "savingAsset"
+	case model.AccountTypeCredit:
+		req.AccountRole = "ccAsset"
+		req.CreditCardType = "monthlyFull"
+		req.MonthlyPaymentDate = date
+	case model.AccountTypeLoan:
+		// Firefly III records what is owed on a liability as a positive
+		// amount
+		req.Type = AccountTypeLiability
+		req.AccountRole = ""
+		req.LiabilityType = "loan"
+		req.LiabilityDirection = "credit"
+		req.Interest = "0"
+		req.InterestPeriod = "monthly"
+		req.OpeningBalance = strconv.FormatFloat(-opening, 'f', 2, 64)
+	}
+	return req
+}
+
+// openingBalance works out the balance before the account's oldest stored
+// transaction, and the date of that transaction
+func (s *Syncer) openingBalance(account model.Account) (float64, string) {
+	balance, _ := strconv.ParseFloat(account.Balance.Amount, 64)
+	date := s.now().Format("2006-01-02")
+	for _, transaction := range s.store.Transactions(account.ID) {
+		if amount, err := strconv.ParseFloat(transaction.Amount.Amount, 64); err == nil {
+			balance -= amount
+		}
+		if transaction.Date < date {
+			date = transaction.Date
+		}
+	}
+	return math.Round(balance*100) / 100, date
+}
+
+// pushTransactions sends an account's stored transactions that haven't
+// been pushed, oldest first, recording each in pushed. It stops at the
+// first failure.
+func (s *Syncer) pushTransactions(ctx context.Context, accountID string, link model.FeedAccount, pushed map[string]string) (int, error) {
+	transactions := s.store.Transactions(accountID)
+	count := 0
+	for i := len(transactions) - 1; i >= 0; i-- {
+		transaction := transactions[i]
+		key := accountID + "/" + transaction.ID
+		if _, ok := pushed[key]; ok || transaction.Date < link.Since {
+			continue
+		}
+		split, ok := transactionSplit(transaction, link.RemoteID)
+		if !ok {
+			continue
+		}
+
+		id, err := s.client.CreateTransaction(ctx, split)
+		if err != nil && !errors.Is(err, ErrDuplicate) {
+			return count, err
+		}
+		pushed[key] = id
+		if err == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// transactionSplit maps a transaction to a Firefly III withdrawal or
+// deposit against the linked account. The merchant, or the description
+// when there isn't one, is the other side. Zero amounts are skipped.
+func transactionSplit(transaction model.Transaction, remoteID string) (TransactionSplit, bool) {
+	amount, err := strconv.ParseFloat(transaction.Amount.Amount, 64)
+	if err != nil || amount == 0 {
+		return TransactionSplit{}, false
+	}
+
+	counterparty := transaction.Description
+	switch {
+	case transaction.MerchantDetails != nil:
+		counterparty = transaction.MerchantDetails.Name
+	case transaction.Merchant != nil && *transaction.Merchant != "":
+		counterparty = *transaction.Merchant
+	}
+
+	split := TransactionSplit{
+		Type:        TransactionWithdrawal,
+		Date:        transaction.Date,
+		Amount:      strconv.FormatFloat(math.Abs(amount), 'f', 2, 64),
+		Description: transaction.Description,
+		ExternalID:  transaction.ID,
+	}
+	if transaction.Category != nil {
+		split.CategoryName = *transaction.Category
+	}
+	if amount < 0 {
+		split.SourceID, split.DestinationName = remoteID, counterparty
+	} else {
+		split.Type = TransactionDeposit
+		split.SourceName, split.DestinationID = counterparty, remoteID
+	}
+	return split, true
+}
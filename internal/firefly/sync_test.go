@@ -0,0 +1,137 @@
+package firefly
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// fakeFirefly is a Firefly III instance with one existing asset account
+type fakeFirefly struct {
+	mu           sync.Mutex
+	accounts     []AccountRequest
+	transactions []TransactionSplit
+	failAfter    int
+}
+
+func (f *fakeFirefly) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/accounts":
+		w.Write([]byte(`{"data": [{"id": "7", "attributes": {"name": "Joint Account", "type": "asset", "account_number": "084001 12344321"}}], "meta": {"pagination": {"total_pages": 1}}}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v1/accounts":
+		var req AccountRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		f.accounts = append(f.accounts, req)
+		w.Write([]byte(`{"data": {"id": "100"}}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v1/transactions":
+		var req struct {
+			ErrorIfDuplicateHash bool               `json:"error_if_duplicate_hash"`
+			Transactions         []TransactionSplit `json:"transactions"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.ErrorIfDuplicateHash {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if strings.Contains(req.Transactions[0].Description, "DUPLICATE") {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message": "Duplicate of transaction #3.", "errors": {}}`))
+			return
+		}
+		if f.failAfter > 0 && len(f.transactions) >= f.failAfter {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		f.transactions = append(f.transactions, req.Transactions[0])
+		w.Write([]byte(`{"data": {"id": "` + req.Transactions[0].ExternalID + `"}}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestPush(t *testing.T) {
+	fake := &fakeFirefly{failAfter: 2}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	everyday := model.Account{ID: "12345678", Name: "Everyday", Type: model.AccountTypeSavings, Balance: model.Money{Amount: "950.00"}}
+	number := "****4321"
+	joint := model.Account{ID: "87654321", Name: "NAB Joint", Type: model.AccountTypeChecking, Balance: model.Money{Amount: "10.00"}, AccountNumber: &number}
+	if err := dataStore.RecordAccounts([]model.Account{everyday, joint}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	coffee := "Coffee"
+	if err := dataStore.SaveTransactions("12345678", []model.Transaction{
+		{ID: "t3", Date: "2024-05-03", Description: "EFTPOS DUPLICATE", Amount: model.Money{Amount: "-10.00"}},
+		{ID: "t2", Date: "2024-05-02", Description: "SALARY", Amount: model.Money{Amount: "100.00"}},
+		{ID: "t1", Date: "2024-05-01", Description: "VISA SEVEN SEEDS", Amount: model.Money{Amount: "-40.00"}, Category: &coffee, MerchantDetails: &model.MerchantDetails{Name: "Seven Seeds"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dataStore.SaveTransactions("87654321", []model.Transaction{
+		{ID: "j1", Date: "2024-01-01", Description: "OLD", Amount: model.Money{Amount: "-5.00"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	syncer := NewSyncer(NewClient(server.URL+"/", "token"), dataStore, log.New(io.Discard, "", 0))
+	if err := syncer.Push(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fake.accounts) != 1 || fake.accounts[0].Name != "Everyday" || fake.accounts[0].AccountRole != "savingAsset" || fake.accounts[0].OpeningBalance != "900.00" || fake.accounts[0].OpeningBalanceDate != "2024-05-01" {
+		t.Fatalf("expected only the everyday account to be created with its opening balance, got %+v", fake.accounts)
+	}
+	if len(fake.transactions) != 2 {
+		t.Fatalf("expected two transactions, got %+v", fake.transactions)
+	}
+	first, second := fake.transactions[0], fake.transactions[1]
+	if first.Type != TransactionWithdrawal || first.SourceID != "100" || first.DestinationName != "Seven Seeds" || first.Amount != "40.00" || first.CategoryName != "Coffee" {
+		t.Errorf("unexpected withdrawal %+v", first)
+	}
+	if second.Type != TransactionDeposit || second.SourceName != "SALARY" || second.DestinationID != "100" || second.Amount != "100.00" {
+		t.Errorf("unexpected deposit %+v", second)
+	}
+
+	state := dataStore.FeedState(FeedName)
+	if state.Accounts["87654321"].RemoteID != "7" || state.LastPushAt == nil {
+		t.Errorf("expected the joint account to be linked to the existing account, got %+v", state)
+	}
+	if _, ok := state.Pushed["12345678/t3"]; !ok {
+		t.Errorf("expected the duplicate to be recorded as pushed, got %v", state.Pushed)
+	}
+
+	// Nothing new means nothing more is sent, even when Firefly III fails
+	if err := syncer.Push(context.Background()); err != nil || len(fake.transactions) != 2 || len(fake.accounts) != 1 {
+		t.Errorf("expected nothing to be pushed again, got %v with %d transactions", err, len(fake.transactions))
+	}
+	if err := dataStore.SaveTransactions("12345678", []model.Transaction{
+		{ID: "t4", Date: "2024-05-04", Description: "REFUND", Amount: model.Money{Amount: "5.00"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := syncer.Push(context.Background()); err == nil {
+		t.Error("expected Firefly III's failure to be returned")
+	}
+}
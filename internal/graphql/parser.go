@@ -0,0 +1,585 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// document is a parsed GraphQL request document
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+// operation is a query operation. Mutations and subscriptions are rejected
+// at parse time.
+type operation struct {
+	name         string
+	variables    map[string]interface{}
+	selectionSet []selection
+}
+
+// fragment is a named fragment definition
+type fragment struct {
+	typeCondition string
+	selectionSet  []selection
+}
+
+// selection is a field, fragment spread or inline fragment
+type selection struct {
+	// Field
+	alias        string
+	name         string
+	arguments    map[string]interface{}
+	selectionSet []selection
+
+	// Fragment spread (fragmentName) or inline fragment (typeCondition)
+	fragmentName  string
+	typeCondition string
+	inline        bool
+}
+
+// variable is an unresolved reference to an operation variable
+type variable string
+
+// enumValue is an enum literal, resolved to its name as a string
+type enumValue string
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+type parser struct {
+	src string
+	pos int
+	tok token
+}
+
+// parse parses a query document
+func parse(src string) (*document, error) {
+	p := &parser{src: src}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	doc := &document{fragments: make(map[string]*fragment)}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.tok.kind == tokenPunct && p.tok.value == "{":
+			set, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, &operation{selectionSet: set})
+		case p.tok.kind == tokenName && p.tok.value == "query":
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case p.tok.kind == tokenName && p.tok.value == "fragment":
+			name, frag, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			doc.fragments[name] = frag
+		case p.tok.kind == tokenName && (p.tok.value == "mutation" || p.tok.value == "subscription"):
+			return nil, fmt.Errorf("%s operations are not supported", p.tok.value)
+		default:
+			return nil, p.unexpected()
+		}
+	}
+
+	if len(doc.operations) == 0 {
+		return nil, fmt.Errorf("document does not contain an operation")
+	}
+
+	return doc, nil
+}
+
+func (p *parser) parseOperation() (*operation, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	op := &operation{variables: make(map[string]interface{})}
+	if p.tok.kind == tokenName {
+		op.name = p.tok.value
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.isPunct("(") {
+		if err := p.parseVariableDefinitions(op.variables); err != nil {
+			return nil, err
+		}
+	}
+
+	set, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selectionSet = set
+
+	return op, nil
+}
+
+// parseVariableDefinitions records each declared variable with its default
+// value. Declared types are not enforced.
+func (p *parser) parseVariableDefinitions(defaults map[string]interface{}) error {
+	if err := p.next(); err != nil {
+		return err
+	}
+
+	for !p.isPunct(")") {
+		if !p.isPunct("$") {
+			return p.unexpected()
+		}
+		if err := p.next(); err != nil {
+			return err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return err
+		}
+		if err := p.skipType(); err != nil {
+			return err
+		}
+
+		defaults[name] = nil
+		if p.isPunct("=") {
+			if err := p.next(); err != nil {
+				return err
+			}
+			value, err := p.parseValue()
+			if err != nil {
+				return err
+			}
+			defaults[name] = value
+		}
+	}
+
+	return p.next()
+}
+
+// skipType consumes a type reference such as [String!]!
+func (p *parser) skipType() error {
+	if p.isPunct("[") {
+		if err := p.next(); err != nil {
+			return err
+		}
+		if err := p.skipType(); err != nil {
+			return err
+		}
+		if err := p.expectPunct("]"); err != nil {
+			return err
+		}
+	} else if _, err := p.expectName(); err != nil {
+		return err
+	}
+
+	if p.isPunct("!") {
+		return p.next()
+	}
+	return nil
+}
+
+func (p *parser) parseFragment() (string, *fragment, error) {
+	if err := p.next(); err != nil {
+		return "", nil, err
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return "", nil, err
+	}
+	if p.tok.kind != tokenName || p.tok.value != "on" {
+		return "", nil, p.unexpected()
+	}
+	if err := p.next(); err != nil {
+		return "", nil, err
+	}
+	typeCondition, err := p.expectName()
+	if err != nil {
+		return "", nil, err
+	}
+
+	set, err := p.parseSelectionSet()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return name, &fragment{typeCondition: typeCondition, selectionSet: set}, nil
+}
+
+func (p *parser) parseSelectionSet() ([]selection, error) {
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+
+	var set []selection
+	for !p.isPunct("}") {
+		if p.tok.kind == tokenEOF {
+			return nil, p.unexpected()
+		}
+
+		if p.isPunct("...") {
+			sel, err := p.parseFragmentSelection()
+			if err != nil {
+				return nil, err
+			}
+			set = append(set, sel)
+			continue
+		}
+
+		sel, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, sel)
+	}
+
+	return set, p.next()
+}
+
+func (p *parser) parseFragmentSelection() (selection, error) {
+	if err := p.next(); err != nil {
+		return selection{}, err
+	}
+
+	if p.tok.kind == tokenName && p.tok.value != "on" {
+		name := p.tok.value
+		return selection{fragmentName: name}, p.next()
+	}
+
+	sel := selection{inline: true}
+	if p.tok.kind == tokenName && p.tok.value == "on" {
+		if err := p.next(); err != nil {
+			return selection{}, err
+		}
+		typeCondition, err := p.expectName()
+		if err != nil {
+			return selection{}, err
+		}
+		sel.typeCondition = typeCondition
+	}
+
+	set, err := p.parseSelectionSet()
+	if err != nil {
+		return selection{}, err
+	}
+	sel.selectionSet = set
+
+	return sel, nil
+}
+
+func (p *parser) parseField() (selection, error) {
+	name, err := p.expectName()
+	if err != nil {
+		return selection{}, err
+	}
+
+	sel := selection{alias: name, name: name}
+	if p.isPunct(":") {
+		if err := p.next(); err != nil {
+			return selection{}, err
+		}
+		if sel.name, err = p.expectName(); err != nil {
+			return selection{}, err
+		}
+	}
+
+	if p.isPunct("(") {
+		if sel.arguments, err = p.parseArguments(); err != nil {
+			return selection{}, err
+		}
+	}
+
+	if p.isPunct("{") {
+		if sel.selectionSet, err = p.parseSelectionSet(); err != nil {
+			return selection{}, err
+		}
+	}
+
+	return sel, nil
+}
+
+func (p *parser) parseArguments() (map[string]interface{}, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	args := make(map[string]interface{})
+	for !p.isPunct(")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		args[name] = value
+	}
+
+	return args, p.next()
+}
+
+func (p *parser) parseValue() (interface{}, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokenPunct:
+		switch tok.value {
+		case "$":
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			name, err := p.expectName()
+			return variable(name), err
+		case "[":
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			list := []interface{}{}
+			for !p.isPunct("]") {
+				value, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, value)
+			}
+			return list, p.next()
+		case "{":
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			object := map[string]interface{}{}
+			for !p.isPunct("}") {
+				name, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expectPunct(":"); err != nil {
+					return nil, err
+				}
+				if object[name], err = p.parseValue(); err != nil {
+					return nil, err
+				}
+			}
+			return object, p.next()
+		}
+	case tokenInt:
+		n, err := strconv.ParseInt(tok.value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return n, p.next()
+	case tokenFloat:
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, err
+		}
+		return f, p.next()
+	case tokenString:
+		return tok.value, p.next()
+	case tokenName:
+		var value interface{}
+		switch tok.value {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null":
+			value = nil
+		default:
+			value = enumValue(tok.value)
+		}
+		return value, p.next()
+	}
+
+	return nil, p.unexpected()
+}
+
+func (p *parser) isPunct(value string) bool {
+	return p.tok.kind == tokenPunct && p.tok.value == value
+}
+
+func (p *parser) expectPunct(value string) error {
+	if !p.isPunct(value) {
+		return p.unexpected()
+	}
+	return p.next()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.unexpected()
+	}
+	name := p.tok.value
+	return name, p.next()
+}
+
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokenEOF {
+		return fmt.Errorf("syntax error: unexpected end of document")
+	}
+	return fmt.Errorf("syntax error: unexpected %q at position %d", p.tok.value, p.tok.pos)
+}
+
+// next advances to the next token, skipping whitespace, commas and comments
+func (p *parser) next() error {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == '#' {
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+			continue
+		}
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			p.pos++
+			continue
+		}
+		break
+	}
+
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.tok = token{kind: tokenEOF, pos: start}
+		return nil
+	}
+
+	c := p.src[p.pos]
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "..."):
+		p.pos += 3
+		p.tok = token{kind: tokenPunct, value: "...", pos: start}
+	case strings.ContainsRune("!$():=@[]{}|", rune(c)):
+		p.pos++
+		p.tok = token{kind: tokenPunct, value: string(c), pos: start}
+	case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		for p.pos < len(p.src) && isNameChar(p.src[p.pos]) {
+			p.pos++
+		}
+		p.tok = token{kind: tokenName, value: p.src[start:p.pos], pos: start}
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.lexNumber(start)
+	case c == '"':
+		return p.lexString(start)
+	default:
+		return fmt.Errorf("syntax error: unexpected character %q at position %d", c, start)
+	}
+
+	return nil
+}
+
+func (p *parser) lexNumber(start int) error {
+	kind := tokenInt
+	if p.src[p.pos] == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c >= '0' && c <= '9':
+		case c == '.' || c == 'e' || c == 'E' || ((c == '+' || c == '-') && kind == tokenFloat):
+			kind = tokenFloat
+		default:
+			p.tok = token{kind: kind, value: p.src[start:p.pos], pos: start}
+			return nil
+		}
+		p.pos++
+	}
+
+	p.tok = token{kind: kind, value: p.src[start:p.pos], pos: start}
+	return nil
+}
+
+func (p *parser) lexString(start int) error {
+	if strings.HasPrefix(p.src[p.pos:], `"""`) {
+		end := strings.Index(p.src[p.pos+3:], `"""`)
+		if end < 0 {
+			return fmt.Errorf("syntax error: unterminated string at position %d", start)
+		}
+		value := p.src[p.pos+3 : p.pos+3+end]
+		p.pos += end + 6
+		p.tok = token{kind: tokenString, value: value, pos: start}
+		return nil
+	}
+
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			p.tok = token{kind: tokenString, value: b.String(), pos: start}
+			return nil
+		case '\n':
+			return fmt.Errorf("syntax error: unterminated string at position %d", start)
+		case '\\':
+			if p.pos+1 >= len(p.src) {
+				return fmt.Errorf("syntax error: unterminated string at position %d", start)
+			}
+			p.pos++
+			switch esc := p.src[p.pos]; esc {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'u':
+				if p.pos+4 >= len(p.src) {
+					return fmt.Errorf("syntax error: invalid unicode escape at position %d", p.pos)
+				}
+				r, err := strconv.ParseUint(p.src[p.pos+1:p.pos+5], 16, 32)
+				if err != nil {
+					return fmt.Errorf("syntax error: invalid unicode escape at position %d", p.pos)
+				}
+				b.WriteRune(rune(r))
+				p.pos += 4
+			default:
+				b.WriteByte(esc)
+			}
+			p.pos++
+		default:
+			r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+			b.WriteRune(r)
+			p.pos += size
+		}
+	}
+
+	return fmt.Errorf("syntax error: unterminated string at position %d", start)
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
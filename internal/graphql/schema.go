@@ -0,0 +1,444 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ResolveFunc resolves a field value from its parent object and arguments.
+// The source is nil for fields on the query root.
+type ResolveFunc func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error)
+
+// Field describes a field on an object type
+type Field struct {
+	// Type is the name of the field's object type, or empty for scalars and
+	// lists of scalars which are returned as-is
+	Type string
+
+	// Resolve computes the field value. When nil, the value is read from the
+	// source struct field whose JSON tag matches the field name.
+	Resolve ResolveFunc
+}
+
+// Object describes an object type
+type Object struct {
+	Name   string
+	Fields map[string]*Field
+}
+
+// Schema is an executable schema of object types rooted at Query
+type Schema struct {
+	Query *Object
+
+	// MaxDepth limits how deeply a query may nest fields, and MaxFields how
+	// many fields it may select once fragments are expanded, so one
+	// request can't set off unbounded work. Zero means no limit.
+	MaxDepth  int
+	MaxFields int
+
+	types map[string]*Object
+}
+
+// NewSchema creates a schema from the query root and the object types it
+// references
+func NewSchema(query *Object, types ...*Object) (*Schema, error) {
+	schema := &Schema{
+		Query: query,
+		types: map[string]*Object{query.Name: query},
+	}
+	for _, object := range types {
+		schema.types[object.Name] = object
+	}
+
+	for _, object := range schema.types {
+		for name, field := range object.Fields {
+			if field.Type != "" && schema.types[field.Type] == nil {
+				return nil, fmt.Errorf("field %s.%s references unknown type %s", object.Name, name, field.Type)
+			}
+		}
+	}
+
+	return schema, nil
+}
+
+// Request is a GraphQL request as sent over HTTP
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Error is a GraphQL error entry
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// Response is a GraphQL response
+type Response struct {
+	Data   interface{} `json:"data"`
+	Errors []Error     `json:"errors,omitempty"`
+}
+
+// Execute parses and executes a query against the schema. Field errors are
+// collected in the response alongside whatever data could be resolved.
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return &Response{Errors: []Error{{Message: err.Error()}}}
+	}
+
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return &Response{Errors: []Error{{Message: err.Error()}}}
+	}
+	if err := s.checkLimits(doc, op); err != nil {
+		return &Response{Errors: []Error{{Message: err.Error()}}}
+	}
+
+	variables := make(map[string]interface{}, len(op.variables))
+	for name, value := range op.variables {
+		variables[name] = value
+	}
+	for name, value := range req.Variables {
+		variables[name] = value
+	}
+
+	e := &executor{schema: s, doc: doc, variables: variables}
+	data := e.executeObject(ctx, s.Query, nil, op.selectionSet, nil)
+
+	return &Response{Data: data, Errors: e.errors}
+}
+
+func selectOperation(doc *document, name string) (*operation, error) {
+	if name == "" {
+		if len(doc.operations) > 1 {
+			return nil, fmt.Errorf("operationName is required when the document contains multiple operations")
+		}
+		return doc.operations[0], nil
+	}
+
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+// checkLimits rejects an operation that nests fields deeper or selects
+// more of them than the schema allows, or that spreads a fragment inside
+// itself
+func (s *Schema) checkLimits(doc *document, op *operation) error {
+	m := &measure{doc: doc, maxFields: s.MaxFields, spreading: make(map[string]bool)}
+	depth, err := m.selectionSet(op.selectionSet)
+	if err != nil {
+		return err
+	}
+	if s.MaxDepth > 0 && depth > s.MaxDepth {
+		return fmt.Errorf("query is nested %d fields deep, more than the limit of %d", depth, s.MaxDepth)
+	}
+	return nil
+}
+
+// measure counts the fields a selection set selects and how deeply it
+// nests them, expanding fragments
+type measure struct {
+	doc       *document
+	maxFields int
+	fields    int
+	spreading map[string]bool
+}
+
+// selectionSet returns how deeply set nests fields. Counting stops as soon
+// as there are more fields than allowed, as spreading fragments inside
+// each other can select exponentially many.
+func (m *measure) selectionSet(set []selection) (int, error) {
+	depth := 0
+	for _, sel := range set {
+		var d int
+		var err error
+		switch {
+		case sel.fragmentName != "":
+			frag, ok := m.doc.fragments[sel.fragmentName]
+			if !ok {
+				// Reported when the query is executed
+				continue
+			}
+			if m.spreading[sel.fragmentName] {
+				return 0, fmt.Errorf("fragment %q is spread inside itself", sel.fragmentName)
+			}
+			m.spreading[sel.fragmentName] = true
+			d, err = m.selectionSet(frag.selectionSet)
+			delete(m.spreading, sel.fragmentName)
+		case sel.inline:
+			d, err = m.selectionSet(sel.selectionSet)
+		default:
+			m.fields++
+			if m.maxFields > 0 && m.fields > m.maxFields {
+				return 0, fmt.Errorf("query selects more than the limit of %d fields", m.maxFields)
+			}
+			d, err = m.selectionSet(sel.selectionSet)
+			d++
+		}
+		if err != nil {
+			return 0, err
+		}
+		if d > depth {
+			depth = d
+		}
+	}
+	return depth, nil
+}
+
+// orderedMap preserves field order when encoded as JSON, matching the order
+// of the selection set as the GraphQL spec requires
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (m *orderedMap) set(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// MarshalJSON encodes the map with keys in insertion order
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return []byte(b.String()), nil
+}
+
+type executor struct {
+	schema    *Schema
+	doc       *document
+	variables map[string]interface{}
+	errors    []Error
+}
+
+func (e *executor) fail(path []interface{}, format string, args ...interface{}) {
+	e.errors = append(e.errors, Error{
+		Message: fmt.Sprintf(format, args...),
+		Path:    append([]interface{}(nil), path...),
+	})
+}
+
+func (e *executor) executeObject(ctx context.Context, object *Object, source interface{}, set []selection, path []interface{}) *orderedMap {
+	result := &orderedMap{values: make(map[string]interface{})}
+	e.collect(ctx, object, source, set, path, result)
+	return result
+}
+
+// collect resolves the selection set into result, expanding fragments whose
+// type condition matches the object
+func (e *executor) collect(ctx context.Context, object *Object, source interface{}, set []selection, path []interface{}, result *orderedMap) {
+	for _, sel := range set {
+		if sel.fragmentName != "" {
+			frag, ok := e.doc.fragments[sel.fragmentName]
+			if !ok {
+				e.fail(path, "unknown fragment %q", sel.fragmentName)
+				continue
+			}
+			if frag.typeCondition == object.Name {
+				e.collect(ctx, object, source, frag.selectionSet, path, result)
+			}
+			continue
+		}
+
+		if sel.inline {
+			if sel.typeCondition == "" || sel.typeCondition == object.Name {
+				e.collect(ctx, object, source, sel.selectionSet, path, result)
+			}
+			continue
+		}
+
+		fieldPath := appendPath(path, sel.alias)
+
+		if sel.name == "__typename" {
+			result.set(sel.alias, object.Name)
+			continue
+		}
+
+		field, ok := object.Fields[sel.name]
+		if !ok {
+			e.fail(fieldPath, "cannot query field %q on type %q", sel.name, object.Name)
+			continue
+		}
+
+		args := e.resolveArguments(sel.arguments)
+
+		var value interface{}
+		var err error
+		if field.Resolve != nil {
+			value, err = field.Resolve(ctx, source, args)
+		} else {
+			value, err = structField(source, sel.name)
+		}
+		if err != nil {
+			e.fail(fieldPath, "%s", err.Error())
+			result.set(sel.alias, nil)
+			continue
+		}
+
+		result.set(sel.alias, e.completeValue(ctx, field, value, sel, fieldPath))
+	}
+}
+
+// completeValue applies the sub-selection to object values and lists
+func (e *executor) completeValue(ctx context.Context, field *Field, value interface{}, sel selection, path []interface{}) interface{} {
+	if field.Type == "" {
+		if len(sel.selectionSet) > 0 {
+			e.fail(path, "field %q is a scalar and cannot have a selection set", sel.name)
+			return nil
+		}
+		return value
+	}
+
+	if len(sel.selectionSet) == 0 {
+		e.fail(path, "field %q of type %q must have a selection set", sel.name, field.Type)
+		return nil
+	}
+
+	object := e.schema.types[field.Type]
+	v := reflect.ValueOf(value)
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = e.executeObject(ctx, object, v.Index(i).Interface(), sel.selectionSet, appendPath(path, i))
+		}
+		return items
+	}
+
+	return e.executeObject(ctx, object, v.Interface(), sel.selectionSet, path)
+}
+
+// appendPath returns path with elem added, in a new slice so paths built
+// from the same parent never share, and overwrite, each other's elements
+func appendPath(path []interface{}, elem interface{}) []interface{} {
+	return append(append(make([]interface{}, 0, len(path)+1), path...), elem)
+}
+
+// resolveArguments substitutes variable references in argument values
+func (e *executor) resolveArguments(args map[string]interface{}) map[string]interface{} {
+	resolved := make(map[string]interface{}, len(args))
+	for name, value := range args {
+		resolved[name] = e.resolveValue(value)
+	}
+	return resolved
+}
+
+func (e *executor) resolveValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case variable:
+		return e.variables[string(v)]
+	case enumValue:
+		return string(v)
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = e.resolveValue(item)
+		}
+		return list
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for name, item := range v {
+			object[name] = e.resolveValue(item)
+		}
+		return object
+	default:
+		return value
+	}
+}
+
+// structField reads the field of a struct whose JSON tag matches name,
+// searching embedded structs
+func structField(source interface{}, name string) (interface{}, error) {
+	v := reflect.ValueOf(source)
+	for v.IsValid() && v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot resolve field %q", name)
+	}
+
+	if value, ok := findJSONField(v, name); ok {
+		return value, nil
+	}
+	return nil, fmt.Errorf("cannot resolve field %q", name)
+}
+
+func findJSONField(v reflect.Value, name string) (interface{}, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Anonymous && tag == "" {
+			if value, ok := findJSONField(reflect.Indirect(v.Field(i)), name); ok {
+				return value, true
+			}
+			continue
+		}
+
+		if tag == name || (tag == "" && f.Name == name) {
+			return v.Field(i).Interface(), true
+		}
+	}
+
+	return nil, false
+}
+
+// StringArg returns a string argument, or false when it is missing or null
+func StringArg(args map[string]interface{}, name string) (string, bool) {
+	s, ok := args[name].(string)
+	return s, ok
+}
+
+// IntArg returns an integer argument, accepting both query literals and
+// JSON-decoded variables
+func IntArg(args map[string]interface{}, name string) (int, bool) {
+	switch v := args[name].(type) {
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	default:
+		return 0, false
+	}
+}
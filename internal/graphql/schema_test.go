@@ -0,0 +1,151 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+type testMoney struct {
+	Amount string `json:"amount"`
+}
+
+type testAccount struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name"`
+	Balance testMoney `json:"balance"`
+}
+
+func newTestSchema(t *testing.T) *Schema {
+	t.Helper()
+
+	accounts := []testAccount{
+		{ID: "1", Name: "Everyday", Balance: testMoney{Amount: "10.00"}},
+		{ID: "2", Name: "Saver", Balance: testMoney{Amount: "20.00"}},
+	}
+
+	query := &Object{
+		Name: "Query",
+		Fields: map[string]*Field{
+			"accounts": {
+				Type: "Account",
+				Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+					if limit, ok := IntArg(args, "limit"); ok {
+						return accounts[:limit], nil
+					}
+					return accounts, nil
+				},
+			},
+		},
+	}
+	account := &Object{
+		Name: "Account",
+		Fields: map[string]*Field{
+			"id":      {},
+			"name":    {},
+			"balance": {Type: "Money"},
+		},
+	}
+	money := &Object{Name: "Money", Fields: map[string]*Field{"amount": {}}}
+
+	schema, err := NewSchema(query, account, money)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func execute(t *testing.T, schema *Schema, req Request) string {
+	t.Helper()
+
+	out, err := json.Marshal(schema.Execute(context.Background(), req))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestExecuteSelectsRequestedFields(t *testing.T) {
+	got := execute(t, newTestSchema(t), Request{
+		Query: `{ accounts { name balance { amount } } }`,
+	})
+
+	want := `{"data":{"accounts":[{"name":"Everyday","balance":{"amount":"10.00"}},{"name":"Saver","balance":{"amount":"20.00"}}]}}`
+	if got != want {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+}
+
+func TestExecuteAliasesVariablesAndFragments(t *testing.T) {
+	got := execute(t, newTestSchema(t), Request{
+		Query: `
+			query Accounts($limit: Int) {
+				first: accounts(limit: $limit) { ...accountFields __typename }
+			}
+			fragment accountFields on Account { id }
+		`,
+		Variables: map[string]interface{}{"limit": float64(1)},
+	})
+
+	want := `{"data":{"first":[{"id":"1","__typename":"Account"}]}}`
+	if got != want {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+}
+
+func TestExecuteReportsErrors(t *testing.T) {
+	schema := newTestSchema(t)
+
+	got := execute(t, schema, Request{Query: `{ accounts { iban } }`})
+	want := `{"data":{"accounts":[{},{}]},"errors":[{"message":"cannot query field \"iban\" on type \"Account\"","path":["accounts",0,"iban"]},{"message":"cannot query field \"iban\" on type \"Account\"","path":["accounts",1,"iban"]}]}`
+	if got != want {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+
+	got = execute(t, schema, Request{Query: `mutation { accounts { id } }`})
+	want = `{"data":null,"errors":[{"message":"mutation operations are not supported"}]}`
+	if got != want {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+}
+
+func TestExecuteEnforcesLimits(t *testing.T) {
+	schema := newTestSchema(t)
+	schema.MaxDepth = 2
+	schema.MaxFields = 4
+
+	got := execute(t, schema, Request{Query: `{ accounts { id balance { amount } } }`})
+	want := `{"data":null,"errors":[{"message":"query is nested 3 fields deep, more than the limit of 2"}]}`
+	if got != want {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+
+	got = execute(t, schema, Request{Query: `{ accounts { id name } accounts { id name } }`})
+	want = `{"data":null,"errors":[{"message":"query selects more than the limit of 4 fields"}]}`
+	if got != want {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+
+	schema.MaxDepth, schema.MaxFields = 0, 0
+	got = execute(t, schema, Request{Query: `{ accounts { ...a } } fragment a on Account { id ...b } fragment b on Account { ...a }`})
+	want = `{"data":null,"errors":[{"message":"fragment \"a\" is spread inside itself"}]}`
+	if got != want {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+
+	got = execute(t, schema, Request{Query: `{ accounts { ...a ...a } } fragment a on Account { id }`})
+	want = `{"data":{"accounts":[{"id":"1"},{"id":"2"}]}}`
+	if got != want {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+}
+
+func TestAppendPathCopies(t *testing.T) {
+	parent := make([]interface{}, 1, 4)
+	parent[0] = "accounts"
+	first := appendPath(parent, 0)
+	second := appendPath(parent, 1)
+	if first[1] != 0 || second[1] != 1 {
+		t.Errorf("expected sibling paths not to share elements, got %v and %v", first, second)
+	}
+}
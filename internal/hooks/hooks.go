@@ -0,0 +1,160 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the request body when a hook
+// has a secret
+const SignatureHeader = "X-NAB-Hook-Signature"
+
+// maxDecisionBytes bounds how much of a pre-hook reply is read
+const maxDecisionBytes = 64 << 10
+
+// ErrInvalidHook is returned when a hook registration fails validation
+var ErrInvalidHook = errors.New("invalid hook")
+
+// Caller posts payloads to refresh hooks
+type Caller struct {
+	client *http.Client
+}
+
+// NewCaller creates a hook caller with the given per-call timeout
+func NewCaller(timeout time.Duration) *Caller {
+	return &Caller{client: &http.Client{Timeout: timeout}}
+}
+
+// Pre calls a pre-scrape hook and returns its decision. Replies without a
+// recognised action are treated as proceed.
+func (c *Caller) Pre(ctx context.Context, hook model.RefreshHook, payload model.HookPayload) (model.HookDecision, error) {
+	proceed := model.HookDecision{Action: model.HookActionProceed}
+
+	body, err := c.post(ctx, hook, payload)
+	if err != nil {
+		return proceed, err
+	}
+
+	var decision model.HookDecision
+	if len(bytes.TrimSpace(body)) == 0 {
+		return proceed, nil
+	}
+	if err := json.Unmarshal(body, &decision); err != nil {
+		return proceed, fmt.Errorf("failed to decode hook reply: %w", err)
+	}
+
+	switch decision.Action {
+	case model.HookActionSkip, model.HookActionDelay:
+		return decision, nil
+	default:
+		return proceed, nil
+	}
+}
+
+// Post calls a post-scrape hook with the scrape results
+func (c *Caller) Post(ctx context.Context, hook model.RefreshHook, payload model.HookPayload) error {
+	_, err := c.post(ctx, hook, payload)
+	return err
+}
+
+// post sends the payload and returns the reply body
+func (c *Caller) post(ctx context.Context, hook model.RefreshHook, payload model.HookPayload) ([]byte, error) {
+	payload.HookID = hook.ID
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode hook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create hook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if hook.Secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(hook.Secret, raw))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("hook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDecisionBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hook reply: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("hook returned status %d", resp.StatusCode)
+	}
+
+	return body, nil
+}
+
+// Sign returns the hex HMAC-SHA256 of body keyed with secret, so receivers
+// can verify payloads came from this server
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Validate checks a hook registration and fills in default phases
+func Validate(req *model.RefreshHookRequest) error {
+	parsed, err := url.Parse(req.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidHook)
+	}
+
+	if len(req.Phases) == 0 {
+		req.Phases = []string{model.HookPhasePre, model.HookPhasePost}
+	}
+	for _, phase := range req.Phases {
+		if phase != model.HookPhasePre && phase != model.HookPhasePost {
+			return fmt.Errorf("%w: unknown phase %q", ErrInvalidHook, phase)
+		}
+	}
+
+	return nil
+}
+
+// HasPhase reports whether the hook is called in the given phase
+func HasPhase(hook model.RefreshHook, phase string) bool {
+	for _, p := range hook.Phases {
+		if p == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// NewHook builds a refresh hook for an account from a validated request
+func NewHook(accountID string, req model.RefreshHookRequest) (model.RefreshHook, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return model.RefreshHook{}, fmt.Errorf("failed to generate hook ID: %w", err)
+	}
+
+	return model.RefreshHook{
+		ID:        "hook_" + hex.EncodeToString(id),
+		AccountID: accountID,
+		URL:       req.URL,
+		Phases:    req.Phases,
+		Secret:    req.Secret,
+		Version:   1,
+		CreatedAt: time.Now(),
+	}, nil
+}
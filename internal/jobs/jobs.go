@@ -0,0 +1,180 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// Task is one item of a job. The result it returns is reported against
+// the item.
+type Task func(ctx context.Context) (interface{}, error)
+
+// jobIDKey is the context key for the ID of the job a task belongs to
+type jobIDKey struct{}
+
+// JobID returns the ID of the job running a task or plan, or "" outside
+// of a job
+func JobID(ctx context.Context) string {
+	id, _ := ctx.Value(jobIDKey{}).(string)
+	return id
+}
+
+// Plan works out a job's tasks in the background, for jobs whose items
+// aren't known until work has started, such as the accounts to sync
+type Plan func(ctx context.Context) ([]Task, error)
+
+// Manager runs jobs in the background and keeps their status for a while
+// after they finish so clients can poll for the outcome. Jobs live in
+// memory and are lost on restart.
+type Manager struct {
+	retention time.Duration
+	now       func() time.Time
+
+	mu   sync.Mutex
+	jobs map[string]*model.Job
+}
+
+// NewManager creates a job manager that forgets finished jobs after
+// retention
+func NewManager(retention time.Duration) *Manager {
+	return &Manager{
+		retention: retention,
+		now:       time.Now,
+		jobs:      make(map[string]*model.Job),
+	}
+}
+
+// Start records a job and runs its tasks one after another in the
+// background, returning the job as first recorded
+func (m *Manager) Start(jobType string, tasks []Task) (model.Job, error) {
+	job, snapshot, err := m.record(jobType, len(tasks))
+	if err != nil {
+		return model.Job{}, err
+	}
+
+	go m.run(job, tasks)
+
+	return snapshot, nil
+}
+
+// StartPlanned records a job whose tasks come from plan, which runs in the
+// background first. The job's total is filled in once the plan is known;
+// if planning fails the job completes with its error and no items.
+func (m *Manager) StartPlanned(jobType string, plan Plan) (model.Job, error) {
+	job, snapshot, err := m.record(jobType, 0)
+	if err != nil {
+		return model.Job{}, err
+	}
+
+	go func() {
+		tasks, err := plan(context.WithValue(context.Background(), jobIDKey{}, job.ID))
+		if err != nil {
+			m.mu.Lock()
+			completedAt := m.now()
+			job.Status = model.JobStatusCompleted
+			job.Error = err.Error()
+			job.CompletedAt = &completedAt
+			m.mu.Unlock()
+			return
+		}
+
+		m.mu.Lock()
+		job.Total = len(tasks)
+		m.mu.Unlock()
+		m.run(job, tasks)
+	}()
+
+	return snapshot, nil
+}
+
+// record adds a new running job, returning it along with a snapshot
+func (m *Manager) record(jobType string, total int) (*model.Job, model.Job, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, model.Job{}, fmt.Errorf("failed to generate job ID: %w", err)
+	}
+
+	job := &model.Job{
+		ID:        "job_" + hex.EncodeToString(id),
+		Type:      jobType,
+		Status:    model.JobStatusRunning,
+		Total:     total,
+		Items:     []model.JobItemResult{},
+		CreatedAt: m.now(),
+	}
+
+	m.mu.Lock()
+	m.prune()
+	m.jobs[job.ID] = job
+	snapshot := copyJob(job)
+	m.mu.Unlock()
+
+	return job, snapshot, nil
+}
+
+// Get returns a job's current status
+func (m *Manager) Get(id string) (model.Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.prune()
+	job, ok := m.jobs[id]
+	if !ok {
+		return model.Job{}, false
+	}
+	return copyJob(job), true
+}
+
+// run processes a job's tasks, recording each outcome as it finishes.
+// Jobs outlive the request that started them, so they aren't cancelled
+// with it.
+func (m *Manager) run(job *model.Job, tasks []Task) {
+	ctx := context.WithValue(context.Background(), jobIDKey{}, job.ID)
+	for i, task := range tasks {
+		result, err := task(ctx)
+
+		item := model.JobItemResult{Index: i, Status: model.JobItemSucceeded, Result: result}
+		if err != nil {
+			item = model.JobItemResult{Index: i, Status: model.JobItemFailed, Error: err.Error()}
+		}
+
+		m.mu.Lock()
+		job.Items = append(job.Items, item)
+		if err != nil {
+			job.Failed++
+		} else {
+			job.Succeeded++
+		}
+		m.mu.Unlock()
+	}
+
+	m.mu.Lock()
+	completedAt := m.now()
+	job.Status = model.JobStatusCompleted
+	job.CompletedAt = &completedAt
+	m.mu.Unlock()
+}
+
+// prune forgets jobs that finished more than the retention period ago.
+// Callers must hold the lock.
+func (m *Manager) prune() {
+	cutoff := m.now().Add(-m.retention)
+	for id, job := range m.jobs {
+		if job.CompletedAt != nil && job.CompletedAt.Before(cutoff) {
+			delete(m.jobs, id)
+		}
+	}
+}
+
+// copyJob copies a job so callers can't race with the job's progress
+func copyJob(job *model.Job) model.Job {
+	snapshot := *job
+	snapshot.Items = append([]model.JobItemResult{}, job.Items...)
+	return snapshot
+}
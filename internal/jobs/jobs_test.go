@@ -0,0 +1,108 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestManagerRunsTasksInBackground(t *testing.T) {
+	manager := NewManager(time.Hour)
+	release := make(chan struct{})
+	tasks := []Task{
+		func(ctx context.Context) (interface{}, error) {
+			<-release
+			return "tagged", nil
+		},
+		func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("transaction not found")
+		},
+	}
+
+	job, err := manager.Start("bulk", tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != model.JobStatusRunning || job.Total != 2 || len(job.Items) != 0 {
+		t.Fatalf("expected a running job with no results yet, got %+v", job)
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for job.Status != model.JobStatusCompleted {
+		if time.Now().After(deadline) {
+			t.Fatal("job didn't complete")
+		}
+		time.Sleep(time.Millisecond)
+		job, _ = manager.Get(job.ID)
+	}
+
+	if job.Succeeded != 1 || job.Failed != 1 {
+		t.Errorf("expected one success and one failure, got %+v", job)
+	}
+	if job.Items[0].Result != "tagged" || job.Items[1].Error != "transaction not found" {
+		t.Errorf("unexpected item results %+v", job.Items)
+	}
+
+	manager.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, ok := manager.Get(job.ID); ok {
+		t.Error("expected the finished job to be forgotten after the retention period")
+	}
+}
+
+func TestManagerRunsPlannedTasks(t *testing.T) {
+	manager := NewManager(time.Hour)
+	release := make(chan struct{})
+	job, err := manager.StartPlanned("sync", func(ctx context.Context) ([]Task, error) {
+		<-release
+		return []Task{
+			func(ctx context.Context) (interface{}, error) { return "12345678", nil },
+			func(ctx context.Context) (interface{}, error) { return "87654321", nil },
+		}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != model.JobStatusRunning || job.Total != 0 {
+		t.Fatalf("expected a running job with no plan yet, got %+v", job)
+	}
+
+	close(release)
+	job = waitForJob(t, manager, job.ID)
+	if job.Total != 2 || job.Succeeded != 2 || job.Error != "" {
+		t.Errorf("expected both planned tasks to succeed, got %+v", job)
+	}
+
+	failed, err := manager.StartPlanned("sync", func(ctx context.Context) ([]Task, error) {
+		return nil, errors.New("login timed out")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	failed = waitForJob(t, manager, failed.ID)
+	if failed.Error != "login timed out" || failed.Total != 0 {
+		t.Errorf("expected the plan's error on the job, got %+v", failed)
+	}
+}
+
+// waitForJob polls until a job completes
+func waitForJob(t *testing.T, manager *Manager, id string) model.Job {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		job, ok := manager.Get(id)
+		if !ok {
+			t.Fatalf("job %s not found", id)
+		}
+		if job.Status == model.JobStatusCompleted {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("job didn't complete")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
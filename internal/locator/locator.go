@@ -0,0 +1,239 @@
+package locator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// DefaultURL is NAB's public location search API, used by the ATM and
+// branch locator on nab.com.au
+const DefaultURL = "https://api.nab.com.au/info/nab/location/locationType/atm+brc/queryType/geo"
+
+// Query describes a locator search around a point
+type Query struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+	Type      string
+	Limit     int
+}
+
+// Client proxies NAB's public ATM and branch locator. No login is needed.
+// Responses are cached by approximate position so nearby repeat searches
+// don't hit NAB.
+type Client struct {
+	baseURL    string
+	apiKey     string
+	ttl        time.Duration
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+// cacheEntry holds a cached upstream result
+type cacheEntry struct {
+	locations []model.Location
+	expires   time.Time
+}
+
+// NewClient creates a locator client. A zero ttl disables caching.
+func NewClient(baseURL, apiKey string, ttl time.Duration) *Client {
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		ttl:        ttl,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		cache:      make(map[string]cacheEntry),
+	}
+}
+
+// Search returns locations near the query point, nearest first. The second
+// return value reports whether the result came from the cache.
+func (c *Client) Search(ctx context.Context, q Query) ([]model.Location, bool, error) {
+	key := cacheKey(q)
+	locations, cached := c.cached(key)
+	if !cached {
+		var err error
+		locations, err = c.fetch(ctx, q)
+		if err != nil {
+			return nil, false, err
+		}
+		c.store(key, locations)
+	}
+
+	var results []model.Location
+	for _, location := range locations {
+		if q.Type != "" && location.Type != q.Type {
+			continue
+		}
+		location.DistanceKm = math.Round(distanceKm(q.Latitude, q.Longitude, location.Latitude, location.Longitude)*100) / 100
+		if q.RadiusKm > 0 && location.DistanceKm > q.RadiusKm {
+			continue
+		}
+		results = append(results, location)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].DistanceKm < results[j].DistanceKm
+	})
+	if q.Limit > 0 && len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+
+	return results, cached, nil
+}
+
+// cached returns an unexpired cache entry
+func (c *Client) cached(key string) ([]model.Location, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.cache[key]
+	if !ok || time.Now().After(entry.expires) {
+		delete(c.cache, key)
+		return nil, false
+	}
+	return entry.locations, true
+}
+
+// store caches an upstream result, dropping expired entries as it goes
+func (c *Client) store(key string, locations []model.Location) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range c.cache {
+		if now.After(entry.expires) {
+			delete(c.cache, k)
+		}
+	}
+	c.cache[key] = cacheEntry{locations: locations, expires: now.Add(c.ttl)}
+}
+
+// cacheKey rounds the position to roughly 100m so nearby searches share an
+// entry. Type and limit are applied after the cache so they are not part
+// of the key.
+func cacheKey(q Query) string {
+	return fmt.Sprintf("%.3f,%.3f,%g", q.Latitude, q.Longitude, q.RadiusKm)
+}
+
+// locationResponse is the upstream search response
+type locationResponse struct {
+	LocationSearchResponse struct {
+		Locations []struct {
+			APIStructType string       `json:"apiStructType"`
+			ATM           *nabLocation `json:"atm"`
+			Branch        *nabLocation `json:"brc"`
+		} `json:"locations"`
+	} `json:"locationSearchResponse"`
+}
+
+// nabLocation is an upstream ATM or branch entry
+type nabLocation struct {
+	Key         string  `json:"key"`
+	Description string  `json:"description"`
+	Address1    string  `json:"address1"`
+	Suburb      string  `json:"suburb"`
+	State       string  `json:"state"`
+	Postcode    string  `json:"postcode"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+}
+
+// fetch queries NAB for locations inside a bounding box around the point
+func (c *Client) fetch(ctx context.Context, q Query) ([]model.Location, error) {
+	radius := q.RadiusKm
+	if radius <= 0 {
+		radius = 5
+	}
+
+	// Degrees of latitude are ~111km; longitude shrinks with latitude
+	dLat := radius / 111.0
+	dLng := radius / (111.0 * math.Max(math.Cos(q.Latitude*math.Pi/180), 0.01))
+	url := fmt.Sprintf("%s/%f/%f/%f/%f/1/500?v=1",
+		c.baseURL, q.Latitude-dLat, q.Longitude-dLng, q.Latitude+dLat, q.Longitude+dLng)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create locator request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("x-nab-key", c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("locator request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("locator returned %s", resp.Status)
+	}
+
+	var body locationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode locator response: %w", err)
+	}
+
+	var locations []model.Location
+	for _, entry := range body.LocationSearchResponse.Locations {
+		switch {
+		case entry.ATM != nil:
+			locations = append(locations, entry.ATM.toModel(model.LocationTypeATM))
+		case entry.Branch != nil:
+			locations = append(locations, entry.Branch.toModel(model.LocationTypeBranch))
+		}
+	}
+
+	return locations, nil
+}
+
+// toModel converts an upstream entry. NAB ATMs are fee-free for NAB
+// customers, so every ATM the locator returns is.
+func (l *nabLocation) toModel(locationType string) model.Location {
+	return model.Location{
+		ID:        l.Key,
+		Type:      locationType,
+		Name:      l.Description,
+		Address:   l.Address1,
+		Suburb:    l.Suburb,
+		State:     l.State,
+		Postcode:  l.Postcode,
+		Latitude:  l.Latitude,
+		Longitude: l.Longitude,
+		FeeFree:   locationType == model.LocationTypeATM,
+	}
+}
+
+// distanceKm returns the great-circle distance between two points
+func distanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	const earthRadiusKm = 6371.0
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return earthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
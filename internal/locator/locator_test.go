@@ -0,0 +1,66 @@
+package locator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testResponse = `{"locationSearchResponse": {"locations": [
+	{"apiStructType": "atm", "atm": {"key": "far", "description": "Far ATM", "latitude": -37.85, "longitude": 144.96}},
+	{"apiStructType": "brc", "brc": {"key": "branch", "description": "Bourke St", "latitude": -37.8155, "longitude": 144.9600}},
+	{"apiStructType": "atm", "atm": {"key": "near", "description": "Near ATM", "latitude": -37.8153, "longitude": 144.9598}}
+]}}`
+
+func TestSearchSortsFiltersAndCaches(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if got := r.Header.Get("x-nab-key"); got != "key" {
+			t.Errorf("x-nab-key = %q", got)
+		}
+		w.Write([]byte(testResponse))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", time.Minute)
+	q := Query{Latitude: -37.8152, Longitude: 144.9597, RadiusKm: 5, Type: "atm"}
+
+	locations, cached, err := client.Search(context.Background(), q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached {
+		t.Error("first search should not be cached")
+	}
+	if len(locations) != 2 || locations[0].ID != "near" || locations[1].ID != "far" {
+		t.Fatalf("unexpected locations %+v", locations)
+	}
+	if !locations[0].FeeFree || locations[0].DistanceKm > locations[1].DistanceKm {
+		t.Errorf("unexpected first location %+v", locations[0])
+	}
+
+	// A nearby search with a different filter reuses the cached response
+	q.Latitude += 0.0001
+	q.Type = "branch"
+	locations, cached, err = client.Search(context.Background(), q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cached || len(locations) != 1 || locations[0].Type != "branch" {
+		t.Errorf("expected cached branch result, got cached=%v %+v", cached, locations)
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("upstream requests = %d, want 1", got)
+	}
+}
+
+func TestDistanceKm(t *testing.T) {
+	// Melbourne to Sydney is roughly 714km
+	if d := distanceKm(-37.8136, 144.9631, -33.8688, 151.2093); d < 700 || d > 730 {
+		t.Errorf("distanceKm = %f", d)
+	}
+}
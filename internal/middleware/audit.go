@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/audit"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/gorilla/mux"
+)
+
+// unauditedPaths are probe and metrics endpoints polled too often to be
+// worth auditing
+var unauditedPaths = []string{"/health", "/livez", "/readyz", "/metrics"}
+
+// Audit records every API call in the audit log: the token ID of the key
+// presented, the route, status and duration. The caller is also recorded
+// in the request context so the actions it takes, such as transfers, are
+// attributed to it. Keys are identified by TokenID whether or not they
+// are valid, so rejected keys can be traced too.
+func Audit(auditLog *audit.Log) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, path := range unauditedPaths {
+				if strings.HasPrefix(r.URL.Path, path) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			actor := audit.AnonymousActor
+			if key := requestAPIKey(r); key != "" {
+				actor = TokenID(key)
+			}
+
+			started := time.Now()
+			counter := &countingWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(counter, r.WithContext(audit.WithActor(r.Context(), actor)))
+
+			result := model.AuditResultSuccess
+			if counter.status >= http.StatusBadRequest {
+				result = model.AuditResultFailure
+			}
+			remoteAddr, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				remoteAddr = r.RemoteAddr
+			}
+			auditLog.Record(model.AuditEntry{
+				Time:       started,
+				Actor:      actor,
+				Kind:       model.AuditKindAPICall,
+				Action:     endpointName(r),
+				Result:     result,
+				Status:     counter.status,
+				RemoteAddr: remoteAddr,
+				DurationMs: time.Since(started).Milliseconds(),
+			})
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/audit"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/gorilla/mux"
+)
+
+func TestAudit(t *testing.T) {
+	auditLog, err := audit.Open("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actor string
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/transfers", func(w http.ResponseWriter, r *http.Request) {
+		actor = audit.Actor(r.Context())
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}).Methods("POST")
+	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {}).Methods("GET")
+	router.Use(Audit(auditLog))
+
+	req := httptest.NewRequest("POST", "/api/v1/transfers", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/health", nil))
+
+	if actor != TokenID("secret") {
+		t.Errorf("expected the handler to see the caller, got %q", actor)
+	}
+	entries, err := auditLog.Query(audit.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected only the transfer call audited, got %+v", entries)
+	}
+	entry := entries[0]
+	if entry.Actor != TokenID("secret") || entry.Kind != model.AuditKindAPICall || entry.Action != "POST /api/v1/transfers" ||
+		entry.Status != http.StatusUnprocessableEntity || entry.Result != model.AuditResultFailure {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/gorilla/mux"
+)
+
+// APIKeyAuth rejects requests that don't present one of the configured API
+// keys, either as a bearer token or in the X-API-Key header. With no keys
+// configured every request is rejected.
+func APIKeyAuth(keys []string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !validAPIKey(requestAPIKey(r), keys) {
+				writeUnauthorized(w)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// TokenAuthenticator resolves a presented API key to the ID of a token
+// that is currently valid
+type TokenAuthenticator interface {
+	Authenticate(key string) (string, bool)
+}
+
+// TokenAuth rejects requests that don't present a valid managed API token,
+// either as a bearer token or in the X-API-Key header
+func TokenAuth(tokens TokenAuthenticator) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := requestAPIKey(r)
+			if key == "" {
+				writeUnauthorized(w)
+				return
+			}
+			if _, ok := tokens.Authenticate(key); !ok {
+				writeUnauthorized(w)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeUnauthorized writes the 401 response for a missing or invalid key
+func writeUnauthorized(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	w.WriteHeader(http.StatusUnauthorized)
+	_ = json.NewEncoder(w).Encode(model.ErrorResponse{
+		Error:     model.ErrorTypeAuthenticationFailed,
+		Message:   "A valid API key is required",
+		Timestamp: time.Now(),
+	})
+}
+
+// requestAPIKey extracts the API key presented by the request
+func requestAPIKey(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return r.Header.Get("X-API-Key")
+}
+
+// validAPIKey compares the presented key against each configured key in
+// constant time
+func validAPIKey(presented string, keys []string) bool {
+	if presented == "" {
+		return false
+	}
+
+	valid := false
+	for _, key := range keys {
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(key)) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
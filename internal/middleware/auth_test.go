@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIKeyAuth(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		keys   []string
+		header string
+		value  string
+		want   int
+	}{
+		{"bearer token", []string{"secret"}, "Authorization", "Bearer secret", http.StatusOK},
+		{"api key header", []string{"other", "secret"}, "X-API-Key", "secret", http.StatusOK},
+		{"wrong key", []string{"secret"}, "X-API-Key", "guess", http.StatusUnauthorized},
+		{"missing key", []string{"secret"}, "", "", http.StatusUnauthorized},
+		{"no keys configured", nil, "X-API-Key", "secret", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/query", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+
+			rr := httptest.NewRecorder()
+			APIKeyAuth(tt.keys)(next).ServeHTTP(rr, req)
+
+			if rr.Code != tt.want {
+				t.Errorf("got status %d, want %d", rr.Code, tt.want)
+			}
+		})
+	}
+}
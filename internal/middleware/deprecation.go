@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/gorilla/mux"
+)
+
+// Deprecation announces that an API version is deprecated on every
+// response from it: the Deprecation header (RFC 9745) with when it was
+// deprecated, the Sunset header (RFC 8594) once a removal date is set, and
+// a successor-version Link to the same resource under successorBasePath
+// when the router serves it there. Versions that aren't deprecated are
+// left alone.
+func Deprecation(version model.APIVersion, successorBasePath string, router *mux.Router) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		if version.Deprecated == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", fmt.Sprintf("@%d", version.Deprecated.Unix()))
+			if version.Sunset != nil {
+				w.Header().Set("Sunset", version.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if successor := successorURL(r, version.BasePath, successorBasePath, router); successor != "" {
+				w.Header().Add("Link", "<"+successor+`>; rel="successor-version"`)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// successorURL returns the request's URL under the successor base path if
+// the router has a route for it there, or "" if it doesn't
+func successorURL(r *http.Request, basePath, successorBasePath string, router *mux.Router) string {
+	if successorBasePath == "" || router == nil {
+		return ""
+	}
+	rest, ok := strings.CutPrefix(r.URL.Path, basePath)
+	if !ok {
+		return ""
+	}
+
+	successor := r.Clone(r.Context())
+	successor.URL.Path = successorBasePath + rest
+	successor.URL.RawPath = ""
+	var match mux.RouteMatch
+	if !router.Match(successor, &match) || match.MatchErr != nil {
+		return ""
+	}
+	if successor.URL.RawQuery != "" {
+		return successor.URL.Path + "?" + successor.URL.RawQuery
+	}
+	return successor.URL.Path
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/gorilla/mux"
+)
+
+func TestDeprecation(t *testing.T) {
+	deprecated := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+
+	router := mux.NewRouter()
+	v1 := router.PathPrefix("/api/v1").Subrouter()
+	v1.HandleFunc("/accounts", ok).Methods("GET")
+	v1.HandleFunc("/messages", ok).Methods("GET")
+	v1.Use(Deprecation(model.APIVersion{BasePath: "/api/v1", Deprecated: &deprecated, Sunset: &sunset}, "/api/v2", router))
+	v2 := router.PathPrefix("/api/v2").Subrouter()
+	v2.HandleFunc("/accounts", ok).Methods("GET")
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/accounts?limit=5", nil))
+	if got := rec.Header().Get("Deprecation"); got != "@1782864000" {
+		t.Errorf("Deprecation = %q", got)
+	}
+	if got := rec.Header().Get("Sunset"); got != "Fri, 01 Jan 2027 00:00:00 GMT" {
+		t.Errorf("Sunset = %q", got)
+	}
+	if got := rec.Header().Get("Link"); got != `</api/v2/accounts?limit=5>; rel="successor-version"` {
+		t.Errorf("Link = %q", got)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/messages", nil))
+	if rec.Header().Get("Deprecation") == "" || rec.Header().Get("Link") != "" {
+		t.Errorf("expected a deprecation without a successor link, got %v", rec.Header())
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v2/accounts", nil))
+	if rec.Header().Get("Deprecation") != "" {
+		t.Error("v2 responses shouldn't be marked deprecated")
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/gorilla/mux"
+)
+
+// Feature answers 404 for the routes it wraps while their feature flag is
+// off, so an experimental feature can be turned off per deployment
+func Feature(name string, enabled bool) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if enabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			writeError(w, http.StatusNotFound, model.ErrorTypeFeatureDisabled, fmt.Sprintf("The %s feature is disabled on this server", name))
+		})
+	}
+}
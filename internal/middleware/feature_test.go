@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/gorilla/mux"
+)
+
+func TestFeature(t *testing.T) {
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+
+	router := mux.NewRouter()
+	enabled := router.PathPrefix("/enabled").Subrouter()
+	enabled.HandleFunc("/transfers", ok).Methods("POST")
+	enabled.Use(Feature("payments", true))
+	disabled := router.PathPrefix("/disabled").Subrouter()
+	disabled.HandleFunc("/transfers", ok).Methods("POST")
+	disabled.Use(Feature("payments", false))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("POST", "/enabled/transfers", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected an enabled feature to be served, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("POST", "/disabled/transfers", nil))
+	var response model.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusNotFound || response.Error != model.ErrorTypeFeatureDisabled {
+		t.Errorf("expected a disabled feature to be a 404, got %d %+v", rec.Code, response)
+	}
+}
@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/gorilla/mux"
+)
+
+// maxIdempotencyKeyLength caps the Idempotency-Key header
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyStore persists the responses to requests made with an
+// Idempotency-Key header
+type IdempotencyStore interface {
+	IdempotentResponse(key string) (model.IdempotentResponse, bool)
+	SaveIdempotentResponse(response model.IdempotentResponse, expiredBefore time.Time) error
+}
+
+// TokenLineage resolves a token to the one it was first issued as,
+// following rotations back from the replacement to the original
+type TokenLineage interface {
+	OriginalTokenID(id string) string
+}
+
+// Idempotency replays the saved response to a POST retried with the same
+// Idempotency-Key header instead of running it again, so a transfer or
+// payment retried after a network blip can't be made twice. Keys are
+// scoped to the API token presented, or the token it was rotated from when
+// lineage is given, so a retry sent with a rotated key is still replayed.
+// Keys are kept for ttl. Every response is
+// saved, failures included, as a request that failed part way may still
+// have reached NAB. Reusing a key for a different request is rejected
+// with a 422, and a retry that arrives while the first is still running
+// gets a 409. A response that can't be saved to the store is logged and
+// kept in memory for ttl instead, so retries are still replayed until the
+// server restarts.
+// The lineage may be nil.
+func Idempotency(store IdempotencyStore, lineage TokenLineage, ttl time.Duration, logger *log.Logger) mux.MiddlewareFunc {
+	var mu sync.Mutex
+	running := make(map[string]bool)
+	unsaved := make(map[string]model.IdempotentResponse)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Idempotency-Key")
+			if r.Method != http.MethodPost || header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(header) > maxIdempotencyKeyLength {
+				writeError(w, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Idempotency-Key must be at most 255 characters")
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, model.ErrorTypeInvalidRequest, "Failed to read request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			scope := TokenID(requestAPIKey(r))
+			if lineage != nil {
+				scope = lineage.OriginalTokenID(scope)
+			}
+			key := scope + ":" + header
+			fingerprint := requestFingerprint(r, body)
+
+			mu.Lock()
+			saved, ok := unsaved[key]
+			if !ok {
+				saved, ok = store.IdempotentResponse(key)
+			}
+			if ok && time.Since(saved.CreatedAt) < ttl {
+				mu.Unlock()
+				if saved.Fingerprint != fingerprint {
+					writeError(w, http.StatusUnprocessableEntity, model.ErrorTypeIdempotencyKeyReused, "Idempotency-Key was already used for a different request")
+					return
+				}
+				replay(w, saved)
+				return
+			}
+			if running[key] {
+				mu.Unlock()
+				writeError(w, http.StatusConflict, model.ErrorTypeRequestInProgress, "A request with this Idempotency-Key is still in progress")
+				return
+			}
+			running[key] = true
+			mu.Unlock()
+			// Released even if the handler panics, so the key isn't stuck
+			// in progress
+			defer func() {
+				mu.Lock()
+				delete(running, key)
+				mu.Unlock()
+			}()
+
+			capture := &capturingWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(capture, r)
+
+			now := time.Now()
+			response := model.IdempotentResponse{
+				Key:         key,
+				Fingerprint: fingerprint,
+				Status:      capture.status,
+				ContentType: capture.Header().Get("Content-Type"),
+				Body:        capture.body.Bytes(),
+				CreatedAt:   now,
+			}
+			err = store.SaveIdempotentResponse(response, now.Add(-ttl))
+
+			mu.Lock()
+			for unsavedKey, saved := range unsaved {
+				if now.Sub(saved.CreatedAt) >= ttl {
+					delete(unsaved, unsavedKey)
+				}
+			}
+			if err != nil {
+				logger.Printf("Failed to save response for Idempotency-Key %s, keeping it in memory: %v", header, err)
+				unsaved[key] = response
+			}
+			mu.Unlock()
+		})
+	}
+}
+
+// requestFingerprint identifies a request by its method, path, query and
+// body
+func requestFingerprint(r *http.Request, body []byte) string {
+	sum := sha256.New()
+	io.WriteString(sum, r.Method+" "+r.URL.RequestURI()+"\n")
+	sum.Write(body)
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// replay writes a saved response, marked as a replay
+func replay(w http.ResponseWriter, saved model.IdempotentResponse) {
+	if saved.ContentType != "" {
+		w.Header().Set("Content-Type", saved.ContentType)
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(saved.Status)
+	_, _ = w.Write(saved.Body)
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, errorType, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(model.ErrorResponse{
+		Error:     errorType,
+		Message:   message,
+		Timestamp: time.Now(),
+	})
+}
+
+// capturingWriter keeps a copy of the response so it can be replayed
+type capturingWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *capturingWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *capturingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer so streaming responses can flush
+func (w *capturingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
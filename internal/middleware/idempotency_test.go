@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/gorilla/mux"
+)
+
+type memoryIdempotencyStore map[string]model.IdempotentResponse
+
+func (s memoryIdempotencyStore) IdempotentResponse(key string) (model.IdempotentResponse, bool) {
+	response, ok := s[key]
+	return response, ok
+}
+
+func (s memoryIdempotencyStore) SaveIdempotentResponse(response model.IdempotentResponse, expiredBefore time.Time) error {
+	s[response.Key] = response
+	return nil
+}
+
+func TestIdempotency(t *testing.T) {
+	transfers := 0
+	router := mux.NewRouter()
+	router.HandleFunc("/transfers", func(w http.ResponseWriter, r *http.Request) {
+		transfers++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"completed"}`))
+	}).Methods("POST")
+	router.Use(Idempotency(memoryIdempotencyStore{}, nil, time.Hour, log.New(io.Discard, "", 0)))
+
+	send := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/transfers", strings.NewReader(body))
+		req.Header.Set("X-API-Key", "secret")
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := send("abc", `{"amount":"10.00"}`)
+	retry := send("abc", `{"amount":"10.00"}`)
+	if transfers != 1 {
+		t.Fatalf("expected the retry to be replayed, got %d transfers", transfers)
+	}
+	if retry.Code != first.Code || retry.Body.String() != first.Body.String() || retry.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("unexpected replay %d %q %v", retry.Code, retry.Body.String(), retry.Header())
+	}
+
+	if rec := send("abc", `{"amount":"99.00"}`); rec.Code != http.StatusUnprocessableEntity || transfers != 1 {
+		t.Errorf("expected a reused key to be rejected, got %d", rec.Code)
+	}
+
+	send("", `{"amount":"10.00"}`)
+	send("def", `{"amount":"10.00"}`)
+	if transfers != 3 {
+		t.Errorf("expected requests without the key or with a new one to run, got %d transfers", transfers)
+	}
+}
+
+type failingIdempotencyStore struct{}
+
+func (failingIdempotencyStore) IdempotentResponse(key string) (model.IdempotentResponse, bool) {
+	return model.IdempotentResponse{}, false
+}
+
+func (failingIdempotencyStore) SaveIdempotentResponse(response model.IdempotentResponse, expiredBefore time.Time) error {
+	return errors.New("disk full")
+}
+
+func TestIdempotencyWithoutStore(t *testing.T) {
+	payments := 0
+	router := mux.NewRouter()
+	router.HandleFunc("/payments", func(w http.ResponseWriter, r *http.Request) {
+		payments++
+		if payments == 1 {
+			panic("scrape failed")
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}).Methods("POST")
+	router.Use(Idempotency(failingIdempotencyStore{}, nil, time.Hour, log.New(io.Discard, "", 0)))
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/payments", strings.NewReader(`{"amount":"10.00"}`))
+		req.Header.Set("Idempotency-Key", "abc")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	func() {
+		defer func() { recover() }()
+		send()
+	}()
+	if rec := send(); rec.Code != http.StatusAccepted {
+		t.Fatalf("expected a retry after a panic to run rather than conflict, got %d", rec.Code)
+	}
+	if rec := send(); rec.Code != http.StatusAccepted || rec.Header().Get("Idempotent-Replayed") != "true" || payments != 2 {
+		t.Errorf("expected the unsaved response to be replayed, got %d after %d payments", rec.Code, payments)
+	}
+}
+
+// rotations maps each rotated token to the one it replaced
+type rotations map[string]string
+
+func (r rotations) OriginalTokenID(id string) string {
+	for r[id] != "" {
+		id = r[id]
+	}
+	return id
+}
+
+func TestIdempotencyAcrossRotation(t *testing.T) {
+	transfers := 0
+	router := mux.NewRouter()
+	router.HandleFunc("/transfers", func(w http.ResponseWriter, r *http.Request) {
+		transfers++
+		w.WriteHeader(http.StatusOK)
+	}).Methods("POST")
+	lineage := rotations{TokenID("rotated"): TokenID("original")}
+	router.Use(Idempotency(memoryIdempotencyStore{}, lineage, time.Hour, log.New(io.Discard, "", 0)))
+
+	send := func(apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/transfers", strings.NewReader(`{"amount":"10.00"}`))
+		req.Header.Set("X-API-Key", apiKey)
+		req.Header.Set("Idempotency-Key", "abc")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	send("original")
+	if rec := send("rotated"); rec.Header().Get("Idempotent-Replayed") != "true" || transfers != 1 {
+		t.Errorf("expected a retry with the rotated key to be replayed, got %d transfers", transfers)
+	}
+	if send("someone-else"); transfers != 2 {
+		t.Errorf("expected another caller's key to run, got %d transfers", transfers)
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// untimedPaths are streams meant to stay open for as long as the client
+// listens
+var untimedPaths = []string{"/api/v1/events"}
+
+// RouteTimeouts bounds how long requests may run, by route group. Zero
+// means no limit.
+type RouteTimeouts struct {
+	Default      time.Duration
+	Accounts     time.Duration
+	Transactions time.Duration
+	Sync         time.Duration
+}
+
+// For returns the timeout for a request, chosen by its route: sync routes,
+// then anything under transactions, then anything under accounts, then
+// the default
+func (t RouteTimeouts) For(r *http.Request) time.Duration {
+	endpoint := endpointName(r)
+	switch {
+	case strings.Contains(endpoint, "/sync"):
+		return t.Sync
+	case strings.Contains(endpoint, "/transactions"):
+		return t.Transactions
+	case strings.Contains(endpoint, "/accounts"):
+		return t.Accounts
+	}
+	return t.Default
+}
+
+// Timeout gives each request a deadline from its route's timeout. The
+// deadline travels with the request's context into the services and the
+// browser, which abandon a scrape part way once it passes, as they do
+// when the client disconnects. Handlers report that as a 504.
+func Timeout(timeouts RouteTimeouts) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, path := range untimedPaths {
+				if strings.HasPrefix(r.URL.Path, path) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			timeout := timeouts.For(r)
+			if timeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
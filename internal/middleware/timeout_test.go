@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func TestTimeout(t *testing.T) {
+	timeouts := RouteTimeouts{Accounts: time.Minute, Transactions: 2 * time.Minute, Sync: 3 * time.Minute}
+
+	remaining := make(map[string]time.Duration)
+	record := func(w http.ResponseWriter, r *http.Request) {
+		if deadline, ok := r.Context().Deadline(); ok {
+			remaining[r.URL.Path] = time.Until(deadline).Round(time.Minute)
+		}
+	}
+	router := mux.NewRouter()
+	for _, path := range []string{"/api/v1/accounts/{accountId}", "/api/v1/transactions", "/api/v1/admin/sync", "/api/v1/payees", "/api/v1/events"} {
+		router.HandleFunc(path, record)
+	}
+	router.Use(Timeout(timeouts))
+
+	for _, path := range []string{"/api/v1/accounts/123", "/api/v1/transactions", "/api/v1/admin/sync", "/api/v1/payees", "/api/v1/events"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	want := map[string]time.Duration{
+		"/api/v1/accounts/123": time.Minute,
+		"/api/v1/transactions": 2 * time.Minute,
+		"/api/v1/admin/sync":   3 * time.Minute,
+	}
+	if len(remaining) != len(want) {
+		t.Fatalf("expected deadlines on %v only, got %v", want, remaining)
+	}
+	for path, timeout := range want {
+		if remaining[path] != timeout {
+			t.Errorf("%s: deadline in %s, want %s", path, remaining[path], timeout)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/gorilla/mux"
+)
+
+// UsageStore persists token usage between restarts
+type UsageStore interface {
+	TokenUsage() []model.TokenUsage
+	SaveTokenUsage(usage []model.TokenUsage) error
+}
+
+// tokenStats accumulates usage for one token
+type tokenStats struct {
+	usage     model.TokenUsage
+	endpoints map[string]int64
+}
+
+// UsageTracker records per-API-key request statistics
+type UsageTracker struct {
+	store         UsageStore
+	authenticator TokenAuthenticator
+
+	mu     sync.Mutex
+	keys   map[string]string
+	tokens map[string]*tokenStats
+	saved  map[string]model.TokenUsage
+	dirty  bool
+}
+
+// TokenID derives the public identifier for an API key
+func TokenID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "tok_" + hex.EncodeToString(sum[:])[:12]
+}
+
+// NewUsageTracker creates a tracker for the given static API keys and any
+// managed tokens the authenticator accepts, resuming from usage saved in
+// the store. Every static key is listed, including those that have never
+// been used; managed tokens are listed once used. The authenticator may be
+// nil.
+func NewUsageTracker(keys []string, authenticator TokenAuthenticator, store UsageStore) *UsageTracker {
+	t := &UsageTracker{
+		store:         store,
+		authenticator: authenticator,
+		keys:          make(map[string]string, len(keys)),
+		tokens:        make(map[string]*tokenStats, len(keys)),
+		saved:         make(map[string]model.TokenUsage),
+	}
+
+	for _, usage := range store.TokenUsage() {
+		t.saved[usage.TokenID] = usage
+	}
+
+	for _, key := range keys {
+		id := TokenID(key)
+		t.keys[key] = id
+		t.statsFor(id)
+	}
+
+	return t
+}
+
+// Middleware records usage for requests that present a configured key.
+// It doesn't authenticate; protected routes still need APIKeyAuth.
+func (t *UsageTracker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := t.tokenFor(requestAPIKey(r))
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		counter := &countingWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(counter, r)
+
+		bytesIn := r.ContentLength
+		if bytesIn < 0 {
+			bytesIn = 0
+		}
+		t.record(id, endpointName(r), counter.status, bytesIn, counter.bytes)
+	})
+}
+
+// Tokens returns usage for every configured token ordered by ID
+func (t *UsageTracker) Tokens() []model.TokenUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tokens := make([]model.TokenUsage, 0, len(t.tokens))
+	for _, stats := range t.tokens {
+		tokens = append(tokens, stats.snapshot())
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].TokenID < tokens[j].TokenID
+	})
+
+	return tokens
+}
+
+// Usage returns usage for one token, including saved usage for managed
+// tokens not yet seen since startup
+func (t *UsageTracker) Usage(id string) (model.TokenUsage, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats, ok := t.tokens[id]
+	if !ok {
+		usage, saved := t.saved[id]
+		return usage, saved
+	}
+	return stats.snapshot(), true
+}
+
+// Run saves usage to the store every interval until the context is
+// cancelled, and once more on the way out
+func (t *UsageTracker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = t.Flush()
+			return
+		case <-ticker.C:
+			_ = t.Flush()
+		}
+	}
+}
+
+// Flush saves usage to the store if anything has changed. Saved usage for
+// managed tokens not seen since startup is kept.
+func (t *UsageTracker) Flush() error {
+	t.mu.Lock()
+	if !t.dirty {
+		t.mu.Unlock()
+		return nil
+	}
+	t.dirty = false
+	var untracked []model.TokenUsage
+	for id, usage := range t.saved {
+		if _, ok := t.tokens[id]; !ok {
+			untracked = append(untracked, usage)
+		}
+	}
+	t.mu.Unlock()
+
+	return t.store.SaveTokenUsage(append(t.Tokens(), untracked...))
+}
+
+// tokenFor returns the token ID for a presented key, checking the static
+// keys before managed tokens
+func (t *UsageTracker) tokenFor(presented string) (string, bool) {
+	if presented == "" {
+		return "", false
+	}
+
+	t.mu.Lock()
+	for key, id := range t.keys {
+		if validAPIKey(presented, []string{key}) {
+			t.mu.Unlock()
+			return id, true
+		}
+	}
+	t.mu.Unlock()
+
+	if t.authenticator == nil {
+		return "", false
+	}
+	return t.authenticator.Authenticate(presented)
+}
+
+// statsFor returns the stats for a token, starting from any saved usage
+// the first time it is seen. Callers must hold the lock or be the
+// constructor.
+func (t *UsageTracker) statsFor(id string) *tokenStats {
+	if stats, ok := t.tokens[id]; ok {
+		return stats
+	}
+
+	stats := &tokenStats{
+		usage:     model.TokenUsage{TokenID: id},
+		endpoints: make(map[string]int64),
+	}
+	if usage, ok := t.saved[id]; ok {
+		stats.usage = usage
+		for _, endpoint := range usage.Endpoints {
+			stats.endpoints[endpoint.Endpoint] = endpoint.Requests
+		}
+	}
+	t.tokens[id] = stats
+	return stats
+}
+
+// record adds one request to a token's usage
+func (t *UsageTracker) record(id, endpoint string, status int, bytesIn, bytesOut int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := t.statsFor(id)
+	now := time.Now()
+	if stats.usage.FirstUsed == nil {
+		stats.usage.FirstUsed = &now
+	}
+	stats.usage.LastUsed = &now
+	stats.usage.Requests++
+	if status >= http.StatusBadRequest {
+		stats.usage.Errors++
+	}
+	stats.usage.BytesIn += bytesIn
+	stats.usage.BytesOut += bytesOut
+	stats.endpoints[endpoint]++
+	t.dirty = true
+}
+
+// snapshot returns a copy of the usage with endpoints busiest first
+func (s *tokenStats) snapshot() model.TokenUsage {
+	usage := s.usage
+	usage.Endpoints = make([]model.EndpointUsage, 0, len(s.endpoints))
+	for endpoint, requests := range s.endpoints {
+		usage.Endpoints = append(usage.Endpoints, model.EndpointUsage{Endpoint: endpoint, Requests: requests})
+	}
+	sort.Slice(usage.Endpoints, func(i, j int) bool {
+		if usage.Endpoints[i].Requests != usage.Endpoints[j].Requests {
+			return usage.Endpoints[i].Requests > usage.Endpoints[j].Requests
+		}
+		return usage.Endpoints[i].Endpoint < usage.Endpoints[j].Endpoint
+	})
+
+	return usage
+}
+
+// endpointName identifies the route template a request matched, so paths
+// with IDs are counted together
+func endpointName(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return r.Method + " " + template
+		}
+	}
+	return r.Method + " (unmatched)"
+}
+
+// countingWriter records the status code and bytes written
+type countingWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *countingWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap exposes the underlying writer so streaming responses can flush
+func (w *countingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/gorilla/mux"
+)
+
+// memoryUsageStore keeps saved usage in memory
+type memoryUsageStore struct {
+	usage []model.TokenUsage
+}
+
+func (s *memoryUsageStore) TokenUsage() []model.TokenUsage { return s.usage }
+
+func (s *memoryUsageStore) SaveTokenUsage(usage []model.TokenUsage) error {
+	s.usage = usage
+	return nil
+}
+
+func TestUsageTracker(t *testing.T) {
+	store := &memoryUsageStore{}
+	tracker := NewUsageTracker([]string{"busy", "idle"}, nil, store)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/accounts/{accountId}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	}).Methods("GET")
+	router.HandleFunc("/api/v1/query", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}).Methods("POST")
+	router.Use(tracker.Middleware)
+
+	for _, path := range []string{"/api/v1/accounts/1", "/api/v1/accounts/2"} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer busy")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	req := httptest.NewRequest("POST", "/api/v1/query", strings.NewReader(`{"sql":"x"}`))
+	req.Header.Set("X-API-Key", "busy")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Unknown keys aren't tracked
+	req = httptest.NewRequest("GET", "/api/v1/accounts/1", nil)
+	req.Header.Set("X-API-Key", "guess")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	usage, ok := tracker.Usage(TokenID("busy"))
+	if !ok {
+		t.Fatal("expected usage for busy token")
+	}
+	if usage.Requests != 3 || usage.Errors != 1 || usage.BytesIn != 11 || usage.BytesOut != 10 || usage.LastUsed == nil {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+	if len(usage.Endpoints) != 2 || usage.Endpoints[0] != (model.EndpointUsage{Endpoint: "GET /api/v1/accounts/{accountId}", Requests: 2}) {
+		t.Errorf("unexpected endpoints %+v", usage.Endpoints)
+	}
+
+	if idle, ok := tracker.Usage(TokenID("idle")); !ok || idle.Requests != 0 || idle.LastUsed != nil {
+		t.Errorf("expected unused idle token, got %+v", idle)
+	}
+
+	// Saved usage is picked up by a new tracker
+	if err := tracker.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	restored, _ := NewUsageTracker([]string{"busy"}, nil, store).Usage(TokenID("busy"))
+	if restored.Requests != 3 || len(restored.Endpoints) != 2 {
+		t.Errorf("expected usage to be restored, got %+v", restored)
+	}
+}
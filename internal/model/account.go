@@ -4,21 +4,47 @@ import (
 	"time"
 )
 
-// Money represents a monetary amount
+// Money represents a monetary amount. Currency is an ISO 4217 code; left
+// empty it means AUD, which is how amounts stored before currencies were
+// recorded read back.
 type Money struct {
-	Amount string `json:"amount" example:"1234.56"`
+	Amount   string `json:"amount" example:"1234.56"`
+	Currency string `json:"currency" example:"AUD"`
 }
 
 // Account represents a bank account
 type Account struct {
-	ID               string     `json:"id" example:"12345678"`
-	Name             string     `json:"name" example:"Complete Access Account"`
-	Type             string     `json:"type" example:"savings"`
-	Balance          Money      `json:"balance"`
-	AvailableBalance *Money     `json:"availableBalance,omitempty"`
-	AccountNumber    *string    `json:"accountNumber,omitempty" example:"****1234"`
-	BSB              *string    `json:"bsb,omitempty" example:"084001"`
-	LastUpdated      *time.Time `json:"lastUpdated,omitempty"`
+	ID               string              `json:"id" example:"12345678"`
+	Name             string              `json:"name" example:"Complete Access Account"`
+	Type             string              `json:"type" example:"savings"`
+	Balance          Money               `json:"balance"`
+	AvailableBalance *Money              `json:"availableBalance,omitempty"`
+	AccountNumber    *string             `json:"accountNumber,omitempty" example:"****1234"`
+	BSB              *string             `json:"bsb,omitempty" example:"084001"`
+	Interest         *InterestDetails    `json:"interest,omitempty"`
+	Credit           *CreditDetails      `json:"credit,omitempty"`
+	TermDeposit      *TermDepositDetails `json:"termDeposit,omitempty"`
+	LastUpdated      *time.Time          `json:"lastUpdated,omitempty"`
+
+	// Cached is set when NAB couldn't be reached and the account was
+	// served from the last successful scrape, as of LastUpdated
+	Cached bool `json:"cached,omitempty"`
+
+	// Stale is set when the account was served from the store past the
+	// cache TTL, as of LastUpdated, while a background scrape refreshes it
+	Stale bool `json:"stale,omitempty"`
+
+	// Archived is set on accounts archived through the bulk API so
+	// clients can hide them. They are still scraped.
+	Archived bool `json:"archived,omitempty"`
+
+	// Metadata is the nickname, emoji, colour and notes the user gave
+	// the account, if any
+	Metadata *AccountMetadata `json:"metadata,omitempty"`
+
+	// Provider names the bank provider the account came from when
+	// accounts from several banks are served together
+	Provider string `json:"provider,omitempty" example:"nab"`
 }
 
 // AccountsResponse represents the response for listing accounts
@@ -26,6 +52,10 @@ type AccountsResponse struct {
 	Accounts    []Account `json:"accounts"`
 	RetrievedAt time.Time `json:"retrievedAt"`
 	Count       int       `json:"count" example:"3"`
+	NextCursor  string    `json:"nextCursor,omitempty" example:"eyJnIjo0LCJvIjoyLCJhIjoiMTIzNDU2NzgifQ"`
+
+	// AsOf is set when the accounts are shown as they were at a past time
+	AsOf *time.Time `json:"asOf,omitempty"`
 }
 
 // Transaction represents a bank transaction
@@ -37,18 +67,52 @@ type Transaction struct {
 	Balance     Money      `json:"balance"`
 	Category    *string    `json:"category,omitempty" example:"Groceries"`
 	Merchant    *string    `json:"merchant,omitempty" example:"COLES SUPERMARKET"`
+
+	// MerchantDetails is filled in by merchant enrichment when the
+	// merchant behind the description is recognised
+	MerchantDetails *MerchantDetails `json:"merchantDetails,omitempty"`
+
+	// CategoryRuleID is the category rule that set Category, if one did
+	CategoryRuleID string `json:"categoryRuleId,omitempty" example:"rule_1f2e3d4c5b6a7988"`
+
+	// SearchText is the description normalised for searching and rule
+	// matching, filled in when the transaction is stored
+	SearchText string `json:"searchText,omitempty" example:"eftpos purchase coles supermarket"`
+
+	// Fingerprint identifies the transaction across scrapes by its date,
+	// amount, description and running balance, as NAB's IDs aren't stable.
+	// It is empty when there is no running balance.
+	Fingerprint string `json:"fingerprint,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015"`
+
+	// Type is the kind of transaction, inferred from its description when
+	// it is stored: one of the TransactionType constants, or empty for
+	// money in that isn't recognised
+	Type string `json:"type,omitempty" example:"purchase"`
+
+	// FeeType is the kind of fee a fee transaction is: one of the FeeType
+	// constants
+	FeeType string `json:"feeType,omitempty" example:"international"`
 }
 
 // AccountDetails extends Account with transaction information
 type AccountDetails struct {
 	Account
+	Loan                     *LoanDetails  `json:"loan,omitempty"`
+	Trend                    []BalanceTrendPoint `json:"trend,omitempty"`
 	Transactions             []Transaction `json:"transactions,omitempty"`
 	RecentTransactionCount   int           `json:"recentTransactionCount,omitempty" example:"10"`
+	SyncWarnings             []SyncWarning `json:"syncWarnings,omitempty"`
+
+	// Statement is the statement period the transactions were limited to
+	// with ?statement=
+	Statement *StatementPeriod `json:"statement,omitempty"`
 }
 
 // AccountDetailsResponse represents the response for getting account details
 type AccountDetailsResponse struct {
-	Account AccountDetails `json:"account"`
+	Account     AccountDetails `json:"account"`
+	RetrievedAt time.Time      `json:"retrievedAt"`
+	AsOf        *time.Time     `json:"asOf,omitempty"`
 }
 
 // ErrorResponse represents an API error response
@@ -61,18 +125,62 @@ type ErrorResponse struct {
 
 // AccountType constants
 const (
-	AccountTypeSavings    = "savings"
-	AccountTypeChecking   = "checking"
-	AccountTypeCredit     = "credit"
-	AccountTypeLoan       = "loan"
-	AccountTypeInvestment = "investment"
+	AccountTypeSavings     = "savings"
+	AccountTypeChecking    = "checking"
+	AccountTypeCredit      = "credit"
+	AccountTypeLoan        = "loan"
+	AccountTypeInvestment  = "investment"
+	AccountTypeTermDeposit = "term_deposit"
+)
+
+// TransactionType constants
+const (
+	TransactionTypePurchase     = "purchase"
+	TransactionTypeTransfer     = "transfer"
+	TransactionTypeDirectDebit  = "direct-debit"
+	TransactionTypeDirectCredit = "direct-credit"
+	TransactionTypeATM          = "atm"
+	TransactionTypeFee          = "fee"
+	TransactionTypeInterest     = "interest"
+	TransactionTypeBPAY         = "bpay"
+)
+
+// FeeType constants
+const (
+	FeeTypeAccountKeeping = "account-keeping"
+	FeeTypeInternational  = "international"
+	FeeTypeATM            = "atm"
+	FeeTypeOther          = "other"
 )
 
 // Error types
 const (
-	ErrorTypeAuthenticationFailed = "AUTHENTICATION_FAILED"
-	ErrorTypeAccountNotFound      = "ACCOUNT_NOT_FOUND"
-	ErrorTypeServiceUnavailable   = "SERVICE_UNAVAILABLE"
-	ErrorTypeInternalError        = "INTERNAL_ERROR"
-	ErrorTypeInvalidRequest       = "INVALID_REQUEST"
+	ErrorTypeAuthenticationFailed    = "AUTHENTICATION_FAILED"
+	ErrorTypeAccountNotFound         = "ACCOUNT_NOT_FOUND"
+	ErrorTypeTransactionNotFound     = "TRANSACTION_NOT_FOUND"
+	ErrorTypeServiceUnavailable      = "SERVICE_UNAVAILABLE"
+	ErrorTypeInternalError           = "INTERNAL_ERROR"
+	ErrorTypeInvalidRequest          = "INVALID_REQUEST"
+	ErrorTypeTransferRejected        = "TRANSFER_REJECTED"
+	ErrorTypeTermsAcceptanceRequired = "TERMS_ACCEPTANCE_REQUIRED"
+	ErrorTypeChallengeRequired       = "CHALLENGE_REQUIRED"
+	ErrorTypeHookNotFound            = "HOOK_NOT_FOUND"
+	ErrorTypeTokenNotFound           = "TOKEN_NOT_FOUND"
+	ErrorTypePayeeNotFound           = "PAYEE_NOT_FOUND"
+	ErrorTypePaymentNotFound         = "PAYMENT_NOT_FOUND"
+	ErrorTypePaymentRejected         = "PAYMENT_REJECTED"
+	ErrorTypeCardNotFound            = "CARD_NOT_FOUND"
+	ErrorTypeCardRejected            = "CARD_REJECTED"
+	ErrorTypeAnnotationNotFound      = "ANNOTATION_NOT_FOUND"
+	ErrorTypeJobNotFound             = "JOB_NOT_FOUND"
+	ErrorTypePreconditionFailed      = "PRECONDITION_FAILED"
+	ErrorTypeCategoryRuleNotFound    = "CATEGORY_RULE_NOT_FOUND"
+	ErrorTypeCategoryRuleExists      = "CATEGORY_RULE_EXISTS"
+	ErrorTypeBudgetNotFound          = "BUDGET_NOT_FOUND"
+	ErrorTypeSyncNotFound            = "SYNC_NOT_FOUND"
+	ErrorTypeNotAcceptable           = "NOT_ACCEPTABLE"
+	ErrorTypeTimeout                 = "TIMEOUT"
+	ErrorTypeFeatureDisabled         = "FEATURE_DISABLED"
+	ErrorTypeIdempotencyKeyReused    = "IDEMPOTENCY_KEY_REUSED"
+	ErrorTypeRequestInProgress       = "REQUEST_IN_PROGRESS"
 )
\ No newline at end of file
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// Annotation kinds
+const (
+	AnnotationKindTag  = "tag"
+	AnnotationKindNote = "note"
+)
+
+// Annotation is a tag or note attached locally to a stored transaction.
+// Deleted annotations sit in the trash, and can be restored, until PurgeAt.
+// Version goes up with every change and is returned as the ETag.
+type Annotation struct {
+	ID            string     `json:"id" example:"ann_1f2e3d4c5b6a7988"`
+	AccountID     string     `json:"accountId" example:"12345678"`
+	TransactionID string     `json:"transactionId" example:"txn_20231017_001"`
+	Kind          string     `json:"kind" example:"tag"`
+	Value         string     `json:"value" example:"tax-deductible"`
+	Version       int        `json:"version" example:"1"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	DeletedAt     *time.Time `json:"deletedAt,omitempty"`
+	PurgeAt       *time.Time `json:"purgeAt,omitempty"`
+}
+
+// AnnotationRequest is the body for annotating a transaction
+type AnnotationRequest struct {
+	Kind  string `json:"kind" example:"tag"`
+	Value string `json:"value" example:"tax-deductible"`
+}
+
+// AnnotationsResponse represents the response for listing annotations
+type AnnotationsResponse struct {
+	Annotations []Annotation `json:"annotations"`
+	Count       int          `json:"count" example:"1"`
+}
+
+// AnnotationTrashResponse lists deleted annotations that can still be
+// restored
+type AnnotationTrashResponse struct {
+	Annotations []Annotation `json:"annotations"`
+	Count       int          `json:"count" example:"1"`
+	Retention   string       `json:"retention" example:"720h0m0s"`
+}
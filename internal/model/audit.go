@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// Audit entry kinds
+const (
+	AuditKindAPICall  = "api_call"
+	AuditKindTransfer = "transfer"
+	AuditKindPayment  = "payment"
+)
+
+// Audit entry results
+const (
+	AuditResultSuccess = "success"
+	AuditResultFailure = "failure"
+)
+
+// AuditEntry records who did what and when, and how it turned out. API
+// calls carry the route and status; money-moving actions carry the
+// movement they attempted.
+type AuditEntry struct {
+	ID         string         `json:"id" example:"aud_5f2b9c1d7e3a4b60"`
+	Time       time.Time      `json:"time"`
+	Actor      string         `json:"actor" example:"tok_3f9a1c2b7d4e"`
+	Kind       string         `json:"kind" example:"transfer"`
+	Action     string         `json:"action" example:"POST /api/v1/transfers"`
+	Result     string         `json:"result" example:"success"`
+	Status     int            `json:"status,omitempty" example:"200"`
+	RemoteAddr string         `json:"remoteAddr,omitempty" example:"10.0.0.12"`
+	DurationMs int64          `json:"durationMs,omitempty" example:"8421"`
+	Movement   *AuditMovement `json:"movement,omitempty"`
+	Error      string         `json:"error,omitempty" example:"transfer rejected by NAB: insufficient funds"`
+}
+
+// AuditMovement is the money a transfer or payment moved, or tried to
+type AuditMovement struct {
+	FromAccountID string  `json:"fromAccountId,omitempty" example:"12345678"`
+	ToAccountID   string  `json:"toAccountId,omitempty" example:"87654321"`
+	PaymentID     string  `json:"paymentId,omitempty" example:"pay_8c1f2e3d4b5a6978"`
+	Payee         *Payee  `json:"payee,omitempty"`
+	Amount        string  `json:"amount,omitempty" example:"250.00"`
+	Description   string  `json:"description,omitempty" example:"Savings top up"`
+	Reference     string  `json:"reference,omitempty" example:"INV-1042"`
+	DryRun        bool    `json:"dryRun"`
+	Status        string  `json:"status,omitempty" example:"completed"`
+	ReceiptNumber *string `json:"receiptNumber,omitempty" example:"N1234567890"`
+}
+
+// AuditLogResponse is a page of audit entries, newest first
+type AuditLogResponse struct {
+	Entries []AuditEntry `json:"entries"`
+	Count   int          `json:"count" example:"1"`
+}
@@ -0,0 +1,107 @@
+package model
+
+// BasiqLinks are the links on a Basiq resource or list
+type BasiqLinks struct {
+	Self         string `json:"self" example:"/basiq/users/me/accounts/12345678"`
+	Next         string `json:"next,omitempty"`
+	Account      string `json:"account,omitempty"`
+	Transactions string `json:"transactions,omitempty"`
+}
+
+// BasiqAccountClass is a Basiq account's type and product name
+type BasiqAccountClass struct {
+	Type    string `json:"type" example:"transaction"`
+	Product string `json:"product" example:"NAB Classic Banking"`
+}
+
+// BasiqAccount is an account in the shape of Basiq's accounts API
+type BasiqAccount struct {
+	Type           string            `json:"type" example:"account"`
+	ID             string            `json:"id" example:"12345678"`
+	AccountNo      string            `json:"accountNo" example:"084001 ****1234"`
+	Name           string            `json:"name" example:"NAB Classic Banking"`
+	Currency       string            `json:"currency" example:"AUD"`
+	Balance        string            `json:"balance" example:"2543.67"`
+	AvailableFunds string            `json:"availableFunds" example:"2543.67"`
+	LastUpdated    string            `json:"lastUpdated,omitempty" example:"2024-05-17T08:30:00Z"`
+	Status         string            `json:"status" example:"available"`
+	Institution    string            `json:"institution" example:"nab"`
+	Connection     string            `json:"connection" example:"nab"`
+	Class          BasiqAccountClass `json:"class"`
+	Links          BasiqLinks        `json:"links"`
+}
+
+// BasiqSubClass is a Basiq transaction's category
+type BasiqSubClass struct {
+	Title string `json:"title" example:"Groceries"`
+}
+
+// BasiqMerchant is the business behind a Basiq transaction
+type BasiqMerchant struct {
+	BusinessName string `json:"businessName" example:"Coles"`
+	Website      string `json:"website,omitempty" example:"coles.com.au"`
+}
+
+// BasiqEnrich is a Basiq transaction's enrichment data
+type BasiqEnrich struct {
+	Merchant BasiqMerchant `json:"merchant"`
+}
+
+// BasiqTransaction is a transaction in the shape of Basiq's transactions
+// API
+type BasiqTransaction struct {
+	Type            string         `json:"type" example:"transaction"`
+	ID              string         `json:"id" example:"12345678.txn_20240517_001"`
+	Status          string         `json:"status" example:"posted"`
+	Description     string         `json:"description" example:"EFTPOS Purchase - COLES SUPERMARKET"`
+	Amount          string         `json:"amount" example:"-85.67"`
+	Account         string         `json:"account" example:"12345678"`
+	Balance         string         `json:"balance" example:"2457.99"`
+	Direction       string         `json:"direction" example:"debit"`
+	Class           string         `json:"class" example:"payment"`
+	Institution     string         `json:"institution" example:"nab"`
+	Connection      string         `json:"connection" example:"nab"`
+	TransactionDate string         `json:"transactionDate" example:"2024-05-17"`
+	PostDate        string         `json:"postDate" example:"2024-05-17"`
+	SubClass        *BasiqSubClass `json:"subClass,omitempty"`
+	Enrich          *BasiqEnrich   `json:"enrich,omitempty"`
+	Links           BasiqLinks     `json:"links"`
+}
+
+// BasiqAccountList is a list of accounts in Basiq's list envelope
+type BasiqAccountList struct {
+	Type  string         `json:"type" example:"list"`
+	Count int            `json:"count" example:"4"`
+	Data  []BasiqAccount `json:"data"`
+	Links BasiqLinks     `json:"links"`
+}
+
+// BasiqTransactionList is a page of transactions in Basiq's list envelope
+type BasiqTransactionList struct {
+	Type  string             `json:"type" example:"list"`
+	Count int                `json:"count" example:"500"`
+	Size  int                `json:"size" example:"1250"`
+	Data  []BasiqTransaction `json:"data"`
+	Links BasiqLinks         `json:"links"`
+}
+
+// BasiqToken is the access token Basiq's token endpoint returns
+type BasiqToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type" example:"Bearer"`
+	ExpiresIn   int    `json:"expires_in" example:"3600"`
+}
+
+// BasiqError is one error in a Basiq error response
+type BasiqError struct {
+	Type   string `json:"type" example:"error"`
+	Code   string `json:"code" example:"resource-not-found"`
+	Title  string `json:"title" example:"Resource not found."`
+	Detail string `json:"detail" example:"Account 12345678 not found."`
+}
+
+// BasiqErrorResponse is Basiq's error envelope
+type BasiqErrorResponse struct {
+	Type string       `json:"type" example:"list"`
+	Data []BasiqError `json:"data"`
+}
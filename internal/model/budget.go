@@ -0,0 +1,69 @@
+package model
+
+import "time"
+
+// Budget periods
+const (
+	BudgetPeriodWeekly  = "weekly"
+	BudgetPeriodMonthly = "monthly"
+	BudgetPeriodYearly  = "yearly"
+)
+
+// Budget statuses, in increasing order of concern
+const (
+	BudgetStatusOK       = "ok"
+	BudgetStatusWarning  = "warning"
+	BudgetStatusExceeded = "exceeded"
+)
+
+// Budget caps spending in a category each period. An alert is sent the
+// first time in a period that spending reaches AlertPercent of the limit,
+// and again if it goes over. Version goes up with every change and is
+// returned as the ETag.
+type Budget struct {
+	ID           string       `json:"id" example:"bud_1f2e3d4c5b6a7988"`
+	Category     string       `json:"category" example:"Groceries"`
+	Period       string       `json:"period" example:"monthly"`
+	Limit        Money        `json:"limit"`
+	AlertPercent int          `json:"alertPercent" example:"80"`
+	LastAlert    *BudgetAlert `json:"lastAlert,omitempty"`
+	Version      int          `json:"version" example:"1"`
+	CreatedAt    time.Time    `json:"createdAt"`
+	UpdatedAt    time.Time    `json:"updatedAt"`
+}
+
+// BudgetAlert records the last alert sent for a budget, so each is only
+// sent once a period
+type BudgetAlert struct {
+	PeriodStart string    `json:"periodStart" example:"2023-10-01"`
+	Status      string    `json:"status" example:"warning"`
+	SentAt      time.Time `json:"sentAt"`
+}
+
+// BudgetRequest is the body for creating or replacing a budget. The alert
+// percentage defaults to 80.
+type BudgetRequest struct {
+	Category     string `json:"category" example:"Groceries"`
+	Period       string `json:"period" example:"monthly"`
+	Limit        string `json:"limit" example:"800.00"`
+	AlertPercent *int   `json:"alertPercent,omitempty" example:"80"`
+}
+
+// BudgetProgress is a budget with its spending in the current period.
+// Spending is what left the category's transactions less refunds into it.
+type BudgetProgress struct {
+	Budget
+	PeriodStart string  `json:"periodStart" example:"2023-10-01"`
+	PeriodEnd   string  `json:"periodEnd" example:"2023-10-31"`
+	Spent       Money   `json:"spent"`
+	Remaining   Money   `json:"remaining"`
+	PercentUsed float64 `json:"percentUsed" example:"64.5"`
+	Status      string  `json:"status" example:"ok"`
+}
+
+// BudgetsResponse represents the response for listing budgets
+type BudgetsResponse struct {
+	Budgets     []BudgetProgress `json:"budgets"`
+	Count       int              `json:"count" example:"1"`
+	RetrievedAt time.Time        `json:"retrievedAt"`
+}
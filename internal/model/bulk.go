@@ -0,0 +1,39 @@
+package model
+
+// Bulk operation types
+const (
+	BulkOpTag          = "tag"
+	BulkOpRecategorize = "recategorize"
+	BulkOpArchive      = "archive"
+	BulkOpUnarchive    = "unarchive"
+)
+
+// BulkOperation is one operation in a bulk request. Tagging needs the
+// account, transaction and tag; recategorising needs the merchant and
+// category; archiving needs the account.
+type BulkOperation struct {
+	Op            string `json:"op" example:"tag"`
+	AccountID     string `json:"accountId,omitempty" example:"12345678"`
+	TransactionID string `json:"transactionId,omitempty" example:"txn_20231017_001"`
+	Tag           string `json:"tag,omitempty" example:"tax-deductible"`
+	Merchant      string `json:"merchant,omitempty" example:"COLES"`
+	Category      string `json:"category,omitempty" example:"Groceries"`
+}
+
+// BulkRequest is a batch of operations processed as a background job
+type BulkRequest struct {
+	Operations []BulkOperation `json:"operations"`
+}
+
+// RecategorizeResult is the outcome of recategorising a merchant
+type RecategorizeResult struct {
+	Merchant string `json:"merchant" example:"COLES"`
+	Category string `json:"category" example:"Groceries"`
+	Updated  int    `json:"updated" example:"42"`
+}
+
+// ArchiveResult is the outcome of archiving or unarchiving an account
+type ArchiveResult struct {
+	AccountID string `json:"accountId" example:"12345678"`
+	Archived  bool   `json:"archived" example:"true"`
+}
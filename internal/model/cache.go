@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+// Cache entry kinds
+const (
+	CacheEntryKindStored = "stored"
+	CacheEntryKindShared = "shared"
+)
+
+// Cache entry states
+const (
+	CacheEntryStateFresh   = "fresh"
+	CacheEntryStateStale   = "stale"
+	CacheEntryStateExpired = "expired"
+)
+
+// CacheEntry describes a cached entry. Stored entries are scrapes kept in
+// the store and served instead of scraping while fresh or stale; shared
+// entries are scrapes shared with other instances through CACHE_URL.
+type CacheEntry struct {
+	Key        string     `json:"key" example:"account:123456789"`
+	Kind       string     `json:"kind" example:"stored"`
+	State      string     `json:"state" example:"fresh"`
+	ScrapedAt  *time.Time `json:"scrapedAt,omitempty"`
+	AgeSeconds int64      `json:"ageSeconds,omitempty" example:"42"`
+	SizeBytes  int        `json:"sizeBytes" example:"18342"`
+	Hits       int64      `json:"hits" example:"12"`
+	StaleHits  int64      `json:"staleHits" example:"3"`
+	Misses     int64      `json:"misses" example:"1"`
+}
+
+// CacheStatsResponse reports how the cache is configured, how requests
+// were served since startup and what is cached now
+type CacheStatsResponse struct {
+	TTLSeconds      int64        `json:"ttlSeconds" example:"300"`
+	MaxStaleSeconds int64        `json:"maxStaleSeconds" example:"3600"`
+	SharedBackend   bool         `json:"sharedBackend" example:"false"`
+	Hits            int64        `json:"hits" example:"40"`
+	StaleHits       int64        `json:"staleHits" example:"6"`
+	Misses          int64        `json:"misses" example:"9"`
+	HitRatio        float64      `json:"hitRatio" example:"0.836"`
+	ClearedAt       *time.Time   `json:"clearedAt,omitempty"`
+	Entries         []CacheEntry `json:"entries"`
+	Timestamp       time.Time    `json:"timestamp"`
+}
+
+// CacheEvictResponse reports the entries evicted from the cache. Evicting
+// a stored entry also removes the shared entries scraped along with it.
+type CacheEvictResponse struct {
+	Evicted   []string  `json:"evicted" example:"account:123456789,scrape:transactions:123456789:"`
+	NotFound  []string  `json:"notFound" example:"account:999"`
+	EvictedAt time.Time `json:"evictedAt"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+)
+
+// Card types
+const (
+	CardTypeDebit  = "debit"
+	CardTypeCredit = "credit"
+)
+
+// Card statuses. A locked card has NAB's temporary block applied and can be
+// unlocked again.
+const (
+	CardStatusActive    = "active"
+	CardStatusLocked    = "locked"
+	CardStatusCancelled = "cancelled"
+)
+
+// Card is a debit or credit card issued on the customer's accounts
+type Card struct {
+	ID         string  `json:"id" example:"card_6d2f8a1b4c9e3d70"`
+	Name       string  `json:"name" example:"NAB Visa Debit"`
+	Type       string  `json:"type" example:"debit"`
+	Last4      string  `json:"last4" example:"4821"`
+	Cardholder *string `json:"cardholder,omitempty" example:"J SMITH"`
+	AccountID  *string `json:"accountId,omitempty" example:"87654321"`
+	Expiry     *string `json:"expiry,omitempty" example:"09/27"`
+	Status     string  `json:"status" example:"active"`
+}
+
+// CardsResponse represents the response for listing cards
+type CardsResponse struct {
+	Cards       []Card    `json:"cards"`
+	RetrievedAt time.Time `json:"retrievedAt"`
+	Count       int       `json:"count" example:"2"`
+}
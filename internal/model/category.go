@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// CategoryRule files stored and future transactions whose merchant or
+// description matches Pattern under Category. When several rules match a
+// transaction, the one with the longest pattern wins. Version goes up with
+// every change and is returned as the ETag.
+type CategoryRule struct {
+	ID        string    `json:"id" example:"rule_1f2e3d4c5b6a7988"`
+	Pattern   string    `json:"pattern" example:"coles"`
+	Category  string    `json:"category" example:"Groceries"`
+	Version   int       `json:"version" example:"1"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CategoryRuleRequest is the body for creating or replacing a category
+// rule
+type CategoryRuleRequest struct {
+	Pattern  string `json:"pattern" example:"coles"`
+	Category string `json:"category" example:"Groceries"`
+}
+
+// CategoryRuleResponse is a category rule that was just changed, with how
+// many stored transactions changed category as a result
+type CategoryRuleResponse struct {
+	Rule          CategoryRule `json:"rule"`
+	Recategorized int          `json:"recategorized" example:"42"`
+}
+
+// CategoryRulesResponse represents the response for listing category rules
+type CategoryRulesResponse struct {
+	Rules []CategoryRule `json:"rules"`
+	Count int            `json:"count" example:"1"`
+}
+
+// Category is a category used by rules or stored transactions
+type Category struct {
+	Name         string `json:"name" example:"Groceries"`
+	Rules        int    `json:"rules" example:"2"`
+	Transactions int    `json:"transactions" example:"118"`
+}
+
+// CategoriesResponse represents the response for listing categories
+type CategoriesResponse struct {
+	Categories []Category `json:"categories"`
+	Count      int        `json:"count" example:"1"`
+}
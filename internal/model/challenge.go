@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// Security challenge kinds
+const (
+	ChallengeKindCaptcha       = "captcha"
+	ChallengeKindSecurityCheck = "security_check"
+)
+
+// Challenge is a captcha or security check NAB showed in place of the page
+// a scrape expected
+type Challenge struct {
+	Kind       string    `json:"kind" example:"captcha"`
+	URL        string    `json:"url" example:"https://ib.nab.com.au/nabib/index.jsp"`
+	Screenshot string    `json:"screenshot,omitempty" example:"/app/screenshots/nab_challenge_20240517_093000.png"`
+	DetectedAt time.Time `json:"detectedAt"`
+}
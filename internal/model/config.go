@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+// ConfigReloadResponse lists the settings applied by a configuration
+// reload. Anything else in the configuration needs a restart to change.
+type ConfigReloadResponse struct {
+	Reloaded   []string  `json:"reloaded" example:"selectors,cache,alerts,notifications"`
+	ReloadedAt time.Time `json:"reloadedAt"`
+}
@@ -0,0 +1,20 @@
+package model
+
+// CreditDetails holds credit card specific account information
+type CreditDetails struct {
+	CreditLimit      Money   `json:"creditLimit"`
+	AvailableCredit  Money   `json:"availableCredit"`
+	StatementBalance *Money  `json:"statementBalance,omitempty"`
+	MinimumPayment   *Money  `json:"minimumPayment,omitempty"`
+	PaymentDueDate   *string `json:"paymentDueDate,omitempty" example:"2023-11-05"`
+
+	// StatementPeriod is the statement currently open, when NAB shows it
+	StatementPeriod *StatementPeriod `json:"statementPeriod,omitempty"`
+}
+
+// StatementPeriod is the span of a credit card statement, from the day it
+// opens to the day it closes
+type StatementPeriod struct {
+	Start string `json:"start" example:"2023-10-16"`
+	End   string `json:"end" example:"2023-11-15"`
+}
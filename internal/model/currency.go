@@ -0,0 +1,35 @@
+package model
+
+import "encoding/json"
+
+// DefaultCurrency is the currency of amounts that don't name one
+const DefaultCurrency = "AUD"
+
+// CurrencyCode returns the money's currency, AUD if none is set
+func (m Money) CurrencyCode() string {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+// money has Money's fields without its JSON methods
+type money Money
+
+// MarshalJSON writes the money with its currency, AUD if none is set
+func (m Money) MarshalJSON() ([]byte, error) {
+	m.Currency = m.CurrencyCode()
+	return json.Marshal(money(m))
+}
+
+// UnmarshalJSON reads money, taking amounts without a currency, such as
+// those stored before currencies were recorded, as AUD
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var decoded money
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*m = Money(decoded)
+	m.Currency = m.CurrencyCode()
+	return nil
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// DashboardAccount is an account with its most recent stored transactions
+type DashboardAccount struct {
+	Account            Account       `json:"account"`
+	RecentTransactions []Transaction `json:"recentTransactions"`
+}
+
+// DashboardTotal is the combined balance of every account of one type
+// held in one currency
+type DashboardTotal struct {
+	Type     string `json:"type" example:"savings"`
+	Balance  Money  `json:"balance"`
+	Accounts int    `json:"accounts" example:"2"`
+}
+
+// DashboardSync summarises the most recent sync
+type DashboardSync struct {
+	Status        string     `json:"status" example:"completed"`
+	StartedAt     time.Time  `json:"startedAt"`
+	CompletedAt   *time.Time `json:"completedAt,omitempty"`
+	AccountsFound int        `json:"accountsFound" example:"4"`
+	Failed        int        `json:"failed" example:"0"`
+}
+
+// DashboardResponse is everything a home screen shows in one payload:
+// the accounts with their latest transactions, balances totalled by
+// account type, and the last sync
+type DashboardResponse struct {
+	Accounts    []DashboardAccount `json:"accounts"`
+	Totals      []DashboardTotal   `json:"totals"`
+	LastSync    *DashboardSync     `json:"lastSync,omitempty"`
+	RetrievedAt time.Time          `json:"retrievedAt"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"sync"
+	"time"
+)
+
+// BankTimezone is the time zone NAB's dates are in
+const BankTimezone = "Australia/Sydney"
+
+var (
+	bankLocationOnce sync.Once
+	bankLocation     *time.Location
+)
+
+// BankLocation returns NAB's time zone. Without time zone data it falls
+// back to AEST, which is out by an hour during daylight saving.
+func BankLocation() *time.Location {
+	bankLocationOnce.Do(func() {
+		location, err := time.LoadLocation(BankTimezone)
+		if err != nil {
+			location = time.FixedZone("AEST", 10*60*60)
+		}
+		bankLocation = location
+	})
+	return bankLocation
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+)
+
+// Direct debit statuses
+const (
+	DirectDebitStatusActive    = "active"
+	DirectDebitStatusCancelled = "cancelled"
+)
+
+// DirectDebit is a direct debit authority letting a merchant draw money
+// from an account
+type DirectDebit struct {
+	ID            string  `json:"id" example:"dd_4b7e1c9a0f3d2e58"`
+	Merchant      string  `json:"merchant" example:"AGL SALES PTY LTD"`
+	UserID        *string `json:"userId,omitempty" example:"123456"`
+	Reference     *string `json:"reference,omitempty" example:"2000123456"`
+	LastAmount    *Money  `json:"lastAmount,omitempty"`
+	LastDebitDate *string `json:"lastDebitDate,omitempty" example:"2023-10-12"`
+	Status        string  `json:"status" example:"active"`
+}
+
+// DirectDebitsResponse represents the response for listing an account's
+// direct debit authorities
+type DirectDebitsResponse struct {
+	AccountID    string        `json:"accountId" example:"87654321"`
+	DirectDebits []DirectDebit `json:"directDebits"`
+	RetrievedAt  time.Time     `json:"retrievedAt"`
+	Count        int           `json:"count" example:"2"`
+}
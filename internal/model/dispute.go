@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+)
+
+// DisputeRequest represents a request to prepare a transaction dispute
+type DisputeRequest struct {
+	Reason   string `json:"reason,omitempty" example:"I did not authorise this transaction"`
+	Navigate bool   `json:"navigate,omitempty"`
+}
+
+// DisputeSummary gathers the details NAB's dispute form asks for
+type DisputeSummary struct {
+	AccountID     string             `json:"accountId" example:"12345678"`
+	AccountName   string             `json:"accountName" example:"Complete Access Account"`
+	CardNumber    *string            `json:"cardNumber,omitempty" example:"****5678"`
+	TransactionID string             `json:"transactionId" example:"txn_20231017_001"`
+	Date          string             `json:"date" example:"2023-10-17"`
+	Merchant      string             `json:"merchant" example:"COLES SUPERMARKET"`
+	Description   string             `json:"description" example:"EFTPOS Purchase - COLES SUPERMARKET"`
+	Amount        Money              `json:"amount"`
+	Reason        string             `json:"reason,omitempty" example:"I did not authorise this transaction"`
+	Document      string             `json:"document"`
+	Form          *DisputeFormResult `json:"form,omitempty"`
+	GeneratedAt   time.Time          `json:"generatedAt"`
+}
+
+// DisputeFormResult reports a dry-run fill of NAB's dispute form. The form
+// is never submitted.
+type DisputeFormResult struct {
+	Reached      bool     `json:"reached"`
+	FieldsFilled []string `json:"fieldsFilled"`
+	Submitted    bool     `json:"submitted"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// ErrorBundle is what the browser was showing when a scrape failed, saved
+// to a directory of its own so the failure can be diagnosed
+type ErrorBundle struct {
+	Path       string    `json:"path" example:"/app/screenshots/nab_error_20240517_093000"`
+	URL        string    `json:"url" example:"https://ib.nab.com.au/nabib/index.jsp"`
+	Title      string    `json:"title" example:"NAB Internet Banking"`
+	Screenshot string    `json:"screenshot,omitempty" example:"/app/screenshots/nab_error_20240517_093000/screenshot.png"`
+	HTML       string    `json:"html,omitempty" example:"/app/screenshots/nab_error_20240517_093000/page.html"`
+	ConsoleLog string    `json:"consoleLog,omitempty" example:"/app/screenshots/nab_error_20240517_093000/console.log"`
+	CapturedAt time.Time `json:"capturedAt"`
+}
+
+// ScrapeErrorDetails are the details of an error response for a failed
+// scrape that saved an error bundle
+type ScrapeErrorDetails struct {
+	Error       string      `json:"error" example:"could not find login button"`
+	ErrorBundle ErrorBundle `json:"errorBundle"`
+}
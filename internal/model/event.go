@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// Event types streamed to clients
+const (
+	EventSyncStarted    = "sync.started"
+	EventSyncStep       = "sync.step"
+	EventTransactionNew = "transaction.new"
+	EventSyncCompleted  = "sync.completed"
+)
+
+// Sync steps reported by sync.step events
+const (
+	SyncStepListingAccounts = "listing_accounts"
+	SyncStepScrapingAccount = "scraping_account"
+)
+
+// Event is something that happened during a sync, streamed to clients as
+// it happens
+type Event struct {
+	ID   int64       `json:"id" example:"42"`
+	Type string      `json:"type" example:"sync.step"`
+	Data interface{} `json:"data"`
+	Time time.Time   `json:"time"`
+}
+
+// SyncEvent describes the progress of one sync. Every event from the same
+// sync carries its ID.
+type SyncEvent struct {
+	SyncID     string `json:"syncId" example:"job_1f2e3d4c5b6a7988"`
+	Trigger    string `json:"trigger" example:"api"`
+	Step       string `json:"step,omitempty" example:"scraping_account"`
+	AccountID  string `json:"accountId,omitempty" example:"12345678"`
+	Accounts   int    `json:"accounts,omitempty" example:"4"`
+	Failed     int    `json:"failed,omitempty" example:"0"`
+	Error      string `json:"error,omitempty" example:"failed to scrape NAB accounts: login timed out"`
+	DurationMs int64  `json:"durationMs,omitempty" example:"48210"`
+	// Errors lists the accounts that failed, on sync.completed
+	Errors []SyncAccountError `json:"errors,omitempty"`
+}
+
+// SyncAccountError is an account that failed to sync
+type SyncAccountError struct {
+	AccountID string `json:"accountId" example:"87654321"`
+	Error     string `json:"error" example:"failed to scrape NAB accounts: context deadline exceeded"`
+}
+
+// TransactionEvent is a transaction seen for the first time
+type TransactionEvent struct {
+	AccountID   string      `json:"accountId" example:"12345678"`
+	Transaction Transaction `json:"transaction"`
+}
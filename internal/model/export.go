@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+)
+
+// ExportFile describes a single file written by an export
+type ExportFile struct {
+	Name     string `json:"name" example:"transactions.parquet"`
+	Location string `json:"location" example:"s3://my-bucket/nab/transactions.parquet"`
+	Rows     int    `json:"rows" example:"1542"`
+}
+
+// ExportResult represents the response for an export run
+type ExportResult struct {
+	Format     string       `json:"format" example:"parquet"`
+	Redaction  string       `json:"redaction" example:"hash"`
+	Files      []ExportFile `json:"files"`
+	ExportedAt time.Time    `json:"exportedAt"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// FeedAccount links an account to its counterpart in an external finance
+// app that transactions are pushed to
+type FeedAccount struct {
+	RemoteID string `json:"remoteId" example:"42"`
+
+	// Since is the first transaction date pushed. Accounts the feed
+	// created get their whole stored history; existing accounts that were
+	// linked only get transactions from the day they were linked.
+	Since    string    `json:"since" example:"2024-05-01"`
+	LinkedAt time.Time `json:"linkedAt"`
+}
+
+// FeedState records what a feed has pushed to an external finance app, so
+// each sync only sends what is new
+type FeedState struct {
+	// Accounts are keyed by account ID
+	Accounts map[string]FeedAccount `json:"accounts,omitempty"`
+
+	// Pushed maps account ID and transaction ID, joined by a slash, to
+	// the ID the app gave the transaction
+	Pushed map[string]string `json:"pushed,omitempty"`
+
+	LastPushAt *time.Time `json:"lastPushAt,omitempty"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+// SavingsGoal is a goal set on a savings account, such as an iSaver goal,
+// with how much has been put towards it
+type SavingsGoal struct {
+	ID              string   `json:"id" example:"goal_4b7e1c9a0f3d2e58"`
+	Name            string   `json:"name" example:"Japan trip"`
+	TargetAmount    *Money   `json:"targetAmount,omitempty"`
+	SavedAmount     Money    `json:"savedAmount"`
+	TargetDate      *string  `json:"targetDate,omitempty" example:"2024-12-01"`
+	PercentComplete *float64 `json:"percentComplete,omitempty" example:"42.5"`
+}
+
+// SavingsGoalsResponse represents the response for listing an account's
+// savings goals
+type SavingsGoalsResponse struct {
+	AccountID   string        `json:"accountId" example:"11223344"`
+	Goals       []SavingsGoal `json:"goals"`
+	RetrievedAt time.Time     `json:"retrievedAt"`
+	Count       int           `json:"count" example:"2"`
+}
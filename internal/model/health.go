@@ -0,0 +1,113 @@
+package model
+
+import (
+	"time"
+)
+
+// Warm-up states
+const (
+	WarmUpStateDisabled = "disabled"
+	WarmUpStatePending  = "pending"
+	WarmUpStateRunning  = "running"
+	WarmUpStateReady    = "ready"
+	WarmUpStateFailed   = "failed"
+)
+
+// Readiness statuses
+const (
+	ReadinessReady     = "ready"
+	ReadinessWarmingUp = "warming_up"
+	ReadinessDegraded  = "degraded"
+)
+
+// WarmUpStatus reports the startup login that saves the first API call
+// from paying the browser cold-start cost
+type WarmUpStatus struct {
+	State       string     `json:"state" example:"ready"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	DurationMs  int64      `json:"durationMs,omitempty" example:"41250"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// ReadinessResponse represents the response for the readiness check
+type ReadinessResponse struct {
+	Status    string       `json:"status" example:"ready"`
+	WarmUp    WarmUpStatus `json:"warmUp"`
+	Timestamp time.Time    `json:"timestamp"`
+}
+
+// Detailed health statuses
+const (
+	HealthStatusHealthy  = "healthy"
+	HealthStatusDegraded = "degraded"
+	HealthStatusBroken   = "broken"
+)
+
+// ScrapeHealth reports recent outcomes of scraping accounts from NAB
+type ScrapeHealth struct {
+	LastSuccessAt        *time.Time `json:"lastSuccessAt,omitempty"`
+	LastFailureAt        *time.Time `json:"lastFailureAt,omitempty"`
+	LastError            string     `json:"lastError,omitempty"`
+	ConsecutiveFailures  int        `json:"consecutiveFailures" example:"0"`
+	AuthenticationFailed bool       `json:"authenticationFailed"`
+	CircuitOpen          bool       `json:"circuitOpen"`
+	CircuitOpenUntil     *time.Time `json:"circuitOpenUntil,omitempty"`
+	BalanceGaps          int        `json:"balanceGaps" example:"0"`
+	LastBalanceGapAt     *time.Time `json:"lastBalanceGapAt,omitempty"`
+}
+
+// BrowserHealth reports the NAB login session and the browsers the client
+// is running
+type BrowserHealth struct {
+	ActiveBrowsers   int        `json:"activeBrowsers" example:"0"`
+	MaxBrowsers      int        `json:"maxBrowsers,omitempty" example:"1"`
+	LoggedIn         bool       `json:"loggedIn"`
+	LastLoginAt      *time.Time `json:"lastLoginAt,omitempty"`
+	LastLoginError   string     `json:"lastLoginError,omitempty"`
+	DeviceProfile    string     `json:"deviceProfile,omitempty" example:"windows-chrome"`
+	PersistedSession bool       `json:"persistedSession"`
+	SessionSavedAt   *time.Time `json:"sessionSavedAt,omitempty"`
+	PausedUntil      *time.Time `json:"pausedUntil,omitempty"`
+}
+
+// CacheHealth reports how fresh the stored accounts are
+type CacheHealth struct {
+	Accounts      int        `json:"accounts" example:"3"`
+	LastUpdatedAt *time.Time `json:"lastUpdatedAt,omitempty"`
+	AgeSeconds    *int64     `json:"ageSeconds,omitempty" example:"540"`
+}
+
+// DetailedHealthResponse represents the response for the detailed health
+// check
+type DetailedHealthResponse struct {
+	Status    string         `json:"status" example:"healthy"`
+	Problems  []string       `json:"problems,omitempty"`
+	Scrape    *ScrapeHealth  `json:"scrape,omitempty"`
+	Browser   *BrowserHealth `json:"browser,omitempty"`
+	Cache     CacheHealth    `json:"cache"`
+	WarmUp    WarmUpStatus   `json:"warmUp"`
+	Timestamp time.Time      `json:"timestamp"`
+}
+
+// Probe statuses, used for the probe and each of its checks
+const (
+	ProbeStatusOK      = "ok"
+	ProbeStatusFailed  = "failed"
+	ProbeStatusSkipped = "skipped"
+)
+
+// ProbeCheck is one check made by a readiness probe
+type ProbeCheck struct {
+	Name   string `json:"name" example:"browser"`
+	Status string `json:"status" example:"ok"`
+	Detail string `json:"detail,omitempty" example:"/usr/bin/chromium-browser"`
+}
+
+// ProbeResponse represents the response for the liveness and readiness
+// probes
+type ProbeResponse struct {
+	Status    string       `json:"status" example:"ok"`
+	Checks    []ProbeCheck `json:"checks,omitempty"`
+	Timestamp time.Time    `json:"timestamp"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+)
+
+// BalanceSnapshot records an account balance at a point in time
+type BalanceSnapshot struct {
+	AccountID        string    `json:"accountId" example:"12345678"`
+	Balance          Money     `json:"balance"`
+	AvailableBalance *Money    `json:"availableBalance,omitempty"`
+	RecordedAt       time.Time `json:"recordedAt"`
+}
+
+// BalanceTrendPoint is an account's closing balance on a day, as used for
+// sparklines
+type BalanceTrendPoint struct {
+	Date    string `json:"date" example:"2023-10-17"`
+	Balance string `json:"balance" example:"1234.56"`
+}
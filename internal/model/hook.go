@@ -0,0 +1,70 @@
+package model
+
+import (
+	"time"
+)
+
+// Refresh hook phases
+const (
+	HookPhasePre  = "pre"
+	HookPhasePost = "post"
+)
+
+// Pre-scrape hook actions
+const (
+	HookActionProceed = "proceed"
+	HookActionSkip    = "skip"
+	HookActionDelay   = "delay"
+)
+
+// Refresh hook events sent in payloads
+const (
+	HookEventPreScrape  = "pre_scrape"
+	HookEventPostScrape = "post_scrape"
+)
+
+// RefreshHook is a webhook called around scheduled scrapes of an account.
+// Version is returned as the ETag.
+type RefreshHook struct {
+	ID        string    `json:"id" example:"hook_1f2e3d4c5b6a7988"`
+	AccountID string    `json:"accountId" example:"12345678"`
+	URL       string    `json:"url" example:"https://orchestrator.example.com/nab/refresh"`
+	Phases    []string  `json:"phases" example:"pre,post"`
+	Secret    string    `json:"secret,omitempty"`
+	Version   int       `json:"version" example:"1"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// RefreshHookRequest is the body for registering a refresh hook. Phases
+// default to both pre and post.
+type RefreshHookRequest struct {
+	URL    string   `json:"url" example:"https://orchestrator.example.com/nab/refresh"`
+	Phases []string `json:"phases,omitempty" example:"pre,post"`
+	Secret string   `json:"secret,omitempty" example:"s3cret"`
+}
+
+// RefreshHooksResponse represents the response for listing refresh hooks
+type RefreshHooksResponse struct {
+	Hooks []RefreshHook `json:"hooks"`
+	Count int           `json:"count" example:"1"`
+}
+
+// HookPayload is posted to refresh hooks. Post-scrape payloads carry the
+// outcome of the scrape.
+type HookPayload struct {
+	Event       string          `json:"event" example:"post_scrape"`
+	HookID      string          `json:"hookId" example:"hook_1f2e3d4c5b6a7988"`
+	AccountID   string          `json:"accountId" example:"12345678"`
+	ScheduledAt time.Time       `json:"scheduledAt"`
+	Success     *bool           `json:"success,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	Account     *AccountDetails `json:"account,omitempty"`
+	CompletedAt *time.Time      `json:"completedAt,omitempty"`
+}
+
+// HookDecision is a pre-scrape hook's reply. An empty or unreadable reply
+// means proceed.
+type HookDecision struct {
+	Action       string `json:"action" example:"delay"`
+	DelaySeconds int    `json:"delaySeconds,omitempty" example:"60"`
+}
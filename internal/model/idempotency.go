@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// IdempotentResponse is the saved response to a request made with an
+// Idempotency-Key header, replayed when the request is retried with the
+// same key. Key is scoped to the API key that made the request, and
+// Fingerprint identifies the request so a reused key can be told apart
+// from a retry.
+type IdempotentResponse struct {
+	Key         string    `json:"key"`
+	Fingerprint string    `json:"fingerprint"`
+	Status      int       `json:"status"`
+	ContentType string    `json:"contentType,omitempty"`
+	Body        []byte    `json:"body"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
@@ -0,0 +1,10 @@
+package model
+
+// InterestDetails holds interest information for savings accounts
+type InterestDetails struct {
+	Rate                    string  `json:"rate" example:"4.50"`
+	BaseRate                *string `json:"baseRate,omitempty" example:"0.10"`
+	BonusRate               *string `json:"bonusRate,omitempty" example:"4.40"`
+	EarnedThisFinancialYear *Money  `json:"earnedThisFinancialYear,omitempty"`
+	BonusQualified          *bool   `json:"bonusQualified,omitempty"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// JobStatus is the state of a background job
+type JobStatus string
+
+// Job states
+const (
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+)
+
+// Job item outcomes
+const (
+	JobItemSucceeded = "succeeded"
+	JobItemFailed    = "failed"
+)
+
+// JobItemResult is the outcome of one item of a background job
+type JobItemResult struct {
+	Index  int         `json:"index" example:"0"`
+	Status string      `json:"status" example:"succeeded"`
+	Error  string      `json:"error,omitempty" example:"transaction not found"`
+	Result interface{} `json:"result,omitempty"`
+}
+
+// Job is a batch of work processed in the background. Items are filled in
+// as they finish, so a running job shows its progress.
+type Job struct {
+	ID          string          `json:"id" example:"job_1f2e3d4c5b6a7988"`
+	Type        string          `json:"type" example:"bulk"`
+	Status      JobStatus       `json:"status" example:"running"`
+	Total       int             `json:"total" example:"500"`
+	Succeeded   int             `json:"succeeded" example:"120"`
+	Failed      int             `json:"failed" example:"2"`
+	Items       []JobItemResult `json:"items"`
+	Error       string          `json:"error,omitempty" example:"failed to scrape NAB accounts: login timed out"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	CompletedAt *time.Time      `json:"completedAt,omitempty"`
+}
@@ -0,0 +1,18 @@
+package model
+
+// Repayment frequencies
+const (
+	RepaymentFrequencyWeekly      = "weekly"
+	RepaymentFrequencyFortnightly = "fortnightly"
+	RepaymentFrequencyMonthly     = "monthly"
+)
+
+// LoanDetails holds home loan specific account information
+type LoanDetails struct {
+	InterestRate       string  `json:"interestRate" example:"6.24"`
+	RepaymentAmount    *Money  `json:"repaymentAmount,omitempty"`
+	RepaymentFrequency string  `json:"repaymentFrequency,omitempty" example:"monthly"`
+	NextRepaymentDate  *string `json:"nextRepaymentDate,omitempty" example:"2023-11-01"`
+	RedrawAvailable    *Money  `json:"redrawAvailable,omitempty"`
+	OriginalAmount     *Money  `json:"originalAmount,omitempty"`
+}
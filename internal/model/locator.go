@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+)
+
+// Location types
+const (
+	LocationTypeATM    = "atm"
+	LocationTypeBranch = "branch"
+)
+
+// Location represents a NAB ATM or branch
+type Location struct {
+	ID         string  `json:"id" example:"ATM12345"`
+	Type       string  `json:"type" example:"atm"`
+	Name       string  `json:"name" example:"NAB ATM 500 Bourke Street"`
+	Address    string  `json:"address" example:"500 Bourke Street"`
+	Suburb     string  `json:"suburb" example:"Melbourne"`
+	State      string  `json:"state" example:"VIC"`
+	Postcode   string  `json:"postcode" example:"3000"`
+	Latitude   float64 `json:"latitude" example:"-37.8152"`
+	Longitude  float64 `json:"longitude" example:"144.9597"`
+	DistanceKm float64 `json:"distanceKm" example:"0.42"`
+	FeeFree    bool    `json:"feeFree"`
+}
+
+// LocationsResponse represents the response for a locator search
+type LocationsResponse struct {
+	Locations   []Location `json:"locations"`
+	Count       int        `json:"count" example:"10"`
+	Cached      bool       `json:"cached"`
+	RetrievedAt time.Time  `json:"retrievedAt"`
+}
@@ -0,0 +1,18 @@
+package model
+
+// MerchantDetails is the merchant behind a transaction, cleaned up from
+// its raw description
+type MerchantDetails struct {
+	// Name is the merchant's canonical name
+	Name string `json:"name" example:"Coles"`
+
+	// ID identifies the merchant for looking up logos and grouping
+	// spending, the same however NAB words its transactions
+	ID string `json:"id" example:"coles"`
+
+	Domain   *string `json:"domain,omitempty" example:"coles.com.au"`
+	Location *string `json:"location,omitempty" example:"Melbourne VIC"`
+
+	// Source is the enrichment provider that recognised the merchant
+	Source string `json:"source" example:"rules"`
+}
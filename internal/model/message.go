@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+)
+
+// Message represents a secure message in the NAB internet banking inbox
+type Message struct {
+	ID      string `json:"id" example:"msg_3f9a1c2b7d4e5f60"`
+	Subject string `json:"subject" example:"Changes to your account interest rate"`
+	Date    string `json:"date" example:"2023-10-17"`
+	Read    bool   `json:"read"`
+	Body    string `json:"body,omitempty" example:"From 1 November 2023 the variable interest rate on your account will change."`
+}
+
+// MessagesResponse represents the response for listing inbox messages
+type MessagesResponse struct {
+	Messages    []Message `json:"messages"`
+	RetrievedAt time.Time `json:"retrievedAt"`
+	Count       int       `json:"count" example:"5"`
+	Unread      int       `json:"unread" example:"1"`
+}
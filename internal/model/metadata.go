@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// AccountMetadata is what the user has attached to an account to tell it
+// apart, as NAB's account names are generic
+type AccountMetadata struct {
+	Nickname  *string   `json:"nickname,omitempty" example:"Holiday fund"`
+	Emoji     *string   `json:"emoji,omitempty" example:"🏖️"`
+	Colour    *string   `json:"colour,omitempty" example:"#2a9d8f"`
+	Notes     *string   `json:"notes,omitempty" example:"Japan trip, April"`
+	Version   int       `json:"version" example:"2"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// AccountMetadataRequest changes an account's metadata. Fields left out
+// are kept, and fields set to an empty string are cleared.
+type AccountMetadataRequest struct {
+	Nickname *string `json:"nickname,omitempty" example:"Holiday fund"`
+	Emoji    *string `json:"emoji,omitempty" example:"🏖️"`
+	Colour   *string `json:"colour,omitempty" example:"#2a9d8f"`
+	Notes    *string `json:"notes,omitempty" example:"Japan trip, April"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// DurationBucket counts the observations taking at most UpperBound
+// seconds, including those in smaller buckets
+type DurationBucket struct {
+	UpperBound float64 `json:"le" example:"5"`
+	Count      int64   `json:"count" example:"12"`
+}
+
+// ScrapeStepMetrics reports how long one named step of the NAB login and
+// scrape flow takes and how it last failed
+type ScrapeStepMetrics struct {
+	Step         string           `json:"step" example:"click_login"`
+	Count        int64            `json:"count" example:"14"`
+	Failures     int64            `json:"failures" example:"2"`
+	TotalSeconds float64          `json:"totalSeconds" example:"37.2"`
+	Buckets      []DurationBucket `json:"buckets"`
+	LastError    string           `json:"lastError,omitempty" example:"could not find login button"`
+	LastErrorAt  *time.Time       `json:"lastErrorAt,omitempty"`
+}
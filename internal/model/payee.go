@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// Payee represents a saved payee in the NAB internet banking address book
+type Payee struct {
+	ID            string  `json:"id" example:"payee_5b1c0e9a2f7d4c83"`
+	Name          string  `json:"name" example:"J SMITH"`
+	BSB           string  `json:"bsb" example:"062000"`
+	AccountNumber string  `json:"accountNumber" example:"12345678"`
+	Nickname      *string `json:"nickname,omitempty" example:"Rent"`
+}
+
+// PayeesResponse represents the response for listing saved payees
+type PayeesResponse struct {
+	Payees      []Payee   `json:"payees"`
+	RetrievedAt time.Time `json:"retrievedAt"`
+	Count       int       `json:"count" example:"2"`
+}
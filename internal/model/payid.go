@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+)
+
+// PayID types
+const (
+	PayIDTypeMobile = "mobile"
+	PayIDTypeEmail  = "email"
+	PayIDTypeABN    = "abn"
+)
+
+// PayID statuses
+const (
+	PayIDStatusActive       = "active"
+	PayIDStatusDisabled     = "disabled"
+	PayIDStatusTransferring = "transferring"
+)
+
+// PayID is a PayID registered with NAB and the account payments to it are
+// paid into
+type PayID struct {
+	ID          string  `json:"id" example:"payid_8f2a6c1e9b3d4a70"`
+	Type        string  `json:"type" example:"mobile"`
+	Value       string  `json:"value" example:"0412345678"`
+	DisplayName *string `json:"displayName,omitempty" example:"J SMITH"`
+	AccountID   *string `json:"accountId,omitempty" example:"87654321"`
+	Status      string  `json:"status" example:"active"`
+}
+
+// PayIDsResponse represents the response for listing registered PayIDs
+type PayIDsResponse struct {
+	PayIDs      []PayID   `json:"payIds"`
+	RetrievedAt time.Time `json:"retrievedAt"`
+	Count       int       `json:"count" example:"1"`
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"time"
+)
+
+// Payment statuses
+const (
+	PaymentStatusValidated   = "validated"
+	PaymentStatusPendingAuth = "pending_auth"
+	PaymentStatusCompleted   = "completed"
+)
+
+// NewPayee is a payee entered by BSB and account number rather than picked
+// from the address book
+type NewPayee struct {
+	Name          string `json:"name" example:"J CITIZEN"`
+	BSB           string `json:"bsb" example:"062000"`
+	AccountNumber string `json:"accountNumber" example:"12345678"`
+}
+
+// PayAnyoneRequest is the body for a Pay Anyone payment. Either payeeId
+// (a saved payee) or payee (a new BSB and account number) must be set.
+type PayAnyoneRequest struct {
+	FromAccountID string    `json:"fromAccountId" example:"12345678"`
+	PayeeID       string    `json:"payeeId,omitempty" example:"payee_001"`
+	Payee         *NewPayee `json:"payee,omitempty"`
+	Amount        string    `json:"amount" example:"120.00"`
+	Description   string    `json:"description,omitempty" example:"March rent"`
+	Reference     string    `json:"reference,omitempty" example:"INV-1042"`
+	DryRun        bool      `json:"dryRun,omitempty"`
+}
+
+// PaymentResult is the outcome of a Pay Anyone payment. Payments to new
+// payees wait in pending_auth until the SMS code NAB sends is submitted
+// before authExpiresAt.
+type PaymentResult struct {
+	ID            string         `json:"id" example:"pay_8c1f2e3d4b5a6978"`
+	Status        string         `json:"status" example:"completed"`
+	FromAccountID string         `json:"fromAccountId" example:"12345678"`
+	Payee         Payee          `json:"payee"`
+	Amount        Money          `json:"amount"`
+	Description   string         `json:"description,omitempty" example:"March rent"`
+	Reference     string         `json:"reference,omitempty" example:"INV-1042"`
+	ReceiptNumber *string        `json:"receiptNumber,omitempty" example:"N1234567890"`
+	DryRun        bool           `json:"dryRun"`
+	Review        *PaymentReview `json:"review,omitempty"`
+	AuthExpiresAt *time.Time     `json:"authExpiresAt,omitempty"`
+	ProcessedAt   time.Time      `json:"processedAt"`
+}
+
+// PaymentAuthRequest is the body for authorising a pending payment with
+// the one-time code NAB sent by SMS
+type PaymentAuthRequest struct {
+	Code string `json:"code" example:"123456"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+)
+
+// QueryRequest represents an ad-hoc SQL query request
+type QueryRequest struct {
+	SQL string `json:"sql" example:"SELECT category, sum(amount) FROM transactions GROUP BY 1"`
+}
+
+// QueryResponse represents the results of an ad-hoc SQL query
+type QueryResponse struct {
+	Rows       []map[string]interface{} `json:"rows"`
+	RowCount   int                      `json:"rowCount" example:"12"`
+	Truncated  bool                     `json:"truncated"`
+	ExecutedAt time.Time                `json:"executedAt"`
+}
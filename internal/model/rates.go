@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+)
+
+// AdvertisedRate is an interest rate published on a NAB product page
+type AdvertisedRate struct {
+	Page       string    `json:"page" example:"https://www.nab.com.au/personal/bank-accounts/savings-accounts"`
+	Product    string    `json:"product" example:"NAB Reward Saver bonus rate"`
+	Rate       string    `json:"rate" example:"4.85"`
+	ObservedAt time.Time `json:"observedAt"`
+}
+
+// RateChange records an advertised rate moving between two checks
+type RateChange struct {
+	Page     string    `json:"page"`
+	Product  string    `json:"product" example:"NAB Reward Saver bonus rate"`
+	OldRate  string    `json:"oldRate" example:"4.75"`
+	NewRate  string    `json:"newRate" example:"4.85"`
+	Observed time.Time `json:"observed"`
+}
+
+// AdvertisedRatesResponse represents the response for listing advertised rates
+type AdvertisedRatesResponse struct {
+	Rates       []AdvertisedRate `json:"rates"`
+	Count       int              `json:"count" example:"12"`
+	RetrievedAt time.Time        `json:"retrievedAt"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// BalanceAssertionStatus is the outcome of comparing an expected balance
+type BalanceAssertionStatus string
+
+// Balance assertion outcomes
+const (
+	BalanceAssertionMatched    BalanceAssertionStatus = "matched"
+	BalanceAssertionMismatched BalanceAssertionStatus = "mismatched"
+)
+
+// BalanceAssertionRequest is a balance an external system expects an
+// account to have
+type BalanceAssertionRequest struct {
+	ExpectedBalance string  `json:"expectedBalance" example:"2543.67"`
+	Tolerance       *string `json:"tolerance,omitempty" example:"0.50"`
+	Source          *string `json:"source,omitempty" example:"xero"`
+	Alert           bool    `json:"alert,omitempty"`
+}
+
+// BalanceAssertionResponse compares an expected balance with the latest
+// scraped one
+type BalanceAssertionResponse struct {
+	AccountID       string                 `json:"accountId" example:"12345678"`
+	Status          BalanceAssertionStatus `json:"status" example:"mismatched"`
+	ExpectedBalance Money                  `json:"expectedBalance"`
+	ActualBalance   Money                  `json:"actualBalance"`
+	Difference      Money                  `json:"difference"`
+	Tolerance       Money                  `json:"tolerance"`
+	Source          *string                `json:"source,omitempty" example:"xero"`
+	ScrapedAt       *time.Time             `json:"scrapedAt,omitempty"`
+	Alerted         bool                   `json:"alerted"`
+	CheckedAt       time.Time              `json:"checkedAt"`
+}
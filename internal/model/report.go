@@ -0,0 +1,160 @@
+package model
+
+import "time"
+
+// SpendingGroup is the spending on one category or merchant in a report
+// period, compared with the period before. Spending is money out less
+// refunds in.
+type SpendingGroup struct {
+	Name          string   `json:"name" example:"Groceries"`
+	Spent         Money    `json:"spent"`
+	Transactions  int      `json:"transactions" example:"14"`
+	PreviousSpent Money    `json:"previousSpent"`
+	Change        Money    `json:"change"`
+	ChangePercent *float64 `json:"changePercent,omitempty" example:"12.5"`
+}
+
+// ForeignCurrencyAccount is an account left out of a report's totals
+// because it is held in a different currency to them
+type ForeignCurrencyAccount struct {
+	AccountID string `json:"accountId" example:"87654321"`
+	Currency  string `json:"currency" example:"USD"`
+}
+
+// SpendingReport aggregates a month's stored transactions by category and
+// merchant, with changes from the month before. Categories and merchants
+// money only came in from, like salary, count towards income instead.
+// Amounts are in Australian dollars, or the account's currency for a
+// single account; accounts in other currencies are listed in
+// foreignCurrencyAccounts instead.
+type SpendingReport struct {
+	Period         string          `json:"period" example:"2024-05"`
+	PeriodStart    string          `json:"periodStart" example:"2024-05-01"`
+	PeriodEnd      string          `json:"periodEnd" example:"2024-05-31"`
+	PreviousPeriod string          `json:"previousPeriod" example:"2024-04"`
+	AccountID      *string         `json:"accountId,omitempty" example:"12345678"`
+	TotalSpent     Money           `json:"totalSpent"`
+	TotalIncome    Money           `json:"totalIncome"`
+	PreviousSpent  Money           `json:"previousSpent"`
+	Change         Money           `json:"change"`
+	ChangePercent  *float64        `json:"changePercent,omitempty" example:"-4.2"`
+	Categories     []SpendingGroup `json:"categories"`
+	Merchants      []SpendingGroup `json:"merchants"`
+	// ForeignCurrencyAccounts had transactions in the period that aren't
+	// counted
+	ForeignCurrencyAccounts []ForeignCurrencyAccount `json:"foreignCurrencyAccounts,omitempty"`
+	GeneratedAt             time.Time                `json:"generatedAt"`
+}
+
+// NetWorthAccount is one account's contribution to net worth. Credit card
+// and loan balances count as liabilities, everything else as assets.
+type NetWorthAccount struct {
+	AccountID string `json:"accountId" example:"12345678"`
+	Name      string `json:"name" example:"NAB Classic Banking"`
+	Type      string `json:"type" example:"savings"`
+	Balance   Money  `json:"balance"`
+	Liability bool   `json:"liability"`
+}
+
+// NetWorthPoint is net worth at the close of a day
+type NetWorthPoint struct {
+	Date        string `json:"date" example:"2024-05-31"`
+	Assets      Money  `json:"assets"`
+	Liabilities Money  `json:"liabilities"`
+	NetWorth    Money  `json:"netWorth"`
+}
+
+// NetWorthReport totals assets less liabilities across every stored
+// account, with a daily history from the recorded balance snapshots.
+// Totals are in Australian dollars; accounts in other currencies are
+// listed but left out of them.
+type NetWorthReport struct {
+	Assets                  Money                    `json:"assets"`
+	Liabilities             Money                    `json:"liabilities"`
+	NetWorth                Money                    `json:"netWorth"`
+	Accounts                []NetWorthAccount        `json:"accounts"`
+	ForeignCurrencyAccounts []ForeignCurrencyAccount `json:"foreignCurrencyAccounts,omitempty"`
+	History                 []NetWorthPoint          `json:"history"`
+	GeneratedAt             time.Time                `json:"generatedAt"`
+}
+
+// FeeTotal is what one kind of fee cost, less any refunded
+type FeeTotal struct {
+	FeeType string `json:"feeType" example:"international"`
+	Total   Money  `json:"total"`
+	Count   int    `json:"count" example:"3"`
+}
+
+// FeeMonth is what an account's fees cost in a calendar month
+type FeeMonth struct {
+	Month string     `json:"month" example:"2024-05"`
+	Total Money      `json:"total"`
+	Count int        `json:"count" example:"4"`
+	Fees  []FeeTotal `json:"fees"`
+}
+
+// FeeAccount is the fees charged to one account, by month, oldest first
+type FeeAccount struct {
+	AccountID string     `json:"accountId" example:"12345678"`
+	Name      string     `json:"name" example:"NAB Classic Banking"`
+	Total     Money      `json:"total"`
+	Months    []FeeMonth `json:"months"`
+}
+
+// FeeReport totals the fees NAB charged over recent months, per account
+// and month and by kind of fee. Fees are positive amounts.
+type FeeReport struct {
+	From        string       `json:"from" example:"2023-06"`
+	To          string       `json:"to" example:"2024-05"`
+	Total       Money        `json:"total"`
+	Fees        []FeeTotal   `json:"fees"`
+	Accounts    []FeeAccount `json:"accounts"`
+	GeneratedAt time.Time    `json:"generatedAt"`
+}
+
+// Subscription kinds
+const (
+	SubscriptionKindStreaming = "streaming"
+	SubscriptionKindGym       = "gym"
+	SubscriptionKindInsurance = "insurance"
+	SubscriptionKindOther     = "other"
+)
+
+// SubscriptionPriceChange is a rise in what a subscription charges
+type SubscriptionPriceChange struct {
+	PreviousAmount Money  `json:"previousAmount"`
+	Increase       Money  `json:"increase"`
+	ChangedOn      string `json:"changedOn" example:"2024-05-03"`
+}
+
+// Subscription is a merchant charging an account at a regular interval.
+// Amounts are positive.
+type Subscription struct {
+	Merchant     string                   `json:"merchant" example:"Netflix"`
+	Kind         string                   `json:"kind" example:"streaming"`
+	AccountID    string                   `json:"accountId" example:"12345678"`
+	Frequency    string                   `json:"frequency" example:"monthly"`
+	Amount       Money                    `json:"amount"`
+	MonthlyCost  Money                    `json:"monthlyCost"`
+	Charges      int                      `json:"charges" example:"6"`
+	LastCharged  string                   `json:"lastCharged" example:"2024-05-03"`
+	NextExpected string                   `json:"nextExpected" example:"2024-06-03"`
+	PriceChange  *SubscriptionPriceChange `json:"priceChange,omitempty"`
+}
+
+// SubscriptionKindTotal is the monthly cost of one kind of subscription
+type SubscriptionKindTotal struct {
+	Kind        string `json:"kind" example:"streaming"`
+	MonthlyCost Money  `json:"monthlyCost"`
+	Count       int    `json:"count" example:"3"`
+}
+
+// SubscriptionReport lists the subscriptions found in stored
+// transactions, with what they cost a month between them
+type SubscriptionReport struct {
+	MonthlyCost    Money                   `json:"monthlyCost"`
+	Kinds          []SubscriptionKindTotal `json:"kinds"`
+	Subscriptions  []Subscription          `json:"subscriptions"`
+	PriceIncreases int                     `json:"priceIncreases" example:"1"`
+	GeneratedAt    time.Time               `json:"generatedAt"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+)
+
+// Scheduled payment frequencies
+const (
+	PaymentFrequencyOnce        = "once"
+	PaymentFrequencyWeekly      = "weekly"
+	PaymentFrequencyFortnightly = "fortnightly"
+	PaymentFrequencyMonthly     = "monthly"
+	PaymentFrequencyQuarterly   = "quarterly"
+	PaymentFrequencyYearly      = "yearly"
+)
+
+// ScheduledPayment is a future-dated or recurring payment or transfer set
+// up in NAB internet banking
+type ScheduledPayment struct {
+	ID            string  `json:"id" example:"sched_3e9a1c7b2d4f6e80"`
+	FromAccountID *string `json:"fromAccountId,omitempty" example:"12345678"`
+	Payee         string  `json:"payee" example:"J SMITH"`
+	Amount        Money   `json:"amount"`
+	Frequency     string  `json:"frequency" example:"monthly"`
+	NextDate      string  `json:"nextDate" example:"2023-11-01"`
+	EndDate       *string `json:"endDate,omitempty" example:"2024-06-01"`
+	Description   *string `json:"description,omitempty" example:"Rent"`
+}
+
+// ScheduledPaymentsResponse represents the response for listing upcoming
+// payments
+type ScheduledPaymentsResponse struct {
+	Payments    []ScheduledPayment `json:"payments"`
+	RetrievedAt time.Time          `json:"retrievedAt"`
+	Count       int                `json:"count" example:"2"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// TransactionMatch is a stored transaction found by a search
+type TransactionMatch struct {
+	AccountID string `json:"accountId" example:"12345678"`
+	Transaction
+}
+
+// TransactionSearchResponse represents the response for a transaction
+// search
+type TransactionSearchResponse struct {
+	Query        string             `json:"query" example:"tfr j smith"`
+	Normalized   string             `json:"normalized" example:"transfer j smith"`
+	Transactions []TransactionMatch `json:"transactions"`
+	RetrievedAt  time.Time          `json:"retrievedAt"`
+	Count        int                `json:"count" example:"3"`
+}
+
+// TransactionsResponse represents a page of stored transactions
+type TransactionsResponse struct {
+	Transactions []TransactionMatch `json:"transactions"`
+	RetrievedAt  time.Time          `json:"retrievedAt"`
+	Count        int                `json:"count" example:"50"`
+	NextCursor   string             `json:"nextCursor,omitempty" example:"eyJnIjo0LCJvIjo1MCwiYSI6IjEyMzQ1Njc4IiwidCI6InR4bl8wMDEifQ"`
+}
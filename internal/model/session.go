@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// SessionLogoutResponse reports what a logout did to the persisted NAB
+// session
+type SessionLogoutResponse struct {
+	LoggedOut      bool      `json:"loggedOut"`
+	SessionCleared bool      `json:"sessionCleared"`
+	LoggedOutAt    time.Time `json:"loggedOutAt"`
+}
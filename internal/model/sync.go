@@ -0,0 +1,66 @@
+package model
+
+import "time"
+
+// Sync warning types
+const (
+	SyncWarningBalanceGap = "balance_gap"
+)
+
+// SyncWarning flags something found wrong with an account's transactions
+// after a sync, such as a running balance that no earlier transaction
+// accounts for
+type SyncWarning struct {
+	Type                    string `json:"type" example:"balance_gap"`
+	TransactionID           string `json:"transactionId" example:"txn_001_12345678"`
+	Date                    string `json:"date" example:"2023-10-17"`
+	ExpectedPreviousBalance Money  `json:"expectedPreviousBalance"`
+	Message                 string `json:"message" example:"No transaction leaves a balance of 2629.34 before this one; transactions may be missing"`
+}
+
+// SyncAccountResult is the outcome of syncing one account in a sync job
+type SyncAccountResult struct {
+	AccountID        string        `json:"accountId" example:"12345678"`
+	Name             string        `json:"name" example:"Complete Access Account"`
+	Balance          Money         `json:"balance"`
+	TransactionCount int           `json:"transactionCount" example:"42"`
+	SyncWarnings     []SyncWarning `json:"syncWarnings,omitempty"`
+}
+
+// SyncReport summarises the most recent sync, whether started through the
+// API or by the schedule
+type SyncReport struct {
+	SyncID        string             `json:"syncId" example:"job_1f2e3d4c5b6a7988"`
+	Trigger       string             `json:"trigger" example:"schedule"`
+	Status        string             `json:"status" example:"completed"`
+	StartedAt     time.Time          `json:"startedAt"`
+	CompletedAt   *time.Time         `json:"completedAt,omitempty"`
+	DurationMs    int64              `json:"durationMs" example:"48210"`
+	AccountsFound int                `json:"accountsFound" example:"4"`
+	Failed        int                `json:"failed" example:"1"`
+	Error         string             `json:"error,omitempty" example:"failed to scrape NAB accounts: login timed out"`
+	Errors        []SyncAccountError `json:"errors"`
+	Screenshots   []string           `json:"screenshots"`
+}
+
+// Sync report states
+const (
+	SyncReportRunning   = "running"
+	SyncReportCompleted = "completed"
+)
+
+// CacheClearResponse reports a cache clear
+type CacheClearResponse struct {
+	SharedEntriesRemoved int       `json:"sharedEntriesRemoved" example:"5"`
+	ClearedAt            time.Time `json:"clearedAt"`
+}
+
+// BrowserModeRequest changes how the browser is launched
+type BrowserModeRequest struct {
+	Headless *bool `json:"headless" example:"false"`
+}
+
+// BrowserModeResponse is how browsers are launched from now on
+type BrowserModeResponse struct {
+	Headless bool `json:"headless" example:"false"`
+}
@@ -0,0 +1,9 @@
+package model
+
+// TermDepositDetails holds term deposit specific account information
+type TermDepositDetails struct {
+	InterestRate       string  `json:"interestRate" example:"4.50"`
+	Term               string  `json:"term,omitempty" example:"12 months"`
+	MaturityDate       *string `json:"maturityDate,omitempty" example:"2024-10-17"`
+	InterestAtMaturity *Money  `json:"interestAtMaturity,omitempty"`
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"time"
+)
+
+// TokenUsage summarises requests made with one API key. Tokens are
+// identified by a hash of the key so the key itself is never exposed.
+type TokenUsage struct {
+	TokenID   string          `json:"tokenId" example:"tok_9f86d081884c"`
+	Requests  int64           `json:"requests" example:"1520"`
+	Errors    int64           `json:"errors" example:"12"`
+	BytesIn   int64           `json:"bytesIn" example:"20480"`
+	BytesOut  int64           `json:"bytesOut" example:"3145728"`
+	FirstUsed *time.Time      `json:"firstUsed,omitempty"`
+	LastUsed  *time.Time      `json:"lastUsed,omitempty"`
+	Endpoints []EndpointUsage `json:"endpoints"`
+}
+
+// EndpointUsage counts requests to one route
+type EndpointUsage struct {
+	Endpoint string `json:"endpoint" example:"GET /api/v1/accounts"`
+	Requests int64  `json:"requests" example:"980"`
+}
+
+// TokensResponse represents the response for listing API tokens
+type TokensResponse struct {
+	Tokens []APIToken `json:"tokens"`
+	Count  int        `json:"count" example:"2"`
+}
+
+// API token statuses
+const (
+	TokenStatusActive   = "active"
+	TokenStatusRotating = "rotating"
+	TokenStatusExpired  = "expired"
+	TokenStatusRevoked  = "revoked"
+)
+
+// APIToken is a managed API key. The key itself is only returned when the
+// token is created or rotated.
+type APIToken struct {
+	ID         string      `json:"id" example:"tok_9f86d081884c"`
+	Name       string      `json:"name" example:"home-assistant"`
+	Status     string      `json:"status" example:"active"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	ExpiresAt  *time.Time  `json:"expiresAt,omitempty"`
+	RevokedAt  *time.Time  `json:"revokedAt,omitempty"`
+	ReplacedBy *string     `json:"replacedBy,omitempty" example:"tok_2c26b46b68ff"`
+	Usage      *TokenUsage `json:"usage,omitempty"`
+}
+
+// APITokenRecord is an API token as persisted, with a hash of its key
+type APITokenRecord struct {
+	APIToken
+	KeyHash string `json:"keyHash"`
+}
+
+// TokenRequest is the body for creating an API token. ExpiresIn is a Go
+// duration such as "720h"; when omitted the configured default applies.
+type TokenRequest struct {
+	Name      string `json:"name" example:"home-assistant"`
+	ExpiresIn string `json:"expiresIn,omitempty" example:"720h"`
+}
+
+// RotateTokenRequest is the body for rotating an API token. The old key
+// keeps working for the grace period.
+type RotateTokenRequest struct {
+	ExpiresIn   string `json:"expiresIn,omitempty" example:"720h"`
+	GracePeriod string `json:"gracePeriod,omitempty" example:"24h"`
+}
+
+// CreatedToken is a newly issued API token along with its key
+type CreatedToken struct {
+	Token APIToken `json:"token"`
+	Key   string   `json:"key" example:"nab_5f2b9c0e7a1d4e6b8c3f2a9d0e1b7c4a5d6e8f9a0b1c2d3e"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+)
+
+// Transfer statuses
+const (
+	TransferStatusValidated = "validated"
+	TransferStatusCompleted = "completed"
+
+	// TransferStatusUnknown is a confirmed transfer whose receipt couldn't
+	// be read, so it may or may not have gone through
+	TransferStatusUnknown = "unknown"
+)
+
+// TransferRequest is the body for moving money between your own accounts
+type TransferRequest struct {
+	FromAccountID string `json:"fromAccountId" example:"12345678"`
+	ToAccountID   string `json:"toAccountId" example:"87654321"`
+	Amount        string `json:"amount" example:"250.00"`
+	Description   string `json:"description,omitempty" example:"Savings top up"`
+	DryRun        bool   `json:"dryRun,omitempty"`
+}
+
+// TransferResult is the outcome of a transfer. Dry runs are validated
+// against NAB's review screen but never confirmed, so have no receipt.
+type TransferResult struct {
+	Status        string         `json:"status" example:"completed"`
+	FromAccountID string         `json:"fromAccountId" example:"12345678"`
+	ToAccountID   string         `json:"toAccountId" example:"87654321"`
+	Amount        Money          `json:"amount"`
+	Description   string         `json:"description,omitempty" example:"Savings top up"`
+	ReceiptNumber *string        `json:"receiptNumber,omitempty" example:"N1234567890"`
+	DryRun        bool           `json:"dryRun"`
+	Review        *PaymentReview `json:"review,omitempty"`
+	ProcessedAt   time.Time      `json:"processedAt"`
+}
+
+// PaymentReview is what NAB's review screen showed for a transfer or
+// payment before it was confirmed. Fee and ProcessingDate are omitted when
+// the screen didn't show them.
+type PaymentReview struct {
+	Fee            *Money  `json:"fee,omitempty"`
+	ProcessingDate *string `json:"processingDate,omitempty" example:"2024-05-17"`
+	Text           string  `json:"text" example:"From Complete Access 5678 To Savings 4321 Amount $250.00 Fee $0.00 Transfer date Today"`
+}
@@ -0,0 +1,77 @@
+package model
+
+import "time"
+
+// V2Money is an amount of money in API v2: whole cents, so clients never
+// parse decimals, alongside the decimal value for display
+type V2Money struct {
+	Cents    int64  `json:"cents" example:"123456"`
+	Value    string `json:"value" example:"1234.56"`
+	Currency string `json:"currency" example:"AUD"`
+}
+
+// V2Page describes where a page sits in a list in API v2. Every list is
+// paged; Next is the URL of the following page, absent on the last one.
+type V2Page struct {
+	Limit      int    `json:"limit" example:"50"`
+	NextCursor string `json:"nextCursor,omitempty" example:"eyJnIjo0LCJvIjo1MCwiYSI6IjEyMzQ1Njc4In0"`
+	Next       string `json:"next,omitempty" example:"/api/v2/transactions?limit=50&cursor=eyJnIjo0LCJvIjo1MCwiYSI6IjEyMzQ1Njc4In0"`
+}
+
+// V2Account is an account in API v2
+type V2Account struct {
+	ID               string     `json:"id" example:"12345678"`
+	Name             string     `json:"name" example:"Complete Access Account"`
+	Type             string     `json:"type" example:"savings"`
+	Balance          V2Money    `json:"balance"`
+	AvailableBalance *V2Money   `json:"availableBalance,omitempty"`
+	AccountNumber    string     `json:"accountNumber,omitempty" example:"****1234"`
+	BSB              string     `json:"bsb,omitempty" example:"084001"`
+	Provider         string     `json:"provider,omitempty" example:"nab"`
+	Archived         bool       `json:"archived"`
+	LastUpdated      *time.Time `json:"lastUpdated,omitempty"`
+
+	// Cached and Stale are set when the account was served from the store
+	// rather than scraped, as in v1
+	Cached bool `json:"cached"`
+	Stale  bool `json:"stale"`
+}
+
+// V2Transaction is a stored transaction in API v2. Its date is a time at
+// midnight in Sydney, NAB's time zone, rather than a date string.
+type V2Transaction struct {
+	ID          string    `json:"id" example:"txn_20231017_001"`
+	AccountID   string    `json:"accountId" example:"12345678"`
+	Date        time.Time `json:"date"`
+	Description string    `json:"description" example:"EFTPOS Purchase - COLES SUPERMARKET"`
+	Amount      V2Money   `json:"amount"`
+	Balance     *V2Money  `json:"balance,omitempty"`
+	Category    string    `json:"category,omitempty" example:"Groceries"`
+	Merchant    string    `json:"merchant,omitempty" example:"COLES SUPERMARKET"`
+	Type        string    `json:"type,omitempty" example:"purchase"`
+	FeeType     string    `json:"feeType,omitempty" example:"international"`
+
+	// MerchantDetails is the merchant recognised behind the description
+	MerchantDetails *MerchantDetails `json:"merchantDetails,omitempty"`
+}
+
+// V2AccountsResponse represents a page of accounts in API v2
+type V2AccountsResponse struct {
+	Accounts    []V2Account `json:"accounts"`
+	Page        V2Page      `json:"page"`
+	RetrievedAt time.Time   `json:"retrievedAt"`
+}
+
+// V2AccountResponse represents a single account in API v2
+type V2AccountResponse struct {
+	Account     V2Account `json:"account"`
+	RetrievedAt time.Time `json:"retrievedAt"`
+}
+
+// V2TransactionsResponse represents a page of stored transactions in API
+// v2
+type V2TransactionsResponse struct {
+	Transactions []V2Transaction `json:"transactions"`
+	Page         V2Page          `json:"page"`
+	RetrievedAt  time.Time       `json:"retrievedAt"`
+}
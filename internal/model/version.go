@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// API version statuses
+const (
+	APIVersionStatusCurrent    = "current"
+	APIVersionStatusSupported  = "supported"
+	APIVersionStatusDeprecated = "deprecated"
+)
+
+// APIVersion describes one version of the HTTP API
+type APIVersion struct {
+	Version  string `json:"version" example:"v1"`
+	BasePath string `json:"basePath" example:"/api/v1"`
+	Status   string `json:"status" example:"supported"`
+
+	// Deprecated and Sunset are when the version was deprecated and when
+	// it will be removed, if they have been announced
+	Deprecated *time.Time `json:"deprecated,omitempty"`
+	Sunset     *time.Time `json:"sunset,omitempty"`
+
+	// Successor is the version replacing this one, if there is one
+	Successor string `json:"successor,omitempty" example:"v2"`
+}
+
+// APIVersionsResponse represents the response for listing API versions
+type APIVersionsResponse struct {
+	Versions []APIVersion `json:"versions"`
+	Current  string       `json:"current" example:"v2"`
+}
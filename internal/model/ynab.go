@@ -0,0 +1,20 @@
+package model
+
+// YNABTransaction is a transaction in the shape YNAB's API accepts.
+// Amounts are in milliunits, so -$12.34 is -12340.
+type YNABTransaction struct {
+	AccountID string `json:"account_id,omitempty" example:"3fa85f64-5717-4562-b3fc-2c963f66afa6"`
+	Date      string `json:"date" example:"2024-05-17"`
+	Amount    int64  `json:"amount" example:"-12340"`
+	PayeeName string `json:"payee_name" example:"Coles"`
+	Memo      string `json:"memo,omitempty" example:"EFTPOS 1234 COLES 0482 MELB"`
+	Cleared   string `json:"cleared" example:"cleared"`
+	Approved  bool   `json:"approved"`
+	ImportID  string `json:"import_id" example:"NAB:9f86d081884c7d659a2feaa0c55ad015"`
+}
+
+// YNABTransactionsResponse is a YNAB export in the body YNAB's create
+// transactions endpoint takes, so it can be posted as is
+type YNABTransactionsResponse struct {
+	Transactions []YNABTransaction `json:"transactions"`
+}
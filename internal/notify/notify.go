@@ -0,0 +1,169 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+// Event identifies the kind of alert being sent
+type Event string
+
+// Event types
+const (
+	EventLargeTransaction Event = "large_transaction"
+	EventLowBalance       Event = "low_balance"
+	EventScrapeFailure    Event = "scrape_failure"
+	EventNewMessage       Event = "new_message"
+	EventRateChange       Event = "rate_change"
+	EventTermsUpdate      Event = "terms_update"
+	EventChallenge        Event = "challenge_required"
+	EventBalanceMismatch  Event = "balance_mismatch"
+	EventBudget           Event = "budget"
+)
+
+// Priority levels, mapped onto each channel's own priority scale
+const (
+	PriorityLow    = -1
+	PriorityNormal = 0
+	PriorityHigh   = 1
+)
+
+// Notification is a single alert to be pushed to a channel
+type Notification struct {
+	Event    Event
+	Title    string
+	Message  string
+	Priority int
+}
+
+// Notifier delivers notifications to a single push channel
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, n Notification) error
+}
+
+// Router dispatches notifications to channels based on their event type
+type Router struct {
+	mu       sync.RWMutex
+	channels map[string]Notifier
+	routes   map[Event][]string
+	logger   *log.Logger
+}
+
+// NewRouter creates a router over the given channels. Events without an
+// explicit route are sent to every channel.
+func NewRouter(channels []Notifier, routes map[Event][]string, logger *log.Logger) *Router {
+	return &Router{
+		channels: channelsByName(channels),
+		routes:   routes,
+		logger:   logger,
+	}
+}
+
+// Update replaces the router's channels and routes. Notifications being
+// sent finish on the old ones.
+func (r *Router) Update(channels []Notifier, routes map[Event][]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.channels = channelsByName(channels)
+	r.routes = routes
+}
+
+// channelsByName indexes channels by their name
+func channelsByName(channels []Notifier) map[string]Notifier {
+	byName := make(map[string]Notifier, len(channels))
+	for _, channel := range channels {
+		byName[channel.Name()] = channel
+	}
+	return byName
+}
+
+// Name returns the router name
+func (r *Router) Name() string {
+	return "router"
+}
+
+// Send delivers the notification to every channel routed for its event
+func (r *Router) Send(ctx context.Context, n Notification) error {
+	r.mu.RLock()
+	channels := r.channels
+	names := r.targets(n.Event)
+	r.mu.RUnlock()
+
+	var errs []error
+	for _, name := range names {
+		channel, ok := channels[name]
+		if !ok {
+			continue
+		}
+		if err := channel.Send(ctx, n); err != nil {
+			r.logger.Printf("Failed to send %s notification via %s: %v", n.Event, name, err)
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// targets returns the channel names a given event should be delivered
+// to. The caller holds mu.
+func (r *Router) targets(event Event) []string {
+	if names, ok := r.routes[event]; ok {
+		return names
+	}
+
+	names := make([]string, 0, len(r.channels))
+	for name := range r.channels {
+		names = append(names, name)
+	}
+	return names
+}
+
+// ParseRoutes parses a route spec such as
+// "large_transaction=ntfy,pushover;scrape_failure=pushover"
+func ParseRoutes(spec string) (map[Event][]string, error) {
+	routes := make(map[Event][]string)
+	if strings.TrimSpace(spec) == "" {
+		return routes, nil
+	}
+
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		event, channels, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid notification route %q", entry)
+		}
+
+		var names []string
+		for _, name := range strings.Split(channels, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		routes[Event(strings.TrimSpace(event))] = names
+	}
+
+	return routes, nil
+}
+
+// Nop is a notifier that discards every notification
+type Nop struct{}
+
+// Name returns the notifier name
+func (Nop) Name() string {
+	return "nop"
+}
+
+// Send discards the notification
+func (Nop) Send(ctx context.Context, n Notification) error {
+	return nil
+}
@@ -0,0 +1,100 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type recordingNotifier struct {
+	name string
+	sent []Notification
+}
+
+func (r *recordingNotifier) Name() string {
+	return r.name
+}
+
+func (r *recordingNotifier) Send(ctx context.Context, n Notification) error {
+	r.sent = append(r.sent, n)
+	return nil
+}
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := ParseRoutes("large_transaction=ntfy, pushover; scrape_failure=pushover")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := routes[EventLargeTransaction]; len(got) != 2 || got[0] != "ntfy" || got[1] != "pushover" {
+		t.Errorf("unexpected large_transaction route: %v", got)
+	}
+	if got := routes[EventScrapeFailure]; len(got) != 1 || got[0] != "pushover" {
+		t.Errorf("unexpected scrape_failure route: %v", got)
+	}
+
+	if _, err := ParseRoutes("low_balance"); err == nil {
+		t.Error("expected error for route without channels")
+	}
+}
+
+func TestRouterSend(t *testing.T) {
+	ntfy := &recordingNotifier{name: "ntfy"}
+	pushover := &recordingNotifier{name: "pushover"}
+	routes := map[Event][]string{EventScrapeFailure: {"pushover"}}
+	router := NewRouter([]Notifier{ntfy, pushover}, routes, log.New(io.Discard, "", 0))
+
+	if err := router.Send(context.Background(), Notification{Event: EventScrapeFailure}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ntfy.sent) != 0 || len(pushover.sent) != 1 {
+		t.Errorf("routed event delivered to wrong channels: ntfy=%d pushover=%d", len(ntfy.sent), len(pushover.sent))
+	}
+
+	if err := router.Send(context.Background(), Notification{Event: EventLowBalance}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ntfy.sent) != 1 || len(pushover.sent) != 2 {
+		t.Errorf("unrouted event should go to all channels: ntfy=%d pushover=%d", len(ntfy.sent), len(pushover.sent))
+	}
+}
+
+func TestRouterUpdate(t *testing.T) {
+	ntfy := &recordingNotifier{name: "ntfy"}
+	pushover := &recordingNotifier{name: "pushover"}
+	router := NewRouter([]Notifier{ntfy}, nil, log.New(io.Discard, "", 0))
+
+	router.Update([]Notifier{ntfy, pushover}, map[Event][]string{EventLowBalance: {"pushover"}})
+	if err := router.Send(context.Background(), Notification{Event: EventLowBalance}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ntfy.sent) != 0 || len(pushover.sent) != 1 {
+		t.Errorf("expected the updated route to be used: ntfy=%d pushover=%d", len(ntfy.sent), len(pushover.sent))
+	}
+}
+
+func TestNtfyNotifierSend(t *testing.T) {
+	var gotPath, gotTitle, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotPath, gotTitle, gotBody = r.URL.Path, r.Header.Get("Title"), string(body)
+	}))
+	defer server.Close()
+
+	notifier := NewNtfyNotifier(server.URL, "nab-alerts", "")
+	err := notifier.Send(context.Background(), Notification{
+		Event:   EventLowBalance,
+		Title:   "Low balance",
+		Message: "Everyday balance is $12.00",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gotPath != "/nab-alerts" || gotTitle != "Low balance" || gotBody != "Everyday balance is $12.00" {
+		t.Errorf("unexpected request: path=%q title=%q body=%q", gotPath, gotTitle, gotBody)
+	}
+}
@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// NtfyNotifier publishes notifications to an ntfy.sh (or self-hosted ntfy) topic
+type NtfyNotifier struct {
+	baseURL string
+	topic   string
+	token   string
+	client  *http.Client
+}
+
+// NewNtfyNotifier creates a new ntfy notifier
+func NewNtfyNotifier(baseURL, topic, token string) *NtfyNotifier {
+	return &NtfyNotifier{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		topic:   topic,
+		token:   token,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns the channel name
+func (n *NtfyNotifier) Name() string {
+	return "ntfy"
+}
+
+// Send publishes the notification to the configured topic
+func (n *NtfyNotifier) Send(ctx context.Context, notification Notification) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.baseURL+"/"+n.topic, strings.NewReader(notification.Message))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Title", notification.Title)
+	req.Header.Set("Tags", string(notification.Event))
+	req.Header.Set("Priority", ntfyPriority(notification.Priority))
+	if n.token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.token)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("ntfy returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// ntfyPriority maps a priority onto ntfy's 1-5 scale
+func ntfyPriority(priority int) string {
+	switch {
+	case priority > PriorityNormal:
+		return "high"
+	case priority < PriorityNormal:
+		return "low"
+	default:
+		return "default"
+	}
+}
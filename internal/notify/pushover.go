@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const pushoverAPIURL = "https://api.pushover.net/1/messages.json"
+
+// PushoverNotifier sends notifications through the Pushover API
+type PushoverNotifier struct {
+	apiURL  string
+	token   string
+	userKey string
+	client  *http.Client
+}
+
+// NewPushoverNotifier creates a new Pushover notifier
+func NewPushoverNotifier(token, userKey string) *PushoverNotifier {
+	return &PushoverNotifier{
+		apiURL:  pushoverAPIURL,
+		token:   token,
+		userKey: userKey,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns the channel name
+func (p *PushoverNotifier) Name() string {
+	return "pushover"
+}
+
+// Send posts the notification to Pushover
+func (p *PushoverNotifier) Send(ctx context.Context, notification Notification) error {
+	form := url.Values{
+		"token":    {p.token},
+		"user":     {p.userKey},
+		"title":    {notification.Title},
+		"message":  {notification.Message},
+		"priority": {strconv.Itoa(notification.Priority)},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.apiURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("pushover returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+//go:embed swagger.html
+var swaggerHTML []byte
+
+// SpecHandler serves the document as JSON. The document is encoded once up
+// front since routes do not change at runtime.
+func SpecHandler(doc *Document) (http.HandlerFunc, error) {
+	body, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}, nil
+}
+
+// DocsHandler serves a Swagger UI page that renders /openapi.json
+func DocsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(swaggerHTML)
+}
@@ -0,0 +1,347 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Document is an OpenAPI 3 document
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]Operation `json:"paths"`
+	Components Components                      `json:"components"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Components holds the reusable schemas and security schemes
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes how a client authenticates
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+	In     string `json:"in,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+// Operation describes a single method on a path
+type Operation struct {
+	Summary     string                `json:"summary"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter describes a path or query parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes a JSON request body
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes a response for a status code
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema for a content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is a JSON schema object, or a reference to a component schema
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Example              interface{}        `json:"example,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+}
+
+// Route declares an API operation using Go values for its request and
+// response bodies. Schemas are derived from the json and example struct tags.
+type Route struct {
+	Method      string
+	Path        string
+	Summary     string
+	Tag         string
+	Parameters  []Parameter
+	Request     interface{}
+	Responses   map[int]interface{}
+	Secured     bool
+	ContentType string
+
+	// AlternateContentTypes are further media types successful responses
+	// can be requested in with the Accept header, documented as text
+	AlternateContentTypes []string
+}
+
+// Builder assembles a Document from routes
+type Builder struct {
+	doc   *Document
+	names map[reflect.Type]string
+}
+
+// securitySchemeName is the name of the API key scheme in the document
+const securitySchemeName = "apiKey"
+
+// NewBuilder creates a document builder
+func NewBuilder(info Info) *Builder {
+	return &Builder{
+		names: make(map[reflect.Type]string),
+		doc: &Document{
+			OpenAPI: "3.0.3",
+			Info:    info,
+			Paths:   make(map[string]map[string]Operation),
+			Components: Components{
+				Schemas: make(map[string]*Schema),
+				SecuritySchemes: map[string]*SecurityScheme{
+					securitySchemeName: {Type: "http", Scheme: "bearer"},
+				},
+			},
+		},
+	}
+}
+
+// Add registers a route with the document
+func (b *Builder) Add(route Route) {
+	contentType := route.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	op := Operation{
+		Summary:    route.Summary,
+		Parameters: route.Parameters,
+		Responses:  make(map[string]Response),
+	}
+	if route.Tag != "" {
+		op.Tags = []string{route.Tag}
+	}
+	if route.Secured {
+		op.Security = []map[string][]string{{securitySchemeName: {}}}
+	}
+	if route.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: b.Schema(reflect.TypeOf(route.Request))}},
+		}
+	}
+
+	for status, body := range route.Responses {
+		response := Response{Description: http.StatusText(status)}
+		if body != nil {
+			content := contentType
+			if status >= 400 {
+				content = "application/json"
+			}
+			response.Content = map[string]MediaType{content: {Schema: b.Schema(reflect.TypeOf(body))}}
+			if status < 400 {
+				for _, alternate := range route.AlternateContentTypes {
+					response.Content[alternate] = MediaType{Schema: &Schema{Type: "string"}}
+				}
+			}
+		}
+		op.Responses[strconv.Itoa(status)] = response
+	}
+
+	if b.doc.Paths[route.Path] == nil {
+		b.doc.Paths[route.Path] = make(map[string]Operation)
+	}
+	b.doc.Paths[route.Path][strings.ToLower(route.Method)] = op
+}
+
+// Document returns the assembled document
+func (b *Builder) Document() *Document {
+	return b.doc
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Schema returns the schema for a Go type, registering named structs as
+// component schemas and referencing them
+func (b *Builder) Schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Struct && t.Name() != "":
+		name, ok := b.names[t]
+		if !ok {
+			name = b.componentName(t)
+			b.names[t] = name
+			// Register the name first so recursive types terminate
+			b.doc.Components.Schemas[name] = &Schema{}
+			*b.doc.Components.Schemas[name] = *b.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	case t.Kind() == reflect.Struct:
+		return b.structSchema(t)
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: b.Schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.Schema(t.Elem())}
+	default:
+		// interface{} and anything else accepts any value
+		return &Schema{}
+	}
+}
+
+// componentName picks a schema name for a struct, qualifying it with its
+// package name when another package has a type of the same name
+func (b *Builder) componentName(t reflect.Type) string {
+	name := t.Name()
+	if _, taken := b.doc.Components.Schemas[name]; !taken {
+		return name
+	}
+
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	if pkg == "" {
+		return name
+	}
+	return strings.ToUpper(pkg[:1]) + pkg[1:] + name
+}
+
+// structSchema builds an object schema from exported fields, flattening
+// embedded structs as encoding/json does
+func (b *Builder) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !(field.Anonymous && indirect(field.Type).Kind() == reflect.Struct) {
+			continue
+		}
+
+		name, omitEmpty, skip := jsonName(field)
+		if skip {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := b.structSchema(indirect(field.Type))
+			for key, value := range embedded.Properties {
+				schema.Properties[key] = value
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := b.Schema(field.Type)
+		if example, ok := field.Tag.Lookup("example"); ok {
+			if property.Ref != "" {
+				// Siblings of $ref are ignored in OpenAPI 3.0
+				property = &Schema{Ref: property.Ref}
+			} else {
+				property.Example = exampleValue(property.Type, example)
+			}
+		}
+		if field.Type.Kind() == reflect.Ptr && property.Ref == "" {
+			property.Nullable = true
+		}
+
+		schema.Properties[name] = property
+		if !omitEmpty && field.Type.Kind() != reflect.Ptr {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	sort.Strings(schema.Required)
+	return schema
+}
+
+// jsonName reads a field's json tag
+func jsonName(field reflect.StructField) (name string, omitEmpty, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	parts := strings.Split(tag, ",")
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return parts[0], omitEmpty, false
+}
+
+// exampleValue converts an example tag to the schema's JSON type
+func exampleValue(schemaType, example string) interface{} {
+	switch schemaType {
+	case "integer":
+		if v, err := strconv.ParseInt(example, 10, 64); err == nil {
+			return v
+		}
+	case "number":
+		if v, err := strconv.ParseFloat(example, 64); err == nil {
+			return v
+		}
+	case "boolean":
+		if v, err := strconv.ParseBool(example); err == nil {
+			return v
+		}
+	}
+
+	return example
+}
+
+// indirect dereferences pointer types
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,106 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testMoney struct {
+	Amount string `json:"amount" example:"12.34"`
+}
+
+type testAccount struct {
+	ID      string     `json:"id" example:"123"`
+	Count   int        `json:"count" example:"3"`
+	Balance testMoney  `json:"balance"`
+	Note    *string    `json:"note,omitempty"`
+	Updated *time.Time `json:"updated,omitempty"`
+	hidden  string
+}
+
+type testDetails struct {
+	testAccount
+	Tags []string `json:"tags,omitempty"`
+}
+
+func TestSchemaFromStructTags(t *testing.T) {
+	b := NewBuilder(Info{Title: "test", Version: "1"})
+	ref := b.Schema(reflect.TypeOf(testDetails{}))
+	if ref.Ref != "#/components/schemas/testDetails" {
+		t.Fatalf("unexpected ref %q", ref.Ref)
+	}
+
+	schema := b.Document().Components.Schemas["testDetails"]
+	if schema == nil {
+		t.Fatal("schema not registered")
+	}
+	for _, name := range []string{"id", "count", "balance", "note", "updated", "tags"} {
+		if schema.Properties[name] == nil {
+			t.Errorf("missing property %q", name)
+		}
+	}
+	if _, ok := schema.Properties["hidden"]; ok {
+		t.Error("unexported field included")
+	}
+	if got := schema.Properties["count"].Example; got != int64(3) {
+		t.Errorf("count example = %#v, want 3", got)
+	}
+	if got := schema.Properties["updated"].Format; got != "date-time" {
+		t.Errorf("updated format = %q, want date-time", got)
+	}
+	if !reflect.DeepEqual(schema.Required, []string{"balance", "count", "id"}) {
+		t.Errorf("required = %v", schema.Required)
+	}
+	if b.Document().Components.Schemas["testMoney"] == nil {
+		t.Error("nested struct not registered as a component")
+	}
+}
+
+func TestBuilderAddsOperations(t *testing.T) {
+	b := NewBuilder(Info{Title: "test", Version: "1"})
+	b.Add(Route{
+		Method:    "POST",
+		Path:      "/things",
+		Request:   testMoney{},
+		Responses: map[int]interface{}{200: testAccount{}, 500: nil},
+		Secured:   true,
+	})
+
+	op, ok := b.Document().Paths["/things"]["post"]
+	if !ok {
+		t.Fatal("operation not registered")
+	}
+	if op.RequestBody == nil || len(op.Security) != 1 {
+		t.Error("expected request body and security requirement")
+	}
+	if op.Responses["500"].Content != nil {
+		t.Error("expected no content for nil response body")
+	}
+
+	if _, err := json.Marshal(b.Document()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuilderAddsAlternateContentTypes(t *testing.T) {
+	b := NewBuilder(Info{Title: "test", Version: "1"})
+	b.Add(Route{
+		Method:                "GET",
+		Path:                  "/things",
+		Responses:             map[int]interface{}{200: testAccount{}, 400: testMoney{}},
+		AlternateContentTypes: []string{"text/csv"},
+	})
+
+	op := b.Document().Paths["/things"]["get"]
+	if _, ok := op.Responses["200"].Content["text/csv"]; !ok {
+		t.Error("expected text/csv on the success response")
+	}
+	if _, ok := op.Responses["200"].Content["application/json"]; !ok {
+		t.Error("expected application/json on the success response")
+	}
+	if _, ok := op.Responses["400"].Content["text/csv"]; ok {
+		t.Error("error responses should only be JSON")
+	}
+}
@@ -0,0 +1,86 @@
+// Package pagination pages through list results with opaque cursors, so
+// clients can walk large result sets without items being skipped or
+// repeated when a refresh changes the list between pages.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidCursor is returned for a cursor that wasn't issued by Page
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Key identifies an item in a list, so a page can resume after it
+type Key struct {
+	AccountID     string `json:"a,omitempty"`
+	TransactionID string `json:"t,omitempty"`
+}
+
+// cursor is the decoded form of a page token: where the last page ended,
+// and the store generation the list was read at
+type cursor struct {
+	Generation uint64 `json:"g"`
+	Offset     int    `json:"o"`
+	Key
+}
+
+// Page returns up to limit items following the cursor token, or from the
+// start for an empty token, and the token for the next page, empty on the
+// last one. While the generation is unchanged the next page starts at the
+// recorded offset. Once a refresh has changed the list, it starts after
+// the last item returned instead, or at the offset if that item is gone.
+func Page[T any](items []T, token string, limit int, generation uint64, key func(T) Key) ([]T, string, error) {
+	start := 0
+	if token != "" {
+		c, err := decode(token)
+		if err != nil {
+			return nil, "", err
+		}
+		start = c.Offset
+		if c.Generation != generation {
+			for i, item := range items {
+				if key(item) == c.Key {
+					start = i + 1
+					break
+				}
+			}
+		}
+	}
+	if start > len(items) {
+		start = len(items)
+	}
+
+	end := len(items)
+	if limit > 0 && start+limit < end {
+		end = start + limit
+	}
+	page := items[start:end]
+	if end == len(items) || len(page) == 0 {
+		return page, "", nil
+	}
+
+	next := encode(cursor{Generation: generation, Offset: end, Key: key(page[len(page)-1])})
+	return page, next, nil
+}
+
+// encode turns a cursor into an opaque URL-safe token
+func encode(c cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decode reverses encode
+func decode(token string) (cursor, error) {
+	var c cursor
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, fmt.Errorf("%w: not base64", ErrInvalidCursor)
+	}
+	if err := json.Unmarshal(raw, &c); err != nil || c.Offset < 0 {
+		return c, fmt.Errorf("%w: malformed", ErrInvalidCursor)
+	}
+	return c, nil
+}
@@ -0,0 +1,49 @@
+package pagination
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPage(t *testing.T) {
+	key := func(id string) Key { return Key{TransactionID: id} }
+	items := []string{"a", "b", "c", "d", "e"}
+
+	var pages [][]string
+	token := ""
+	for {
+		page, next, err := Page(items, token, 2, 1, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages = append(pages, page)
+		if next == "" {
+			break
+		}
+		token = next
+	}
+	if want := [][]string{{"a", "b"}, {"c", "d"}, {"e"}}; !reflect.DeepEqual(pages, want) {
+		t.Fatalf("got pages %v, want %v", pages, want)
+	}
+
+	// A refresh adds newer items ahead of the ones already paged through
+	first, next, _ := Page(items, "", 2, 1, key)
+	refreshed := append([]string{"z"}, items...)
+	second, _, err := Page(refreshed, next, 2, 2, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first[1] != "b" || !reflect.DeepEqual(second, []string{"c", "d"}) {
+		t.Errorf("expected the page after b despite the refresh, got %v", second)
+	}
+
+	all, next, _ := Page(items, "", 0, 1, key)
+	if len(all) != len(items) || next != "" {
+		t.Errorf("expected everything without a limit, got %v and cursor %q", all, next)
+	}
+
+	if _, _, err := Page(items, "not a cursor!", 2, 1, key); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected invalid cursor error, got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// Named is a bank provider with the name it was registered under
+type Named struct {
+	Name     string
+	Provider service.BankProvider
+}
+
+// Multi serves accounts from several bank providers under one roof.
+// Accounts are tagged with their provider, and transaction requests are
+// routed to the provider the account came from. Only the BankProvider
+// methods are combined, so features behind optional interfaces such as
+// transfers are unavailable while several providers are enabled.
+type Multi struct {
+	providers []Named
+
+	mu     sync.Mutex
+	owners map[string]Named
+}
+
+// NewMulti combines bank providers, listing accounts in the order the
+// providers are given
+func NewMulti(providers ...Named) *Multi {
+	return &Multi{providers: providers, owners: make(map[string]Named)}
+}
+
+// GetAccounts lists every provider's accounts. Any provider failing fails
+// the whole list, as a partial list would look like closed accounts.
+func (m *Multi) GetAccounts(ctx context.Context) ([]model.Account, error) {
+	var accounts []model.Account
+	owners := make(map[string]Named)
+	for _, named := range m.providers {
+		provided, err := named.Provider.GetAccounts(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", named.Name, err)
+		}
+		for _, account := range provided {
+			if owner, ok := owners[account.ID]; ok {
+				return nil, fmt.Errorf("account %s is provided by both %s and %s", account.ID, owner.Name, named.Name)
+			}
+			owners[account.ID] = named
+			account.Provider = named.Name
+			accounts = append(accounts, account)
+		}
+	}
+
+	m.mu.Lock()
+	m.owners = owners
+	m.mu.Unlock()
+	return accounts, nil
+}
+
+// GetAccountTransactions returns the transactions of an account from the
+// provider it belongs to
+func (m *Multi) GetAccountTransactions(ctx context.Context, accountID string) ([]model.Transaction, error) {
+	owner, err := m.owner(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return owner.Provider.GetAccountTransactions(ctx, accountID)
+}
+
+// GetAccountTransactionsSince returns an account's transactions on or
+// after since, fetching them all if its provider can't filter by date
+func (m *Multi) GetAccountTransactionsSince(ctx context.Context, accountID string, since string) ([]model.Transaction, error) {
+	owner, err := m.owner(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if incremental, ok := owner.Provider.(service.IncrementalTransactionClient); ok {
+		return incremental.GetAccountTransactionsSince(ctx, accountID, since)
+	}
+	return owner.Provider.GetAccountTransactions(ctx, accountID)
+}
+
+// GetMessages lists every provider's messages
+func (m *Multi) GetMessages(ctx context.Context) ([]model.Message, error) {
+	return collect(ctx, m.providers, service.BankProvider.GetMessages)
+}
+
+// GetPayees lists every provider's saved payees
+func (m *Multi) GetPayees(ctx context.Context) ([]model.Payee, error) {
+	return collect(ctx, m.providers, service.BankProvider.GetPayees)
+}
+
+// GetScheduledPayments lists every provider's scheduled payments
+func (m *Multi) GetScheduledPayments(ctx context.Context) ([]model.ScheduledPayment, error) {
+	return collect(ctx, m.providers, service.BankProvider.GetScheduledPayments)
+}
+
+// owner finds the provider an account belongs to, listing accounts again
+// if it hasn't been seen yet
+func (m *Multi) owner(ctx context.Context, accountID string) (Named, error) {
+	m.mu.Lock()
+	owner, ok := m.owners[accountID]
+	m.mu.Unlock()
+	if ok {
+		return owner, nil
+	}
+
+	if _, err := m.GetAccounts(ctx); err != nil {
+		return Named{}, err
+	}
+	m.mu.Lock()
+	owner, ok = m.owners[accountID]
+	m.mu.Unlock()
+	if !ok {
+		return Named{}, service.ErrAccountNotFound
+	}
+	return owner, nil
+}
+
+// collect concatenates a list from every provider
+func collect[T any](ctx context.Context, providers []Named, list func(service.BankProvider, context.Context) ([]T, error)) ([]T, error) {
+	result := []T{}
+	for _, named := range providers {
+		items, err := list(named.Provider, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", named.Name, err)
+		}
+		result = append(result, items...)
+	}
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/config"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// fakeBank serves fixed accounts, each with one transaction
+type fakeBank struct {
+	accounts []string
+	payees   []model.Payee
+}
+
+func (f *fakeBank) GetAccounts(ctx context.Context) ([]model.Account, error) {
+	var accounts []model.Account
+	for _, id := range f.accounts {
+		accounts = append(accounts, model.Account{ID: id})
+	}
+	return accounts, nil
+}
+
+func (f *fakeBank) GetAccountTransactions(ctx context.Context, accountID string) ([]model.Transaction, error) {
+	for _, id := range f.accounts {
+		if id == accountID {
+			return []model.Transaction{{ID: "txn_" + accountID}}, nil
+		}
+	}
+	return nil, errors.New("wrong bank")
+}
+
+func (f *fakeBank) GetMessages(ctx context.Context) ([]model.Message, error) {
+	return nil, nil
+}
+
+func (f *fakeBank) GetPayees(ctx context.Context) ([]model.Payee, error) {
+	return f.payees, nil
+}
+
+func (f *fakeBank) GetScheduledPayments(ctx context.Context) ([]model.ScheduledPayment, error) {
+	return nil, nil
+}
+
+func TestRegistryOpen(t *testing.T) {
+	nab := &fakeBank{accounts: []string{"12345678"}}
+	up := &fakeBank{accounts: []string{"up-1"}}
+	registry := NewRegistry()
+	registry.Register("nab", func(*config.Config, *log.Logger) (service.BankProvider, error) { return nab, nil })
+	registry.Register("up", func(*config.Config, *log.Logger) (service.BankProvider, error) { return up, nil })
+	logger := log.New(io.Discard, "", 0)
+
+	single, err := registry.Open([]string{"nab"}, &config.Config{}, logger)
+	if err != nil || single != service.BankProvider(nab) {
+		t.Errorf("expected a single provider to be returned unwrapped, got %v %v", single, err)
+	}
+	if _, err := registry.Open([]string{"cba"}, &config.Config{}, logger); err == nil {
+		t.Error("expected an unknown provider to fail")
+	}
+	combined, err := registry.Open([]string{"nab", "up"}, &config.Config{}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := combined.(*Multi); !ok {
+		t.Errorf("expected several providers to be combined, got %T", combined)
+	}
+}
+
+func TestMulti(t *testing.T) {
+	multi := NewMulti(
+		Named{Name: "nab", Provider: &fakeBank{accounts: []string{"12345678"}, payees: []model.Payee{{ID: "payee_1"}}}},
+		Named{Name: "up", Provider: &fakeBank{accounts: []string{"up-1", "up-2"}, payees: []model.Payee{{ID: "payee_2"}}}},
+	)
+	ctx := context.Background()
+
+	// Transactions are routed before accounts have been listed
+	transactions, err := multi.GetAccountTransactionsSince(ctx, "up-2", "2024-05-01")
+	if err != nil || len(transactions) != 1 || transactions[0].ID != "txn_up-2" {
+		t.Errorf("unexpected transactions %+v: %v", transactions, err)
+	}
+	if _, err := multi.GetAccountTransactions(ctx, "99999999"); !errors.Is(err, service.ErrAccountNotFound) {
+		t.Errorf("expected account not found, got %v", err)
+	}
+
+	accounts, err := multi.GetAccounts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 3 || accounts[0].Provider != "nab" || accounts[2].Provider != "up" {
+		t.Errorf("unexpected accounts %+v", accounts)
+	}
+
+	payees, err := multi.GetPayees(ctx)
+	if err != nil || len(payees) != 2 {
+		t.Errorf("unexpected payees %+v: %v", payees, err)
+	}
+
+	clash := NewMulti(Named{Name: "nab", Provider: &fakeBank{accounts: []string{"1"}}}, Named{Name: "up", Provider: &fakeBank{accounts: []string{"1"}}})
+	if _, err := clash.GetAccounts(ctx); err == nil {
+		t.Error("expected an account ID provided twice to fail")
+	}
+}
@@ -0,0 +1,67 @@
+// Package provider selects the bank providers accounts are read from
+package provider
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/benrowe/nab-bank-api/internal/config"
+	"github.com/benrowe/nab-bank-api/internal/service"
+)
+
+// Factory creates a bank provider from the configuration
+type Factory func(cfg *config.Config, logger *log.Logger) (service.BankProvider, error)
+
+// Registry holds the bank providers that can be enabled by name
+type Registry struct {
+	factories map[string]Factory
+}
+
+// NewRegistry creates an empty provider registry
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]Factory)}
+}
+
+// Register makes a provider available under name, replacing any provider
+// already registered with it
+func (r *Registry) Register(name string, factory Factory) {
+	r.factories[name] = factory
+}
+
+// Names lists the registered providers in alphabetical order
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open creates the named providers. A single provider is returned as is,
+// keeping any optional interfaces it implements; several are combined
+// with NewMulti.
+func (r *Registry) Open(names []string, cfg *config.Config, logger *log.Logger) (service.BankProvider, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no bank providers configured")
+	}
+
+	providers := make([]Named, 0, len(names))
+	for _, name := range names {
+		factory, ok := r.factories[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown bank provider %q, expected one of %v", name, r.Names())
+		}
+		bank, err := factory(cfg, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create bank provider %s: %w", name, err)
+		}
+		providers = append(providers, Named{Name: name, Provider: bank})
+	}
+
+	if len(providers) == 1 {
+		return providers[0].Provider, nil
+	}
+	return NewMulti(providers...), nil
+}
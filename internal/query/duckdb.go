@@ -0,0 +1,140 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/export"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// Query errors
+var (
+	ErrNotReadOnly    = errors.New("only a single SELECT or WITH statement is allowed")
+	ErrQueryTimeout   = errors.New("query timed out")
+	ErrEngineNotFound = errors.New("duckdb executable not found")
+)
+
+// readOnlyPattern matches statements that start with SELECT or WITH
+var readOnlyPattern = regexp.MustCompile(`(?is)^\s*(select|with)\b`)
+
+// Engine runs ad-hoc read-only SQL against an analytical copy of the store
+// using the DuckDB CLI. Each query loads a fresh Parquet snapshot into an
+// in-memory database with external file access disabled, so queries can
+// neither modify the store nor read other files.
+type Engine struct {
+	store      *store.Store
+	duckdbPath string
+	timeout    time.Duration
+	maxRows    int
+}
+
+// NewEngine creates a query engine using the duckdb executable at path,
+// which may be a bare name looked up on PATH
+func NewEngine(store *store.Store, path string, timeout time.Duration, maxRows int) *Engine {
+	return &Engine{
+		store:      store,
+		duckdbPath: path,
+		timeout:    timeout,
+		maxRows:    maxRows,
+	}
+}
+
+// Available reports whether the duckdb executable can be found
+func (e *Engine) Available() bool {
+	_, err := exec.LookPath(e.duckdbPath)
+	return err == nil
+}
+
+// Run executes a read-only query, returning at most the configured number
+// of rows
+func (e *Engine) Run(ctx context.Context, sql string) (*model.QueryResponse, error) {
+	sql = strings.TrimRight(strings.TrimSpace(sql), ";")
+	if !readOnlyPattern.MatchString(sql) || strings.Contains(sql, ";") {
+		return nil, ErrNotReadOnly
+	}
+
+	executable, err := exec.LookPath(e.duckdbPath)
+	if err != nil {
+		return nil, ErrEngineNotFound
+	}
+
+	dir, err := os.MkdirTemp("", "nab-query-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	sink, err := export.NewSink(dir, export.S3Credentials{})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := export.NewExporter(e.store, sink, "").Export(ctx, export.Options{Redaction: export.RedactNone}); err != nil {
+		return nil, fmt.Errorf("failed to snapshot store: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, executable, "-json", "-bail", ":memory:")
+	cmd.Stdin = strings.NewReader(e.script(dir, sql))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, ErrQueryTimeout
+		}
+		return nil, fmt.Errorf("query failed: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	rows := []map[string]interface{}{}
+	if out := bytes.TrimSpace(stdout.Bytes()); len(out) > 0 {
+		if err := json.Unmarshal(out, &rows); err != nil {
+			return nil, fmt.Errorf("failed to decode query results: %w", err)
+		}
+	}
+
+	response := &model.QueryResponse{
+		Rows:       rows,
+		ExecutedAt: time.Now(),
+	}
+	if len(rows) > e.maxRows {
+		response.Rows = rows[:e.maxRows]
+		response.Truncated = true
+	}
+	response.RowCount = len(response.Rows)
+
+	return response, nil
+}
+
+// script loads the snapshot into memory, locks down external access and
+// runs the query with a row limit
+func (e *Engine) script(dir, sql string) string {
+	tables := map[string]string{
+		"transactions":    export.TransactionsFile,
+		"balance_history": export.BalanceHistoryFile,
+	}
+
+	var b strings.Builder
+	for table, file := range tables {
+		path := strings.ReplaceAll(filepath.Join(dir, file), "'", "''")
+		fmt.Fprintf(&b, "CREATE TABLE %s AS SELECT * FROM read_parquet('%s');\n", table, path)
+	}
+	b.WriteString("SET enable_external_access = false;\n")
+	b.WriteString("SET lock_configuration = true;\n")
+	fmt.Fprintf(&b, "SELECT * FROM (\n%s\n) AS q LIMIT %d;\n", sql, e.maxRows+1)
+
+	return b.String()
+}
@@ -0,0 +1,185 @@
+package ratewatch
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/notify"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// DefaultPages are NAB's public savings and home loan rate pages
+var DefaultPages = []string{
+	"https://www.nab.com.au/personal/bank-accounts/savings-accounts",
+	"https://www.nab.com.au/personal/home-loans/home-loan-interest-rates",
+}
+
+// Fetcher returns the rendered text of a public web page
+type Fetcher interface {
+	FetchText(ctx context.Context, url string) (string, error)
+}
+
+// Watcher periodically scrapes NAB's public product pages and notifies
+// when an advertised rate changes
+type Watcher struct {
+	fetcher  Fetcher
+	store    *store.Store
+	notifier notify.Notifier
+	pages    []string
+	logger   *log.Logger
+}
+
+// NewWatcher creates a rate watcher over the given pages. A nil notifier
+// records rates without sending alerts.
+func NewWatcher(fetcher Fetcher, store *store.Store, notifier notify.Notifier, pages []string, logger *log.Logger) *Watcher {
+	if notifier == nil {
+		notifier = notify.Nop{}
+	}
+
+	return &Watcher{
+		fetcher:  fetcher,
+		store:    store,
+		notifier: notifier,
+		pages:    pages,
+		logger:   logger,
+	}
+}
+
+// Run checks the pages immediately and then every interval until the
+// context is cancelled
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Check(ctx); err != nil {
+			w.logger.Printf("Rate watch check failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check scrapes every page once, sends a notification for each changed
+// rate and returns the changes. Pages that fail are skipped so one broken
+// page doesn't stop the others being checked.
+func (w *Watcher) Check(ctx context.Context) ([]model.RateChange, error) {
+	var changes []model.RateChange
+	var failed []string
+
+	for _, page := range w.pages {
+		text, err := w.fetcher.FetchText(ctx, page)
+		if err != nil {
+			w.logger.Printf("Failed to fetch rate page %s: %v", page, err)
+			failed = append(failed, page)
+			continue
+		}
+
+		rates := ParseRates(page, text, time.Now())
+		if len(rates) == 0 {
+			// An empty page usually means the layout changed or the page
+			// didn't render, so keep the previous rates
+			w.logger.Printf("No rates found on %s", page)
+			continue
+		}
+
+		pageChanges, err := w.store.RecordAdvertisedRates(page, rates)
+		if err != nil {
+			return changes, fmt.Errorf("failed to record rates: %w", err)
+		}
+		changes = append(changes, pageChanges...)
+	}
+
+	for _, change := range changes {
+		w.notify(ctx, change)
+	}
+
+	if len(failed) > 0 {
+		return changes, fmt.Errorf("failed to fetch %d of %d rate pages", len(failed), len(w.pages))
+	}
+	return changes, nil
+}
+
+// notify sends a rate change alert
+func (w *Watcher) notify(ctx context.Context, change model.RateChange) {
+	direction := "up"
+	oldRate, _ := strconv.ParseFloat(change.OldRate, 64)
+	newRate, _ := strconv.ParseFloat(change.NewRate, 64)
+	if newRate < oldRate {
+		direction = "down"
+	}
+
+	err := w.notifier.Send(ctx, notify.Notification{
+		Event:    notify.EventRateChange,
+		Title:    "NAB rate change",
+		Message:  fmt.Sprintf("%s %s from %s%% to %s%% p.a.", change.Product, direction, change.OldRate, change.NewRate),
+		Priority: notify.PriorityNormal,
+	})
+	if err != nil {
+		w.logger.Printf("Failed to send rate change notification: %v", err)
+	}
+}
+
+var ratePattern = regexp.MustCompile(`(?i)(\d{1,2}\.\d{1,2})\s*%(?:\s*p\.?\s*a\.?)?`)
+
+// ParseRates extracts advertised rates from page text. Each rate is
+// labelled with the text before it on the same line, or the nearest
+// preceding non-empty line when the rate stands alone, which is how NAB
+// lays out product tables.
+func ParseRates(page, text string, observedAt time.Time) []model.AdvertisedRate {
+	var rates []model.AdvertisedRate
+	seen := make(map[string]int)
+	previous := ""
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			continue
+		}
+
+		matches := ratePattern.FindAllStringSubmatchIndex(line, -1)
+		if matches == nil {
+			previous = line
+			continue
+		}
+
+		start := 0
+		for _, match := range matches {
+			label := strings.Trim(line[start:match[0]], " :-–|")
+			if label == "" {
+				label = previous
+			}
+			start = match[1]
+			if label == "" {
+				continue
+			}
+
+			// Repeated labels, such as several "Comparison rate" cells,
+			// are numbered so each keeps its own history
+			seen[label]++
+			product := label
+			if seen[label] > 1 {
+				product = fmt.Sprintf("%s (%d)", label, seen[label])
+			}
+
+			rates = append(rates, model.AdvertisedRate{
+				Page:       page,
+				Product:    product,
+				Rate:       line[match[2]:match[3]],
+				ObservedAt: observedAt,
+			})
+		}
+	}
+
+	return rates
+}
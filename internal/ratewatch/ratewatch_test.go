@@ -0,0 +1,89 @@
+package ratewatch
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/notify"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+type fakeFetcher map[string]string
+
+func (f fakeFetcher) FetchText(ctx context.Context, url string) (string, error) {
+	return f[url], nil
+}
+
+type recordingNotifier struct {
+	sent []notify.Notification
+}
+
+func (r *recordingNotifier) Name() string { return "recording" }
+
+func (r *recordingNotifier) Send(ctx context.Context, n notify.Notification) error {
+	r.sent = append(r.sent, n)
+	return nil
+}
+
+func TestParseRates(t *testing.T) {
+	rates := ParseRates("page", `NAB Reward Saver
+Bonus rate 4.85% p.a.
+Base rate: 0.10% p.a.
+
+Basic Variable Rate Home Loan
+6.24%
+Comparison rate 6.28% p.a.
+Comparison rate 6.40% p.a.`, time.Now())
+
+	want := map[string]string{
+		"Bonus rate":                    "4.85",
+		"Base rate":                     "0.10",
+		"Basic Variable Rate Home Loan": "6.24",
+		"Comparison rate":               "6.28",
+		"Comparison rate (2)":           "6.40",
+	}
+	if len(rates) != len(want) {
+		t.Fatalf("got %d rates, want %d: %+v", len(rates), len(want), rates)
+	}
+	for _, rate := range rates {
+		if want[rate.Product] != rate.Rate {
+			t.Errorf("%q = %q, want %q", rate.Product, rate.Rate, want[rate.Product])
+		}
+	}
+}
+
+func TestCheckNotifiesOnChange(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fetcher := fakeFetcher{"savings": "Bonus rate 4.85% p.a.\nBase rate 0.10% p.a."}
+	notifier := &recordingNotifier{}
+	watcher := NewWatcher(fetcher, dataStore, notifier, []string{"savings"}, log.New(io.Discard, "", 0))
+
+	if _, err := watcher.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.sent) != 0 {
+		t.Fatalf("first check should only record rates, sent %+v", notifier.sent)
+	}
+
+	fetcher["savings"] = "Bonus rate 4.60% p.a.\nBase rate 0.10% p.a."
+	changes, err := watcher.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].OldRate != "4.85" || changes[0].NewRate != "4.60" {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0].Event != notify.EventRateChange {
+		t.Fatalf("unexpected notifications %+v", notifier.sent)
+	}
+	if got := notifier.sent[0].Message; got != "Bonus rate down from 4.85% to 4.60% p.a." {
+		t.Errorf("message = %q", got)
+	}
+}
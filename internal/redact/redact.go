@@ -0,0 +1,101 @@
+// Package redact masks usernames, account numbers and dollar amounts in
+// log output, so logs can be shipped and shared without leaking banking
+// details.
+package redact
+
+import (
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Masks written in place of redacted values
+const (
+	secretMask = "[REDACTED]"
+	amountMask = "***"
+)
+
+// visibleDigits is how many trailing digits of an account or card number
+// are kept, enough to tell accounts apart when debugging
+const visibleDigits = 4
+
+var (
+	// accountNumberPattern matches runs of 6 to 19 digits, covering account,
+	// card and member numbers but not times, ports or IDs mixing letters
+	accountNumberPattern = regexp.MustCompile(`\b\d{6,19}\b`)
+
+	// dollarPattern matches amounts written with a dollar sign
+	dollarPattern = regexp.MustCompile(`-?\$\s?-?\d[\d,]*(?:\.\d{1,2})?`)
+
+	// decimalAmountPattern matches amounts with cents and no dollar sign,
+	// such as balances formatted for the API
+	decimalAmountPattern = regexp.MustCompile(`-?\b\d[\d,]*\.\d{2}\b`)
+)
+
+// Redactor masks sensitive values in text: the configured secrets, such as
+// the NAB username, dollar amounts, and all but the last digits of
+// account numbers
+type Redactor struct {
+	secrets *strings.Replacer
+}
+
+// New creates a redactor masking the given secrets as well as account
+// numbers and amounts. Empty secrets are ignored.
+func New(secrets ...string) *Redactor {
+	var pairs []string
+	for _, secret := range secrets {
+		if secret = strings.TrimSpace(secret); secret != "" {
+			pairs = append(pairs, secret, secretMask)
+		}
+	}
+	return &Redactor{secrets: strings.NewReplacer(pairs...)}
+}
+
+// String returns text with sensitive values masked
+func (r *Redactor) String(text string) string {
+	text = r.secrets.Replace(text)
+	text = dollarPattern.ReplaceAllString(text, "$$"+amountMask)
+	text = replaceAmounts(text)
+	return accountNumberPattern.ReplaceAllStringFunc(text, func(number string) string {
+		return strings.Repeat("*", visibleDigits) + number[len(number)-visibleDigits:]
+	})
+}
+
+// replaceAmounts masks decimal amounts, leaving alone numbers that are part
+// of a longer dotted run such as an IP address or version
+func replaceAmounts(text string) string {
+	var out strings.Builder
+	last := 0
+	for _, match := range decimalAmountPattern.FindAllStringIndex(text, -1) {
+		start, end := match[0], match[1]
+		if (start > 0 && text[start-1] == '.') || (end < len(text) && text[end] == '.') {
+			continue
+		}
+		out.WriteString(text[last:start])
+		out.WriteString(amountMask)
+		last = end
+	}
+	out.WriteString(text[last:])
+	return out.String()
+}
+
+// Writer returns a writer that masks sensitive values in everything
+// written before passing it on. Each log line is a single write, so
+// values aren't split across writes.
+func (r *Redactor) Writer(w io.Writer) io.Writer {
+	return &writer{redactor: r, out: w}
+}
+
+// writer redacts writes to out
+type writer struct {
+	redactor *Redactor
+	out      io.Writer
+}
+
+// Write redacts p and writes it on, reporting all of p as written
+func (w *writer) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.out, w.redactor.String(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
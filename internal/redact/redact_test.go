@@ -0,0 +1,42 @@
+package redact
+
+import (
+	"bytes"
+	"log"
+	"testing"
+)
+
+func TestRedactorString(t *testing.T) {
+	redactor := New("jcitizen", "")
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"username", "Logging in as jcitizen", "Logging in as [REDACTED]"},
+		{"account number", "Scraping transactions for account 12345678...", "Scraping transactions for account ****5678..."},
+		{"card number", "card 4111111111111111 locked", "card ****1111 locked"},
+		{"dollar amount", "amount exceeds available balance of $1,234.50", "amount exceeds available balance of $***"},
+		{"balance", "expected 2543.67, scraped -12.00", "expected ***, scraped ***"},
+		{"path", "GET /api/v1/accounts/12345678?minBalance=100.00 10.0.0.12:51234", "GET /api/v1/accounts/****5678?minBalance=*** 10.0.0.12:51234"},
+		{"ids and times", "job_911df2b2bf935054 finished in 1.50s at 2024/05/17 09:30:00", "job_911df2b2bf935054 finished in 1.50s at 2024/05/17 09:30:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactor.String(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactorWriter(t *testing.T) {
+	var out bytes.Buffer
+	logger := log.New(New("jcitizen").Writer(&out), "", 0)
+	logger.Printf("Balance mismatch for jcitizen's account %s: $%s", "12345678", "10.00")
+
+	if got, want := out.String(), "Balance mismatch for [REDACTED]'s account ****5678: $***\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
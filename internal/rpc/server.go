@@ -0,0 +1,168 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	nabbankv1 "github.com/benrowe/nab-bank-api/api/proto/nabbank/v1"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// NewServer creates a gRPC server exposing the account and transaction
+// services, with reflection enabled for tools like grpcurl
+func NewServer(accountService service.AccountService, logger *log.Logger) *grpc.Server {
+	server := grpc.NewServer(grpc.UnaryInterceptor(loggingInterceptor(logger)))
+
+	nabbankv1.RegisterAccountServiceServer(server, &accountServer{accountService: accountService})
+	nabbankv1.RegisterTransactionServiceServer(server, &transactionServer{accountService: accountService})
+	reflection.Register(server)
+
+	return server
+}
+
+// accountServer implements nabbankv1.AccountServiceServer
+type accountServer struct {
+	nabbankv1.UnimplementedAccountServiceServer
+	accountService service.AccountService
+}
+
+// ListAccounts returns all bank accounts
+func (s *accountServer) ListAccounts(ctx context.Context, req *nabbankv1.ListAccountsRequest) (*nabbankv1.ListAccountsResponse, error) {
+	accounts, err := s.accountService.GetAllAccounts(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	response := &nabbankv1.ListAccountsResponse{
+		Accounts:    make([]*nabbankv1.Account, 0, len(accounts)),
+		RetrievedAt: timestamppb.Now(),
+		Count:       int32(len(accounts)),
+	}
+	for i := range accounts {
+		response.Accounts = append(response.Accounts, toAccount(&accounts[i]))
+	}
+
+	return response, nil
+}
+
+// GetAccount returns an account with its recent transactions
+func (s *accountServer) GetAccount(ctx context.Context, req *nabbankv1.GetAccountRequest) (*nabbankv1.GetAccountResponse, error) {
+	if req.GetAccountId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "account_id is required")
+	}
+
+	details, err := s.accountService.GetAccountDetails(ctx, req.GetAccountId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &nabbankv1.GetAccountResponse{
+		Account: &nabbankv1.AccountDetails{
+			Account:                toAccount(&details.Account),
+			Transactions:           toTransactions(details.Transactions),
+			RecentTransactionCount: int32(details.RecentTransactionCount),
+		},
+	}, nil
+}
+
+// transactionServer implements nabbankv1.TransactionServiceServer
+type transactionServer struct {
+	nabbankv1.UnimplementedTransactionServiceServer
+	accountService service.AccountService
+}
+
+// ListTransactions returns the recent transactions for an account
+func (s *transactionServer) ListTransactions(ctx context.Context, req *nabbankv1.ListTransactionsRequest) (*nabbankv1.ListTransactionsResponse, error) {
+	if req.GetAccountId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "account_id is required")
+	}
+
+	details, err := s.accountService.GetAccountDetails(ctx, req.GetAccountId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	transactions := details.Transactions
+	if limit := int(req.GetLimit()); limit > 0 && limit < len(transactions) {
+		transactions = transactions[:limit]
+	}
+
+	return &nabbankv1.ListTransactionsResponse{
+		Transactions: toTransactions(transactions),
+	}, nil
+}
+
+// statusError maps service errors onto gRPC status codes
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrAccountNotFound):
+		return status.Error(codes.NotFound, "account not found")
+	case errors.Is(err, service.ErrServiceUnavailable):
+		return status.Error(codes.Unavailable, "service temporarily unavailable")
+	case errors.Is(err, service.ErrAuthenticationFailed):
+		return status.Error(codes.Unauthenticated, "authentication failed")
+	case errors.Is(err, service.ErrChallengeRequired):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// loggingInterceptor logs each unary call, mirroring the HTTP logging middleware
+func loggingInterceptor(logger *log.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logger.Printf("gRPC %s %s (%s)", info.FullMethod, status.Code(err), time.Since(start))
+		return resp, err
+	}
+}
+
+func toAccount(account *model.Account) *nabbankv1.Account {
+	pb := &nabbankv1.Account{
+		Id:               account.ID,
+		Name:             account.Name,
+		Type:             account.Type,
+		Balance:          toMoney(&account.Balance),
+		AvailableBalance: toMoney(account.AvailableBalance),
+		AccountNumber:    account.AccountNumber,
+		Bsb:              account.BSB,
+	}
+	if account.LastUpdated != nil {
+		pb.LastUpdated = timestamppb.New(*account.LastUpdated)
+	}
+
+	return pb
+}
+
+func toTransactions(transactions []model.Transaction) []*nabbankv1.Transaction {
+	pb := make([]*nabbankv1.Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		pb = append(pb, &nabbankv1.Transaction{
+			Id:          transaction.ID,
+			Date:        transaction.Date,
+			Description: transaction.Description,
+			Amount:      toMoney(&transaction.Amount),
+			Balance:     toMoney(&transaction.Balance),
+			Category:    transaction.Category,
+			Merchant:    transaction.Merchant,
+		})
+	}
+
+	return pb
+}
+
+func toMoney(money *model.Money) *nabbankv1.Money {
+	if money == nil {
+		return nil
+	}
+	return &nabbankv1.Money{Amount: money.Amount, Currency: money.CurrencyCode()}
+}
@@ -0,0 +1,79 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"testing"
+
+	nabbankv1 "github.com/benrowe/nab-bank-api/api/proto/nabbank/v1"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/benrowe/nab-bank-api/internal/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountService := service.NewAccountService(service.NewMockNABClient(), dataStore, nil, service.AlertThresholds{}, service.RetryPolicy{}, service.BreakerPolicy{}, service.CachePolicy{}, nil, nil)
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(accountService, log.New(io.Discard, "", 0))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func TestListAccounts(t *testing.T) {
+	client := nabbankv1.NewAccountServiceClient(newTestClient(t))
+
+	resp, err := client.ListAccounts(context.Background(), &nabbankv1.ListAccountsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.GetCount() != 6 || len(resp.GetAccounts()) != 6 {
+		t.Fatalf("expected 6 accounts, got %d", resp.GetCount())
+	}
+	if balance := resp.GetAccounts()[0].GetBalance(); balance.GetAmount() != "2543.67" || balance.GetCurrency() != "AUD" {
+		t.Errorf("unexpected balance %v", balance)
+	}
+}
+
+func TestListTransactions(t *testing.T) {
+	client := nabbankv1.NewTransactionServiceClient(newTestClient(t))
+
+	resp, err := client.ListTransactions(context.Background(), &nabbankv1.ListTransactionsRequest{AccountId: "12345678", Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.GetTransactions()) != 2 {
+		t.Errorf("expected 2 transactions, got %d", len(resp.GetTransactions()))
+	}
+
+	_, err = client.ListTransactions(context.Background(), &nabbankv1.ListTransactionsRequest{AccountId: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+}
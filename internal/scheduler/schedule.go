@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	// Presets are evaluated in Australian time, which minimal containers
+	// don't ship zone data for
+	_ "time/tzdata"
+)
+
+// Schedule decides when the next sync runs
+type Schedule interface {
+	// Next returns the first sync time after t
+	Next(t time.Time) time.Time
+}
+
+// Every is a schedule running at a fixed interval
+type Every time.Duration
+
+// Next returns t plus the interval
+func (e Every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// window refreshes every interval between start and end hours on the
+// given days. An end of 24 runs to midnight.
+type window struct {
+	days     []time.Weekday
+	start    int
+	end      int
+	interval time.Duration
+}
+
+// windowSchedule runs syncs inside windows of local time, and not at all
+// outside them
+type windowSchedule struct {
+	location *time.Location
+	windows  []window
+}
+
+var (
+	weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	weekends = []time.Weekday{time.Saturday, time.Sunday}
+)
+
+// schedulePresets are the named schedules accepted by SYNC_SCHEDULE. NAB
+// posts most transactions in batches on weekday evenings, so "smart"
+// refreshes often then and rarely overnight or at weekends.
+var schedulePresets = map[string][]window{
+	"smart": {
+		{days: weekdays, start: 7, end: 17, interval: 2 * time.Hour},
+		{days: weekdays, start: 17, end: 23, interval: 30 * time.Minute},
+		{days: weekends, start: 9, end: 21, interval: 6 * time.Hour},
+	},
+	"light": {
+		{days: weekdays, start: 7, end: 8, interval: time.Hour},
+		{days: weekdays, start: 21, end: 22, interval: time.Hour},
+		{days: weekends, start: 12, end: 13, interval: time.Hour},
+	},
+}
+
+// SchedulePresets lists the preset names, for config validation and help
+func SchedulePresets() []string {
+	return []string{"smart", "light"}
+}
+
+// ParseSchedule returns the named preset evaluated in the given time zone
+func ParseSchedule(name, timezone string) (Schedule, error) {
+	windows, ok := schedulePresets[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("unknown sync schedule %q, expected one of %s", name, strings.Join(SchedulePresets(), ", "))
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sync time zone %q: %w", timezone, err)
+	}
+	return &windowSchedule{location: location, windows: windows}, nil
+}
+
+// Next returns the earliest slot after t across all windows, looking up
+// to a week ahead
+func (s *windowSchedule) Next(t time.Time) time.Time {
+	local := t.In(s.location)
+	var next time.Time
+	for offset := 0; offset <= 7; offset++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, s.location)
+		for _, w := range s.windows {
+			if !w.runsOn(day.Weekday()) {
+				continue
+			}
+			if slot, ok := w.nextSlot(day, local); ok && (next.IsZero() || slot.Before(next)) {
+				next = slot
+			}
+		}
+		if !next.IsZero() {
+			return next
+		}
+	}
+	return t.Add(24 * time.Hour)
+}
+
+// runsOn reports whether the window applies on a weekday
+func (w window) runsOn(weekday time.Weekday) bool {
+	for _, day := range w.days {
+		if day == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// nextSlot returns the first slot in the window on day that is after t
+func (w window) nextSlot(day, t time.Time) (time.Time, bool) {
+	start := time.Date(day.Year(), day.Month(), day.Day(), w.start, 0, 0, 0, day.Location())
+	end := time.Date(day.Year(), day.Month(), day.Day(), w.end, 0, 0, 0, day.Location())
+	for slot := start; slot.Before(end); slot = slot.Add(w.interval) {
+		if slot.After(t) {
+			return slot, true
+		}
+	}
+	return time.Time{}, false
+}
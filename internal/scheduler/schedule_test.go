@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSmartScheduleNext(t *testing.T) {
+	schedule, err := ParseSchedule("smart", "Australia/Melbourne")
+	if err != nil {
+		t.Fatal(err)
+	}
+	melbourne, _ := time.LoadLocation("Australia/Melbourne")
+	at := func(day, hour, minute int) time.Time {
+		// October 2026 starts on a Thursday
+		return time.Date(2026, time.October, day, hour, minute, 0, 0, melbourne)
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"weekday daytime", at(14, 9, 10), at(14, 11, 0)},
+		{"into the evening window", at(14, 15, 30), at(14, 17, 0)},
+		{"weekday evening", at(14, 19, 5), at(14, 19, 30)},
+		{"overnight", at(14, 23, 10), at(15, 7, 0)},
+		{"friday night to saturday", at(16, 23, 45), at(17, 9, 0)},
+		{"weekend", at(17, 10, 0), at(17, 15, 0)},
+		{"sunday night to monday", at(18, 21, 0), at(19, 7, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schedule.Next(tt.now); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseScheduleRejectsUnknownPreset(t *testing.T) {
+	if _, err := ParseSchedule("hourly", "Australia/Melbourne"); err == nil {
+		t.Error("expected an error for an unknown preset")
+	}
+	if _, err := ParseSchedule("smart", "Mars/Olympus"); err == nil {
+		t.Error("expected an error for an unknown time zone")
+	}
+}
@@ -0,0 +1,247 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/events"
+	"github.com/benrowe/nab-bank-api/internal/hooks"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// Feed pushes stored data to an external app after each sync
+type Feed interface {
+	Name() string
+	Push(ctx context.Context) error
+}
+
+// Scheduler periodically refreshes every account, calling each account's
+// refresh hooks before and after its scrape, then pushes to any feeds
+type Scheduler struct {
+	accountService service.AccountService
+	store          *store.Store
+	hooks          *hooks.Caller
+	maxDelay       time.Duration
+	concurrency    int
+	events         *events.Broker
+	feeds          []Feed
+	logger         *log.Logger
+}
+
+// NewScheduler creates a scheduler. Delays requested by pre-scrape hooks
+// are capped at maxDelay, and up to concurrency accounts are scraped at
+// once. Each sync's progress is published to the event broker, which may
+// be nil.
+func NewScheduler(accountService service.AccountService, store *store.Store, caller *hooks.Caller, maxDelay time.Duration, concurrency int, broker *events.Broker, logger *log.Logger, feeds ...Feed) *Scheduler {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Scheduler{
+		accountService: accountService,
+		store:          store,
+		hooks:          caller,
+		maxDelay:       maxDelay,
+		concurrency:    concurrency,
+		events:         broker,
+		feeds:          feeds,
+		logger:         logger,
+	}
+}
+
+// Run syncs immediately and then whenever the schedule says, until the
+// context is cancelled
+func (s *Scheduler) Run(ctx context.Context, schedule Schedule) {
+	for {
+		s.SyncOnce(ctx)
+
+		next := schedule.Next(time.Now())
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// accountSync is what a sync refreshes accounts through
+type accountSync interface {
+	Accounts() []model.Account
+	GetAccountDetails(ctx context.Context, accountID string) (*model.AccountDetails, error)
+	Close()
+}
+
+// serviceSync refreshes accounts one GetAccountDetails call at a time, for
+// account services that can't sync over a single login
+type serviceSync struct {
+	service.AccountService
+	accounts []model.Account
+}
+
+func (s serviceSync) Accounts() []model.Account { return s.accounts }
+
+func (s serviceSync) Close() {}
+
+// startSync lists the accounts to refresh, logging in once for the whole
+// sync when the account service can
+func (s *Scheduler) startSync(ctx context.Context) (accountSync, error) {
+	if syncer, ok := s.accountService.(service.SessionSyncer); ok {
+		return syncer.StartSync(ctx)
+	}
+	accounts, err := s.accountService.GetAllAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return serviceSync{AccountService: s.accountService, accounts: accounts}, nil
+}
+
+// SyncOnce refreshes the account list and then each account's details,
+// and pushes the results to the feeds. Where the account service allows,
+// the whole sync is scraped over one login.
+func (s *Scheduler) SyncOnce(ctx context.Context) {
+	progress := s.events.StartSync("", "schedule")
+	progress.Step(model.SyncStepListingAccounts, "")
+	accountSync, err := s.startSync(ctx)
+	if err != nil {
+		s.logger.Printf("Scheduled sync failed to list accounts: %v", err)
+		progress.Completed(0, err)
+		return
+	}
+
+	accounts := accountSync.Accounts()
+	s.syncAccounts(ctx, progress, accountSync, accounts)
+	accountSync.Close()
+	progress.Completed(len(accounts), ctx.Err())
+	if ctx.Err() != nil {
+		return
+	}
+
+	for _, feed := range s.feeds {
+		if err := feed.Push(ctx); err != nil {
+			s.logger.Printf("Pushing to %s failed: %v", feed.Name(), err)
+		}
+	}
+}
+
+// syncAccounts scrapes every account, running up to the concurrency limit
+// at once, and waits for them all to finish
+func (s *Scheduler) syncAccounts(ctx context.Context, progress *events.Sync, accountSync accountSync, accounts []model.Account) {
+	slots := make(chan struct{}, s.concurrency)
+	var wg sync.WaitGroup
+	for _, account := range accounts {
+		select {
+		case <-ctx.Done():
+		case slots <- struct{}{}:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(accountID string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			progress.Step(model.SyncStepScrapingAccount, accountID)
+			if err := s.syncAccount(ctx, accountSync, accountID); err != nil {
+				progress.AccountFailed(accountID, err)
+			}
+		}(account.ID)
+	}
+	wg.Wait()
+}
+
+// syncAccount scrapes one account, honouring its pre-scrape hooks, and
+// returns the scrape's error
+func (s *Scheduler) syncAccount(ctx context.Context, accountSync accountSync, accountID string) error {
+	registered := s.store.Hooks(accountID)
+	scheduledAt := time.Now()
+
+	delay, skip := s.runPreHooks(ctx, registered, accountID, scheduledAt)
+	if skip {
+		s.logger.Printf("Scheduled scrape of account %s skipped by hook", accountID)
+		return nil
+	}
+	if delay > 0 {
+		s.logger.Printf("Scheduled scrape of account %s delayed %s by hook", accountID, delay)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	details, err := accountSync.GetAccountDetails(ctx, accountID)
+	if err != nil {
+		s.logger.Printf("Scheduled scrape of account %s failed: %v", accountID, err)
+	}
+
+	s.runPostHooks(ctx, registered, accountID, scheduledAt, details, err)
+	return err
+}
+
+// runPreHooks calls the pre-scrape hooks. Any hook can skip the scrape;
+// otherwise the longest requested delay wins.
+func (s *Scheduler) runPreHooks(ctx context.Context, registered []model.RefreshHook, accountID string, scheduledAt time.Time) (time.Duration, bool) {
+	var delay time.Duration
+	for _, hook := range registered {
+		if !hooks.HasPhase(hook, model.HookPhasePre) {
+			continue
+		}
+
+		decision, err := s.hooks.Pre(ctx, hook, model.HookPayload{
+			Event:       model.HookEventPreScrape,
+			AccountID:   accountID,
+			ScheduledAt: scheduledAt,
+		})
+		if err != nil {
+			// An unreachable hook shouldn't stop the account being refreshed
+			s.logger.Printf("Pre-scrape hook %s for account %s failed: %v", hook.ID, accountID, err)
+			continue
+		}
+
+		switch decision.Action {
+		case model.HookActionSkip:
+			return 0, true
+		case model.HookActionDelay:
+			if requested := time.Duration(decision.DelaySeconds) * time.Second; requested > delay {
+				delay = requested
+			}
+		}
+	}
+
+	if delay > s.maxDelay {
+		delay = s.maxDelay
+	}
+	return delay, false
+}
+
+// runPostHooks sends the scrape outcome to the post-scrape hooks
+func (s *Scheduler) runPostHooks(ctx context.Context, registered []model.RefreshHook, accountID string, scheduledAt time.Time, details *model.AccountDetails, scrapeErr error) {
+	completedAt := time.Now()
+	success := scrapeErr == nil
+	payload := model.HookPayload{
+		Event:       model.HookEventPostScrape,
+		AccountID:   accountID,
+		ScheduledAt: scheduledAt,
+		Success:     &success,
+		Account:     details,
+		CompletedAt: &completedAt,
+	}
+	if scrapeErr != nil {
+		payload.Error = scrapeErr.Error()
+	}
+
+	for _, hook := range registered {
+		if !hooks.HasPhase(hook, model.HookPhasePost) {
+			continue
+		}
+		if err := s.hooks.Post(ctx, hook, payload); err != nil {
+			s.logger.Printf("Post-scrape hook %s for account %s failed: %v", hook.ID, accountID, err)
+		}
+	}
+}
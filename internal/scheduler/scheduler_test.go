@@ -0,0 +1,179 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/hooks"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/service"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestSyncOnceCallsHooks(t *testing.T) {
+	var mu sync.Mutex
+	var payloads []model.HookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload model.HookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		if r.Header.Get(hooks.SignatureHeader) == "" {
+			t.Error("expected signed payload")
+		}
+
+		mu.Lock()
+		payloads = append(payloads, payload)
+		mu.Unlock()
+
+		if payload.Event == model.HookEventPreScrape && payload.AccountID == "87654321" {
+			json.NewEncoder(w).Encode(model.HookDecision{Action: model.HookActionSkip})
+		}
+	}))
+	defer server.Close()
+
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, accountID := range []string{"12345678", "87654321"} {
+		hook := model.RefreshHook{
+			ID:        "hook_" + accountID,
+			AccountID: accountID,
+			URL:       server.URL,
+			Phases:    []string{model.HookPhasePre, model.HookPhasePost},
+			Secret:    "secret",
+		}
+		if err := dataStore.SaveHook(hook); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	accountService := service.NewAccountService(service.NewMockNABClient(), dataStore, nil, service.AlertThresholds{}, service.RetryPolicy{}, service.BreakerPolicy{}, service.CachePolicy{}, nil, nil)
+	scheduler := NewScheduler(accountService, dataStore, hooks.NewCaller(time.Second), time.Minute, 1, nil, log.New(io.Discard, "", 0))
+	scheduler.SyncOnce(context.Background())
+
+	events := map[string][]string{}
+	for _, payload := range payloads {
+		events[payload.AccountID] = append(events[payload.AccountID], payload.Event)
+		if payload.Event == model.HookEventPostScrape && (payload.Success == nil || !*payload.Success || payload.Account == nil) {
+			t.Errorf("expected successful post-scrape payload, got %+v", payload)
+		}
+	}
+
+	if got := events["12345678"]; len(got) != 2 || got[0] != model.HookEventPreScrape || got[1] != model.HookEventPostScrape {
+		t.Errorf("expected pre and post hooks for 12345678, got %v", got)
+	}
+	if got := events["87654321"]; len(got) != 1 {
+		t.Errorf("expected skipped scrape to only call the pre hook, got %v", got)
+	}
+	if got := len(dataStore.Transactions("87654321")); got != 0 {
+		t.Errorf("expected skipped account to have no transactions, got %d", got)
+	}
+}
+
+// slowClient records how many transaction scrapes run at once
+type slowClient struct {
+	service.MockNABClient
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (c *slowClient) GetAccountTransactions(ctx context.Context, accountID string) ([]model.Transaction, error) {
+	c.mu.Lock()
+	c.inFlight++
+	if c.inFlight > c.peak {
+		c.peak = c.inFlight
+	}
+	c.mu.Unlock()
+
+	time.Sleep(50 * time.Millisecond)
+
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+	return c.MockNABClient.GetAccountTransactions(ctx, accountID)
+}
+
+func TestSyncOnceScrapesAccountsConcurrently(t *testing.T) {
+	for _, concurrency := range []int{1, 2} {
+		dataStore, err := store.Open("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := &slowClient{}
+		accountService := service.NewAccountService(client, dataStore, nil, service.AlertThresholds{}, service.RetryPolicy{}, service.BreakerPolicy{}, service.CachePolicy{}, nil, nil)
+		scheduler := NewScheduler(accountService, dataStore, hooks.NewCaller(time.Second), time.Minute, concurrency, nil, log.New(io.Discard, "", 0))
+		scheduler.SyncOnce(context.Background())
+
+		if client.peak != concurrency {
+			t.Errorf("expected %d scrapes at once, got %d", concurrency, client.peak)
+		}
+		if got := len(dataStore.Transactions("87654321")); got == 0 {
+			t.Errorf("expected account 87654321 to be synced with concurrency %d", concurrency)
+		}
+	}
+}
+
+// sessionClient is a slowClient that logs in once per session, counting
+// its logins and account lists
+type sessionClient struct {
+	slowClient
+	logins int
+	lists  int
+	closed int
+}
+
+func (c *sessionClient) OpenSession(ctx context.Context) (service.ProviderSession, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logins++
+	return clientSession{c}, nil
+}
+
+func (c *sessionClient) GetAccounts(ctx context.Context) ([]model.Account, error) {
+	c.mu.Lock()
+	c.lists++
+	c.mu.Unlock()
+	return c.MockNABClient.GetAccounts(ctx)
+}
+
+// clientSession reads through the client it was opened on
+type clientSession struct {
+	*sessionClient
+}
+
+func (s clientSession) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed++
+}
+
+func TestSyncOnceLogsInOnce(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &sessionClient{}
+	accountService := service.NewAccountService(client, dataStore, nil, service.AlertThresholds{}, service.RetryPolicy{}, service.BreakerPolicy{}, service.CachePolicy{}, nil, nil)
+	scheduler := NewScheduler(accountService, dataStore, hooks.NewCaller(time.Second), time.Minute, 3, nil, log.New(io.Discard, "", 0))
+	scheduler.SyncOnce(context.Background())
+
+	if client.logins != 1 || client.lists != 1 || client.closed != 1 {
+		t.Errorf("expected one login and account list per sync, closed afterwards, got %d logins, %d lists and %d closed", client.logins, client.lists, client.closed)
+	}
+	if client.peak != 3 {
+		t.Errorf("expected 3 scrapes at once in the session, got %d", client.peak)
+	}
+	if got := len(dataStore.Transactions("87654321")); got == 0 {
+		t.Error("expected account 87654321 to be synced")
+	}
+}
@@ -0,0 +1,120 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Index is an inverted index over normalised text, finding the documents
+// that Matches would without checking every one of them
+type Index struct {
+	mu       sync.Mutex
+	postings map[string]map[string]bool
+	docs     map[string][]string
+
+	// words is every indexed word in order, for finding the words a query
+	// word is the start of. It is sorted lazily after changes.
+	words  []string
+	sorted bool
+}
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{
+		postings: make(map[string]map[string]bool),
+		docs:     make(map[string][]string),
+		sorted:   true,
+	}
+}
+
+// Add indexes a document's normalised text, replacing what was indexed
+// for it before
+func (i *Index) Add(id, searchText string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(id)
+	words := strings.Fields(searchText)
+	i.docs[id] = words
+	for _, word := range words {
+		docs, ok := i.postings[word]
+		if !ok {
+			docs = make(map[string]bool)
+			i.postings[word] = docs
+			i.words = append(i.words, word)
+			i.sorted = false
+		}
+		docs[id] = true
+	}
+}
+
+// Remove drops a document from the index
+func (i *Index) Remove(id string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(id)
+}
+
+// remove drops a document with the lock held. Words left without
+// documents stay in the word list until it is next rebuilt.
+func (i *Index) remove(id string) {
+	for _, word := range i.docs[id] {
+		delete(i.postings[word], id)
+	}
+	delete(i.docs, id)
+}
+
+// Search returns the IDs of documents containing every word of the query,
+// each matching the start of a word, in no particular order
+func (i *Index) Search(query string) []string {
+	queryWords := strings.Fields(Normalize(query))
+	if len(queryWords) == 0 {
+		return nil
+	}
+
+	// Searching may sort the word list, so it takes the write lock
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if !i.sorted {
+		i.rebuildWords()
+	}
+
+	var matches map[string]bool
+	for _, queryWord := range queryWords {
+		found := make(map[string]bool)
+		for at := sort.SearchStrings(i.words, queryWord); at < len(i.words) && strings.HasPrefix(i.words[at], queryWord); at++ {
+			for id := range i.postings[i.words[at]] {
+				if matches == nil || matches[id] {
+					found[id] = true
+				}
+			}
+		}
+		matches = found
+		if len(matches) == 0 {
+			return nil
+		}
+	}
+
+	ids := make([]string, 0, len(matches))
+	for id := range matches {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// rebuildWords sorts the word list, dropping words no document has
+func (i *Index) rebuildWords() {
+	words := i.words[:0]
+	for _, word := range i.words {
+		if len(i.postings[word]) > 0 {
+			words = append(words, word)
+		} else {
+			delete(i.postings, word)
+		}
+	}
+	sort.Strings(words)
+	i.words = words
+	i.sorted = true
+}
@@ -0,0 +1,48 @@
+package search
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+	index := NewIndex()
+	index.Add("coles", Normalize("EFTPOS PURCH COLES 0456 CARD XX4521"))
+	index.Add("woolworths", Normalize("EFTPOS Purchase - WOOLWORTHS"))
+	index.Add("transfer", Normalize("TFR TO J SMITH REF 99231"))
+
+	search := func(query string) []string {
+		ids := index.Search(query)
+		sort.Strings(ids)
+		return ids
+	}
+	for query, want := range map[string][]string{
+		"coles":            {"coles"},
+		"eftpos purchase":  {"coles", "woolworths"},
+		"wool":             {"woolworths"},
+		"tfr smith":        {"transfer"},
+		"coles woolworths": nil,
+		"":                 nil,
+	} {
+		got := search(query)
+		if len(got) != len(want) {
+			t.Errorf("Search(%q) = %v, want %v", query, got, want)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("Search(%q) = %v, want %v", query, got, want)
+			}
+		}
+	}
+
+	// Re-adding replaces, and removed documents are no longer found
+	index.Add("coles", Normalize("Direct Debit NETFLIX"))
+	index.Remove("woolworths")
+	if got := search("eftpos"); len(got) != 0 {
+		t.Errorf("expected no eftpos matches left, got %v", got)
+	}
+	if got := search("netflix"); len(got) != 1 {
+		t.Errorf("expected the replaced document to be found by its new text, got %v", got)
+	}
+}
@@ -0,0 +1,124 @@
+// Package search normalises transaction descriptions so searches and
+// rules match however NAB happened to word, abbreviate or number them.
+package search
+
+import (
+	"regexp"
+	"strings"
+)
+
+// phraseAbbreviations are expanded before punctuation is stripped, as
+// they contain it
+var phraseAbbreviations = strings.NewReplacer(
+	"w/d", " withdrawal ",
+	"a/c", " account ",
+	"o/s", " overseas ",
+	"c/o", " care of ",
+)
+
+// wordAbbreviations expands the short forms NAB and merchants use
+var wordAbbreviations = map[string]string{
+	"tfr":   "transfer",
+	"trf":   "transfer",
+	"trfr":  "transfer",
+	"xfer":  "transfer",
+	"wdl":   "withdrawal",
+	"wdr":   "withdrawal",
+	"dep":   "deposit",
+	"pmt":   "payment",
+	"pymt":  "payment",
+	"pay":   "payment",
+	"chq":   "cheque",
+	"int":   "interest",
+	"dd":    "direct debit",
+	"sal":   "salary",
+	"purch": "purchase",
+	"intl":  "international",
+	"inet":  "internet",
+	"ib":    "internet banking",
+	"cr":    "credit",
+	"dr":    "debit",
+	"fx":    "foreign exchange",
+	"acct":  "account",
+}
+
+var (
+	// punctuationPattern matches runs of anything but letters and digits
+	punctuationPattern = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
+	// referencePattern matches tokens that are reference, receipt and card
+	// numbers rather than words: four or more digits, or masked cards
+	referencePattern = regexp.MustCompile(`^(?:[a-z]{0,3}\d{4,}[a-z\d]*|x+\d+|\d+x+\d*)$`)
+)
+
+// referenceLabels are words that only introduce a reference number
+var referenceLabels = map[string]bool{
+	"ref":       true,
+	"reference": true,
+	"receipt":   true,
+	"card":      true,
+	"no":        true,
+}
+
+// Normalize folds a description into its searchable form: lower case,
+// abbreviations expanded, reference and card numbers removed, and words
+// separated by single spaces. "TFR TO J SMITH REF 99231" and "Transfer
+// to J Smith" both become "transfer to j smith".
+func Normalize(description string) string {
+	text := strings.ToLower(description)
+	text = phraseAbbreviations.Replace(text)
+	tokens := strings.Fields(punctuationPattern.ReplaceAllString(text, " "))
+
+	words := make([]string, 0, len(tokens))
+	for i, token := range tokens {
+		if referencePattern.MatchString(token) {
+			continue
+		}
+		if referenceLabels[token] && labelsReference(tokens[i+1:]) {
+			continue
+		}
+		if expanded, ok := wordAbbreviations[token]; ok {
+			token = expanded
+		}
+		words = append(words, token)
+	}
+	return strings.Join(words, " ")
+}
+
+// labelsReference reports whether the tokens after a reference label are
+// more labels followed by a reference number, or nothing
+func labelsReference(rest []string) bool {
+	for _, token := range rest {
+		if referencePattern.MatchString(token) {
+			return true
+		}
+		if !referenceLabels[token] {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches reports whether normalised text contains every word of the
+// query, each matching the start of a word so "wool" finds "woolworths"
+func Matches(searchText, query string) bool {
+	queryWords := strings.Fields(Normalize(query))
+	if len(queryWords) == 0 {
+		return false
+	}
+
+	words := strings.Fields(searchText)
+	for _, queryWord := range queryWords {
+		found := false
+		for _, word := range words {
+			if strings.HasPrefix(word, queryWord) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,41 @@
+package search
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		description string
+		want        string
+	}{
+		{"TFR TO J SMITH REF 99231", "transfer to j smith"},
+		{"Transfer  to J Smith", "transfer to j smith"},
+		{"ATM W/D 7-ELEVEN 1234 MELBOURNE", "atm withdrawal 7 eleven melbourne"},
+		{"EFTPOS PURCH WOOLWORTHS 3021 CARD XX4521", "eftpos purchase woolworths"},
+		{"INET PMT BPAY Receipt No. 445566", "internet payment bpay"},
+		{"DD NETFLIX.COM", "direct debit netflix com"},
+		{"Salary ACME PTY LTD", "salary acme pty ltd"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.description); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.description, got, tt.want)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	text := Normalize("EFTPOS PURCH WOOLWORTHS 3021 CARD XX4521")
+	for query, want := range map[string]bool{
+		"woolworths":        true,
+		"wool purchase":     true,
+		"purch WOOLWORTHS":  true,
+		"coles":             false,
+		"woolworths 3021":   true,
+		"":                  false,
+		"eftpos woolworths": true,
+	} {
+		if got := Matches(text, query); got != want {
+			t.Errorf("Matches(%q, %q) = %v, want %v", text, query, got, want)
+		}
+	}
+}
@@ -3,9 +3,15 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"time"
 
+	"github.com/benrowe/nab-bank-api/internal/enrich"
+	"github.com/benrowe/nab-bank-api/internal/events"
 	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/notify"
+	"github.com/benrowe/nab-bank-api/internal/store"
 )
 
 // AccountService defines the interface for account operations
@@ -19,50 +25,326 @@ var (
 	ErrAccountNotFound      = errors.New("account not found")
 	ErrServiceUnavailable   = errors.New("service unavailable")
 	ErrAuthenticationFailed = errors.New("authentication failed")
+
+	// ErrTermsAcceptanceRequired is returned when NAB shows updated terms
+	// and conditions that must be accepted before continuing
+	ErrTermsAcceptanceRequired = errors.New("NAB terms and conditions must be accepted")
+
+	// ErrScrapingPaused is returned while scraping is paused waiting for
+	// the terms and conditions to be accepted
+	ErrScrapingPaused = errors.New("scraping paused")
+
+	// ErrRequestAborted is returned when a scrape stops part way because
+	// the request it was for was cancelled or ran out of time
+	ErrRequestAborted = errors.New("request cancelled or timed out")
 )
 
 // accountService implements AccountService
 type accountService struct {
-	nabClient NABClient
+	nabClient  BankProvider
+	store      *store.Store
+	alerts     *alerter
+	retry      RetryPolicy
+	breaker    *breaker
+	scrapes    scrapeTracker
+	cacheMu    sync.RWMutex
+	cache      CachePolicy
+	refreshes  revalidator
+	enricher   MerchantEnricher
+	events     *events.Broker
+	cleared    cacheClearing
+	cacheStats cacheStats
 }
 
-// NABClient defines the interface for interacting with NAB's website
-type NABClient interface {
+// BankProvider defines the interface for reading a bank's accounts. NAB is
+// read by scraping its website or through the CDR; other banks can be
+// added as providers alongside it. Providers may also implement optional
+// interfaces such as LoanDetailsClient or IncrementalTransactionClient.
+type BankProvider interface {
 	GetAccounts(ctx context.Context) ([]model.Account, error)
 	GetAccountTransactions(ctx context.Context, accountID string) ([]model.Transaction, error)
+	GetMessages(ctx context.Context) ([]model.Message, error)
+	GetPayees(ctx context.Context) ([]model.Payee, error)
+	GetScheduledPayments(ctx context.Context) ([]model.ScheduledPayment, error)
+}
+
+// LoanDetailsClient is implemented by NAB clients that can scrape home loan
+// details
+type LoanDetailsClient interface {
+	GetLoanDetails(ctx context.Context, accountID string) (*model.LoanDetails, error)
+}
+
+// MerchantEnricher fills in the merchants behind newly scraped
+// transactions before they are stored
+type MerchantEnricher interface {
+	Enrich(ctx context.Context, transactions []model.Transaction)
 }
 
-// NewAccountService creates a new account service
-func NewAccountService(nabClient NABClient) AccountService {
+// IncrementalTransactionClient is implemented by NAB clients that can
+// scrape only the transactions on or after a date, sparing the page loads
+// of an account's full history
+type IncrementalTransactionClient interface {
+	GetAccountTransactionsSince(ctx context.Context, accountID string, since string) ([]model.Transaction, error)
+}
+
+// syncOverlapDays is how far before an account's newest stored transaction
+// an incremental sync starts, so transactions NAB posts late with an
+// earlier date are still picked up. Fingerprints match the repeats to
+// the transactions already stored.
+const syncOverlapDays = 7
+
+// NewAccountService creates a new account service. Scraped data is recorded
+// in the store, and alerts are pushed to the notifier when the given
+// thresholds are crossed; a nil notifier disables them. Failed scrapes are
+// retried according to the retry policy, and after repeated failures the
+// breaker policy stops scraping for a while, serving the stored accounts
+// instead where there are any. The cache policy decides when recently
+// stored data is served without scraping. Scraped transactions are passed
+// through the merchant enricher; a nil enricher disables enrichment.
+// Transactions seen for the first time are published to the event broker,
+// which may be nil.
+func NewAccountService(nabClient BankProvider, store *store.Store, notifier notify.Notifier, thresholds AlertThresholds, retryPolicy RetryPolicy, breakerPolicy BreakerPolicy, cachePolicy CachePolicy, enricher MerchantEnricher, broker *events.Broker) AccountService {
 	return &accountService{
 		nabClient: nabClient,
+		store:     store,
+		alerts:    newAlerter(notifier, thresholds),
+		retry:     retryPolicy,
+		breaker:   newBreaker(breakerPolicy),
+		cache:     cachePolicy,
+		enricher:  enricher,
+		events:    broker,
+	}
+}
+
+// scrape runs a scrape through the circuit breaker, retrying failures. A
+// scrape cut short by its request being cancelled or timing out fails
+// with ErrRequestAborted and isn't held against NAB.
+func scrape[T any](ctx context.Context, s *accountService, fn func() (T, error)) (T, error) {
+	if err := s.breaker.allow(); err != nil {
+		var none T
+		return none, err
+	}
+	result, err := retry(ctx, s.retry, fn)
+	if err != nil && ctx.Err() != nil && !errors.Is(err, ErrRequestAborted) {
+		err = fmt.Errorf("%w: %w", ErrRequestAborted, ctx.Err())
+	}
+	s.breaker.record(err)
+	s.scrapes.record(err)
+	return result, err
+}
+
+// accountReader reads accounts and their transactions: the bank provider,
+// or a session logged in to it
+type accountReader interface {
+	GetAccounts(ctx context.Context) ([]model.Account, error)
+	GetAccountTransactions(ctx context.Context, accountID string) ([]model.Transaction, error)
+}
+
+// getAccounts scrapes the account list, stamping each account with when
+// it was scraped
+func (s *accountService) getAccounts(ctx context.Context) ([]model.Account, error) {
+	return s.listAccounts(ctx, s.nabClient)
+}
+
+// listAccounts scrapes the account list through reader, stamping each
+// account with when it was scraped
+func (s *accountService) listAccounts(ctx context.Context, reader accountReader) ([]model.Account, error) {
+	return scrapeShared(ctx, s, "accounts", func() ([]model.Account, error) {
+		accounts, err := reader.GetAccounts(ctx)
+		now := time.Now()
+		for i := range accounts {
+			accounts[i].LastUpdated = &now
+		}
+		return accounts, err
+	})
+}
+
+// cachedAccounts returns the stored accounts, marked as cached
+func (s *accountService) cachedAccounts() []model.Account {
+	accounts := s.store.Accounts()
+	for i := range accounts {
+		accounts[i].Cached = true
+		applyLocalState(s.store, &accounts[i])
+	}
+	return accounts
+}
+
+// cachedAccountDetails builds account details from the store while the
+// circuit is open, failing with the circuit error if nothing is stored
+func (s *accountService) cachedAccountDetails(accountID string, circuitErr error) (*model.AccountDetails, error) {
+	accounts := s.cachedAccounts()
+	if len(accounts) == 0 {
+		return nil, circuitErr
+	}
+
+	for _, account := range accounts {
+		if account.ID == accountID {
+			return s.storedAccountDetails(account), nil
+		}
+	}
+	return nil, ErrAccountNotFound
+}
+
+// storedAccountDetails builds account details from a stored account and
+// its stored transactions
+func (s *accountService) storedAccountDetails(account model.Account) *model.AccountDetails {
+	transactions := s.store.Transactions(account.ID)
+	asOf := time.Now()
+	if account.LastUpdated != nil {
+		asOf = *account.LastUpdated
+	}
+	return &model.AccountDetails{
+		Account:                account,
+		Transactions:           transactions,
+		RecentTransactionCount: len(transactions),
+		Trend:                  balanceTrend(s.store.BalanceHistory(account.ID), account.Balance, asOf),
+		SyncWarnings:           checkRunningBalances(transactions),
+	}
+}
+
+// freshAccounts returns the stored accounts when the cache policy allows
+// serving them instead of scraping. Stale accounts are marked as such and
+// refreshed in the background.
+func (s *accountService) freshAccounts() ([]model.Account, bool) {
+	accounts := s.store.Accounts()
+	if len(accounts) == 0 {
+		return nil, false
+	}
+	var scrapedAt time.Time
+	for _, account := range accounts {
+		if account.LastUpdated == nil {
+			return nil, false
+		}
+		if scrapedAt.IsZero() || account.LastUpdated.Before(scrapedAt) {
+			scrapedAt = *account.LastUpdated
+		}
+	}
+
+	freshness := s.freshness(accountsCacheKey, scrapedAt)
+	if freshness == freshnessExpired {
+		return nil, false
+	}
+	for i := range accounts {
+		applyLocalState(s.store, &accounts[i])
+		accounts[i].Stale = freshness == freshnessStale
+	}
+	if freshness == freshnessStale {
+		s.refreshes.start(accountsCacheKey, func(ctx context.Context) {
+			s.scrapeAccounts(ctx)
+		})
+	}
+	return accounts, true
+}
+
+// freshAccountDetails returns an account's stored details when the cache
+// policy allows serving them instead of scraping, going by whichever of
+// the account and its transactions was scraped longer ago. Stale details
+// are marked as such and refreshed in the background.
+func (s *accountService) freshAccountDetails(accountID string) (*model.AccountDetails, bool) {
+	transactionsAt, ok := s.store.TransactionsUpdatedAt(accountID)
+	if !ok {
+		return nil, false
+	}
+	for _, account := range s.store.Accounts() {
+		if account.ID != accountID || account.LastUpdated == nil {
+			continue
+		}
+		scrapedAt := *account.LastUpdated
+		if transactionsAt.Before(scrapedAt) {
+			scrapedAt = transactionsAt
+		}
+
+		freshness := s.freshness(accountCacheKey(accountID), scrapedAt)
+		if freshness == freshnessExpired {
+			return nil, false
+		}
+		applyLocalState(s.store, &account)
+		account.Stale = freshness == freshnessStale
+		account.LastUpdated = &scrapedAt
+		if freshness == freshnessStale {
+			s.refreshes.start(accountCacheKey(accountID), func(ctx context.Context) {
+				s.scrapeAccountDetails(ctx, accountID)
+			})
+		}
+		return s.storedAccountDetails(account), true
 	}
+	return nil, false
 }
 
-// GetAllAccounts retrieves all accounts from NAB
+// GetAllAccounts retrieves all accounts from NAB, or from the store when
+// they were scraped recently enough
 func (s *accountService) GetAllAccounts(ctx context.Context) ([]model.Account, error) {
-	accounts, err := s.nabClient.GetAccounts(ctx)
+	if accounts, ok := s.freshAccounts(); ok {
+		s.cacheStats.record(accountsCacheKey, servedFreshness(accounts[0].Stale))
+		return accounts, nil
+	}
+	s.cacheStats.record(accountsCacheKey, freshnessExpired)
+	return s.scrapeAccounts(ctx)
+}
+
+// scrapeAccounts scrapes and stores all accounts
+func (s *accountService) scrapeAccounts(ctx context.Context) ([]model.Account, error) {
+	accounts, err := s.getAccounts(ctx)
+	return s.storeAccounts(accounts, err)
+}
+
+// storeAccounts stores a scraped account list and checks it for alerts.
+// While the circuit is open the stored accounts are returned instead.
+func (s *accountService) storeAccounts(accounts []model.Account, err error) ([]model.Account, error) {
+	if errors.Is(err, ErrCircuitOpen) {
+		if cached := s.cachedAccounts(); len(cached) > 0 {
+			return cached, nil
+		}
+		return nil, err
+	}
 	if err != nil {
+		s.alerts.scrapeFailed(err)
 		return nil, err
 	}
 
-	// Update last updated timestamp
 	now := time.Now()
+	if err := s.store.RecordAccounts(accounts, now); err != nil {
+		return nil, err
+	}
 	for i := range accounts {
-		accounts[i].LastUpdated = &now
+		applyLocalState(s.store, &accounts[i])
 	}
 
+	s.alerts.checkAccounts(accounts)
+
 	return accounts, nil
 }
 
-// GetAccountDetails retrieves detailed account information including transactions
+// GetAccountDetails retrieves detailed account information including
+// transactions, from the store when they were scraped recently enough
 func (s *accountService) GetAccountDetails(ctx context.Context, accountID string) (*model.AccountDetails, error) {
+	if details, ok := s.freshAccountDetails(accountID); ok {
+		s.cacheStats.record(accountCacheKey(accountID), servedFreshness(details.Account.Stale))
+		return details, nil
+	}
+	s.cacheStats.record(accountCacheKey(accountID), freshnessExpired)
+	return s.scrapeAccountDetails(ctx, accountID)
+}
+
+// scrapeAccountDetails scrapes an account and its transactions, storing
+// the transactions
+func (s *accountService) scrapeAccountDetails(ctx context.Context, accountID string) (*model.AccountDetails, error) {
 	// First get all accounts to find the requested one
-	accounts, err := s.nabClient.GetAccounts(ctx)
+	accounts, err := s.getAccounts(ctx)
+	if errors.Is(err, ErrCircuitOpen) {
+		return s.cachedAccountDetails(accountID, err)
+	}
 	if err != nil {
+		s.alerts.scrapeFailed(err)
 		return nil, err
 	}
+	return s.scrapeListedAccountDetails(ctx, s.nabClient, accounts, accountID, true)
+}
 
+// scrapeListedAccountDetails scrapes the transactions of one of the listed
+// accounts through reader, storing them. With storeBalances set and
+// caching on, the listed accounts' balances are stored as well.
+func (s *accountService) scrapeListedAccountDetails(ctx context.Context, reader accountReader, accounts []model.Account, accountID string, storeBalances bool) (*model.AccountDetails, error) {
 	var targetAccount *model.Account
 	for _, account := range accounts {
 		if account.ID == accountID {
@@ -75,21 +357,134 @@ func (s *accountService) GetAccountDetails(ctx context.Context, accountID string
 		return nil, ErrAccountNotFound
 	}
 
-	// Get transactions for this account
-	transactions, err := s.nabClient.GetAccountTransactions(ctx, accountID)
+	// Get transactions for this account, only those since the last sync
+	// once there is one
+	since, incremental := s.syncSince(accountID)
+	transactions, err := scrapeShared(ctx, s, "transactions:"+accountID+":"+since, func() ([]model.Transaction, error) {
+		if client, ok := reader.(IncrementalTransactionClient); ok && incremental {
+			return client.GetAccountTransactionsSince(ctx, accountID, since)
+		}
+		return reader.GetAccountTransactions(ctx, accountID)
+	})
+	if errors.Is(err, ErrCircuitOpen) {
+		return s.cachedAccountDetails(accountID, err)
+	}
 	if err != nil {
+		s.alerts.scrapeFailed(err)
+		return nil, err
+	}
+	if incremental {
+		transactions = transactionsSince(transactions, since)
+	}
+	if s.enricher != nil {
+		s.enricher.Enrich(ctx, transactions)
+	}
+	for i := range transactions {
+		enrich.Classify(&transactions[i])
+	}
+
+	// NAB's transaction IDs aren't stable, so transactions seen before
+	// take their stored IDs before anything, including alerts and hooks,
+	// sees them
+	transactions = s.store.DedupeTransactions(accountID, transactions)
+	stored := make(map[string]bool)
+	for _, transaction := range s.store.Transactions(accountID) {
+		stored[transaction.ID] = true
+	}
+	if err := s.store.SaveTransactions(accountID, transactions); err != nil {
 		return nil, err
 	}
+	s.store.ApplyCategoryRules(transactions)
+	// An account's first sync imports its history rather than finding new
+	// transactions
+	for _, transaction := range transactions {
+		if len(stored) > 0 && !stored[transaction.ID] {
+			s.events.Publish(model.EventTransactionNew, model.TransactionEvent{AccountID: accountID, Transaction: transaction})
+		}
+	}
+
+	s.alerts.checkTransactions(*targetAccount, transactions)
 
 	// Update last updated timestamp
 	now := time.Now()
 	targetAccount.LastUpdated = &now
+	applyLocalState(s.store, targetAccount)
+
+	// With caching on, the balances scraped along the way are stored so
+	// the account's cached details are as fresh as its transactions
+	if storeBalances && s.cachePolicy().TTL > 0 {
+		for i := range accounts {
+			accounts[i].LastUpdated = &now
+		}
+		if err := s.store.RecordAccounts(accounts, now); err != nil {
+			return nil, err
+		}
+	}
+
+	// An incremental sync only scraped the newest transactions, so the
+	// rest come from the store
+	if incremental {
+		transactions = s.store.Transactions(accountID)
+	}
+
+	// Running balances that don't follow on from one another mean the
+	// scrape missed transactions somewhere
+	warnings := checkRunningBalances(s.store.Transactions(accountID))
+	s.scrapes.recordBalanceGaps(accountID, len(warnings))
 
 	accountDetails := &model.AccountDetails{
-		Account:                  *targetAccount,
-		Transactions:             transactions,
-		RecentTransactionCount:   len(transactions),
+		Account:                *targetAccount,
+		Transactions:           transactions,
+		RecentTransactionCount: len(transactions),
+		Trend:                  balanceTrend(s.store.BalanceHistory(accountID), targetAccount.Balance, now),
+		SyncWarnings:           warnings,
+	}
+
+	if targetAccount.Type == model.AccountTypeLoan {
+		if loans, ok := reader.(LoanDetailsClient); ok {
+			loan, err := loans.GetLoanDetails(ctx, accountID)
+			if err != nil {
+				s.alerts.scrapeFailed(err)
+				return nil, err
+			}
+			accountDetails.Loan = loan
+		}
 	}
 
 	return accountDetails, nil
-}
\ No newline at end of file
+}
+
+// syncSince returns the date an account's transactions should be synced
+// from, and false if they have never been synced and need a full scrape
+func (s *accountService) syncSince(accountID string) (string, bool) {
+	newest, ok := s.store.NewestTransactionDate(accountID)
+	if !ok {
+		return "", false
+	}
+	date, err := time.Parse("2006-01-02", newest)
+	if err != nil {
+		return "", false
+	}
+	return date.AddDate(0, 0, -syncOverlapDays).Format("2006-01-02"), true
+}
+
+// transactionsSince returns the transactions dated on or after since
+func transactionsSince(transactions []model.Transaction, since string) []model.Transaction {
+	kept := make([]model.Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		if transaction.Date >= since {
+			kept = append(kept, transaction)
+		}
+	}
+	return kept
+}
+
+// applyLocalState marks an account with what is kept about it locally
+// rather than scraped: whether it is archived and its metadata
+func applyLocalState(store *store.Store, account *model.Account) {
+	account.Archived = store.AccountArchived(account.ID)
+	account.Metadata = nil
+	if metadata, ok := store.AccountMetadata(account.ID); ok {
+		account.Metadata = &metadata
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// ErrInvalidAccountFilter is returned for a balance bound that isn't a
+// dollar amount or an unknown sort
+var ErrInvalidAccountFilter = errors.New("invalid account filter")
+
+// AccountFilter narrows down and orders an account list. Empty fields
+// don't filter, and an empty Sort keeps the order NAB lists accounts in.
+type AccountFilter struct {
+	// Types are the account types to keep, e.g. savings and credit
+	Types []string
+
+	// MinBalance and MaxBalance are inclusive dollar amounts
+	MinBalance string
+	MaxBalance string
+
+	// Sort is balance or name, prefixed with - for descending
+	Sort string
+}
+
+// Validate checks the balance bounds and sort, so a bad filter can be
+// rejected before scraping
+func (f AccountFilter) Validate() error {
+	_, _, err := f.bounds()
+	if err != nil {
+		return err
+	}
+	if field := strings.TrimPrefix(f.Sort, "-"); field != "" && field != "balance" && field != "name" {
+		return fmt.Errorf("%w: sort must be balance or name, optionally prefixed with -", ErrInvalidAccountFilter)
+	}
+	return nil
+}
+
+// bounds parses the balance bounds into cents, nil where unset
+func (f AccountFilter) bounds() (*int64, *int64, error) {
+	bound := func(name, amount string) (*int64, error) {
+		if amount == "" {
+			return nil, nil
+		}
+		cents, err := parseBalanceCents(amount)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s %v", ErrInvalidAccountFilter, name, err)
+		}
+		return &cents, nil
+	}
+	minBalance, err := bound("minBalance", f.MinBalance)
+	if err != nil {
+		return nil, nil, err
+	}
+	maxBalance, err := bound("maxBalance", f.MaxBalance)
+	if err != nil {
+		return nil, nil, err
+	}
+	return minBalance, maxBalance, nil
+}
+
+// FilterAccounts returns the accounts matching the filter in the order it
+// asks for. Accounts whose balance can't be read are dropped by balance
+// bounds and sorted last by balance.
+func FilterAccounts(accounts []model.Account, filter AccountFilter) ([]model.Account, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	minBalance, maxBalance, _ := filter.bounds()
+	field, descending := strings.TrimPrefix(filter.Sort, "-"), strings.HasPrefix(filter.Sort, "-")
+
+	types := make(map[string]bool, len(filter.Types))
+	for _, accountType := range filter.Types {
+		types[strings.ToLower(strings.TrimSpace(accountType))] = true
+	}
+
+	filtered := []model.Account{}
+	for _, account := range accounts {
+		if len(types) > 0 && !types[strings.ToLower(account.Type)] {
+			continue
+		}
+		if minBalance != nil || maxBalance != nil {
+			balance, err := parseBalanceCents(account.Balance.Amount)
+			if err != nil || (minBalance != nil && balance < *minBalance) || (maxBalance != nil && balance > *maxBalance) {
+				continue
+			}
+		}
+		filtered = append(filtered, account)
+	}
+
+	switch field {
+	case "name":
+		sort.SliceStable(filtered, func(i, j int) bool {
+			a, b := strings.ToLower(filtered[i].Name), strings.ToLower(filtered[j].Name)
+			if descending {
+				return a > b
+			}
+			return a < b
+		})
+	case "balance":
+		sort.SliceStable(filtered, func(i, j int) bool {
+			a, errA := parseBalanceCents(filtered[i].Balance.Amount)
+			b, errB := parseBalanceCents(filtered[j].Balance.Amount)
+			if errA != nil || errB != nil {
+				return errA == nil && errB != nil
+			}
+			if descending {
+				return a > b
+			}
+			return a < b
+		})
+	}
+
+	return filtered, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestFilterAccounts(t *testing.T) {
+	accounts := []model.Account{
+		{ID: "1", Name: "Everyday", Type: "transaction", Balance: model.Money{Amount: "250.00"}},
+		{ID: "2", Name: "Reward Saver", Type: "savings", Balance: model.Money{Amount: "15420.89"}},
+		{ID: "3", Name: "Credit Card", Type: "credit", Balance: model.Money{Amount: "-1250.50"}},
+		{ID: "4", Name: "Bonus Saver", Type: "savings", Balance: model.Money{Amount: "n/a"}},
+	}
+	ids := func(accounts []model.Account) []string {
+		result := []string{}
+		for _, account := range accounts {
+			result = append(result, account.ID)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name   string
+		filter AccountFilter
+		want   []string
+	}{
+		{"no filter keeps NAB's order", AccountFilter{}, []string{"1", "2", "3", "4"}},
+		{"types", AccountFilter{Types: []string{"savings", "Credit"}}, []string{"2", "3", "4"}},
+		{"min balance", AccountFilter{MinBalance: "0"}, []string{"1", "2"}},
+		{"balance range", AccountFilter{MinBalance: "-2000", MaxBalance: "$300"}, []string{"1", "3"}},
+		{"by name", AccountFilter{Sort: "name"}, []string{"4", "3", "1", "2"}},
+		{"by balance descending", AccountFilter{Types: []string{"savings", "credit"}, Sort: "-balance"}, []string{"2", "3", "4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered, err := FilterAccounts(accounts, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ids(filtered); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := FilterAccounts(accounts, AccountFilter{Sort: "age"}); !errors.Is(err, ErrInvalidAccountFilter) {
+		t.Errorf("expected invalid filter error, got %v", err)
+	}
+	if _, err := FilterAccounts(accounts, AccountFilter{MinBalance: "lots"}); !errors.Is(err, ErrInvalidAccountFilter) {
+		t.Errorf("expected invalid filter error, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// SessionProvider is implemented by bank providers that can log in once
+// and read several accounts in that one session, so a sync needn't log in
+// again for every account
+type SessionProvider interface {
+	OpenSession(ctx context.Context) (ProviderSession, error)
+}
+
+// ProviderSession is a logged in session with a bank. Its reads may run
+// concurrently, and it may also implement optional interfaces such as
+// LoanDetailsClient or IncrementalTransactionClient. Close ends the
+// session.
+type ProviderSession interface {
+	GetAccounts(ctx context.Context) ([]model.Account, error)
+	GetAccountTransactions(ctx context.Context, accountID string) ([]model.Transaction, error)
+	Close()
+}
+
+// SessionSyncer is implemented by account services that can refresh every
+// account over a single login
+type SessionSyncer interface {
+	StartSync(ctx context.Context) (*AccountSync, error)
+}
+
+// AccountSync refreshes the accounts listed when it started, scraping
+// them all in one session with the bank. The session is opened the first
+// time it is needed, so a sync served from the cache never logs in. Its
+// details may be fetched concurrently, and it must be closed once done.
+type AccountSync struct {
+	service  *accountService
+	accounts []model.Account
+
+	mu      sync.Mutex
+	session ProviderSession
+	err     error
+}
+
+// StartSync lists the accounts, from the store when they were scraped
+// recently enough, and returns a sync for refreshing their details in the
+// session the list was scraped in
+func (s *accountService) StartSync(ctx context.Context) (*AccountSync, error) {
+	accountSync := &AccountSync{service: s}
+	if accounts, ok := s.freshAccounts(); ok {
+		s.cacheStats.record(accountsCacheKey, servedFreshness(accounts[0].Stale))
+		accountSync.accounts = accounts
+		return accountSync, nil
+	}
+	s.cacheStats.record(accountsCacheKey, freshnessExpired)
+
+	// A failed login has already been alerted on, but with the circuit
+	// open the stored accounts are synced instead
+	reader, err := accountSync.reader(ctx)
+	var accounts []model.Account
+	switch {
+	case err == nil:
+		accounts, err = s.listAccounts(ctx, reader)
+	case !errors.Is(err, ErrCircuitOpen):
+		return nil, err
+	}
+	accounts, err = s.storeAccounts(accounts, err)
+	if err != nil {
+		accountSync.Close()
+		return nil, err
+	}
+	accountSync.accounts = accounts
+	return accountSync, nil
+}
+
+// Accounts returns the accounts listed when the sync started
+func (a *AccountSync) Accounts() []model.Account {
+	return a.accounts
+}
+
+// GetAccountDetails refreshes an account's details in the sync's session,
+// or serves them from the store when they were scraped recently enough
+func (a *AccountSync) GetAccountDetails(ctx context.Context, accountID string) (*model.AccountDetails, error) {
+	s := a.service
+	if details, ok := s.freshAccountDetails(accountID); ok {
+		s.cacheStats.record(accountCacheKey(accountID), servedFreshness(details.Account.Stale))
+		return details, nil
+	}
+	s.cacheStats.record(accountCacheKey(accountID), freshnessExpired)
+
+	reader, err := a.reader(ctx)
+	if errors.Is(err, ErrCircuitOpen) {
+		return s.cachedAccountDetails(accountID, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.scrapeListedAccountDetails(ctx, reader, a.accounts, accountID, false)
+}
+
+// Close ends the sync's session, if one was opened
+func (a *AccountSync) Close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.session != nil {
+		a.session.Close()
+		a.session = nil
+	}
+}
+
+// reader returns what the sync scrapes through: its session, logging in
+// the first time, or the provider itself if it has no sessions. A failed
+// login is alerted on once and isn't tried again for every account.
+func (a *AccountSync) reader(ctx context.Context) (accountReader, error) {
+	s := a.service
+	provider, ok := s.nabClient.(SessionProvider)
+	if !ok {
+		return s.nabClient, nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.session == nil && a.err == nil {
+		a.session, a.err = scrape(ctx, s, func() (ProviderSession, error) {
+			return provider.OpenSession(ctx)
+		})
+		if a.err != nil && !errors.Is(a.err, ErrCircuitOpen) {
+			s.alerts.scrapeFailed(a.err)
+		}
+	}
+	if a.err != nil {
+		return nil, a.err
+	}
+	return a.session, nil
+}
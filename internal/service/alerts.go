@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/notify"
+)
+
+// notificationTimeout bounds how long a single alert delivery may take
+const notificationTimeout = 15 * time.Second
+
+// AlertThresholds configures when account alerts are raised. A zero value
+// disables the corresponding alert.
+type AlertThresholds struct {
+	LowBalance       float64
+	LargeTransaction float64
+}
+
+// alerter raises notifications for notable account activity, remembering
+// what it has already reported so repeated fetches don't repeat alerts
+type alerter struct {
+	notifier notify.Notifier
+
+	mu               sync.Mutex
+	thresholds       AlertThresholds
+	lowBalance       map[string]bool
+	seenTransactions map[string]bool
+}
+
+func newAlerter(notifier notify.Notifier, thresholds AlertThresholds) *alerter {
+	if notifier == nil {
+		notifier = notify.Nop{}
+	}
+
+	return &alerter{
+		notifier:         notifier,
+		thresholds:       thresholds,
+		lowBalance:       make(map[string]bool),
+		seenTransactions: make(map[string]bool),
+	}
+}
+
+// setThresholds changes the thresholds from the next check on
+func (a *alerter) setThresholds(thresholds AlertThresholds) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.thresholds = thresholds
+}
+
+// checkAccounts raises a low balance alert for accounts that have dropped
+// below the threshold since the last check
+func (a *alerter) checkAccounts(accounts []model.Account) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.thresholds.LowBalance <= 0 {
+		return
+	}
+
+	for _, account := range accounts {
+		if account.Type == model.AccountTypeCredit || account.Type == model.AccountTypeLoan {
+			continue
+		}
+
+		balance, err := strconv.ParseFloat(account.Balance.Amount, 64)
+		if err != nil {
+			continue
+		}
+
+		low := balance < a.thresholds.LowBalance
+		if low && !a.lowBalance[account.ID] {
+			a.send(notify.Notification{
+				Event:    notify.EventLowBalance,
+				Title:    "Low balance",
+				Message:  fmt.Sprintf("%s balance is $%s", account.Name, account.Balance.Amount),
+				Priority: notify.PriorityHigh,
+			})
+		}
+		a.lowBalance[account.ID] = low
+	}
+}
+
+// checkTransactions raises an alert for each unseen transaction at or above
+// the large transaction threshold
+func (a *alerter) checkTransactions(account model.Account, transactions []model.Transaction) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.thresholds.LargeTransaction <= 0 {
+		return
+	}
+
+	for _, transaction := range transactions {
+		if a.seenTransactions[transaction.ID] {
+			continue
+		}
+		a.seenTransactions[transaction.ID] = true
+
+		amount, err := strconv.ParseFloat(transaction.Amount.Amount, 64)
+		if err != nil || math.Abs(amount) < a.thresholds.LargeTransaction {
+			continue
+		}
+
+		a.send(notify.Notification{
+			Event:    notify.EventLargeTransaction,
+			Title:    "Large transaction",
+			Message:  fmt.Sprintf("%s: $%s %s", account.Name, transaction.Amount.Amount, transaction.Description),
+			Priority: notify.PriorityNormal,
+		})
+	}
+}
+
+// newMessages raises an alert for each unread message. Messages whose
+// subject mentions one of the keywords are sent at high priority.
+func (a *alerter) newMessages(messages []model.Message, keywords []string) {
+	for _, message := range messages {
+		if message.Read {
+			continue
+		}
+
+		priority := notify.PriorityNormal
+		subject := strings.ToLower(message.Subject)
+		for _, keyword := range keywords {
+			if keyword != "" && strings.Contains(subject, strings.ToLower(keyword)) {
+				priority = notify.PriorityHigh
+				break
+			}
+		}
+
+		a.send(notify.Notification{
+			Event:    notify.EventNewMessage,
+			Title:    "New NAB message",
+			Message:  message.Subject,
+			Priority: priority,
+		})
+	}
+}
+
+// scrapeFailed raises an alert that fetching data from NAB failed. Updated
+// terms and security challenges get their own alerts, and nothing is sent
+// while scraping is paused, since the user has already been told, or for
+// requests abandoned part way.
+func (a *alerter) scrapeFailed(err error) {
+	switch {
+	case errors.Is(err, ErrScrapingPaused), errors.Is(err, ErrRequestAborted):
+		return
+	case errors.Is(err, ErrTermsAcceptanceRequired):
+		a.send(notify.Notification{
+			Event:    notify.EventTermsUpdate,
+			Title:    "NAB terms need accepting",
+			Message:  "NAB is asking for updated terms and conditions to be accepted. Log in to internet banking to accept them; scraping is paused until then.",
+			Priority: notify.PriorityHigh,
+		})
+		return
+	case errors.Is(err, ErrChallengeRequired):
+		a.send(notify.Notification{
+			Event:    notify.EventChallenge,
+			Title:    "NAB security challenge",
+			Message:  err.Error(),
+			Priority: notify.PriorityHigh,
+		})
+		return
+	}
+
+	a.send(notify.Notification{
+		Event:    notify.EventScrapeFailure,
+		Title:    "NAB scrape failed",
+		Message:  err.Error(),
+		Priority: notify.PriorityHigh,
+	})
+}
+
+// balanceMismatch raises an alert that an external system's expected
+// balance doesn't match the scraped one
+func (a *alerter) balanceMismatch(account model.Account, assertion *model.BalanceAssertionResponse) {
+	source := "An external system"
+	if assertion.Source != nil {
+		source = *assertion.Source
+	}
+	a.send(notify.Notification{
+		Event:    notify.EventBalanceMismatch,
+		Title:    "Balance mismatch",
+		Message:  fmt.Sprintf("%s expected %s to be $%s but NAB shows $%s (difference $%s)", source, account.Name, assertion.ExpectedBalance.Amount, assertion.ActualBalance.Amount, assertion.Difference.Amount),
+		Priority: notify.PriorityHigh,
+	})
+}
+
+// budgetAlert raises an alert that spending has neared or gone over a
+// budget
+func (a *alerter) budgetAlert(progress model.BudgetProgress) {
+	title, priority := "Budget nearly spent", notify.PriorityNormal
+	if progress.Status == model.BudgetStatusExceeded {
+		title, priority = "Budget exceeded", notify.PriorityHigh
+	}
+	a.send(notify.Notification{
+		Event:    notify.EventBudget,
+		Title:    title,
+		Message:  fmt.Sprintf("$%s of the $%s %s %s budget spent (%.0f%%)", progress.Spent.Amount, progress.Limit.Amount, progress.Period, progress.Category, progress.PercentUsed),
+		Priority: priority,
+	})
+}
+
+// send delivers a notification in the background so alerts never hold up
+// an API response
+func (a *alerter) send(n notify.Notification) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+		defer cancel()
+		_ = a.notifier.Send(ctx, n)
+	}()
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/notify"
+)
+
+func TestScrapeFailedTermsAlerts(t *testing.T) {
+	notifier := make(channelNotifier, 4)
+	alerts := newAlerter(notifier, AlertThresholds{})
+
+	alerts.scrapeFailed(fmt.Errorf("failed to scrape NAB accounts: %w", ErrTermsAcceptanceRequired))
+	select {
+	case n := <-notifier:
+		if n.Event != notify.EventTermsUpdate || n.Priority != notify.PriorityHigh {
+			t.Errorf("unexpected notification %+v", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a terms update notification")
+	}
+
+	// Paused scrapes have already been reported
+	alerts.scrapeFailed(fmt.Errorf("%w until later", ErrScrapingPaused))
+	select {
+	case n := <-notifier:
+		t.Errorf("unexpected notification while paused %+v", n)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	alerts.scrapeFailed(errors.New("timeout"))
+	select {
+	case n := <-notifier:
+		if n.Event != notify.EventScrapeFailure {
+			t.Errorf("unexpected notification %+v", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a scrape failure notification")
+	}
+}
+
+func TestScrapeFailedChallengeAlerts(t *testing.T) {
+	notifier := make(channelNotifier, 1)
+	alerts := newAlerter(notifier, AlertThresholds{})
+
+	err := fmt.Errorf("failed to scrape NAB accounts: %w", &ChallengeError{Challenge: model.Challenge{Kind: model.ChallengeKindCaptcha, URL: "https://ib.nab.com.au/login", Screenshot: "/app/screenshots/nab_challenge.png"}})
+	if retryable(err) {
+		t.Error("expected challenges not to be retried")
+	}
+	alerts.scrapeFailed(err)
+	select {
+	case n := <-notifier:
+		if n.Event != notify.EventChallenge || !strings.Contains(n.Message, "/app/screenshots/nab_challenge.png") {
+			t.Errorf("unexpected notification %+v", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a challenge notification")
+	}
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+var (
+	// ErrAnnotationNotFound is returned for unknown annotations, and for
+	// deleted ones whose restore window has passed
+	ErrAnnotationNotFound = errors.New("annotation not found")
+
+	// ErrInvalidAnnotation is returned when an annotation's kind or value
+	// is unusable
+	ErrInvalidAnnotation = errors.New("invalid annotation")
+)
+
+// Annotation value limits
+const (
+	maxTagLength  = 64
+	maxNoteLength = 2000
+)
+
+// AnnotationService manages tags and notes attached to stored
+// transactions. Deleting an annotation moves it to the trash, from where
+// it can be restored until the retention period ends.
+type AnnotationService interface {
+	Annotations(accountID, transactionID string) []model.Annotation
+	Annotate(accountID, transactionID string, req model.AnnotationRequest) (*model.Annotation, error)
+	Delete(id string, version int) (*model.Annotation, error)
+	Restore(id string, version int) (*model.Annotation, error)
+	Trash() ([]model.Annotation, error)
+	Retention() time.Duration
+}
+
+// annotationService implements AnnotationService
+type annotationService struct {
+	store     *store.Store
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewAnnotationService creates an annotation service that keeps deleted
+// annotations restorable for retention
+func NewAnnotationService(store *store.Store, retention time.Duration) AnnotationService {
+	return &annotationService{
+		store:     store,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// Annotations returns the live annotations on a transaction, oldest first
+func (s *annotationService) Annotations(accountID, transactionID string) []model.Annotation {
+	var annotations []model.Annotation
+	for _, annotation := range s.store.Annotations() {
+		if annotation.DeletedAt == nil && annotation.AccountID == accountID && annotation.TransactionID == transactionID {
+			annotations = append(annotations, annotation)
+		}
+	}
+	return annotations
+}
+
+// Annotate attaches a tag or note to a stored transaction. Tags are
+// lowercased, and adding a tag the transaction already has returns the
+// existing one.
+func (s *annotationService) Annotate(accountID, transactionID string, req model.AnnotationRequest) (*model.Annotation, error) {
+	value := strings.TrimSpace(req.Value)
+	switch req.Kind {
+	case model.AnnotationKindTag:
+		value = strings.ToLower(value)
+		if value == "" || len(value) > maxTagLength {
+			return nil, fmt.Errorf("%w: tags must be 1 to %d characters", ErrInvalidAnnotation, maxTagLength)
+		}
+	case model.AnnotationKindNote:
+		if value == "" || len(value) > maxNoteLength {
+			return nil, fmt.Errorf("%w: notes must be 1 to %d characters", ErrInvalidAnnotation, maxNoteLength)
+		}
+	default:
+		return nil, fmt.Errorf("%w: kind must be %q or %q", ErrInvalidAnnotation, model.AnnotationKindTag, model.AnnotationKindNote)
+	}
+
+	if !s.storedTransaction(accountID, transactionID) {
+		return nil, ErrTransactionNotFound
+	}
+	if req.Kind == model.AnnotationKindTag {
+		for _, existing := range s.Annotations(accountID, transactionID) {
+			if existing.Kind == model.AnnotationKindTag && existing.Value == value {
+				return &existing, nil
+			}
+		}
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate annotation ID: %w", err)
+	}
+	annotation := model.Annotation{
+		ID:            "ann_" + hex.EncodeToString(id),
+		AccountID:     accountID,
+		TransactionID: transactionID,
+		Kind:          req.Kind,
+		Value:         value,
+		Version:       1,
+		CreatedAt:     s.now(),
+	}
+	if err := s.store.SaveAnnotation(annotation); err != nil {
+		return nil, fmt.Errorf("failed to save annotation: %w", err)
+	}
+
+	return &annotation, nil
+}
+
+// Delete moves an annotation to the trash. A non-zero version makes the
+// delete conditional on the annotation not having changed since.
+func (s *annotationService) Delete(id string, version int) (*model.Annotation, error) {
+	annotation, ok, err := s.store.UpdateAnnotation(id, func(annotation *model.Annotation) error {
+		if annotation.DeletedAt != nil {
+			return ErrAnnotationNotFound
+		}
+		if err := CheckVersion(annotation.Version, version); err != nil {
+			return err
+		}
+
+		now := s.now()
+		purgeAt := now.Add(s.retention)
+		annotation.DeletedAt = &now
+		annotation.PurgeAt = &purgeAt
+		annotation.Version++
+		return nil
+	})
+	if !ok {
+		return nil, ErrAnnotationNotFound
+	}
+	if err != nil {
+		return nil, annotationError("delete", err)
+	}
+
+	return &annotation, nil
+}
+
+// Restore takes an annotation back out of the trash, conditionally on its
+// version like Delete. Restoring one that was never deleted returns it
+// unchanged.
+func (s *annotationService) Restore(id string, version int) (*model.Annotation, error) {
+	annotation, ok, err := s.store.UpdateAnnotation(id, func(annotation *model.Annotation) error {
+		if err := CheckVersion(annotation.Version, version); err != nil {
+			return err
+		}
+		if annotation.DeletedAt == nil {
+			return nil
+		}
+		if annotation.PurgeAt.Before(s.now()) {
+			return fmt.Errorf("%w: restore window ended %s", ErrAnnotationNotFound, annotation.PurgeAt.Format(time.RFC3339))
+		}
+
+		annotation.DeletedAt = nil
+		annotation.PurgeAt = nil
+		annotation.Version++
+		return nil
+	})
+	if !ok {
+		return nil, ErrAnnotationNotFound
+	}
+	if err != nil {
+		return nil, annotationError("restore", err)
+	}
+
+	return &annotation, nil
+}
+
+// Trash purges annotations past their restore window and returns the
+// rest of the deleted ones, most recently deleted first
+func (s *annotationService) Trash() ([]model.Annotation, error) {
+	if _, err := s.store.PurgeAnnotations(s.now()); err != nil {
+		return nil, fmt.Errorf("failed to purge annotations: %w", err)
+	}
+
+	var trash []model.Annotation
+	for _, annotation := range s.store.Annotations() {
+		if annotation.DeletedAt != nil {
+			trash = append(trash, annotation)
+		}
+	}
+	sort.SliceStable(trash, func(i, j int) bool {
+		return trash[i].DeletedAt.After(*trash[j].DeletedAt)
+	})
+
+	return trash, nil
+}
+
+// Retention returns how long deleted annotations stay restorable
+func (s *annotationService) Retention() time.Duration {
+	return s.retention
+}
+
+// annotationError passes on annotation not found and version mismatch
+// errors, wrapping anything else as a failure to save
+func annotationError(action string, err error) error {
+	if errors.Is(err, ErrAnnotationNotFound) || errors.Is(err, ErrVersionMismatch) {
+		return err
+	}
+	return fmt.Errorf("failed to %s annotation: %w", action, err)
+}
+
+// storedTransaction reports whether the transaction has been scraped
+func (s *annotationService) storedTransaction(accountID, transactionID string) bool {
+	for _, transaction := range s.store.Transactions(accountID) {
+		if transaction.ID == transactionID {
+			return true
+		}
+	}
+	return false
+}
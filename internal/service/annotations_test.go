@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestAnnotationTrash(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dataStore.SaveTransactions("12345678", []model.Transaction{{ID: "txn_1", Date: "2023-10-17"}}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2023, 10, 17, 9, 0, 0, 0, time.UTC)
+	svc := &annotationService{store: dataStore, retention: 24 * time.Hour, now: func() time.Time { return now }}
+
+	if _, err := svc.Annotate("12345678", "txn_1", model.AnnotationRequest{Kind: "label", Value: "x"}); !errors.Is(err, ErrInvalidAnnotation) {
+		t.Errorf("expected invalid annotation, got %v", err)
+	}
+	if _, err := svc.Annotate("12345678", "txn_2", model.AnnotationRequest{Kind: model.AnnotationKindNote, Value: "x"}); !errors.Is(err, ErrTransactionNotFound) {
+		t.Errorf("expected transaction not found, got %v", err)
+	}
+
+	tag, err := svc.Annotate("12345678", "txn_1", model.AnnotationRequest{Kind: model.AnnotationKindTag, Value: " Tax-Deductible "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := svc.Annotate("12345678", "txn_1", model.AnnotationRequest{Kind: model.AnnotationKindTag, Value: "tax-deductible"}); again.ID != tag.ID {
+		t.Errorf("expected the existing tag back, got %s", again.ID)
+	}
+	note, err := svc.Annotate("12345678", "txn_1", model.AnnotationRequest{Kind: model.AnnotationKindNote, Value: "Work lunch"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.Delete(tag.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Delete(tag.ID, 0); !errors.Is(err, ErrAnnotationNotFound) {
+		t.Errorf("expected deleting twice to fail, got %v", err)
+	}
+	if live := svc.Annotations("12345678", "txn_1"); len(live) != 1 || live[0].ID != note.ID {
+		t.Errorf("expected only the note to remain, got %+v", live)
+	}
+
+	if _, err := svc.Restore(tag.ID, 1); !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("expected restoring an old version to fail, got %v", err)
+	}
+	restored, err := svc.Restore(tag.ID, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.DeletedAt != nil || restored.Version != 3 || len(svc.Annotations("12345678", "txn_1")) != 2 {
+		t.Errorf("expected the tag to be restored, got %+v", restored)
+	}
+
+	if _, err := svc.Delete(note.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if trash, _ := svc.Trash(); len(trash) != 1 || trash[0].ID != note.ID {
+		t.Errorf("expected the note in the trash, got %+v", trash)
+	}
+
+	now = now.Add(25 * time.Hour)
+	if _, err := svc.Restore(note.ID, 0); !errors.Is(err, ErrAnnotationNotFound) {
+		t.Errorf("expected the restore window to have passed, got %v", err)
+	}
+	if trash, _ := svc.Trash(); len(trash) != 0 {
+		t.Errorf("expected the trash to be purged, got %+v", trash)
+	}
+	if _, ok := dataStore.Annotation(note.ID); ok {
+		t.Error("expected the purged note to be gone from the store")
+	}
+}
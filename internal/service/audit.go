@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+
+	"github.com/benrowe/nab-bank-api/internal/audit"
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// recordMovement records an attempt to move money in the audit log,
+// attributed to the caller making the request
+func recordMovement(ctx context.Context, auditLog *audit.Log, kind, action string, movement model.AuditMovement, err error) {
+	entry := model.AuditEntry{
+		Actor:    audit.Actor(ctx),
+		Kind:     kind,
+		Action:   action,
+		Result:   model.AuditResultSuccess,
+		Movement: &movement,
+	}
+	if err != nil {
+		entry.Result = model.AuditResultFailure
+		entry.Error = err.Error()
+	}
+	auditLog.Record(entry)
+}
+
+// applyPayment fills in a payment movement from NAB's outcome
+func applyPayment(movement *model.AuditMovement, result *model.PaymentResult) {
+	payee := result.Payee
+	movement.PaymentID = result.ID
+	movement.FromAccountID = result.FromAccountID
+	movement.Payee = &payee
+	movement.Amount = result.Amount.Amount
+	movement.Description = result.Description
+	movement.Reference = result.Reference
+	movement.DryRun = result.DryRun
+	movement.Status = result.Status
+	movement.ReceiptNumber = result.ReceiptNumber
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned, wrapped with ErrServiceUnavailable, while
+// scraping is suspended after repeated failures
+var ErrCircuitOpen = errors.New("NAB scraping suspended after repeated failures")
+
+// BreakerPolicy controls the circuit breaker around NAB scrapes. After
+// Threshold consecutive failures no scrapes are attempted for Cooldown,
+// then a single trial scrape decides whether to resume. A zero Threshold
+// disables the breaker.
+type BreakerPolicy struct {
+	Threshold int
+	Cooldown  time.Duration
+}
+
+// breaker is a circuit breaker that stops launching browser sessions
+// while NAB keeps failing
+type breaker struct {
+	policy BreakerPolicy
+	now    func() time.Time
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	trial     bool
+}
+
+// newBreaker creates a circuit breaker, or nil if the policy disables it
+func newBreaker(policy BreakerPolicy) *breaker {
+	if policy.Threshold <= 0 {
+		return nil
+	}
+	return &breaker{policy: policy, now: time.Now}
+}
+
+// allow reports whether a scrape may go ahead. Once the cooldown has
+// passed, one caller is let through as a trial while the rest keep
+// failing fast until it finishes.
+func (b *breaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.policy.Threshold {
+		return nil
+	}
+	if b.trial || b.now().Before(b.openUntil) {
+		return fmt.Errorf("%w: %w: %d consecutive failures, next attempt after %s",
+			ErrServiceUnavailable, ErrCircuitOpen, b.failures, b.openUntil.Format(time.RFC3339))
+	}
+	b.trial = true
+	return nil
+}
+
+// record updates the breaker with the outcome of a scrape. Errors that
+// say nothing about NAB's health neither open nor close it.
+func (b *breaker) record(err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+	switch {
+	case err == nil:
+		b.failures = 0
+	case reflectsNABHealth(err):
+		b.failures++
+		if b.failures >= b.policy.Threshold {
+			b.openUntil = b.now().Add(b.policy.Cooldown)
+		}
+	}
+}
+
+// open reports whether the breaker is stopping scrapes, and until when
+func (b *breaker) open() (bool, time.Time) {
+	if b == nil {
+		return false, time.Time{}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.policy.Threshold {
+		return false, time.Time{}
+	}
+	return true, b.openUntil
+}
+
+// reflectsNABHealth reports whether a scrape error says something about
+// NAB or the browser. Paused scraping, unknown accounts and cancelled or
+// timed out requests don't.
+func reflectsNABHealth(err error) bool {
+	return !errors.Is(err, ErrScrapingPaused) &&
+		!errors.Is(err, ErrAccountNotFound) &&
+		!errors.Is(err, ErrRequestAborted) &&
+		!errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestCircuitBreakerServesCachedAccounts(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &flakyClient{}
+	svc := NewAccountService(client, dataStore, nil, AlertThresholds{}, RetryPolicy{}, BreakerPolicy{Threshold: 2, Cooldown: time.Hour}, CachePolicy{}, nil, nil).(*accountService)
+	now := time.Now()
+	svc.breaker.now = func() time.Time { return now }
+
+	// A successful scrape stores the accounts to fall back on
+	if _, err := svc.GetAllAccounts(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	client.calls, client.failures, client.err = 0, 10, context.DeadlineExceeded
+	for i := 0; i < 2; i++ {
+		if _, err := svc.GetAllAccounts(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected scrape failure, got %v", err)
+		}
+	}
+
+	accounts, err := svc.GetAllAccounts(context.Background())
+	if err != nil {
+		t.Fatalf("expected cached accounts while the circuit is open, got %v", err)
+	}
+	if len(accounts) == 0 || !accounts[0].Cached {
+		t.Errorf("expected cached accounts, got %+v", accounts)
+	}
+	details, err := svc.GetAccountDetails(context.Background(), accounts[0].ID)
+	if err != nil || !details.Cached {
+		t.Errorf("expected cached account details, got %+v, %v", details, err)
+	}
+	if client.calls != 2 {
+		t.Errorf("expected no scrapes while the circuit is open, got %d", client.calls)
+	}
+
+	// After the cooldown a trial scrape closes the circuit again
+	now = now.Add(time.Hour)
+	client.failures = 0
+	accounts, err = svc.GetAllAccounts(context.Background())
+	if err != nil || accounts[0].Cached {
+		t.Errorf("expected a fresh scrape after the cooldown, got %+v, %v", accounts, err)
+	}
+}
+
+func TestCircuitBreakerWithoutCache(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &flakyClient{failures: 10, err: errors.New("could not find username input field")}
+	svc := NewAccountService(client, dataStore, nil, AlertThresholds{}, RetryPolicy{}, BreakerPolicy{Threshold: 1, Cooldown: time.Hour}, CachePolicy{}, nil, nil)
+
+	svc.GetAllAccounts(context.Background())
+	_, err = svc.GetAllAccounts(context.Background())
+	if !errors.Is(err, ErrServiceUnavailable) || !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected the circuit to fail fast, got %v", err)
+	}
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/notify"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+var (
+	// ErrBudgetNotFound is returned for unknown budgets
+	ErrBudgetNotFound = errors.New("budget not found")
+
+	// ErrInvalidBudget is returned when a budget's category, period, limit
+	// or alert percentage is unusable
+	ErrInvalidBudget = errors.New("invalid budget")
+)
+
+// defaultBudgetAlertPercent is how much of a budget can be spent before
+// an alert, unless the budget says otherwise
+const defaultBudgetAlertPercent = 80
+
+// BudgetService manages spending budgets per category and alerts when
+// they are nearly spent or exceeded
+type BudgetService interface {
+	Budgets(at time.Time) []model.BudgetProgress
+	Create(req model.BudgetRequest) (*model.BudgetProgress, error)
+	Update(id string, req model.BudgetRequest, version int) (*model.BudgetProgress, error)
+	Delete(id string, version int) (*model.Budget, error)
+	Check(at time.Time) error
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// budgetService implements BudgetService
+type budgetService struct {
+	store  *store.Store
+	alerts *alerter
+	logger *log.Logger
+	now    func() time.Time
+}
+
+// NewBudgetService creates a budget service. Alerts are pushed to the
+// notifier; a nil notifier disables them.
+func NewBudgetService(store *store.Store, notifier notify.Notifier, logger *log.Logger) BudgetService {
+	return &budgetService{
+		store:  store,
+		alerts: newAlerter(notifier, AlertThresholds{}),
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Budgets returns every budget with its spending in the period containing
+// at, oldest budget first
+func (s *budgetService) Budgets(at time.Time) []model.BudgetProgress {
+	budgets := s.store.Budgets()
+	progress := make([]model.BudgetProgress, 0, len(budgets))
+	for _, budget := range budgets {
+		progress = append(progress, s.progress(budget, at))
+	}
+	return progress
+}
+
+// Create adds a budget
+func (s *budgetService) Create(req model.BudgetRequest) (*model.BudgetProgress, error) {
+	budget, err := validateBudget(req)
+	if err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate budget ID: %w", err)
+	}
+	now := s.now()
+	budget.ID = "bud_" + hex.EncodeToString(id)
+	budget.Version = 1
+	budget.CreatedAt = now
+	budget.UpdatedAt = now
+	if err := s.store.SaveBudget(budget); err != nil {
+		return nil, fmt.Errorf("failed to save budget: %w", err)
+	}
+
+	progress := s.progress(budget, now)
+	return &progress, nil
+}
+
+// Update replaces a budget's category, period, limit and alert
+// percentage. A non-zero version makes the change conditional on the
+// budget not having changed since.
+func (s *budgetService) Update(id string, req model.BudgetRequest, version int) (*model.BudgetProgress, error) {
+	replacement, err := validateBudget(req)
+	if err != nil {
+		return nil, err
+	}
+
+	budget, ok, err := s.store.UpdateBudget(id, func(budget *model.Budget) error {
+		if err := CheckVersion(budget.Version, version); err != nil {
+			return err
+		}
+		budget.Category = replacement.Category
+		budget.Period = replacement.Period
+		budget.Limit = replacement.Limit
+		budget.AlertPercent = replacement.AlertPercent
+		budget.LastAlert = nil
+		budget.Version++
+		budget.UpdatedAt = s.now()
+		return nil
+	})
+	if !ok {
+		return nil, ErrBudgetNotFound
+	}
+	if err != nil {
+		return nil, budgetError("update", err)
+	}
+
+	progress := s.progress(budget, s.now())
+	return &progress, nil
+}
+
+// Delete removes a budget, conditionally on its version like Update
+func (s *budgetService) Delete(id string, version int) (*model.Budget, error) {
+	budget, ok, err := s.store.DeleteBudget(id, func(budget model.Budget) error {
+		return CheckVersion(budget.Version, version)
+	})
+	if !ok {
+		return nil, ErrBudgetNotFound
+	}
+	if err != nil {
+		return nil, budgetError("delete", err)
+	}
+	return &budget, nil
+}
+
+// Check alerts on budgets that are nearly spent or exceeded in the period
+// containing at. Each budget alerts at most once a period for each
+// status, so checking often is cheap and quiet.
+func (s *budgetService) Check(at time.Time) error {
+	for _, progress := range s.Budgets(at) {
+		if progress.Status == model.BudgetStatusOK {
+			continue
+		}
+		last := progress.LastAlert
+		if last != nil && last.PeriodStart == progress.PeriodStart && budgetStatusRank(last.Status) >= budgetStatusRank(progress.Status) {
+			continue
+		}
+
+		// Recording the alert doesn't change the budget's version, as
+		// it isn't a change anyone made to it
+		_, ok, err := s.store.UpdateBudget(progress.ID, func(budget *model.Budget) error {
+			budget.LastAlert = &model.BudgetAlert{
+				PeriodStart: progress.PeriodStart,
+				Status:      progress.Status,
+				SentAt:      s.now(),
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record alert for budget %s: %w", progress.ID, err)
+		}
+		if ok {
+			s.alerts.budgetAlert(progress)
+		}
+	}
+	return nil
+}
+
+// Run checks the budgets immediately and then every interval until the
+// context is cancelled
+func (s *budgetService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Check(s.now()); err != nil {
+			s.logger.Printf("Budget check failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// progress works out a budget's spending in the period containing at
+func (s *budgetService) progress(budget model.Budget, at time.Time) model.BudgetProgress {
+	start, end := budgetPeriod(budget.Period, at)
+	from, to := start.Format("2006-01-02"), end.Format("2006-01-02")
+
+	var spent int64
+	for _, transaction := range s.store.CategoryTransactions(budget.Category, from, to) {
+		if amount, err := parseBalanceCents(transaction.Amount.Amount); err == nil {
+			spent -= amount
+		}
+	}
+	limit, _ := parseBalanceCents(budget.Limit.Amount)
+
+	progress := model.BudgetProgress{
+		Budget:      budget,
+		PeriodStart: from,
+		PeriodEnd:   to,
+		Spent:       model.Money{Amount: formatCents(spent)},
+		Remaining:   model.Money{Amount: formatCents(limit - spent)},
+		Status:      model.BudgetStatusOK,
+	}
+	if limit > 0 {
+		progress.PercentUsed = math.Round(float64(spent)*1000/float64(limit)) / 10
+	}
+	switch {
+	case spent > limit:
+		progress.Status = model.BudgetStatusExceeded
+	case progress.PercentUsed >= float64(budget.AlertPercent):
+		progress.Status = model.BudgetStatusWarning
+	}
+	return progress
+}
+
+// budgetPeriod returns the first and last days of the budget period
+// containing at. Weeks start on Monday.
+func budgetPeriod(period string, at time.Time) (time.Time, time.Time) {
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+	switch period {
+	case model.BudgetPeriodWeekly:
+		start := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 6)
+	case model.BudgetPeriodYearly:
+		start := time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, day.Location())
+		return start, start.AddDate(1, 0, -1)
+	default:
+		start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+		return start, start.AddDate(0, 1, -1)
+	}
+}
+
+// budgetStatusRank orders budget statuses by concern
+func budgetStatusRank(status string) int {
+	switch status {
+	case model.BudgetStatusExceeded:
+		return 2
+	case model.BudgetStatusWarning:
+		return 1
+	}
+	return 0
+}
+
+// validateBudget checks a budget request and turns it into a budget
+func validateBudget(req model.BudgetRequest) (model.Budget, error) {
+	category := strings.TrimSpace(req.Category)
+	if category == "" || len(category) > maxCategoryLength {
+		return model.Budget{}, fmt.Errorf("%w: category must be 1 to %d characters", ErrInvalidBudget, maxCategoryLength)
+	}
+	switch req.Period {
+	case model.BudgetPeriodWeekly, model.BudgetPeriodMonthly, model.BudgetPeriodYearly:
+	default:
+		return model.Budget{}, fmt.Errorf("%w: period must be %q, %q or %q", ErrInvalidBudget, model.BudgetPeriodWeekly, model.BudgetPeriodMonthly, model.BudgetPeriodYearly)
+	}
+	limit, err := parseBalanceCents(req.Limit)
+	if err != nil || limit <= 0 {
+		return model.Budget{}, fmt.Errorf("%w: limit must be a positive dollar amount", ErrInvalidBudget)
+	}
+	alertPercent := defaultBudgetAlertPercent
+	if req.AlertPercent != nil {
+		alertPercent = *req.AlertPercent
+		if alertPercent < 1 || alertPercent > 100 {
+			return model.Budget{}, fmt.Errorf("%w: alertPercent must be between 1 and 100", ErrInvalidBudget)
+		}
+	}
+
+	return model.Budget{
+		Category:     category,
+		Period:       req.Period,
+		Limit:        model.Money{Amount: formatCents(limit)},
+		AlertPercent: alertPercent,
+	}, nil
+}
+
+// budgetError passes on version mismatch errors, wrapping anything else as
+// a failure to save
+func budgetError(action string, err error) error {
+	if errors.Is(err, ErrVersionMismatch) {
+		return err
+	}
+	return fmt.Errorf("failed to %s budget: %w", action, err)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/notify"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestBudgets(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	groceries := "Groceries"
+	if err := dataStore.SaveTransactions("12345678", []model.Transaction{
+		{ID: "txn_001", Date: "2023-10-17", Amount: model.Money{Amount: "-450.00"}, Category: &groceries},
+		{ID: "txn_002", Date: "2023-10-12", Amount: model.Money{Amount: "-250.00"}, Category: &groceries},
+		{ID: "txn_003", Date: "2023-10-13", Amount: model.Money{Amount: "40.00"}, Category: &groceries},
+		{ID: "txn_004", Date: "2023-09-30", Amount: model.Money{Amount: "-500.00"}, Category: &groceries},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	notifier := make(channelNotifier, 4)
+	svc := NewBudgetService(dataStore, notifier, log.New(io.Discard, "", 0))
+	at := time.Date(2023, 10, 18, 12, 0, 0, 0, time.Local)
+
+	if _, err := svc.Create(model.BudgetRequest{Category: "Groceries", Period: "fortnightly", Limit: "800"}); !errors.Is(err, ErrInvalidBudget) {
+		t.Errorf("expected an unknown period to be refused, got %v", err)
+	}
+	budget, err := svc.Create(model.BudgetRequest{Category: "Groceries", Period: model.BudgetPeriodMonthly, Limit: "800"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	progress := svc.Budgets(at)[0]
+	if progress.PeriodStart != "2023-10-01" || progress.PeriodEnd != "2023-10-31" || progress.Spent.Amount != "660.00" || progress.Remaining.Amount != "140.00" || progress.PercentUsed != 82.5 || progress.Status != model.BudgetStatusWarning {
+		t.Fatalf("unexpected progress %+v", progress)
+	}
+
+	if _, err := svc.Update(budget.ID, model.BudgetRequest{Category: "Groceries", Period: model.BudgetPeriodWeekly, Limit: "800"}, 1); err != nil {
+		t.Fatal(err)
+	}
+	weekly := svc.Budgets(at)
+	if weekly[0].PeriodStart != "2023-10-16" || weekly[0].Spent.Amount != "450.00" || weekly[0].Status != model.BudgetStatusOK {
+		t.Errorf("unexpected weekly progress %+v", weekly[0])
+	}
+	if _, err := svc.Update(budget.ID, model.BudgetRequest{Category: "Groceries", Period: model.BudgetPeriodMonthly, Limit: "800"}, 1); !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("expected a stale version to be refused, got %v", err)
+	}
+	if _, err := svc.Update(budget.ID, model.BudgetRequest{Category: "Groceries", Period: model.BudgetPeriodMonthly, Limit: "800"}, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each status alerts once a period
+	for i := 0; i < 2; i++ {
+		if err := svc.Check(at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case n := <-notifier:
+		if n.Event != notify.EventBudget || n.Priority != notify.PriorityNormal {
+			t.Errorf("unexpected notification %+v", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a budget notification")
+	}
+	select {
+	case n := <-notifier:
+		t.Errorf("unexpected repeat notification %+v", n)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := dataStore.SaveTransactions("12345678", []model.Transaction{
+		{ID: "txn_005", Date: "2023-10-18", Amount: model.Money{Amount: "-200.00"}, Category: &groceries},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Check(at); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case n := <-notifier:
+		if n.Priority != notify.PriorityHigh {
+			t.Errorf("expected a high priority exceeded alert, got %+v", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected an exceeded notification")
+	}
+}
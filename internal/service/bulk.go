@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/jobs"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+var (
+	// ErrInvalidBulkRequest is returned when a bulk request is empty, too
+	// large or has an operation missing what it needs
+	ErrInvalidBulkRequest = errors.New("invalid bulk request")
+
+	// ErrJobNotFound is returned for unknown jobs, and for finished jobs
+	// that have been forgotten
+	ErrJobNotFound = errors.New("job not found")
+)
+
+// maxBulkOperations caps the operations in one bulk request
+const maxBulkOperations = 1000
+
+// BulkService runs batches of local operations, such as tagging
+// transactions or recategorising a merchant, as background jobs
+type BulkService interface {
+	Submit(req model.BulkRequest) (*model.Job, error)
+	Job(id string) (*model.Job, error)
+}
+
+// bulkService implements BulkService
+type bulkService struct {
+	store       *store.Store
+	annotations AnnotationService
+	categories  CategoryService
+	jobs        *jobs.Manager
+}
+
+// NewBulkService creates a bulk service that runs its jobs on the job
+// manager
+func NewBulkService(store *store.Store, annotations AnnotationService, categories CategoryService, jobs *jobs.Manager) BulkService {
+	return &bulkService{
+		store:       store,
+		annotations: annotations,
+		categories:  categories,
+		jobs:        jobs,
+	}
+}
+
+// Submit checks every operation is well formed and starts a job
+// processing them in order. Operations that fail, for example on an
+// unknown transaction, are reported in the job's results without stopping
+// the rest.
+func (s *bulkService) Submit(req model.BulkRequest) (*model.Job, error) {
+	if len(req.Operations) == 0 || len(req.Operations) > maxBulkOperations {
+		return nil, fmt.Errorf("%w: between 1 and %d operations are accepted", ErrInvalidBulkRequest, maxBulkOperations)
+	}
+
+	tasks := make([]jobs.Task, 0, len(req.Operations))
+	for i, op := range req.Operations {
+		task, err := s.task(op)
+		if err != nil {
+			return nil, fmt.Errorf("%w: operation %d: %v", ErrInvalidBulkRequest, i, err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	job, err := s.jobs.Start("bulk", tasks)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Job returns a background job's progress and results, including sync
+// jobs, which share the job manager
+func (s *bulkService) Job(id string) (*model.Job, error) {
+	job, ok := s.jobs.Get(id)
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return &job, nil
+}
+
+// task turns an operation into a job task
+func (s *bulkService) task(op model.BulkOperation) (jobs.Task, error) {
+	switch op.Op {
+	case model.BulkOpTag:
+		if op.AccountID == "" || op.TransactionID == "" || strings.TrimSpace(op.Tag) == "" {
+			return nil, errors.New("tag needs accountId, transactionId and tag")
+		}
+		return func(ctx context.Context) (interface{}, error) {
+			return s.annotations.Annotate(op.AccountID, op.TransactionID, model.AnnotationRequest{
+				Kind:  model.AnnotationKindTag,
+				Value: op.Tag,
+			})
+		}, nil
+
+	case model.BulkOpRecategorize:
+		merchant, category := strings.TrimSpace(op.Merchant), strings.TrimSpace(op.Category)
+		if merchant == "" || category == "" {
+			return nil, errors.New("recategorize needs merchant and category")
+		}
+		return func(ctx context.Context) (interface{}, error) {
+			response, err := s.categories.SetMerchantCategory(merchant, category)
+			if err != nil {
+				return nil, fmt.Errorf("failed to recategorize %s: %w", merchant, err)
+			}
+			return model.RecategorizeResult{Merchant: merchant, Category: category, Updated: response.Recategorized}, nil
+		}, nil
+
+	case model.BulkOpArchive, model.BulkOpUnarchive:
+		if op.AccountID == "" {
+			return nil, fmt.Errorf("%s needs accountId", op.Op)
+		}
+		archived := op.Op == model.BulkOpArchive
+		return func(ctx context.Context) (interface{}, error) {
+			if !s.storedAccount(op.AccountID) {
+				return nil, ErrAccountNotFound
+			}
+			if err := s.store.SetAccountArchived(op.AccountID, archived, time.Now()); err != nil {
+				return nil, fmt.Errorf("failed to %s account: %w", op.Op, err)
+			}
+			return model.ArchiveResult{AccountID: op.AccountID, Archived: archived}, nil
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown op %q", op.Op)
+}
+
+// storedAccount reports whether the account has been scraped
+func (s *bulkService) storedAccount(accountID string) bool {
+	for _, account := range s.store.Accounts() {
+		if account.ID == accountID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/jobs"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestBulkOperations(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dataStore.RecordAccounts([]model.Account{{ID: "12345678"}}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := dataStore.SaveTransactions("12345678", []model.Transaction{
+		{ID: "txn_1", Date: "2023-10-17", Description: "EFTPOS Purchase - COLES 0482 MELBOURNE"},
+		{ID: "txn_2", Date: "2023-10-16", Description: "EFTPOS Purchase - WOOLWORTHS 1234"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewBulkService(dataStore, NewAnnotationService(dataStore, time.Hour), NewCategoryService(dataStore), jobs.NewManager(time.Hour))
+
+	if _, err := svc.Submit(model.BulkRequest{Operations: []model.BulkOperation{{Op: "delete"}}}); !errors.Is(err, ErrInvalidBulkRequest) {
+		t.Errorf("expected an unknown op to be rejected, got %v", err)
+	}
+
+	job, err := svc.Submit(model.BulkRequest{Operations: []model.BulkOperation{
+		{Op: model.BulkOpTag, AccountID: "12345678", TransactionID: "txn_1", Tag: "groceries"},
+		{Op: model.BulkOpTag, AccountID: "12345678", TransactionID: "txn_9", Tag: "groceries"},
+		{Op: model.BulkOpRecategorize, Merchant: "Coles", Category: "Groceries"},
+		{Op: model.BulkOpArchive, AccountID: "12345678"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for job.Status != model.JobStatusCompleted {
+		if time.Now().After(deadline) {
+			t.Fatal("job didn't complete")
+		}
+		time.Sleep(time.Millisecond)
+		if job, err = svc.Job(job.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if job.Succeeded != 3 || job.Failed != 1 || job.Items[1].Status != model.JobItemFailed {
+		t.Errorf("expected only the unknown transaction to fail, got %+v", job.Items)
+	}
+	if result, ok := job.Items[2].Result.(model.RecategorizeResult); !ok || result.Updated != 1 {
+		t.Errorf("expected one transaction recategorised, got %+v", job.Items[2].Result)
+	}
+	for _, transaction := range dataStore.Transactions("12345678") {
+		isColes := transaction.ID == "txn_1"
+		if (transaction.Category != nil) != isColes {
+			t.Errorf("unexpected category on %s: %v", transaction.ID, transaction.Category)
+		}
+	}
+	if !dataStore.AccountArchived("12345678") {
+		t.Error("expected the account to be archived")
+	}
+
+	if _, err := svc.Job("job_missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected job not found, got %v", err)
+	}
+}
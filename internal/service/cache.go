@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/cache"
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// CachePolicy controls serving stored data instead of scraping. Data
+// scraped within TTL is served as is. Older data, up to MaxStale old, is
+// served marked stale while a background scrape refreshes it, so requests
+// don't wait on the browser. A zero TTL scrapes on every request, and a
+// zero MaxStale serves stale data however old it is.
+//
+// When Backend is set, scrape results are shared through it for TTL, so
+// API instances sharing a backend reuse each other's scrapes instead of
+// each logging in to NAB.
+type CachePolicy struct {
+	TTL      time.Duration
+	MaxStale time.Duration
+	Backend  cache.Cache
+}
+
+// freshness is how usable stored data is under a cache policy
+type freshness int
+
+const (
+	freshnessExpired freshness = iota
+	freshnessStale
+	freshnessFresh
+)
+
+// freshness classifies data scraped at scrapedAt
+func (p CachePolicy) freshness(scrapedAt, now time.Time) freshness {
+	age := now.Sub(scrapedAt)
+	switch {
+	case p.TTL <= 0:
+		return freshnessExpired
+	case age < p.TTL:
+		return freshnessFresh
+	case p.MaxStale <= 0 || age < p.MaxStale:
+		return freshnessStale
+	}
+	return freshnessExpired
+}
+
+// scrapeShared scrapes through scrape, first checking whether an instance
+// sharing the cache backend scraped the same thing within the TTL, and
+// sharing the result otherwise. The backend is best effort: if it can't
+// be reached, NAB is scraped as usual.
+func scrapeShared[T any](ctx context.Context, s *accountService, key string, fn func() (T, error)) (T, error) {
+	policy := s.cachePolicy()
+	backend := policy.Backend
+	if backend == nil || policy.TTL <= 0 {
+		return scrape(ctx, s, fn)
+	}
+
+	key = "scrape:" + key
+	s.cleared.wrote(key)
+	if raw, err := backend.Get(ctx, key); err == nil {
+		var shared T
+		if json.Unmarshal(raw, &shared) == nil {
+			s.cacheStats.record(key, freshnessFresh)
+			return shared, nil
+		}
+	}
+	s.cacheStats.record(key, freshnessExpired)
+
+	result, err := scrape(ctx, s, fn)
+	if err == nil {
+		if raw, err := json.Marshal(result); err == nil {
+			_ = backend.Set(ctx, key, raw, policy.TTL)
+		}
+	}
+	return result, err
+}
+
+// cachePolicy returns the cache policy in force
+func (s *accountService) cachePolicy() CachePolicy {
+	s.cacheMu.RLock()
+	defer s.cacheMu.RUnlock()
+
+	return s.cache
+}
+
+// SetCacheTTL changes how long scrapes are served from the cache and how
+// stale they may get, from the next request on. The shared backend is
+// kept.
+func (s *accountService) SetCacheTTL(ttl, maxStale time.Duration) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	s.cache.TTL = ttl
+	s.cache.MaxStale = maxStale
+}
+
+// CacheClearer is implemented by services that can forget what they have
+// cached, so the next request scrapes NAB
+type CacheClearer interface {
+	ClearCache(ctx context.Context) (*model.CacheClearResponse, error)
+}
+
+// cacheClearing remembers when the cache was last cleared and when stored
+// entries were evicted one at a time, and the shared entries this instance
+// has written so clearing can remove them
+type cacheClearing struct {
+	mu        sync.Mutex
+	clearedAt time.Time
+	evicted   map[string]time.Time
+	keys      map[string]bool
+}
+
+// wrote notes a shared entry this instance may have written
+func (c *cacheClearing) wrote(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.keys == nil {
+		c.keys = make(map[string]bool)
+	}
+	c.keys[key] = true
+}
+
+// clear marks everything scraped until now as expired, returning the
+// shared entries to remove
+func (c *cacheClearing) clear(now time.Time) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.clearedAt = now
+	c.evicted = nil
+	keys := make([]string, 0, len(c.keys))
+	for key := range c.keys {
+		keys = append(keys, key)
+	}
+	c.keys = nil
+	return keys
+}
+
+// evict marks a stored entry scraped until now as expired
+func (c *cacheClearing) evict(key string, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.evicted == nil {
+		c.evicted = make(map[string]time.Time)
+	}
+	c.evicted[key] = now
+}
+
+// forget stops tracking a shared entry once it has been removed
+func (c *cacheClearing) forget(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.keys, key)
+}
+
+// shared lists the shared entries this instance may have written, sorted
+func (c *cacheClearing) shared() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]string, 0, len(c.keys))
+	for key := range c.keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// expired reports whether the stored entry key, scraped at scrapedAt,
+// predates a clear or its eviction
+func (c *cacheClearing) expired(key string, scrapedAt time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return !scrapedAt.After(c.clearedAt) || !scrapedAt.After(c.evicted[key])
+}
+
+// ClearCache stops stored data being served instead of scraping until it's
+// scraped again, and removes the shared entries this instance wrote. The
+// stored data itself is kept for history and for when NAB is unavailable.
+func (s *accountService) ClearCache(ctx context.Context) (*model.CacheClearResponse, error) {
+	now := time.Now()
+	response := &model.CacheClearResponse{ClearedAt: now}
+	for _, key := range s.cleared.clear(now) {
+		if err := s.cachePolicy().Backend.Delete(ctx, key); err != nil {
+			return response, fmt.Errorf("failed to remove %s from the shared cache: %w", key, err)
+		}
+		response.SharedEntriesRemoved++
+	}
+	return response, nil
+}
+
+// freshness classifies the stored entry key, scraped at scrapedAt,
+// treating anything scraped before the cache was last cleared or the entry
+// was evicted as expired
+func (s *accountService) freshness(key string, scrapedAt time.Time) freshness {
+	if s.cleared.expired(key, scrapedAt) {
+		return freshnessExpired
+	}
+	return s.cachePolicy().freshness(scrapedAt, time.Now())
+}
+
+// revalidator runs background refreshes, at most one at a time for each
+// key so a burst of requests for stale data scrapes once
+type revalidator struct {
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+// start runs refresh in the background unless one is already running for
+// the key. Refreshes outlive the request that triggered them, so they
+// aren't cancelled with it.
+func (r *revalidator) start(key string, refresh func(ctx context.Context)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.running[key] {
+		return
+	}
+	if r.running == nil {
+		r.running = make(map[string]bool)
+	}
+	r.running[key] = true
+
+	go func() {
+		defer func() {
+			r.mu.Lock()
+			delete(r.running, key)
+			r.mu.Unlock()
+		}()
+		refresh(context.Background())
+	}()
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/cache"
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// accountsCacheKey is the stored entry for the account list
+const accountsCacheKey = "accounts"
+
+// accountCacheKey is the stored entry for an account's details
+func accountCacheKey(accountID string) string {
+	return "account:" + accountID
+}
+
+// CacheInspector is implemented by services that can report on what they
+// have cached and evict individual entries
+type CacheInspector interface {
+	CacheStats(ctx context.Context) (*model.CacheStatsResponse, error)
+	EvictCache(ctx context.Context, keys []string) (*model.CacheEvictResponse, error)
+}
+
+// cacheCounts counts how requests for a cached entry were served
+type cacheCounts struct {
+	hits, staleHits, misses int64
+}
+
+// cacheStats counts cache hits and misses by entry since startup
+type cacheStats struct {
+	mu      sync.Mutex
+	entries map[string]*cacheCounts
+}
+
+// record counts a request for key served fresh or stale from the cache, or
+// missed because the entry was expired or absent
+func (c *cacheStats) record(key string, served freshness) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[string]*cacheCounts)
+	}
+	counts, ok := c.entries[key]
+	if !ok {
+		counts = &cacheCounts{}
+		c.entries[key] = counts
+	}
+	switch served {
+	case freshnessFresh:
+		counts.hits++
+	case freshnessStale:
+		counts.staleHits++
+	default:
+		counts.misses++
+	}
+}
+
+// counts returns the counts for key
+func (c *cacheStats) counts(key string) cacheCounts {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if counts, ok := c.entries[key]; ok {
+		return *counts
+	}
+	return cacheCounts{}
+}
+
+// totals returns the counts across every entry
+func (c *cacheStats) totals() cacheCounts {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var totals cacheCounts
+	for _, counts := range c.entries {
+		totals.hits += counts.hits
+		totals.staleHits += counts.staleHits
+		totals.misses += counts.misses
+	}
+	return totals
+}
+
+// servedFreshness is the freshness of data served from the cache
+func servedFreshness(stale bool) freshness {
+	if stale {
+		return freshnessStale
+	}
+	return freshnessFresh
+}
+
+// freshnessStates names freshness levels as cache entry states
+var freshnessStates = map[freshness]string{
+	freshnessFresh:   model.CacheEntryStateFresh,
+	freshnessStale:   model.CacheEntryStateStale,
+	freshnessExpired: model.CacheEntryStateExpired,
+}
+
+// CacheStats reports the cache policy, hits and misses since startup, and
+// every stored and shared entry with its age and size
+func (s *accountService) CacheStats(ctx context.Context) (*model.CacheStatsResponse, error) {
+	now := time.Now()
+	policy := s.cachePolicy()
+	totals := s.cacheStats.totals()
+	response := &model.CacheStatsResponse{
+		TTLSeconds:      int64(policy.TTL.Seconds()),
+		MaxStaleSeconds: int64(policy.MaxStale.Seconds()),
+		SharedBackend:   policy.Backend != nil,
+		Hits:            totals.hits,
+		StaleHits:       totals.staleHits,
+		Misses:          totals.misses,
+		Entries:         []model.CacheEntry{},
+		Timestamp:       now,
+	}
+	if requests := totals.hits + totals.staleHits + totals.misses; requests > 0 {
+		response.HitRatio = float64(totals.hits+totals.staleHits) / float64(requests)
+	}
+	s.cleared.mu.Lock()
+	if !s.cleared.clearedAt.IsZero() {
+		clearedAt := s.cleared.clearedAt
+		response.ClearedAt = &clearedAt
+	}
+	s.cleared.mu.Unlock()
+
+	accounts := s.store.Accounts()
+	var listScrapedAt time.Time
+	for _, account := range accounts {
+		if account.LastUpdated != nil && (listScrapedAt.IsZero() || account.LastUpdated.Before(listScrapedAt)) {
+			listScrapedAt = *account.LastUpdated
+		}
+	}
+	if len(accounts) > 0 && !listScrapedAt.IsZero() {
+		response.Entries = append(response.Entries, s.storedCacheEntry(accountsCacheKey, listScrapedAt, accounts, now))
+	}
+	for _, account := range accounts {
+		transactionsAt, ok := s.store.TransactionsUpdatedAt(account.ID)
+		if !ok || account.LastUpdated == nil {
+			continue
+		}
+		scrapedAt := *account.LastUpdated
+		if transactionsAt.Before(scrapedAt) {
+			scrapedAt = transactionsAt
+		}
+		details := struct {
+			Account      model.Account       `json:"account"`
+			Transactions []model.Transaction `json:"transactions"`
+		}{account, s.store.Transactions(account.ID)}
+		response.Entries = append(response.Entries, s.storedCacheEntry(accountCacheKey(account.ID), scrapedAt, details, now))
+	}
+
+	if policy.Backend == nil {
+		return response, nil
+	}
+	for _, key := range s.cleared.shared() {
+		raw, err := policy.Backend.Get(ctx, key)
+		if errors.Is(err, cache.ErrMiss) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from the shared cache: %w", key, err)
+		}
+		counts := s.cacheStats.counts(key)
+		response.Entries = append(response.Entries, model.CacheEntry{
+			Key:       key,
+			Kind:      model.CacheEntryKindShared,
+			State:     model.CacheEntryStateFresh,
+			SizeBytes: len(raw),
+			Hits:      counts.hits,
+			Misses:    counts.misses,
+		})
+	}
+	return response, nil
+}
+
+// storedCacheEntry describes a stored entry, sized as its JSON encoding
+func (s *accountService) storedCacheEntry(key string, scrapedAt time.Time, data interface{}, now time.Time) model.CacheEntry {
+	counts := s.cacheStats.counts(key)
+	entry := model.CacheEntry{
+		Key:        key,
+		Kind:       model.CacheEntryKindStored,
+		State:      freshnessStates[s.freshness(key, scrapedAt)],
+		ScrapedAt:  &scrapedAt,
+		AgeSeconds: int64(now.Sub(scrapedAt).Seconds()),
+		Hits:       counts.hits,
+		StaleHits:  counts.staleHits,
+		Misses:     counts.misses,
+	}
+	if raw, err := json.Marshal(data); err == nil {
+		entry.SizeBytes = len(raw)
+	}
+	return entry
+}
+
+// EvictCache expires the given entries so the next request for them
+// scrapes NAB. Evicting a stored entry also removes the shared entries
+// scraped along with it, so the scrape isn't answered from them. Keys
+// that aren't cached are reported as not found.
+func (s *accountService) EvictCache(ctx context.Context, keys []string) (*model.CacheEvictResponse, error) {
+	now := time.Now()
+	response := &model.CacheEvictResponse{Evicted: []string{}, NotFound: []string{}, EvictedAt: now}
+
+	stats, err := s.CacheStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cached := make(map[string]string)
+	for _, entry := range stats.Entries {
+		cached[entry.Key] = entry.Kind
+	}
+
+	evicted := make(map[string]bool)
+	removeShared := func(key string) error {
+		if evicted[key] {
+			return nil
+		}
+		if err := s.cachePolicy().Backend.Delete(ctx, key); err != nil {
+			return fmt.Errorf("failed to remove %s from the shared cache: %w", key, err)
+		}
+		s.cleared.forget(key)
+		evicted[key] = true
+		response.Evicted = append(response.Evicted, key)
+		return nil
+	}
+
+	for _, key := range keys {
+		switch cached[key] {
+		case model.CacheEntryKindStored:
+			if !evicted[key] {
+				s.cleared.evict(key, now)
+				evicted[key] = true
+				response.Evicted = append(response.Evicted, key)
+			}
+			for _, shared := range sharedCacheKeys(key, cached) {
+				if err := removeShared(shared); err != nil {
+					return response, err
+				}
+			}
+		case model.CacheEntryKindShared:
+			if err := removeShared(key); err != nil {
+				return response, err
+			}
+		default:
+			response.NotFound = append(response.NotFound, key)
+		}
+	}
+	return response, nil
+}
+
+// sharedCacheKeys lists the cached shared entries scraped along with the
+// stored entry key: the account list, or an account's transactions
+func sharedCacheKeys(key string, cached map[string]string) []string {
+	prefix := "scrape:accounts"
+	if accountID, ok := strings.CutPrefix(key, "account:"); ok {
+		prefix = "scrape:transactions:" + accountID + ":"
+	}
+
+	var keys []string
+	for shared, kind := range cached {
+		if kind == model.CacheEntryKindShared && strings.HasPrefix(shared, prefix) {
+			keys = append(keys, shared)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
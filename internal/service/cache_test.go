@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/cache"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &flakyClient{}
+	svc := NewAccountService(client, dataStore, nil, AlertThresholds{}, RetryPolicy{}, BreakerPolicy{}, CachePolicy{TTL: time.Minute, MaxStale: time.Hour}, nil, nil)
+
+	if _, err := svc.GetAllAccounts(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	accounts, err := svc.GetAllAccounts(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.calls != 1 || accounts[0].Stale {
+		t.Fatalf("expected fresh accounts served from the store, got %d scrapes", client.calls)
+	}
+
+	// Age the stored accounts past the TTL
+	scrapedAt := time.Now().Add(-10 * time.Minute)
+	for i := range accounts {
+		accounts[i].LastUpdated = &scrapedAt
+	}
+	if err := dataStore.RecordAccounts(accounts, scrapedAt); err != nil {
+		t.Fatal(err)
+	}
+
+	accounts, err = svc.GetAllAccounts(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !accounts[0].Stale || !accounts[0].LastUpdated.Equal(scrapedAt) {
+		t.Errorf("expected stale accounts as of the last scrape, got %+v", accounts[0])
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		stored := dataStore.Accounts()
+		if stored[0].LastUpdated.After(scrapedAt) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected a background refresh")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if got := (CachePolicy{TTL: time.Minute, MaxStale: time.Hour}).freshness(scrapedAt.Add(-time.Hour), time.Now()); got != freshnessExpired {
+		t.Errorf("expected data older than MaxStale to be scraped again, got %v", got)
+	}
+}
+
+func TestSharedCacheBackend(t *testing.T) {
+	backend := cache.NewMemory()
+	policy := CachePolicy{TTL: time.Minute, Backend: backend}
+
+	newInstance := func() (AccountService, *flakyClient) {
+		dataStore, err := store.Open("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		client := &flakyClient{}
+		return NewAccountService(client, dataStore, nil, AlertThresholds{}, RetryPolicy{}, BreakerPolicy{}, policy, nil, nil), client
+	}
+
+	first, firstClient := newInstance()
+	scraped, err := first.GetAllAccounts(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, secondClient := newInstance()
+	shared, err := second.GetAllAccounts(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if firstClient.calls != 1 || secondClient.calls != 0 {
+		t.Fatalf("expected one scrape shared between instances, got %d and %d", firstClient.calls, secondClient.calls)
+	}
+	if len(shared) != len(scraped) || !shared[0].LastUpdated.Equal(*scraped[0].LastUpdated) {
+		t.Errorf("expected the shared accounts with their original scrape time, got %+v", shared)
+	}
+}
+
+func TestClearCache(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &flakyClient{}
+	svc := NewAccountService(client, dataStore, nil, AlertThresholds{}, RetryPolicy{}, BreakerPolicy{}, CachePolicy{TTL: time.Minute, Backend: cache.NewMemory()}, nil, nil)
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.GetAllAccounts(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if client.calls != 1 {
+		t.Fatalf("expected the second request served from the cache, got %d scrapes", client.calls)
+	}
+
+	cleared, err := svc.(CacheClearer).ClearCache(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cleared.SharedEntriesRemoved != 1 {
+		t.Errorf("expected the shared accounts entry removed, got %d", cleared.SharedEntriesRemoved)
+	}
+
+	if _, err := svc.GetAllAccounts(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if client.calls != 2 {
+		t.Errorf("expected a scrape after clearing the cache, got %d scrapes", client.calls)
+	}
+}
+
+func TestCacheStatsAndEvict(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &flakyClient{}
+	svc := NewAccountService(client, dataStore, nil, AlertThresholds{}, RetryPolicy{}, BreakerPolicy{}, CachePolicy{TTL: time.Minute, Backend: cache.NewMemory()}, nil, nil)
+	inspector := svc.(CacheInspector)
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.GetAllAccounts(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := inspector.CacheStats(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("expected a stored hit and stored and shared misses, got %d hits and %d misses", stats.Hits, stats.Misses)
+	}
+	entries := make(map[string]model.CacheEntry)
+	for _, entry := range stats.Entries {
+		entries[entry.Key] = entry
+	}
+	if entry := entries["accounts"]; entry.State != model.CacheEntryStateFresh || entry.SizeBytes == 0 || entry.Hits != 1 {
+		t.Errorf("unexpected accounts entry %+v", entry)
+	}
+	if entry := entries["scrape:accounts"]; entry.Kind != model.CacheEntryKindShared {
+		t.Errorf("expected the shared accounts entry, got %+v", stats.Entries)
+	}
+
+	evicted, err := inspector.EvictCache(context.Background(), []string{"accounts", "account:missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evicted.Evicted) != 2 || len(evicted.NotFound) != 1 {
+		t.Errorf("expected accounts and its shared entry evicted, got %+v", evicted)
+	}
+
+	if _, err := svc.GetAllAccounts(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if client.calls != 2 {
+		t.Errorf("expected a scrape after evicting the accounts, got %d scrapes", client.calls)
+	}
+}
+
+func TestSetCacheTTL(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &flakyClient{}
+	svc := NewAccountService(client, dataStore, nil, AlertThresholds{}, RetryPolicy{}, BreakerPolicy{}, CachePolicy{}, nil, nil)
+
+	svc.(ReloadableService).SetCacheTTL(time.Minute, time.Hour)
+	for i := 0; i < 2; i++ {
+		if _, err := svc.GetAllAccounts(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if client.calls != 1 {
+		t.Errorf("expected the reloaded TTL to serve stored accounts, got %d scrapes", client.calls)
+	}
+}
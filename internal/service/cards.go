@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/notify"
+)
+
+// Card errors
+var (
+	ErrCardNotFound = errors.New("card not found")
+
+	// ErrCardRejected is returned when NAB refuses to lock or unlock a card
+	ErrCardRejected = errors.New("card change rejected by NAB")
+
+	// ErrCardsUnsupported is returned when the NAB client cannot manage cards
+	ErrCardsUnsupported = errors.New("cards not supported")
+)
+
+// CardClient is implemented by NAB clients that can scrape cards and drive
+// NAB's temporary card block
+type CardClient interface {
+	GetCards(ctx context.Context) ([]model.Card, error)
+	SetCardLocked(ctx context.Context, card model.Card, locked bool) (*model.Card, error)
+}
+
+// CardService defines the interface for card operations
+type CardService interface {
+	GetCards(ctx context.Context) ([]model.Card, error)
+	LockCard(ctx context.Context, cardID string) (*model.Card, error)
+	UnlockCard(ctx context.Context, cardID string) (*model.Card, error)
+}
+
+// cardService implements CardService
+type cardService struct {
+	nabClient BankProvider
+	alerts    *alerter
+}
+
+// NewCardService creates a new card service. Scrape failures are pushed to
+// the notifier; a nil notifier disables them.
+func NewCardService(nabClient BankProvider, notifier notify.Notifier) CardService {
+	return &cardService{
+		nabClient: nabClient,
+		alerts:    newAlerter(notifier, AlertThresholds{}),
+	}
+}
+
+// GetCards retrieves the cards on the customer's accounts
+func (s *cardService) GetCards(ctx context.Context) ([]model.Card, error) {
+	client, ok := s.nabClient.(CardClient)
+	if !ok {
+		return nil, ErrCardsUnsupported
+	}
+
+	cards, err := client.GetCards(ctx)
+	if err != nil {
+		s.alerts.scrapeFailed(err)
+		return nil, err
+	}
+
+	return cards, nil
+}
+
+// LockCard applies NAB's temporary block to a card
+func (s *cardService) LockCard(ctx context.Context, cardID string) (*model.Card, error) {
+	return s.setLocked(ctx, cardID, true)
+}
+
+// UnlockCard removes NAB's temporary block from a card
+func (s *cardService) UnlockCard(ctx context.Context, cardID string) (*model.Card, error) {
+	return s.setLocked(ctx, cardID, false)
+}
+
+// setLocked locks or unlocks a card. A card already in the requested state
+// is returned as is, so retrying is safe.
+func (s *cardService) setLocked(ctx context.Context, cardID string, locked bool) (*model.Card, error) {
+	client, ok := s.nabClient.(CardClient)
+	if !ok {
+		return nil, ErrCardsUnsupported
+	}
+
+	cards, err := client.GetCards(ctx)
+	if err != nil {
+		s.alerts.scrapeFailed(err)
+		return nil, err
+	}
+
+	var card *model.Card
+	for i := range cards {
+		if cards[i].ID == cardID {
+			card = &cards[i]
+			break
+		}
+	}
+	if card == nil {
+		return nil, ErrCardNotFound
+	}
+
+	if card.Status == model.CardStatusCancelled {
+		return nil, fmt.Errorf("%w: card is cancelled", ErrCardRejected)
+	}
+	if (card.Status == model.CardStatusLocked) == locked {
+		return card, nil
+	}
+
+	updated, err := client.SetCardLocked(ctx, *card, locked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update card: %w", err)
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestCardServiceLockAndUnlock(t *testing.T) {
+	service := NewCardService(NewMockNABClient(), nil)
+	ctx := context.Background()
+
+	card, err := service.LockCard(ctx, "card_001")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if card.Status != model.CardStatusLocked {
+		t.Errorf("expected locked card, got %s", card.Status)
+	}
+
+	// Locking again leaves the card as it is
+	card, err = service.LockCard(ctx, "card_001")
+	if err != nil || card.Status != model.CardStatusLocked {
+		t.Errorf("expected card to stay locked, got %+v, %v", card, err)
+	}
+
+	card, err = service.UnlockCard(ctx, "card_001")
+	if err != nil || card.Status != model.CardStatusActive {
+		t.Errorf("expected unlocked card, got %+v, %v", card, err)
+	}
+
+	if _, err := service.LockCard(ctx, "card_999"); !errors.Is(err, ErrCardNotFound) {
+		t.Errorf("expected ErrCardNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/search"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+var (
+	// ErrCategoryRuleNotFound is returned for unknown category rules
+	ErrCategoryRuleNotFound = errors.New("category rule not found")
+
+	// ErrCategoryRuleExists is returned when creating a rule for a pattern
+	// another rule already has
+	ErrCategoryRuleExists = errors.New("category rule already exists")
+
+	// ErrInvalidCategoryRule is returned when a rule's pattern or category
+	// is unusable
+	ErrInvalidCategoryRule = errors.New("invalid category rule")
+)
+
+// Category rule limits
+const (
+	maxRulePatternLength = 100
+	maxCategoryLength    = 64
+)
+
+// CategoryService manages the rules that file transactions under
+// categories. Every change is reapplied to the stored transactions
+// straight away, as well as to transactions scraped later.
+type CategoryService interface {
+	Categories() []model.Category
+	Rules() []model.CategoryRule
+	CreateRule(req model.CategoryRuleRequest) (*model.CategoryRuleResponse, error)
+	UpdateRule(id string, req model.CategoryRuleRequest, version int) (*model.CategoryRuleResponse, error)
+	DeleteRule(id string, version int) (*model.CategoryRuleResponse, error)
+	SetMerchantCategory(merchant, category string) (*model.CategoryRuleResponse, error)
+}
+
+// categoryService implements CategoryService
+type categoryService struct {
+	store *store.Store
+	now   func() time.Time
+}
+
+// NewCategoryService creates a category service over the stored rules
+func NewCategoryService(store *store.Store) CategoryService {
+	return &categoryService{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// Categories returns every category used by a rule or stored transaction,
+// by name
+func (s *categoryService) Categories() []model.Category {
+	byName := make(map[string]*model.Category)
+	category := func(name string) *model.Category {
+		if byName[name] == nil {
+			byName[name] = &model.Category{Name: name}
+		}
+		return byName[name]
+	}
+	for _, rule := range s.store.CategoryRules() {
+		category(rule.Category).Rules++
+	}
+	for name, count := range s.store.TransactionCategories() {
+		category(name).Transactions = count
+	}
+
+	categories := make([]model.Category, 0, len(byName))
+	for _, category := range byName {
+		categories = append(categories, *category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+	return categories
+}
+
+// Rules returns every category rule, oldest first
+func (s *categoryService) Rules() []model.CategoryRule {
+	return s.store.CategoryRules()
+}
+
+// CreateRule adds a category rule, refusing one whose pattern another rule
+// already has
+func (s *categoryService) CreateRule(req model.CategoryRuleRequest) (*model.CategoryRuleResponse, error) {
+	pattern, category, err := validateCategoryRule(req)
+	if err != nil {
+		return nil, err
+	}
+	if existing, ok := s.ruleForPattern(pattern); ok {
+		return nil, fmt.Errorf("%w: %s already files %q under %s", ErrCategoryRuleExists, existing.ID, existing.Pattern, existing.Category)
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate category rule ID: %w", err)
+	}
+	now := s.now()
+	rule := model.CategoryRule{
+		ID:        "rule_" + hex.EncodeToString(id),
+		Pattern:   pattern,
+		Category:  category,
+		Version:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	updated, err := s.store.SaveCategoryRule(rule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save category rule: %w", err)
+	}
+
+	return &model.CategoryRuleResponse{Rule: rule, Recategorized: updated}, nil
+}
+
+// UpdateRule replaces a rule's pattern and category. A non-zero version
+// makes the change conditional on the rule not having changed since.
+func (s *categoryService) UpdateRule(id string, req model.CategoryRuleRequest, version int) (*model.CategoryRuleResponse, error) {
+	pattern, category, err := validateCategoryRule(req)
+	if err != nil {
+		return nil, err
+	}
+	if existing, ok := s.ruleForPattern(pattern); ok && existing.ID != id {
+		return nil, fmt.Errorf("%w: %s already files %q under %s", ErrCategoryRuleExists, existing.ID, existing.Pattern, existing.Category)
+	}
+
+	rule, updated, ok, err := s.store.UpdateCategoryRule(id, func(rule *model.CategoryRule) error {
+		if err := CheckVersion(rule.Version, version); err != nil {
+			return err
+		}
+		rule.Pattern = pattern
+		rule.Category = category
+		rule.Version++
+		rule.UpdatedAt = s.now()
+		return nil
+	})
+	if !ok {
+		return nil, ErrCategoryRuleNotFound
+	}
+	if err != nil {
+		return nil, categoryRuleError("update", err)
+	}
+
+	return &model.CategoryRuleResponse{Rule: rule, Recategorized: updated}, nil
+}
+
+// DeleteRule removes a rule, conditionally on its version like UpdateRule
+func (s *categoryService) DeleteRule(id string, version int) (*model.CategoryRuleResponse, error) {
+	rule, updated, ok, err := s.store.DeleteCategoryRule(id, func(rule model.CategoryRule) error {
+		return CheckVersion(rule.Version, version)
+	})
+	if !ok {
+		return nil, ErrCategoryRuleNotFound
+	}
+	if err != nil {
+		return nil, categoryRuleError("delete", err)
+	}
+
+	return &model.CategoryRuleResponse{Rule: rule, Recategorized: updated}, nil
+}
+
+// SetMerchantCategory files a merchant under a category, changing the rule
+// for the merchant if there is one and creating it otherwise
+func (s *categoryService) SetMerchantCategory(merchant, category string) (*model.CategoryRuleResponse, error) {
+	req := model.CategoryRuleRequest{Pattern: merchant, Category: category}
+	pattern, _, err := validateCategoryRule(req)
+	if err != nil {
+		return nil, err
+	}
+	if existing, ok := s.ruleForPattern(pattern); ok {
+		return s.UpdateRule(existing.ID, req, 0)
+	}
+	return s.CreateRule(req)
+}
+
+// ruleForPattern finds the rule matching the same transactions as a
+// pattern, however differently it is worded
+func (s *categoryService) ruleForPattern(pattern string) (model.CategoryRule, bool) {
+	normalized := search.Normalize(pattern)
+	for _, rule := range s.store.CategoryRules() {
+		if search.Normalize(rule.Pattern) == normalized {
+			return rule, true
+		}
+	}
+	return model.CategoryRule{}, false
+}
+
+// validateCategoryRule trims a rule's pattern and category and checks they
+// are usable
+func validateCategoryRule(req model.CategoryRuleRequest) (string, string, error) {
+	pattern, category := strings.TrimSpace(req.Pattern), strings.TrimSpace(req.Category)
+	if search.Normalize(pattern) == "" || len(pattern) > maxRulePatternLength {
+		return "", "", fmt.Errorf("%w: pattern must be 1 to %d characters with at least one word", ErrInvalidCategoryRule, maxRulePatternLength)
+	}
+	if category == "" || len(category) > maxCategoryLength {
+		return "", "", fmt.Errorf("%w: category must be 1 to %d characters", ErrInvalidCategoryRule, maxCategoryLength)
+	}
+	return pattern, category, nil
+}
+
+// categoryRuleError passes on version mismatch errors, wrapping anything
+// else as a failure to save
+func categoryRuleError(action string, err error) error {
+	if errors.Is(err, ErrVersionMismatch) {
+		return err
+	}
+	return fmt.Errorf("failed to %s category rule: %w", action, err)
+}
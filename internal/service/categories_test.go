@@ -0,0 +1,82 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestCategoryRules(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dataStore.SaveTransactions("12345678", []model.Transaction{
+		{ID: "txn_001", Date: "2023-10-17", Description: "EFTPOS Purchase - COLES SUPERMARKET"},
+		{ID: "txn_002", Date: "2023-10-16", Description: "EFTPOS 1234 COLES EXPRESS 0482 MELB"},
+		{ID: "txn_003", Date: "2023-10-15", Description: "Direct Debit - NETFLIX.COM"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewCategoryService(dataStore)
+	category := func(id string) string {
+		for _, transaction := range dataStore.Transactions("12345678") {
+			if transaction.ID == id && transaction.Category != nil {
+				return *transaction.Category
+			}
+		}
+		return ""
+	}
+
+	groceries, err := svc.CreateRule(model.CategoryRuleRequest{Pattern: "Coles", Category: "Groceries"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if groceries.Recategorized != 2 || category("txn_001") != "Groceries" || category("txn_002") != "Groceries" {
+		t.Fatalf("expected both Coles transactions recategorised, got %d", groceries.Recategorized)
+	}
+
+	// The longer pattern wins
+	fuel, err := svc.CreateRule(model.CategoryRuleRequest{Pattern: "coles express", Category: "Fuel"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fuel.Recategorized != 1 || category("txn_002") != "Fuel" || category("txn_001") != "Groceries" {
+		t.Errorf("expected only Coles Express refiled as fuel, got %d", fuel.Recategorized)
+	}
+
+	if _, err := svc.CreateRule(model.CategoryRuleRequest{Pattern: "COLES", Category: "Shopping"}); !errors.Is(err, ErrCategoryRuleExists) {
+		t.Errorf("expected a duplicate pattern to be refused, got %v", err)
+	}
+	if _, err := svc.CreateRule(model.CategoryRuleRequest{Pattern: "  ", Category: "Shopping"}); !errors.Is(err, ErrInvalidCategoryRule) {
+		t.Errorf("expected an empty pattern to be refused, got %v", err)
+	}
+	if _, err := svc.UpdateRule(groceries.Rule.ID, model.CategoryRuleRequest{Pattern: "coles", Category: "Food"}, 2); !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("expected a stale version to be refused, got %v", err)
+	}
+
+	updated, err := svc.UpdateRule(groceries.Rule.ID, model.CategoryRuleRequest{Pattern: "netflix", Category: "Entertainment"}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Rule.Version != 2 || category("txn_003") != "Entertainment" || category("txn_001") != "" {
+		t.Errorf("expected the rule moved from Coles to Netflix, got version %d", updated.Rule.Version)
+	}
+
+	if _, err := svc.DeleteRule(fuel.Rule.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if category("txn_002") != "" {
+		t.Errorf("expected the deleted rule's category cleared, got %q", category("txn_002"))
+	}
+	if _, err := svc.DeleteRule(fuel.Rule.ID, 0); !errors.Is(err, ErrCategoryRuleNotFound) {
+		t.Errorf("expected deleting twice to fail, got %v", err)
+	}
+
+	categories := svc.Categories()
+	if len(categories) != 1 || categories[0].Name != "Entertainment" || categories[0].Rules != 1 || categories[0].Transactions != 1 {
+		t.Errorf("unexpected categories %+v", categories)
+	}
+}
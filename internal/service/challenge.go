@@ -0,0 +1,31 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// ErrChallengeRequired is returned when NAB shows a captcha or security
+// check that wasn't solved
+var ErrChallengeRequired = errors.New("NAB security challenge must be solved")
+
+// ChallengeError describes the challenge behind ErrChallengeRequired
+type ChallengeError struct {
+	Challenge model.Challenge
+}
+
+// Error describes the challenge and where its screenshot was saved
+func (e *ChallengeError) Error() string {
+	message := fmt.Sprintf("%v: %s at %s", ErrChallengeRequired, e.Challenge.Kind, e.Challenge.URL)
+	if e.Challenge.Screenshot != "" {
+		message += ", screenshot " + e.Challenge.Screenshot
+	}
+	return message
+}
+
+// Unwrap makes the error match ErrChallengeRequired
+func (e *ChallengeError) Unwrap() error {
+	return ErrChallengeRequired
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// checkRunningBalances flags transactions, newest first as stored, whose
+// balance before them isn't left by any other transaction. Only the oldest
+// transaction should start the chain of running balances; any other start
+// means transactions between it and the one before are missing. Matching
+// on balances rather than order keeps same-day transactions, which NAB
+// doesn't timestamp, from raising false alarms. Transactions without a
+// readable balance are skipped.
+func checkRunningBalances(transactions []model.Transaction) []model.SyncWarning {
+	balances := make(map[int64]bool, len(transactions))
+	for _, transaction := range transactions {
+		if balance, err := parseBalanceCents(transaction.Balance.Amount); err == nil {
+			balances[balance] = true
+		}
+	}
+
+	var starts []int
+	for i, transaction := range transactions {
+		if previous, ok := previousBalance(transaction); ok && !balances[previous] {
+			starts = append(starts, i)
+		}
+	}
+
+	// The oldest start is where the stored history begins
+	oldest := -1
+	for _, i := range starts {
+		if oldest < 0 || transactions[i].Date <= transactions[oldest].Date {
+			oldest = i
+		}
+	}
+
+	var warnings []model.SyncWarning
+	for _, i := range starts {
+		if i != oldest {
+			warnings = append(warnings, balanceGap(transactions[i]))
+		}
+	}
+	return warnings
+}
+
+// previousBalance returns an account's balance, in cents, before a
+// transaction, and false if the transaction's amounts are unreadable
+func previousBalance(transaction model.Transaction) (int64, bool) {
+	balance, err := parseBalanceCents(transaction.Balance.Amount)
+	if err != nil {
+		return 0, false
+	}
+	amount, err := parseBalanceCents(transaction.Amount.Amount)
+	if err != nil {
+		return 0, false
+	}
+	return balance - amount, true
+}
+
+// balanceGap warns that the balance before a transaction is unaccounted for
+func balanceGap(transaction model.Transaction) model.SyncWarning {
+	cents, _ := previousBalance(transaction)
+	previous := formatCents(cents)
+	return model.SyncWarning{
+		Type:                    model.SyncWarningBalanceGap,
+		TransactionID:           transaction.ID,
+		Date:                    transaction.Date,
+		ExpectedPreviousBalance: model.Money{Amount: previous},
+		Message:                 fmt.Sprintf("No transaction leaves a balance of %s before this one; transactions may be missing", previous),
+	}
+}
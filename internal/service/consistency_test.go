@@ -0,0 +1,40 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestCheckRunningBalances(t *testing.T) {
+	transaction := func(id, date, amount, balance string) model.Transaction {
+		return model.Transaction{ID: id, Date: date, Amount: model.Money{Amount: amount}, Balance: model.Money{Amount: balance}}
+	}
+
+	// Newest first, as stored, with same-day transactions out of order
+	consistent := []model.Transaction{
+		transaction("t4", "2023-10-17", "-20.00", "2500.00"),
+		transaction("t5", "2023-10-17", "-10.00", "2490.00"),
+		transaction("t3", "2023-10-16", "-85.67", "2520.00"),
+		transaction("t2", "2023-10-15", "2500.00", "2605.67"),
+		transaction("t1", "2023-10-14", "-100.00", "105.67"),
+	}
+	if warnings := checkRunningBalances(consistent); len(warnings) != 0 {
+		t.Errorf("expected no warnings, got %+v", warnings)
+	}
+
+	// t3 went missing, so t4 follows a balance nothing left
+	missing := append(append([]model.Transaction{}, consistent[:2]...), consistent[3:]...)
+	warnings := checkRunningBalances(missing)
+	if len(warnings) != 1 {
+		t.Fatalf("expected one gap, got %+v", warnings)
+	}
+	if warnings[0].TransactionID != "t4" || warnings[0].ExpectedPreviousBalance.Amount != "2520.00" || warnings[0].Type != model.SyncWarningBalanceGap {
+		t.Errorf("unexpected warning %+v", warnings[0])
+	}
+
+	unreadable := append([]model.Transaction{transaction("t6", "2023-10-18", "", "")}, consistent...)
+	if warnings := checkRunningBalances(unreadable); len(warnings) != 0 {
+		t.Errorf("expected transactions without balances skipped, got %+v", warnings)
+	}
+}
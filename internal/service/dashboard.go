@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// dashboardTransactions is how many of each account's transactions the
+// dashboard shows
+const dashboardTransactions = 5
+
+// DashboardService assembles the home screen view
+type DashboardService interface {
+	Dashboard(ctx context.Context) (*model.DashboardResponse, error)
+}
+
+// dashboardService implements DashboardService
+type dashboardService struct {
+	accounts AccountService
+	store    *store.Store
+	reports  *SyncReporter
+	now      func() time.Time
+}
+
+// NewDashboardService creates a dashboard service. Accounts come from the
+// account service, so its caching applies; transactions are the stored
+// ones. The sync reporter may be nil.
+func NewDashboardService(accounts AccountService, store *store.Store, reports *SyncReporter) DashboardService {
+	return &dashboardService{
+		accounts: accounts,
+		store:    store,
+		reports:  reports,
+		now:      time.Now,
+	}
+}
+
+// Dashboard returns every account with its last few transactions, the
+// balances totalled by account type and currency, and a summary of the
+// last sync
+func (s *dashboardService) Dashboard(ctx context.Context) (*model.DashboardResponse, error) {
+	accounts, err := s.accounts.GetAllAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.DashboardResponse{
+		Accounts:    make([]model.DashboardAccount, 0, len(accounts)),
+		Totals:      []model.DashboardTotal{},
+		RetrievedAt: s.now(),
+	}
+	// Balances in different currencies are totalled separately
+	type totalKey struct{ accountType, currency string }
+	totals := make(map[totalKey]int64)
+	counts := make(map[totalKey]int)
+	for _, account := range accounts {
+		balance, err := parseBalanceCents(account.Balance.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("balance %q for account %s is unreadable: %w", account.Balance.Amount, account.ID, err)
+		}
+		key := totalKey{account.Type, account.Balance.CurrencyCode()}
+		totals[key] += balance
+		counts[key]++
+
+		// Stored transactions are newest first
+		recent := s.store.Transactions(account.ID)
+		if len(recent) > dashboardTransactions {
+			recent = recent[:dashboardTransactions]
+		}
+		if recent == nil {
+			recent = []model.Transaction{}
+		}
+		response.Accounts = append(response.Accounts, model.DashboardAccount{
+			Account:            account,
+			RecentTransactions: recent,
+		})
+	}
+	for key, cents := range totals {
+		response.Totals = append(response.Totals, model.DashboardTotal{
+			Type:     key.accountType,
+			Balance:  model.Money{Amount: formatCents(cents), Currency: key.currency},
+			Accounts: counts[key],
+		})
+	}
+	sort.Slice(response.Totals, func(i, j int) bool {
+		a, b := response.Totals[i], response.Totals[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Balance.Currency < b.Balance.Currency
+	})
+
+	if s.reports != nil {
+		if report, ok := s.reports.Last(); ok {
+			response.LastSync = &model.DashboardSync{
+				Status:        report.Status,
+				StartedAt:     report.StartedAt,
+				CompletedAt:   report.CompletedAt,
+				AccountsFound: report.AccountsFound,
+				Failed:        report.Failed,
+			}
+		}
+	}
+	return response, nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// fixedAccountService returns the same accounts every time
+type fixedAccountService struct {
+	accounts []model.Account
+}
+
+func (s fixedAccountService) GetAllAccounts(ctx context.Context) ([]model.Account, error) {
+	return s.accounts, nil
+}
+
+func (s fixedAccountService) GetAccountDetails(ctx context.Context, accountID string) (*model.AccountDetails, error) {
+	return nil, ErrAccountNotFound
+}
+
+func TestDashboard(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var transactions []model.Transaction
+	for day := 1; day <= 7; day++ {
+		transactions = append(transactions, model.Transaction{
+			ID:          fmt.Sprintf("txn_%d", day),
+			Date:        fmt.Sprintf("2024-05-%02d", day),
+			Description: fmt.Sprintf("Purchase %d", day),
+			Amount:      model.Money{Amount: "-1.00"},
+		})
+	}
+	if err := dataStore.SaveTransactions("1", transactions); err != nil {
+		t.Fatal(err)
+	}
+
+	accounts := fixedAccountService{accounts: []model.Account{
+		{ID: "1", Type: model.AccountTypeSavings, Balance: model.Money{Amount: "1,000.50"}},
+		{ID: "2", Type: model.AccountTypeSavings, Balance: model.Money{Amount: "250.00"}},
+		{ID: "3", Type: model.AccountTypeCredit, Balance: model.Money{Amount: "-300.00"}},
+		{ID: "4", Type: model.AccountTypeSavings, Balance: model.Money{Amount: "80.00", Currency: "USD"}},
+	}}
+	dashboard, err := NewDashboardService(accounts, dataStore, nil).Dashboard(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dashboard.Accounts) != 4 {
+		t.Fatalf("expected 4 accounts, got %+v", dashboard.Accounts)
+	}
+	recent := dashboard.Accounts[0].RecentTransactions
+	if len(recent) != dashboardTransactions || recent[0].ID != "txn_7" || recent[4].ID != "txn_3" {
+		t.Errorf("expected the five newest transactions, got %+v", recent)
+	}
+	if dashboard.Accounts[1].RecentTransactions == nil {
+		t.Error("expected an empty list for an account without transactions")
+	}
+
+	want := []model.DashboardTotal{
+		{Type: model.AccountTypeCredit, Balance: model.Money{Amount: "-300.00", Currency: "AUD"}, Accounts: 1},
+		{Type: model.AccountTypeSavings, Balance: model.Money{Amount: "1250.50", Currency: "AUD"}, Accounts: 2},
+		{Type: model.AccountTypeSavings, Balance: model.Money{Amount: "80.00", Currency: "USD"}, Accounts: 1},
+	}
+	if len(dashboard.Totals) != len(want) {
+		t.Fatalf("expected %+v, got %+v", want, dashboard.Totals)
+	}
+	for i := range want {
+		if dashboard.Totals[i] != want[i] {
+			t.Errorf("total %d: got %+v, want %+v", i, dashboard.Totals[i], want[i])
+		}
+	}
+	if dashboard.LastSync != nil {
+		t.Errorf("expected no last sync, got %+v", dashboard.LastSync)
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// renumberingClient returns the mock transactions under new IDs on every
+// scrape, as NAB can
+type renumberingClient struct {
+	MockNABClient
+	scrapes int
+}
+
+func (c *renumberingClient) GetAccountTransactions(ctx context.Context, accountID string) ([]model.Transaction, error) {
+	c.scrapes++
+	transactions, err := c.MockNABClient.GetAccountTransactions(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range transactions {
+		transactions[i].ID = fmt.Sprintf("%s_scrape%d_%d", transactions[i].ID, c.scrapes, i)
+	}
+	return transactions, nil
+}
+
+func TestTransactionsAreDeduplicated(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewAccountService(&renumberingClient{}, dataStore, nil, AlertThresholds{}, RetryPolicy{}, BreakerPolicy{}, CachePolicy{}, nil, nil)
+
+	first, err := svc.GetAccountDetails(context.Background(), "12345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := svc.GetAccountDetails(context.Background(), "12345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(second.Transactions) != 3 || len(dataStore.Transactions("12345678")) != 3 {
+		t.Fatalf("expected the rescraped transactions to replace the stored ones, got %d scraped and %d stored", len(second.Transactions), len(dataStore.Transactions("12345678")))
+	}
+	for i := range first.Transactions {
+		if second.Transactions[i].ID != first.Transactions[i].ID {
+			t.Errorf("expected transaction %d to keep its stored ID %s, got %s", i, first.Transactions[i].ID, second.Transactions[i].ID)
+		}
+		if first.Transactions[i].Fingerprint == "" {
+			t.Errorf("expected transaction %d to be fingerprinted", i)
+		}
+	}
+}
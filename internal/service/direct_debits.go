@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/notify"
+)
+
+// ErrDirectDebitsUnsupported is returned when the NAB client cannot scrape
+// direct debits
+var ErrDirectDebitsUnsupported = errors.New("direct debits not supported")
+
+// DirectDebitClient is implemented by NAB clients that can scrape the
+// direct debit authorities on an account
+type DirectDebitClient interface {
+	GetDirectDebits(ctx context.Context, accountID string) ([]model.DirectDebit, error)
+}
+
+// DirectDebitService defines the interface for direct debit operations
+type DirectDebitService interface {
+	GetDirectDebits(ctx context.Context, accountID string) ([]model.DirectDebit, error)
+}
+
+// directDebitService implements DirectDebitService
+type directDebitService struct {
+	nabClient BankProvider
+	alerts    *alerter
+}
+
+// NewDirectDebitService creates a new direct debit service. Scrape failures
+// are pushed to the notifier; a nil notifier disables them.
+func NewDirectDebitService(nabClient BankProvider, notifier notify.Notifier) DirectDebitService {
+	return &directDebitService{
+		nabClient: nabClient,
+		alerts:    newAlerter(notifier, AlertThresholds{}),
+	}
+}
+
+// GetDirectDebits retrieves the direct debit authorities for an account
+func (s *directDebitService) GetDirectDebits(ctx context.Context, accountID string) ([]model.DirectDebit, error) {
+	client, ok := s.nabClient.(DirectDebitClient)
+	if !ok {
+		return nil, ErrDirectDebitsUnsupported
+	}
+
+	accounts, err := s.nabClient.GetAccounts(ctx)
+	if err != nil {
+		s.alerts.scrapeFailed(err)
+		return nil, err
+	}
+
+	found := false
+	for _, account := range accounts {
+		if account.ID == accountID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, ErrAccountNotFound
+	}
+
+	debits, err := client.GetDirectDebits(ctx, accountID)
+	if err != nil {
+		s.alerts.scrapeFailed(err)
+		return nil, err
+	}
+
+	return debits, nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// ErrTransactionNotFound is returned when a transaction does not exist on
+// the account
+var ErrTransactionNotFound = errors.New("transaction not found")
+
+// ErrDisputeFormUnsupported is returned when the NAB client cannot drive
+// the dispute form
+var ErrDisputeFormUnsupported = errors.New("dispute form navigation not supported")
+
+// DisputeFormFiller is implemented by NAB clients that can open the dispute
+// form and fill it in without submitting it
+type DisputeFormFiller interface {
+	FillDisputeForm(ctx context.Context, dispute model.DisputeSummary) (*model.DisputeFormResult, error)
+}
+
+// DisputeService defines the interface for preparing transaction disputes
+type DisputeService interface {
+	PrepareDispute(ctx context.Context, accountID, transactionID string, req model.DisputeRequest) (*model.DisputeSummary, error)
+}
+
+// disputeService implements DisputeService
+type disputeService struct {
+	accountService AccountService
+	nabClient      BankProvider
+	store          *store.Store
+}
+
+// NewDisputeService creates a new dispute service. Transactions are looked
+// up in the store first and fetched from NAB when missing.
+func NewDisputeService(accountService AccountService, nabClient BankProvider, store *store.Store) DisputeService {
+	return &disputeService{
+		accountService: accountService,
+		nabClient:      nabClient,
+		store:          store,
+	}
+}
+
+// PrepareDispute builds a dispute summary for a transaction, optionally
+// filling in NAB's dispute form in dry-run mode
+func (s *disputeService) PrepareDispute(ctx context.Context, accountID, transactionID string, req model.DisputeRequest) (*model.DisputeSummary, error) {
+	account, transaction, err := s.find(ctx, accountID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	merchant := transaction.Description
+	if transaction.Merchant != nil && *transaction.Merchant != "" {
+		merchant = *transaction.Merchant
+	}
+
+	summary := &model.DisputeSummary{
+		AccountID:     account.ID,
+		AccountName:   account.Name,
+		CardNumber:    account.AccountNumber,
+		TransactionID: transaction.ID,
+		Date:          transaction.Date,
+		Merchant:      merchant,
+		Description:   transaction.Description,
+		Amount:        transaction.Amount,
+		Reason:        strings.TrimSpace(req.Reason),
+		GeneratedAt:   time.Now(),
+	}
+	summary.Document = disputeDocument(summary)
+
+	if req.Navigate {
+		filler, ok := s.nabClient.(DisputeFormFiller)
+		if !ok {
+			return nil, ErrDisputeFormUnsupported
+		}
+
+		form, err := filler.FillDisputeForm(ctx, *summary)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fill dispute form: %w", err)
+		}
+		summary.Form = form
+	}
+
+	return summary, nil
+}
+
+// find locates the account and transaction, preferring stored data
+func (s *disputeService) find(ctx context.Context, accountID, transactionID string) (model.Account, model.Transaction, error) {
+	for _, account := range s.store.Accounts() {
+		if account.ID != accountID {
+			continue
+		}
+		for _, transaction := range s.store.Transactions(accountID) {
+			if transaction.ID == transactionID {
+				return account, transaction, nil
+			}
+		}
+	}
+
+	details, err := s.accountService.GetAccountDetails(ctx, accountID)
+	if err != nil {
+		return model.Account{}, model.Transaction{}, err
+	}
+	for _, transaction := range details.Transactions {
+		if transaction.ID == transactionID {
+			return details.Account, transaction, nil
+		}
+	}
+
+	return model.Account{}, model.Transaction{}, ErrTransactionNotFound
+}
+
+// disputeDocument renders the summary as a plain text document that can be
+// copied into NAB's dispute form or attached to a dispute email
+func disputeDocument(summary *model.DisputeSummary) string {
+	var b strings.Builder
+
+	b.WriteString("NAB Transaction Dispute\n")
+	b.WriteString("=======================\n\n")
+	fmt.Fprintf(&b, "Account:         %s (%s)\n", summary.AccountName, summary.AccountID)
+	if summary.CardNumber != nil {
+		fmt.Fprintf(&b, "Card/account no: %s\n", *summary.CardNumber)
+	}
+	fmt.Fprintf(&b, "Transaction ID:  %s\n", summary.TransactionID)
+	fmt.Fprintf(&b, "Date:            %s\n", summary.Date)
+	fmt.Fprintf(&b, "Merchant:        %s\n", summary.Merchant)
+	fmt.Fprintf(&b, "Description:     %s\n", summary.Description)
+	fmt.Fprintf(&b, "Amount:          $%s\n", strings.TrimPrefix(summary.Amount.Amount, "-"))
+
+	reason := summary.Reason
+	if reason == "" {
+		reason = "(describe why you are disputing this transaction)"
+	}
+	fmt.Fprintf(&b, "\nReason for dispute:\n%s\n", reason)
+	fmt.Fprintf(&b, "\nPrepared %s\n", summary.GeneratedAt.Format("2 January 2006"))
+
+	return b.String()
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestPrepareDispute(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewMockNABClient()
+	svc := NewDisputeService(NewAccountService(client, dataStore, nil, AlertThresholds{}, RetryPolicy{}, BreakerPolicy{}, CachePolicy{}, nil, nil), client, dataStore)
+
+	summary, err := svc.PrepareDispute(context.Background(), "12345678", "txn_001_12345678", model.DisputeRequest{
+		Reason:   "Charged twice",
+		Navigate: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.Merchant != "COLES SUPERMARKET" || summary.Amount.Amount != "-85.67" {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+	if summary.CardNumber == nil || *summary.CardNumber != "****5678" {
+		t.Errorf("unexpected card number %v", summary.CardNumber)
+	}
+	for _, want := range []string{"COLES SUPERMARKET", "$85.67", "Charged twice"} {
+		if !strings.Contains(summary.Document, want) {
+			t.Errorf("document missing %q:\n%s", want, summary.Document)
+		}
+	}
+	if summary.Form == nil || !summary.Form.Reached || summary.Form.Submitted {
+		t.Errorf("unexpected form result %+v", summary.Form)
+	}
+
+	_, err = svc.PrepareDispute(context.Background(), "12345678", "missing", model.DisputeRequest{})
+	if !errors.Is(err, ErrTransactionNotFound) {
+		t.Errorf("expected ErrTransactionNotFound, got %v", err)
+	}
+}
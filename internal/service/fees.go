@@ -0,0 +1,149 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// Fee report length in months, by default and at most
+const (
+	defaultFeeMonths = 12
+	maxFeeMonths     = 36
+)
+
+// feeTotals accumulates fees in cents, and how many there were, by kind
+// of fee. Fees are counted positive, so a refund takes one off.
+type feeTotals struct {
+	cents map[string]int64
+	count map[string]int
+}
+
+// newFeeTotals creates empty fee totals
+func newFeeTotals() feeTotals {
+	return feeTotals{cents: make(map[string]int64), count: make(map[string]int)}
+}
+
+// add counts a fee transaction
+func (t feeTotals) add(transaction model.Transaction, amount int64) {
+	feeType := transaction.FeeType
+	if feeType == "" {
+		feeType = model.FeeTypeOther
+	}
+	t.cents[feeType] -= amount
+	t.count[feeType]++
+}
+
+// total returns the cost and number of every fee
+func (t feeTotals) total() (int64, int) {
+	var cents int64
+	var count int
+	for feeType, amount := range t.cents {
+		cents += amount
+		count += t.count[feeType]
+	}
+	return cents, count
+}
+
+// list returns the totals by kind of fee, dearest first
+func (t feeTotals) list() []model.FeeTotal {
+	feeTypes := make([]string, 0, len(t.cents))
+	for feeType := range t.cents {
+		feeTypes = append(feeTypes, feeType)
+	}
+	sort.Slice(feeTypes, func(i, j int) bool {
+		if a, b := t.cents[feeTypes[i]], t.cents[feeTypes[j]]; a != b {
+			return a > b
+		}
+		return feeTypes[i] < feeTypes[j]
+	})
+
+	totals := make([]model.FeeTotal, 0, len(feeTypes))
+	for _, feeType := range feeTypes {
+		totals = append(totals, model.FeeTotal{
+			FeeType: feeType,
+			Total:   model.Money{Amount: formatCents(t.cents[feeType])},
+			Count:   t.count[feeType],
+		})
+	}
+	return totals
+}
+
+// Fees reports the fees on stored transactions in the last months
+// (default 12) including this one, per account and month and by kind
+func (s *reportService) Fees(months int) (*model.FeeReport, error) {
+	if months == 0 {
+		months = defaultFeeMonths
+	}
+	if months < 1 || months > maxFeeMonths {
+		return nil, fmt.Errorf("%w: months must be between 1 and %d", ErrInvalidReport, maxFeeMonths)
+	}
+	now := s.now()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	from, to := thisMonth.AddDate(0, -(months-1), 0).Format("2006-01"), thisMonth.Format("2006-01")
+
+	overall := newFeeTotals()
+	byAccount := make(map[string]map[string]feeTotals)
+	for accountID, transactions := range s.store.AllTransactions() {
+		for _, transaction := range transactions {
+			if transaction.Type != model.TransactionTypeFee || len(transaction.Date) < 7 {
+				continue
+			}
+			month := transaction.Date[:7]
+			if month < from || month > to {
+				continue
+			}
+			amount, err := parseBalanceCents(transaction.Amount.Amount)
+			if err != nil {
+				continue
+			}
+			if byAccount[accountID] == nil {
+				byAccount[accountID] = make(map[string]feeTotals)
+			}
+			if _, ok := byAccount[accountID][month]; !ok {
+				byAccount[accountID][month] = newFeeTotals()
+			}
+			byAccount[accountID][month].add(transaction, amount)
+			overall.add(transaction, amount)
+		}
+	}
+
+	names := make(map[string]string)
+	for _, account := range s.store.Accounts() {
+		names[account.ID] = account.Name
+	}
+	total, _ := overall.total()
+	report := &model.FeeReport{
+		From:        from,
+		To:          to,
+		Total:       model.Money{Amount: formatCents(total)},
+		Fees:        overall.list(),
+		Accounts:    []model.FeeAccount{},
+		GeneratedAt: now,
+	}
+	for accountID, monthly := range byAccount {
+		account := model.FeeAccount{AccountID: accountID, Name: names[accountID], Months: []model.FeeMonth{}}
+		var accountTotal int64
+		for month, totals := range monthly {
+			cents, count := totals.total()
+			accountTotal += cents
+			account.Months = append(account.Months, model.FeeMonth{
+				Month: month,
+				Total: model.Money{Amount: formatCents(cents)},
+				Count: count,
+				Fees:  totals.list(),
+			})
+		}
+		sort.Slice(account.Months, func(i, j int) bool {
+			return account.Months[i].Month < account.Months[j].Month
+		})
+		account.Total = model.Money{Amount: formatCents(accountTotal)}
+		report.Accounts = append(report.Accounts, account)
+	}
+	sort.Slice(report.Accounts, func(i, j int) bool {
+		return report.Accounts[i].AccountID < report.Accounts[j].AccountID
+	})
+	return report, nil
+}
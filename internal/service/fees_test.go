@@ -0,0 +1,72 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestFees(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dataStore.RecordAccounts([]model.Account{{ID: "12345678", Name: "Everyday"}}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	fee := func(id, date, description, amount string) model.Transaction {
+		return model.Transaction{ID: id, Date: date, Description: description, Amount: model.Money{Amount: amount}}
+	}
+	if err := dataStore.SaveTransactions("12345678", []model.Transaction{
+		fee("txn_1", "2024-05-01", "MONTHLY ACCOUNT FEE", "-5.00"),
+		fee("txn_2", "2024-05-12", "INTL TRANSACTION FEE", "-2.10"),
+		fee("txn_3", "2024-05-13", "EFTPOS Purchase - COLES", "-80.00"),
+		fee("txn_4", "2024-04-01", "MONTHLY ACCOUNT FEE", "-5.00"),
+		fee("txn_5", "2024-04-03", "ATM OPERATOR FEE", "-3.00"),
+		fee("txn_6", "2024-04-04", "ATM OPERATOR FEE REFUND", "3.00"),
+		fee("txn_7", "2023-01-01", "MONTHLY ACCOUNT FEE", "-5.00"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dataStore.SaveTransactions("55667788", []model.Transaction{
+		fee("txn_8", "2024-05-20", "ANNUAL FEE", "-90.00"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewReportService(dataStore)
+	svc.(*reportService).now = func() time.Time { return time.Date(2024, 5, 20, 12, 0, 0, 0, time.Local) }
+
+	report, err := svc.Fees(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.From != "2023-06" || report.To != "2024-05" || report.Total.Amount != "102.10" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if len(report.Fees) != 4 || report.Fees[0].FeeType != model.FeeTypeOther || report.Fees[0].Total.Amount != "90.00" {
+		t.Errorf("unexpected fee totals %+v", report.Fees)
+	}
+
+	if len(report.Accounts) != 2 {
+		t.Fatalf("expected two accounts, got %+v", report.Accounts)
+	}
+	everyday := report.Accounts[0]
+	if everyday.Name != "Everyday" || everyday.Total.Amount != "12.10" || len(everyday.Months) != 2 {
+		t.Fatalf("unexpected account %+v", everyday)
+	}
+	april, may := everyday.Months[0], everyday.Months[1]
+	if april.Month != "2024-04" || april.Total.Amount != "5.00" || april.Count != 3 {
+		t.Errorf("expected the refunded ATM fee taken off April, got %+v", april)
+	}
+	if may.Month != "2024-05" || may.Total.Amount != "7.10" || len(may.Fees) != 2 || may.Fees[0].FeeType != model.FeeTypeAccountKeeping {
+		t.Errorf("unexpected May %+v", may)
+	}
+
+	if _, err := svc.Fees(maxFeeMonths + 1); !errors.Is(err, ErrInvalidReport) {
+		t.Errorf("expected an invalid report error, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/notify"
+)
+
+// ErrSavingsGoalsUnsupported is returned when the NAB client cannot scrape
+// savings goals
+var ErrSavingsGoalsUnsupported = errors.New("savings goals not supported")
+
+// SavingsGoalClient is implemented by NAB clients that can scrape the
+// savings goals set on an account
+type SavingsGoalClient interface {
+	GetSavingsGoals(ctx context.Context, accountID string) ([]model.SavingsGoal, error)
+}
+
+// SavingsGoalService defines the interface for savings goal operations
+type SavingsGoalService interface {
+	GetSavingsGoals(ctx context.Context, accountID string) ([]model.SavingsGoal, error)
+}
+
+// savingsGoalService implements SavingsGoalService
+type savingsGoalService struct {
+	nabClient BankProvider
+	alerts    *alerter
+}
+
+// NewSavingsGoalService creates a new savings goal service. Scrape
+// failures are pushed to the notifier; a nil notifier disables them.
+func NewSavingsGoalService(nabClient BankProvider, notifier notify.Notifier) SavingsGoalService {
+	return &savingsGoalService{
+		nabClient: nabClient,
+		alerts:    newAlerter(notifier, AlertThresholds{}),
+	}
+}
+
+// GetSavingsGoals retrieves the savings goals set on an account
+func (s *savingsGoalService) GetSavingsGoals(ctx context.Context, accountID string) ([]model.SavingsGoal, error) {
+	client, ok := s.nabClient.(SavingsGoalClient)
+	if !ok {
+		return nil, ErrSavingsGoalsUnsupported
+	}
+
+	accounts, err := s.nabClient.GetAccounts(ctx)
+	if err != nil {
+		s.alerts.scrapeFailed(err)
+		return nil, err
+	}
+
+	found := false
+	for _, account := range accounts {
+		if account.ID == accountID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, ErrAccountNotFound
+	}
+
+	goals, err := client.GetSavingsGoals(ctx, accountID)
+	if err != nil {
+		s.alerts.scrapeFailed(err)
+		return nil, err
+	}
+
+	return goals, nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// ScrapeHealthReporter is implemented by services that track how scraping
+// NAB has been going
+type ScrapeHealthReporter interface {
+	ScrapeHealth() model.ScrapeHealth
+}
+
+// BrowserHealthReporter is implemented by NAB clients that can report
+// their login session and running browsers
+type BrowserHealthReporter interface {
+	BrowserHealth() model.BrowserHealth
+}
+
+// StepMetricsReporter is implemented by NAB clients that time each step of
+// the login and scrape flow
+type StepMetricsReporter interface {
+	StepMetrics() []model.ScrapeStepMetrics
+}
+
+// scrapeTracker remembers the outcome of recent scrapes
+type scrapeTracker struct {
+	mu          sync.Mutex
+	lastSuccess time.Time
+	lastFailure time.Time
+	lastErr     error
+	failures    int
+	balanceGaps map[string]int
+	lastGap     time.Time
+}
+
+// record notes the outcome of a scrape, ignoring errors that say nothing
+// about NAB's health
+func (t *scrapeTracker) record(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch {
+	case err == nil:
+		t.lastSuccess = time.Now()
+		t.failures = 0
+	case reflectsNABHealth(err):
+		t.lastFailure = time.Now()
+		t.lastErr = err
+		t.failures++
+	}
+}
+
+// recordBalanceGaps notes how many running balance gaps an account's
+// latest sync found
+func (t *scrapeTracker) recordBalanceGaps(accountID string, gaps int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.balanceGaps == nil {
+		t.balanceGaps = make(map[string]int)
+	}
+	t.balanceGaps[accountID] = gaps
+	if gaps > 0 {
+		t.lastGap = time.Now()
+	}
+}
+
+// health reports the tracked outcomes
+func (t *scrapeTracker) health() model.ScrapeHealth {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	health := model.ScrapeHealth{ConsecutiveFailures: t.failures}
+	for _, gaps := range t.balanceGaps {
+		health.BalanceGaps += gaps
+	}
+	if !t.lastGap.IsZero() {
+		lastGap := t.lastGap
+		health.LastBalanceGapAt = &lastGap
+	}
+	if !t.lastSuccess.IsZero() {
+		lastSuccess := t.lastSuccess
+		health.LastSuccessAt = &lastSuccess
+	}
+	if !t.lastFailure.IsZero() {
+		lastFailure := t.lastFailure
+		health.LastFailureAt = &lastFailure
+		health.LastError = t.lastErr.Error()
+		health.AuthenticationFailed = t.failures > 0 && errors.Is(t.lastErr, ErrAuthenticationFailed)
+	}
+	return health
+}
+
+// ScrapeHealth reports recent account scrapes and the circuit breaker
+func (s *accountService) ScrapeHealth() model.ScrapeHealth {
+	health := s.scrapes.health()
+	if open, until := s.breaker.open(); open {
+		health.CircuitOpen = true
+		health.CircuitOpenUntil = &until
+	}
+	return health
+}
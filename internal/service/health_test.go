@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestScrapeHealth(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &flakyClient{failures: 1, err: fmt.Errorf("%w: NAB rejected the login", ErrAuthenticationFailed)}
+	svc := NewAccountService(client, dataStore, nil, AlertThresholds{}, RetryPolicy{}, BreakerPolicy{Threshold: 1, Cooldown: time.Hour}, CachePolicy{}, nil, nil)
+	reporter := svc.(ScrapeHealthReporter)
+
+	if health := reporter.ScrapeHealth(); health.LastSuccessAt != nil || health.LastFailureAt != nil {
+		t.Errorf("expected no scrapes yet, got %+v", health)
+	}
+
+	svc.GetAllAccounts(context.Background())
+	health := reporter.ScrapeHealth()
+	if !health.AuthenticationFailed || !health.CircuitOpen || health.ConsecutiveFailures != 1 || health.LastError == "" {
+		t.Errorf("expected a rejected login to open the circuit, got %+v", health)
+	}
+
+	breaker := svc.(*accountService).breaker
+	breaker.now = func() time.Time { return time.Now().Add(time.Hour) }
+	if _, err := svc.GetAllAccounts(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	health = reporter.ScrapeHealth()
+	if health.AuthenticationFailed || health.CircuitOpen || health.ConsecutiveFailures != 0 || health.LastSuccessAt == nil {
+		t.Errorf("expected a successful scrape to clear the failure, got %+v", health)
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// HistoryService answers what accounts looked like at a past time, from
+// the balance snapshots and transactions recorded on each refresh
+type HistoryService interface {
+	AccountsAsOf(at time.Time) []model.Account
+	AccountDetailsAsOf(accountID string, at time.Time) (*model.AccountDetails, error)
+}
+
+// historyService implements HistoryService
+type historyService struct {
+	store *store.Store
+}
+
+// NewHistoryService creates a history service. Nothing is scraped: only
+// what the store recorded at or before the requested time is used.
+func NewHistoryService(store *store.Store) HistoryService {
+	return &historyService{store: store}
+}
+
+// AccountsAsOf returns each account with the last balance recorded at or
+// before at, and when it was recorded as its last updated time. Accounts
+// first seen after at are left out.
+func (s *historyService) AccountsAsOf(at time.Time) []model.Account {
+	latest := make(map[string]model.BalanceSnapshot)
+	for _, snapshot := range s.store.BalanceHistory("") {
+		if !snapshot.RecordedAt.After(at) {
+			latest[snapshot.AccountID] = snapshot
+		}
+	}
+
+	accounts := []model.Account{}
+	for _, account := range s.store.Accounts() {
+		snapshot, ok := latest[account.ID]
+		if !ok {
+			continue
+		}
+		recordedAt := snapshot.RecordedAt
+		account.Balance = snapshot.Balance
+		account.AvailableBalance = snapshot.AvailableBalance
+		account.LastUpdated = &recordedAt
+		account.Stale = false
+		account.Cached = false
+		applyLocalState(s.store, &account)
+		accounts = append(accounts, account)
+	}
+	return accounts
+}
+
+// AccountDetailsAsOf returns an account as it was at at, with the
+// transactions dated on or before that day and its balance trend up to it
+func (s *historyService) AccountDetailsAsOf(accountID string, at time.Time) (*model.AccountDetails, error) {
+	for _, account := range s.AccountsAsOf(at) {
+		if account.ID != accountID {
+			continue
+		}
+
+		day := at.Format("2006-01-02")
+		transactions := []model.Transaction{}
+		for _, transaction := range s.store.Transactions(accountID) {
+			if transaction.Date <= day {
+				transactions = append(transactions, transaction)
+			}
+		}
+		return &model.AccountDetails{
+			Account:                account,
+			Transactions:           transactions,
+			RecentTransactionCount: len(transactions),
+			Trend:                  balanceTrend(s.store.BalanceHistory(accountID), account.Balance, at),
+		}, nil
+	}
+	return nil, ErrAccountNotFound
+}
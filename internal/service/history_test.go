@@ -0,0 +1,52 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestHistoryAsOf(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	june := time.Date(2024, 6, 30, 9, 0, 0, 0, time.UTC)
+	july := time.Date(2024, 7, 2, 9, 0, 0, 0, time.UTC)
+	record := func(id, amount string, at time.Time) {
+		if err := dataStore.RecordAccounts([]model.Account{{ID: id, Balance: model.Money{Amount: amount}}}, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	record("12345678", "1000.00", june)
+	record("12345678", "1500.00", july)
+	record("87654321", "50.00", july)
+	if err := dataStore.SaveTransactions("12345678", []model.Transaction{
+		{ID: "txn_june", Date: "2024-06-28"},
+		{ID: "txn_july", Date: "2024-07-01"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewHistoryService(dataStore)
+
+	endOfJune := time.Date(2024, 6, 30, 23, 59, 59, 0, time.UTC)
+	accounts := svc.AccountsAsOf(endOfJune)
+	if len(accounts) != 1 || accounts[0].Balance.Amount != "1000.00" || !accounts[0].LastUpdated.Equal(june) {
+		t.Fatalf("expected only the June balance, got %+v", accounts)
+	}
+
+	details, err := svc.AccountDetailsAsOf("12345678", endOfJune)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(details.Transactions) != 1 || details.Transactions[0].ID != "txn_june" {
+		t.Errorf("expected transactions up to June 30, got %+v", details.Transactions)
+	}
+
+	if _, err := svc.AccountDetailsAsOf("87654321", endOfJune); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("expected an account first seen in July to be missing in June, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"time"
+)
+
+// ErrSessionExpired is returned by a keep-alive when the persisted NAB
+// session has already expired
+var ErrSessionExpired = errors.New("NAB session expired")
+
+// KeepAliveClient is implemented by NAB clients whose persisted sessions
+// can be kept from expiring between scrapes
+type KeepAliveClient interface {
+	KeepAlive(ctx context.Context) error
+}
+
+// KeepAlive touches the persisted NAB session on an interval so it doesn't
+// expire between syncs and need a fresh login
+type KeepAlive struct {
+	client   KeepAliveClient
+	interval time.Duration
+	jitter   float64
+	logger   *log.Logger
+}
+
+// NewKeepAlive creates a session keep-alive, or returns nil if the interval
+// is zero or the NAB client has no session to keep alive. Each wait is
+// randomised by the jitter fraction so the touches don't look scheduled.
+func NewKeepAlive(nabClient BankProvider, interval time.Duration, jitter float64, logger *log.Logger) *KeepAlive {
+	client, ok := nabClient.(KeepAliveClient)
+	if !ok || interval <= 0 {
+		return nil
+	}
+	return &KeepAlive{client: client, interval: interval, jitter: jitter, logger: logger}
+}
+
+// Run touches the session after every wait until ctx is done. An expired
+// session is left for the next scrape to log in again.
+func (k *KeepAlive) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(k.wait()):
+		}
+
+		switch err := k.client.KeepAlive(ctx); {
+		case errors.Is(err, ErrSessionExpired):
+			k.logger.Println("NAB session has expired; the next scrape will log in again")
+		case err != nil:
+			k.logger.Printf("NAB session keep-alive failed: %v", err)
+		}
+	}
+}
+
+// wait returns the interval randomised by up to the jitter fraction either
+// way
+func (k *KeepAlive) wait() time.Duration {
+	spread := float64(k.interval) * k.jitter
+	return k.interval + time.Duration(spread*(2*rand.Float64()-1))
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+)
+
+// keepAliveClient counts keep-alives, cancelling the loop after the third
+type keepAliveClient struct {
+	*MockNABClient
+	calls  int
+	cancel context.CancelFunc
+}
+
+func (k *keepAliveClient) KeepAlive(ctx context.Context) error {
+	k.calls++
+	if k.calls == 3 {
+		k.cancel()
+	}
+	return ErrSessionExpired
+}
+
+func TestKeepAlive(t *testing.T) {
+	if NewKeepAlive(NewMockNABClient(), time.Minute, 0.2, nil) != nil {
+		t.Error("expected no keep-alive for a client without sessions")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := &keepAliveClient{MockNABClient: &MockNABClient{}, cancel: cancel}
+	if NewKeepAlive(client, 0, 0.2, nil) != nil {
+		t.Error("expected no keep-alive without an interval")
+	}
+
+	keepAlive := NewKeepAlive(client, time.Millisecond, 0.5, log.New(io.Discard, "", 0))
+	done := make(chan struct{})
+	go func() {
+		keepAlive.Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("keep-alive didn't stop when cancelled")
+	}
+	if client.calls != 3 {
+		t.Errorf("expected 3 keep-alives, got %d", client.calls)
+	}
+
+	keepAlive = NewKeepAlive(client, time.Minute, 0.2, nil)
+	for i := 0; i < 100; i++ {
+		if wait := keepAlive.wait(); wait < 48*time.Second || wait > 72*time.Second {
+			t.Fatalf("wait %s outside the jitter", wait)
+		}
+	}
+}
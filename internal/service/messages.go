@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/notify"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// MessageService defines the interface for secure message operations
+type MessageService interface {
+	GetMessages(ctx context.Context) ([]model.Message, error)
+}
+
+// messageService implements MessageService
+type messageService struct {
+	nabClient BankProvider
+	store     *store.Store
+	alerts    *alerter
+	keywords  []string
+}
+
+// NewMessageService creates a new message service. New unread messages are
+// pushed to the notifier, at high priority when their subject contains one
+// of the keywords; a nil notifier disables them.
+func NewMessageService(nabClient BankProvider, store *store.Store, notifier notify.Notifier, keywords []string) MessageService {
+	return &messageService{
+		nabClient: nabClient,
+		store:     store,
+		alerts:    newAlerter(notifier, AlertThresholds{}),
+		keywords:  keywords,
+	}
+}
+
+// GetMessages retrieves the inbox from NAB and alerts on new messages
+func (s *messageService) GetMessages(ctx context.Context) ([]model.Message, error) {
+	messages, err := s.nabClient.GetMessages(ctx)
+	if err != nil {
+		s.alerts.scrapeFailed(err)
+		return nil, err
+	}
+
+	// The first fetch only seeds the store so the existing inbox isn't
+	// reported as new
+	seeded := len(s.store.Messages()) > 0
+
+	added, err := s.store.RecordMessages(messages)
+	if err != nil {
+		return nil, err
+	}
+
+	if seeded {
+		s.alerts.newMessages(added, s.keywords)
+	}
+
+	return messages, nil
+}
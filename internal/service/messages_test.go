@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/notify"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// inboxClient serves a configurable inbox
+type inboxClient struct {
+	MockNABClient
+	messages []model.Message
+}
+
+func (c *inboxClient) GetMessages(ctx context.Context) ([]model.Message, error) {
+	return c.messages, nil
+}
+
+// channelNotifier records notifications on a channel
+type channelNotifier chan notify.Notification
+
+func (c channelNotifier) Name() string { return "channel" }
+
+func (c channelNotifier) Send(ctx context.Context, n notify.Notification) error {
+	c <- n
+	return nil
+}
+
+func TestGetMessagesAlertsOnNewUnread(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &inboxClient{messages: []model.Message{{ID: "1", Subject: "Welcome", Read: true}}}
+	notifier := make(channelNotifier, 4)
+	svc := NewMessageService(client, dataStore, notifier, []string{"rate"})
+
+	// The first fetch seeds the store without alerting
+	client.messages = append(client.messages, model.Message{ID: "2", Subject: "Old unread"})
+	if _, err := svc.GetMessages(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	client.messages = append(client.messages,
+		model.Message{ID: "3", Subject: "Interest RATE change"},
+		model.Message{ID: "4", Subject: "Statement ready", Read: true},
+	)
+	if _, err := svc.GetMessages(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case n := <-notifier:
+		if n.Event != notify.EventNewMessage || n.Message != "Interest RATE change" || n.Priority != notify.PriorityHigh {
+			t.Errorf("unexpected notification %+v", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a new message notification")
+	}
+
+	select {
+	case n := <-notifier:
+		t.Errorf("unexpected extra notification %+v", n)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if got := len(dataStore.Messages()); got != 4 {
+		t.Errorf("stored %d messages, want 4", got)
+	}
+}
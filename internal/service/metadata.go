@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// ErrInvalidAccountMetadata is returned when a nickname, emoji, colour or
+// note is unusable
+var ErrInvalidAccountMetadata = errors.New("invalid account metadata")
+
+// Account metadata limits, in characters
+const (
+	maxNicknameLength = 64
+	maxEmojiLength    = 8
+	maxNotesLength    = 2000
+)
+
+// colourPattern matches a hex colour like #2a9d8f or #fc0
+var colourPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// AccountMetadataService manages the nicknames, emoji, colours and notes
+// users attach to accounts
+type AccountMetadataService interface {
+	Update(accountID string, req model.AccountMetadataRequest, version int) (*model.AccountMetadata, error)
+}
+
+// accountMetadataService implements AccountMetadataService
+type accountMetadataService struct {
+	store *store.Store
+	now   func() time.Time
+}
+
+// NewAccountMetadataService creates an account metadata service. Metadata
+// is kept in the store and merged into scraped accounts.
+func NewAccountMetadataService(store *store.Store) AccountMetadataService {
+	return &accountMetadataService{store: store, now: time.Now}
+}
+
+// Update merges the request into an account's metadata. The account must
+// have been scraped, and a non-zero version must match the metadata's
+// current version.
+func (s *accountMetadataService) Update(accountID string, req model.AccountMetadataRequest, version int) (*model.AccountMetadata, error) {
+	if err := validateAccountMetadata(req); err != nil {
+		return nil, err
+	}
+	if !s.storedAccount(accountID) {
+		return nil, ErrAccountNotFound
+	}
+
+	metadata, err := s.store.UpdateAccountMetadata(accountID, func(metadata *model.AccountMetadata) error {
+		if err := CheckVersion(metadata.Version, version); err != nil {
+			return err
+		}
+		metadata.Nickname = mergeMetadataField(metadata.Nickname, req.Nickname)
+		metadata.Emoji = mergeMetadataField(metadata.Emoji, req.Emoji)
+		metadata.Colour = mergeMetadataField(metadata.Colour, req.Colour)
+		metadata.Notes = mergeMetadataField(metadata.Notes, req.Notes)
+		if metadata.Colour != nil {
+			lower := strings.ToLower(*metadata.Colour)
+			metadata.Colour = &lower
+		}
+		metadata.Version++
+		metadata.UpdatedAt = s.now()
+		return nil
+	})
+	if errors.Is(err, ErrVersionMismatch) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update account metadata: %w", err)
+	}
+
+	return &metadata, nil
+}
+
+// storedAccount reports whether the account has been scraped
+func (s *accountMetadataService) storedAccount(accountID string) bool {
+	for _, account := range s.store.Accounts() {
+		if account.ID == accountID {
+			return true
+		}
+	}
+	return false
+}
+
+// validateAccountMetadata checks the fields being set
+func validateAccountMetadata(req model.AccountMetadataRequest) error {
+	limits := []struct {
+		name  string
+		value *string
+		max   int
+	}{
+		{"nickname", req.Nickname, maxNicknameLength},
+		{"emoji", req.Emoji, maxEmojiLength},
+		{"notes", req.Notes, maxNotesLength},
+	}
+	for _, limit := range limits {
+		if limit.value != nil && utf8.RuneCountInString(strings.TrimSpace(*limit.value)) > limit.max {
+			return fmt.Errorf("%w: %s can be at most %d characters", ErrInvalidAccountMetadata, limit.name, limit.max)
+		}
+	}
+	if req.Colour != nil {
+		if colour := strings.TrimSpace(*req.Colour); colour != "" && !colourPattern.MatchString(colour) {
+			return fmt.Errorf("%w: colour must be a hex colour like #2a9d8f", ErrInvalidAccountMetadata)
+		}
+	}
+	return nil
+}
+
+// mergeMetadataField returns the new value for a field: the current one
+// if the request leaves it out, nil if it is set to an empty string
+func mergeMetadataField(current, requested *string) *string {
+	if requested == nil {
+		return current
+	}
+	value := strings.TrimSpace(*requested)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
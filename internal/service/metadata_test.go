@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestUpdateAccountMetadata(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dataStore.RecordAccounts([]model.Account{{ID: "12345678", Name: "Complete Access Account"}}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewAccountMetadataService(dataStore)
+	text := func(s string) *string { return &s }
+
+	metadata, err := svc.Update("12345678", model.AccountMetadataRequest{Nickname: text(" Holiday fund "), Colour: text("#2A9D8F")}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *metadata.Nickname != "Holiday fund" || *metadata.Colour != "#2a9d8f" || metadata.Version != 1 {
+		t.Fatalf("unexpected metadata %+v", metadata)
+	}
+
+	// Fields left out are kept and empty ones cleared
+	metadata, err = svc.Update("12345678", model.AccountMetadataRequest{Emoji: text("🏖️"), Colour: text("")}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.Nickname == nil || metadata.Colour != nil || *metadata.Emoji != "🏖️" || metadata.Version != 2 {
+		t.Errorf("unexpected merged metadata %+v", metadata)
+	}
+
+	if _, err := svc.Update("12345678", model.AccountMetadataRequest{Notes: text("stale")}, 1); !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("expected a version mismatch, got %v", err)
+	}
+	if _, err := svc.Update("12345678", model.AccountMetadataRequest{Colour: text("teal")}, 0); !errors.Is(err, ErrInvalidAccountMetadata) {
+		t.Errorf("expected invalid metadata, got %v", err)
+	}
+	if _, err := svc.Update("99999999", model.AccountMetadataRequest{Nickname: text("Ghost")}, 0); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("expected account not found, got %v", err)
+	}
+
+	account := model.Account{ID: "12345678"}
+	applyLocalState(dataStore, &account)
+	if account.Metadata == nil || *account.Metadata.Nickname != "Holiday fund" {
+		t.Errorf("expected metadata merged into the account, got %+v", account.Metadata)
+	}
+}
@@ -2,16 +2,21 @@ package service
 
 import (
 	"context"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/benrowe/nab-bank-api/internal/model"
 )
 
-// MockNABClient is a mock implementation of NABClient for testing
-type MockNABClient struct{}
+// MockNABClient is a mock implementation of BankProvider for testing
+type MockNABClient struct {
+	mu          sync.Mutex
+	lockedCards map[string]bool
+}
 
 // NewMockNABClient creates a new mock NAB client
-func NewMockNABClient() NABClient {
+func NewMockNABClient() BankProvider {
 	return &MockNABClient{}
 }
 
@@ -56,6 +61,62 @@ func (m *MockNABClient) GetAccounts(ctx context.Context) ([]model.Account, error
 			},
 			AccountNumber: stringPtr("****3344"),
 			BSB:          stringPtr("084001"),
+			Interest: &model.InterestDetails{
+				Rate:                    "5.00",
+				BaseRate:                stringPtr("0.10"),
+				BonusRate:               stringPtr("4.90"),
+				EarnedThisFinancialYear: &model.Money{Amount: "312.45"},
+				BonusQualified:          boolPtr(true),
+			},
+		},
+		{
+			ID:   "55667788",
+			Name: "NAB Low Rate Card",
+			Type: model.AccountTypeCredit,
+			Balance: model.Money{
+				Amount: "-1234.56",
+			},
+			AvailableBalance: &model.Money{
+				Amount: "4765.44",
+			},
+			AccountNumber: stringPtr("****7788"),
+			Credit: &model.CreditDetails{
+				CreditLimit:      model.Money{Amount: "6000.00"},
+				AvailableCredit:  model.Money{Amount: "4765.44"},
+				StatementBalance: &model.Money{Amount: "980.10"},
+				MinimumPayment:   &model.Money{Amount: "29.40"},
+				PaymentDueDate:   stringPtr(time.Now().AddDate(0, 0, 12).Format("2006-01-02")),
+				StatementPeriod: &model.StatementPeriod{
+					Start: time.Now().AddDate(0, 0, -18).Format("2006-01-02"),
+					End:   time.Now().AddDate(0, 0, 12).Format("2006-01-02"),
+				},
+			},
+		},
+		{
+			ID:   "99887766",
+			Name: "NAB Base Variable Rate Home Loan",
+			Type: model.AccountTypeLoan,
+			Balance: model.Money{
+				Amount: "-412345.67",
+			},
+			AccountNumber: stringPtr("****7766"),
+			BSB:           stringPtr("084001"),
+		},
+		{
+			ID:   "44332211",
+			Name: "NAB Term Deposit",
+			Type: model.AccountTypeTermDeposit,
+			Balance: model.Money{
+				Amount: "25000.00",
+			},
+			AccountNumber: stringPtr("****2211"),
+			BSB:           stringPtr("084001"),
+			TermDeposit: &model.TermDepositDetails{
+				InterestRate:       "4.50",
+				Term:               "12 months",
+				MaturityDate:       stringPtr(time.Now().AddDate(0, 7, 0).Format("2006-01-02")),
+				InterestAtMaturity: &model.Money{Amount: "1125.00"},
+			},
 		},
 	}
 
@@ -110,7 +171,310 @@ func (m *MockNABClient) GetAccountTransactions(ctx context.Context, accountID st
 	return mockTransactions, nil
 }
 
+// GetMessages returns mock inbox messages
+func (m *MockNABClient) GetMessages(ctx context.Context) ([]model.Message, error) {
+	mockMessages := []model.Message{
+		{
+			ID:      "msg_001",
+			Subject: "Changes to your account interest rate",
+			Date:    time.Now().AddDate(0, 0, -1).Format("2006-01-02"),
+			Read:    false,
+			Body:    "From next month the variable interest rate on your NAB Reward Saver will change.",
+		},
+		{
+			ID:      "msg_002",
+			Subject: "Your replacement card is on its way",
+			Date:    time.Now().AddDate(0, 0, -14).Format("2006-01-02"),
+			Read:    true,
+			Body:    "Your replacement Visa Debit card has been posted to your address on file.",
+		},
+	}
+
+	return mockMessages, nil
+}
+
+// GetPayees returns mock saved payees
+func (m *MockNABClient) GetPayees(ctx context.Context) ([]model.Payee, error) {
+	mockPayees := []model.Payee{
+		{
+			ID:            "payee_001",
+			Name:          "J SMITH",
+			BSB:           "062000",
+			AccountNumber: "12345678",
+			Nickname:      stringPtr("Rent"),
+		},
+		{
+			ID:            "payee_002",
+			Name:          "ACME PLUMBING PTY LTD",
+			BSB:           "033001",
+			AccountNumber: "987654",
+		},
+	}
+
+	return mockPayees, nil
+}
+
+// GetScheduledPayments returns mock upcoming payments
+func (m *MockNABClient) GetScheduledPayments(ctx context.Context) ([]model.ScheduledPayment, error) {
+	mockPayments := []model.ScheduledPayment{
+		{
+			ID:            "sched_001",
+			FromAccountID: stringPtr("87654321"),
+			Payee:         "J SMITH",
+			Amount:        model.Money{Amount: "450.00"},
+			Frequency:     model.PaymentFrequencyFortnightly,
+			NextDate:      time.Now().AddDate(0, 0, 3).Format("2006-01-02"),
+			Description:   stringPtr("Rent"),
+		},
+		{
+			ID:            "sched_002",
+			FromAccountID: stringPtr("12345678"),
+			Payee:         "NAB Reward Saver",
+			Amount:        model.Money{Amount: "200.00"},
+			Frequency:     model.PaymentFrequencyMonthly,
+			NextDate:      time.Now().AddDate(0, 0, 10).Format("2006-01-02"),
+			EndDate:       stringPtr(time.Now().AddDate(1, 0, 0).Format("2006-01-02")),
+		},
+		{
+			ID:            "sched_003",
+			FromAccountID: stringPtr("12345678"),
+			Payee:         "ACME PLUMBING PTY LTD",
+			Amount:        model.Money{Amount: "385.00"},
+			Frequency:     model.PaymentFrequencyOnce,
+			NextDate:      time.Now().AddDate(0, 0, 1).Format("2006-01-02"),
+			Description:   stringPtr("INV 2231"),
+		},
+	}
+
+	return mockPayments, nil
+}
+
+// GetLoanDetails returns mock home loan details
+func (m *MockNABClient) GetLoanDetails(ctx context.Context, accountID string) (*model.LoanDetails, error) {
+	return &model.LoanDetails{
+		InterestRate:       "6.24",
+		RepaymentAmount:    &model.Money{Amount: "2850.00"},
+		RepaymentFrequency: model.RepaymentFrequencyMonthly,
+		NextRepaymentDate:  stringPtr(time.Now().AddDate(0, 0, 9).Format("2006-01-02")),
+		RedrawAvailable:    &model.Money{Amount: "15200.00"},
+		OriginalAmount:     &model.Money{Amount: "500000.00"},
+	}, nil
+}
+
+// GetDirectDebits returns mock direct debit authorities for the everyday
+// account
+func (m *MockNABClient) GetDirectDebits(ctx context.Context, accountID string) ([]model.DirectDebit, error) {
+	if accountID != "87654321" {
+		return []model.DirectDebit{}, nil
+	}
+
+	return []model.DirectDebit{
+		{
+			ID:            "dd_001",
+			Merchant:      "AGL SALES PTY LTD",
+			UserID:        stringPtr("123456"),
+			Reference:     stringPtr("2000123456"),
+			LastAmount:    &model.Money{Amount: "187.40"},
+			LastDebitDate: stringPtr(time.Now().AddDate(0, 0, -12).Format("2006-01-02")),
+			Status:        model.DirectDebitStatusActive,
+		},
+		{
+			ID:            "dd_002",
+			Merchant:      "FITNESS FIRST",
+			UserID:        stringPtr("654321"),
+			LastAmount:    &model.Money{Amount: "32.95"},
+			LastDebitDate: stringPtr(time.Now().AddDate(0, -3, 0).Format("2006-01-02")),
+			Status:        model.DirectDebitStatusCancelled,
+		},
+	}, nil
+}
+
+// GetSavingsGoals returns mock goals on the reward saver
+func (m *MockNABClient) GetSavingsGoals(ctx context.Context, accountID string) ([]model.SavingsGoal, error) {
+	if accountID != "11223344" {
+		return []model.SavingsGoal{}, nil
+	}
+
+	return []model.SavingsGoal{
+		{
+			ID:              "goal_001",
+			Name:            "Japan trip",
+			TargetAmount:    &model.Money{Amount: "8000.00"},
+			SavedAmount:     model.Money{Amount: "3400.00"},
+			TargetDate:      stringPtr(time.Now().AddDate(0, 6, 0).Format("2006-01-02")),
+			PercentComplete: float64Ptr(42.5),
+		},
+		{
+			ID:          "goal_002",
+			Name:        "Rainy day",
+			SavedAmount: model.Money{Amount: "1250.00"},
+		},
+	}, nil
+}
+
+// GetPayIDs returns a mock mobile PayID linked to the everyday account
+func (m *MockNABClient) GetPayIDs(ctx context.Context) ([]model.PayID, error) {
+	return []model.PayID{
+		{
+			ID:          "payid_001",
+			Type:        model.PayIDTypeMobile,
+			Value:       "0412345678",
+			DisplayName: stringPtr("J SMITH"),
+			AccountID:   stringPtr("87654321"),
+			Status:      model.PayIDStatusActive,
+		},
+	}, nil
+}
+
+// GetCards returns mock cards, reflecting any locks applied
+func (m *MockNABClient) GetCards(ctx context.Context) ([]model.Card, error) {
+	cards := []model.Card{
+		{
+			ID:         "card_001",
+			Name:       "NAB Visa Debit",
+			Type:       model.CardTypeDebit,
+			Last4:      "4821",
+			Cardholder: stringPtr("J SMITH"),
+			AccountID:  stringPtr("87654321"),
+			Expiry:     stringPtr("09/27"),
+			Status:     model.CardStatusActive,
+		},
+		{
+			ID:         "card_002",
+			Name:       "NAB Low Rate Card",
+			Type:       model.CardTypeCredit,
+			Last4:      "1234",
+			Cardholder: stringPtr("J SMITH"),
+			Expiry:     stringPtr("03/26"),
+			Status:     model.CardStatusActive,
+		},
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range cards {
+		if m.lockedCards[cards[i].ID] {
+			cards[i].Status = model.CardStatusLocked
+		}
+	}
+
+	return cards, nil
+}
+
+// SetCardLocked pretends to apply or remove the temporary block on a card
+func (m *MockNABClient) SetCardLocked(ctx context.Context, card model.Card, locked bool) (*model.Card, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.lockedCards == nil {
+		m.lockedCards = make(map[string]bool)
+	}
+	m.lockedCards[card.ID] = locked
+
+	card.Status = model.CardStatusActive
+	if locked {
+		card.Status = model.CardStatusLocked
+	}
+	return &card, nil
+}
+
+// FillDisputeForm pretends to fill in the dispute form
+func (m *MockNABClient) FillDisputeForm(ctx context.Context, dispute model.DisputeSummary) (*model.DisputeFormResult, error) {
+	return &model.DisputeFormResult{
+		Reached:      true,
+		FieldsFilled: []string{"date", "merchant", "amount", "reason"},
+	}, nil
+}
+
+// Transfer pretends to transfer between accounts, issuing a receipt unless
+// it is a dry run
+func (m *MockNABClient) Transfer(ctx context.Context, req model.TransferRequest, from, to model.Account) (*model.TransferResult, error) {
+	result := &model.TransferResult{
+		Status:        model.TransferStatusValidated,
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        model.Money{Amount: req.Amount},
+		Description:   req.Description,
+		DryRun:        req.DryRun,
+		Review:        mockReview(req.Amount),
+		ProcessedAt:   time.Now(),
+	}
+	if !req.DryRun {
+		result.Status = model.TransferStatusCompleted
+		result.ReceiptNumber = stringPtr("N" + time.Now().Format("0102150405"))
+	}
+
+	return result, nil
+}
+
+// mockReview is the review screen shown for a mock transfer or payment
+func mockReview(amount string) *model.PaymentReview {
+	today := time.Now().Format("2006-01-02")
+	return &model.PaymentReview{
+		Fee:            &model.Money{Amount: "0.00"},
+		ProcessingDate: &today,
+		Text:           "Amount $" + amount + " Fee $0.00 Payment date Today",
+	}
+}
+
+// PayAnyone pretends to make a Pay Anyone payment. Payments to new payees
+// are held for SMS authorisation as they are by NAB.
+func (m *MockNABClient) PayAnyone(ctx context.Context, req model.PayAnyoneRequest, from model.Account, payee model.Payee) (*model.PaymentResult, error) {
+	result := &model.PaymentResult{
+		ID:            "pay_" + time.Now().Format("0102150405"),
+		Status:        model.PaymentStatusValidated,
+		FromAccountID: from.ID,
+		Payee:         payee,
+		Amount:        model.Money{Amount: req.Amount},
+		Description:   req.Description,
+		Reference:     req.Reference,
+		DryRun:        req.DryRun,
+		Review:        mockReview(req.Amount),
+		ProcessedAt:   time.Now(),
+	}
+	switch {
+	case req.DryRun:
+	case payee.ID == "":
+		expires := time.Now().Add(5 * time.Minute)
+		result.Status = model.PaymentStatusPendingAuth
+		result.AuthExpiresAt = &expires
+	default:
+		result.Status = model.PaymentStatusCompleted
+		result.ReceiptNumber = stringPtr("N" + time.Now().Format("0102150405"))
+	}
+
+	return result, nil
+}
+
+// AuthorizePayment pretends to confirm a pending payment with its SMS code
+func (m *MockNABClient) AuthorizePayment(ctx context.Context, paymentID, code string) (*model.PaymentResult, error) {
+	if !strings.HasPrefix(paymentID, "pay_") {
+		return nil, ErrPaymentNotFound
+	}
+
+	return &model.PaymentResult{
+		ID:            paymentID,
+		Status:        model.PaymentStatusCompleted,
+		ReceiptNumber: stringPtr("N" + time.Now().Format("0102150405")),
+		ProcessedAt:   time.Now(),
+	}, nil
+}
+
+// WarmUp pretends to log in ahead of the first request
+func (m *MockNABClient) WarmUp(ctx context.Context) error {
+	return nil
+}
+
 // stringPtr is a helper function to create string pointers
 func stringPtr(s string) *string {
 	return &s
+}
+
+// boolPtr is a helper function to create bool pointers
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// float64Ptr is a helper function to create float64 pointers
+func float64Ptr(f float64) *float64 {
+	return &f
 }
\ No newline at end of file
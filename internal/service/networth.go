@@ -0,0 +1,127 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// Net worth history length in days, by default and at most
+const (
+	defaultNetWorthDays = 90
+	maxNetWorthDays     = 365
+)
+
+// isLiability reports whether an account's balance is money owed rather
+// than money held
+func isLiability(accountType string) bool {
+	return accountType == model.AccountTypeCredit || accountType == model.AccountTypeLoan
+}
+
+// netWorthTotals accumulates assets and liabilities in cents. Liabilities
+// are kept positive, so a card $500 in debit owes 50000.
+type netWorthTotals struct {
+	assets      int64
+	liabilities int64
+}
+
+// add counts an account's balance towards assets or liabilities
+func (t *netWorthTotals) add(accountType string, balance int64) {
+	if isLiability(accountType) {
+		t.liabilities -= balance
+	} else {
+		t.assets += balance
+	}
+}
+
+// point returns the totals as net worth at the close of date
+func (t netWorthTotals) point(date string) model.NetWorthPoint {
+	return model.NetWorthPoint{
+		Date:        date,
+		Assets:      audMoney(t.assets),
+		Liabilities: audMoney(t.liabilities),
+		NetWorth:    audMoney(t.assets - t.liabilities),
+	}
+}
+
+// audMoney formats cents as Australian dollars
+func audMoney(cents int64) model.Money {
+	return model.Money{Amount: formatCents(cents), Currency: model.DefaultCurrency}
+}
+
+// NetWorth totals the latest stored balances of every account, with net
+// worth at the close of each of the last days (default 90) that had a
+// balance recorded by then. Each day uses every account's last snapshot on
+// or before it. Accounts held in other currencies are listed but not
+// totalled, as their balances can't be added to Australian dollars.
+func (s *reportService) NetWorth(days int) (*model.NetWorthReport, error) {
+	if days == 0 {
+		days = defaultNetWorthDays
+	}
+	if days < 1 || days > maxNetWorthDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", ErrInvalidReport, maxNetWorthDays)
+	}
+	now := s.now()
+
+	report := &model.NetWorthReport{
+		Accounts:    []model.NetWorthAccount{},
+		History:     []model.NetWorthPoint{},
+		GeneratedAt: now,
+	}
+	types := make(map[string]string)
+	var current netWorthTotals
+	for _, account := range s.store.Accounts() {
+		report.Accounts = append(report.Accounts, model.NetWorthAccount{
+			AccountID: account.ID,
+			Name:      account.Name,
+			Type:      account.Type,
+			Balance:   account.Balance,
+			Liability: isLiability(account.Type),
+		})
+		if currency := account.Balance.CurrencyCode(); currency != model.DefaultCurrency {
+			report.ForeignCurrencyAccounts = append(report.ForeignCurrencyAccounts, model.ForeignCurrencyAccount{
+				AccountID: account.ID,
+				Currency:  currency,
+			})
+			continue
+		}
+
+		types[account.ID] = account.Type
+		balance, err := parseBalanceCents(account.Balance.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("stored balance %q for account %s is unreadable: %w", account.Balance.Amount, account.ID, err)
+		}
+		current.add(account.Type, balance)
+	}
+	report.Assets = audMoney(current.assets)
+	report.Liabilities = audMoney(current.liabilities)
+	report.NetWorth = audMoney(current.assets - current.liabilities)
+
+	history := s.store.BalanceHistory("")
+	latest := make(map[string]int64)
+	next := 0
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for day := today.AddDate(0, 0, -(days - 1)); !day.After(today); day = day.AddDate(0, 0, 1) {
+		closing := day.AddDate(0, 0, 1)
+		for ; next < len(history) && history[next].RecordedAt.Before(closing); next++ {
+			snapshot := history[next]
+			if _, ok := types[snapshot.AccountID]; !ok {
+				continue
+			}
+			if balance, err := parseBalanceCents(snapshot.Balance.Amount); err == nil {
+				latest[snapshot.AccountID] = balance
+			}
+		}
+		if len(latest) == 0 {
+			continue
+		}
+
+		var totals netWorthTotals
+		for accountID, balance := range latest {
+			totals.add(types[accountID], balance)
+		}
+		report.History = append(report.History, totals.point(day.Format("2006-01-02")))
+	}
+	return report, nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestNetWorth(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.Local)
+	savings := model.Account{ID: "12345678", Name: "Everyday", Type: model.AccountTypeSavings}
+	card := model.Account{ID: "55667788", Name: "Low Rate Card", Type: model.AccountTypeCredit}
+	record := func(at time.Time, accounts ...model.Account) {
+		if err := dataStore.RecordAccounts(accounts, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	withBalance := func(account model.Account, balance string) model.Account {
+		account.Balance = model.Money{Amount: balance}
+		return account
+	}
+
+	record(now.AddDate(0, 0, -5), withBalance(savings, "1000.00"))
+	record(now.AddDate(0, 0, -3), withBalance(savings, "1500.00"), withBalance(card, "-200.00"))
+	record(now.AddDate(0, 0, -3).Add(time.Hour), withBalance(card, "-250.00"))
+	record(now, withBalance(savings, "1200.00"), withBalance(card, "-300.00"))
+
+	svc := NewReportService(dataStore)
+	svc.(*reportService).now = func() time.Time { return now }
+
+	report, err := svc.NetWorth(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Assets.Amount != "1200.00" || report.Liabilities.Amount != "300.00" || report.NetWorth.Amount != "900.00" {
+		t.Fatalf("unexpected totals %+v", report)
+	}
+	if len(report.Accounts) != 2 || !report.Accounts[1].Liability || report.Accounts[0].Liability {
+		t.Errorf("unexpected accounts %+v", report.Accounts)
+	}
+
+	want := []struct{ date, netWorth string }{
+		{"2024-05-05", "1000.00"},
+		{"2024-05-06", "1000.00"},
+		{"2024-05-07", "1250.00"},
+		{"2024-05-08", "1250.00"},
+		{"2024-05-09", "1250.00"},
+		{"2024-05-10", "900.00"},
+	}
+	if len(report.History) != len(want) {
+		t.Fatalf("expected %d days of history, got %+v", len(want), report.History)
+	}
+	for i, w := range want {
+		if got := report.History[i]; got.Date != w.date || got.NetWorth.Amount != w.netWorth {
+			t.Errorf("day %d: got %s %s, want %s %s", i, got.Date, got.NetWorth.Amount, w.date, w.netWorth)
+		}
+	}
+
+	if report, err := svc.NetWorth(2); err != nil || len(report.History) != 2 || report.History[0].NetWorth.Amount != "1250.00" {
+		t.Errorf("expected two days carrying the earlier balances forward, got %+v, %v", report, err)
+	}
+	if _, err := svc.NetWorth(maxNetWorthDays + 1); !errors.Is(err, ErrInvalidReport) {
+		t.Errorf("expected an invalid report error, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+var (
+	// ErrNoSyncYet is returned for the last sync report before any sync
+	// has run
+	ErrNoSyncYet = errors.New("no sync has run yet")
+
+	// ErrBrowserModeUnsupported is returned when the NAB client can't
+	// change how its browser is launched
+	ErrBrowserModeUnsupported = errors.New("browser mode can't be changed")
+
+	// ErrCacheUnsupported is returned when the account service has no
+	// cache to clear
+	ErrCacheUnsupported = errors.New("cache clearing not supported")
+)
+
+// BrowserModeClient is implemented by NAB clients that launch their own
+// browser and can switch it between headless and visible
+type BrowserModeClient interface {
+	SetHeadless(headless bool) error
+	Headless() bool
+}
+
+// OperationsService lets operators drive syncs and the browser by hand
+type OperationsService interface {
+	ForceSync(ctx context.Context) (*model.Job, error)
+	LastSync() (*model.SyncReport, error)
+	ClearCache(ctx context.Context) (*model.CacheClearResponse, error)
+	CacheStats(ctx context.Context) (*model.CacheStatsResponse, error)
+	EvictCache(ctx context.Context, keys []string) (*model.CacheEvictResponse, error)
+	SetHeadless(headless bool) (*model.BrowserModeResponse, error)
+}
+
+// operationsService implements OperationsService
+type operationsService struct {
+	nabClient BankProvider
+	accounts  AccountService
+	syncs     SyncService
+	reports   *SyncReporter
+}
+
+// NewOperationsService creates an operations service over the services a
+// sync is made of
+func NewOperationsService(nabClient BankProvider, accounts AccountService, syncs SyncService, reports *SyncReporter) OperationsService {
+	return &operationsService{
+		nabClient: nabClient,
+		accounts:  accounts,
+		syncs:     syncs,
+		reports:   reports,
+	}
+}
+
+// ForceSync clears the cache so nothing is served from it, then starts a
+// sync job. A sync already running is returned instead.
+func (s *operationsService) ForceSync(ctx context.Context) (*model.Job, error) {
+	if _, err := s.ClearCache(ctx); err != nil && !errors.Is(err, ErrCacheUnsupported) {
+		return nil, err
+	}
+	return s.syncs.Start()
+}
+
+// LastSync reports on the sync running now, or the last one to finish
+func (s *operationsService) LastSync() (*model.SyncReport, error) {
+	report, ok := s.reports.Last()
+	if !ok {
+		return nil, ErrNoSyncYet
+	}
+	return report, nil
+}
+
+// ClearCache makes the next request for each account scrape NAB
+func (s *operationsService) ClearCache(ctx context.Context) (*model.CacheClearResponse, error) {
+	clearer, ok := s.accounts.(CacheClearer)
+	if !ok {
+		return nil, ErrCacheUnsupported
+	}
+	return clearer.ClearCache(ctx)
+}
+
+// CacheStats reports cache hits and misses and what is cached now
+func (s *operationsService) CacheStats(ctx context.Context) (*model.CacheStatsResponse, error) {
+	inspector, ok := s.accounts.(CacheInspector)
+	if !ok {
+		return nil, ErrCacheUnsupported
+	}
+	return inspector.CacheStats(ctx)
+}
+
+// EvictCache makes the next request for each of the given entries scrape
+// NAB, leaving the rest of the cache alone
+func (s *operationsService) EvictCache(ctx context.Context, keys []string) (*model.CacheEvictResponse, error) {
+	inspector, ok := s.accounts.(CacheInspector)
+	if !ok {
+		return nil, ErrCacheUnsupported
+	}
+	return inspector.EvictCache(ctx, keys)
+}
+
+// SetHeadless switches the browser launched for the next scrape, and
+// those after it, between headless and visible
+func (s *operationsService) SetHeadless(headless bool) (*model.BrowserModeResponse, error) {
+	client, ok := s.nabClient.(BrowserModeClient)
+	if !ok {
+		return nil, ErrBrowserModeUnsupported
+	}
+	if err := client.SetHeadless(headless); err != nil {
+		return nil, err
+	}
+	return &model.BrowserModeResponse{Headless: client.Headless()}, nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/notify"
+)
+
+// PayeeService defines the interface for saved payee operations
+type PayeeService interface {
+	GetPayees(ctx context.Context) ([]model.Payee, error)
+}
+
+// payeeService implements PayeeService
+type payeeService struct {
+	nabClient BankProvider
+	alerts    *alerter
+}
+
+// NewPayeeService creates a new payee service. Scrape failures are pushed
+// to the notifier; a nil notifier disables them.
+func NewPayeeService(nabClient BankProvider, notifier notify.Notifier) PayeeService {
+	return &payeeService{
+		nabClient: nabClient,
+		alerts:    newAlerter(notifier, AlertThresholds{}),
+	}
+}
+
+// GetPayees retrieves the saved payee list from NAB
+func (s *payeeService) GetPayees(ctx context.Context) ([]model.Payee, error) {
+	payees, err := s.nabClient.GetPayees(ctx)
+	if err != nil {
+		s.alerts.scrapeFailed(err)
+		return nil, err
+	}
+
+	return payees, nil
+}
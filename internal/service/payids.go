@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/notify"
+)
+
+// ErrPayIDsUnsupported is returned when the NAB client cannot scrape PayIDs
+var ErrPayIDsUnsupported = errors.New("PayIDs not supported")
+
+// PayIDClient is implemented by NAB clients that can scrape the PayID
+// settings page
+type PayIDClient interface {
+	GetPayIDs(ctx context.Context) ([]model.PayID, error)
+}
+
+// PayIDService defines the interface for PayID operations
+type PayIDService interface {
+	GetPayIDs(ctx context.Context) ([]model.PayID, error)
+}
+
+// payIDService implements PayIDService
+type payIDService struct {
+	nabClient BankProvider
+	alerts    *alerter
+}
+
+// NewPayIDService creates a new PayID service. Scrape failures are pushed
+// to the notifier; a nil notifier disables them.
+func NewPayIDService(nabClient BankProvider, notifier notify.Notifier) PayIDService {
+	return &payIDService{
+		nabClient: nabClient,
+		alerts:    newAlerter(notifier, AlertThresholds{}),
+	}
+}
+
+// GetPayIDs retrieves the registered PayIDs and their linked accounts
+func (s *payIDService) GetPayIDs(ctx context.Context) ([]model.PayID, error) {
+	client, ok := s.nabClient.(PayIDClient)
+	if !ok {
+		return nil, ErrPayIDsUnsupported
+	}
+
+	payIDs, err := client.GetPayIDs(ctx)
+	if err != nil {
+		s.alerts.scrapeFailed(err)
+		return nil, err
+	}
+
+	return payIDs, nil
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/audit"
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// ErrInvalidPayment is returned when a payment request fails validation
+var ErrInvalidPayment = errors.New("invalid payment")
+
+// ErrPaymentRejected is returned when NAB refuses a payment or its
+// one-time code
+var ErrPaymentRejected = errors.New("payment rejected by NAB")
+
+// ErrPaymentNotFound is returned when no payment is waiting for
+// authorisation under the given ID
+var ErrPaymentNotFound = errors.New("payment not found")
+
+// ErrPayeeNotFound is returned when a saved payee does not exist
+var ErrPayeeNotFound = errors.New("payee not found")
+
+// ErrPaymentsUnsupported is returned when the NAB client cannot drive the
+// Pay Anyone form
+var ErrPaymentsUnsupported = errors.New("payments not supported")
+
+// maxPaymentTextLength is the longest description or reference NAB accepts
+const maxPaymentTextLength = 18
+
+var (
+	paymentBSBPattern     = regexp.MustCompile(`^\d{6}$`)
+	paymentAccountPattern = regexp.MustCompile(`^\d{5,10}$`)
+	paymentCodePattern    = regexp.MustCompile(`^\d{4,8}$`)
+)
+
+// PayAnyoneClient is implemented by NAB clients that can make Pay Anyone
+// payments. Payments NAB holds for SMS confirmation are returned as
+// pending_auth and completed with AuthorizePayment.
+type PayAnyoneClient interface {
+	PayAnyone(ctx context.Context, req model.PayAnyoneRequest, from model.Account, payee model.Payee) (*model.PaymentResult, error)
+	AuthorizePayment(ctx context.Context, paymentID, code string) (*model.PaymentResult, error)
+}
+
+// PaymentService defines the interface for Pay Anyone payments
+type PaymentService interface {
+	PayAnyone(ctx context.Context, req model.PayAnyoneRequest) (*model.PaymentResult, error)
+	AuthorizePayment(ctx context.Context, paymentID string, req model.PaymentAuthRequest) (*model.PaymentResult, error)
+}
+
+// paymentService implements PaymentService
+type paymentService struct {
+	accountService AccountService
+	nabClient      BankProvider
+	audit          *audit.Log
+}
+
+// NewPaymentService creates a new payment service. Every payment and
+// authorisation attempted is recorded in the audit log, never with the
+// SMS code; a nil log disables that.
+func NewPaymentService(accountService AccountService, nabClient BankProvider, auditLog *audit.Log) PaymentService {
+	return &paymentService{
+		accountService: accountService,
+		nabClient:      nabClient,
+		audit:          auditLog,
+	}
+}
+
+// PayAnyone validates the request against current account and payee data
+// and then submits it through NAB, or stops at the review screen for a dry
+// run
+func (s *paymentService) PayAnyone(ctx context.Context, req model.PayAnyoneRequest) (*model.PaymentResult, error) {
+	result, err := s.payAnyone(ctx, req)
+
+	movement := model.AuditMovement{
+		FromAccountID: req.FromAccountID,
+		Amount:        req.Amount,
+		Description:   req.Description,
+		Reference:     req.Reference,
+		DryRun:        req.DryRun,
+	}
+	switch {
+	case req.Payee != nil:
+		movement.Payee = &model.Payee{Name: req.Payee.Name, BSB: req.Payee.BSB, AccountNumber: req.Payee.AccountNumber}
+	case req.PayeeID != "":
+		movement.Payee = &model.Payee{ID: req.PayeeID}
+	}
+	if result != nil {
+		applyPayment(&movement, result)
+	}
+	recordMovement(ctx, s.audit, model.AuditKindPayment, "pay_anyone", movement, err)
+
+	return result, err
+}
+
+// payAnyone carries out a payment for PayAnyone
+func (s *paymentService) payAnyone(ctx context.Context, req model.PayAnyoneRequest) (*model.PaymentResult, error) {
+	client, ok := s.nabClient.(PayAnyoneClient)
+	if !ok {
+		return nil, ErrPaymentsUnsupported
+	}
+
+	req.Amount = strings.TrimPrefix(strings.TrimSpace(req.Amount), "$")
+	req.Description = strings.TrimSpace(req.Description)
+	req.Reference = strings.TrimSpace(req.Reference)
+
+	from, err := s.validate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	payee, err := s.resolvePayee(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.PayAnyone(ctx, req, from, payee)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pay: %w", err)
+	}
+	if result.ProcessedAt.IsZero() {
+		result.ProcessedAt = time.Now()
+	}
+
+	return result, nil
+}
+
+// AuthorizePayment submits the SMS code for a payment waiting in
+// pending_auth
+func (s *paymentService) AuthorizePayment(ctx context.Context, paymentID string, req model.PaymentAuthRequest) (*model.PaymentResult, error) {
+	result, err := s.authorizePayment(ctx, paymentID, req)
+
+	movement := model.AuditMovement{PaymentID: paymentID}
+	if result != nil {
+		applyPayment(&movement, result)
+	}
+	recordMovement(ctx, s.audit, model.AuditKindPayment, "authorize_payment", movement, err)
+
+	return result, err
+}
+
+// authorizePayment carries out an authorisation for AuthorizePayment
+func (s *paymentService) authorizePayment(ctx context.Context, paymentID string, req model.PaymentAuthRequest) (*model.PaymentResult, error) {
+	client, ok := s.nabClient.(PayAnyoneClient)
+	if !ok {
+		return nil, ErrPaymentsUnsupported
+	}
+
+	code := strings.ReplaceAll(strings.TrimSpace(req.Code), " ", "")
+	if !paymentCodePattern.MatchString(code) {
+		return nil, fmt.Errorf("%w: code must be the numeric code NAB sent by SMS", ErrInvalidPayment)
+	}
+
+	result, err := client.AuthorizePayment(ctx, paymentID, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authorise payment: %w", err)
+	}
+	if result.ProcessedAt.IsZero() {
+		result.ProcessedAt = time.Now()
+	}
+
+	return result, nil
+}
+
+// validate checks the request and returns the account paying
+func (s *paymentService) validate(ctx context.Context, req model.PayAnyoneRequest) (model.Account, error) {
+	if req.FromAccountID == "" {
+		return model.Account{}, fmt.Errorf("%w: fromAccountId is required", ErrInvalidPayment)
+	}
+	if (req.PayeeID == "") == (req.Payee == nil) {
+		return model.Account{}, fmt.Errorf("%w: exactly one of payeeId or payee is required", ErrInvalidPayment)
+	}
+	if !transferAmountPattern.MatchString(req.Amount) {
+		return model.Account{}, fmt.Errorf("%w: amount must be a positive dollar amount", ErrInvalidPayment)
+	}
+	amount, _ := strconv.ParseFloat(req.Amount, 64)
+	if amount <= 0 {
+		return model.Account{}, fmt.Errorf("%w: amount must be greater than zero", ErrInvalidPayment)
+	}
+	if len(req.Description) > maxPaymentTextLength || len(req.Reference) > maxPaymentTextLength {
+		return model.Account{}, fmt.Errorf("%w: description and reference must be at most %d characters", ErrInvalidPayment, maxPaymentTextLength)
+	}
+
+	accounts, err := s.accountService.GetAllAccounts(ctx)
+	if err != nil {
+		return model.Account{}, err
+	}
+
+	var from *model.Account
+	for i := range accounts {
+		if accounts[i].ID == req.FromAccountID {
+			from = &accounts[i]
+			break
+		}
+	}
+	if from == nil {
+		return model.Account{}, ErrAccountNotFound
+	}
+
+	switch from.Type {
+	case model.AccountTypeLoan, model.AccountTypeTermDeposit:
+		return model.Account{}, fmt.Errorf("%w: cannot pay from a %s account", ErrInvalidPayment, strings.ReplaceAll(from.Type, "_", " "))
+	}
+
+	available := from.Balance
+	if from.AvailableBalance != nil {
+		available = *from.AvailableBalance
+	}
+	if funds, err := strconv.ParseFloat(available.Amount, 64); err == nil && amount > funds {
+		return model.Account{}, fmt.Errorf("%w: amount exceeds available balance of $%s", ErrInvalidPayment, available.Amount)
+	}
+
+	return *from, nil
+}
+
+// resolvePayee looks up a saved payee, or checks the BSB and account
+// number of a new one
+func (s *paymentService) resolvePayee(ctx context.Context, req model.PayAnyoneRequest) (model.Payee, error) {
+	if req.Payee != nil {
+		payee := model.Payee{
+			Name:          strings.TrimSpace(req.Payee.Name),
+			BSB:           strings.NewReplacer("-", "", " ", "").Replace(req.Payee.BSB),
+			AccountNumber: strings.NewReplacer("-", "", " ", "").Replace(req.Payee.AccountNumber),
+		}
+		if payee.Name == "" {
+			return model.Payee{}, fmt.Errorf("%w: payee name is required", ErrInvalidPayment)
+		}
+		if !paymentBSBPattern.MatchString(payee.BSB) {
+			return model.Payee{}, fmt.Errorf("%w: payee BSB must be 6 digits", ErrInvalidPayment)
+		}
+		if !paymentAccountPattern.MatchString(payee.AccountNumber) {
+			return model.Payee{}, fmt.Errorf("%w: payee account number must be 5 to 10 digits", ErrInvalidPayment)
+		}
+		return payee, nil
+	}
+
+	payees, err := s.nabClient.GetPayees(ctx)
+	if err != nil {
+		return model.Payee{}, fmt.Errorf("failed to get payees: %w", err)
+	}
+	for _, payee := range payees {
+		if payee.ID == req.PayeeID {
+			return payee, nil
+		}
+	}
+
+	return model.Payee{}, ErrPayeeNotFound
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestPayAnyone(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewMockNABClient()
+	svc := NewPaymentService(NewAccountService(client, dataStore, nil, AlertThresholds{}, RetryPolicy{}, BreakerPolicy{}, CachePolicy{}, nil, nil), client, nil)
+	ctx := context.Background()
+
+	saved, err := svc.PayAnyone(ctx, model.PayAnyoneRequest{FromAccountID: "12345678", PayeeID: "payee_001", Amount: "$120", Reference: "RENT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Status != model.PaymentStatusCompleted || saved.ReceiptNumber == nil || saved.Payee.Name != "J SMITH" {
+		t.Errorf("unexpected saved payee result %+v", saved)
+	}
+
+	newPayee := &model.NewPayee{Name: "J CITIZEN", BSB: "062-000", AccountNumber: "1234 5678"}
+	pending, err := svc.PayAnyone(ctx, model.PayAnyoneRequest{FromAccountID: "12345678", Payee: newPayee, Amount: "50.00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending.Status != model.PaymentStatusPendingAuth || pending.AuthExpiresAt == nil || pending.Payee.BSB != "062000" {
+		t.Fatalf("expected pending auth for new payee, got %+v", pending)
+	}
+
+	authorised, err := svc.AuthorizePayment(ctx, pending.ID, model.PaymentAuthRequest{Code: "123 456"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authorised.Status != model.PaymentStatusCompleted {
+		t.Errorf("expected completed after authorisation, got %+v", authorised)
+	}
+	if _, err := svc.AuthorizePayment(ctx, pending.ID, model.PaymentAuthRequest{Code: "abc"}); !errors.Is(err, ErrInvalidPayment) {
+		t.Errorf("expected invalid code, got %v", err)
+	}
+
+	invalid := []model.PayAnyoneRequest{
+		{FromAccountID: "12345678", Amount: "10.00"},
+		{FromAccountID: "12345678", PayeeID: "payee_001", Payee: newPayee, Amount: "10.00"},
+		{FromAccountID: "12345678", PayeeID: "payee_001", Amount: "9999.00"},
+		{FromAccountID: "12345678", PayeeID: "payee_001", Amount: "10.00", Reference: "THIS REFERENCE IS TOO LONG"},
+		{FromAccountID: "99887766", PayeeID: "payee_001", Amount: "10.00"},
+		{FromAccountID: "12345678", Payee: &model.NewPayee{Name: "X", BSB: "0620", AccountNumber: "12345678"}, Amount: "10.00"},
+	}
+	for _, req := range invalid {
+		if _, err := svc.PayAnyone(ctx, req); !errors.Is(err, ErrInvalidPayment) {
+			t.Errorf("expected invalid payment for %+v, got %v", req, err)
+		}
+	}
+
+	if _, err := svc.PayAnyone(ctx, model.PayAnyoneRequest{FromAccountID: "12345678", PayeeID: "payee_999", Amount: "10.00"}); !errors.Is(err, ErrPayeeNotFound) {
+		t.Errorf("expected payee not found, got %v", err)
+	}
+}
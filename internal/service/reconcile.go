@@ -0,0 +1,133 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/notify"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// ErrInvalidBalanceAssertion is returned when an expected balance or
+// tolerance isn't a dollar amount
+var ErrInvalidBalanceAssertion = errors.New("invalid balance assertion")
+
+// balanceAmountPattern matches a dollar amount, optionally negative, with up
+// to two decimal places
+var balanceAmountPattern = regexp.MustCompile(`^-?\d+(\.\d{1,2})?$`)
+
+// BalanceAssertionService checks balances expected by external systems
+// against the latest scraped balances
+type BalanceAssertionService interface {
+	Assert(accountID string, req model.BalanceAssertionRequest) (*model.BalanceAssertionResponse, error)
+}
+
+// balanceAssertionService implements BalanceAssertionService
+type balanceAssertionService struct {
+	store  *store.Store
+	alerts *alerter
+}
+
+// NewBalanceAssertionService creates a balance assertion service. Balances
+// are compared with the store rather than scraped, so assertions are cheap
+// enough to run after every accounting sync. Mismatches can be pushed to
+// the notifier; a nil notifier disables them.
+func NewBalanceAssertionService(store *store.Store, notifier notify.Notifier) BalanceAssertionService {
+	return &balanceAssertionService{
+		store:  store,
+		alerts: newAlerter(notifier, AlertThresholds{}),
+	}
+}
+
+// Assert compares the expected balance with the account's latest scraped
+// balance, alerting on a mismatch beyond the tolerance if asked to
+func (s *balanceAssertionService) Assert(accountID string, req model.BalanceAssertionRequest) (*model.BalanceAssertionResponse, error) {
+	expected, err := parseBalanceCents(req.ExpectedBalance)
+	if err != nil {
+		return nil, fmt.Errorf("%w: expectedBalance %v", ErrInvalidBalanceAssertion, err)
+	}
+	var tolerance int64
+	if req.Tolerance != nil {
+		if tolerance, err = parseBalanceCents(*req.Tolerance); err != nil || tolerance < 0 {
+			return nil, fmt.Errorf("%w: tolerance must be a positive dollar amount", ErrInvalidBalanceAssertion)
+		}
+	}
+
+	var account *model.Account
+	for _, stored := range s.store.Accounts() {
+		if stored.ID == accountID {
+			account = &stored
+			break
+		}
+	}
+	if account == nil {
+		return nil, ErrAccountNotFound
+	}
+	actual, err := parseBalanceCents(account.Balance.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("scraped balance %q for account %s is unreadable: %w", account.Balance.Amount, accountID, err)
+	}
+
+	difference := actual - expected
+	response := &model.BalanceAssertionResponse{
+		AccountID:       accountID,
+		Status:          model.BalanceAssertionMatched,
+		ExpectedBalance: model.Money{Amount: formatCents(expected)},
+		ActualBalance:   account.Balance,
+		Difference:      model.Money{Amount: formatCents(difference)},
+		Tolerance:       model.Money{Amount: formatCents(tolerance)},
+		Source:          req.Source,
+		ScrapedAt:       account.LastUpdated,
+		CheckedAt:       time.Now(),
+	}
+	if difference > tolerance || -difference > tolerance {
+		response.Status = model.BalanceAssertionMismatched
+		if req.Alert {
+			s.alerts.balanceMismatch(*account, response)
+			response.Alerted = true
+		}
+	}
+
+	return response, nil
+}
+
+// parseBalanceCents parses a dollar amount like "-1,234.50" or "$12" into
+// cents
+func parseBalanceCents(amount string) (int64, error) {
+	amount = strings.ReplaceAll(strings.TrimSpace(amount), ",", "")
+	negative := strings.HasPrefix(amount, "-")
+	amount = strings.TrimPrefix(strings.TrimPrefix(amount, "-"), "$")
+	if negative {
+		amount = "-" + amount
+	}
+	if !balanceAmountPattern.MatchString(amount) {
+		return 0, fmt.Errorf("%q is not a dollar amount", amount)
+	}
+
+	whole, fraction, _ := strings.Cut(strings.TrimPrefix(amount, "-"), ".")
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+	cents, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is out of range", amount)
+	}
+	if negative {
+		cents = -cents
+	}
+	return cents, nil
+}
+
+// formatCents formats cents as a dollar amount with two decimal places
+func formatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
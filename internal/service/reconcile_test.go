@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestAssertBalance(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts := []model.Account{{ID: "12345678", Name: "Everyday", Balance: model.Money{Amount: "2543.67"}}}
+	if err := dataStore.RecordAccounts(accounts, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewBalanceAssertionService(dataStore, nil)
+
+	tolerance := "0.50"
+	tests := []struct {
+		name           string
+		req            model.BalanceAssertionRequest
+		wantStatus     model.BalanceAssertionStatus
+		wantDifference string
+	}{
+		{"exact", model.BalanceAssertionRequest{ExpectedBalance: "2543.67"}, model.BalanceAssertionMatched, "0.00"},
+		{"formatted", model.BalanceAssertionRequest{ExpectedBalance: "$2,543.67"}, model.BalanceAssertionMatched, "0.00"},
+		{"within tolerance", model.BalanceAssertionRequest{ExpectedBalance: "2544", Tolerance: &tolerance}, model.BalanceAssertionMatched, "-0.33"},
+		{"beyond tolerance", model.BalanceAssertionRequest{ExpectedBalance: "2500.1", Tolerance: &tolerance, Alert: true}, model.BalanceAssertionMismatched, "43.57"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := svc.Assert("12345678", tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if response.Status != tt.wantStatus || response.Difference.Amount != tt.wantDifference {
+				t.Errorf("got %s with difference %s, want %s with %s", response.Status, response.Difference.Amount, tt.wantStatus, tt.wantDifference)
+			}
+			if response.Alerted != (tt.req.Alert && tt.wantStatus == model.BalanceAssertionMismatched) {
+				t.Errorf("unexpected alerted %v", response.Alerted)
+			}
+		})
+	}
+
+	if _, err := svc.Assert("12345678", model.BalanceAssertionRequest{ExpectedBalance: "lots"}); !errors.Is(err, ErrInvalidBalanceAssertion) {
+		t.Errorf("expected invalid assertion error, got %v", err)
+	}
+	if _, err := svc.Assert("99999999", model.BalanceAssertionRequest{ExpectedBalance: "1.00"}); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("expected account not found, got %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+package service
+
+import "time"
+
+// ReloadableService is implemented by account services whose cache TTLs
+// and alert thresholds can change while they run, so a configuration
+// reload doesn't need a restart
+type ReloadableService interface {
+	SetCacheTTL(ttl, maxStale time.Duration)
+	SetAlertThresholds(thresholds AlertThresholds)
+}
+
+// SetAlertThresholds changes when low balance and large transaction alerts
+// are raised, from the next scrape on
+func (s *accountService) SetAlertThresholds(thresholds AlertThresholds) {
+	s.alerts.setThresholds(thresholds)
+}
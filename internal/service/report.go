@@ -0,0 +1,227 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// ErrInvalidReport is returned for a report period that isn't a month
+var ErrInvalidReport = errors.New("invalid report")
+
+// Names given to transactions without a category or merchant in reports
+const (
+	uncategorised   = "Uncategorised"
+	unknownMerchant = "Unknown"
+)
+
+// ReportService aggregates stored transactions and balances into reports
+type ReportService interface {
+	Spending(period, accountID string) (*model.SpendingReport, error)
+	NetWorth(days int) (*model.NetWorthReport, error)
+	Fees(months int) (*model.FeeReport, error)
+	Subscriptions() (*model.SubscriptionReport, error)
+}
+
+// reportService implements ReportService
+type reportService struct {
+	store *store.Store
+	now   func() time.Time
+}
+
+// NewReportService creates a report service over the stored transactions
+// and balance snapshots
+func NewReportService(store *store.Store) ReportService {
+	return &reportService{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+// spendingTotals accumulates net spending in cents, and how many
+// transactions went into it, by name
+type spendingTotals struct {
+	cents map[string]int64
+	count map[string]int
+}
+
+// add takes a transaction's amount off the named group's spending
+func (t spendingTotals) add(name string, amount int64) {
+	t.cents[name] -= amount
+	t.count[name]++
+}
+
+// spentAndIncome totals the spending of groups money went out of, and the
+// income from groups money only came in from
+func (t spendingTotals) spentAndIncome() (int64, int64) {
+	var spent, income int64
+	for _, cents := range t.cents {
+		if cents > 0 {
+			spent += cents
+		} else {
+			income -= cents
+		}
+	}
+	return spent, income
+}
+
+// Spending reports a month's spending, given as YYYY-MM and defaulting to
+// the current month, optionally for a single account. Spending is in
+// Australian dollars, or a single account's own currency, and accounts in
+// other currencies are flagged rather than added in.
+func (s *reportService) Spending(period, accountID string) (*model.SpendingReport, error) {
+	now := s.now()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	if period != "" {
+		parsed, err := time.ParseInLocation("2006-01", period, now.Location())
+		if err != nil {
+			return nil, fmt.Errorf("%w: period must be a month like 2024-05", ErrInvalidReport)
+		}
+		start = parsed
+	}
+	previousStart := start.AddDate(0, -1, 0)
+
+	currencies := make(map[string]string)
+	for _, account := range s.store.Accounts() {
+		currencies[account.ID] = account.Balance.CurrencyCode()
+	}
+	currency := model.DefaultCurrency
+	if accountCurrency, ok := currencies[accountID]; ok {
+		currency = accountCurrency
+	}
+	money := func(cents int64) model.Money {
+		return model.Money{Amount: formatCents(cents), Currency: currency}
+	}
+
+	current, foreign := s.totals(start, accountID, currency, currencies)
+	previous, _ := s.totals(previousStart, accountID, currency, currencies)
+	spent, income := current[0].spentAndIncome()
+	previousSpent, _ := previous[0].spentAndIncome()
+
+	report := &model.SpendingReport{
+		Period:         start.Format("2006-01"),
+		PeriodStart:    start.Format("2006-01-02"),
+		PeriodEnd:      start.AddDate(0, 1, -1).Format("2006-01-02"),
+		PreviousPeriod: previousStart.Format("2006-01"),
+		TotalSpent:     money(spent),
+		TotalIncome:    money(income),
+		PreviousSpent:  money(previousSpent),
+		Change:         money(spent - previousSpent),
+		ChangePercent:  changePercent(spent, previousSpent),
+		Categories:     spendingGroups(current[0], previous[0], money),
+		Merchants:      spendingGroups(current[1], previous[1], money),
+		GeneratedAt:    now,
+	}
+	for _, id := range foreign {
+		report.ForeignCurrencyAccounts = append(report.ForeignCurrencyAccounts, model.ForeignCurrencyAccount{
+			AccountID: id,
+			Currency:  currencies[id],
+		})
+	}
+	if accountID != "" {
+		report.AccountID = &accountID
+	}
+	return report, nil
+}
+
+// totals adds up the stored transactions in currency in the month starting
+// at start by category and by merchant. Accounts in other currencies with
+// transactions in the month are returned, sorted, rather than added up.
+func (s *reportService) totals(start time.Time, accountID, currency string, currencies map[string]string) ([2]spendingTotals, []string) {
+	from, to := start.Format("2006-01-02"), start.AddDate(0, 1, -1).Format("2006-01-02")
+	totals := [2]spendingTotals{}
+	for i := range totals {
+		totals[i] = spendingTotals{cents: make(map[string]int64), count: make(map[string]int)}
+	}
+
+	var foreign []string
+	for id, transactions := range s.store.AllTransactions() {
+		if accountID != "" && id != accountID {
+			continue
+		}
+		for _, transaction := range transactions {
+			if transaction.Date < from || transaction.Date > to {
+				continue
+			}
+			// Scraped amounts don't record a currency; it's the account's
+			transactionCurrency, ok := currencies[id]
+			if !ok {
+				transactionCurrency = transaction.Amount.CurrencyCode()
+			}
+			if transactionCurrency != currency {
+				foreign = append(foreign, id)
+				break
+			}
+			amount, err := parseBalanceCents(transaction.Amount.Amount)
+			if err != nil {
+				continue
+			}
+
+			category := uncategorised
+			if transaction.Category != nil && *transaction.Category != "" {
+				category = *transaction.Category
+			}
+			merchant := unknownMerchant
+			switch {
+			case transaction.MerchantDetails != nil:
+				merchant = transaction.MerchantDetails.Name
+			case transaction.Merchant != nil && *transaction.Merchant != "":
+				merchant = *transaction.Merchant
+			}
+			totals[0].add(category, amount)
+			totals[1].add(merchant, amount)
+		}
+	}
+	sort.Strings(foreign)
+	return totals, foreign
+}
+
+// spendingGroups lists the groups spent on in either period, most spent
+// first
+func spendingGroups(current, previous spendingTotals, money func(int64) model.Money) []model.SpendingGroup {
+	names := make(map[string]bool)
+	for _, totals := range []spendingTotals{current, previous} {
+		for name, cents := range totals.cents {
+			if cents > 0 {
+				names[name] = true
+			}
+		}
+	}
+
+	groups := make([]model.SpendingGroup, 0, len(names))
+	for name := range names {
+		now, before := max(current.cents[name], 0), max(previous.cents[name], 0)
+		groups = append(groups, model.SpendingGroup{
+			Name:          name,
+			Spent:         money(now),
+			Transactions:  current.count[name],
+			PreviousSpent: money(before),
+			Change:        money(now - before),
+			ChangePercent: changePercent(now, before),
+		})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := current.cents[groups[i].Name], current.cents[groups[j].Name]
+		if a != b {
+			return a > b
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+// changePercent returns the change from before to now as a percentage of
+// before, rounded to one decimal place, or nil when nothing was spent
+// before
+func changePercent(now, before int64) *float64 {
+	if before == 0 {
+		return nil
+	}
+	percent := math.Round(float64(now-before)*1000/float64(before)) / 10
+	return &percent
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestSpendingReport(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	category := func(name string) *string { return &name }
+	coles := &model.MerchantDetails{Name: "Coles", ID: "coles"}
+	if err := dataStore.SaveTransactions("12345678", []model.Transaction{
+		{ID: "t1", Date: "2024-05-20", Amount: model.Money{Amount: "-120.00"}, Category: category("Groceries"), MerchantDetails: coles},
+		{ID: "t2", Date: "2024-05-10", Amount: model.Money{Amount: "-60.00"}, Category: category("Groceries"), MerchantDetails: coles},
+		{ID: "t3", Date: "2024-05-11", Amount: model.Money{Amount: "20.00"}, Category: category("Groceries"), MerchantDetails: coles},
+		{ID: "t4", Date: "2024-05-15", Amount: model.Money{Amount: "2500.00"}, Category: category("Income")},
+		{ID: "t5", Date: "2024-05-03", Amount: model.Money{Amount: "-15.99"}},
+		{ID: "t6", Date: "2024-04-12", Amount: model.Money{Amount: "-200.00"}, Category: category("Groceries"), MerchantDetails: coles},
+		{ID: "t7", Date: "2024-04-02", Amount: model.Money{Amount: "-50.00"}, Category: category("Fuel")},
+		{ID: "t8", Date: "2024-06-01", Amount: model.Money{Amount: "-999.00"}, Category: category("Groceries")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewReportService(dataStore)
+
+	report, err := svc.Spending("2024-05", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.PeriodEnd != "2024-05-31" || report.PreviousPeriod != "2024-04" || report.TotalSpent.Amount != "175.99" || report.TotalIncome.Amount != "2500.00" || report.PreviousSpent.Amount != "250.00" || report.Change.Amount != "-74.01" {
+		t.Fatalf("unexpected totals %+v", report)
+	}
+
+	want := []struct {
+		name, spent, previous string
+		transactions          int
+	}{
+		{"Groceries", "160.00", "200.00", 3},
+		{uncategorised, "15.99", "0.00", 1},
+		{"Fuel", "0.00", "50.00", 0},
+	}
+	if len(report.Categories) != len(want) {
+		t.Fatalf("expected %d categories, got %+v", len(want), report.Categories)
+	}
+	for i, w := range want {
+		got := report.Categories[i]
+		if got.Name != w.name || got.Spent.Amount != w.spent || got.PreviousSpent.Amount != w.previous || got.Transactions != w.transactions {
+			t.Errorf("category %d: got %+v, want %+v", i, got, w)
+		}
+	}
+	if *report.Categories[0].ChangePercent != -20 || report.Categories[1].ChangePercent != nil {
+		t.Errorf("unexpected change percentages %v and %v", report.Categories[0].ChangePercent, report.Categories[1].ChangePercent)
+	}
+	if report.Merchants[0].Name != "Coles" || report.Merchants[0].Spent.Amount != "160.00" {
+		t.Errorf("unexpected merchants %+v", report.Merchants)
+	}
+
+	if report, err := svc.Spending("2024-05", "99999999"); err != nil || report.TotalSpent.Amount != "0.00" {
+		t.Errorf("expected nothing spent from another account, got %+v, %v", report, err)
+	}
+	if _, err := svc.Spending("May 2024", ""); !errors.Is(err, ErrInvalidReport) {
+		t.Errorf("expected an invalid period error, got %v", err)
+	}
+
+	svc.(*reportService).now = func() time.Time { return time.Date(2024, 6, 15, 0, 0, 0, 0, time.Local) }
+	if report, err := svc.Spending("", ""); err != nil || report.Period != "2024-06" || report.TotalSpent.Amount != "999.00" {
+		t.Errorf("expected the current month by default, got %+v, %v", report, err)
+	}
+}
+
+func TestReportsKeepCurrenciesApart(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 5, 20, 12, 0, 0, 0, time.Local)
+	if err := dataStore.RecordAccounts([]model.Account{
+		{ID: "12345678", Name: "Everyday", Type: model.AccountTypeSavings, Balance: model.Money{Amount: "1000.00", Currency: "AUD"}},
+		{ID: "87654321", Name: "Travel USD", Type: model.AccountTypeSavings, Balance: model.Money{Amount: "500.00", Currency: "USD"}},
+		{ID: "55667788", Name: "Low Rate Card", Type: model.AccountTypeCredit, Balance: model.Money{Amount: "-200.00"}},
+	}, now); err != nil {
+		t.Fatal(err)
+	}
+	for id, amount := range map[string]string{"12345678": "-40.00", "87654321": "-75.00"} {
+		if err := dataStore.SaveTransactions(id, []model.Transaction{{ID: "t_" + id, Date: "2024-05-10", Amount: model.Money{Amount: amount}}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svc := NewReportService(dataStore)
+	svc.(*reportService).now = func() time.Time { return now }
+
+	netWorth, err := svc.NetWorth(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if netWorth.NetWorth.Amount != "800.00" || netWorth.NetWorth.Currency != "AUD" || len(netWorth.Accounts) != 3 {
+		t.Errorf("expected the USD account left out of net worth, got %+v", netWorth)
+	}
+	if len(netWorth.History) != 1 || netWorth.History[0].Assets.Amount != "1000.00" {
+		t.Errorf("expected the USD account left out of the history, got %+v", netWorth.History)
+	}
+	if want := (model.ForeignCurrencyAccount{AccountID: "87654321", Currency: "USD"}); len(netWorth.ForeignCurrencyAccounts) != 1 || netWorth.ForeignCurrencyAccounts[0] != want {
+		t.Errorf("expected the USD account flagged, got %+v", netWorth.ForeignCurrencyAccounts)
+	}
+
+	spending, err := svc.Spending("2024-05", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spending.TotalSpent.Amount != "40.00" || spending.TotalSpent.Currency != "AUD" || len(spending.ForeignCurrencyAccounts) != 1 || spending.ForeignCurrencyAccounts[0].AccountID != "87654321" {
+		t.Errorf("expected only AUD spending with the USD account flagged, got %+v", spending)
+	}
+
+	travel, err := svc.Spending("2024-05", "87654321")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if travel.TotalSpent.Amount != "75.00" || travel.TotalSpent.Currency != "USD" || travel.ForeignCurrencyAccounts != nil {
+		t.Errorf("expected a single account's spending in its own currency, got %+v", travel)
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy controls how failed scrapes are retried. The zero value
+// makes a single attempt.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// Jitter randomises each backoff by up to this fraction, so retries
+	// from concurrent requests don't line up
+	Jitter float64
+}
+
+// backoff returns the wait before the given retry, doubling from the
+// initial backoff up to the maximum
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	wait := p.InitialBackoff
+	for i := 1; i < retry && (p.MaxBackoff <= 0 || wait < p.MaxBackoff); i++ {
+		wait *= 2
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		wait += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(wait))
+	}
+	return wait
+}
+
+// retryable reports whether a scrape failure is worth trying again.
+// Timeouts and pages that didn't render as expected often succeed on a
+// second attempt; bad credentials, paused scraping, terms waiting to be
+// accepted, security challenges and abandoned requests won't, and
+// retrying a rejected login risks locking the account.
+func retryable(err error) bool {
+	switch {
+	case errors.Is(err, ErrAuthenticationFailed),
+		errors.Is(err, ErrTermsAcceptanceRequired),
+		errors.Is(err, ErrChallengeRequired),
+		errors.Is(err, ErrScrapingPaused),
+		errors.Is(err, ErrAccountNotFound),
+		errors.Is(err, ErrRequestAborted),
+		errors.Is(err, context.Canceled):
+		return false
+	}
+	return true
+}
+
+// retry calls fn until it succeeds, fails with an error that isn't
+// retryable, or the policy's attempts run out
+func retry[T any](ctx context.Context, policy RetryPolicy, fn func() (T, error)) (T, error) {
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		result, err := fn()
+		if err == nil || !retryable(err) {
+			return result, err
+		}
+		if attempt == attempts {
+			if attempts > 1 {
+				err = fmt.Errorf("%w (gave up after %d attempts)", err, attempts)
+			}
+			return result, err
+		}
+
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+	}
+}
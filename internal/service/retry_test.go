@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// flakyClient fails the first scrapes of accounts with err
+type flakyClient struct {
+	MockNABClient
+	failures int
+	err      error
+	calls    int
+}
+
+func (c *flakyClient) GetAccounts(ctx context.Context) ([]model.Account, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		return nil, c.err
+	}
+	return c.MockNABClient.GetAccounts(ctx)
+}
+
+func TestGetAllAccountsRetries(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond, Jitter: 0.5}
+	tests := []struct {
+		name      string
+		failures  int
+		err       error
+		wantCalls int
+		wantErr   bool
+	}{
+		{"succeeds after timeouts", 2, context.DeadlineExceeded, 3, false},
+		{"gives up after max attempts", 5, errors.New("could not find username input field"), 3, true},
+		{"bad credentials aren't retried", 5, ErrAuthenticationFailed, 1, true},
+		{"paused scraping isn't retried", 5, ErrScrapingPaused, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataStore, err := store.Open("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			client := &flakyClient{failures: tt.failures, err: tt.err}
+			svc := NewAccountService(client, dataStore, nil, AlertThresholds{}, policy, BreakerPolicy{}, CachePolicy{}, nil, nil)
+
+			_, err = svc.GetAllAccounts(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("expected %v to wrap %v", err, tt.err)
+			}
+			if client.calls != tt.wantCalls {
+				t.Errorf("expected %d attempts, got %d", tt.wantCalls, client.calls)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	for retry, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		if got := policy.backoff(retry); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", retry, got, want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/notify"
+)
+
+// ScheduledPaymentService defines the interface for upcoming payment
+// operations
+type ScheduledPaymentService interface {
+	GetScheduledPayments(ctx context.Context, accountID string) ([]model.ScheduledPayment, error)
+}
+
+// scheduledPaymentService implements ScheduledPaymentService
+type scheduledPaymentService struct {
+	nabClient BankProvider
+	alerts    *alerter
+}
+
+// NewScheduledPaymentService creates a new scheduled payment service. Scrape
+// failures are pushed to the notifier; a nil notifier disables them.
+func NewScheduledPaymentService(nabClient BankProvider, notifier notify.Notifier) ScheduledPaymentService {
+	return &scheduledPaymentService{
+		nabClient: nabClient,
+		alerts:    newAlerter(notifier, AlertThresholds{}),
+	}
+}
+
+// GetScheduledPayments retrieves upcoming payments from NAB, soonest first,
+// optionally limited to those leaving one account
+func (s *scheduledPaymentService) GetScheduledPayments(ctx context.Context, accountID string) ([]model.ScheduledPayment, error) {
+	payments, err := s.nabClient.GetScheduledPayments(ctx)
+	if err != nil {
+		s.alerts.scrapeFailed(err)
+		return nil, err
+	}
+
+	if accountID != "" {
+		filtered := payments[:0]
+		for _, payment := range payments {
+			if payment.FromAccountID != nil && *payment.FromAccountID == accountID {
+				filtered = append(filtered, payment)
+			}
+		}
+		payments = filtered
+	}
+
+	sort.SliceStable(payments, func(i, j int) bool {
+		return payments[i].NextDate < payments[j].NextDate
+	})
+
+	return payments, nil
+}
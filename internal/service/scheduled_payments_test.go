@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetScheduledPayments(t *testing.T) {
+	svc := NewScheduledPaymentService(NewMockNABClient(), nil)
+
+	payments, err := svc.GetScheduledPayments(context.Background(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payments) != 3 || payments[0].ID != "sched_003" {
+		t.Fatalf("expected 3 payments soonest first, got %+v", payments)
+	}
+
+	fromAccount, err := svc.GetScheduledPayments(context.Background(), "12345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fromAccount) != 2 {
+		t.Errorf("expected 2 payments from account, got %d", len(fromAccount))
+	}
+}
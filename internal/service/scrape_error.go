@@ -0,0 +1,24 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// ScrapeError is a scrape failure along with the error bundle saved when
+// it happened
+type ScrapeError struct {
+	Err    error
+	Bundle model.ErrorBundle
+}
+
+// Error describes the failure and where its error bundle was saved
+func (e *ScrapeError) Error() string {
+	return fmt.Sprintf("%v (error bundle %s)", e.Err, e.Bundle.Path)
+}
+
+// Unwrap makes the error match the failure it describes
+func (e *ScrapeError) Unwrap() error {
+	return e.Err
+}
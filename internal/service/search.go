@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/search"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// ErrInvalidSearch is returned for a search without words to look for or
+// with an unreadable amount bound
+var ErrInvalidSearch = errors.New("invalid search")
+
+// TransactionSearch is a search over stored transactions. Empty fields
+// don't filter.
+type TransactionSearch struct {
+	Query     string
+	AccountID string
+
+	// AmountMin and AmountMax are inclusive dollar amounts compared with
+	// the size of each transaction, whether money went in or out
+	AmountMin string
+	AmountMax string
+
+	Limit int
+}
+
+// SearchService finds stored transactions by description across accounts
+type SearchService interface {
+	Search(search TransactionSearch) ([]model.TransactionMatch, error)
+}
+
+// searchService implements SearchService
+type searchService struct {
+	store *store.Store
+}
+
+// NewSearchService creates a search service over the store's search index
+func NewSearchService(store *store.Store) SearchService {
+	return &searchService{store: store}
+}
+
+// Search returns matching transactions ranked by recency, newest first
+// across accounts with a stable order within a day
+func (s *searchService) Search(req TransactionSearch) ([]model.TransactionMatch, error) {
+	if search.Normalize(req.Query) == "" {
+		return nil, fmt.Errorf("%w: q must contain at least one word to search for", ErrInvalidSearch)
+	}
+	bound := func(name, amount string) (*int64, error) {
+		if amount == "" {
+			return nil, nil
+		}
+		cents, err := parseBalanceCents(amount)
+		if err != nil || cents < 0 {
+			return nil, fmt.Errorf("%w: %s must be a positive dollar amount", ErrInvalidSearch, name)
+		}
+		return &cents, nil
+	}
+	amountMin, err := bound("amountMin", req.AmountMin)
+	if err != nil {
+		return nil, err
+	}
+	amountMax, err := bound("amountMax", req.AmountMax)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []model.TransactionMatch{}
+	for _, match := range s.store.SearchTransactions(req.Query) {
+		if req.AccountID != "" && match.AccountID != req.AccountID {
+			continue
+		}
+		if amountMin != nil || amountMax != nil {
+			amount, err := parseBalanceCents(match.Amount.Amount)
+			if err != nil {
+				continue
+			}
+			if amount < 0 {
+				amount = -amount
+			}
+			if (amountMin != nil && amount < *amountMin) || (amountMax != nil && amount > *amountMax) {
+				continue
+			}
+		}
+		matches = append(matches, match)
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Date != matches[j].Date {
+			return matches[i].Date > matches[j].Date
+		}
+		if matches[i].AccountID != matches[j].AccountID {
+			return matches[i].AccountID < matches[j].AccountID
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	if req.Limit > 0 && len(matches) > req.Limit {
+		matches = matches[:req.Limit]
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestSearchTransactions(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	save := func(accountID string, transactions ...model.Transaction) {
+		if err := dataStore.SaveTransactions(accountID, transactions); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save("12345678",
+		model.Transaction{ID: "txn_1", Date: "2024-03-01", Description: "EFTPOS PURCH COLES 0456", Amount: model.Money{Amount: "-85.67"}},
+		model.Transaction{ID: "txn_2", Date: "2024-03-05", Description: "EFTPOS PURCH COLES 0456", Amount: model.Money{Amount: "-12.40"}},
+		model.Transaction{ID: "txn_3", Date: "2024-03-06", Description: "Direct Credit SALARY", Amount: model.Money{Amount: "2500.00"}},
+	)
+	save("87654321",
+		model.Transaction{ID: "txn_4", Date: "2024-03-03", Description: "Coles Express", Amount: model.Money{Amount: "-60.00"}},
+	)
+	svc := NewSearchService(dataStore)
+
+	ids := func(matches []model.TransactionMatch) []string {
+		var result []string
+		for _, match := range matches {
+			result = append(result, match.ID)
+		}
+		return result
+	}
+	tests := []struct {
+		name   string
+		search TransactionSearch
+		want   []string
+	}{
+		{"newest first across accounts", TransactionSearch{Query: "coles"}, []string{"txn_2", "txn_4", "txn_1"}},
+		{"one account", TransactionSearch{Query: "coles", AccountID: "87654321"}, []string{"txn_4"}},
+		{"amount range ignores direction", TransactionSearch{Query: "coles", AmountMin: "50", AmountMax: "$80"}, []string{"txn_4"}},
+		{"limited", TransactionSearch{Query: "eftpos", Limit: 1}, []string{"txn_2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := svc.Search(tt.search)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ids(matches)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	if _, err := svc.Search(TransactionSearch{Query: "coles", AmountMin: "-5"}); !errors.Is(err, ErrInvalidSearch) {
+		t.Errorf("expected invalid search, got %v", err)
+	}
+	if _, err := svc.Search(TransactionSearch{Query: "  "}); !errors.Is(err, ErrInvalidSearch) {
+		t.Errorf("expected invalid search, got %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+var (
+	// ErrLogoutUnsupported is returned when the NAB client has no session
+	// to log out of
+	ErrLogoutUnsupported = errors.New("logout not supported")
+
+	// ErrScreenshotUnsupported is returned when the NAB client has no
+	// browser to capture
+	ErrScreenshotUnsupported = errors.New("screenshots not supported")
+)
+
+// LogoutClient is implemented by NAB clients that keep a login between
+// scrapes
+type LogoutClient interface {
+	// Logout ends the NAB session, reporting whether NAB acknowledged it,
+	// and discards any persisted cookies and browser data
+	Logout(ctx context.Context) (loggedOut bool, cleared bool, err error)
+}
+
+// PageCaptureClient is implemented by NAB clients that drive a browser
+type PageCaptureClient interface {
+	// Screenshot captures the page NAB shows the session as a PNG
+	Screenshot(ctx context.Context) ([]byte, error)
+}
+
+// SessionService defines the interface for managing the NAB session
+type SessionService interface {
+	Logout(ctx context.Context) (*model.SessionLogoutResponse, error)
+	Screenshot(ctx context.Context) ([]byte, error)
+}
+
+// sessionService implements SessionService
+type sessionService struct {
+	nabClient BankProvider
+}
+
+// NewSessionService creates a new session service
+func NewSessionService(nabClient BankProvider) SessionService {
+	return &sessionService{nabClient: nabClient}
+}
+
+// Logout ends the NAB session so the next scrape logs in from scratch
+func (s *sessionService) Logout(ctx context.Context) (*model.SessionLogoutResponse, error) {
+	client, ok := s.nabClient.(LogoutClient)
+	if !ok {
+		return nil, ErrLogoutUnsupported
+	}
+
+	loggedOut, cleared, err := client.Logout(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &model.SessionLogoutResponse{
+		LoggedOut:      loggedOut,
+		SessionCleared: cleared,
+		LoggedOutAt:    time.Now(),
+	}, nil
+}
+
+// Screenshot captures what NAB is showing the session, to see why scrapes
+// fail without access to the server
+func (s *sessionService) Screenshot(ctx context.Context) ([]byte, error) {
+	client, ok := s.nabClient.(PageCaptureClient)
+	if !ok {
+		return nil, ErrScreenshotUnsupported
+	}
+	return client.Screenshot(ctx)
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// Statements transactions can be limited to
+const (
+	StatementCurrent  = "current"
+	StatementPrevious = "previous"
+)
+
+// ErrNoStatementPeriod is returned when limiting transactions to a
+// statement of an account without a known statement period, such as one
+// that isn't a credit card
+var ErrNoStatementPeriod = errors.New("no statement period known for this account")
+
+// StatementPeriod returns a credit card's current or previous statement
+// period. NAB only shows the current one, so the previous one is taken to
+// close the day before it opened and to open a month before that.
+func StatementPeriod(credit *model.CreditDetails, statement string) (*model.StatementPeriod, error) {
+	if credit == nil || credit.StatementPeriod == nil {
+		return nil, ErrNoStatementPeriod
+	}
+	current := *credit.StatementPeriod
+	switch statement {
+	case StatementCurrent:
+		return &current, nil
+	case StatementPrevious:
+		start, err := time.Parse("2006-01-02", current.Start)
+		if err != nil {
+			return nil, fmt.Errorf("statement period start %q is unreadable: %w", current.Start, err)
+		}
+		return &model.StatementPeriod{
+			Start: start.AddDate(0, -1, 0).Format("2006-01-02"),
+			End:   start.AddDate(0, 0, -1).Format("2006-01-02"),
+		}, nil
+	}
+	return nil, fmt.Errorf("statement must be %s or %s", StatementCurrent, StatementPrevious)
+}
+
+// FilterStatement limits an account's transactions to those dated in its
+// current or previous statement period, recording the period on the
+// details
+func FilterStatement(details *model.AccountDetails, statement string) error {
+	period, err := StatementPeriod(details.Credit, statement)
+	if err != nil {
+		return err
+	}
+
+	transactions := []model.Transaction{}
+	for _, transaction := range details.Transactions {
+		if transaction.Date >= period.Start && transaction.Date <= period.End {
+			transactions = append(transactions, transaction)
+		}
+	}
+	details.Transactions = transactions
+	details.Statement = period
+	return nil
+}
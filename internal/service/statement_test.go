@@ -0,0 +1,45 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestFilterStatement(t *testing.T) {
+	card := func() *model.AccountDetails {
+		return &model.AccountDetails{
+			Account: model.Account{
+				ID:     "55667788",
+				Credit: &model.CreditDetails{StatementPeriod: &model.StatementPeriod{Start: "2024-03-16", End: "2024-04-15"}},
+			},
+			Transactions: []model.Transaction{
+				{ID: "txn_4", Date: "2024-04-15"},
+				{ID: "txn_3", Date: "2024-03-16"},
+				{ID: "txn_2", Date: "2024-03-15"},
+				{ID: "txn_1", Date: "2024-02-15"},
+			},
+		}
+	}
+
+	current := card()
+	if err := FilterStatement(current, StatementCurrent); err != nil {
+		t.Fatal(err)
+	}
+	if len(current.Transactions) != 2 || current.Transactions[1].ID != "txn_3" || current.Statement.Start != "2024-03-16" {
+		t.Errorf("unexpected current statement %+v", current)
+	}
+
+	previous := card()
+	if err := FilterStatement(previous, StatementPrevious); err != nil {
+		t.Fatal(err)
+	}
+	if previous.Statement.Start != "2024-02-16" || previous.Statement.End != "2024-03-15" || len(previous.Transactions) != 1 || previous.Transactions[0].ID != "txn_2" {
+		t.Errorf("unexpected previous statement %+v, %+v", previous.Statement, previous.Transactions)
+	}
+
+	if err := FilterStatement(&model.AccountDetails{}, StatementCurrent); !errors.Is(err, ErrNoStatementPeriod) {
+		t.Errorf("expected no statement period, got %v", err)
+	}
+}
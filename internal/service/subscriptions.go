@@ -0,0 +1,226 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/search"
+)
+
+// recurrence is a frequency charges can recur at, recognised by the days
+// between them
+type recurrence struct {
+	frequency string
+	minDays   int
+	maxDays   int
+	perYear   int64
+}
+
+// recurrences are the intervals a merchant's charges must all fall within
+// to count as a subscription
+var recurrences = []recurrence{
+	{model.PaymentFrequencyWeekly, 6, 8, 52},
+	{model.PaymentFrequencyFortnightly, 13, 15, 26},
+	{model.PaymentFrequencyMonthly, 27, 32, 12},
+	{model.PaymentFrequencyQuarterly, 85, 95, 4},
+	{model.PaymentFrequencyYearly, 355, 375, 1},
+}
+
+// subscriptionKinds are words in a merchant's name or description that
+// tell what kind of subscription it is, checked in order
+var subscriptionKinds = []struct {
+	words []string
+	kind  string
+}{
+	{[]string{"netflix", "spotify", "stan", "disney", "binge", "kayo", "foxtel", "youtube", "paramount", "prime video", "apple com bill"}, model.SubscriptionKindStreaming},
+	{[]string{"gym", "fitness", "f45", "goodlife", "jetts", "crossfit", "yoga", "pilates"}, model.SubscriptionKindGym},
+	{[]string{"insurance", "insure", "nrma", "aami", "racv", "bupa", "medibank", "hcf", "nib", "allianz", "youi", "budget direct"}, model.SubscriptionKindInsurance},
+}
+
+// charge is one payment to a merchant
+type charge struct {
+	date   time.Time
+	cents  int64
+	record model.Transaction
+}
+
+// subscriptionKind classifies a merchant's subscription by its name and
+// description
+func subscriptionKind(merchant string, transaction model.Transaction) string {
+	text := " " + search.Normalize(merchant) + " " + transaction.SearchText + " "
+	for _, candidate := range subscriptionKinds {
+		for _, word := range candidate.words {
+			if strings.Contains(text, " "+word+" ") {
+				return candidate.kind
+			}
+		}
+	}
+	return model.SubscriptionKindOther
+}
+
+// merchantKey groups a transaction's charges by the merchant behind them,
+// falling back to its normalised description
+func merchantKey(transaction model.Transaction) (key, name string) {
+	switch {
+	case transaction.MerchantDetails != nil:
+		return transaction.MerchantDetails.ID, transaction.MerchantDetails.Name
+	case transaction.Merchant != nil && *transaction.Merchant != "":
+		return strings.ToLower(*transaction.Merchant), *transaction.Merchant
+	}
+	text := transaction.SearchText
+	if text == "" {
+		text = search.Normalize(transaction.Description)
+	}
+	return text, transaction.Description
+}
+
+// maxPriceStep is the most a subscription's price may move between
+// charges, as a fraction, before the charges are taken as ordinary
+// shopping that happens to be regular
+const maxPriceStep = 0.2
+
+// steadyAmounts reports whether each charge is within maxPriceStep of the
+// one before
+func steadyAmounts(charges []charge) bool {
+	for i := 1; i < len(charges); i++ {
+		previous, current := float64(charges[i-1].cents), float64(charges[i].cents)
+		if current < previous*(1-maxPriceStep) || current > previous*(1+maxPriceStep) {
+			return false
+		}
+	}
+	return true
+}
+
+// detectRecurrence returns the frequency every interval between the
+// charges, oldest first, falls within
+func detectRecurrence(charges []charge) (recurrence, bool) {
+	if len(charges) < 2 {
+		return recurrence{}, false
+	}
+	for _, candidate := range recurrences {
+		matches := true
+		for i := 1; i < len(charges); i++ {
+			days := int(charges[i].date.Sub(charges[i-1].date).Hours()/24 + 0.5)
+			if days < candidate.minDays || days > candidate.maxDays {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return candidate, true
+		}
+	}
+	return recurrence{}, false
+}
+
+// Subscriptions finds merchants charging an account at a regular weekly,
+// fortnightly, monthly, quarterly or yearly interval in the stored
+// transactions, flagging those whose latest charge rose. Subscriptions
+// not charged for two intervals are taken as cancelled and left out.
+func (s *reportService) Subscriptions() (*model.SubscriptionReport, error) {
+	now := s.now()
+
+	type groupKey struct{ accountID, merchant string }
+	groups := make(map[groupKey][]charge)
+	names := make(map[groupKey]string)
+	for accountID, transactions := range s.store.AllTransactions() {
+		for _, transaction := range transactions {
+			if transaction.Type == model.TransactionTypeTransfer || transaction.Type == model.TransactionTypeFee || transaction.Type == model.TransactionTypeInterest {
+				continue
+			}
+			cents, err := parseBalanceCents(transaction.Amount.Amount)
+			if err != nil || cents >= 0 {
+				continue
+			}
+			date, err := time.ParseInLocation("2006-01-02", transaction.Date, now.Location())
+			if err != nil {
+				continue
+			}
+			merchant, name := merchantKey(transaction)
+			key := groupKey{accountID, merchant}
+			groups[key] = append(groups[key], charge{date: date, cents: -cents, record: transaction})
+			names[key] = name
+		}
+	}
+
+	report := &model.SubscriptionReport{
+		Kinds:         []model.SubscriptionKindTotal{},
+		Subscriptions: []model.Subscription{},
+		GeneratedAt:   now,
+	}
+	var monthly int64
+	kindCosts := make(map[string]int64)
+	kindCounts := make(map[string]int)
+	for key, charges := range groups {
+		sort.Slice(charges, func(i, j int) bool { return charges[i].date.Before(charges[j].date) })
+		every, ok := detectRecurrence(charges)
+		if !ok || !steadyAmounts(charges) {
+			continue
+		}
+		last := charges[len(charges)-1]
+		if now.Sub(last.date) > time.Duration(2*every.maxDays)*24*time.Hour {
+			continue
+		}
+
+		cost := (last.cents*every.perYear + 6) / 12
+		subscription := model.Subscription{
+			Merchant:     names[key],
+			Kind:         subscriptionKind(names[key], last.record),
+			AccountID:    key.accountID,
+			Frequency:    every.frequency,
+			Amount:       model.Money{Amount: formatCents(last.cents)},
+			MonthlyCost:  model.Money{Amount: formatCents(cost)},
+			Charges:      len(charges),
+			LastCharged:  last.date.Format("2006-01-02"),
+			NextExpected: nextCharge(last.date, every).Format("2006-01-02"),
+		}
+		if previous := charges[len(charges)-2]; last.cents > previous.cents {
+			subscription.PriceChange = &model.SubscriptionPriceChange{
+				PreviousAmount: model.Money{Amount: formatCents(previous.cents)},
+				Increase:       model.Money{Amount: formatCents(last.cents - previous.cents)},
+				ChangedOn:      subscription.LastCharged,
+			}
+			report.PriceIncreases++
+		}
+
+		monthly += cost
+		kindCosts[subscription.Kind] += cost
+		kindCounts[subscription.Kind]++
+		report.Subscriptions = append(report.Subscriptions, subscription)
+	}
+
+	sort.Slice(report.Subscriptions, func(i, j int) bool {
+		a, b := report.Subscriptions[i], report.Subscriptions[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Merchant < b.Merchant
+	})
+	for kind, cost := range kindCosts {
+		report.Kinds = append(report.Kinds, model.SubscriptionKindTotal{
+			Kind:        kind,
+			MonthlyCost: model.Money{Amount: formatCents(cost)},
+			Count:       kindCounts[kind],
+		})
+	}
+	sort.Slice(report.Kinds, func(i, j int) bool { return report.Kinds[i].Kind < report.Kinds[j].Kind })
+	report.MonthlyCost = model.Money{Amount: formatCents(monthly)}
+	return report, nil
+}
+
+// nextCharge returns when a subscription is next expected to charge
+func nextCharge(last time.Time, every recurrence) time.Time {
+	switch every.frequency {
+	case model.PaymentFrequencyWeekly:
+		return last.AddDate(0, 0, 7)
+	case model.PaymentFrequencyFortnightly:
+		return last.AddDate(0, 0, 14)
+	case model.PaymentFrequencyMonthly:
+		return last.AddDate(0, 1, 0)
+	case model.PaymentFrequencyQuarterly:
+		return last.AddDate(0, 3, 0)
+	}
+	return last.AddDate(1, 0, 0)
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestSubscriptions(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var transactions []model.Transaction
+	add := func(date time.Time, description, amount string) {
+		transactions = append(transactions, model.Transaction{
+			ID:          fmt.Sprintf("txn_%d", len(transactions)+1),
+			Date:        date.Format("2006-01-02"),
+			Description: description,
+			Amount:      model.Money{Amount: amount},
+		})
+	}
+	start := time.Date(2024, 1, 3, 0, 0, 0, 0, time.Local)
+	for month := 0; month < 5; month++ {
+		price := "-16.99"
+		if month == 4 {
+			price = "-18.99"
+		}
+		add(start.AddDate(0, month, 0), "NETFLIX.COM MELBOURNE", price)
+		add(start.AddDate(0, month, 2), "Direct Debit - ANYTIME FITNESS", "-65.00")
+		if month < 2 {
+			// cancelled in February, so no longer a subscription
+			add(start.AddDate(0, month, 5), "OLD MAGAZINE SUBSCRIPTION", "-9.00")
+		}
+	}
+	for week := 0; week < 20; week++ {
+		add(start.AddDate(0, 0, 7*week), "EFTPOS COLES", fmt.Sprintf("-%d.00", 60+week*7%40))
+	}
+	add(start.AddDate(0, -12, 10), "AAMI INSURANCE", "-480.00")
+	add(start.AddDate(0, 0, 10), "AAMI INSURANCE", "-480.00")
+	if err := dataStore.SaveTransactions("12345678", transactions); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewReportService(dataStore)
+	svc.(*reportService).now = func() time.Time { return time.Date(2024, 5, 20, 12, 0, 0, 0, time.Local) }
+
+	report, err := svc.Subscriptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Subscriptions) != 3 {
+		t.Fatalf("expected netflix, the gym and insurance, got %+v", report.Subscriptions)
+	}
+	gym, insurance, netflix := report.Subscriptions[0], report.Subscriptions[1], report.Subscriptions[2]
+	if gym.Kind != model.SubscriptionKindGym || gym.Frequency != model.PaymentFrequencyMonthly || gym.MonthlyCost.Amount != "65.00" || gym.PriceChange != nil {
+		t.Errorf("unexpected gym %+v", gym)
+	}
+	if insurance.Kind != model.SubscriptionKindInsurance || insurance.Frequency != model.PaymentFrequencyYearly || insurance.MonthlyCost.Amount != "40.00" {
+		t.Errorf("unexpected insurance %+v", insurance)
+	}
+	if netflix.Kind != model.SubscriptionKindStreaming || netflix.Amount.Amount != "18.99" || netflix.LastCharged != "2024-05-03" || netflix.NextExpected != "2024-06-03" {
+		t.Errorf("unexpected netflix %+v", netflix)
+	}
+	if change := netflix.PriceChange; change == nil || change.PreviousAmount.Amount != "16.99" || change.Increase.Amount != "2.00" {
+		t.Errorf("expected netflix's price rise, got %+v", change)
+	}
+	if report.PriceIncreases != 1 || report.MonthlyCost.Amount != "123.99" || len(report.Kinds) != 3 {
+		t.Errorf("unexpected totals %+v", report)
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/benrowe/nab-bank-api/internal/events"
+	"github.com/benrowe/nab-bank-api/internal/jobs"
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// SyncService scrapes every account as a background job, for clients that
+// can't wait the best part of a minute for a scrape to finish
+type SyncService interface {
+	Start() (*model.Job, error)
+}
+
+// syncService implements SyncService
+type syncService struct {
+	accounts AccountService
+	jobs     *jobs.Manager
+	events   *events.Broker
+
+	mu      sync.Mutex
+	current string
+}
+
+// NewSyncService creates a sync service that runs its jobs on the job
+// manager, publishing their progress to the event broker, which may be nil
+func NewSyncService(accounts AccountService, jobs *jobs.Manager, broker *events.Broker) SyncService {
+	return &syncService{
+		accounts: accounts,
+		jobs:     jobs,
+		events:   broker,
+	}
+}
+
+// Start begins a sync job that lists the accounts and then refreshes each
+// one's details, with an item per account. While a sync is running it is
+// returned instead of starting another.
+func (s *syncService) Start() (*model.Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.current != "" {
+		if job, ok := s.jobs.Get(s.current); ok && job.Status == model.JobStatusRunning {
+			return &job, nil
+		}
+	}
+
+	job, err := s.jobs.StartPlanned("sync", s.plan)
+	if err != nil {
+		return nil, err
+	}
+	s.current = job.ID
+	return &job, nil
+}
+
+// plan lists the accounts to sync, with a task for each. The job runs
+// its tasks in order, so the last one reports the sync complete.
+func (s *syncService) plan(ctx context.Context) ([]jobs.Task, error) {
+	progress := s.events.StartSync(jobs.JobID(ctx), "api")
+	progress.Step(model.SyncStepListingAccounts, "")
+	accounts, err := s.accounts.GetAllAccounts(ctx)
+	if err != nil {
+		progress.Completed(0, err)
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		progress.Completed(0, nil)
+	}
+
+	tasks := make([]jobs.Task, 0, len(accounts))
+	for i, account := range accounts {
+		accountID, last := account.ID, i == len(accounts)-1
+		tasks = append(tasks, func(ctx context.Context) (interface{}, error) {
+			progress.Step(model.SyncStepScrapingAccount, accountID)
+			details, err := s.accounts.GetAccountDetails(ctx, accountID)
+			if err != nil {
+				progress.AccountFailed(accountID, err)
+			}
+			if last {
+				progress.Completed(len(accounts), nil)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return model.SyncAccountResult{
+				AccountID:        details.ID,
+				Name:             details.Name,
+				Balance:          details.Balance,
+				TransactionCount: len(details.Transactions),
+				SyncWarnings:     details.SyncWarnings,
+			}, nil
+		})
+	}
+	return tasks, nil
+}
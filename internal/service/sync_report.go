@@ -0,0 +1,108 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/events"
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// ScreenshotClient is implemented by NAB clients that capture screenshots
+// when a scrape goes wrong
+type ScreenshotClient interface {
+	// Screenshots lists the screenshots captured between from and to; a
+	// zero to means up to now
+	Screenshots(from, to time.Time) []string
+}
+
+// SyncReporter keeps a report of the most recent sync, built from the
+// progress events every sync publishes
+type SyncReporter struct {
+	screenshots ScreenshotClient
+
+	mu     sync.Mutex
+	latest *model.SyncReport
+}
+
+// NewSyncReporter creates a sync reporter following the broker's events.
+// Screenshots are listed when the NAB client captures them.
+func NewSyncReporter(nabClient BankProvider, broker *events.Broker) *SyncReporter {
+	reporter := &SyncReporter{}
+	reporter.screenshots, _ = nabClient.(ScreenshotClient)
+
+	subscription, _ := broker.Subscribe()
+	go func() {
+		for event := range subscription {
+			reporter.record(event)
+		}
+	}()
+	return reporter
+}
+
+// Last returns the report for the sync running now or, failing that, the
+// one that finished last, and false if there hasn't been a sync
+func (r *SyncReporter) Last() (*model.SyncReport, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.latest == nil {
+		return nil, false
+	}
+	report := *r.latest
+	if report.Status == model.SyncReportRunning {
+		report.DurationMs = time.Since(report.StartedAt).Milliseconds()
+	}
+	report.Errors = append([]model.SyncAccountError{}, r.latest.Errors...)
+	var completedAt time.Time
+	if report.CompletedAt != nil {
+		completedAt = *report.CompletedAt
+	}
+	report.Screenshots = r.screenshotsDuring(report.StartedAt, completedAt)
+	return &report, true
+}
+
+// record updates the report from a sync event
+func (r *SyncReporter) record(event model.Event) {
+	progress, ok := event.Data.(model.SyncEvent)
+	if !ok {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch event.Type {
+	case model.EventSyncStarted:
+		r.latest = &model.SyncReport{
+			SyncID:    progress.SyncID,
+			Trigger:   progress.Trigger,
+			Status:    model.SyncReportRunning,
+			StartedAt: event.Time,
+		}
+	case model.EventSyncCompleted:
+		if r.latest == nil || r.latest.SyncID != progress.SyncID {
+			return
+		}
+		completedAt := event.Time
+		r.latest.Status = model.SyncReportCompleted
+		r.latest.CompletedAt = &completedAt
+		r.latest.DurationMs = progress.DurationMs
+		r.latest.AccountsFound = progress.Accounts
+		r.latest.Failed = progress.Failed
+		r.latest.Error = progress.Error
+		r.latest.Errors = progress.Errors
+	}
+}
+
+// screenshotsDuring lists the screenshots captured during a sync
+func (r *SyncReporter) screenshotsDuring(from, to time.Time) []string {
+	if r.screenshots == nil {
+		return []string{}
+	}
+	screenshots := r.screenshots.Screenshots(from, to)
+	if screenshots == nil {
+		return []string{}
+	}
+	return screenshots
+}
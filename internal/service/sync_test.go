@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/events"
+	"github.com/benrowe/nab-bank-api/internal/jobs"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// sinceClient records how each scrape of transactions was asked for
+type sinceClient struct {
+	MockNABClient
+	full  int
+	since []string
+}
+
+func (c *sinceClient) GetAccountTransactions(ctx context.Context, accountID string) ([]model.Transaction, error) {
+	c.full++
+	return c.MockNABClient.GetAccountTransactions(ctx, accountID)
+}
+
+func (c *sinceClient) GetAccountTransactionsSince(ctx context.Context, accountID string, since string) ([]model.Transaction, error) {
+	c.since = append(c.since, since)
+	return []model.Transaction{{
+		ID:          "txn_new_" + accountID,
+		Date:        time.Now().Format("2006-01-02"),
+		Description: "EFTPOS Purchase - CAFE",
+		Amount:      model.Money{Amount: "-4.50"},
+		Balance:     model.Money{Amount: "2539.17"},
+	}}, nil
+}
+
+func TestIncrementalSync(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &sinceClient{}
+	broker := events.NewBroker()
+	published, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+	svc := NewAccountService(client, dataStore, nil, AlertThresholds{}, RetryPolicy{}, BreakerPolicy{}, CachePolicy{}, nil, broker)
+
+	first, err := svc.GetAccountDetails(context.Background(), "12345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.full != 1 || len(client.since) != 0 {
+		t.Fatalf("expected a full first sync, got %d full and %d incremental", client.full, len(client.since))
+	}
+	if len(published) != 0 {
+		t.Errorf("expected no new transaction events for the first sync, got %d", len(published))
+	}
+
+	second, err := svc.GetAccountDetails(context.Background(), "12345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.full != 1 || len(client.since) != 1 {
+		t.Fatalf("expected an incremental second sync, got %d full and %d incremental", client.full, len(client.since))
+	}
+	want := time.Now().AddDate(0, 0, -1-syncOverlapDays).Format("2006-01-02")
+	if client.since[0] != want {
+		t.Errorf("expected sync from %s, got %s", want, client.since[0])
+	}
+	if len(second.Transactions) != len(first.Transactions)+1 || second.Transactions[0].ID != "txn_new_12345678" {
+		t.Errorf("expected the new transaction on top of the stored ones, got %+v", second.Transactions)
+	}
+	if len(published) != 1 {
+		t.Fatalf("expected one new transaction event, got %d", len(published))
+	}
+	if event := (<-published).Data.(model.TransactionEvent); event.Transaction.ID != "txn_new_12345678" {
+		t.Errorf("unexpected new transaction event %+v", event)
+	}
+}
+
+func TestSyncJob(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts := NewAccountService(NewMockNABClient(), dataStore, nil, AlertThresholds{}, RetryPolicy{}, BreakerPolicy{}, CachePolicy{}, nil, nil)
+	manager := jobs.NewManager(time.Hour)
+	svc := NewSyncService(accounts, manager, nil)
+
+	job, err := svc.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, err := svc.Start(); err != nil || again.ID != job.ID {
+		t.Errorf("expected the running sync to be returned, got %v, %v", again, err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		finished, _ := manager.Get(job.ID)
+		if finished.Status == model.JobStatusCompleted {
+			if finished.Total == 0 || finished.Succeeded != finished.Total {
+				t.Errorf("expected every account to sync, got %+v", finished)
+			}
+			result, ok := finished.Items[0].Result.(model.SyncAccountResult)
+			if !ok || result.AccountID == "" || result.TransactionCount == 0 {
+				t.Errorf("expected an account result, got %+v", finished.Items[0].Result)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("sync didn't complete")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// screenshotClient reports a screenshot for every sync
+type screenshotClient struct {
+	MockNABClient
+}
+
+func (c *screenshotClient) Screenshots(from, to time.Time) []string {
+	return []string{"/app/screenshots/nab_debug_error_20240517_093000.png"}
+}
+
+func TestSyncReporter(t *testing.T) {
+	broker := events.NewBroker()
+	reporter := NewSyncReporter(&screenshotClient{}, broker)
+	if _, ok := reporter.Last(); ok {
+		t.Fatal("expected no report before a sync")
+	}
+
+	progress := broker.StartSync("job_1", "api")
+	progress.AccountFailed("87654321", ErrAccountNotFound)
+	progress.Completed(4, nil)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		report, ok := reporter.Last()
+		if ok && report.Status == model.SyncReportCompleted {
+			if report.SyncID != "job_1" || report.AccountsFound != 4 || report.Failed != 1 || report.Errors[0].AccountID != "87654321" {
+				t.Errorf("unexpected report %+v", report)
+			}
+			if len(report.Screenshots) != 1 {
+				t.Errorf("expected the sync's screenshot, got %v", report.Screenshots)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected a completed report")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
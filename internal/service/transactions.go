@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// TransactionsService brings the stored transactions of several accounts
+// up to date at once, so they can be listed together
+type TransactionsService interface {
+	RefreshTransactions(ctx context.Context, accountIDs []string) error
+}
+
+// transactionsService implements TransactionsService
+type transactionsService struct {
+	accountService AccountService
+	concurrency    int
+}
+
+// NewTransactionsService creates a transactions service that fetches up to
+// concurrency accounts at once. Accounts are fetched through the account
+// service, so its cache policy decides which of them are scraped.
+func NewTransactionsService(accountService AccountService, concurrency int) TransactionsService {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &transactionsService{
+		accountService: accountService,
+		concurrency:    concurrency,
+	}
+}
+
+// RefreshTransactions fetches the given accounts, or every account when
+// none are given, storing their transactions. Every account is tried; the
+// errors of those that failed are returned together.
+func (s *transactionsService) RefreshTransactions(ctx context.Context, accountIDs []string) error {
+	if len(accountIDs) == 0 {
+		accounts, err := s.accountService.GetAllAccounts(ctx)
+		if err != nil {
+			return err
+		}
+		for _, account := range accounts {
+			accountIDs = append(accountIDs, account.ID)
+		}
+	}
+
+	slots := make(chan struct{}, s.concurrency)
+	errs := make([]error, len(accountIDs))
+	var wg sync.WaitGroup
+	for i, accountID := range accountIDs {
+		wg.Add(1)
+		go func(i int, accountID string) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				errs[i] = fmt.Errorf("%w: %w", ErrRequestAborted, ctx.Err())
+				return
+			}
+			defer func() { <-slots }()
+
+			if _, err := s.accountService.GetAccountDetails(ctx, accountID); err != nil {
+				errs[i] = fmt.Errorf("account %s: %w", accountID, err)
+			}
+		}(i, accountID)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
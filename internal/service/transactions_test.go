@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// slowAccountService fetches accounts slowly, recording how many it
+// fetches at once
+type slowAccountService struct {
+	mu      sync.Mutex
+	running int
+	peak    int
+	fetched []string
+}
+
+func (s *slowAccountService) GetAllAccounts(ctx context.Context) ([]model.Account, error) {
+	return []model.Account{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}}, nil
+}
+
+func (s *slowAccountService) GetAccountDetails(ctx context.Context, accountID string) (*model.AccountDetails, error) {
+	s.mu.Lock()
+	s.running++
+	if s.running > s.peak {
+		s.peak = s.running
+	}
+	s.fetched = append(s.fetched, accountID)
+	s.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	s.mu.Lock()
+	s.running--
+	s.mu.Unlock()
+	if accountID == "missing" {
+		return nil, ErrAccountNotFound
+	}
+	return &model.AccountDetails{}, nil
+}
+
+func TestRefreshTransactions(t *testing.T) {
+	accounts := &slowAccountService{}
+	svc := NewTransactionsService(accounts, 2)
+
+	if err := svc.RefreshTransactions(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts.fetched) != 4 || accounts.peak != 2 {
+		t.Errorf("expected every account fetched two at a time, got %v with %d at once", accounts.fetched, accounts.peak)
+	}
+
+	err := svc.RefreshTransactions(context.Background(), []string{"1", "missing"})
+	if !errors.Is(err, ErrAccountNotFound) || len(accounts.fetched) != 6 {
+		t.Errorf("expected the missing account's error after fetching both, got %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/audit"
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// ErrInvalidTransfer is returned when a transfer request fails validation
+var ErrInvalidTransfer = errors.New("invalid transfer")
+
+// ErrTransferRejected is returned when NAB refuses a transfer
+var ErrTransferRejected = errors.New("transfer rejected by NAB")
+
+// ErrTransfersUnsupported is returned when the NAB client cannot drive the
+// transfer form
+var ErrTransfersUnsupported = errors.New("transfers not supported")
+
+// transferAmountPattern matches a positive dollar amount with up to two
+// decimal places
+var transferAmountPattern = regexp.MustCompile(`^\d+(\.\d{1,2})?$`)
+
+// TransferClient is implemented by NAB clients that can move money between
+// the customer's own accounts. In a dry run the form is filled and
+// reviewed but never confirmed.
+type TransferClient interface {
+	Transfer(ctx context.Context, req model.TransferRequest, from, to model.Account) (*model.TransferResult, error)
+}
+
+// TransferService defines the interface for transfers between own accounts
+type TransferService interface {
+	Transfer(ctx context.Context, req model.TransferRequest) (*model.TransferResult, error)
+}
+
+// transferService implements TransferService
+type transferService struct {
+	accountService AccountService
+	nabClient      BankProvider
+	audit          *audit.Log
+}
+
+// NewTransferService creates a new transfer service. Every transfer
+// attempted, including dry runs and rejected ones, is recorded in the
+// audit log; a nil log disables that.
+func NewTransferService(accountService AccountService, nabClient BankProvider, auditLog *audit.Log) TransferService {
+	return &transferService{
+		accountService: accountService,
+		nabClient:      nabClient,
+		audit:          auditLog,
+	}
+}
+
+// Transfer validates the request against current account data and then
+// submits it through NAB, or stops at the review screen for a dry run
+func (s *transferService) Transfer(ctx context.Context, req model.TransferRequest) (*model.TransferResult, error) {
+	result, err := s.transfer(ctx, req)
+
+	movement := model.AuditMovement{
+		FromAccountID: req.FromAccountID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        req.Amount,
+		Description:   req.Description,
+		DryRun:        req.DryRun,
+	}
+	if result != nil {
+		movement.Amount = result.Amount.Amount
+		movement.Status = result.Status
+		movement.ReceiptNumber = result.ReceiptNumber
+	}
+	recordMovement(ctx, s.audit, model.AuditKindTransfer, "transfer", movement, err)
+
+	return result, err
+}
+
+// transfer carries out a transfer for Transfer
+func (s *transferService) transfer(ctx context.Context, req model.TransferRequest) (*model.TransferResult, error) {
+	client, ok := s.nabClient.(TransferClient)
+	if !ok {
+		return nil, ErrTransfersUnsupported
+	}
+
+	req.Amount = strings.TrimPrefix(strings.TrimSpace(req.Amount), "$")
+	req.Description = strings.TrimSpace(req.Description)
+
+	from, to, err := s.validate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.Transfer(ctx, req, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transfer: %w", err)
+	}
+	if result.ProcessedAt.IsZero() {
+		result.ProcessedAt = time.Now()
+	}
+
+	return result, nil
+}
+
+// validate checks the request and returns the source and destination
+// accounts
+func (s *transferService) validate(ctx context.Context, req model.TransferRequest) (model.Account, model.Account, error) {
+	if req.FromAccountID == "" || req.ToAccountID == "" {
+		return model.Account{}, model.Account{}, fmt.Errorf("%w: fromAccountId and toAccountId are required", ErrInvalidTransfer)
+	}
+	if req.FromAccountID == req.ToAccountID {
+		return model.Account{}, model.Account{}, fmt.Errorf("%w: cannot transfer to the same account", ErrInvalidTransfer)
+	}
+	if !transferAmountPattern.MatchString(req.Amount) {
+		return model.Account{}, model.Account{}, fmt.Errorf("%w: amount must be a positive dollar amount", ErrInvalidTransfer)
+	}
+	amount, _ := strconv.ParseFloat(req.Amount, 64)
+	if amount <= 0 {
+		return model.Account{}, model.Account{}, fmt.Errorf("%w: amount must be greater than zero", ErrInvalidTransfer)
+	}
+
+	accounts, err := s.accountService.GetAllAccounts(ctx)
+	if err != nil {
+		return model.Account{}, model.Account{}, err
+	}
+
+	var from, to *model.Account
+	for i := range accounts {
+		switch accounts[i].ID {
+		case req.FromAccountID:
+			from = &accounts[i]
+		case req.ToAccountID:
+			to = &accounts[i]
+		}
+	}
+	if from == nil || to == nil {
+		return model.Account{}, model.Account{}, ErrAccountNotFound
+	}
+
+	switch from.Type {
+	case model.AccountTypeLoan, model.AccountTypeTermDeposit:
+		return model.Account{}, model.Account{}, fmt.Errorf("%w: cannot transfer from a %s account", ErrInvalidTransfer, strings.ReplaceAll(from.Type, "_", " "))
+	}
+	if to.Type == model.AccountTypeTermDeposit {
+		return model.Account{}, model.Account{}, fmt.Errorf("%w: cannot transfer to a term deposit", ErrInvalidTransfer)
+	}
+
+	available := from.Balance
+	if from.AvailableBalance != nil {
+		available = *from.AvailableBalance
+	}
+	if funds, err := strconv.ParseFloat(available.Amount, 64); err == nil && amount > funds {
+		return model.Account{}, model.Account{}, fmt.Errorf("%w: amount exceeds available balance of $%s", ErrInvalidTransfer, available.Amount)
+	}
+
+	return *from, *to, nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/audit"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestTransfer(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewMockNABClient()
+	svc := NewTransferService(NewAccountService(client, dataStore, nil, AlertThresholds{}, RetryPolicy{}, BreakerPolicy{}, CachePolicy{}, nil, nil), client, nil)
+	ctx := context.Background()
+
+	dryRun, err := svc.Transfer(ctx, model.TransferRequest{FromAccountID: "12345678", ToAccountID: "11223344", Amount: "$250", DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dryRun.Status != model.TransferStatusValidated || dryRun.ReceiptNumber != nil || dryRun.Amount.Amount != "250" || dryRun.Review == nil {
+		t.Errorf("unexpected dry run result %+v", dryRun)
+	}
+
+	completed, err := svc.Transfer(ctx, model.TransferRequest{FromAccountID: "12345678", ToAccountID: "11223344", Amount: "250.00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if completed.Status != model.TransferStatusCompleted || completed.ReceiptNumber == nil {
+		t.Errorf("expected receipt for completed transfer, got %+v", completed)
+	}
+
+	invalid := []model.TransferRequest{
+		{FromAccountID: "12345678", ToAccountID: "12345678", Amount: "10.00"},
+		{FromAccountID: "12345678", ToAccountID: "11223344", Amount: "-10.00"},
+		{FromAccountID: "12345678", ToAccountID: "11223344", Amount: "10.001"},
+		{FromAccountID: "12345678", ToAccountID: "11223344", Amount: "9999.00"},
+		{FromAccountID: "99887766", ToAccountID: "12345678", Amount: "10.00"},
+		{FromAccountID: "12345678", ToAccountID: "44332211", Amount: "10.00"},
+	}
+	for _, req := range invalid {
+		if _, err := svc.Transfer(ctx, req); !errors.Is(err, ErrInvalidTransfer) {
+			t.Errorf("expected invalid transfer for %+v, got %v", req, err)
+		}
+	}
+
+	if _, err := svc.Transfer(ctx, model.TransferRequest{FromAccountID: "12345678", ToAccountID: "00000000", Amount: "10.00"}); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("expected account not found, got %v", err)
+	}
+}
+
+func TestTransferIsAudited(t *testing.T) {
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	auditLog, err := audit.Open("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewMockNABClient()
+	svc := NewTransferService(NewAccountService(client, dataStore, nil, AlertThresholds{}, RetryPolicy{}, BreakerPolicy{}, CachePolicy{}, nil, nil), client, auditLog)
+	ctx := audit.WithActor(context.Background(), "tok_3f9a1c2b7d4e")
+
+	if _, err := svc.Transfer(ctx, model.TransferRequest{FromAccountID: "12345678", ToAccountID: "11223344", Amount: "250.00"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Transfer(ctx, model.TransferRequest{FromAccountID: "12345678", ToAccountID: "12345678", Amount: "10.00"}); err == nil {
+		t.Fatal("expected a transfer to the same account to fail")
+	}
+
+	entries, err := auditLog.Query(audit.Filter{Kind: model.AuditKindTransfer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected both transfers audited, got %+v", entries)
+	}
+	failed, completed := entries[0], entries[1]
+	if completed.Actor != "tok_3f9a1c2b7d4e" || completed.Result != model.AuditResultSuccess ||
+		completed.Movement.Status != model.TransferStatusCompleted || completed.Movement.ReceiptNumber == nil {
+		t.Errorf("unexpected entry for the completed transfer %+v", completed)
+	}
+	if failed.Result != model.AuditResultFailure || failed.Error == "" || failed.Movement.Amount != "10.00" {
+		t.Errorf("unexpected entry for the rejected transfer %+v", failed)
+	}
+}
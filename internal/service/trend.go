@@ -0,0 +1,41 @@
+package service
+
+import (
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// trendDays is how many days of closing balances account details include
+const trendDays = 30
+
+// balanceTrend reduces an account's balance history to one closing balance
+// per day over the last trendDays days, oldest first. The current balance
+// is used for today, as it may not have been recorded yet. Days without a
+// snapshot are left out.
+func balanceTrend(history []model.BalanceSnapshot, current model.Money, now time.Time) []model.BalanceTrendPoint {
+	today := now.Format("2006-01-02")
+	from := now.AddDate(0, 0, -(trendDays - 1)).Format("2006-01-02")
+
+	closing := make(map[string]model.BalanceSnapshot)
+	for _, snapshot := range history {
+		day := snapshot.RecordedAt.In(now.Location()).Format("2006-01-02")
+		if day < from || day > today {
+			continue
+		}
+		if last, ok := closing[day]; !ok || !snapshot.RecordedAt.Before(last.RecordedAt) {
+			closing[day] = snapshot
+		}
+	}
+	closing[today] = model.BalanceSnapshot{Balance: current, RecordedAt: now}
+
+	var trend []model.BalanceTrendPoint
+	for day := now.AddDate(0, 0, -(trendDays - 1)); !day.After(now); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		if snapshot, ok := closing[date]; ok {
+			trend = append(trend, model.BalanceTrendPoint{Date: date, Balance: snapshot.Balance.Amount})
+		}
+	}
+
+	return trend
+}
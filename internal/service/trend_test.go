@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func TestBalanceTrend(t *testing.T) {
+	now := time.Date(2023, 10, 17, 9, 0, 0, 0, time.UTC)
+	snapshot := func(at time.Time, amount string) model.BalanceSnapshot {
+		return model.BalanceSnapshot{AccountID: "12345678", Balance: model.Money{Amount: amount}, RecordedAt: at}
+	}
+
+	trend := balanceTrend([]model.BalanceSnapshot{
+		snapshot(now.AddDate(0, 0, -40), "900.00"),
+		snapshot(now.AddDate(0, 0, -29), "1000.00"),
+		snapshot(now.AddDate(0, 0, -2).Add(-time.Hour), "1100.00"),
+		snapshot(now.AddDate(0, 0, -2), "1150.00"),
+		snapshot(now.Add(-time.Hour), "1190.00"),
+	}, model.Money{Amount: "1200.00"}, now)
+
+	want := []model.BalanceTrendPoint{
+		{Date: "2023-09-18", Balance: "1000.00"},
+		{Date: "2023-10-15", Balance: "1150.00"},
+		{Date: "2023-10-17", Balance: "1200.00"},
+	}
+	if len(trend) != len(want) {
+		t.Fatalf("expected %d points, got %+v", len(want), trend)
+	}
+	for i := range want {
+		if trend[i] != want[i] {
+			t.Errorf("point %d: expected %+v, got %+v", i, want[i], trend[i])
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrVersionMismatch is returned when a change is made conditional on a
+// version of a resource that is no longer current, so two clients editing
+// the same resource can't silently overwrite each other
+var ErrVersionMismatch = errors.New("resource has changed since it was read")
+
+// CheckVersion checks a resource's current version against the version a
+// change expects. An expected version of zero makes the change
+// unconditional.
+func CheckVersion(current, expected int) error {
+	if expected != 0 && expected != current {
+		return fmt.Errorf("%w: expected version %d, current version is %d", ErrVersionMismatch, expected, current)
+	}
+	return nil
+}
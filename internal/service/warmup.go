@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// WarmUpClient is implemented by NAB clients that can log in ahead of the
+// first request
+type WarmUpClient interface {
+	WarmUp(ctx context.Context) error
+}
+
+// WarmUp logs in to NAB once at startup and tracks how it went for the
+// readiness check
+type WarmUp struct {
+	client WarmUpClient
+
+	mu     sync.RWMutex
+	status model.WarmUpStatus
+}
+
+// NewWarmUp creates a warm-up for the NAB client. It is reported as
+// disabled when enabled is false or the client cannot warm up.
+func NewWarmUp(nabClient BankProvider, enabled bool) *WarmUp {
+	w := &WarmUp{status: model.WarmUpStatus{State: model.WarmUpStateDisabled}}
+	if client, ok := nabClient.(WarmUpClient); ok && enabled {
+		w.client = client
+		w.status.State = model.WarmUpStatePending
+	}
+	return w
+}
+
+// Run performs the warm-up login. It does nothing when disabled.
+func (w *WarmUp) Run(ctx context.Context) error {
+	if w.client == nil {
+		return nil
+	}
+
+	started := time.Now()
+	w.set(model.WarmUpStatus{State: model.WarmUpStateRunning, StartedAt: &started})
+
+	err := w.client.WarmUp(ctx)
+
+	completed := time.Now()
+	status := model.WarmUpStatus{
+		State:       model.WarmUpStateReady,
+		StartedAt:   &started,
+		CompletedAt: &completed,
+		DurationMs:  completed.Sub(started).Milliseconds(),
+	}
+	if err != nil {
+		status.State = model.WarmUpStateFailed
+		status.Error = err.Error()
+	}
+	w.set(status)
+
+	return err
+}
+
+// Status returns the current warm-up status
+func (w *WarmUp) Status() model.WarmUpStatus {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.status
+}
+
+// set replaces the warm-up status
+func (w *WarmUp) set(status model.WarmUpStatus) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.status = status
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// failingWarmUpClient fails to log in
+type failingWarmUpClient struct {
+	MockNABClient
+}
+
+func (c *failingWarmUpClient) WarmUp(ctx context.Context) error {
+	return errors.New("login page not found")
+}
+
+func TestWarmUp(t *testing.T) {
+	if state := NewWarmUp(NewMockNABClient(), false).Status().State; state != model.WarmUpStateDisabled {
+		t.Errorf("expected disabled warm-up, got %s", state)
+	}
+
+	warmUp := NewWarmUp(NewMockNABClient(), true)
+	if state := warmUp.Status().State; state != model.WarmUpStatePending {
+		t.Errorf("expected pending warm-up, got %s", state)
+	}
+	if err := warmUp.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	status := warmUp.Status()
+	if status.State != model.WarmUpStateReady || status.StartedAt == nil || status.CompletedAt == nil {
+		t.Errorf("unexpected status after warm-up %+v", status)
+	}
+
+	failing := NewWarmUp(&failingWarmUpClient{}, true)
+	if err := failing.Run(context.Background()); err == nil {
+		t.Fatal("expected warm-up error")
+	}
+	if status := failing.Status(); status.State != model.WarmUpStateFailed || status.Error != "login page not found" {
+		t.Errorf("unexpected failed status %+v", status)
+	}
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"sort"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// Budgets returns every budget, oldest first
+func (s *Store) Budgets() []model.Budget {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	budgets := make([]model.Budget, 0, len(s.data.Budgets))
+	for _, budget := range s.data.Budgets {
+		budgets = append(budgets, budget)
+	}
+	sort.Slice(budgets, func(i, j int) bool {
+		if !budgets[i].CreatedAt.Equal(budgets[j].CreatedAt) {
+			return budgets[i].CreatedAt.Before(budgets[j].CreatedAt)
+		}
+		return budgets[i].ID < budgets[j].ID
+	})
+
+	return budgets
+}
+
+// SaveBudget stores a budget, replacing any with the same ID
+func (s *Store) SaveBudget(budget model.Budget) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Budgets[budget.ID] = budget
+	return s.save()
+}
+
+// UpdateBudget applies update to a stored budget and saves it, reporting
+// whether the budget exists. The update runs under the store's lock, and
+// nothing is saved if it fails.
+func (s *Store) UpdateBudget(id string, update func(budget *model.Budget) error) (model.Budget, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	budget, ok := s.data.Budgets[id]
+	if !ok {
+		return model.Budget{}, false, nil
+	}
+	if err := update(&budget); err != nil {
+		return model.Budget{}, true, err
+	}
+	s.data.Budgets[id] = budget
+	return budget, true, s.save()
+}
+
+// DeleteBudget removes a budget if check allows it, reporting whether the
+// budget exists
+func (s *Store) DeleteBudget(id string, check func(budget model.Budget) error) (model.Budget, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	budget, ok := s.data.Budgets[id]
+	if !ok {
+		return model.Budget{}, false, nil
+	}
+	if err := check(budget); err != nil {
+		return model.Budget{}, true, err
+	}
+	delete(s.data.Budgets, id)
+	return budget, true, s.save()
+}
+
+// CategoryTransactions returns the stored transactions in a category
+// dated between two dates, inclusive, across every account
+func (s *Store) CategoryTransactions(category, from, to string) []model.Transaction {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matched []model.Transaction
+	for _, transactions := range s.data.Transactions {
+		for _, transaction := range transactions {
+			if transaction.Category != nil && *transaction.Category == category && transaction.Date >= from && transaction.Date <= to {
+				matched = append(matched, transaction)
+			}
+		}
+	}
+	return matched
+}
@@ -0,0 +1,188 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/search"
+)
+
+// migrateMerchantCategories turns the merchant categories of stores
+// written before category rules into rules, with IDs derived from their
+// merchants so reopening an unsaved store gives the same IDs
+func migrateMerchantCategories(data *storeData, at time.Time) {
+	for merchant, category := range data.MerchantCategories {
+		sum := sha256.Sum256([]byte(merchant))
+		id := "rule_" + hex.EncodeToString(sum[:8])
+		data.CategoryRules[id] = model.CategoryRule{
+			ID:        id,
+			Pattern:   merchant,
+			Category:  category,
+			Version:   1,
+			CreatedAt: at,
+			UpdatedAt: at,
+		}
+	}
+	data.MerchantCategories = nil
+}
+
+// CategoryRules returns every category rule, oldest first
+func (s *Store) CategoryRules() []model.CategoryRule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rules := make([]model.CategoryRule, 0, len(s.data.CategoryRules))
+	for _, rule := range s.data.CategoryRules {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if !rules[i].CreatedAt.Equal(rules[j].CreatedAt) {
+			return rules[i].CreatedAt.Before(rules[j].CreatedAt)
+		}
+		return rules[i].ID < rules[j].ID
+	})
+
+	return rules
+}
+
+// CategoryRule returns a category rule by ID
+func (s *Store) CategoryRule(id string) (model.CategoryRule, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rule, ok := s.data.CategoryRules[id]
+	return rule, ok
+}
+
+// SaveCategoryRule stores a category rule, replacing any with the same ID,
+// and recategorises the stored transactions. It returns how many changed
+// category.
+func (s *Store) SaveCategoryRule(rule model.CategoryRule) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.CategoryRules[rule.ID] = rule
+	return s.recategorize(), s.save()
+}
+
+// UpdateCategoryRule applies update to a stored category rule, saves it and
+// recategorises the stored transactions, reporting whether the rule exists
+// and how many transactions changed category. The update runs under the
+// store's lock, and nothing is saved if it fails.
+func (s *Store) UpdateCategoryRule(id string, update func(rule *model.CategoryRule) error) (model.CategoryRule, int, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rule, ok := s.data.CategoryRules[id]
+	if !ok {
+		return model.CategoryRule{}, 0, false, nil
+	}
+	if err := update(&rule); err != nil {
+		return model.CategoryRule{}, 0, true, err
+	}
+	s.data.CategoryRules[id] = rule
+	return rule, s.recategorize(), true, s.save()
+}
+
+// DeleteCategoryRule removes a category rule if check allows it, and
+// recategorises the stored transactions. Transactions it had categorised
+// that no other rule matches are left uncategorised until they are next
+// scraped.
+func (s *Store) DeleteCategoryRule(id string, check func(rule model.CategoryRule) error) (model.CategoryRule, int, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rule, ok := s.data.CategoryRules[id]
+	if !ok {
+		return model.CategoryRule{}, 0, false, nil
+	}
+	if err := check(rule); err != nil {
+		return model.CategoryRule{}, 0, true, err
+	}
+	delete(s.data.CategoryRules, id)
+	return rule, s.recategorize(), true, s.save()
+}
+
+// TransactionCategories counts the stored transactions in each category
+func (s *Store) TransactionCategories() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, transactions := range s.data.Transactions {
+		for _, transaction := range transactions {
+			if transaction.Category != nil {
+				counts[*transaction.Category]++
+			}
+		}
+	}
+	return counts
+}
+
+// ApplyCategoryRules fills in the category of transactions matching a
+// category rule
+func (s *Store) ApplyCategoryRules(transactions []model.Transaction) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i, transaction := range transactions {
+		if rule, ok := s.categoryRule(transaction); ok {
+			transactions[i].Category = &rule.Category
+			transactions[i].CategoryRuleID = rule.ID
+		}
+	}
+}
+
+// recategorize reapplies the category rules to every stored transaction,
+// returning how many changed category. Transactions a rule no longer
+// matches lose the category it gave them. Callers must hold the lock.
+func (s *Store) recategorize() int {
+	updated := 0
+	for _, transactions := range s.data.Transactions {
+		for i, transaction := range transactions {
+			rule, ok := s.categoryRule(transaction)
+			switch {
+			case ok && (transaction.Category == nil || *transaction.Category != rule.Category):
+				transactions[i].Category = &rule.Category
+				updated++
+			case !ok && transaction.CategoryRuleID != "":
+				transactions[i].Category = nil
+				updated++
+			}
+			transactions[i].CategoryRuleID = rule.ID
+		}
+	}
+	return updated
+}
+
+// categoryRule finds the rule for a transaction's merchant or description,
+// preferring the longest pattern when several match. Callers must hold the
+// lock.
+func (s *Store) categoryRule(transaction model.Transaction) (model.CategoryRule, bool) {
+	text := transaction.SearchText
+	if text == "" {
+		text = search.Normalize(transaction.Description)
+	}
+	if transaction.Merchant != nil {
+		text += " " + search.Normalize(*transaction.Merchant)
+	}
+	if transaction.MerchantDetails != nil {
+		text += " " + search.Normalize(transaction.MerchantDetails.Name)
+	}
+
+	var best model.CategoryRule
+	bestPattern := ""
+	for _, rule := range s.data.CategoryRules {
+		pattern := search.Normalize(rule.Pattern)
+		if len(pattern) < len(bestPattern) || (len(pattern) == len(bestPattern) && rule.ID > best.ID && bestPattern != "") {
+			continue
+		}
+		if search.Matches(text, pattern) {
+			best, bestPattern = rule, pattern
+		}
+	}
+	return best, bestPattern != ""
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// TransactionFingerprint identifies a transaction by what NAB shows for
+// it: its date, amount, description and the running balance after it.
+// NAB's transaction IDs aren't stable between scrapes, so this is what
+// tells a transaction seen again from a new one. Without a running
+// balance two identical purchases on the same day can't be told apart,
+// so there is no fingerprint and the transaction's ID is trusted instead.
+func TransactionFingerprint(transaction model.Transaction) string {
+	balance := strings.TrimSpace(transaction.Balance.Amount)
+	if balance == "" {
+		return ""
+	}
+	description := strings.ToLower(strings.Join(strings.Fields(transaction.Description), " "))
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		transaction.Date,
+		strings.TrimSpace(transaction.Amount.Amount),
+		description,
+		balance,
+	}, "\x1f")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// DedupeTransactions fingerprints scraped transactions and gives any
+// already stored under another ID their stored ID, so saving them updates
+// rather than duplicates. Each stored transaction is matched at most once,
+// so a fingerprint scraped more often than it is stored leaves the extra
+// transactions as new ones.
+func (s *Store) DedupeTransactions(accountID string, transactions []model.Transaction) []model.Transaction {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.dedupe(accountID, transactions)
+}
+
+// dedupe implements DedupeTransactions with the lock held
+func (s *Store) dedupe(accountID string, transactions []model.Transaction) []model.Transaction {
+	// The IDs stored under each fingerprint that are yet to be matched
+	storedIDs := make(map[string][]string)
+	for _, stored := range s.data.Transactions[accountID] {
+		if stored.Fingerprint != "" {
+			storedIDs[stored.Fingerprint] = append(storedIDs[stored.Fingerprint], stored.ID)
+		}
+	}
+
+	deduped := make([]model.Transaction, len(transactions))
+	var unmatched []int
+	for i, transaction := range transactions {
+		transaction.Fingerprint = TransactionFingerprint(transaction)
+		deduped[i] = transaction
+		// A transaction scraped under its stored ID matches it first, so
+		// it isn't handed to an identical one
+		if ids, ok := takeID(storedIDs[transaction.Fingerprint], transaction.ID); ok {
+			storedIDs[transaction.Fingerprint] = ids
+			continue
+		}
+		unmatched = append(unmatched, i)
+	}
+	for _, i := range unmatched {
+		fingerprint := deduped[i].Fingerprint
+		if ids := storedIDs[fingerprint]; len(ids) > 0 {
+			deduped[i].ID = ids[0]
+			storedIDs[fingerprint] = ids[1:]
+		}
+	}
+	return deduped
+}
+
+// takeID removes id from ids, reporting whether it was there
+func takeID(ids []string, id string) ([]string, bool) {
+	for i, stored := range ids {
+		if stored == id {
+			return append(ids[:i:i], ids[i+1:]...), true
+		}
+	}
+	return ids, false
+}
+
+// collapseDuplicates fingerprints stored transactions and drops any
+// stored twice under different IDs, keeping the first. Transactions
+// without a fingerprint are all kept.
+func collapseDuplicates(transactions []model.Transaction) []model.Transaction {
+	seen := make(map[string]bool, len(transactions))
+	collapsed := transactions[:0]
+	for _, transaction := range transactions {
+		transaction.Fingerprint = TransactionFingerprint(transaction)
+		if transaction.Fingerprint != "" {
+			if seen[transaction.Fingerprint] {
+				continue
+			}
+			seen[transaction.Fingerprint] = true
+		}
+		collapsed = append(collapsed, transaction)
+	}
+	return collapsed
+}
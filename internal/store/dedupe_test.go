@@ -0,0 +1,127 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+func transaction(id, amount, balance string) model.Transaction {
+	return model.Transaction{
+		ID:          id,
+		Date:        "2024-05-01",
+		Description: "EFTPOS PURCHASE COLES",
+		Amount:      model.Money{Amount: amount},
+		Balance:     model.Money{Amount: balance},
+	}
+}
+
+func transactionIDs(transactions []model.Transaction) []string {
+	ids := make([]string, len(transactions))
+	for i, transaction := range transactions {
+		ids[i] = transaction.ID
+	}
+	return ids
+}
+
+func TestDedupeGivesRescrapedTransactionsTheirStoredIDs(t *testing.T) {
+	s, err := Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveTransactions("acc", []model.Transaction{
+		transaction("txn_1", "-5.00", "95.00"),
+		transaction("txn_2", "-5.00", "90.00"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	deduped := s.DedupeTransactions("acc", []model.Transaction{
+		transaction("txn_a", "-5.00", "90.00"),
+		transaction("txn_b", "-5.00", "95.00"),
+		transaction("txn_c", "-5.00", "85.00"),
+	})
+	if got := transactionIDs(deduped); got[0] != "txn_2" || got[1] != "txn_1" || got[2] != "txn_c" {
+		t.Fatalf("IDs = %v, want [txn_2 txn_1 txn_c]", got)
+	}
+	if err := s.SaveTransactions("acc", deduped); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(s.Transactions("acc")); got != 3 {
+		t.Errorf("stored %d transactions, want 3", got)
+	}
+}
+
+func TestDedupeMatchesEachStoredTransactionOnce(t *testing.T) {
+	s, err := Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Zero amounts leave the running balance unchanged, so these share a
+	// fingerprint
+	if err := s.SaveTransactions("acc", []model.Transaction{transaction("txn_1", "0.00", "100.00")}); err != nil {
+		t.Fatal(err)
+	}
+
+	deduped := s.DedupeTransactions("acc", []model.Transaction{
+		transaction("txn_a", "0.00", "100.00"),
+		transaction("txn_1", "0.00", "100.00"),
+	})
+	if got := transactionIDs(deduped); got[0] != "txn_a" || got[1] != "txn_1" {
+		t.Fatalf("IDs = %v, want [txn_a txn_1]", got)
+	}
+}
+
+func TestDedupeTrustsIDsWithoutRunningBalance(t *testing.T) {
+	s, err := Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	purchases := []model.Transaction{
+		transaction("txn_1", "-5.00", ""),
+		transaction("txn_2", "-5.00", ""),
+	}
+	for i := 0; i < 2; i++ {
+		deduped := s.DedupeTransactions("acc", purchases)
+		if got := transactionIDs(deduped); len(got) != 2 || got[0] != "txn_1" || got[1] != "txn_2" {
+			t.Fatalf("IDs = %v, want [txn_1 txn_2]", got)
+		}
+		if deduped[0].Fingerprint != "" {
+			t.Errorf("fingerprint = %q, want none without a running balance", deduped[0].Fingerprint)
+		}
+		if err := s.SaveTransactions("acc", deduped); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := len(s.Transactions("acc")); got != 2 {
+		t.Errorf("stored %d transactions, want 2", got)
+	}
+}
+
+func TestOpenCollapsesOnlyFingerprintedDuplicates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Stored before deduplication existed
+	s.data.Transactions["acc"] = []model.Transaction{
+		transaction("txn_1", "-5.00", "95.00"),
+		transaction("txn_2", "-5.00", "95.00"),
+		transaction("txn_3", "-5.00", ""),
+		transaction("txn_4", "-5.00", ""),
+	}
+	if err := s.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := transactionIDs(reopened.Transactions("acc"))
+	if len(got) != 3 || got[0] != "txn_1" || got[1] != "txn_3" || got[2] != "txn_4" {
+		t.Errorf("IDs = %v, want [txn_1 txn_3 txn_4]", got)
+	}
+}
@@ -0,0 +1,39 @@
+package store
+
+import (
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// FeedState returns what the named feed has pushed so far
+func (s *Store) FeedState(name string) model.FeedState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return copyFeedState(s.data.Feeds[name])
+}
+
+// UpdateFeedState applies update to the named feed's state and saves it
+func (s *Store) UpdateFeedState(name string, update func(state *model.FeedState)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := copyFeedState(s.data.Feeds[name])
+	update(&state)
+	s.data.Feeds[name] = state
+	return s.save()
+}
+
+// copyFeedState copies a feed state so callers can't change the store's
+// maps, creating them if they are missing
+func copyFeedState(state model.FeedState) model.FeedState {
+	accounts := make(map[string]model.FeedAccount, len(state.Accounts))
+	for id, account := range state.Accounts {
+		accounts[id] = account
+	}
+	pushed := make(map[string]string, len(state.Pushed))
+	for key, remoteID := range state.Pushed {
+		pushed[key] = remoteID
+	}
+	state.Accounts, state.Pushed = accounts, pushed
+	return state
+}
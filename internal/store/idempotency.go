@@ -0,0 +1,31 @@
+package store
+
+import (
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// IdempotentResponse returns the response saved for an idempotency key
+func (s *Store) IdempotentResponse(key string) (model.IdempotentResponse, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	response, ok := s.data.IdempotentResponses[key]
+	return response, ok
+}
+
+// SaveIdempotentResponse saves the response for an idempotency key,
+// dropping saved responses created before expiredBefore
+func (s *Store) SaveIdempotentResponse(response model.IdempotentResponse, expiredBefore time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, saved := range s.data.IdempotentResponses {
+		if saved.CreatedAt.Before(expiredBefore) {
+			delete(s.data.IdempotentResponses, key)
+		}
+	}
+	s.data.IdempotentResponses[response.Key] = response
+	return s.save()
+}
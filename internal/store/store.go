@@ -0,0 +1,719 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/enrich"
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/search"
+)
+
+// Store persists scraped accounts, transactions, balance history, inbox
+// messages and advertised rates, along with transaction annotations,
+// merchant categories, archived accounts, registered refresh hooks, API
+// tokens and their usage, to a JSON file so data survives restarts and can be
+// exported for analysis
+type Store struct {
+	mu   sync.RWMutex
+	path string
+	data storeData
+
+	// index finds transactions by their search text. It isn't persisted
+	// but rebuilt when the store is opened.
+	index *search.Index
+}
+
+// storeData is the on-disk representation of the store
+type storeData struct {
+	Accounts     map[string]model.Account        `json:"accounts"`
+	Transactions map[string][]model.Transaction  `json:"transactions"`
+	Balances     []model.BalanceSnapshot         `json:"balances"`
+	Messages     map[string]model.Message        `json:"messages"`
+	Rates        map[string]model.AdvertisedRate `json:"rates"`
+	Hooks        map[string]model.RefreshHook    `json:"hooks"`
+	APITokens    map[string]model.APITokenRecord `json:"apiTokens"`
+	TokenUsage   []model.TokenUsage              `json:"tokenUsage,omitempty"`
+	Annotations  map[string]model.Annotation     `json:"annotations,omitempty"`
+
+	// MerchantCategories maps normalised merchant names to the category
+	// their transactions are filed under. It is only read from stores
+	// written before category rules, and turned into rules.
+	MerchantCategories map[string]string             `json:"merchantCategories,omitempty"`
+	CategoryRules      map[string]model.CategoryRule `json:"categoryRules,omitempty"`
+	ArchivedAccounts   map[string]time.Time          `json:"archivedAccounts,omitempty"`
+
+	AccountMetadata map[string]model.AccountMetadata `json:"accountMetadata,omitempty"`
+	Budgets         map[string]model.Budget          `json:"budgets,omitempty"`
+
+	// Feeds records what has been pushed to each external finance app
+	Feeds map[string]model.FeedState `json:"feeds,omitempty"`
+
+	// TransactionsUpdatedAt is when each account's transactions were last
+	// saved
+	TransactionsUpdatedAt map[string]time.Time `json:"transactionsUpdatedAt,omitempty"`
+
+	// IdempotentResponses are the responses to requests made with an
+	// Idempotency-Key, by key
+	IdempotentResponses map[string]model.IdempotentResponse `json:"idempotentResponses,omitempty"`
+
+	// Generation goes up every time accounts or transactions are saved,
+	// so pagination cursors can tell whether a list has changed
+	Generation uint64 `json:"generation,omitempty"`
+}
+
+// Open loads the store from path, creating it on first write. An empty path
+// gives an in-memory store that is never written to disk.
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path:  path,
+		index: search.NewIndex(),
+		data: storeData{
+			Accounts:     make(map[string]model.Account),
+			Transactions: make(map[string][]model.Transaction),
+			Messages:     make(map[string]model.Message),
+			Rates:        make(map[string]model.AdvertisedRate),
+			Hooks:        make(map[string]model.RefreshHook),
+			APITokens:    make(map[string]model.APITokenRecord),
+			Annotations:  make(map[string]model.Annotation),
+
+			CategoryRules:    make(map[string]model.CategoryRule),
+			ArchivedAccounts: make(map[string]time.Time),
+
+			AccountMetadata: make(map[string]model.AccountMetadata),
+			Budgets:         make(map[string]model.Budget),
+			Feeds:           make(map[string]model.FeedState),
+
+			TransactionsUpdatedAt: make(map[string]time.Time),
+			IdempotentResponses:   make(map[string]model.IdempotentResponse),
+		},
+	}
+
+	if path == "" {
+		return s, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read store: %w", err)
+	}
+
+	if err := json.Unmarshal(raw, &s.data); err != nil {
+		return nil, fmt.Errorf("failed to decode store: %w", err)
+	}
+	if s.data.Accounts == nil {
+		s.data.Accounts = make(map[string]model.Account)
+	}
+	if s.data.Transactions == nil {
+		s.data.Transactions = make(map[string][]model.Transaction)
+	}
+	if s.data.Messages == nil {
+		s.data.Messages = make(map[string]model.Message)
+	}
+	if s.data.Rates == nil {
+		s.data.Rates = make(map[string]model.AdvertisedRate)
+	}
+	if s.data.Hooks == nil {
+		s.data.Hooks = make(map[string]model.RefreshHook)
+	}
+	if s.data.APITokens == nil {
+		s.data.APITokens = make(map[string]model.APITokenRecord)
+	}
+	if s.data.Annotations == nil {
+		s.data.Annotations = make(map[string]model.Annotation)
+	}
+	if s.data.CategoryRules == nil {
+		s.data.CategoryRules = make(map[string]model.CategoryRule)
+	}
+	if s.data.ArchivedAccounts == nil {
+		s.data.ArchivedAccounts = make(map[string]time.Time)
+	}
+	if s.data.AccountMetadata == nil {
+		s.data.AccountMetadata = make(map[string]model.AccountMetadata)
+	}
+	if s.data.Budgets == nil {
+		s.data.Budgets = make(map[string]model.Budget)
+	}
+	if s.data.Feeds == nil {
+		s.data.Feeds = make(map[string]model.FeedState)
+	}
+	if s.data.TransactionsUpdatedAt == nil {
+		s.data.TransactionsUpdatedAt = make(map[string]time.Time)
+	}
+	if s.data.IdempotentResponses == nil {
+		s.data.IdempotentResponses = make(map[string]model.IdempotentResponse)
+	}
+
+	// Versions start at 1, so resources stored before they were versioned
+	// can still be matched against an ETag
+	for id, hook := range s.data.Hooks {
+		if hook.Version == 0 {
+			hook.Version = 1
+			s.data.Hooks[id] = hook
+		}
+	}
+	for id, annotation := range s.data.Annotations {
+		if annotation.Version == 0 {
+			annotation.Version = 1
+			s.data.Annotations[id] = annotation
+		}
+	}
+	migrateMerchantCategories(&s.data, time.Now())
+
+	// Stores written before search text and types existed, or by an
+	// older normaliser, are brought up to date, and transactions stored twice
+	// before deduplication are collapsed
+	for accountID, transactions := range s.data.Transactions {
+		transactions = collapseDuplicates(transactions)
+		s.data.Transactions[accountID] = transactions
+		for i := range transactions {
+			transactions[i].SearchText = search.Normalize(transactions[i].Description)
+			enrich.Classify(&transactions[i])
+			s.index.Add(searchDocument(accountID, transactions[i].ID), transactions[i].SearchText)
+		}
+	}
+
+	return s, nil
+}
+
+// RecordAccounts stores the latest account details and appends a balance
+// snapshot for each account
+func (s *Store) RecordAccounts(accounts []model.Account, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, account := range accounts {
+		s.data.Accounts[account.ID] = account
+		s.data.Balances = append(s.data.Balances, model.BalanceSnapshot{
+			AccountID:        account.ID,
+			Balance:          account.Balance,
+			AvailableBalance: account.AvailableBalance,
+			RecordedAt:       at,
+		})
+	}
+	s.data.Generation++
+
+	return s.save()
+}
+
+// SaveTransactions merges transactions for an account into the store,
+// replacing any existing transaction with the same ID or fingerprint. Each
+// transaction's search text, type and fee kind are filled in from its
+// description,
+// and its category from any category rule that matches.
+func (s *Store) SaveTransactions(accountID string, transactions []model.Transaction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	transactions = s.dedupe(accountID, transactions)
+
+	existing := s.data.Transactions[accountID]
+	index := make(map[string]int, len(existing))
+	for i, transaction := range existing {
+		index[transaction.ID] = i
+	}
+
+	for _, transaction := range transactions {
+		transaction.SearchText = search.Normalize(transaction.Description)
+		enrich.Classify(&transaction)
+		s.index.Add(searchDocument(accountID, transaction.ID), transaction.SearchText)
+		if rule, ok := s.categoryRule(transaction); ok {
+			transaction.Category = &rule.Category
+			transaction.CategoryRuleID = rule.ID
+		}
+		if i, ok := index[transaction.ID]; ok {
+			existing[i] = transaction
+			continue
+		}
+		index[transaction.ID] = len(existing)
+		existing = append(existing, transaction)
+	}
+
+	// Keep newest first, matching how NAB lists transactions
+	sort.SliceStable(existing, func(i, j int) bool {
+		return existing[i].Date > existing[j].Date
+	})
+	s.data.Transactions[accountID] = existing
+	s.data.TransactionsUpdatedAt[accountID] = time.Now()
+	s.data.Generation++
+
+	return s.save()
+}
+
+// Accounts returns the most recently recorded accounts ordered by ID
+func (s *Store) Accounts() []model.Account {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	accounts := make([]model.Account, 0, len(s.data.Accounts))
+	for _, account := range s.data.Accounts {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].ID < accounts[j].ID
+	})
+
+	return accounts
+}
+
+// Transactions returns the stored transactions for an account, newest first
+func (s *Store) Transactions(accountID string) []model.Transaction {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]model.Transaction(nil), s.data.Transactions[accountID]...)
+}
+
+// NewestTransactionDate returns the date of an account's newest stored
+// transaction, the high-water mark incremental syncs start from
+func (s *Store) NewestTransactionDate(accountID string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	transactions := s.data.Transactions[accountID]
+	if len(transactions) == 0 {
+		return "", false
+	}
+	return transactions[0].Date, true
+}
+
+// TransactionsUpdatedAt returns when an account's transactions were last
+// saved, if they ever were
+func (s *Store) TransactionsUpdatedAt(accountID string) (time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	at, ok := s.data.TransactionsUpdatedAt[accountID]
+	return at, ok
+}
+
+// SearchTransactions returns the stored transactions whose search text
+// contains every word of the query, each matching the start of a word, in
+// no particular order
+func (s *Store) SearchTransactions(query string) []model.TransactionMatch {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	wanted := make(map[string]map[string]bool)
+	for _, document := range s.index.Search(query) {
+		accountID, transactionID, _ := strings.Cut(document, "\x00")
+		if wanted[accountID] == nil {
+			wanted[accountID] = make(map[string]bool)
+		}
+		wanted[accountID][transactionID] = true
+	}
+
+	matches := []model.TransactionMatch{}
+	for accountID, ids := range wanted {
+		for _, transaction := range s.data.Transactions[accountID] {
+			if ids[transaction.ID] {
+				matches = append(matches, model.TransactionMatch{AccountID: accountID, Transaction: transaction})
+			}
+		}
+	}
+	return matches
+}
+
+// searchDocument is a transaction's ID in the search index
+func searchDocument(accountID, transactionID string) string {
+	return accountID + "\x00" + transactionID
+}
+
+// Generation returns the current sync generation, which changes whenever
+// accounts or transactions are saved
+func (s *Store) Generation() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.data.Generation
+}
+
+// AllTransactions returns every stored transaction keyed by account ID
+func (s *Store) AllTransactions() map[string][]model.Transaction {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	all := make(map[string][]model.Transaction, len(s.data.Transactions))
+	for accountID, transactions := range s.data.Transactions {
+		all[accountID] = append([]model.Transaction(nil), transactions...)
+	}
+
+	return all
+}
+
+// BalanceHistory returns balance snapshots in the order they were recorded.
+// An empty account ID returns the history for every account.
+func (s *Store) BalanceHistory(accountID string) []model.BalanceSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var history []model.BalanceSnapshot
+	for _, snapshot := range s.data.Balances {
+		if accountID == "" || snapshot.AccountID == accountID {
+			history = append(history, snapshot)
+		}
+	}
+
+	return history
+}
+
+// RecordMessages stores inbox messages, replacing any with the same ID, and
+// returns the messages that had not been recorded before
+func (s *Store) RecordMessages(messages []model.Message) ([]model.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var added []model.Message
+	for _, message := range messages {
+		if _, ok := s.data.Messages[message.ID]; !ok {
+			added = append(added, message)
+		}
+		s.data.Messages[message.ID] = message
+	}
+
+	return added, s.save()
+}
+
+// Messages returns the stored inbox messages, newest first
+func (s *Store) Messages() []model.Message {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	messages := make([]model.Message, 0, len(s.data.Messages))
+	for _, message := range s.data.Messages {
+		messages = append(messages, message)
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		if messages[i].Date != messages[j].Date {
+			return messages[i].Date > messages[j].Date
+		}
+		return messages[i].ID < messages[j].ID
+	})
+
+	return messages
+}
+
+// RecordAdvertisedRates replaces the rates stored for a page and returns
+// the products whose rate differs from the last check. Products seen for
+// the first time are stored but not reported as changes.
+func (s *Store) RecordAdvertisedRates(page string, rates []model.AdvertisedRate) ([]model.RateChange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var changes []model.RateChange
+	current := make(map[string]bool, len(rates))
+	for _, rate := range rates {
+		key := page + "\x00" + rate.Product
+		current[key] = true
+
+		if previous, ok := s.data.Rates[key]; ok && previous.Rate != rate.Rate {
+			changes = append(changes, model.RateChange{
+				Page:     page,
+				Product:  rate.Product,
+				OldRate:  previous.Rate,
+				NewRate:  rate.Rate,
+				Observed: rate.ObservedAt,
+			})
+		}
+		s.data.Rates[key] = rate
+	}
+
+	// Drop products no longer listed on the page
+	for key, rate := range s.data.Rates {
+		if rate.Page == page && !current[key] {
+			delete(s.data.Rates, key)
+		}
+	}
+
+	return changes, s.save()
+}
+
+// AdvertisedRates returns the latest advertised rates ordered by page and
+// product
+func (s *Store) AdvertisedRates() []model.AdvertisedRate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rates := make([]model.AdvertisedRate, 0, len(s.data.Rates))
+	for _, rate := range s.data.Rates {
+		rates = append(rates, rate)
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].Page != rates[j].Page {
+			return rates[i].Page < rates[j].Page
+		}
+		return rates[i].Product < rates[j].Product
+	})
+
+	return rates
+}
+
+// SaveHook stores a refresh hook, replacing any with the same ID
+func (s *Store) SaveHook(hook model.RefreshHook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Hooks[hook.ID] = hook
+	return s.save()
+}
+
+// DeleteHook removes a refresh hook, reporting whether it existed. When
+// check is given the hook is only removed if check allows it, so the
+// decision can't race with another change.
+func (s *Store) DeleteHook(id string, check func(hook model.RefreshHook) error) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hook, ok := s.data.Hooks[id]
+	if !ok {
+		return false, nil
+	}
+	if check != nil {
+		if err := check(hook); err != nil {
+			return true, err
+		}
+	}
+	delete(s.data.Hooks, id)
+	return true, s.save()
+}
+
+// Hooks returns the refresh hooks registered for an account, oldest first
+func (s *Store) Hooks(accountID string) []model.RefreshHook {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var hooks []model.RefreshHook
+	for _, hook := range s.data.Hooks {
+		if hook.AccountID == accountID {
+			hooks = append(hooks, hook)
+		}
+	}
+	sort.Slice(hooks, func(i, j int) bool {
+		if !hooks[i].CreatedAt.Equal(hooks[j].CreatedAt) {
+			return hooks[i].CreatedAt.Before(hooks[j].CreatedAt)
+		}
+		return hooks[i].ID < hooks[j].ID
+	})
+
+	return hooks
+}
+
+// SaveAPIToken stores an API token, replacing any with the same ID
+func (s *Store) SaveAPIToken(token model.APITokenRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.APITokens[token.ID] = token
+	return s.save()
+}
+
+// APITokens returns every stored API token, oldest first
+func (s *Store) APITokens() []model.APITokenRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tokens := make([]model.APITokenRecord, 0, len(s.data.APITokens))
+	for _, token := range s.data.APITokens {
+		tokens = append(tokens, token)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if !tokens[i].CreatedAt.Equal(tokens[j].CreatedAt) {
+			return tokens[i].CreatedAt.Before(tokens[j].CreatedAt)
+		}
+		return tokens[i].ID < tokens[j].ID
+	})
+
+	return tokens
+}
+
+// SaveTokenUsage replaces the stored API token usage
+func (s *Store) SaveTokenUsage(usage []model.TokenUsage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.TokenUsage = usage
+	return s.save()
+}
+
+// TokenUsage returns the stored API token usage
+func (s *Store) TokenUsage() []model.TokenUsage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]model.TokenUsage(nil), s.data.TokenUsage...)
+}
+
+// SaveAnnotation stores a transaction annotation, replacing any with the
+// same ID
+func (s *Store) SaveAnnotation(annotation model.Annotation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data.Annotations[annotation.ID] = annotation
+	return s.save()
+}
+
+// UpdateAnnotation applies update to a stored annotation and saves it,
+// reporting whether the annotation exists. The update runs under the
+// store's lock, and nothing is saved if it fails.
+func (s *Store) UpdateAnnotation(id string, update func(annotation *model.Annotation) error) (model.Annotation, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	annotation, ok := s.data.Annotations[id]
+	if !ok {
+		return model.Annotation{}, false, nil
+	}
+	if err := update(&annotation); err != nil {
+		return model.Annotation{}, true, err
+	}
+	s.data.Annotations[id] = annotation
+	return annotation, true, s.save()
+}
+
+// Annotation returns an annotation by ID, including deleted ones
+func (s *Store) Annotation(id string) (model.Annotation, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	annotation, ok := s.data.Annotations[id]
+	return annotation, ok
+}
+
+// Annotations returns every annotation, including deleted ones, oldest
+// first
+func (s *Store) Annotations() []model.Annotation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	annotations := make([]model.Annotation, 0, len(s.data.Annotations))
+	for _, annotation := range s.data.Annotations {
+		annotations = append(annotations, annotation)
+	}
+	sort.Slice(annotations, func(i, j int) bool {
+		if !annotations[i].CreatedAt.Equal(annotations[j].CreatedAt) {
+			return annotations[i].CreatedAt.Before(annotations[j].CreatedAt)
+		}
+		return annotations[i].ID < annotations[j].ID
+	})
+
+	return annotations
+}
+
+// PurgeAnnotations permanently removes deleted annotations whose restore
+// window ended before at, returning how many were removed
+func (s *Store) PurgeAnnotations(at time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	purged := 0
+	for id, annotation := range s.data.Annotations {
+		if annotation.PurgeAt != nil && annotation.PurgeAt.Before(at) {
+			delete(s.data.Annotations, id)
+			purged++
+		}
+	}
+	if purged == 0 {
+		return 0, nil
+	}
+	return purged, s.save()
+}
+
+// SetAccountArchived archives or unarchives an account
+func (s *Store) SetAccountArchived(accountID string, archived bool, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if archived {
+		s.data.ArchivedAccounts[accountID] = at
+	} else {
+		delete(s.data.ArchivedAccounts, accountID)
+	}
+	return s.save()
+}
+
+// AccountArchived reports whether an account has been archived
+func (s *Store) AccountArchived(accountID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.data.ArchivedAccounts[accountID]
+	return ok
+}
+
+// AccountMetadata returns the metadata attached to an account, if any
+func (s *Store) AccountMetadata(accountID string) (model.AccountMetadata, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	metadata, ok := s.data.AccountMetadata[accountID]
+	return metadata, ok
+}
+
+// UpdateAccountMetadata applies update to an account's metadata, starting
+// from empty metadata if it has none, and saves the result unless update
+// returns an error
+func (s *Store) UpdateAccountMetadata(accountID string, update func(metadata *model.AccountMetadata) error) (model.AccountMetadata, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	metadata := s.data.AccountMetadata[accountID]
+	if err := update(&metadata); err != nil {
+		return model.AccountMetadata{}, err
+	}
+	s.data.AccountMetadata[accountID] = metadata
+	return metadata, s.save()
+}
+
+// Writable checks the store's directory can still be written to, so a
+// full or read-only volume is noticed before a scrape fails to save. An
+// in-memory store is always writable.
+func (s *Store) Writable() error {
+	if s.path == "" {
+		return nil
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create store directory: %w", err)
+	}
+	probe, err := os.CreateTemp(dir, ".probe-*")
+	if err != nil {
+		return fmt.Errorf("store directory is not writable: %w", err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+// save writes the store to disk atomically. Callers must hold the lock.
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	raw, err := json.Marshal(s.data)
+	if err != nil {
+		return fmt.Errorf("failed to encode store: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return fmt.Errorf("failed to write store: %w", err)
+	}
+
+	return os.Rename(tmp, s.path)
+}
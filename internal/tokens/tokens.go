@@ -0,0 +1,320 @@
+package tokens
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/middleware"
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// ErrTokenNotFound is returned when no token has the given ID
+var ErrTokenNotFound = errors.New("token not found")
+
+// ErrInvalidToken is returned when a token request fails validation, or a
+// token can no longer be rotated
+var ErrInvalidToken = errors.New("invalid token request")
+
+// keyPrefix marks keys issued by the token API
+const keyPrefix = "nab_"
+
+// Store persists API tokens
+type Store interface {
+	APITokens() []model.APITokenRecord
+	SaveAPIToken(token model.APITokenRecord) error
+}
+
+// Manager issues, rotates and revokes API tokens. Only a hash of each key
+// is kept.
+type Manager struct {
+	store      Store
+	defaultTTL time.Duration
+	grace      time.Duration
+
+	mu     sync.RWMutex
+	tokens map[string]model.APITokenRecord
+	hashes map[string]string
+}
+
+// NewManager creates a token manager from the tokens saved in the store.
+// Tokens are created with defaultTTL unless a request says otherwise (zero
+// means they never expire), and rotated keys stay valid for grace.
+func NewManager(store Store, defaultTTL, grace time.Duration) *Manager {
+	m := &Manager{
+		store:      store,
+		defaultTTL: defaultTTL,
+		grace:      grace,
+		tokens:     make(map[string]model.APITokenRecord),
+		hashes:     make(map[string]string),
+	}
+	for _, token := range store.APITokens() {
+		m.tokens[token.ID] = token
+		m.hashes[token.KeyHash] = token.ID
+	}
+	return m
+}
+
+// Import adds statically configured keys as tokens that never expire, so
+// they can be listed, rotated and revoked. Keys already known, including
+// revoked ones, are left as they are. It returns how many were added.
+func (m *Manager) Import(keys []string, name string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	added := 0
+	for i, key := range keys {
+		hash := hashKey(key)
+		if _, ok := m.hashes[hash]; ok {
+			continue
+		}
+
+		token := model.APITokenRecord{
+			APIToken: model.APIToken{
+				ID:        middleware.TokenID(key),
+				Name:      fmt.Sprintf("%s[%d]", name, i),
+				CreatedAt: time.Now(),
+			},
+			KeyHash: hash,
+		}
+		if err := m.save(token); err != nil {
+			return added, err
+		}
+		added++
+	}
+	return added, nil
+}
+
+// Create issues a new token, returning its key
+func (m *Manager) Create(req model.TokenRequest) (model.CreatedToken, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return model.CreatedToken{}, fmt.Errorf("%w: name is required", ErrInvalidToken)
+	}
+	ttl, err := parseDuration("expiresIn", req.ExpiresIn, m.defaultTTL)
+	if err != nil {
+		return model.CreatedToken{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.issue(name, ttl)
+}
+
+// Rotate issues a replacement for a token. The old key keeps working for
+// the grace period, or until it would have expired anyway.
+func (m *Manager) Rotate(id string, req model.RotateTokenRequest) (model.CreatedToken, error) {
+	ttl, err := parseDuration("expiresIn", req.ExpiresIn, m.defaultTTL)
+	if err != nil {
+		return model.CreatedToken{}, err
+	}
+	grace, err := parseDuration("gracePeriod", req.GracePeriod, m.grace)
+	if err != nil {
+		return model.CreatedToken{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	old, ok := m.tokens[id]
+	if !ok {
+		return model.CreatedToken{}, ErrTokenNotFound
+	}
+	now := time.Now()
+	switch status(old.APIToken, now) {
+	case model.TokenStatusRevoked, model.TokenStatusExpired:
+		return model.CreatedToken{}, fmt.Errorf("%w: token is %s", ErrInvalidToken, status(old.APIToken, now))
+	case model.TokenStatusRotating:
+		return model.CreatedToken{}, fmt.Errorf("%w: token has already been rotated to %s", ErrInvalidToken, *old.ReplacedBy)
+	}
+
+	created, err := m.issue(old.Name, ttl)
+	if err != nil {
+		return model.CreatedToken{}, err
+	}
+
+	expires := now.Add(grace)
+	if old.ExpiresAt == nil || expires.Before(*old.ExpiresAt) {
+		old.ExpiresAt = &expires
+	}
+	old.ReplacedBy = &created.Token.ID
+	if err := m.save(old); err != nil {
+		return model.CreatedToken{}, err
+	}
+
+	return created, nil
+}
+
+// Revoke stops a token working immediately. Revoking a revoked token does
+// nothing.
+func (m *Manager) Revoke(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	token, ok := m.tokens[id]
+	if !ok {
+		return ErrTokenNotFound
+	}
+	if token.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	token.RevokedAt = &now
+	return m.save(token)
+}
+
+// List returns every token, oldest first
+func (m *Manager) List() []model.APIToken {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	tokens := make([]model.APIToken, 0, len(m.tokens))
+	for _, token := range m.tokens {
+		tokens = append(tokens, withStatus(token.APIToken, now))
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if !tokens[i].CreatedAt.Equal(tokens[j].CreatedAt) {
+			return tokens[i].CreatedAt.Before(tokens[j].CreatedAt)
+		}
+		return tokens[i].ID < tokens[j].ID
+	})
+	return tokens
+}
+
+// Get returns one token
+func (m *Manager) Get(id string) (model.APIToken, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	token, ok := m.tokens[id]
+	if !ok {
+		return model.APIToken{}, false
+	}
+	return withStatus(token.APIToken, time.Now()), true
+}
+
+// Authenticate returns the ID of the token for a key if it is neither
+// revoked nor expired
+func (m *Manager) Authenticate(key string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	id, ok := m.hashes[hashKey(key)]
+	if !ok {
+		return "", false
+	}
+	switch status(m.tokens[id].APIToken, time.Now()) {
+	case model.TokenStatusActive, model.TokenStatusRotating:
+		return id, true
+	}
+	return "", false
+}
+
+// OriginalTokenID follows a token's rotations back to the token it was
+// first issued as. Unknown tokens, such as admin keys, are their own
+// original.
+func (m *Manager) OriginalTokenID(id string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Each token replaces at most one other, so the chain is no longer
+	// than the token list
+	for range m.tokens {
+		previous := ""
+		for _, token := range m.tokens {
+			if token.ReplacedBy != nil && *token.ReplacedBy == id {
+				previous = token.ID
+				break
+			}
+		}
+		if previous == "" {
+			break
+		}
+		id = previous
+	}
+	return id
+}
+
+// issue generates a key and saves its token. Callers must hold the lock.
+func (m *Manager) issue(name string, ttl time.Duration) (model.CreatedToken, error) {
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return model.CreatedToken{}, fmt.Errorf("failed to generate key: %w", err)
+	}
+	key := keyPrefix + hex.EncodeToString(raw)
+
+	now := time.Now()
+	token := model.APITokenRecord{
+		APIToken: model.APIToken{
+			ID:        middleware.TokenID(key),
+			Name:      name,
+			CreatedAt: now,
+		},
+		KeyHash: hashKey(key),
+	}
+	if ttl > 0 {
+		expires := now.Add(ttl)
+		token.ExpiresAt = &expires
+	}
+	if err := m.save(token); err != nil {
+		return model.CreatedToken{}, err
+	}
+
+	return model.CreatedToken{Token: withStatus(token.APIToken, now), Key: key}, nil
+}
+
+// save persists a token and then updates the in-memory copy. Callers must
+// hold the lock.
+func (m *Manager) save(token model.APITokenRecord) error {
+	if err := m.store.SaveAPIToken(token); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+	m.tokens[token.ID] = token
+	m.hashes[token.KeyHash] = token.ID
+	return nil
+}
+
+// withStatus fills in the token's status as of now
+func withStatus(token model.APIToken, now time.Time) model.APIToken {
+	token.Status = status(token, now)
+	return token
+}
+
+// status works out whether a token is usable as of now
+func status(token model.APIToken, now time.Time) string {
+	switch {
+	case token.RevokedAt != nil:
+		return model.TokenStatusRevoked
+	case token.ExpiresAt != nil && !now.Before(*token.ExpiresAt):
+		return model.TokenStatusExpired
+	case token.ReplacedBy != nil:
+		return model.TokenStatusRotating
+	}
+	return model.TokenStatusActive
+}
+
+// parseDuration parses an optional duration field, where "0" means none
+func parseDuration(field, value string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%w: %s must be a duration such as 720h", ErrInvalidToken, field)
+	}
+	return d, nil
+}
+
+// hashKey hashes a key for storage and lookup
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
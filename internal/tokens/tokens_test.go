@@ -0,0 +1,114 @@
+package tokens
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/middleware"
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// memoryStore keeps tokens in memory
+type memoryStore struct {
+	tokens map[string]model.APITokenRecord
+}
+
+func (s *memoryStore) APITokens() []model.APITokenRecord {
+	var tokens []model.APITokenRecord
+	for _, token := range s.tokens {
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+func (s *memoryStore) SaveAPIToken(token model.APITokenRecord) error {
+	s.tokens[token.ID] = token
+	return nil
+}
+
+func TestTokenLifecycle(t *testing.T) {
+	store := &memoryStore{tokens: make(map[string]model.APITokenRecord)}
+	manager := NewManager(store, time.Hour, time.Minute)
+
+	if added, err := manager.Import([]string{"legacy"}, "API_KEYS"); err != nil || added != 1 {
+		t.Fatalf("expected 1 imported key, got %d (%v)", added, err)
+	}
+	if added, _ := manager.Import([]string{"legacy"}, "API_KEYS"); added != 0 {
+		t.Errorf("expected re-import to be skipped, got %d", added)
+	}
+	if id, ok := manager.Authenticate("legacy"); !ok || id != middleware.TokenID("legacy") {
+		t.Errorf("expected imported key to authenticate, got %q", id)
+	}
+
+	created, err := manager.Create(model.TokenRequest{Name: "ci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Token.ExpiresAt == nil || created.Token.Status != model.TokenStatusActive {
+		t.Errorf("expected default expiry, got %+v", created.Token)
+	}
+	for _, stored := range store.tokens {
+		if stored.KeyHash == created.Key {
+			t.Fatal("key must not be stored in plain text")
+		}
+	}
+
+	rotated, err := manager.Rotate(created.Token.ID, model.RotateTokenRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := manager.Authenticate(created.Key); !ok {
+		t.Error("old key should work during the grace period")
+	}
+	if _, ok := manager.Authenticate(rotated.Key); !ok {
+		t.Error("new key should work")
+	}
+	old, _ := manager.Get(created.Token.ID)
+	if old.Status != model.TokenStatusRotating || old.ReplacedBy == nil || *old.ReplacedBy != rotated.Token.ID {
+		t.Errorf("unexpected rotated token %+v", old)
+	}
+	if _, err := manager.Rotate(created.Token.ID, model.RotateTokenRequest{}); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected second rotation to fail, got %v", err)
+	}
+
+	// A zero grace period retires the old key straight away
+	immediate, err := manager.Rotate(rotated.Token.ID, model.RotateTokenRequest{GracePeriod: "0s", ExpiresIn: "0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := manager.Authenticate(rotated.Key); ok {
+		t.Error("old key should stop working with no grace period")
+	}
+	if immediate.Token.ExpiresAt != nil {
+		t.Error("expected token without expiry")
+	}
+	if id := manager.OriginalTokenID(immediate.Token.ID); id != created.Token.ID {
+		t.Errorf("expected rotations followed back to %s, got %s", created.Token.ID, id)
+	}
+	if id := manager.OriginalTokenID(middleware.TokenID("legacy")); id != middleware.TokenID("legacy") {
+		t.Errorf("expected an unrotated token to be its own original, got %s", id)
+	}
+
+	if err := manager.Revoke(immediate.Token.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := manager.Authenticate(immediate.Key); ok {
+		t.Error("revoked key should not authenticate")
+	}
+	if err := manager.Revoke("tok_missing"); !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("expected not found, got %v", err)
+	}
+	if _, err := manager.Create(model.TokenRequest{Name: "bad", ExpiresIn: "soon"}); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected invalid duration, got %v", err)
+	}
+
+	// Tokens survive a restart
+	reloaded := NewManager(store, time.Hour, time.Minute)
+	if len(reloaded.List()) != 4 {
+		t.Errorf("expected 4 tokens after reload, got %d", len(reloaded.List()))
+	}
+	if _, ok := reloaded.Authenticate("legacy"); !ok {
+		t.Error("imported key should still authenticate after reload")
+	}
+}
@@ -0,0 +1,81 @@
+package ynab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// DefaultURL is YNAB's API
+const DefaultURL = "https://api.ynab.com/v1"
+
+// Client creates transactions through YNAB's API with a personal access
+// token
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a YNAB client. An empty baseURL uses DefaultURL.
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// CreateTransactions adds transactions to a budget ("last-used" for the
+// most recently opened one), returning how many were created and how many
+// YNAB skipped because their import ID was already used
+func (c *Client) CreateTransactions(ctx context.Context, budgetID string, transactions []model.YNABTransaction) (int, int, error) {
+	body, err := json.Marshal(model.YNABTransactionsResponse{Transactions: transactions})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	endpoint := c.baseURL + "/budgets/" + url.PathEscape(budgetID) + "/transactions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to create YNAB transactions: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read YNAB response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, 0, fmt.Errorf("failed to create YNAB transactions: unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+
+	var response struct {
+		Data struct {
+			TransactionIDs     []string `json:"transaction_ids"`
+			DuplicateImportIDs []string `json:"duplicate_import_ids"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return 0, 0, fmt.Errorf("failed to decode YNAB response: %w", err)
+	}
+	return len(response.Data.TransactionIDs), len(response.Data.DuplicateImportIDs), nil
+}
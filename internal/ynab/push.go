@@ -0,0 +1,105 @@
+package ynab
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+// FeedName is the name the YNAB feed's state is stored under
+const FeedName = "ynab"
+
+// Pusher sends new transactions on mapped accounts to YNAB, standing in
+// for the bank feed YNAB doesn't offer for NAB
+type Pusher struct {
+	client   *Client
+	store    *store.Store
+	budgetID string
+	accounts map[string]string
+	logger   *log.Logger
+	now      func() time.Time
+}
+
+// NewPusher creates a YNAB pusher for the accounts mapped to YNAB account
+// IDs in a budget
+func NewPusher(client *Client, store *store.Store, budgetID string, accounts map[string]string, logger *log.Logger) *Pusher {
+	return &Pusher{
+		client:   client,
+		store:    store,
+		budgetID: budgetID,
+		accounts: accounts,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Name identifies the feed in logs
+func (p *Pusher) Name() string {
+	return "YNAB"
+}
+
+// Push sends each mapped account's transactions that haven't been sent
+// yet. History from before an account was first pushed is left for a
+// CSV import, so only transactions dated from then on are sent.
+func (p *Pusher) Push(ctx context.Context) error {
+	accountIDs := make([]string, 0, len(p.accounts))
+	for accountID := range p.accounts {
+		accountIDs = append(accountIDs, accountID)
+	}
+	sort.Strings(accountIDs)
+
+	now := p.now()
+	state := p.store.FeedState(FeedName)
+	created, skipped := 0, 0
+	for _, accountID := range accountIDs {
+		link, linked := state.Accounts[accountID]
+		if !linked {
+			link = model.FeedAccount{Since: now.Format("2006-01-02"), LinkedAt: now}
+		}
+		link.RemoteID = p.accounts[accountID]
+
+		var unpushed []model.Transaction
+		for _, transaction := range p.store.Transactions(accountID) {
+			if _, ok := state.Pushed[accountID+"/"+transaction.ID]; !ok {
+				unpushed = append(unpushed, transaction)
+			}
+		}
+		pending := Transactions(accountID, link.RemoteID, unpushed, link.Since, "")
+		if linked && len(pending) == 0 {
+			continue
+		}
+
+		if len(pending) > 0 {
+			added, duplicates, err := p.client.CreateTransactions(ctx, p.budgetID, pending)
+			if err != nil {
+				return fmt.Errorf("failed to push account %s to YNAB: %w", accountID, err)
+			}
+			created, skipped = created+added, skipped+duplicates
+		}
+		if err := p.store.UpdateFeedState(FeedName, func(stored *model.FeedState) {
+			stored.Accounts[accountID] = link
+			for _, transaction := range unpushed {
+				if transaction.Date >= link.Since {
+					stored.Pushed[accountID+"/"+transaction.ID] = ImportID(accountID, transaction.ID)
+				}
+			}
+		}); err != nil {
+			return fmt.Errorf("failed to save YNAB progress: %w", err)
+		}
+	}
+
+	if err := p.store.UpdateFeedState(FeedName, func(stored *model.FeedState) {
+		stored.LastPushAt = &now
+	}); err != nil {
+		return fmt.Errorf("failed to save YNAB progress: %w", err)
+	}
+	if created > 0 || skipped > 0 {
+		p.logger.Printf("Pushed %d transactions to YNAB (%d already imported)", created, skipped)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package ynab
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+)
+
+// Field limits from YNAB's API
+const (
+	maxPayeeLength = 200
+	maxMemoLength  = 500
+)
+
+// ParseAccounts parses an account mapping such as
+// "12345678=3fa85f64-...,87654321=9c1b..." from NAB account IDs to YNAB
+// account IDs
+func ParseAccounts(spec string) (map[string]string, error) {
+	accounts := make(map[string]string)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		accountID, ynabID, ok := strings.Cut(entry, "=")
+		accountID, ynabID = strings.TrimSpace(accountID), strings.TrimSpace(ynabID)
+		if !ok || accountID == "" || ynabID == "" {
+			return nil, fmt.Errorf("invalid YNAB account mapping %q", entry)
+		}
+		accounts[accountID] = ynabID
+	}
+	return accounts, nil
+}
+
+// Transactions maps an account's stored transactions dated from from to
+// to (either may be empty) to YNAB transactions against ynabAccountID,
+// oldest first. Zero amounts are left out.
+func Transactions(accountID, ynabAccountID string, transactions []model.Transaction, from, to string) []model.YNABTransaction {
+	result := []model.YNABTransaction{}
+	for _, transaction := range transactions {
+		if (from != "" && transaction.Date < from) || (to != "" && transaction.Date > to) {
+			continue
+		}
+		if converted, ok := Transaction(accountID, ynabAccountID, transaction); ok {
+			result = append(result, converted)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Date < result[j].Date
+	})
+	return result
+}
+
+// Transaction maps a stored transaction to a YNAB transaction. The payee is
+// the merchant, or the description when there isn't one, and the memo is
+// NAB's description. The import ID is derived from the account and
+// transaction IDs, so YNAB skips a transaction it has already imported.
+func Transaction(accountID, ynabAccountID string, transaction model.Transaction) (model.YNABTransaction, bool) {
+	amount, err := strconv.ParseFloat(transaction.Amount.Amount, 64)
+	if err != nil || amount == 0 {
+		return model.YNABTransaction{}, false
+	}
+
+	payee := transaction.Description
+	switch {
+	case transaction.MerchantDetails != nil:
+		payee = transaction.MerchantDetails.Name
+	case transaction.Merchant != nil && *transaction.Merchant != "":
+		payee = *transaction.Merchant
+	}
+
+	return model.YNABTransaction{
+		AccountID: ynabAccountID,
+		Date:      transaction.Date,
+		Amount:    int64(math.Round(amount * 1000)),
+		PayeeName: truncate(payee, maxPayeeLength),
+		Memo:      truncate(transaction.Description, maxMemoLength),
+		Cleared:   "cleared",
+		ImportID:  ImportID(accountID, transaction.ID),
+	}, true
+}
+
+// ImportID returns the YNAB import ID for a stored transaction. YNAB
+// allows 36 characters.
+func ImportID(accountID, transactionID string) string {
+	sum := sha256.Sum256([]byte(accountID + "/" + transactionID))
+	return "NAB:" + hex.EncodeToString(sum[:16])
+}
+
+// CSV encodes transactions in the Date, Payee, Memo, Outflow, Inflow
+// layout YNAB's file import reads
+func CSV(transactions []model.YNABTransaction) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write([]string{"Date", "Payee", "Memo", "Outflow", "Inflow"})
+	for _, transaction := range transactions {
+		outflow, inflow := "", milliunits(transaction.Amount)
+		if transaction.Amount < 0 {
+			outflow, inflow = milliunits(-transaction.Amount), ""
+		}
+		writer.Write([]string{transaction.Date, transaction.PayeeName, transaction.Memo, outflow, inflow})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("failed to encode YNAB CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// milliunits formats a milliunit amount as dollars and cents
+func milliunits(amount int64) string {
+	return fmt.Sprintf("%d.%02d", amount/1000, amount%1000/10)
+}
+
+// truncate shortens s to at most n characters
+func truncate(s string, n int) string {
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}
@@ -0,0 +1,106 @@
+package ynab
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benrowe/nab-bank-api/internal/model"
+	"github.com/benrowe/nab-bank-api/internal/store"
+)
+
+func TestTransactionsAndCSV(t *testing.T) {
+	merchant := "SEVEN SEEDS"
+	transactions := Transactions("12345678", "ynab-1", []model.Transaction{
+		{ID: "t3", Date: "2024-05-03", Description: "SALARY ACME", Amount: model.Money{Amount: "2500.00"}},
+		{ID: "t2", Date: "2024-05-02", Description: "VISA SEVEN SEEDS CARLTON", Amount: model.Money{Amount: "-12.34"}, Merchant: &merchant},
+		{ID: "t1", Date: "2024-05-01", Description: "EFTPOS COLES", Amount: model.Money{Amount: "-80.05"}, MerchantDetails: &model.MerchantDetails{Name: "Coles"}},
+		{ID: "t0", Date: "2024-04-30", Description: "TOO EARLY", Amount: model.Money{Amount: "-1.00"}},
+	}, "2024-05-01", "")
+
+	if len(transactions) != 3 {
+		t.Fatalf("expected three transactions, got %+v", transactions)
+	}
+	coles := transactions[0]
+	if coles.PayeeName != "Coles" || coles.Memo != "EFTPOS COLES" || coles.Amount != -80050 || coles.AccountID != "ynab-1" || coles.Cleared != "cleared" {
+		t.Errorf("unexpected transaction %+v", coles)
+	}
+	if transactions[1].PayeeName != "SEVEN SEEDS" || transactions[2].Amount != 2500000 {
+		t.Errorf("unexpected transactions %+v", transactions[1:])
+	}
+	if len(coles.ImportID) > 36 || coles.ImportID != ImportID("12345678", "t1") || coles.ImportID == ImportID("87654321", "t1") {
+		t.Errorf("unexpected import ID %q", coles.ImportID)
+	}
+
+	body, err := CSV(transactions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Date,Payee,Memo,Outflow,Inflow\n" +
+		"2024-05-01,Coles,EFTPOS COLES,80.05,\n" +
+		"2024-05-02,SEVEN SEEDS,VISA SEVEN SEEDS CARLTON,12.34,\n" +
+		"2024-05-03,SALARY ACME,SALARY ACME,,2500.00\n"
+	if string(body) != want {
+		t.Errorf("unexpected CSV:\n%s", body)
+	}
+
+	if _, err := ParseAccounts("12345678=ynab-1, 87654321"); err == nil {
+		t.Error("expected an invalid mapping error")
+	}
+}
+
+func TestPush(t *testing.T) {
+	var posted []model.YNABTransaction
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/budgets/last-used/transactions" || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body model.YNABTransactionsResponse
+		json.NewDecoder(r.Body).Decode(&body)
+		posted = append(posted, body.Transactions...)
+		ids := make([]string, len(body.Transactions))
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"transaction_ids": ids}})
+	}))
+	defer server.Close()
+
+	dataStore, err := store.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	save := func(transactions ...model.Transaction) {
+		if err := dataStore.SaveTransactions("12345678", transactions); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save(
+		model.Transaction{ID: "old", Date: "2024-05-09", Description: "BEFORE LINKING", Amount: model.Money{Amount: "-5.00"}},
+		model.Transaction{ID: "t1", Date: "2024-05-10", Description: "EFTPOS COLES", Amount: model.Money{Amount: "-80.05"}},
+	)
+	if err := dataStore.SaveTransactions("87654321", []model.Transaction{{ID: "other", Date: "2024-05-10", Description: "UNMAPPED", Amount: model.Money{Amount: "-1.00"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	pusher := NewPusher(NewClient(server.URL, "token"), dataStore, "last-used", map[string]string{"12345678": "ynab-1"}, log.New(io.Discard, "", 0))
+	pusher.now = func() time.Time { return time.Date(2024, 5, 10, 18, 0, 0, 0, time.Local) }
+
+	if err := pusher.Push(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(posted) != 1 || posted[0].AccountID != "ynab-1" || posted[0].Memo != "EFTPOS COLES" {
+		t.Fatalf("expected the mapped account's transactions from the linking day on, got %+v", posted)
+	}
+
+	save(model.Transaction{ID: "t2", Date: "2024-05-11", Description: "REFUND", Amount: model.Money{Amount: "5.00"}})
+	if err := pusher.Push(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(posted) != 2 || posted[1].Memo != "REFUND" {
+		t.Errorf("expected only the new transaction to be pushed, got %+v", posted)
+	}
+}